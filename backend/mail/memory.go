@@ -0,0 +1,59 @@
+package mail
+
+import (
+	"context"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// InMemorySender implements Sender by keeping the messages, for development and tests
+type InMemorySender struct {
+	mu       sync.RWMutex
+	messages []Message
+	// Err is returned by Send when set, to simulate a provider failure
+	Err error
+}
+
+// NewInMemorySender creates a new in-memory sender
+func NewInMemorySender() *InMemorySender {
+	return &InMemorySender{
+		messages: make([]Message, 0),
+	}
+}
+
+// Send implements Sender. The message is logged, so that the emails can be read when developing locally
+func (s *InMemorySender) Send(ctx context.Context, msg Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.Err != nil {
+		return s.Err
+	}
+	s.messages = append(s.messages, msg)
+	log.Infof("📧 Email to %s: %s\n%s", msg.To, msg.Subject, msg.Body)
+	return nil
+}
+
+// Messages returns the messages sent so far, oldest first
+func (s *InMemorySender) Messages() []Message {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	messages := make([]Message, len(s.messages))
+	copy(messages, s.messages)
+	return messages
+}
+
+// LastMessageTo returns the latest message sent to the address, and false if none was
+func (s *InMemorySender) LastMessageTo(to string) (Message, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for i := len(s.messages) - 1; i >= 0; i-- {
+		if s.messages[i].To == to {
+			return s.messages[i], true
+		}
+	}
+	return Message{}, false
+}
@@ -0,0 +1,37 @@
+package mail
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInMemorySender_KeepsMessages(t *testing.T) {
+	sender := NewInMemorySender()
+	ctx := context.Background()
+
+	require.NoError(t, sender.Send(ctx, Message{To: "a@example.com", Subject: "first"}))
+	require.NoError(t, sender.Send(ctx, Message{To: "b@example.com", Subject: "second"}))
+	require.NoError(t, sender.Send(ctx, Message{To: "a@example.com", Subject: "third"}))
+
+	assert.Len(t, sender.Messages(), 3)
+
+	last, ok := sender.LastMessageTo("a@example.com")
+	require.True(t, ok)
+	assert.Equal(t, "third", last.Subject)
+
+	_, ok = sender.LastMessageTo("c@example.com")
+	assert.False(t, ok)
+}
+
+func TestInMemorySender_ErrorInjection(t *testing.T) {
+	sender := NewInMemorySender()
+	sender.Err = errors.New("provider down")
+
+	err := sender.Send(context.Background(), Message{To: "a@example.com"})
+	assert.Error(t, err)
+	assert.Empty(t, sender.Messages())
+}
@@ -0,0 +1,17 @@
+package mail
+
+import "context"
+
+// Message is a plain text email
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Sender is a provider-agnostic interface for sending emails.
+// Implementations can include SMTP, a transactional email API, or in-memory for testing.
+type Sender interface {
+	// Send delivers the message, or returns an error if the provider refused it
+	Send(ctx context.Context, msg Message) error
+}
@@ -0,0 +1,88 @@
+package mail
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"os"
+	"strings"
+	"time"
+)
+
+// SMTPConfig holds the settings of an SMTP relay
+type SMTPConfig struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	// From is the address the emails are sent from
+	From string
+}
+
+// SMTPSender implements Sender with an SMTP relay, using STARTTLS when the server offers it
+type SMTPSender struct {
+	config SMTPConfig
+	auth   smtp.Auth
+}
+
+// NewSMTPSender creates an SMTPSender. The relay is only authenticated against when a username is given
+func NewSMTPSender(config SMTPConfig) (*SMTPSender, error) {
+	if config.Host == "" {
+		return nil, errors.New("SMTP host is required")
+	}
+	if config.From == "" {
+		return nil, errors.New("SMTP sender address is required")
+	}
+	if config.Port == "" {
+		config.Port = "587"
+	}
+
+	var auth smtp.Auth
+	if config.Username != "" {
+		auth = smtp.PlainAuth("", config.Username, config.Password, config.Host)
+	}
+
+	return &SMTPSender{config: config, auth: auth}, nil
+}
+
+// NewSMTPSenderFromEnv creates an SMTPSender from SMTP_HOST, SMTP_PORT, SMTP_USERNAME, SMTP_PASSWORD and SMTP_FROM
+func NewSMTPSenderFromEnv() (*SMTPSender, error) {
+	return NewSMTPSender(SMTPConfig{
+		Host:     os.Getenv("SMTP_HOST"),
+		Port:     os.Getenv("SMTP_PORT"),
+		Username: os.Getenv("SMTP_USERNAME"),
+		Password: os.Getenv("SMTP_PASSWORD"),
+		From:     os.Getenv("SMTP_FROM"),
+	})
+}
+
+// Send implements Sender. net/smtp doesn't take a context, so the context is only checked before sending
+func (s *SMTPSender) Send(ctx context.Context, msg Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	addr := net.JoinHostPort(s.config.Host, s.config.Port)
+	if err := smtp.SendMail(addr, s.auth, s.config.From, []string{msg.To}, buildMessage(s.config.From, msg, time.Now())); err != nil {
+		return fmt.Errorf("failed to send email to %s: %v", msg.To, err)
+	}
+	return nil
+}
+
+// buildMessage formats the message with its headers, as expected by the DATA command
+func buildMessage(from string, msg Message, date time.Time) []byte {
+	var b strings.Builder
+	b.WriteString("From: " + from + "\r\n")
+	b.WriteString("To: " + msg.To + "\r\n")
+	b.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", msg.Subject) + "\r\n")
+	b.WriteString("Date: " + date.Format(time.RFC1123Z) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(strings.ReplaceAll(msg.Body, "\r\n", "\n"), "\n", "\r\n"))
+	return []byte(b.String())
+}
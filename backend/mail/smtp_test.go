@@ -0,0 +1,47 @@
+package mail
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSMTPSender_Validation(t *testing.T) {
+	_, err := NewSMTPSender(SMTPConfig{From: "noreply@ligain.app"})
+	assert.Error(t, err)
+
+	_, err = NewSMTPSender(SMTPConfig{Host: "smtp.example.com"})
+	assert.Error(t, err)
+
+	sender, err := NewSMTPSender(SMTPConfig{Host: "smtp.example.com", From: "noreply@ligain.app"})
+	require.NoError(t, err)
+	assert.Equal(t, "587", sender.config.Port)
+	assert.Nil(t, sender.auth)
+}
+
+func TestBuildMessage(t *testing.T) {
+	date := time.Date(2026, 3, 14, 10, 0, 0, 0, time.UTC)
+	raw := string(buildMessage("noreply@ligain.app", Message{
+		To:      "player@example.com",
+		Subject: "Your Ligain sign-in code",
+		Body:    "Your code is 123456\nIt expires in 15 minutes",
+	}, date))
+
+	headers, body, found := strings.Cut(raw, "\r\n\r\n")
+	require.True(t, found)
+	assert.Contains(t, headers, "From: noreply@ligain.app\r\n")
+	assert.Contains(t, headers, "To: player@example.com\r\n")
+	assert.Contains(t, headers, "Subject: Your Ligain sign-in code\r\n")
+	assert.Contains(t, headers, "Date: Sat, 14 Mar 2026 10:00:00 +0000")
+	assert.Contains(t, headers, "Content-Type: text/plain; charset=utf-8")
+	assert.Equal(t, "Your code is 123456\r\nIt expires in 15 minutes", body)
+}
+
+func TestBuildMessage_EncodesNonASCIISubject(t *testing.T) {
+	raw := string(buildMessage("noreply@ligain.app", Message{To: "player@example.com", Subject: "Connexion à Ligain"}, time.Now()))
+	assert.Contains(t, raw, "Subject: =?utf-8?q?")
+	assert.NotContains(t, raw, "Connexion à Ligain")
+}
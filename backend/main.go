@@ -2,20 +2,25 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
 	"database/sql"
 	"fmt"
 	"ligain/backend/api"
+	"ligain/backend/mail"
 	"ligain/backend/middleware"
 	"ligain/backend/models"
+	"ligain/backend/ratelimit"
 	"ligain/backend/repositories"
 	"ligain/backend/repositories/postgres"
 	"ligain/backend/routes"
 	"ligain/backend/rules"
+	"ligain/backend/scheduler"
 	"ligain/backend/services"
 	"ligain/backend/storage"
 	"net/http"
 	_ "net/http/pprof"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -50,14 +55,31 @@ func main() {
 	ctx := context.Background()
 
 	var (
-		playerRepo     repositories.PlayerRepository
-		gameRepo       repositories.GameRepository
-		betRepo        repositories.BetRepository
-		matchRepo      repositories.MatchRepository
-		gameCodeRepo   repositories.GameCodeRepository
-		gamePlayerRepo repositories.GamePlayerRepository
-		uow            repositories.UnitOfWork
-		watcher        services.MatchWatcherService
+		playerRepo      repositories.PlayerRepository
+		gameRepo        repositories.GameRepository
+		gameEventRepo   repositories.GameEventRepository
+		betRepo         repositories.BetRepository
+		betHistoryRepo  repositories.BetHistoryRepository
+		matchRepo       repositories.MatchRepository
+		gameCodeRepo    repositories.GameCodeRepository
+		gamePlayerRepo  repositories.GamePlayerRepository
+		achievementRepo repositories.AchievementRepository
+		ratingRepo      repositories.RatingRepository
+		commentRepo     repositories.CommentRepository
+		activityRepo    repositories.ActivityRepository
+		refreshRepo     repositories.RefreshTokenRepository
+		guestCredRepo   repositories.GuestCredentialRepository
+		mergeRepo       repositories.AccountMergeRepository
+		exportRepo      repositories.DataExportRepository
+		deletionRepo    repositories.AccountDeletionRepository
+		emailSignInRepo repositories.EmailSignInRepository
+		apiKeyRepo      repositories.APIKeyRepository
+		adminRepo       repositories.AdminRepository
+		rateLimitStore  ratelimit.Store
+		jobStore        scheduler.Store
+		database        *sql.DB // nil when the repositories are in memory
+		uow             repositories.UnitOfWork
+		watcher         services.MatchWatcherService
 	)
 
 	if env == "fake" {
@@ -65,7 +87,7 @@ func main() {
 		if fakeDBURL != "" {
 			log.Info("Running in fake mode — using real postgres")
 
-			db, err := sql.Open("pgx", fakeDBURL)
+			db, err := postgres.OpenDB(fakeDBURL)
 			if err != nil {
 				log.Fatal("Failed to connect to database:", err)
 			}
@@ -80,12 +102,31 @@ func main() {
 				log.Fatal("Failed to create game repository:", err)
 			}
 			gameRepo = pgGameRepo
+			gameEventRepo = postgres.NewPostgresGameEventRepository(db)
 			betRepo = postgres.NewPostgresBetRepository(db)
+			betHistoryRepo = postgres.NewPostgresBetHistoryRepository(db)
 			playerRepo = postgres.NewPostgresPlayerRepository(db)
 			matchRepo = postgres.NewPostgresMatchRepository(db)
 			gameCodeRepo = postgres.NewPostgresGameCodeRepository(db)
 			gamePlayerRepo = postgres.NewPostgresGamePlayerRepository(db)
+			achievementRepo = postgres.NewPostgresAchievementRepository(db)
+			ratingRepo = postgres.NewPostgresRatingRepository(db)
+			commentRepo = postgres.NewPostgresCommentRepository(db)
+			activityRepo = postgres.NewPostgresActivityRepository(db)
+			refreshRepo = postgres.NewPostgresRefreshTokenRepository(db)
+			guestCredRepo = postgres.NewPostgresGuestCredentialRepository(db)
+			mergeRepo = postgres.NewPostgresAccountMergeRepository(db)
+			exportRepo = postgres.NewPostgresDataExportRepository(db)
+			deletionRepo = postgres.NewPostgresAccountDeletionRepository(db)
+			emailSignInRepo = postgres.NewPostgresEmailSignInRepository(db)
+			apiKeyRepo = postgres.NewPostgresAPIKeyRepository(db)
+			adminRepo = postgres.NewPostgresAdminRepository(db)
+			if os.Getenv("RATE_LIMIT_BACKEND") == "postgres" {
+				rateLimitStore = postgres.NewPostgresRateLimitStore(db)
+			}
+			jobStore = postgres.NewPostgresJobStore(db)
 			uow = postgres.NewUnitOfWork(db)
+			database = db
 
 			matches, err := matchRepo.GetMatchesByCompetitionAndSeason("Ligue 1", "2025/2026")
 			log.Infof("Got %d matches", len(matches))
@@ -100,7 +141,9 @@ func main() {
 			}
 			simulatedAPI := api.NewSimulatedSportsmonkAPI(api.NewSportsmonkAPI(os.Getenv("SPORTSMONK_API_TOKEN")))
 			sportsmonkRepo := repositories.NewSportsmonkRepository(simulatedAPI)
-			watcher = services.NewMatchWatcherServiceSportsmonkWithOptions(sportsmonkRepo, matchesMap, matchRepo, 1*time.Minute)
+			w := services.NewMatchWatcherServiceSportsmonkWithOptions(sportsmonkRepo, matchesMap, matchRepo, 1*time.Minute)
+			w.SetLeaderLock(postgres.NewPostgresLeaderLock(db, matchWatcherLeaderLock))
+			watcher = w
 			log.Info("Running in fake mode: SimulatedSportsmonkAPI + real postgres (1m poll)")
 		} else {
 			log.Info("Running in fake mode — all repositories are in-memory, no DB required")
@@ -108,10 +151,23 @@ func main() {
 			inMemPlayerRepo := repositories.NewInMemoryPlayerRepository()
 			playerRepo = inMemPlayerRepo
 			gameRepo = repositories.NewInMemoryGameRepository()
+			gameEventRepo = repositories.NewInMemoryGameEventRepository()
 			betRepo = repositories.NewInMemoryBetRepository()
+			betHistoryRepo = repositories.NewInMemoryBetHistoryRepository()
 			matchRepo = repositories.NewInMemoryMatchRepository()
 			gameCodeRepo = repositories.NewInMemoryGameCodeRepository()
 			gamePlayerRepo = repositories.NewInMemoryGamePlayerRepository(inMemPlayerRepo)
+			achievementRepo = repositories.NewInMemoryAchievementRepository()
+			ratingRepo = repositories.NewInMemoryRatingRepository()
+			commentRepo = repositories.NewInMemoryCommentRepository()
+			activityRepo = repositories.NewInMemoryActivityRepository()
+			refreshRepo = repositories.NewInMemoryRefreshTokenRepository()
+			guestCredRepo = repositories.NewInMemoryGuestCredentialRepository()
+			exportRepo = repositories.NewInMemoryDataExportRepository()
+			deletionRepo = repositories.NewInMemoryAccountDeletionRepository()
+			emailSignInRepo = repositories.NewInMemoryEmailSignInRepository()
+			apiKeyRepo = repositories.NewInMemoryAPIKeyRepository()
+			adminRepo = repositories.NewInMemoryAdminRepository(inMemPlayerRepo, gameRepo, gameCodeRepo, gamePlayerRepo)
 			uow = repositories.NewNoopUnitOfWork()
 
 			fakeSeasonMatches := []models.SeasonMatch{
@@ -151,7 +207,7 @@ func main() {
 			log.Fatal("DATABASE_URL environment variable is not set")
 		}
 
-		db, err := sql.Open("pgx", databaseURL)
+		db, err := postgres.OpenDB(databaseURL)
 		if err != nil {
 			log.Fatal("Failed to connect to database:", err)
 		}
@@ -166,12 +222,31 @@ func main() {
 			log.Fatal("Failed to create game repository:", err)
 		}
 		gameRepo = pgGameRepo
+		gameEventRepo = postgres.NewPostgresGameEventRepository(db)
 		betRepo = postgres.NewPostgresBetRepository(db)
+		betHistoryRepo = postgres.NewPostgresBetHistoryRepository(db)
 		playerRepo = postgres.NewPostgresPlayerRepository(db)
 		matchRepo = postgres.NewPostgresMatchRepository(db)
 		gameCodeRepo = postgres.NewPostgresGameCodeRepository(db)
 		gamePlayerRepo = postgres.NewPostgresGamePlayerRepository(db)
+		achievementRepo = postgres.NewPostgresAchievementRepository(db)
+		ratingRepo = postgres.NewPostgresRatingRepository(db)
+		commentRepo = postgres.NewPostgresCommentRepository(db)
+		activityRepo = postgres.NewPostgresActivityRepository(db)
+		refreshRepo = postgres.NewPostgresRefreshTokenRepository(db)
+		guestCredRepo = postgres.NewPostgresGuestCredentialRepository(db)
+		mergeRepo = postgres.NewPostgresAccountMergeRepository(db)
+		exportRepo = postgres.NewPostgresDataExportRepository(db)
+		deletionRepo = postgres.NewPostgresAccountDeletionRepository(db)
+		emailSignInRepo = postgres.NewPostgresEmailSignInRepository(db)
+		apiKeyRepo = postgres.NewPostgresAPIKeyRepository(db)
+		adminRepo = postgres.NewPostgresAdminRepository(db)
+		if os.Getenv("RATE_LIMIT_BACKEND") == "postgres" {
+			rateLimitStore = postgres.NewPostgresRateLimitStore(db)
+		}
+		jobStore = postgres.NewPostgresJobStore(db)
 		uow = postgres.NewUnitOfWork(db)
+		database = db
 
 		matches, err := matchRepo.GetMatchesByCompetitionAndSeason("Ligue 1", "2025/2026")
 		log.Infof("Got %d matches", len(matches))
@@ -188,6 +263,8 @@ func main() {
 		if err != nil {
 			log.Fatal("Failed to create match watcher service:", err)
 		}
+		// Only one of the replicas polls the matches
+		w.SetLeaderLock(postgres.NewPostgresLeaderLock(db, matchWatcherLeaderLock))
 		watcher = w
 	}
 
@@ -195,14 +272,45 @@ func main() {
 		log.Fatal("Failed to start match watcher service:", err)
 	}
 
-	authService := services.NewAuthService(playerRepo)
+	signingKey := []byte(os.Getenv("AUTH_TOKEN_SIGNING_KEY"))
+	if len(signingKey) == 0 && !isLocalEnv(env) {
+		log.Fatalf("AUTH_TOKEN_SIGNING_KEY must be set in the %q environment", env)
+	}
+	if len(signingKey) == 0 {
+		log.Warn("AUTH_TOKEN_SIGNING_KEY is not set, access tokens are signed with a random key and won't survive a restart")
+		signingKey = make([]byte, 32)
+		if _, err := rand.Read(signingKey); err != nil {
+			log.Fatal("Failed to generate a signing key:", err)
+		}
+	}
+	signer, err := services.NewAccessTokenSigner(signingKey, services.DefaultAccessTokenTTL)
+	if err != nil {
+		log.Fatal("Invalid AUTH_TOKEN_SIGNING_KEY:", err)
+	}
+	authService := services.NewAuthServiceWithTokens(playerRepo, refreshRepo, guestCredRepo, deletionRepo, signer, services.NewOAuthVerifier(), time.Now)
+
+	achievementService := services.NewAchievementService(achievementRepo)
+	ratingService := services.NewRatingService(ratingRepo)
+	activityService := services.NewActivityService(activityRepo, gamePlayerRepo)
 
-	registry, err := services.NewGameServiceRegistry(gameRepo, betRepo, gamePlayerRepo, watcher)
+	registry, err := services.NewGameServiceRegistryWithUnitOfWork(uow, gameRepo, betRepo, gamePlayerRepo, watcher, achievementService, ratingService, activityService)
 	if err != nil {
 		log.Fatal("Failed to create game registry:", err)
 	}
+	// Record every bet saved, the history is the proof of when each one was placed
+	betHistoryService := services.NewBetHistoryService(betHistoryRepo, gameRepo, gamePlayerRepo)
+	registry.AddBetRecorder(betHistoryService)
+
+	// Keep the cached games fresh when the other instances or the scripts change them
+	if database != nil {
+		invalidator := services.NewGameCacheInvalidator(gameRepo, registry)
+		if err := postgres.NewGameChangeListener(database, invalidator).Start(ctx); err != nil {
+			log.Fatal("Failed to listen to game changes:", err)
+		}
+	}
 
 	membershipService := services.NewGameMembershipService(uow, gamePlayerRepo, gameRepo, gameCodeRepo, registry, watcher)
+	membershipService.AddActivityObserver(activityService)
 	queryService := services.NewGameQueryService(gameRepo, gamePlayerRepo, gameCodeRepo, betRepo)
 	joinService := services.NewGameJoinService(gameCodeRepo, gameRepo, gamePlayerRepo, membershipService, registry, time.Now)
 	creationService := services.NewGameCreationServiceWithServices(
@@ -211,7 +319,31 @@ func main() {
 		time.Now,
 	)
 
+	// Background jobs run on a single instance at a time, their runs are kept in the job_run table
+	if jobStore == nil {
+		jobStore = scheduler.NewInMemoryStore()
+	}
+	instance, _ := os.Hostname()
+	jobScheduler := scheduler.NewScheduler(jobStore, instance)
+	registerJob(jobScheduler.Register, "cleanup-game-codes", "20 * * * *", func(ctx context.Context) error {
+		return creationService.CleanupExpiredCodes()
+	})
+	registerJob(jobScheduler.Register, "cleanup-auth-tokens", "25 * * * *", authService.CleanupExpiredTokens)
+	registerJob(jobScheduler.Register, "prune-job-runs", "0 4 * * *", func(ctx context.Context) error {
+		return jobStore.PruneRuns(ctx, time.Now().Add(-jobRunRetention))
+	})
+	// Each instance checks the games it keeps in memory, a match is scored once whichever instance does it
+	registerJob(jobScheduler.RegisterEveryInstance, "reconcile-scores", "*/15 * * * *", registry.ReconcileScores)
+
 	router := gin.Default()
+	// Clients can send any X-Forwarded-For, so gin never reads it. On Cloud Run, K_SERVICE is set
+	// and the client address is the hop appended by the Google front end
+	if err := router.SetTrustedProxies(nil); err != nil {
+		log.Fatalf("Failed to configure trusted proxies: %v", err)
+	}
+	if os.Getenv("K_SERVICE") != "" {
+		router.Use(middleware.CloudRunClientIP())
+	}
 
 	// Setup CORS with specific origins
 	allowedOriginsStr := os.Getenv("ALLOWED_ORIGINS")
@@ -242,8 +374,52 @@ func main() {
 	// Apply metrics middleware (should be before auth to capture all requests)
 	router.Use(middleware.MetricsMiddleware())
 
-	// Apply API key authentication middleware
-	router.Use(middleware.APIKeyAuth())
+	// Blob storage holds the avatars and the data export archives. Without GCS, a local directory stands in
+	// for it on a developer machine, its signed URLs being served before the API key check like GCS ones would be
+	var blobStorage storage.BlobStorage
+	bucketName := os.Getenv("GCS_BUCKET_NAME")
+	if bucketName != "" {
+		gcsStorage, err := storage.NewGCSBlobStorage(ctx, bucketName)
+		if err != nil {
+			log.Fatalf("Failed to create GCS storage: %v", err)
+		}
+		defer gcsStorage.Close()
+		blobStorage = gcsStorage
+	} else if !isLocalEnv(env) {
+		log.Fatalf("GCS_BUCKET_NAME must be set in the %q environment", env)
+	} else {
+		localStorage, err := newLocalBlobStorage()
+		if err != nil {
+			log.Fatalf("Failed to create local storage: %v", err)
+		}
+		router.GET("/storage/*object-path", gin.WrapH(http.StripPrefix("/storage", localStorage)))
+		blobStorage = localStorage
+	}
+
+	// Apply API key authentication middleware. The single API_KEY given to the apps before the key store
+	// existed is imported as the "default" client, so that the installed apps keep working
+	apiKeyService := services.NewAPIKeyService(apiKeyRepo)
+	if legacyKey := os.Getenv("API_KEY"); legacyKey != "" {
+		scopes := []string{models.APIKeyScopeRead, models.APIKeyScopeWrite}
+		if _, err := apiKeyService.ImportKey(ctx, "default", legacyKey, scopes, 0); err != nil {
+			log.Fatalf("Failed to import API_KEY: %v", err)
+		}
+	}
+	// Rate limits are counted per instance, unless RATE_LIMIT_BACKEND=postgres shares them between the instances
+	registerPruneJob := jobScheduler.Register
+	if rateLimitStore == nil {
+		rateLimitStore = ratelimit.NewInMemoryStore()
+		registerPruneJob = jobScheduler.RegisterEveryInstance
+	}
+	registerJob(registerPruneJob, "prune-rate-limit-buckets", "0 * * * *", func(ctx context.Context) error {
+		// The buckets unused for longer than the period of their policy are full
+		return rateLimitStore.Prune(ctx, time.Now().Add(-24*time.Hour))
+	})
+	authService.SetGuestAttemptStore(rateLimitStore)
+	router.Use(middleware.APIKeyAuth(apiKeyService, rateLimitStore))
+
+	// Apply the rate limits of the routes that can be abused
+	router.Use(middleware.RateLimit(rateLimitStore, rateLimitRules(authService)))
 
 	// Apply version check middleware
 	router.Use(middleware.VersionCheck())
@@ -256,29 +432,104 @@ func main() {
 	authHandler := routes.NewAuthHandler(authService)
 	authHandler.SetupRoutes(router)
 
+	// Setup email sign-in routes. Without SMTP, the emails are only logged on a developer machine,
+	// and email sign-in is disabled in the deployed environments
+	var mailSender mail.Sender
+	if os.Getenv("SMTP_HOST") != "" {
+		smtpSender, err := mail.NewSMTPSenderFromEnv()
+		if err != nil {
+			log.Fatalf("Failed to create SMTP sender: %v", err)
+		}
+		mailSender = smtpSender
+	} else if isLocalEnv(env) {
+		log.Warn("SMTP_HOST not set, sign-in emails are logged instead of sent")
+		mailSender = mail.NewInMemorySender()
+	}
+	if mailSender != nil {
+		emailSignInService := services.NewEmailSignInService(emailSignInRepo, mailSender, authService, os.Getenv("EMAIL_SIGN_IN_LINK_URL"))
+		routes.NewEmailSignInHandler(emailSignInService).SetupRoutes(router)
+
+		// Delete the codes that can't be used nor count against the requests anymore
+		registerJob(jobScheduler.Register, "cleanup-email-sign-in-codes", "5 * * * *", emailSignInService.CleanupExpiredCodes)
+	} else {
+		log.Warn("SMTP_HOST not set, email sign-in disabled")
+	}
+
+	// Setup account merge routes, merges move rows across many tables in one transaction so they need postgres
+	if mergeRepo != nil {
+		mergeService := services.NewAccountMergeService(uow, mergeRepo, playerRepo, gameRepo, authService)
+		routes.NewAccountMergeHandler(mergeService, authService).SetupRoutes(router)
+	} else {
+		log.Warn("No database configured, account merge disabled")
+	}
+
 	// Setup game routes with all specialized services
 	gameHandler := routes.NewGameHandler(creationService, joinService, queryService, membershipService, authService)
 	gameHandler.SetupRoutes(router)
 
 	// Setup profile routes (avatar upload requires GCS, display name works without it)
 	var profileService services.ProfileService
-	if bucketName := os.Getenv("GCS_BUCKET_NAME"); bucketName != "" {
-		gcsStorage, err := storage.NewGCSBlobStorage(ctx, bucketName)
-		if err != nil {
-			log.Fatalf("Failed to create GCS storage: %v", err)
-		}
-		defer gcsStorage.Close()
-
-		storageService := services.NewStorageService(gcsStorage)
+	if bucketName != "" {
+		storageService := services.NewStorageService(blobStorage)
 		imageProcessor := services.NewImageProcessor()
 		profileService = services.NewProfileService(storageService, imageProcessor, playerRepo)
 		log.Infof("Profile routes enabled with GCS bucket: %s", bucketName)
+
+		// Refresh the avatar URLs before they expire, as the games show them without refreshing them
+		registerJob(jobScheduler.Register, "refresh-avatar-urls", "30 */6 * * *", func(ctx context.Context) error {
+			refreshed, err := profileService.RefreshExpiringAvatarURLs(ctx)
+			if err == nil && refreshed > 0 {
+				log.Infof("Refreshed %d avatar URLs", refreshed)
+			}
+			return err
+		})
 	} else {
 		log.Warn("GCS_BUCKET_NAME not set, avatar upload disabled")
 	}
-	profileHandler := routes.NewProfileHandler(profileService, authService)
+	profileHandler := routes.NewProfileHandlerWithPlayerStats(profileService, authService, achievementService, ratingService)
 	profileHandler.SetupRoutes(router)
 
+	// Setup the cross-game rating routes
+	ratingHandler := routes.NewRatingHandler(ratingService, authService)
+	ratingHandler.SetupRoutes(router)
+
+	// Setup comment routes
+	commentService := services.NewCommentService(commentRepo, gameRepo, gamePlayerRepo)
+	commentHandler := routes.NewCommentHandler(commentService, authService)
+	commentHandler.SetupRoutes(router)
+
+	// Setup bet history routes
+	routes.NewBetHistoryHandler(betHistoryService, authService).SetupRoutes(router)
+
+	// Setup activity feed routes
+	activityHandler := routes.NewActivityHandler(activityService, authService)
+	activityHandler.SetupRoutes(router)
+
+	// Setup the operator API, restricted to the admins
+	adminService := services.NewAdminService(uow, adminRepo, gameRepo, gameEventRepo, gameCodeRepo, betRepo, matchRepo, registry, watcher, achievementService, ratingService, activityService)
+	routes.NewAdminHandler(adminService, authService).SetupRoutes(router)
+	routes.NewJobHandler(jobScheduler, authService).SetupRoutes(router)
+
+	// Setup personal data export routes, the archives are delivered through blob storage signed URLs
+	if blobStorage != nil {
+		exportService := services.NewDataExportService(exportRepo, playerRepo, refreshRepo, gamePlayerRepo, gameRepo, betRepo, betHistoryRepo, commentRepo, blobStorage)
+		routes.NewDataExportHandler(exportService, authService).SetupRoutes(router)
+
+		// Delete the archives past their retention
+		registerJob(jobScheduler.Register, "cleanup-data-exports", "10 * * * *", exportService.CleanupExpiredExports)
+	} else {
+		log.Warn("No blob storage configured, data export disabled")
+	}
+
+	// Purge the personal data of the accounts deleted longer than the grace period ago
+	purgeService := services.NewAccountPurgeService(uow, deletionRepo, blobStorage)
+	registerJob(jobScheduler.Register, "purge-deleted-accounts", "15 * * * *", func(ctx context.Context) error {
+		_, err := purgeService.PurgeDeletedAccounts(ctx)
+		return err
+	})
+
+	jobScheduler.Start(ctx)
+
 	// Start pprof server on :6060 for heap profiling
 	go func() {
 		log.Info("Starting pprof server on :6060")
@@ -296,3 +547,81 @@ func main() {
 		log.Fatal("Failed to start server:", err)
 	}
 }
+
+// jobRunRetention is how long the history of the background jobs is kept
+const jobRunRetention = 30 * 24 * time.Hour
+
+// registerJob adds a job to the scheduler with one of its register functions, and stops the startup
+// when its spec is invalid
+func registerJob(register func(name string, spec string, run scheduler.JobFunc) error, name string, spec string, run scheduler.JobFunc) {
+	if err := register(name, spec, run); err != nil {
+		log.Fatalf("Failed to register job %s: %v", name, err)
+	}
+}
+
+// isLocalEnv checks if the server runs on a developer machine, the only place where the fallbacks for a missing
+// signing key, blob storage or SMTP server are allowed. In the deployed environments ENV is the Pulumi stack name
+func isLocalEnv(env string) bool {
+	return env == "fake" || env == "local"
+}
+
+// newLocalBlobStorage creates the blob storage used when GCS isn't configured. Its signed URLs point to
+// LOCAL_STORAGE_BASE_URL, http://localhost:8080/storage by default, and are signed with a random key
+func newLocalBlobStorage() (*storage.LocalBlobStorage, error) {
+	dir := os.Getenv("LOCAL_STORAGE_DIR")
+	if dir == "" {
+		dir = filepath.Join(os.TempDir(), "ligain-storage")
+	}
+	baseURL := os.Getenv("LOCAL_STORAGE_BASE_URL")
+	if baseURL == "" {
+		baseURL = "http://localhost:8080/storage"
+	}
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+
+	log.Infof("Using local blob storage in %s, served at %s", dir, baseURL)
+	return storage.NewLocalBlobStorage(dir, baseURL, key)
+}
+
+// rateLimitRules are the limits of the routes that can be abused: the sign-ins that guess a guest name or an emailed
+// code, and the routes of the authenticated players that guess a join code or flood the bets
+func rateLimitRules(authService services.AuthServiceInterface) []middleware.RateLimitRule {
+	byPlayer := middleware.RateLimitByPlayer(authService)
+	return []middleware.RateLimitRule{
+		{
+			Method: http.MethodPost,
+			Route:  "/api/auth/signin/guest",
+			Policy: ratelimit.Policy{Name: "guest-signin", Limit: 10, Period: time.Minute},
+			Key:    middleware.RateLimitByIP,
+		},
+		{
+			Method: http.MethodPost,
+			Route:  "/api/auth/email/request",
+			Policy: ratelimit.Policy{Name: "email-signin-request", Limit: 10, Period: time.Hour},
+			Key:    middleware.RateLimitByIP,
+		},
+		{
+			Method: http.MethodPost,
+			Route:  "/api/auth/email/verify",
+			Policy: ratelimit.Policy{Name: "email-signin-verify", Limit: 20, Period: time.Minute},
+			Key:    middleware.RateLimitByIP,
+		},
+		{
+			Method: http.MethodPost,
+			Route:  "/api/games/join",
+			Policy: ratelimit.Policy{Name: "game-join", Limit: 10, Period: time.Minute},
+			Key:    byPlayer,
+		},
+		{
+			Method: http.MethodPost,
+			Route:  "/api/game/:game-id/bet",
+			Policy: ratelimit.Policy{Name: "bets", Limit: 60, Period: time.Minute},
+			Key:    byPlayer,
+		},
+	}
+}
+
+// matchWatcherLeaderLock is the lock electing the replica which polls the matches
+const matchWatcherLeaderLock = "match-watcher"
@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"errors"
+	"ligain/backend/models"
+	"ligain/backend/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+// adminKey is the key of the authenticated admin in the Gin context
+const adminKey = "admin"
+
+// AdminAuth middleware only lets the admins through, and must come after PlayerAuth.
+// The role is read from the database on every request, since access tokens don't carry it, so that removing it takes effect right away.
+// When the request came with an API key, the key needs the admin scope too
+func AdminAuth(authService services.AuthServiceInterface) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, hasAPIKey := c.Get(apiKeyNameKey); hasAPIKey && !hasAPIKeyScope(c, models.APIKeyScopeAdmin) {
+			log.Warnf("AdminAuth - API key %s lacks the admin scope for %s %s", APIKeyName(c), c.Request.Method, c.Request.URL.Path)
+			c.JSON(http.StatusForbidden, gin.H{"error": "API key is missing the admin scope"})
+			c.Abort()
+			return
+		}
+
+		value, _ := c.Get("player")
+		player, ok := value.(*models.PlayerData)
+		if !ok || player == nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid player type in context"})
+			c.Abort()
+			return
+		}
+
+		admin, err := authService.GetPlayer(c.Request.Context(), player.ID)
+		if err != nil {
+			var notFoundErr *models.PlayerNotFoundError
+			if errors.As(err, &notFoundErr) {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "Player not found"})
+				c.Abort()
+				return
+			}
+			log.Errorf("AdminAuth - Failed to get player %s: %v", player.ID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check admin role"})
+			c.Abort()
+			return
+		}
+
+		if !admin.IsAdmin() {
+			log.Warnf("AdminAuth - Player %s is not an admin for %s %s", player.ID, c.Request.Method, c.Request.URL.Path)
+			c.JSON(http.StatusForbidden, gin.H{"error": "Admin role is required"})
+			c.Abort()
+			return
+		}
+
+		c.Set(adminKey, admin)
+		c.Next()
+	}
+}
+
+// Admin returns the admin authenticated by AdminAuth
+func Admin(c *gin.Context) *models.PlayerData {
+	value, _ := c.Get(adminKey)
+	admin, _ := value.(*models.PlayerData)
+	return admin
+}
+
+// hasAPIKeyScope checks if the API key of the request was given the scope
+func hasAPIKeyScope(c *gin.Context, scope string) bool {
+	value, _ := c.Get(apiKeyScopesKey)
+	scopes, _ := value.([]string)
+	key := models.APIKey{Scopes: scopes}
+	return key.HasScope(scope)
+}
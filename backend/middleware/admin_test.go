@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"context"
+	"ligain/backend/models"
+	"ligain/backend/ratelimit"
+	"ligain/backend/services"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupAdminTestRouter creates a router behind PlayerAuth and AdminAuth, with an admin signed in with "admin_token"
+// and a player signed in with "player_token"
+func setupAdminTestRouter(t *testing.T, keyService services.APIKeyService) *gin.Engine {
+	mockRepo := NewMockPlayerRepository()
+	authService := services.NewAuthServiceWithTimeFunc(mockRepo, nil, func() time.Time { return frozenTime })
+
+	ctx := context.Background()
+	players := map[string]*models.PlayerData{
+		"admin_token":  {ID: "admin_id", Name: "Admin", Role: models.PlayerRoleAdmin},
+		"player_token": {ID: "player_id", Name: "Player", Role: models.PlayerRolePlayer},
+	}
+	for token, player := range players {
+		require.NoError(t, mockRepo.CreatePlayer(ctx, player))
+		require.NoError(t, mockRepo.CreateAuthToken(ctx, &models.AuthToken{
+			PlayerID:  player.ID,
+			Token:     token,
+			ExpiresAt: frozenTime.Add(24 * time.Hour),
+		}))
+	}
+
+	router := setupTestRouter()
+	if keyService != nil {
+		router.Use(APIKeyAuth(keyService, ratelimit.NewInMemoryStore()))
+	}
+	router.GET("/admin", PlayerAuth(authService), AdminAuth(authService), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"admin": Admin(c).Name})
+	})
+	return router
+}
+
+func sendAdminRequest(router *gin.Engine, token string, apiKey string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("GET", "/admin", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	if apiKey != "" {
+		req.Header.Set("X-API-Key", apiKey)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestAdminAuth_Admin(t *testing.T) {
+	router := setupAdminTestRouter(t, nil)
+
+	w := sendAdminRequest(router, "admin_token", "")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"admin":"Admin"`)
+}
+
+func TestAdminAuth_NotAdmin(t *testing.T) {
+	router := setupAdminTestRouter(t, nil)
+
+	w := sendAdminRequest(router, "player_token", "")
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestAdminAuth_APIKeyScope(t *testing.T) {
+	keyService := newTestAPIKeyService(t)
+	_, err := keyService.ImportKey(context.Background(), "ops", "ops_key_789", []string{models.APIKeyScopeRead, models.APIKeyScopeAdmin}, 0)
+	require.NoError(t, err)
+	router := setupAdminTestRouter(t, keyService)
+
+	// The app key can't reach the operator API, even for an admin
+	assert.Equal(t, http.StatusForbidden, sendAdminRequest(router, "admin_token", "test_api_key_123").Code)
+	assert.Equal(t, http.StatusOK, sendAdminRequest(router, "admin_token", "ops_key_789").Code)
+	// The ops key doesn't make a player an admin
+	assert.Equal(t, http.StatusForbidden, sendAdminRequest(router, "player_token", "ops_key_789").Code)
+}
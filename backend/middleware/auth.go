@@ -2,93 +2,103 @@ package middleware
 
 import (
 	"errors"
+	"fmt"
 	"ligain/backend/models"
+	"ligain/backend/ratelimit"
 	"ligain/backend/services"
 	"net/http"
-	"os"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	log "github.com/sirupsen/logrus"
 )
 
-// APIKeyAuth middleware checks for a valid API key in the request header
-func APIKeyAuth() gin.HandlerFunc {
+const (
+	// apiKeyNameKey is the key of the client name in the Gin context
+	apiKeyNameKey = "apiKeyName"
+	// apiKeyScopesKey is the key of the scopes of the client's API key in the Gin context
+	apiKeyScopesKey = "apiKeyScopes"
+	// RefreshTokenAppVersion is the first app version that refreshes its tokens with /api/auth/refresh.
+	// Older versions only know the X-New-Token header, sent when PlayerAuth renews their expired access token
+	RefreshTokenAppVersion = "2.1.0"
+)
+
+// APIKeyAuth middleware checks for a valid API key in the request header.
+// The key must have the read scope for GET, HEAD and OPTIONS requests and the write scope for the others,
+// and stay under its rate limit, counted in limitStore. The name of the client is attached to the Gin context and to the request context
+func APIKeyAuth(keyService services.APIKeyService, limitStore ratelimit.Store) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		apiKey := c.GetHeader("X-API-Key")
-		log.Infof("🔑 APIKeyAuth - Request to %s from %s", c.Request.URL.Path, c.ClientIP())
-
 		if apiKey == "" {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "API key is required"})
 			c.Abort()
 			return
 		}
 
-		// Get allowed API keys from environment variables
-		allowedKeys := []string{
-			os.Getenv("API_KEY"),
-		}
-
-		// Check if the provided API key is valid
-		isValid := false
-		for _, key := range allowedKeys {
-			if key != "" && key == apiKey {
-				isValid = true
-				break
-			}
-		}
-
-		if !isValid {
+		key, err := keyService.Authenticate(c.Request.Context(), apiKey)
+		if errors.Is(err, services.ErrInvalidAPIKey) {
+			log.Warnf("APIKeyAuth - Invalid API key for %s from %s", c.Request.URL.Path, c.ClientIP())
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid API key"})
 			c.Abort()
 			return
 		}
-		c.Next()
-	}
-}
-
-// PlayerAuth middleware validates player authentication token
-func PlayerAuth(authService services.AuthServiceInterface) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
-			log.Error("PlayerAuth - Authorization header is required")
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header is required"})
+		if err != nil {
+			log.Errorf("APIKeyAuth - Failed to check API key: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check API key"})
 			c.Abort()
 			return
 		}
 
-		// Extract token from "Bearer <token>" format
-		tokenParts := strings.Split(authHeader, " ")
-		if len(tokenParts) != 2 || tokenParts[0] != "Bearer" {
-			log.Error("PlayerAuth - Invalid authorization header format")
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization header format"})
-			c.Abort()
-			return
-		}
+		c.Set(apiKeyNameKey, key.Name)
+		c.Set(apiKeyScopesKey, key.Scopes)
+		c.Request = c.Request.WithContext(services.WithAPIKeyName(c.Request.Context(), key.Name))
 
-		token := tokenParts[1]
-		player, err := authService.ValidateToken(c.Request.Context(), token)
-		if err != nil {
-			log.Error("PlayerAuth - Invalid or expired token")
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
+		scope := requiredAPIKeyScope(c.Request.Method)
+		if !key.HasScope(scope) {
+			log.Warnf("APIKeyAuth - API key %s lacks the %s scope for %s %s", key.Name, scope, c.Request.Method, c.Request.URL.Path)
+			c.JSON(http.StatusForbidden, gin.H{"error": fmt.Sprintf("API key is missing the %s scope", scope)})
 			c.Abort()
 			return
 		}
 
-		// Store player in Gin context
-		c.Set("player", player)
+		if key.RateLimitPerMinute > 0 {
+			policy := ratelimit.Policy{Name: "api-key", Limit: key.RateLimitPerMinute, Period: time.Minute}
+			result, err := limitStore.Take(c.Request.Context(), key.ID, policy, time.Now())
+			if err != nil {
+				log.Errorf("APIKeyAuth - Failed to check the rate limit of %s: %v", key.Name, err)
+			} else if !result.Allowed {
+				rejectRateLimited(c, policy, result)
+				return
+			}
+		}
 
 		c.Next()
 	}
 }
 
-// PlayerAuthWithRefresh middleware validates player authentication token and attempts refresh if expired
-func PlayerAuthWithRefresh(authService services.AuthServiceInterface) gin.HandlerFunc {
+// requiredAPIKeyScope returns the scope an API key needs for a request method
+func requiredAPIKeyScope(method string) string {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return models.APIKeyScopeRead
+	default:
+		return models.APIKeyScopeWrite
+	}
+}
+
+// APIKeyName returns the name of the client whose API key authenticated the request, or an empty name
+func APIKeyName(c *gin.Context) string {
+	return c.GetString(apiKeyNameKey)
+}
+
+// PlayerAuth middleware validates player authentication token.
+// The expired access tokens of the app versions older than RefreshTokenAppVersion are renewed, and sent back in X-New-Token
+func PlayerAuth(authService services.AuthServiceInterface) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			log.Error("PlayerAuthWithRefresh - Authorization header is required")
+			log.Error("PlayerAuth - Authorization header is required")
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header is required"})
 			c.Abort()
 			return
@@ -97,7 +107,7 @@ func PlayerAuthWithRefresh(authService services.AuthServiceInterface) gin.Handle
 		// Extract token from "Bearer <token>" format
 		tokenParts := strings.Split(authHeader, " ")
 		if len(tokenParts) != 2 || tokenParts[0] != "Bearer" {
-			log.Error("PlayerAuthWithRefresh - Invalid authorization header format")
+			log.Error("PlayerAuth - Invalid authorization header format")
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization header format"})
 			c.Abort()
 			return
@@ -105,45 +115,23 @@ func PlayerAuthWithRefresh(authService services.AuthServiceInterface) gin.Handle
 
 		token := tokenParts[1]
 		player, err := authService.ValidateToken(c.Request.Context(), token)
-		if err != nil {
-			// Check if it's a token expired error
-			var tokenExpiredErr *models.TokenExpiredError
-
-			if errors.As(err, &tokenExpiredErr) {
-				log.Info("PlayerAuthWithRefresh - Token expired, attempting refresh")
-
-				// For expired tokens, we need to get the player ID first before the token is deleted
-				// Let's try to get the auth token directly to extract the player ID
-				authToken, getTokenErr := authService.GetAuthTokenDirectly(c.Request.Context(), token)
-				if getTokenErr != nil || authToken == nil {
-					log.Error("PlayerAuthWithRefresh - Could not get token info for refresh")
-					c.JSON(http.StatusUnauthorized, gin.H{"error": "Token expired and refresh failed"})
-					c.Abort()
-					return
-				}
-
-				// Attempt to refresh the token (this will delete the old token)
-				refreshResp, refreshErr := authService.RefreshToken(c.Request.Context(), token)
-				if refreshErr != nil {
-					log.Error("PlayerAuthWithRefresh - Token refresh failed")
-					c.JSON(http.StatusUnauthorized, gin.H{"error": "Token expired and refresh failed"})
-					c.Abort()
-					return
-				}
-
-				// Set the new token in response headers
-				c.Header("X-New-Token", refreshResp.Token)
+		var tokenExpiredErr *models.TokenExpiredError
+		if errors.As(err, &tokenExpiredErr) && !knowsRefreshTokens(c) {
+			renewed, renewErr := authService.RenewAccessToken(c.Request.Context(), token)
+			if renewErr == nil {
+				c.Header("X-New-Token", renewed.Token)
 				c.Header("X-Token-Refreshed", "true")
-
-				// Use the player from the refresh response
-				player = &refreshResp.Player
+				player, err = &renewed.Player, nil
 			} else {
-				log.Error("PlayerAuthWithRefresh - Invalid token")
-				c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
-				c.Abort()
-				return
+				log.Infof("PlayerAuth - Could not renew expired token: %v", renewErr)
 			}
 		}
+		if err != nil {
+			log.Error("PlayerAuth - Invalid or expired token")
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
+			c.Abort()
+			return
+		}
 
 		// Store player in Gin context
 		c.Set("player", player)
@@ -151,3 +139,9 @@ func PlayerAuthWithRefresh(authService services.AuthServiceInterface) gin.Handle
 		c.Next()
 	}
 }
+
+// knowsRefreshTokens checks if the app sending the request refreshes its own tokens
+func knowsRefreshTokens(c *gin.Context) bool {
+	appVersion := c.GetHeader("X-App-Version")
+	return appVersion != "" && compareVersions(appVersion, RefreshTokenAppVersion) >= 0
+}
@@ -5,10 +5,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"ligain/backend/models"
+	"ligain/backend/ratelimit"
+	"ligain/backend/repositories"
 	"ligain/backend/services"
 	"net/http"
 	"net/http/httptest"
-	"os"
 	"testing"
 	"time"
 
@@ -142,6 +143,10 @@ func (m *MockPlayerRepository) UpdateAvatarSignedURL(ctx context.Context, player
 	return nil
 }
 
+func (m *MockPlayerRepository) GetPlayersWithAvatarURLExpiringBefore(ctx context.Context, before time.Time) ([]*models.PlayerData, error) {
+	return nil, nil
+}
+
 func (m *MockPlayerRepository) ClearAvatar(ctx context.Context, playerID string) error {
 	return nil
 }
@@ -163,7 +168,7 @@ func (m *MockAuthService) Authenticate(ctx context.Context, req *models.AuthRequ
 	return nil, fmt.Errorf("not implemented")
 }
 
-func (m *MockAuthService) AuthenticateGuest(ctx context.Context, displayName string) (*models.AuthResponse, error) {
+func (m *MockAuthService) AuthenticateGuest(ctx context.Context, displayName string, secret string) (*models.AuthResponse, error) {
 	return nil, fmt.Errorf("not implemented")
 }
 
@@ -212,11 +217,46 @@ func (m *MockAuthService) RefreshToken(ctx context.Context, expiredToken string)
 	return nil, fmt.Errorf("token not found for refresh")
 }
 
-func (m *MockAuthService) GetAuthTokenDirectly(ctx context.Context, token string) (*models.AuthToken, error) {
-	if authToken, exists := m.tokens[token]; exists {
-		return authToken, nil
+func (m *MockAuthService) GetPlayer(ctx context.Context, playerID string) (*models.PlayerData, error) {
+	if m.shouldFail || m.player == nil {
+		return nil, fmt.Errorf("mock get player failed")
 	}
-	return nil, nil
+	return m.player, nil
+}
+
+func (m *MockAuthService) RegenerateGuestSecret(ctx context.Context, playerID string) (string, error) {
+	if m.shouldFail {
+		return "", fmt.Errorf("mock regenerate guest secret failed")
+	}
+	return "ABCD-EFGH-IJKL-MNOP", nil
+}
+
+func (m *MockAuthService) LinkProvider(ctx context.Context, playerID string, req *models.AuthRequest) (*models.AuthResponse, error) {
+	if m.shouldFail || m.player == nil {
+		return nil, fmt.Errorf("mock link provider failed")
+	}
+	return &models.AuthResponse{Player: *m.player, Token: "linked-token"}, nil
+}
+
+func (m *MockAuthService) ListSessions(ctx context.Context, playerID string, currentToken string) ([]*models.Session, error) {
+	if m.shouldFail {
+		return nil, fmt.Errorf("mock list sessions failed")
+	}
+	return []*models.Session{{ID: "current-session", Current: true}}, nil
+}
+
+func (m *MockAuthService) RevokeSession(ctx context.Context, playerID string, sessionID string) error {
+	if m.shouldFail {
+		return fmt.Errorf("mock revoke session failed")
+	}
+	return nil
+}
+
+func (m *MockAuthService) RevokeOtherSessions(ctx context.Context, playerID string, currentToken string) (int, error) {
+	if m.shouldFail {
+		return 0, fmt.Errorf("mock revoke sessions failed")
+	}
+	return 0, nil
 }
 
 func (m *MockAuthService) RefreshTokenByPlayerID(ctx context.Context, playerID string) (*models.AuthResponse, error) {
@@ -230,6 +270,10 @@ func (m *MockAuthService) RefreshTokenByPlayerID(ctx context.Context, playerID s
 	}, nil
 }
 
+func (m *MockAuthService) RenewAccessToken(ctx context.Context, expiredToken string) (*models.AuthResponse, error) {
+	return nil, &models.TokenExpiredError{Reason: "mock renewal"}
+}
+
 func (m *MockAuthService) Logout(ctx context.Context, token string) error {
 	return nil
 }
@@ -256,21 +300,30 @@ func setupTestRouter() *gin.Engine {
 	return router
 }
 
-func TestAPIKeyAuth_ValidKey(t *testing.T) {
-	// Set up test API key
-	testAPIKey := "test_api_key_123"
-	os.Setenv("API_KEY", testAPIKey)
-	defer os.Unsetenv("API_KEY")
+// newTestAPIKeyService creates a key store holding an app key and a read-only partner key
+func newTestAPIKeyService(t *testing.T) *services.APIKeyServiceImpl {
+	keyService := services.NewAPIKeyService(repositories.NewInMemoryAPIKeyRepository())
+	_, err := keyService.ImportKey(context.Background(), "ios", "test_api_key_123", []string{models.APIKeyScopeRead, models.APIKeyScopeWrite}, 0)
+	assert.NoError(t, err)
+	_, err = keyService.ImportKey(context.Background(), "partner", "partner_key_456", []string{models.APIKeyScopeRead}, 2)
+	assert.NoError(t, err)
+	return keyService
+}
 
+func TestAPIKeyAuth_ValidKey(t *testing.T) {
 	router := setupTestRouter()
-	router.Use(APIKeyAuth())
+	router.Use(APIKeyAuth(newTestAPIKeyService(t), ratelimit.NewInMemoryStore()))
 
 	router.GET("/test", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{"message": "success"})
+		c.JSON(http.StatusOK, gin.H{
+			"message": "success",
+			"client":  APIKeyName(c),
+			"context": services.APIKeyNameFromContext(c.Request.Context()),
+		})
 	})
 
 	req := httptest.NewRequest("GET", "/test", nil)
-	req.Header.Set("X-API-Key", testAPIKey)
+	req.Header.Set("X-API-Key", "test_api_key_123")
 	w := httptest.NewRecorder()
 
 	router.ServeHTTP(w, req)
@@ -281,11 +334,13 @@ func TestAPIKeyAuth_ValidKey(t *testing.T) {
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(t, err)
 	assert.Equal(t, "success", response["message"])
+	assert.Equal(t, "ios", response["client"])
+	assert.Equal(t, "ios", response["context"])
 }
 
 func TestAPIKeyAuth_MissingKey(t *testing.T) {
 	router := setupTestRouter()
-	router.Use(APIKeyAuth())
+	router.Use(APIKeyAuth(newTestAPIKeyService(t), ratelimit.NewInMemoryStore()))
 
 	router.GET("/test", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{"message": "success"})
@@ -306,13 +361,8 @@ func TestAPIKeyAuth_MissingKey(t *testing.T) {
 }
 
 func TestAPIKeyAuth_InvalidKey(t *testing.T) {
-	// Set up test API key
-	testAPIKey := "test_api_key_123"
-	os.Setenv("API_KEY", testAPIKey)
-	defer os.Unsetenv("API_KEY")
-
 	router := setupTestRouter()
-	router.Use(APIKeyAuth())
+	router.Use(APIKeyAuth(newTestAPIKeyService(t), ratelimit.NewInMemoryStore()))
 
 	router.GET("/test", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{"message": "success"})
@@ -332,6 +382,49 @@ func TestAPIKeyAuth_InvalidKey(t *testing.T) {
 	assert.Equal(t, "Invalid API key", response["error"])
 }
 
+func TestAPIKeyAuth_Scopes(t *testing.T) {
+	router := setupTestRouter()
+	router.Use(APIKeyAuth(newTestAPIKeyService(t), ratelimit.NewInMemoryStore()))
+	router.GET("/test", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.POST("/test", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	send := func(method string, key string) int {
+		req := httptest.NewRequest(method, "/test", nil)
+		req.Header.Set("X-API-Key", key)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusOK, send("POST", "test_api_key_123"))
+	assert.Equal(t, http.StatusOK, send("GET", "partner_key_456"))
+	assert.Equal(t, http.StatusForbidden, send("POST", "partner_key_456"))
+}
+
+func TestAPIKeyAuth_RateLimit(t *testing.T) {
+	router := setupTestRouter()
+	router.Use(APIKeyAuth(newTestAPIKeyService(t), ratelimit.NewInMemoryStore()))
+	router.GET("/test", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	send := func(key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/test", nil)
+		req.Header.Set("X-API-Key", key)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// The partner key allows 2 requests per minute
+	assert.Equal(t, http.StatusOK, send("partner_key_456").Code)
+	assert.Equal(t, http.StatusOK, send("partner_key_456").Code)
+	w := send("partner_key_456")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.NotEmpty(t, w.Header().Get("Retry-After"))
+
+	// The other keys have their own limit
+	assert.Equal(t, http.StatusOK, send("test_api_key_123").Code)
+}
+
 func TestPlayerAuth_ValidToken(t *testing.T) {
 	mockRepo := NewMockPlayerRepository()
 	authService := services.NewAuthServiceWithTimeFunc(mockRepo, nil, func() time.Time { return frozenTime })
@@ -492,136 +585,65 @@ func TestPlayerAuth_ExpiredToken(t *testing.T) {
 	assert.Equal(t, "Invalid or expired token", response["error"])
 }
 
-// TestPlayerAuthWithRefresh tests the PlayerAuthWithRefresh middleware
-func TestPlayerAuthWithRefresh(t *testing.T) {
-	mockAuthService := NewMockAuthService()
-
-	// Create test tokens in the mock auth service
-	expiredToken := &models.AuthToken{
-		PlayerID:  "test_player_id",
-		Token:     "expired_token_123",
-		ExpiresAt: frozenTime.Add(-25 * time.Hour), // Expired (more than 24 hours)
-	}
-	mockAuthService.tokens["expired_token_123"] = expiredToken
+func TestPlayerAuth_RenewsExpiredTokenOfOldApps(t *testing.T) {
+	mockRepo := NewMockPlayerRepository()
+	now := frozenTime
+	authService := services.NewAuthServiceWithTimeFunc(mockRepo, nil, func() time.Time { return now })
 
-	validToken := &models.AuthToken{
-		PlayerID:  "test_player_id",
-		Token:     "valid_token_123",
-		ExpiresAt: frozenTime.Add(1 * time.Hour), // Valid
+	signIn, err := authService.AuthenticateGuest(context.Background(), "Guest Player", "")
+	if err != nil {
+		t.Fatalf("guest sign-in failed: %v", err)
 	}
-	mockAuthService.tokens["valid_token_123"] = validToken
+	now = frozenTime.Add(services.DefaultAccessTokenTTL + time.Hour)
 
 	router := setupTestRouter()
-	router.Use(PlayerAuthWithRefresh(mockAuthService))
-
+	router.Use(PlayerAuth(authService))
 	router.GET("/test", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{"message": "success"})
 	})
 
-	t.Run("ValidToken", func(t *testing.T) {
+	request := func(appVersion string) *httptest.ResponseRecorder {
 		req := httptest.NewRequest("GET", "/test", nil)
-		req.Header.Set("Authorization", "Bearer valid_token_123")
-		w := httptest.NewRecorder()
-
-		router.ServeHTTP(w, req)
-
-		assert.Equal(t, http.StatusOK, w.Code)
-
-		var response map[string]interface{}
-		err := json.Unmarshal(w.Body.Bytes(), &response)
-		assert.NoError(t, err)
-		assert.Equal(t, "success", response["message"])
-	})
-
-	t.Run("ExpiredTokenWithRefresh", func(t *testing.T) {
-		req := httptest.NewRequest("GET", "/test", nil)
-		req.Header.Set("Authorization", "Bearer expired_token_123")
+		req.Header.Set("Authorization", "Bearer "+signIn.Token)
+		if appVersion != "" {
+			req.Header.Set("X-App-Version", appVersion)
+		}
 		w := httptest.NewRecorder()
-
 		router.ServeHTTP(w, req)
+		return w
+	}
 
-		assert.Equal(t, http.StatusOK, w.Code)
-
-		// Check that the response headers indicate token refresh
+	for _, appVersion := range []string{"", "2.0.0"} {
+		w := request(appVersion)
+		assert.Equal(t, http.StatusOK, w.Code, "app version %q", appVersion)
 		assert.Equal(t, "true", w.Header().Get("X-Token-Refreshed"))
-		assert.NotEmpty(t, w.Header().Get("X-New-Token"))
+		newToken := w.Header().Get("X-New-Token")
+		assert.NotEmpty(t, newToken)
 
-		var response map[string]interface{}
-		err := json.Unmarshal(w.Body.Bytes(), &response)
+		player, err := authService.ValidateToken(context.Background(), newToken)
 		assert.NoError(t, err)
-		assert.Equal(t, "success", response["message"])
-	})
-
-	t.Run("InvalidToken", func(t *testing.T) {
-		req := httptest.NewRequest("GET", "/test", nil)
-		req.Header.Set("Authorization", "Bearer invalid_token")
-		w := httptest.NewRecorder()
-
-		router.ServeHTTP(w, req)
-
-		assert.Equal(t, http.StatusUnauthorized, w.Code)
-
-		var response map[string]interface{}
-		err := json.Unmarshal(w.Body.Bytes(), &response)
-		assert.NoError(t, err)
-		assert.Equal(t, "Invalid token", response["error"])
-	})
-
-	t.Run("MissingAuthorizationHeader", func(t *testing.T) {
-		req := httptest.NewRequest("GET", "/test", nil)
-		w := httptest.NewRecorder()
-
-		router.ServeHTTP(w, req)
-
-		assert.Equal(t, http.StatusUnauthorized, w.Code)
-
-		var response map[string]interface{}
-		err := json.Unmarshal(w.Body.Bytes(), &response)
-		assert.NoError(t, err)
-		assert.Equal(t, "Authorization header is required", response["error"])
-	})
-
-	t.Run("InvalidAuthorizationFormat", func(t *testing.T) {
-		req := httptest.NewRequest("GET", "/test", nil)
-		req.Header.Set("Authorization", "InvalidFormat valid_token_123")
-		w := httptest.NewRecorder()
-
-		router.ServeHTTP(w, req)
-
-		assert.Equal(t, http.StatusUnauthorized, w.Code)
-
-		var response map[string]interface{}
-		err := json.Unmarshal(w.Body.Bytes(), &response)
-		assert.NoError(t, err)
-		assert.Equal(t, "Invalid authorization header format", response["error"])
-	})
-}
-
-// TestPlayerAuthWithRefreshFailedRefresh tests when token refresh fails
-func TestPlayerAuthWithRefreshFailedRefresh(t *testing.T) {
-	// Create a mock auth service that fails refresh operations
-	mockAuthService := NewMockAuthService()
-	mockAuthService.shouldFail = true
-
-	router := setupTestRouter()
-	router.Use(PlayerAuthWithRefresh(mockAuthService))
-
-	router.GET("/test", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{"message": "success"})
-	})
-
-	req := httptest.NewRequest("GET", "/test", nil)
-	req.Header.Set("Authorization", "Bearer any_token")
-	w := httptest.NewRecorder()
-
-	router.ServeHTTP(w, req)
+		assert.Equal(t, signIn.Player.ID, player.ID)
+	}
 
+	// The apps that know refresh tokens refresh on their own
+	w := request(RefreshTokenAppVersion)
 	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Empty(t, w.Header().Get("X-New-Token"))
+
+	// The header is sent by the client, a token expired for too long isn't renewed whatever it says
+	now = frozenTime.Add(services.DefaultAccessTokenTTL + services.AccessTokenRenewalWindow + time.Minute)
+	for _, appVersion := range []string{"", "2.0.0"} {
+		w := request(appVersion)
+		assert.Equal(t, http.StatusUnauthorized, w.Code, "app version %q", appVersion)
+		assert.Empty(t, w.Header().Get("X-New-Token"))
+	}
 
-	var response map[string]interface{}
-	err := json.Unmarshal(w.Body.Bytes(), &response)
-	assert.NoError(t, err)
-	assert.Equal(t, "Token expired and refresh failed", response["error"])
+	// Signing out ends the renewals
+	now = frozenTime.Add(services.DefaultAccessTokenTTL + time.Hour)
+	assert.NoError(t, authService.Logout(context.Background(), signIn.RefreshToken))
+	w = request("2.0.0")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Empty(t, w.Header().Get("X-New-Token"))
 }
 
 // MockPlayerRepositoryWithErrors is a mock that returns errors for testing error scenarios
@@ -691,6 +713,10 @@ func (m *MockPlayerRepositoryWithErrors) UpdateAvatarSignedURL(ctx context.Conte
 	return fmt.Errorf("mock error")
 }
 
+func (m *MockPlayerRepositoryWithErrors) GetPlayersWithAvatarURLExpiringBefore(ctx context.Context, before time.Time) ([]*models.PlayerData, error) {
+	return nil, fmt.Errorf("mock error")
+}
+
 func (m *MockPlayerRepositoryWithErrors) ClearAvatar(ctx context.Context, playerID string) error {
 	return fmt.Errorf("mock error")
 }
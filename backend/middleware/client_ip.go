@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"net"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CloudRunClientIP middleware takes the address of the client from the right-most hop of X-Forwarded-For,
+// the one appended by the Google front end of Cloud Run. The hops before it are sent by the client and can't be trusted.
+// The router must trust no proxy, so that c.ClientIP() returns the address set here and never reads the headers itself
+func CloudRunClientIP() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if ip := rightmostForwardedIP(c.Request.Header.Values("X-Forwarded-For")); ip != "" {
+			c.Request.RemoteAddr = net.JoinHostPort(ip, "0")
+		}
+		c.Next()
+	}
+}
+
+// rightmostForwardedIP returns the last address of the X-Forwarded-For headers, or an empty string if it isn't an IP address
+func rightmostForwardedIP(headers []string) string {
+	if len(headers) == 0 {
+		return ""
+	}
+	hops := strings.Split(headers[len(headers)-1], ",")
+	ip := net.ParseIP(strings.TrimSpace(hops[len(hops)-1]))
+	if ip == nil {
+		return ""
+	}
+	return ip.String()
+}
@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupClientIPRouter answers each request with its rate limit key by IP
+func setupClientIPRouter(t *testing.T, behindCloudRun bool) *gin.Engine {
+	router := setupTestRouter()
+	require.NoError(t, router.SetTrustedProxies(nil))
+	if behindCloudRun {
+		router.Use(CloudRunClientIP())
+	}
+	router.GET("/key", func(c *gin.Context) { c.String(http.StatusOK, RateLimitByIP(c)) })
+	return router
+}
+
+func rateLimitKey(router *gin.Engine, forwardedFor string) string {
+	req := httptest.NewRequest("GET", "/key", nil)
+	req.RemoteAddr = "169.254.1.1:1234"
+	if forwardedFor != "" {
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w.Body.String()
+}
+
+func TestCloudRunClientIP_SpoofedForwardedForKeepsTheKey(t *testing.T) {
+	router := setupClientIPRouter(t, true)
+
+	// The Google front end appends the address it received the request from
+	key := rateLimitKey(router, "203.0.113.7")
+	assert.Equal(t, "ip:203.0.113.7", key)
+
+	assert.Equal(t, key, rateLimitKey(router, "198.51.100.1, 203.0.113.7"))
+	assert.Equal(t, key, rateLimitKey(router, "10.0.0.1, 198.51.100.2, 203.0.113.7"))
+}
+
+func TestCloudRunClientIP_InvalidHop(t *testing.T) {
+	router := setupClientIPRouter(t, true)
+
+	assert.Equal(t, "ip:169.254.1.1", rateLimitKey(router, "203.0.113.7, not-an-ip"))
+	assert.Equal(t, "ip:169.254.1.1", rateLimitKey(router, ""))
+}
+
+func TestClientIP_NoTrustedProxyIgnoresForwardedFor(t *testing.T) {
+	router := setupClientIPRouter(t, false)
+
+	assert.Equal(t, "ip:169.254.1.1", rateLimitKey(router, "198.51.100.1"))
+}
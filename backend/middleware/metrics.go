@@ -34,6 +34,7 @@ func MetricsMiddleware() gin.HandlerFunc {
 			"path":        c.Request.URL.Path,
 			"client_ip":   c.ClientIP(),
 			"user_agent":  c.Request.UserAgent(),
+			"api_key":     APIKeyName(c),
 			"metric_type": "http_request",
 		}).Info("request completed")
 	}
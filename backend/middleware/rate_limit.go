@@ -0,0 +1,118 @@
+package middleware
+
+import (
+	"fmt"
+	"ligain/backend/ratelimit"
+	"ligain/backend/services"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+// RateLimitKeyFunc returns the client a request is counted against
+type RateLimitKeyFunc func(c *gin.Context) string
+
+// RateLimitRule limits the requests to a route, like POST /api/games/join
+type RateLimitRule struct {
+	Method string
+	// Route is the route pattern, as registered on the router
+	Route  string
+	Policy ratelimit.Policy
+	Key    RateLimitKeyFunc
+}
+
+// RateLimitByIP counts the requests of each IP address
+func RateLimitByIP(c *gin.Context) string {
+	return "ip:" + c.ClientIP()
+}
+
+// RateLimitByAPIKey counts the requests of each client, falling back to the IP address without an API key
+func RateLimitByAPIKey(c *gin.Context) string {
+	if name := APIKeyName(c); name != "" {
+		return "key:" + name
+	}
+	return RateLimitByIP(c)
+}
+
+// RateLimitByPlayer counts the requests of each player. The rate limit runs before PlayerAuth, so the token
+// is verified here too, which doesn't need the database for signed access tokens.
+// Requests without a valid token fall back to their IP address, and are rejected by PlayerAuth anyway
+func RateLimitByPlayer(authService services.AuthServiceInterface) RateLimitKeyFunc {
+	return func(c *gin.Context) string {
+		token, found := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if found && token != "" {
+			if player, err := authService.ValidateToken(c.Request.Context(), token); err == nil {
+				return "player:" + player.ID
+			}
+		}
+		return RateLimitByIP(c)
+	}
+}
+
+// RateLimit middleware enforces the rules of the route of each request.
+// The limit closest to being reached is reported with the RateLimit-* headers, and the store failing lets requests through
+func RateLimit(store ratelimit.Store, rules []RateLimitRule) gin.HandlerFunc {
+	rulesByRoute := make(map[string][]RateLimitRule)
+	for _, rule := range rules {
+		route := rule.Method + " " + rule.Route
+		rulesByRoute[route] = append(rulesByRoute[route], rule)
+	}
+
+	return func(c *gin.Context) {
+		routeRules := rulesByRoute[c.Request.Method+" "+c.FullPath()]
+		if len(routeRules) == 0 {
+			c.Next()
+			return
+		}
+
+		now := time.Now()
+		var closest *ratelimit.Result
+		var closestPolicy ratelimit.Policy
+		for _, rule := range routeRules {
+			result, err := store.Take(c.Request.Context(), rule.Key(c), rule.Policy, now)
+			if err != nil {
+				log.Errorf("RateLimit - Failed to check the %s limit: %v", rule.Policy.Name, err)
+				continue
+			}
+			if !result.Allowed {
+				rejectRateLimited(c, rule.Policy, result)
+				return
+			}
+			if closest == nil || result.Remaining < closest.Remaining {
+				closest = &result
+				closestPolicy = rule.Policy
+			}
+		}
+
+		if closest != nil {
+			setRateLimitHeaders(c, closestPolicy, *closest)
+		}
+		c.Next()
+	}
+}
+
+// rejectRateLimited answers a request over its limit
+func rejectRateLimited(c *gin.Context, policy ratelimit.Policy, result ratelimit.Result) {
+	log.Warnf("RateLimit - %s %s over the %s limit for %s", c.Request.Method, c.Request.URL.Path, policy.Name, c.ClientIP())
+	setRateLimitHeaders(c, policy, result)
+	c.Header("Retry-After", strconv.Itoa(ceilSeconds(result.RetryAfter)))
+	c.JSON(http.StatusTooManyRequests, gin.H{"error": "Too many requests, please retry later"})
+	c.Abort()
+}
+
+// setRateLimitHeaders sets the RateLimit-* headers of the IETF httpapi draft
+func setRateLimitHeaders(c *gin.Context, policy ratelimit.Policy, result ratelimit.Result) {
+	c.Header("RateLimit-Limit", strconv.Itoa(result.Limit))
+	c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	c.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.ResetAfter)))
+	c.Header("RateLimit-Policy", fmt.Sprintf("%d;w=%d", policy.Limit, ceilSeconds(policy.Period)))
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,133 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"ligain/backend/models"
+	"ligain/backend/ratelimit"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// failingRateLimitStore is a store whose backend is down
+type failingRateLimitStore struct{}
+
+func (s *failingRateLimitStore) Take(ctx context.Context, key string, policy ratelimit.Policy, now time.Time) (ratelimit.Result, error) {
+	return ratelimit.Result{}, errors.New("database down")
+}
+
+func (s *failingRateLimitStore) Prune(ctx context.Context, unusedSince time.Time) error {
+	return nil
+}
+
+func setupRateLimitRouter(store ratelimit.Store, rules []RateLimitRule) *gin.Engine {
+	router := setupTestRouter()
+	router.Use(RateLimit(store, rules))
+	router.POST("/api/games/join", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.POST("/api/game/:game-id/bet", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.GET("/api/games", func(c *gin.Context) { c.Status(http.StatusOK) })
+	return router
+}
+
+func sendRateLimited(router *gin.Engine, method string, path string, token string, ip string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	req.RemoteAddr = ip + ":1234"
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestRateLimit_ByIP(t *testing.T) {
+	router := setupRateLimitRouter(ratelimit.NewInMemoryStore(), []RateLimitRule{{
+		Method: http.MethodPost,
+		Route:  "/api/games/join",
+		Policy: ratelimit.Policy{Name: "join", Limit: 2, Period: time.Minute},
+		Key:    RateLimitByIP,
+	}})
+
+	w := sendRateLimited(router, "POST", "/api/games/join", "", "10.0.0.1")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "2", w.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "1", w.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "30", w.Header().Get("RateLimit-Reset"))
+	assert.Equal(t, "2;w=60", w.Header().Get("RateLimit-Policy"))
+
+	assert.Equal(t, http.StatusOK, sendRateLimited(router, "POST", "/api/games/join", "", "10.0.0.1").Code)
+
+	w = sendRateLimited(router, "POST", "/api/games/join", "", "10.0.0.1")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "0", w.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "30", w.Header().Get("Retry-After"))
+
+	// Other addresses and other routes aren't affected
+	assert.Equal(t, http.StatusOK, sendRateLimited(router, "POST", "/api/games/join", "", "10.0.0.2").Code)
+	w = sendRateLimited(router, "GET", "/api/games", "", "10.0.0.1")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("RateLimit-Limit"))
+}
+
+func TestRateLimit_ByPlayer(t *testing.T) {
+	authService := NewMockAuthService()
+	authService.tokens["alice-token"] = &models.AuthToken{PlayerID: "alice", ExpiresAt: time.Now().Add(time.Hour)}
+	authService.tokens["bob-token"] = &models.AuthToken{PlayerID: "bob", ExpiresAt: time.Now().Add(time.Hour)}
+
+	router := setupRateLimitRouter(ratelimit.NewInMemoryStore(), []RateLimitRule{{
+		Method: http.MethodPost,
+		Route:  "/api/game/:game-id/bet",
+		Policy: ratelimit.Policy{Name: "bets", Limit: 1, Period: time.Minute},
+		Key:    RateLimitByPlayer(authService),
+	}})
+
+	// The players share an address, but are counted on their own
+	assert.Equal(t, http.StatusOK, sendRateLimited(router, "POST", "/api/game/game1/bet", "alice-token", "10.0.0.1").Code)
+	assert.Equal(t, http.StatusOK, sendRateLimited(router, "POST", "/api/game/game2/bet", "bob-token", "10.0.0.1").Code)
+	assert.Equal(t, http.StatusTooManyRequests, sendRateLimited(router, "POST", "/api/game/game2/bet", "alice-token", "10.0.0.1").Code)
+
+	// Without a valid token, the address is counted
+	assert.Equal(t, http.StatusOK, sendRateLimited(router, "POST", "/api/game/game1/bet", "forged", "10.0.0.1").Code)
+	assert.Equal(t, http.StatusTooManyRequests, sendRateLimited(router, "POST", "/api/game/game1/bet", "", "10.0.0.1").Code)
+}
+
+func TestRateLimit_ReportsClosestLimit(t *testing.T) {
+	router := setupRateLimitRouter(ratelimit.NewInMemoryStore(), []RateLimitRule{
+		{
+			Method: http.MethodPost,
+			Route:  "/api/games/join",
+			Policy: ratelimit.Policy{Name: "join-burst", Limit: 5, Period: time.Minute},
+			Key:    RateLimitByIP,
+		},
+		{
+			Method: http.MethodPost,
+			Route:  "/api/games/join",
+			Policy: ratelimit.Policy{Name: "join-daily", Limit: 3, Period: 24 * time.Hour},
+			Key:    RateLimitByIP,
+		},
+	})
+
+	w := sendRateLimited(router, "POST", "/api/games/join", "", "10.0.0.1")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "3", w.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "2", w.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "3;w=86400", w.Header().Get("RateLimit-Policy"))
+}
+
+func TestRateLimit_StoreFailureLetsRequestsThrough(t *testing.T) {
+	router := setupRateLimitRouter(&failingRateLimitStore{}, []RateLimitRule{{
+		Method: http.MethodPost,
+		Route:  "/api/games/join",
+		Policy: ratelimit.Policy{Name: "join", Limit: 1, Period: time.Minute},
+		Key:    RateLimitByIP,
+	}})
+
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, http.StatusOK, sendRateLimited(router, "POST", "/api/games/join", "", "10.0.0.1").Code)
+	}
+}
@@ -0,0 +1,3 @@
+-- Remove player_achievement table
+DROP INDEX IF EXISTS idx_player_achievement_player_id;
+DROP TABLE IF EXISTS player_achievement;
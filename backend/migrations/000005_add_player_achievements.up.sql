@@ -0,0 +1,13 @@
+-- Add player_achievement table to store the badges awarded to players
+CREATE TABLE IF NOT EXISTS player_achievement (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    player_id UUID NOT NULL REFERENCES player(id) ON DELETE CASCADE,
+    game_id UUID NOT NULL REFERENCES game(id) ON DELETE CASCADE,
+    code VARCHAR(64) NOT NULL,
+    match_local_id TEXT NOT NULL,
+    awarded_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    -- A player can only earn each badge once per game
+    CONSTRAINT unique_player_game_achievement UNIQUE (player_id, game_id, code)
+);
+
+CREATE INDEX IF NOT EXISTS idx_player_achievement_player_id ON player_achievement(player_id);
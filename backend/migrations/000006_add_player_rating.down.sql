@@ -0,0 +1,3 @@
+-- Remove player_rating table
+DROP INDEX IF EXISTS idx_player_rating_rating;
+DROP TABLE IF EXISTS player_rating;
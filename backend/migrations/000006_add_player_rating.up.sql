@@ -0,0 +1,9 @@
+-- Add player_rating table to store the cross-game skill rating of players
+CREATE TABLE IF NOT EXISTS player_rating (
+    player_id UUID PRIMARY KEY REFERENCES player(id) ON DELETE CASCADE,
+    rating DOUBLE PRECISION NOT NULL DEFAULT 1500,
+    matches_rated INTEGER NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_player_rating_rating ON player_rating(rating DESC);
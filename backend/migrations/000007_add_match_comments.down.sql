@@ -0,0 +1,4 @@
+-- Remove match comments and reactions tables
+DROP TABLE IF EXISTS match_reaction;
+DROP INDEX IF EXISTS idx_match_comment_game_match;
+DROP TABLE IF EXISTS match_comment;
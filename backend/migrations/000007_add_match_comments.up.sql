@@ -0,0 +1,21 @@
+-- Add tables for the comments and emoji reactions posted on the matches of a game
+CREATE TABLE IF NOT EXISTS match_comment (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    game_id UUID NOT NULL REFERENCES game(id) ON DELETE CASCADE,
+    match_local_id TEXT NOT NULL,
+    player_id UUID NOT NULL REFERENCES player(id) ON DELETE CASCADE,
+    content TEXT NOT NULL,
+    mentions_bet BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_match_comment_game_match ON match_comment(game_id, match_local_id, created_at);
+
+CREATE TABLE IF NOT EXISTS match_reaction (
+    game_id UUID NOT NULL REFERENCES game(id) ON DELETE CASCADE,
+    match_local_id TEXT NOT NULL,
+    player_id UUID NOT NULL REFERENCES player(id) ON DELETE CASCADE,
+    emoji VARCHAR(32) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (game_id, match_local_id, player_id, emoji)
+);
@@ -0,0 +1,3 @@
+-- Remove game_activity table
+DROP INDEX IF EXISTS idx_game_activity_game_id;
+DROP TABLE IF EXISTS game_activity;
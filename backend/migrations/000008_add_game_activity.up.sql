@@ -0,0 +1,14 @@
+-- Add game_activity table to store the activity feed of the games
+CREATE TABLE IF NOT EXISTS game_activity (
+    id BIGSERIAL PRIMARY KEY,
+    game_id UUID NOT NULL REFERENCES game(id) ON DELETE CASCADE,
+    type VARCHAR(32) NOT NULL,
+    -- The player is kept by name, so that the feed still makes sense after they leave the game
+    player_id UUID,
+    player_name VARCHAR(255),
+    match_local_id TEXT,
+    details JSONB NOT NULL DEFAULT '{}'::jsonb,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_game_activity_game_id ON game_activity(game_id, id DESC);
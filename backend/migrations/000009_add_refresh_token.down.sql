@@ -0,0 +1,4 @@
+-- Remove refresh_token table
+DROP INDEX IF EXISTS idx_refresh_token_player_id;
+DROP INDEX IF EXISTS idx_refresh_token_family_id;
+DROP TABLE IF EXISTS refresh_token;
@@ -0,0 +1,14 @@
+-- Add refresh_token table. Access tokens are signed and never stored, only the hash of the refresh tokens is
+CREATE TABLE IF NOT EXISTS refresh_token (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    player_id UUID NOT NULL REFERENCES player(id) ON DELETE CASCADE,
+    -- Every rotation keeps the family, so that a reused token revokes the whole chain
+    family_id UUID NOT NULL,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    revoked_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_refresh_token_family_id ON refresh_token(family_id);
+CREATE INDEX IF NOT EXISTS idx_refresh_token_player_id ON refresh_token(player_id);
@@ -0,0 +1,6 @@
+-- Remove the session device columns from refresh_token
+ALTER TABLE refresh_token DROP COLUMN IF EXISTS session_started_at;
+ALTER TABLE refresh_token DROP COLUMN IF EXISTS ip_address;
+ALTER TABLE refresh_token DROP COLUMN IF EXISTS app_version;
+ALTER TABLE refresh_token DROP COLUMN IF EXISTS platform;
+ALTER TABLE refresh_token DROP COLUMN IF EXISTS device_name;
@@ -0,0 +1,6 @@
+-- Add the device of the sessions to refresh_token. A session is a token family, its live token holds the latest device info
+ALTER TABLE refresh_token ADD COLUMN IF NOT EXISTS device_name VARCHAR(100);
+ALTER TABLE refresh_token ADD COLUMN IF NOT EXISTS platform VARCHAR(20);
+ALTER TABLE refresh_token ADD COLUMN IF NOT EXISTS app_version VARCHAR(20);
+ALTER TABLE refresh_token ADD COLUMN IF NOT EXISTS ip_address VARCHAR(45);
+ALTER TABLE refresh_token ADD COLUMN IF NOT EXISTS session_started_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP;
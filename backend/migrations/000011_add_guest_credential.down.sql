@@ -0,0 +1,2 @@
+-- Remove guest_credential table
+DROP TABLE IF EXISTS guest_credential;
@@ -0,0 +1,6 @@
+-- Add guest_credential table. Guests present their secret to sign in again, only its hash is stored
+CREATE TABLE IF NOT EXISTS guest_credential (
+    player_id UUID PRIMARY KEY REFERENCES player(id) ON DELETE CASCADE,
+    secret_hash VARCHAR(64) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
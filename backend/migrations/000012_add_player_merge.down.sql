@@ -0,0 +1,2 @@
+-- Remove player_merge table
+DROP TABLE IF EXISTS player_merge;
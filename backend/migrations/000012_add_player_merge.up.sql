@@ -0,0 +1,13 @@
+-- Add player_merge table to record the accounts merged into another one.
+-- The OAuth identity of a merged account is kept, so that signing in with it leads to the account it was merged into
+CREATE TABLE IF NOT EXISTS player_merge (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    source_player_id UUID NOT NULL,
+    target_player_id UUID NOT NULL REFERENCES player(id) ON DELETE CASCADE,
+    provider VARCHAR(50),
+    provider_id VARCHAR(255),
+    merged_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT unique_merged_provider_id UNIQUE (provider, provider_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_player_merge_target_player_id ON player_merge(target_player_id);
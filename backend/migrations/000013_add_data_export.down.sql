@@ -0,0 +1,2 @@
+-- Remove data_export table
+DROP TABLE IF EXISTS data_export;
@@ -0,0 +1,14 @@
+-- Add data_export table to track the archives players request with a copy of their personal data
+CREATE TABLE IF NOT EXISTS data_export (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    player_id UUID NOT NULL REFERENCES player(id) ON DELETE CASCADE,
+    status VARCHAR(16) NOT NULL,
+    -- The archive is kept in the blob storage until it expires
+    object_key TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    expires_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_data_export_player_id ON data_export(player_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_data_export_expires_at ON data_export(expires_at);
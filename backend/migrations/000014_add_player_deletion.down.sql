@@ -0,0 +1,2 @@
+-- Remove player_deletion table
+DROP TABLE IF EXISTS player_deletion;
@@ -0,0 +1,13 @@
+-- Add player_deletion table to record the players who deleted their account.
+-- The player is anonymized right away and keeps their bets and scores, so that the games they played stay consistent.
+-- What's needed to restore the account is kept here until the grace period ends and the personal data is purged
+CREATE TABLE IF NOT EXISTS player_deletion (
+    player_id UUID PRIMARY KEY REFERENCES player(id) ON DELETE CASCADE,
+    original_name VARCHAR(255) NOT NULL,
+    avatar_object_key TEXT,
+    deleted_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    purge_after TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_player_deletion_purge_after ON player_deletion(purge_after);
+CREATE INDEX IF NOT EXISTS idx_player_deletion_original_name ON player_deletion(original_name);
@@ -0,0 +1,2 @@
+-- Remove email_sign_in_code table
+DROP TABLE IF EXISTS email_sign_in_code;
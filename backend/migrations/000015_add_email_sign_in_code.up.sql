@@ -0,0 +1,16 @@
+-- Add email_sign_in_code table for passwordless email sign-in.
+-- Each request emails a short code along with a magic link, only their hashes are stored.
+-- The rows are short-lived and also count the recent requests of an email, for throttling
+CREATE TABLE IF NOT EXISTS email_sign_in_code (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    email VARCHAR(255) NOT NULL,
+    code_hash VARCHAR(64) NOT NULL,
+    link_token_hash VARCHAR(64) NOT NULL UNIQUE,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_email_sign_in_code_email_created_at ON email_sign_in_code(email, created_at);
+CREATE INDEX IF NOT EXISTS idx_email_sign_in_code_expires_at ON email_sign_in_code(expires_at);
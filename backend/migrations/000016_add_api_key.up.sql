@@ -0,0 +1,16 @@
+-- Add api_key table for the keys identifying the clients of the API (apps, operator tooling, partners).
+-- Only the hash of a key is stored. A client keeps its previous key until it expires while a new one is rolled out
+CREATE TABLE IF NOT EXISTS api_key (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(100) NOT NULL,
+    key_prefix VARCHAR(16) NOT NULL,
+    key_hash VARCHAR(64) NOT NULL UNIQUE,
+    -- Comma-separated scopes, like read,write
+    scopes TEXT NOT NULL,
+    rate_limit_per_minute INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP WITH TIME ZONE,
+    revoked_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_api_key_name ON api_key(name);
@@ -0,0 +1,2 @@
+-- Remove rate_limit_bucket table
+DROP TABLE IF EXISTS rate_limit_bucket;
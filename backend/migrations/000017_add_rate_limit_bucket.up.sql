@@ -0,0 +1,9 @@
+-- Add rate_limit_bucket table for the token buckets shared between the instances.
+-- A bucket is keyed by the name of its policy and its client, like bets:player:<id>
+CREATE TABLE IF NOT EXISTS rate_limit_bucket (
+    bucket_key VARCHAR(255) PRIMARY KEY,
+    tokens DOUBLE PRECISION NOT NULL,
+    refilled_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_rate_limit_bucket_refilled_at ON rate_limit_bucket(refilled_at);
@@ -0,0 +1,3 @@
+-- Remove admin_audit_log table and the role of the players
+DROP TABLE IF EXISTS admin_audit_log;
+ALTER TABLE player DROP COLUMN IF EXISTS role;
@@ -0,0 +1,17 @@
+-- Add the role of the players. Admins can use the operator API under /api/admin
+ALTER TABLE player ADD COLUMN IF NOT EXISTS role VARCHAR(16) NOT NULL DEFAULT 'player';
+
+-- Add admin_audit_log table recording every action done through the operator API.
+-- The admin is kept by name too, so that the log still makes sense after their account is deleted
+CREATE TABLE IF NOT EXISTS admin_audit_log (
+    id BIGSERIAL PRIMARY KEY,
+    admin_id UUID,
+    admin_name VARCHAR(255) NOT NULL,
+    action VARCHAR(32) NOT NULL,
+    target_type VARCHAR(16) NOT NULL,
+    target_id TEXT NOT NULL,
+    details JSONB NOT NULL DEFAULT '{}'::jsonb,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_admin_audit_log_target ON admin_audit_log(target_type, target_id, id DESC);
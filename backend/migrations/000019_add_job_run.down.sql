@@ -0,0 +1,2 @@
+-- Remove job_run table
+DROP TABLE IF EXISTS job_run;
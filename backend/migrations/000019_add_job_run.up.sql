@@ -0,0 +1,15 @@
+-- Add job_run table keeping the history of the background jobs.
+-- A job runs once per scheduled time, whichever instance claims the run first
+CREATE TABLE IF NOT EXISTS job_run (
+    id BIGSERIAL PRIMARY KEY,
+    job_name VARCHAR(64) NOT NULL,
+    instance VARCHAR(255) NOT NULL,
+    scheduled_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    finished_at TIMESTAMP WITH TIME ZONE,
+    status VARCHAR(16) NOT NULL,
+    error TEXT NOT NULL DEFAULT '',
+    UNIQUE (job_name, scheduled_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_job_run_started_at ON job_run(started_at);
@@ -0,0 +1,6 @@
+-- Remove the game_changed notifications
+DROP TRIGGER IF EXISTS game_changed ON game_player;
+DROP TRIGGER IF EXISTS game_changed ON score;
+DROP TRIGGER IF EXISTS game_changed ON bet;
+DROP TRIGGER IF EXISTS game_changed ON game;
+DROP FUNCTION IF EXISTS notify_game_changed();
@@ -0,0 +1,30 @@
+-- Notify the game_changed channel with the id of the game whose state changed, so that every instance
+-- drops it from its cache, whichever instance or script changed it. Notifications are sent on commit,
+-- and the ones repeated in a transaction are sent once
+CREATE OR REPLACE FUNCTION notify_game_changed() RETURNS trigger AS $$
+DECLARE
+    changed JSONB;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        changed := to_jsonb(OLD);
+    ELSE
+        changed := to_jsonb(NEW);
+    END IF;
+
+    IF TG_TABLE_NAME = 'game' THEN
+        PERFORM pg_notify('game_changed', changed->>'id');
+    ELSE
+        PERFORM pg_notify('game_changed', changed->>'game_id');
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER game_changed AFTER INSERT OR UPDATE OR DELETE ON game
+    FOR EACH ROW EXECUTE FUNCTION notify_game_changed();
+CREATE TRIGGER game_changed AFTER INSERT OR UPDATE OR DELETE ON bet
+    FOR EACH ROW EXECUTE FUNCTION notify_game_changed();
+CREATE TRIGGER game_changed AFTER INSERT OR UPDATE OR DELETE ON score
+    FOR EACH ROW EXECUTE FUNCTION notify_game_changed();
+CREATE TRIGGER game_changed AFTER INSERT OR UPDATE OR DELETE ON game_player
+    FOR EACH ROW EXECUTE FUNCTION notify_game_changed();
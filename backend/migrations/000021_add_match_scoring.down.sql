@@ -0,0 +1,2 @@
+-- Remove match_scoring table
+DROP TABLE IF EXISTS match_scoring;
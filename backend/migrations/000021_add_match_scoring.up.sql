@@ -0,0 +1,16 @@
+-- Add match_scoring table recording each scoring of a match applied to a game.
+-- A scoring is applied once: replaying it is a no-op, scoring a match again takes the next version
+CREATE TABLE IF NOT EXISTS match_scoring (
+    game_id UUID NOT NULL REFERENCES game(id) ON DELETE CASCADE,
+    match_id UUID NOT NULL REFERENCES match(id) ON DELETE CASCADE,
+    version INTEGER NOT NULL,
+    scored_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (game_id, match_id, version)
+);
+
+-- The matches scored before the scorings were recorded
+INSERT INTO match_scoring (game_id, match_id, version, scored_at)
+SELECT game_id, match_id, 1, COALESCE(MIN(created_at), CURRENT_TIMESTAMP)
+FROM score
+GROUP BY game_id, match_id
+ON CONFLICT DO NOTHING;
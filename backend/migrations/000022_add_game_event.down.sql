@@ -0,0 +1,3 @@
+-- Remove game_snapshot and game_event tables
+DROP TABLE IF EXISTS game_snapshot;
+DROP TABLE IF EXISTS game_event;
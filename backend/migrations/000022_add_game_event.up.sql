@@ -0,0 +1,20 @@
+-- Add game_event table holding the append-only history of the games, and game_snapshot table holding their state
+-- at some points of it. A game is rebuilt from its latest snapshot and the events recorded after it.
+-- The events only hold the ids of the players, so that their personal data stays in the player table
+CREATE TABLE IF NOT EXISTS game_event (
+    game_id UUID NOT NULL REFERENCES game(id) ON DELETE CASCADE,
+    sequence BIGINT NOT NULL,
+    type VARCHAR(32) NOT NULL,
+    payload JSONB NOT NULL DEFAULT '{}'::jsonb,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (game_id, sequence)
+);
+
+CREATE TABLE IF NOT EXISTS game_snapshot (
+    game_id UUID NOT NULL REFERENCES game(id) ON DELETE CASCADE,
+    -- The snapshot is the state of the game once the events up to this sequence are applied
+    sequence BIGINT NOT NULL,
+    state JSONB NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (game_id, sequence)
+);
@@ -0,0 +1,2 @@
+-- Remove bet_history table
+DROP TABLE IF EXISTS bet_history;
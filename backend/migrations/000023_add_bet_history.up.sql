@@ -0,0 +1,21 @@
+-- Add bet_history table recording every change of the bets, with the time it was saved and the client it came from.
+-- The bet table only keeps the latest prediction, the history proves when each one was made
+CREATE TABLE IF NOT EXISTS bet_history (
+    id BIGSERIAL PRIMARY KEY,
+    game_id UUID NOT NULL REFERENCES game(id) ON DELETE CASCADE,
+    match_local_id TEXT NOT NULL,
+    player_id UUID NOT NULL REFERENCES player(id) ON DELETE CASCADE,
+    predicted_home_goals INT NOT NULL,
+    predicted_away_goals INT NOT NULL,
+    placed_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    -- The date of the match when the bet was saved, it can move afterwards if the match is postponed
+    kickoff_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    device_name VARCHAR(100),
+    platform VARCHAR(20),
+    app_version VARCHAR(20),
+    ip_address VARCHAR(45),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_bet_history_game_match ON bet_history(game_id, match_local_id, id);
+CREATE INDEX IF NOT EXISTS idx_bet_history_player ON bet_history(player_id, id);
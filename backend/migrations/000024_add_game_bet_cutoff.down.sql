@@ -0,0 +1,4 @@
+-- Remove the bet cutoff of the games
+ALTER TABLE game
+    DROP COLUMN IF EXISTS bet_cutoff_matchday,
+    DROP COLUMN IF EXISTS bet_cutoff_minutes;
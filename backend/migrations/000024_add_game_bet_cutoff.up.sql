@@ -0,0 +1,5 @@
+-- Add the bet cutoff of the games: how long before kickoff the bets close, and whether a whole matchday
+-- closes with its first match. The existing games keep closing the bets at kickoff
+ALTER TABLE game
+    ADD COLUMN IF NOT EXISTS bet_cutoff_minutes INT NOT NULL DEFAULT 0 CHECK (bet_cutoff_minutes >= 0),
+    ADD COLUMN IF NOT EXISTS bet_cutoff_matchday BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,3 @@
+-- Remove the tiebreakers of the games
+ALTER TABLE game
+    DROP COLUMN IF EXISTS tiebreakers;
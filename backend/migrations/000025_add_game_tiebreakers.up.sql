@@ -0,0 +1,4 @@
+-- Add the tiebreakers of the games, as the comma separated names applied in order to the players with the same points.
+-- The existing games keep the default tiebreakers, which a NULL stands for
+ALTER TABLE game
+    ADD COLUMN IF NOT EXISTS tiebreakers TEXT;
@@ -0,0 +1,24 @@
+-- Notify every write of the bets with the id of the changed game only
+DROP TRIGGER IF EXISTS game_changed_update ON bet;
+DROP TRIGGER IF EXISTS game_changed ON bet;
+CREATE TRIGGER game_changed AFTER INSERT OR UPDATE OR DELETE ON bet
+    FOR EACH ROW EXECUTE FUNCTION notify_game_changed();
+
+CREATE OR REPLACE FUNCTION notify_game_changed() RETURNS trigger AS $$
+DECLARE
+    changed JSONB;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        changed := to_jsonb(OLD);
+    ELSE
+        changed := to_jsonb(NEW);
+    END IF;
+
+    IF TG_TABLE_NAME = 'game' THEN
+        PERFORM pg_notify('game_changed', changed->>'id');
+    ELSE
+        PERFORM pg_notify('game_changed', changed->>'game_id');
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
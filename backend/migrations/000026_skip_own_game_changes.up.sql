@@ -0,0 +1,38 @@
+-- Send the application_name of the connection with the id of the changed game, so that an instance skips
+-- its own changes, whose game it already keeps in its cache
+CREATE OR REPLACE FUNCTION notify_game_changed() RETURNS trigger AS $$
+DECLARE
+    changed JSONB;
+    changed_game_id TEXT;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        changed := to_jsonb(OLD);
+    ELSE
+        changed := to_jsonb(NEW);
+    END IF;
+
+    IF TG_TABLE_NAME = 'game' THEN
+        changed_game_id := changed->>'id';
+    ELSE
+        changed_game_id := changed->>'game_id';
+    END IF;
+    PERFORM pg_notify('game_changed', json_build_object(
+        'game_id', changed_game_id,
+        'sender', current_setting('application_name')
+    )::text);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+-- A bet saved again with the same scoreline, like when the app retries, doesn't change the game
+DROP TRIGGER IF EXISTS game_changed ON bet;
+CREATE TRIGGER game_changed AFTER INSERT OR DELETE ON bet
+    FOR EACH ROW EXECUTE FUNCTION notify_game_changed();
+CREATE TRIGGER game_changed_update AFTER UPDATE ON bet
+    FOR EACH ROW
+    WHEN (OLD.game_id IS DISTINCT FROM NEW.game_id
+        OR OLD.match_id IS DISTINCT FROM NEW.match_id
+        OR OLD.player_id IS DISTINCT FROM NEW.player_id
+        OR OLD.predicted_home_goals IS DISTINCT FROM NEW.predicted_home_goals
+        OR OLD.predicted_away_goals IS DISTINCT FROM NEW.predicted_away_goals)
+    EXECUTE FUNCTION notify_game_changed();
@@ -0,0 +1,2 @@
+-- Remove player_rating_change table
+DROP TABLE IF EXISTS player_rating_change;
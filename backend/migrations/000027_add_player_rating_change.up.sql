@@ -0,0 +1,10 @@
+-- Add player_rating_change table to store the change the last scoring of each match made to the rating of each player,
+-- so that a match scored again replaces the change instead of adding to it
+CREATE TABLE IF NOT EXISTS player_rating_change (
+    game_id UUID NOT NULL REFERENCES game(id) ON DELETE CASCADE,
+    match_local_id TEXT NOT NULL,
+    player_id UUID NOT NULL REFERENCES player(id) ON DELETE CASCADE,
+    version INTEGER NOT NULL,
+    delta DOUBLE PRECISION NOT NULL,
+    PRIMARY KEY (game_id, match_local_id, player_id)
+);
@@ -0,0 +1 @@
+DROP TRIGGER IF EXISTS game_changed ON game_event;
@@ -0,0 +1,4 @@
+-- The kickoffs and live goals of the matches are only saved in the history of the games, by the instance watching
+-- the matches. Notify them like the other changes of the games, so that the other instances drop them from their cache
+CREATE TRIGGER game_changed AFTER INSERT ON game_event
+    FOR EACH ROW EXECUTE FUNCTION notify_game_changed();
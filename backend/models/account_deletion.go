@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// DeletedPlayerName is the name a player gets once they delete their account. It can't be taken as a display name
+const DeletedPlayerName = "Deleted player"
+
+// AccountDeletion is an account deleted by its player, that can still be restored until PurgeAfter
+type AccountDeletion struct {
+	PlayerID string `json:"-" db:"player_id"`
+	// OriginalName and AvatarObjectKey are given back to the player if they restore their account
+	OriginalName    string    `json:"-" db:"original_name"`
+	AvatarObjectKey *string   `json:"-" db:"avatar_object_key"`
+	DeletedAt       time.Time `json:"deletedAt" db:"deleted_at"`
+	// PurgeAfter is the end of the grace period, the personal data of the player is purged after it
+	PurgeAfter time.Time `json:"purgeAfter" db:"purge_after"`
+}
@@ -0,0 +1,18 @@
+package models
+
+// BetConflict is a match of a game both merged accounts bet on. Only one of the bets is kept, with its score
+type BetConflict struct {
+	GameID  string `json:"gameId"`
+	MatchID string `json:"matchId"`
+	// KeptMergedBet is true when the bet of the merged account was kept, because it was updated last
+	KeptMergedBet bool `json:"keptMergedBet"`
+}
+
+// MergeSummary describes what an account merge moved into the kept account
+type MergeSummary struct {
+	Player    *PlayerData   `json:"player"`
+	MovedBets int           `json:"movedBets"`
+	Conflicts []BetConflict `json:"conflicts"`
+	// GameIDs are the games the merged account was part of
+	GameIDs []string `json:"gameIds"`
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"time"
+)
+
+// AchievementCode identifies the rule that awarded an achievement
+type AchievementCode string
+
+const (
+	// AchievementFirstPerfectScore is awarded the first time a player predicts the exact score of a match
+	AchievementFirstPerfectScore AchievementCode = "first_perfect_score"
+	// AchievementCorrectOutcomeStreak is awarded when a player predicts the right outcome of 5 matches in a row
+	AchievementCorrectOutcomeStreak AchievementCode = "correct_outcome_streak"
+	// AchievementUpsetCalled is awarded when a player predicts the right outcome of a match worth a 2.0 risk multiplier
+	AchievementUpsetCalled AchievementCode = "upset_called"
+	// AchievementFullMatchday is awarded when a player bets on every match of a matchday
+	AchievementFullMatchday AchievementCode = "full_matchday"
+)
+
+var achievementTitles = map[AchievementCode]string{
+	AchievementFirstPerfectScore:    "First perfect score",
+	AchievementCorrectOutcomeStreak: "5 correct outcomes in a row",
+	AchievementUpsetCalled:          "Called an upset",
+	AchievementFullMatchday:         "Never missed a bet in a matchday",
+}
+
+// Title returns a human readable name for the achievement
+func (c AchievementCode) Title() string {
+	if title, exists := achievementTitles[c]; exists {
+		return title
+	}
+	return string(c)
+}
+
+// Achievement represents a badge awarded to a player in a game. A player can only earn each badge once per game
+type Achievement struct {
+	ID       string          `json:"id" db:"id"`
+	PlayerID string          `json:"playerId" db:"player_id"`
+	GameID   string          `json:"gameId" db:"game_id"`
+	Code     AchievementCode `json:"code" db:"code"`
+	// MatchID is the match whose result awarded the achievement
+	MatchID   string    `json:"matchId" db:"match_local_id"`
+	AwardedAt time.Time `json:"awardedAt" db:"awarded_at"`
+}
+
+// NewAchievement creates a new Achievement instance
+func NewAchievement(playerID, gameID string, code AchievementCode, matchID string, awardedAt time.Time) *Achievement {
+	return &Achievement{
+		PlayerID:  playerID,
+		GameID:    gameID,
+		Code:      code,
+		MatchID:   matchID,
+		AwardedAt: awardedAt,
+	}
+}
@@ -0,0 +1,71 @@
+package models
+
+import "time"
+
+// ActivityType is the kind of event shown in the activity feed of a game
+type ActivityType string
+
+const (
+	ActivityPlayerJoined  ActivityType = "player_joined"
+	ActivityPlayerLeft    ActivityType = "player_left"
+	ActivityBetPlaced     ActivityType = "bet_placed"
+	ActivityMatchStarted  ActivityType = "match_started"
+	ActivityGoal          ActivityType = "goal"
+	ActivityMatchScored   ActivityType = "match_scored"
+	ActivityLeaderChanged ActivityType = "leader_changed"
+)
+
+// Activity represents an event of a game, as shown in its activity feed
+type Activity struct {
+	// ID increases with every activity, and is used as the pagination cursor of the feed
+	ID         int64           `json:"id" db:"id"`
+	GameID     string          `json:"gameId" db:"game_id"`
+	Type       ActivityType    `json:"type" db:"type"`
+	PlayerID   string          `json:"playerId,omitempty" db:"player_id"`
+	PlayerName string          `json:"playerName,omitempty" db:"player_name"`
+	MatchID    string          `json:"matchId,omitempty" db:"match_local_id"`
+	Details    ActivityDetails `json:"details" db:"details"`
+	CreatedAt  time.Time       `json:"createdAt" db:"created_at"`
+}
+
+// ActivityDetails holds the data specific to each type of activity.
+// A bet placed never carries the predicted scoreline, so the feed can't reveal bets before kickoff
+type ActivityDetails struct {
+	HomeTeam  string `json:"homeTeam,omitempty"`
+	AwayTeam  string `json:"awayTeam,omitempty"`
+	HomeGoals *int   `json:"homeGoals,omitempty"`
+	AwayGoals *int   `json:"awayGoals,omitempty"`
+	// Points earned by each player on a scored match, keyed by player id
+	Points map[string]int `json:"points,omitempty"`
+	// ScoringVersion is the version of the scoring of a scored match, above 1 when its result was corrected
+	ScoringVersion int `json:"scoringVersion,omitempty"`
+	// LeaderIDs are the players ranked first after a leader change
+	LeaderIDs []string `json:"leaderIds,omitempty"`
+}
+
+// NewPlayerActivity creates an activity about a player of a game
+func NewPlayerActivity(gameID string, activityType ActivityType, player Player) *Activity {
+	return &Activity{
+		GameID:     gameID,
+		Type:       activityType,
+		PlayerID:   player.GetID(),
+		PlayerName: player.GetName(),
+	}
+}
+
+// NewMatchActivity creates an activity about a match of a game, with the teams and the current score of the match
+func NewMatchActivity(gameID string, activityType ActivityType, match Match) *Activity {
+	homeGoals := match.GetHomeGoals()
+	awayGoals := match.GetAwayGoals()
+	return &Activity{
+		GameID:  gameID,
+		Type:    activityType,
+		MatchID: match.Id(),
+		Details: ActivityDetails{
+			HomeTeam:  match.GetHomeTeam(),
+			AwayTeam:  match.GetAwayTeam(),
+			HomeGoals: &homeGoals,
+			AwayGoals: &awayGoals,
+		},
+	}
+}
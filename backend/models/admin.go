@@ -0,0 +1,60 @@
+package models
+
+import "time"
+
+// The roles of a player
+const (
+	PlayerRolePlayer = "player"
+	// PlayerRoleAdmin allows the operator API, to inspect and fix the games without SQL
+	PlayerRoleAdmin = "admin"
+)
+
+// IsValidPlayerRole checks if a role is one a player can be given
+func IsValidPlayerRole(role string) bool {
+	return role == PlayerRolePlayer || role == PlayerRoleAdmin
+}
+
+// AdminAction is the kind of change made through the operator API
+type AdminAction string
+
+const (
+	AdminActionForceMatchResult AdminAction = "force_match_result"
+	AdminActionRescoreGame      AdminAction = "rescore_game"
+	AdminActionFinishGame       AdminAction = "finish_game"
+	AdminActionUnfinishGame     AdminAction = "unfinish_game"
+	AdminActionResetJoinCode    AdminAction = "reset_join_code"
+	AdminActionSetPlayerRole    AdminAction = "set_player_role"
+)
+
+// The kinds of object an admin action is done on
+const (
+	AdminTargetGame   = "game"
+	AdminTargetMatch  = "match"
+	AdminTargetPlayer = "player"
+)
+
+// AdminAuditEntry records an action done by an admin through the operator API
+type AdminAuditEntry struct {
+	// ID increases with every entry, and is used as the pagination cursor of the log
+	ID         int64          `json:"id" db:"id"`
+	AdminID    string         `json:"adminId" db:"admin_id"`
+	AdminName  string         `json:"adminName" db:"admin_name"`
+	Action     AdminAction    `json:"action" db:"action"`
+	TargetType string         `json:"targetType" db:"target_type"`
+	TargetID   string         `json:"targetId" db:"target_id"`
+	Details    map[string]any `json:"details,omitempty" db:"details"`
+	CreatedAt  time.Time      `json:"createdAt" db:"created_at"`
+}
+
+// AdminGameSummary is a game as listed in the operator API
+type AdminGameSummary struct {
+	ID              string `json:"id" db:"id"`
+	Name            string `json:"name" db:"game_name"`
+	CompetitionName string `json:"competitionName" db:"competition_name"`
+	SeasonYear      string `json:"seasonYear" db:"season_year"`
+	Status          string `json:"status" db:"status"`
+	PlayerCount     int    `json:"playerCount" db:"player_count"`
+	// Code is the join code of the game, empty once it expired
+	Code      string     `json:"code,omitempty" db:"code"`
+	CreatedAt *time.Time `json:"createdAt,omitempty" db:"created_at"`
+}
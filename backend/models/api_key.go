@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+// The scopes of an API key
+const (
+	// APIKeyScopeRead allows the requests reading data (GET, HEAD and OPTIONS)
+	APIKeyScopeRead = "read"
+	// APIKeyScopeWrite allows the requests changing data
+	APIKeyScopeWrite = "write"
+	// APIKeyScopeAdmin allows the operator endpoints
+	APIKeyScopeAdmin = "admin"
+)
+
+// APIKey identifies a client of the API, like the iOS app, the Android app or a partner integration.
+// Only the hash of the key is stored, the key itself is shown once when it's created
+type APIKey struct {
+	ID string `json:"id" db:"id"`
+	// Name is the client the key belongs to. A client has several keys while one is rotated
+	Name string `json:"name" db:"name"`
+	// Prefix is the beginning of the key, to tell the keys of a client apart
+	Prefix  string   `json:"prefix" db:"key_prefix"`
+	KeyHash string   `json:"-" db:"key_hash"`
+	Scopes  []string `json:"scopes" db:"scopes"`
+	// RateLimitPerMinute is the number of requests the key can make per minute, 0 for no limit
+	RateLimitPerMinute int        `json:"rateLimitPerMinute" db:"rate_limit_per_minute"`
+	CreatedAt          time.Time  `json:"createdAt" db:"created_at"`
+	ExpiresAt          *time.Time `json:"expiresAt,omitempty" db:"expires_at"`
+	RevokedAt          *time.Time `json:"revokedAt,omitempty" db:"revoked_at"`
+}
+
+// IsActive checks if the key is accepted at the given time
+func (k *APIKey) IsActive(now time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || now.Before(*k.ExpiresAt))
+}
+
+// HasScope checks if the key was given the scope
+func (k *APIKey) HasScope(scope string) bool {
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,29 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// MaxBetCutoffMinutes is the longest a game can close the bets before kickoff
+const MaxBetCutoffMinutes = 24 * 60
+
+// BetCutoff is how long before kickoff a game stops taking bets.
+// With Matchday set, every match of a matchday closes with the first match of that matchday
+type BetCutoff struct {
+	Minutes  int  `json:"minutes"`
+	Matchday bool `json:"matchday"`
+}
+
+// Validate checks that the cutoff is between zero and MaxBetCutoffMinutes
+func (c BetCutoff) Validate() error {
+	if c.Minutes < 0 || c.Minutes > MaxBetCutoffMinutes {
+		return fmt.Errorf("bet cutoff must be between 0 and %d minutes", MaxBetCutoffMinutes)
+	}
+	return nil
+}
+
+// ClosesAt returns when the bets close for a match kicking off at the given time
+func (c BetCutoff) ClosesAt(kickoff time.Time) time.Time {
+	return kickoff.Add(-time.Duration(c.Minutes) * time.Minute)
+}
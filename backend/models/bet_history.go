@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// BetChange is a bet as a player placed or changed it, as recorded in the history of the bets.
+// The history is append-only, so that it proves when each prediction was made
+type BetChange struct {
+	ID                 int64  `json:"id" db:"id"`
+	GameID             string `json:"gameId" db:"game_id"`
+	MatchID            string `json:"matchId" db:"match_local_id"`
+	PlayerID           string `json:"playerId" db:"player_id"`
+	PredictedHomeGoals int    `json:"predictedHomeGoals" db:"predicted_home_goals"`
+	PredictedAwayGoals int    `json:"predictedAwayGoals" db:"predicted_away_goals"`
+	// PlacedAt is the time of the server when the bet was saved
+	PlacedAt time.Time `json:"placedAt" db:"placed_at"`
+	// KickoffAt is the date of the match when the bet was saved, it can move afterwards if the match is postponed
+	KickoffAt time.Time `json:"kickoffAt" db:"kickoff_at"`
+	// Device is the client the bet was sent from
+	Device DeviceInfo `json:"device"`
+}
+
+// NewBetChange creates the record of a bet saved at placedAt
+func NewBetChange(gameID string, playerID string, bet *Bet, placedAt time.Time, device DeviceInfo) *BetChange {
+	return &BetChange{
+		GameID:             gameID,
+		MatchID:            bet.Match.Id(),
+		PlayerID:           playerID,
+		PredictedHomeGoals: bet.PredictedHomeGoals,
+		PredictedAwayGoals: bet.PredictedAwayGoals,
+		PlacedAt:           placedAt,
+		KickoffAt:          bet.Match.GetDate(),
+		Device:             device,
+	}
+}
+
+// PlacedBeforeKickoff tells whether the bet was saved before the match started, as it was scheduled then
+func (c *BetChange) PlacedBeforeKickoff() bool {
+	return c.PlacedAt.Before(c.KickoffAt)
+}
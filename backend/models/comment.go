@@ -0,0 +1,45 @@
+package models
+
+import (
+	"regexp"
+	"time"
+)
+
+// scorePattern matches a score prediction like "2-1" or "0 : 0"
+var scorePattern = regexp.MustCompile(`\b\d{1,2}\s*[-:]\s*\d{1,2}\b`)
+
+// MatchComment represents a comment posted by a player on a match of a game
+type MatchComment struct {
+	ID         string `json:"id" db:"id"`
+	GameID     string `json:"gameId" db:"game_id"`
+	MatchID    string `json:"matchId" db:"match_local_id"`
+	PlayerID   string `json:"playerId" db:"player_id"`
+	PlayerName string `json:"playerName" db:"name"`
+	Content    string `json:"content" db:"content"`
+	// MentionsBet is true when the comment could reveal a bet, so it's hidden from the other players until kickoff
+	MentionsBet bool      `json:"mentionsBet" db:"mentions_bet"`
+	CreatedAt   time.Time `json:"createdAt" db:"created_at"`
+	// Hidden is true when the content has been removed because it mentions a bet and the match hasn't started
+	Hidden bool `json:"hidden" db:"-"`
+}
+
+// CommentMentionsBet checks if the content of a comment looks like a score prediction
+func CommentMentionsBet(content string) bool {
+	return scorePattern.MatchString(content)
+}
+
+// MatchReaction represents an emoji reaction of a player on a match of a game
+type MatchReaction struct {
+	GameID    string    `json:"gameId" db:"game_id"`
+	MatchID   string    `json:"matchId" db:"match_local_id"`
+	PlayerID  string    `json:"playerId" db:"player_id"`
+	Emoji     string    `json:"emoji" db:"emoji"`
+	CreatedAt time.Time `json:"createdAt" db:"created_at"`
+}
+
+// ReactionSummary represents how many players reacted to a match with an emoji
+type ReactionSummary struct {
+	Emoji       string `json:"emoji"`
+	Count       int    `json:"count"`
+	ReactedByMe bool   `json:"reactedByMe"`
+}
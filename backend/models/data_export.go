@@ -0,0 +1,68 @@
+package models
+
+import "time"
+
+// DataExportStatus is the state of the archive of a data export
+type DataExportStatus string
+
+const (
+	DataExportPending DataExportStatus = "pending"
+	DataExportReady   DataExportStatus = "ready"
+	DataExportFailed  DataExportStatus = "failed"
+)
+
+// DataExport is a request of a player for a copy of their personal data.
+// The archive is built in the background and deleted once it expires
+type DataExport struct {
+	ID       string           `json:"id" db:"id"`
+	PlayerID string           `json:"-" db:"player_id"`
+	Status   DataExportStatus `json:"status" db:"status"`
+	// ObjectKey is the path of the archive in the blob storage, once it's ready
+	ObjectKey   string     `json:"-" db:"object_key"`
+	CreatedAt   time.Time  `json:"createdAt" db:"created_at"`
+	CompletedAt *time.Time `json:"completedAt,omitempty" db:"completed_at"`
+	// ExpiresAt is when the archive is deleted, once it's ready
+	ExpiresAt *time.Time `json:"expiresAt,omitempty" db:"expires_at"`
+	// DownloadURL is a signed URL of the archive, only set when it's ready
+	DownloadURL string `json:"downloadUrl,omitempty" db:"-"`
+}
+
+// IsExpired checks if the archive of the export has been or is about to be deleted
+func (e *DataExport) IsExpired(now time.Time) bool {
+	return e.ExpiresAt != nil && !now.Before(*e.ExpiresAt)
+}
+
+// PlayerBetRecord is a bet of a player with its score, as it appears in their data export
+type PlayerBetRecord struct {
+	GameID             string     `json:"gameId"`
+	MatchID            string     `json:"matchId"`
+	HomeTeam           string     `json:"homeTeam"`
+	AwayTeam           string     `json:"awayTeam"`
+	PredictedHomeGoals int        `json:"predictedHomeGoals"`
+	PredictedAwayGoals int        `json:"predictedAwayGoals"`
+	Points             *int       `json:"points,omitempty"`
+	UpdatedAt          *time.Time `json:"updatedAt,omitempty"`
+}
+
+// PlayerGameRecord is a game a player is part of, as it appears in their data export
+type PlayerGameRecord struct {
+	GameID          string `json:"gameId"`
+	Name            string `json:"name"`
+	CompetitionName string `json:"competitionName"`
+	SeasonYear      string `json:"seasonYear"`
+}
+
+// PlayerDataArchive is the personal data of a player, written as JSON in the archive of their data export
+type PlayerDataArchive struct {
+	ExportedAt time.Time          `json:"exportedAt"`
+	Profile    *PlayerData        `json:"profile"`
+	Sessions   []*Session         `json:"sessions"`
+	Games      []PlayerGameRecord `json:"games"`
+	Bets       []*PlayerBetRecord `json:"bets"`
+	// BetHistory is every change of the bets, with the device each one was sent from
+	BetHistory []*BetChange     `json:"betHistory"`
+	Comments   []*MatchComment  `json:"comments"`
+	Reactions  []*MatchReaction `json:"reactions"`
+	// Avatar is the name of the avatar file in the archive, if the player has one
+	Avatar string `json:"avatar,omitempty"`
+}
@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// EmailSignInProvider is the provider of the players who sign in with a code or a link sent by email
+const EmailSignInProvider = "email"
+
+// EmailSignInCode is a one-time code emailed to sign in, along with the magic link carrying the same sign-in.
+// Only the hashes of the code and of the link token are stored
+type EmailSignInCode struct {
+	ID            string `json:"id" db:"id"`
+	Email         string `json:"email" db:"email"`
+	CodeHash      string `json:"-" db:"code_hash"`
+	LinkTokenHash string `json:"-" db:"link_token_hash"`
+	// Attempts counts the wrong codes entered while this code was the latest one of the email
+	Attempts  int        `json:"attempts" db:"attempts"`
+	CreatedAt time.Time  `json:"createdAt" db:"created_at"`
+	ExpiresAt time.Time  `json:"expiresAt" db:"expires_at"`
+	UsedAt    *time.Time `json:"usedAt,omitempty" db:"used_at"`
+}
+
+// IsUsable checks if the code can still be used to sign in at the given time
+func (c *EmailSignInCode) IsUsable(now time.Time, maxAttempts int) bool {
+	return c.UsedAt == nil && now.Before(c.ExpiresAt) && c.Attempts < maxAttempts
+}
+
+// EmailSignInVerifyRequest verifies an emailed sign-in, either with the email and the code or with the link token
+type EmailSignInVerifyRequest struct {
+	Email string `json:"email"`
+	Code  string `json:"code"`
+	Token string `json:"token"`
+	// Name is the display name, required when the email doesn't belong to a player yet
+	Name string `json:"name"`
+}
@@ -1,6 +1,9 @@
 package models
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 // InvalidDisplayNameError is returned when a display name is invalid
 // (e.g., empty, too short, too long)
@@ -31,6 +34,16 @@ func (e *TokenExpiredError) Error() string {
 	return fmt.Sprintf("token expired: %s", e.Reason)
 }
 
+// RefreshTokenReusedError is returned when a refresh token that was already rotated or revoked is used again.
+// It means the token was probably stolen, so every token of its family is revoked
+type RefreshTokenReusedError struct {
+	FamilyID string
+}
+
+func (e *RefreshTokenReusedError) Error() string {
+	return fmt.Sprintf("refresh token reused, token family %s revoked", e.FamilyID)
+}
+
 // UnsupportedProviderError is returned when an unsupported auth provider is used
 type UnsupportedProviderError struct {
 	Provider string
@@ -40,6 +53,56 @@ func (e *UnsupportedProviderError) Error() string {
 	return fmt.Sprintf("unsupported provider: %s", e.Provider)
 }
 
+// AccountAlreadyLinkedError is returned when linking an OAuth identity to a player who isn't a guest anymore
+type AccountAlreadyLinkedError struct {
+	Provider string
+}
+
+func (e *AccountAlreadyLinkedError) Error() string {
+	return fmt.Sprintf("account already linked to %s", e.Provider)
+}
+
+// IdentityInUseError is returned when linking an OAuth identity that already belongs to another player
+type IdentityInUseError struct {
+	Provider string
+}
+
+func (e *IdentityInUseError) Error() string {
+	return fmt.Sprintf("this %s account is already used by another player", e.Provider)
+}
+
+// InvalidMergeError is returned when two accounts can't be merged
+type InvalidMergeError struct {
+	Reason string
+}
+
+func (e *InvalidMergeError) Error() string {
+	return fmt.Sprintf("cannot merge accounts: %s", e.Reason)
+}
+
+// InvalidGuestSecretError is returned when a guest signs in again without their secret, or with a wrong one
+type InvalidGuestSecretError struct{}
+
+func (e *InvalidGuestSecretError) Error() string {
+	return "invalid guest secret"
+}
+
+// InvalidSignInCodeError is returned when an emailed sign-in code or link is wrong, expired or already used
+type InvalidSignInCodeError struct{}
+
+func (e *InvalidSignInCodeError) Error() string {
+	return "invalid or expired sign-in code"
+}
+
+// TooManyAttemptsError is returned when an account had too many failed sign-in attempts recently
+type TooManyAttemptsError struct {
+	RetryAfter time.Duration
+}
+
+func (e *TooManyAttemptsError) Error() string {
+	return fmt.Sprintf("too many attempts, retry in %s", e.RetryAfter.Round(time.Second))
+}
+
 // GeneralAuthError is a fallback for other auth errors
 type GeneralAuthError struct {
 	Reason string
@@ -20,7 +20,12 @@ type Game interface {
 	GetCompetitionName() string
 	GetGameStatus() GameStatus
 	GetName() string
+	// CheckPlayerBetValidity checks that the player can bet on the match at datetime, before the bets close
 	CheckPlayerBetValidity(player Player, bet *Bet, datetime time.Time) error
+	// GetBetCutoff returns how long before kickoff the game stops taking bets
+	GetBetCutoff() BetCutoff
+	// BettingClosesAt returns when the game stops taking bets on the match, following its bet cutoff
+	BettingClosesAt(match Match) time.Time
 	AddPlayerBet(player Player, bet *Bet) error
 	AddPlayer(player Player) error
 	RemovePlayer(player Player) error
@@ -30,9 +35,22 @@ type Game interface {
 	GetPlayersPoints() map[string]int
 	GetPlayers() []Player
 	IsFinished() bool
+	// GetTiebreakers returns the names of the criteria separating the players with the same points, in the order they're applied
+	GetTiebreakers() []string
+	// GetWinner returns the players ranked first once every tiebreaker has been applied
 	GetWinner() []Player
-	// GetIncomingMatchesForTesting returns all incoming matches with all bets (for testing only)
-	GetIncomingMatchesForTesting() map[string]*MatchResult
+	// GetStandings ranks the players on their points, breaking ties with the game's tiebreakers
+	GetStandings() []Standing
+	// RankPlayers ranks the players on the given points, breaking ties with the game's tiebreakers
+	RankPlayers(points map[string]int) []Standing
+	// GetIncomingMatchesWithAllBets returns all incoming matches with the bets of every player, for operators and tests
+	GetIncomingMatchesWithAllBets() map[string]*MatchResult
+	// GetProvisionalResults scores every in-progress match against its live score, without applying anything
+	GetProvisionalResults() map[string]*MatchResult
 	GetMatchById(matchId string) (Match, error)
 	Finish()
+	// Reopen puts a finished game back in progress
+	Reopen()
+	// RescoreMatch replaces the result of a past match and scores it again, returning the new points of the players
+	RescoreMatch(match Match) (map[string]int, error)
 }
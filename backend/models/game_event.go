@@ -0,0 +1,143 @@
+package models
+
+import "time"
+
+// GameEventType is the kind of change recorded in the history of a game
+type GameEventType string
+
+const (
+	GameEventPlayerJoined GameEventType = "player_joined"
+	GameEventPlayerLeft   GameEventType = "player_left"
+	GameEventBetPlaced    GameEventType = "bet_placed"
+	GameEventMatchUpdated GameEventType = "match_updated"
+	// GameEventMatchScored is recorded each time a match is scored, the points replace the ones the players had on the match
+	GameEventMatchScored  GameEventType = "match_scored"
+	GameEventGameFinished GameEventType = "game_finished"
+	GameEventGameReopened GameEventType = "game_reopened"
+	// GameEventHistoryRebased is recorded when the game was changed outside of its events, like by an account merge.
+	// The game can't be replayed past it, it's rebuilt from the snapshot taken at the same sequence
+	GameEventHistoryRebased GameEventType = "history_rebased"
+)
+
+// GameEvent is a change of a game, as recorded in its append-only history.
+// The events only hold the ids of the players, their names are looked up when the game is rebuilt
+type GameEvent struct {
+	GameID string `json:"gameId" db:"game_id"`
+	// Sequence increases by one with each event of the game, starting at 1
+	Sequence  int64            `json:"sequence" db:"sequence"`
+	Type      GameEventType    `json:"type" db:"type"`
+	Payload   GameEventPayload `json:"payload" db:"payload"`
+	CreatedAt time.Time        `json:"createdAt" db:"created_at"`
+}
+
+// GameEventPayload holds the data specific to each type of game event
+type GameEventPayload struct {
+	PlayerID string `json:"playerId,omitempty"`
+	// Match is the state of the match when it was updated or scored, or the match of a bet
+	Match              *SeasonMatch `json:"match,omitempty"`
+	PredictedHomeGoals *int         `json:"predictedHomeGoals,omitempty"`
+	PredictedAwayGoals *int         `json:"predictedAwayGoals,omitempty"`
+	// Points earned by each player on a scored match, keyed by player id
+	Points map[string]int `json:"points,omitempty"`
+}
+
+// NewPlayerGameEvent creates an event about a player joining or leaving a game
+func NewPlayerGameEvent(eventType GameEventType, playerID string) *GameEvent {
+	return &GameEvent{
+		Type:    eventType,
+		Payload: GameEventPayload{PlayerID: playerID},
+	}
+}
+
+// NewBetPlacedEvent creates an event about a player placing or changing a bet
+func NewBetPlacedEvent(playerID string, bet *Bet) *GameEvent {
+	homeGoals := bet.PredictedHomeGoals
+	awayGoals := bet.PredictedAwayGoals
+	return &GameEvent{
+		Type: GameEventBetPlaced,
+		Payload: GameEventPayload{
+			PlayerID:           playerID,
+			Match:              AsSeasonMatch(bet.Match),
+			PredictedHomeGoals: &homeGoals,
+			PredictedAwayGoals: &awayGoals,
+		},
+	}
+}
+
+// NewMatchGameEvent creates an event about a match of a game. The points are only set when the match is scored
+func NewMatchGameEvent(eventType GameEventType, match Match, points map[string]int) *GameEvent {
+	return &GameEvent{
+		Type: eventType,
+		Payload: GameEventPayload{
+			Match:  AsSeasonMatch(match),
+			Points: points,
+		},
+	}
+}
+
+// NewGameStatusEvent creates an event about a game finishing or being put back in progress
+func NewGameStatusEvent(eventType GameEventType) *GameEvent {
+	return &GameEvent{Type: eventType}
+}
+
+// GameState is the whole state of a game at some point of its history, as saved in its snapshots
+type GameState struct {
+	SeasonYear      string     `json:"seasonYear"`
+	CompetitionName string     `json:"competitionName"`
+	Name            string     `json:"name"`
+	Status          GameStatus `json:"status"`
+	BetCutoff       BetCutoff  `json:"betCutoff"`
+	// Tiebreakers are the names of the tiebreakers of the game, the snapshots taken before they were kept have none
+	Tiebreakers     []string       `json:"tiebreakers"`
+	PlayerIDs       []string       `json:"playerIds"`
+	IncomingMatches []*SeasonMatch `json:"incomingMatches"`
+	PastMatches     []*SeasonMatch `json:"pastMatches"`
+	// Bets are keyed by match id then player id
+	Bets map[string]map[string]GameStateBet `json:"bets"`
+	// Points are keyed by match id then player id
+	Points map[string]map[string]int `json:"points"`
+}
+
+// GameStateBet is a bet as saved in a snapshot, its match is the one of the game with the same id
+type GameStateBet struct {
+	PredictedHomeGoals int `json:"predictedHomeGoals"`
+	PredictedAwayGoals int `json:"predictedAwayGoals"`
+}
+
+// GameSnapshot is the state of a game once the events up to Sequence are applied.
+// A game is rebuilt by replaying the events after its latest snapshot
+type GameSnapshot struct {
+	GameID    string     `json:"gameId" db:"game_id"`
+	Sequence  int64      `json:"sequence" db:"sequence"`
+	State     *GameState `json:"state" db:"state"`
+	CreatedAt time.Time  `json:"createdAt" db:"created_at"`
+}
+
+// EventSourcedGame is implemented by the games recording the events of their changes, so that their history can be saved and replayed
+type EventSourcedGame interface {
+	// TakeEvents returns the events of the changes made since the last call, oldest first, and forgets them
+	TakeEvents() []*GameEvent
+	// State returns the whole state of the game, as saved in its snapshots
+	State() *GameState
+}
+
+// AsSeasonMatch returns a copy of the match, so that its state at the time of an event can't change afterwards
+func AsSeasonMatch(match Match) *SeasonMatch {
+	if seasonMatch, ok := match.(*SeasonMatch); ok {
+		copied := *seasonMatch
+		return &copied
+	}
+	return &SeasonMatch{
+		HomeTeam:        match.GetHomeTeam(),
+		AwayTeam:        match.GetAwayTeam(),
+		HomeGoals:       match.GetHomeGoals(),
+		AwayGoals:       match.GetAwayGoals(),
+		HomeTeamOdds:    match.GetHomeTeamOdds(),
+		AwayTeamOdds:    match.GetAwayTeamOdds(),
+		DrawOdds:        match.GetDrawOdds(),
+		Status:          match.GetStatus(),
+		SeasonCode:      match.GetSeasonCode(),
+		CompetitionCode: match.GetCompetitionCode(),
+		Date:            match.GetDate(),
+	}
+}
@@ -0,0 +1,45 @@
+package models
+
+// ProvisionalLeaderboard represents the standings of a game as they would be if every
+// in-progress match ended right now with its live score. Nothing in it is persisted.
+type ProvisionalLeaderboard struct {
+	// Provisional is always true, so that clients can't mistake it for the real standings
+	Provisional bool
+	// LiveMatches holds the provisional result of each in-progress match, keyed by match id
+	LiveMatches map[string]*MatchResult
+	// ConfirmedPoints are the points already earned on scored matches, keyed by player id
+	ConfirmedPoints map[string]int
+	// ProvisionalPoints are the confirmed points plus the points of the live matches, keyed by player id
+	ProvisionalPoints map[string]int
+	// Standings ranks the players on their provisional points, using the game's tiebreakers
+	Standings []Standing
+}
+
+// NewProvisionalLeaderboard builds a provisional leaderboard from the confirmed points and the live results
+func NewProvisionalLeaderboard(confirmedPoints map[string]int, liveMatches map[string]*MatchResult) *ProvisionalLeaderboard {
+	provisionalPoints := make(map[string]int)
+	for playerID, points := range confirmedPoints {
+		provisionalPoints[playerID] = points
+	}
+	for _, result := range liveMatches {
+		for playerID, points := range result.Scores {
+			provisionalPoints[playerID] += points
+		}
+	}
+	return &ProvisionalLeaderboard{
+		Provisional:       true,
+		LiveMatches:       liveMatches,
+		ConfirmedPoints:   confirmedPoints,
+		ProvisionalPoints: provisionalPoints,
+	}
+}
+
+// Standing represents the position of a player in a game, with the statistics used to break ties
+type Standing struct {
+	Player Player
+	// Rank is 1-based. Players still tied after every tiebreaker share the same rank
+	Rank        int
+	Points      int
+	ExactScores int
+	MissedBets  int
+}
@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 type ScoreBreakdown struct {
 	BaseScore             int     `json:"baseScore"`
 	RiskMultiplier        float64 `json:"riskMultiplier"`
@@ -7,11 +9,13 @@ type ScoreBreakdown struct {
 }
 
 type MatchResult struct {
-	Match            Match
-	Bets             map[string]*Bet
-	Scores           map[string]int
-	ScoreBreakdowns  map[string]ScoreBreakdown
-	PlayerBetStatus  map[string]bool // playerID → hasBet, for non-requesting players
+	Match           Match
+	Bets            map[string]*Bet
+	Scores          map[string]int
+	ScoreBreakdowns map[string]ScoreBreakdown
+	PlayerBetStatus map[string]bool // playerID → hasBet, for non-requesting players
+	// BettingClosesAt is when the bets close on an incoming match, zero for the other matches
+	BettingClosesAt time.Time
 }
 
 func NewUnscoredMatch(match Match) *MatchResult {
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+)
+
+// InitialScoringVersion is the version of the first scoring of a match in a game, each new scoring of the match takes the next one
+const InitialScoringVersion = 1
+
+// MatchScoring is a scoring of a match applied to the points of a game. A scoring is applied once,
+// so that replaying the result of a match doesn't score it again
+type MatchScoring struct {
+	GameID string `json:"gameId" db:"game_id"`
+	// MatchID is the local id of the match
+	MatchID  string    `json:"matchId" db:"match_local_id"`
+	Version  int       `json:"version" db:"version"`
+	ScoredAt time.Time `json:"scoredAt" db:"scored_at"`
+}
+
+// NewMatchScoring creates a new MatchScoring instance
+func NewMatchScoring(gameID, matchID string, version int, scoredAt time.Time) *MatchScoring {
+	return &MatchScoring{
+		GameID:   gameID,
+		MatchID:  matchID,
+		Version:  version,
+		ScoredAt: scoredAt,
+	}
+}
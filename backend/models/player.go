@@ -38,6 +38,8 @@ type PlayerData struct {
 	AvatarObjectKey          *string    `json:"avatar_object_key,omitempty" db:"avatar_object_key"`
 	AvatarSignedURL          *string    `json:"avatar_signed_url,omitempty" db:"avatar_signed_url"`
 	AvatarSignedURLExpiresAt *time.Time `json:"avatar_signed_url_expires_at,omitempty" db:"avatar_signed_url_expires_at"`
+	// Role is PlayerRolePlayer or PlayerRoleAdmin. It's empty when the player wasn't loaded from the database
+	Role string `json:"role,omitempty" db:"role"`
 }
 
 // Implement Player interface for SimplePlayer
@@ -54,6 +56,16 @@ func (p *PlayerData) GetID() string {
 	return p.ID
 }
 
+// IsGuest checks if the player signed in as a guest and never linked an OAuth identity
+func (p *PlayerData) IsGuest() bool {
+	return p.Provider == nil && p.Email == nil
+}
+
+// IsAdmin checks if the player can use the operator API
+func (p *PlayerData) IsAdmin() bool {
+	return p.Role == PlayerRoleAdmin
+}
+
 func (p *PlayerData) GetName() string {
 	return p.Name
 }
@@ -67,6 +79,27 @@ type AuthToken struct {
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
 }
 
+// RefreshToken represents a long-lived token used to get new access tokens.
+// Only the hash of the token is stored. Every refresh rotates the token: the used one is revoked
+// and a new one is issued in the same family, so that the reuse of a revoked token can be detected
+type RefreshToken struct {
+	ID        string     `json:"id" db:"id"`
+	PlayerID  string     `json:"player_id" db:"player_id"`
+	FamilyID  string     `json:"family_id" db:"family_id"`
+	TokenHash string     `json:"-" db:"token_hash"`
+	Device    DeviceInfo `json:"device" db:"-"`
+	// SessionStartedAt is when the player signed in, it's kept when the token is rotated
+	SessionStartedAt time.Time  `json:"session_started_at" db:"session_started_at"`
+	ExpiresAt        time.Time  `json:"expires_at" db:"expires_at"`
+	CreatedAt        time.Time  `json:"created_at" db:"created_at"`
+	RevokedAt        *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+}
+
+// IsRevoked checks if the token has already been used or revoked
+func (t *RefreshToken) IsRevoked() bool {
+	return t.RevokedAt != nil
+}
+
 // AuthRequest represents the authentication request from the frontend
 type AuthRequest struct {
 	Provider string `json:"provider"` // "google" or "apple"
@@ -78,7 +111,14 @@ type AuthRequest struct {
 // AuthResponse represents the authentication response
 type AuthResponse struct {
 	Player PlayerData `json:"player"`
-	Token  string     `json:"token"`
+	// Token is the short-lived signed access token
+	Token string `json:"token"`
+	// ExpiresAt is the expiry of the access token
+	ExpiresAt time.Time `json:"expiresAt"`
+	// RefreshToken is used to get a new access token once it expired
+	RefreshToken string `json:"refreshToken"`
+	// GuestSecret is the secret a guest needs to sign in again. It's only returned when it's created
+	GuestSecret string `json:"guestSecret,omitempty"`
 }
 
 // ToSimplePlayer converts a PlayerData to a SimplePlayer
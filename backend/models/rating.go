@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+)
+
+// InitialRating is the rating of a player who has never been rated
+const InitialRating = 1500.0
+
+// PlayerRating represents the cross-game skill rating of a player
+type PlayerRating struct {
+	PlayerID     string    `json:"playerId" db:"player_id"`
+	PlayerName   string    `json:"playerName" db:"name"`
+	Rating       float64   `json:"rating" db:"rating"`
+	MatchesRated int       `json:"matchesRated" db:"matches_rated"`
+	UpdatedAt    time.Time `json:"updatedAt" db:"updated_at"`
+}
+
+// NewPlayerRating creates the rating of a player who has never been rated
+func NewPlayerRating(playerID, playerName string) *PlayerRating {
+	return &PlayerRating{
+		PlayerID:   playerID,
+		PlayerName: playerName,
+		Rating:     InitialRating,
+	}
+}
+
+// RatingChange is the change a scoring of a match made to the rating of a player in a game.
+// A scoring of the match with a later version replaces it, once the change was reverted
+type RatingChange struct {
+	GameID   string `json:"gameId" db:"game_id"`
+	MatchID  string `json:"matchId" db:"match_local_id"`
+	PlayerID string `json:"playerId" db:"player_id"`
+	// Version is the version of the scoring of the match which made the change
+	Version int     `json:"version" db:"version"`
+	Delta   float64 `json:"delta" db:"delta"`
+}
@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// MaxDeviceNameLength is the maximum length of a device name, longer names are truncated
+const MaxDeviceNameLength = 100
+
+// DeviceInfo describes the device a player signed in from
+type DeviceInfo struct {
+	Name       string `json:"deviceName,omitempty" db:"device_name"`
+	Platform   string `json:"platform,omitempty" db:"platform"`
+	AppVersion string `json:"appVersion,omitempty" db:"app_version"`
+	IPAddress  string `json:"ipAddress,omitempty" db:"ip_address"`
+}
+
+// Session is a sign-in of a player on a device, it lasts as long as its refresh tokens are rotated
+type Session struct {
+	ID string `json:"id"`
+	DeviceInfo
+	CreatedAt time.Time `json:"createdAt"`
+	// LastUsedAt is the last time the session was refreshed, so it's at most one access token lifetime behind
+	LastUsedAt time.Time `json:"lastUsedAt"`
+	Current    bool      `json:"current"`
+}
+
+// NewSessionFromRefreshToken creates the session of the live refresh token of a family
+func NewSessionFromRefreshToken(token *RefreshToken, currentSessionID string) *Session {
+	return &Session{
+		ID:         token.FamilyID,
+		DeviceInfo: token.Device,
+		CreatedAt:  token.SessionStartedAt,
+		LastUsedAt: token.CreatedAt,
+		Current:    token.FamilyID == currentSessionID,
+	}
+}
@@ -0,0 +1,45 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// InMemoryStore implements Store in memory, so each instance enforces the limits on its own
+type InMemoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]*Bucket // policy name and key -> bucket
+}
+
+// NewInMemoryStore creates a new in-memory store
+func NewInMemoryStore() *InMemoryStore {
+	return &InMemoryStore{
+		buckets: make(map[string]*Bucket),
+	}
+}
+
+func (s *InMemoryStore) Take(ctx context.Context, key string, policy Policy, now time.Time) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	bucketKey := policy.Name + ":" + key
+	bucket, exists := s.buckets[bucketKey]
+	if !exists {
+		bucket = NewBucket(policy, now)
+		s.buckets[bucketKey] = bucket
+	}
+	return bucket.Take(policy, now), nil
+}
+
+func (s *InMemoryStore) Prune(ctx context.Context, unusedSince time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for bucketKey, bucket := range s.buckets {
+		if bucket.RefilledAt.Before(unusedSince) {
+			delete(s.buckets, bucketKey)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInMemoryStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewInMemoryStore()
+	join := Policy{Name: "join", Limit: 1, Period: time.Minute}
+	bets := Policy{Name: "bets", Limit: 1, Period: time.Minute}
+
+	result, err := store.Take(ctx, "player:1", join, frozenTime)
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+	result, err = store.Take(ctx, "player:1", join, frozenTime)
+	require.NoError(t, err)
+	assert.False(t, result.Allowed)
+
+	// Each key and each policy has its own bucket
+	result, err = store.Take(ctx, "player:2", join, frozenTime)
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+	result, err = store.Take(ctx, "player:1", bets, frozenTime)
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+
+	require.NoError(t, store.Prune(ctx, frozenTime.Add(time.Second)))
+	assert.Empty(t, store.buckets)
+}
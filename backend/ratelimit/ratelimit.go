@@ -0,0 +1,73 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// Policy allows Limit requests per Period, in bursts of up to Limit requests
+type Policy struct {
+	// Name tells the buckets of the policies apart, so that a client has one bucket per policy
+	Name   string
+	Limit  int
+	Period time.Duration
+}
+
+// Result is the outcome of a request against a policy
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// ResetAfter is how long until the bucket is full again
+	ResetAfter time.Duration
+	// RetryAfter is how long until the next request is allowed, when this one wasn't
+	RetryAfter time.Duration
+}
+
+// Store keeps the buckets of the clients. Implementations can be in memory for a single instance,
+// or shared between the instances
+type Store interface {
+	// Take takes a token from the bucket of the key for the policy, if it has one left
+	Take(ctx context.Context, key string, policy Policy, now time.Time) (Result, error)
+	// Prune removes the buckets unused since the given time. Buckets unused for longer than the period
+	// of their policy are full, so removing them doesn't change anything
+	Prune(ctx context.Context, unusedSince time.Time) error
+}
+
+// Bucket is a token bucket, holding the requests a client can still make
+type Bucket struct {
+	Tokens     float64
+	RefilledAt time.Time
+}
+
+// NewBucket creates a full bucket for the policy
+func NewBucket(policy Policy, now time.Time) *Bucket {
+	return &Bucket{Tokens: float64(policy.Limit), RefilledAt: now}
+}
+
+// Take refills the bucket for the time elapsed since it was last used, and takes a token if there is one
+func (b *Bucket) Take(policy Policy, now time.Time) Result {
+	capacity := float64(policy.Limit)
+	perSecond := capacity / policy.Period.Seconds()
+
+	if elapsed := now.Sub(b.RefilledAt).Seconds(); elapsed > 0 {
+		b.Tokens = math.Min(capacity, b.Tokens+elapsed*perSecond)
+		b.RefilledAt = now
+	}
+
+	result := Result{Limit: policy.Limit}
+	if b.Tokens >= 1 {
+		b.Tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = secondsToDuration((1 - b.Tokens) / perSecond)
+	}
+	result.Remaining = int(math.Floor(b.Tokens))
+	result.ResetAfter = secondsToDuration((capacity - b.Tokens) / perSecond)
+	return result
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
+}
@@ -0,0 +1,50 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var frozenTime = time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+
+func TestBucket_Take(t *testing.T) {
+	policy := Policy{Name: "bets", Limit: 60, Period: time.Minute}
+	bucket := NewBucket(policy, frozenTime)
+
+	// The bucket starts full, with a burst of a whole period
+	for i := 0; i < 60; i++ {
+		result := bucket.Take(policy, frozenTime)
+		assert.True(t, result.Allowed)
+		assert.Equal(t, 59-i, result.Remaining)
+	}
+
+	result := bucket.Take(policy, frozenTime)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, 60, result.Limit)
+	assert.Equal(t, 0, result.Remaining)
+	assert.Equal(t, time.Second, result.RetryAfter)
+	assert.Equal(t, time.Minute, result.ResetAfter)
+
+	// It refills at the rate of the policy
+	result = bucket.Take(policy, frozenTime.Add(time.Second))
+	assert.True(t, result.Allowed)
+	result = bucket.Take(policy, frozenTime.Add(time.Second))
+	assert.False(t, result.Allowed)
+
+	// And never holds more than the limit
+	result = bucket.Take(policy, frozenTime.Add(time.Hour))
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 59, result.Remaining)
+	assert.Equal(t, time.Second, result.ResetAfter)
+}
+
+func TestBucket_IgnoresClockGoingBack(t *testing.T) {
+	policy := Policy{Name: "join", Limit: 2, Period: time.Minute}
+	bucket := NewBucket(policy, frozenTime)
+
+	assert.True(t, bucket.Take(policy, frozenTime).Allowed)
+	assert.True(t, bucket.Take(policy, frozenTime.Add(-time.Minute)).Allowed)
+	assert.False(t, bucket.Take(policy, frozenTime).Allowed)
+}
@@ -0,0 +1,104 @@
+package repositories
+
+import (
+	"context"
+	"ligain/backend/models"
+	"sync"
+	"time"
+)
+
+// AccountDeletionRepository stores the accounts deleted by their players during their grace period
+type AccountDeletionRepository interface {
+	// CreateDeletion records the deletion of an account
+	CreateDeletion(ctx context.Context, deletion *models.AccountDeletion) error
+	// GetDeletion returns the deletion of a player's account, or nil if the account isn't pending deletion
+	GetDeletion(ctx context.Context, playerID string) (*models.AccountDeletion, error)
+	// GetDeletionByOriginalName returns the last deletion of an account that had the given name, or nil if there is none
+	GetDeletionByOriginalName(ctx context.Context, name string) (*models.AccountDeletion, error)
+	// DeleteDeletion forgets the deletion of an account once it's restored
+	DeleteDeletion(ctx context.Context, playerID string) error
+	// GetDeletionsToPurge returns the deletions whose grace period ended before the given time
+	GetDeletionsToPurge(ctx context.Context, now time.Time) ([]*models.AccountDeletion, error)
+	// PurgePlayer removes the personal data of a deleted player along with their deletion, and returns the object keys
+	// of the data export archives it removed, to delete from the blob storage.
+	// The anonymized player is kept with their bets, bet history, scores and game memberships
+	PurgePlayer(ctx context.Context, playerID string) ([]string, error)
+}
+
+// InMemoryAccountDeletionRepository implements AccountDeletionRepository using in-memory storage.
+// The other in-memory repositories don't share its storage, so PurgePlayer only forgets the deletion
+type InMemoryAccountDeletionRepository struct {
+	mu        sync.RWMutex
+	deletions map[string]*models.AccountDeletion // playerID -> deletion
+}
+
+// NewInMemoryAccountDeletionRepository creates a new in-memory account deletion repository
+func NewInMemoryAccountDeletionRepository() *InMemoryAccountDeletionRepository {
+	return &InMemoryAccountDeletionRepository{
+		deletions: make(map[string]*models.AccountDeletion),
+	}
+}
+
+func (r *InMemoryAccountDeletionRepository) CreateDeletion(ctx context.Context, deletion *models.AccountDeletion) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := *deletion
+	r.deletions[deletion.PlayerID] = &stored
+	return nil
+}
+
+func (r *InMemoryAccountDeletionRepository) GetDeletion(ctx context.Context, playerID string) (*models.AccountDeletion, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	deletion, exists := r.deletions[playerID]
+	if !exists {
+		return nil, nil
+	}
+	result := *deletion
+	return &result, nil
+}
+
+func (r *InMemoryAccountDeletionRepository) GetDeletionByOriginalName(ctx context.Context, name string) (*models.AccountDeletion, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var latest *models.AccountDeletion
+	for _, deletion := range r.deletions {
+		if deletion.OriginalName == name && (latest == nil || deletion.DeletedAt.After(latest.DeletedAt)) {
+			latest = deletion
+		}
+	}
+	if latest == nil {
+		return nil, nil
+	}
+	result := *latest
+	return &result, nil
+}
+
+func (r *InMemoryAccountDeletionRepository) DeleteDeletion(ctx context.Context, playerID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.deletions, playerID)
+	return nil
+}
+
+func (r *InMemoryAccountDeletionRepository) GetDeletionsToPurge(ctx context.Context, now time.Time) ([]*models.AccountDeletion, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	deletions := make([]*models.AccountDeletion, 0)
+	for _, deletion := range r.deletions {
+		if !now.Before(deletion.PurgeAfter) {
+			result := *deletion
+			deletions = append(deletions, &result)
+		}
+	}
+	return deletions, nil
+}
+
+func (r *InMemoryAccountDeletionRepository) PurgePlayer(ctx context.Context, playerID string) ([]string, error) {
+	return nil, r.DeleteDeletion(ctx, playerID)
+}
@@ -0,0 +1,23 @@
+package repositories
+
+import (
+	"context"
+	"ligain/backend/models"
+	"time"
+)
+
+// AccountMergeRepository moves what a player owns to another player, when two accounts of the same person are merged.
+// The methods are meant to run in a single UnitOfWork transaction, so there is no in-memory implementation
+type AccountMergeRepository interface {
+	// MergeBets moves the bets, scores and bet history of the source player to the target player, and returns the number of bets moved.
+	// When both bet on the same match of a game, the bet updated last is kept with its score, the target's one on a tie
+	MergeBets(ctx context.Context, sourceID, targetID string) (int, []models.BetConflict, error)
+	// MergeGameData moves the game memberships, achievements, comments, reactions, activity and rating of the source player,
+	// and returns the games the source player was part of
+	MergeGameData(ctx context.Context, sourceID, targetID string) ([]string, error)
+	// MoveSessions moves the refresh and legacy tokens of the source player, so that its devices stay signed in
+	MoveSessions(ctx context.Context, sourceID, targetID string) error
+	// CompleteMerge gives the avatar, email and OAuth identity of the source player to the target player when it has none,
+	// records the merge and deletes the source player
+	CompleteMerge(ctx context.Context, source, target *models.PlayerData, mergedAt time.Time) error
+}
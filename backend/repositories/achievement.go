@@ -0,0 +1,91 @@
+package repositories
+
+import (
+	"context"
+	"ligain/backend/models"
+	"sort"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// AchievementRepository stores the achievements awarded to players
+type AchievementRepository interface {
+	// SaveAchievement awards an achievement. It returns false without error if the player already has it in this game
+	SaveAchievement(ctx context.Context, achievement *models.Achievement) (bool, error)
+	// GetPlayerAchievements returns the achievements of a player in every game, oldest first
+	GetPlayerAchievements(ctx context.Context, playerID string) ([]*models.Achievement, error)
+	// GetMatchAchievements returns the achievements awarded with the result of a match of a game
+	GetMatchAchievements(ctx context.Context, gameID, matchID string) ([]*models.Achievement, error)
+	// DeleteAchievement revokes an achievement. Revoking a missing achievement does nothing
+	DeleteAchievement(ctx context.Context, achievementID string) error
+}
+
+// InMemoryAchievementRepository implements AchievementRepository using in-memory storage
+type InMemoryAchievementRepository struct {
+	mu           sync.RWMutex
+	achievements map[string][]*models.Achievement // playerID -> achievements
+}
+
+// NewInMemoryAchievementRepository creates a new in-memory achievement repository
+func NewInMemoryAchievementRepository() *InMemoryAchievementRepository {
+	return &InMemoryAchievementRepository{
+		achievements: make(map[string][]*models.Achievement),
+	}
+}
+
+func (r *InMemoryAchievementRepository) SaveAchievement(ctx context.Context, achievement *models.Achievement) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.achievements[achievement.PlayerID] {
+		if existing.GameID == achievement.GameID && existing.Code == achievement.Code {
+			return false, nil
+		}
+	}
+	achievement.ID = uuid.New().String()
+	r.achievements[achievement.PlayerID] = append(r.achievements[achievement.PlayerID], achievement)
+	return true, nil
+}
+
+func (r *InMemoryAchievementRepository) GetPlayerAchievements(ctx context.Context, playerID string) ([]*models.Achievement, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	achievements := make([]*models.Achievement, len(r.achievements[playerID]))
+	copy(achievements, r.achievements[playerID])
+	sort.SliceStable(achievements, func(i, j int) bool {
+		return achievements[i].AwardedAt.Before(achievements[j].AwardedAt)
+	})
+	return achievements, nil
+}
+
+func (r *InMemoryAchievementRepository) GetMatchAchievements(ctx context.Context, gameID, matchID string) ([]*models.Achievement, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	achievements := make([]*models.Achievement, 0)
+	for _, playerAchievements := range r.achievements {
+		for _, achievement := range playerAchievements {
+			if achievement.GameID == gameID && achievement.MatchID == matchID {
+				achievements = append(achievements, achievement)
+			}
+		}
+	}
+	return achievements, nil
+}
+
+func (r *InMemoryAchievementRepository) DeleteAchievement(ctx context.Context, achievementID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for playerID, achievements := range r.achievements {
+		for i, achievement := range achievements {
+			if achievement.ID == achievementID {
+				r.achievements[playerID] = append(achievements[:i:i], achievements[i+1:]...)
+				return nil
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package repositories
+
+import (
+	"context"
+	"ligain/backend/models"
+	"sync"
+)
+
+// ActivityRepository stores the activity feed of the games
+type ActivityRepository interface {
+	// SaveActivity appends an activity to the feed of its game and sets its id
+	SaveActivity(ctx context.Context, activity *models.Activity) error
+	// GetActivities returns at most limit activities of a game, newest first.
+	// Only the activities with an id lower than beforeID are returned, unless beforeID is 0
+	GetActivities(ctx context.Context, gameID string, beforeID int64, limit int) ([]*models.Activity, error)
+	// GetLatestMatchActivity returns the last activity of a type about a match of a game, or nil if there is none
+	GetLatestMatchActivity(ctx context.Context, gameID, matchID string, activityType models.ActivityType) (*models.Activity, error)
+}
+
+// InMemoryActivityRepository implements ActivityRepository using in-memory storage
+type InMemoryActivityRepository struct {
+	mu         sync.RWMutex
+	lastID     int64
+	activities map[string][]*models.Activity // gameID -> activities, oldest first
+}
+
+// NewInMemoryActivityRepository creates a new in-memory activity repository
+func NewInMemoryActivityRepository() *InMemoryActivityRepository {
+	return &InMemoryActivityRepository{
+		activities: make(map[string][]*models.Activity),
+	}
+}
+
+func (r *InMemoryActivityRepository) SaveActivity(ctx context.Context, activity *models.Activity) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.lastID++
+	activity.ID = r.lastID
+	stored := *activity
+	r.activities[activity.GameID] = append(r.activities[activity.GameID], &stored)
+	return nil
+}
+
+func (r *InMemoryActivityRepository) GetActivities(ctx context.Context, gameID string, beforeID int64, limit int) ([]*models.Activity, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	activities := r.activities[gameID]
+	result := make([]*models.Activity, 0)
+	for i := len(activities) - 1; i >= 0 && len(result) < limit; i-- {
+		if beforeID > 0 && activities[i].ID >= beforeID {
+			continue
+		}
+		activity := *activities[i]
+		result = append(result, &activity)
+	}
+	return result, nil
+}
+
+func (r *InMemoryActivityRepository) GetLatestMatchActivity(ctx context.Context, gameID, matchID string, activityType models.ActivityType) (*models.Activity, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	activities := r.activities[gameID]
+	for i := len(activities) - 1; i >= 0; i-- {
+		if activities[i].MatchID == matchID && activities[i].Type == activityType {
+			activity := *activities[i]
+			return &activity, nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,177 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"ligain/backend/models"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ErrPlayerNotFound is returned when changing a player that doesn't exist
+var ErrPlayerNotFound = errors.New("player not found")
+
+// AdminRepository holds the queries of the operator API, and its audit log
+type AdminRepository interface {
+	// SearchPlayers returns at most limit players whose id is the query, or whose name or email contains it, ignoring case.
+	// Every player matches an empty query
+	SearchPlayers(ctx context.Context, query string, limit int) ([]*models.PlayerData, error)
+	// SearchGames returns at most limit games, newest first, whose id or join code is the query, or whose name contains it, ignoring case.
+	// Every game matches an empty query, the finished ones included
+	SearchGames(ctx context.Context, query string, limit int) ([]*models.AdminGameSummary, error)
+	// SetPlayerRole changes the role of a player, and returns ErrPlayerNotFound if there is no such player
+	SetPlayerRole(ctx context.Context, playerID string, role string) error
+	// SaveAuditEntry appends an entry to the audit log and sets its id
+	SaveAuditEntry(ctx context.Context, entry *models.AdminAuditEntry) error
+	// GetAuditEntries returns at most limit entries of the audit log, newest first.
+	// Only the entries about the given target are returned, unless targetType is empty,
+	// and only the entries with an id lower than beforeID, unless beforeID is 0
+	GetAuditEntries(ctx context.Context, targetType string, targetID string, beforeID int64, limit int) ([]*models.AdminAuditEntry, error)
+}
+
+// InMemoryAdminRepository implements AdminRepository on top of the other in-memory repositories.
+// The in-memory game repository doesn't list the finished games, so they can't be searched
+type InMemoryAdminRepository struct {
+	mu             sync.RWMutex
+	playerRepo     *InMemoryPlayerRepository
+	gameRepo       GameRepository
+	gameCodeRepo   GameCodeRepository
+	gamePlayerRepo GamePlayerRepository
+	lastID         int64
+	entries        []*models.AdminAuditEntry // oldest first
+}
+
+// NewInMemoryAdminRepository creates a new in-memory admin repository
+func NewInMemoryAdminRepository(playerRepo *InMemoryPlayerRepository, gameRepo GameRepository, gameCodeRepo GameCodeRepository, gamePlayerRepo GamePlayerRepository) *InMemoryAdminRepository {
+	return &InMemoryAdminRepository{
+		playerRepo:     playerRepo,
+		gameRepo:       gameRepo,
+		gameCodeRepo:   gameCodeRepo,
+		gamePlayerRepo: gamePlayerRepo,
+	}
+}
+
+func (r *InMemoryAdminRepository) SearchPlayers(ctx context.Context, query string, limit int) ([]*models.PlayerData, error) {
+	players := make([]*models.PlayerData, 0)
+	for _, player := range r.playerRepo.players {
+		playerData, ok := player.(*models.PlayerData)
+		if !ok {
+			continue
+		}
+		email := ""
+		if playerData.Email != nil {
+			email = *playerData.Email
+		}
+		if matchesSearch(query, playerData.ID, "", playerData.Name, email) {
+			playerCopy := *playerData
+			players = append(players, &playerCopy)
+		}
+	}
+	sort.Slice(players, func(i, j int) bool {
+		return players[i].Name < players[j].Name
+	})
+	if len(players) > limit {
+		players = players[:limit]
+	}
+	return players, nil
+}
+
+func (r *InMemoryAdminRepository) SearchGames(ctx context.Context, query string, limit int) ([]*models.AdminGameSummary, error) {
+	games, err := r.gameRepo.GetAllGames()
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make([]*models.AdminGameSummary, 0)
+	for gameID, game := range games {
+		code := ""
+		if gameCode, err := r.gameCodeRepo.GetGameCodeByGameID(gameID); err == nil {
+			code = gameCode.Code
+		}
+		if !matchesSearch(query, gameID, code, game.GetName()) {
+			continue
+		}
+		players, err := r.gamePlayerRepo.GetPlayersInGame(ctx, gameID)
+		if err != nil {
+			return nil, err
+		}
+		summaries = append(summaries, &models.AdminGameSummary{
+			ID:              gameID,
+			Name:            game.GetName(),
+			CompetitionName: game.GetCompetitionName(),
+			SeasonYear:      game.GetSeasonYear(),
+			Status:          string(game.GetGameStatus()),
+			PlayerCount:     len(players),
+			Code:            code,
+		})
+	}
+	// The in-memory ids are increasing numbers, so the newest games have the longest and then the greatest ids
+	sort.Slice(summaries, func(i, j int) bool {
+		if len(summaries[i].ID) != len(summaries[j].ID) {
+			return len(summaries[i].ID) > len(summaries[j].ID)
+		}
+		return summaries[i].ID > summaries[j].ID
+	})
+	if len(summaries) > limit {
+		summaries = summaries[:limit]
+	}
+	return summaries, nil
+}
+
+func (r *InMemoryAdminRepository) SetPlayerRole(ctx context.Context, playerID string, role string) error {
+	player, exists := r.playerRepo.players[playerID]
+	if !exists {
+		return ErrPlayerNotFound
+	}
+	playerData, ok := player.(*models.PlayerData)
+	if !ok {
+		return ErrPlayerNotFound
+	}
+	playerData.Role = role
+	return nil
+}
+
+func (r *InMemoryAdminRepository) SaveAuditEntry(ctx context.Context, entry *models.AdminAuditEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.lastID++
+	entry.ID = r.lastID
+	stored := *entry
+	r.entries = append(r.entries, &stored)
+	return nil
+}
+
+func (r *InMemoryAdminRepository) GetAuditEntries(ctx context.Context, targetType string, targetID string, beforeID int64, limit int) ([]*models.AdminAuditEntry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]*models.AdminAuditEntry, 0)
+	for i := len(r.entries) - 1; i >= 0 && len(result) < limit; i-- {
+		entry := r.entries[i]
+		if beforeID > 0 && entry.ID >= beforeID {
+			continue
+		}
+		if targetType != "" && (entry.TargetType != targetType || entry.TargetID != targetID) {
+			continue
+		}
+		entryCopy := *entry
+		result = append(result, &entryCopy)
+	}
+	return result, nil
+}
+
+// matchesSearch checks if the query is the id or the code, or is contained in one of the texts, ignoring case
+func matchesSearch(query string, id string, code string, texts ...string) bool {
+	if query == "" || query == id || (code != "" && strings.EqualFold(query, code)) {
+		return true
+	}
+	query = strings.ToLower(query)
+	for _, text := range texts {
+		if strings.Contains(strings.ToLower(text), query) {
+			return true
+		}
+	}
+	return false
+}
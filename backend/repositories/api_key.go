@@ -0,0 +1,128 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"ligain/backend/models"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrAPIKeyNotFound is returned when an API key doesn't exist
+var ErrAPIKeyNotFound = errors.New("api key not found")
+
+// APIKeyRepository stores the API keys of the clients
+type APIKeyRepository interface {
+	// CreateAPIKey stores a new key and sets its ID
+	CreateAPIKey(ctx context.Context, key *models.APIKey) error
+	// GetAPIKeyByHash returns the key with the given hash, or nil if there is none
+	GetAPIKeyByHash(ctx context.Context, keyHash string) (*models.APIKey, error)
+	// GetAPIKeysByName returns the keys of a client, oldest first
+	GetAPIKeysByName(ctx context.Context, name string) ([]*models.APIKey, error)
+	// ListAPIKeys returns every key, ordered by client and then oldest first
+	ListAPIKeys(ctx context.Context) ([]*models.APIKey, error)
+	// SetAPIKeyExpiry sets the time after which a key isn't accepted anymore
+	SetAPIKeyExpiry(ctx context.Context, keyID string, expiresAt time.Time) error
+	// RevokeAPIKey stops accepting a key right away
+	RevokeAPIKey(ctx context.Context, keyID string, revokedAt time.Time) error
+}
+
+// InMemoryAPIKeyRepository implements APIKeyRepository using in-memory storage
+type InMemoryAPIKeyRepository struct {
+	mu   sync.RWMutex
+	keys map[string]*models.APIKey // keyID -> key
+}
+
+// NewInMemoryAPIKeyRepository creates a new in-memory API key repository
+func NewInMemoryAPIKeyRepository() *InMemoryAPIKeyRepository {
+	return &InMemoryAPIKeyRepository{
+		keys: make(map[string]*models.APIKey),
+	}
+}
+
+func (r *InMemoryAPIKeyRepository) CreateAPIKey(ctx context.Context, key *models.APIKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key.ID = uuid.New().String()
+	r.keys[key.ID] = copyAPIKey(key)
+	return nil
+}
+
+func (r *InMemoryAPIKeyRepository) GetAPIKeyByHash(ctx context.Context, keyHash string) (*models.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, key := range r.keys {
+		if key.KeyHash == keyHash {
+			return copyAPIKey(key), nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *InMemoryAPIKeyRepository) GetAPIKeysByName(ctx context.Context, name string) ([]*models.APIKey, error) {
+	keys, err := r.ListAPIKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	named := make([]*models.APIKey, 0)
+	for _, key := range keys {
+		if key.Name == name {
+			named = append(named, key)
+		}
+	}
+	return named, nil
+}
+
+func (r *InMemoryAPIKeyRepository) ListAPIKeys(ctx context.Context) ([]*models.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	keys := make([]*models.APIKey, 0, len(r.keys))
+	for _, key := range r.keys {
+		keys = append(keys, copyAPIKey(key))
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Name != keys[j].Name {
+			return keys[i].Name < keys[j].Name
+		}
+		return keys[i].CreatedAt.Before(keys[j].CreatedAt)
+	})
+	return keys, nil
+}
+
+func (r *InMemoryAPIKeyRepository) SetAPIKeyExpiry(ctx context.Context, keyID string, expiresAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key, exists := r.keys[keyID]
+	if !exists {
+		return ErrAPIKeyNotFound
+	}
+	key.ExpiresAt = &expiresAt
+	return nil
+}
+
+func (r *InMemoryAPIKeyRepository) RevokeAPIKey(ctx context.Context, keyID string, revokedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key, exists := r.keys[keyID]
+	if !exists {
+		return ErrAPIKeyNotFound
+	}
+	key.RevokedAt = &revokedAt
+	return nil
+}
+
+// copyAPIKey copies a key along with its scopes
+func copyAPIKey(key *models.APIKey) *models.APIKey {
+	keyCopy := *key
+	keyCopy.Scopes = append([]string(nil), key.Scopes...)
+	return &keyCopy
+}
@@ -1,9 +1,11 @@
 package repositories
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"ligain/backend/models"
+	"sync"
 )
 
 const betCacheSize = 5000 // Maximum number of bets to keep in cache
@@ -14,7 +16,7 @@ type BetRepository interface {
 	// GetBets returns all bets for a given player
 	GetBets(gameId string, player models.Player) ([]*models.Bet, error)
 	// SaveBet saves or updates a bet and returns the bet id and the bet
-	SaveBet(gameId string, bet *models.Bet, player models.Player) (string, *models.Bet, error)
+	SaveBet(ctx context.Context, gameId string, bet *models.Bet, player models.Player) (string, *models.Bet, error)
 	// GetBetsForMatch returns all bets and their associated players for a specific match
 	GetBetsForMatch(match models.Match, gameId string) ([]*models.Bet, []models.Player, error)
 	// SaveWithId saves a bet with a specific ID
@@ -27,6 +29,15 @@ type BetRepository interface {
 	GetScores(gameId string) (map[string]map[string]int, error)
 	// GetScoresByMatchAndPlayer returns scores organized by match ID and player
 	GetScoresByMatchAndPlayer(gameId string) (map[string]map[string]int, error)
+	// GetPlayerBets returns every bet of a player across all games, with its score when the match was scored
+	GetPlayerBets(playerId string) ([]*models.PlayerBetRecord, error)
+	// ClaimScoring records a scoring of a match within the transaction of ctx.
+	// Returns false when that scoring was already applied, in which case nothing must be saved for it
+	ClaimScoring(ctx context.Context, scoring *models.MatchScoring) (bool, error)
+	// GetScoringVersions returns the version of the last scoring of each scored match of a game
+	GetScoringVersions(ctx context.Context, gameId string) (map[string]int, error)
+	// SaveScores saves the points of the players for a match within the transaction of ctx
+	SaveScores(ctx context.Context, gameId string, match models.Match, players []models.Player, scores map[string]int) error
 }
 
 type BetEntry struct {
@@ -40,11 +51,15 @@ type BetEntry struct {
 
 type InMemoryBetRepository struct {
 	cache *Cache[string, BetEntry]
+	// scoringVersions holds the versions of the scorings applied to each match, by game and match
+	scoringMu       sync.Mutex
+	scoringVersions map[string]map[string]map[int]bool
 }
 
 func NewInMemoryBetRepository() *InMemoryBetRepository {
 	return &InMemoryBetRepository{
-		cache: NewCache[string, BetEntry](betCacheSize),
+		cache:           NewCache[string, BetEntry](betCacheSize),
+		scoringVersions: make(map[string]map[string]map[int]bool),
 	}
 }
 
@@ -58,7 +73,7 @@ func (r *InMemoryBetRepository) GetBets(gameId string, player models.Player) ([]
 	return bets, nil
 }
 
-func (r *InMemoryBetRepository) SaveBet(gameId string, bet *models.Bet, player models.Player) (string, *models.Bet, error) {
+func (r *InMemoryBetRepository) SaveBet(ctx context.Context, gameId string, bet *models.Bet, player models.Player) (string, *models.Bet, error) {
 	matchId := bet.Match.Id()
 	betKey := fmt.Sprintf("%s:%s:%s", gameId, player.GetName(), matchId)
 	entry := BetEntry{
@@ -156,3 +171,67 @@ func (r *InMemoryBetRepository) GetScoresByMatchAndPlayer(gameId string) (map[st
 	}
 	return playerScores, nil
 }
+
+func (r *InMemoryBetRepository) GetPlayerBets(playerId string) ([]*models.PlayerBetRecord, error) {
+	records := make([]*models.PlayerBetRecord, 0)
+	for _, entry := range r.cache.GetAll() {
+		if entry.Value.Bet == nil || entry.Value.Player.GetID() != playerId {
+			continue
+		}
+		records = append(records, &models.PlayerBetRecord{
+			GameID:             entry.Value.GameId,
+			MatchID:            entry.Value.MatchId,
+			HomeTeam:           entry.Value.Bet.Match.GetHomeTeam(),
+			AwayTeam:           entry.Value.Bet.Match.GetAwayTeam(),
+			PredictedHomeGoals: entry.Value.Bet.PredictedHomeGoals,
+			PredictedAwayGoals: entry.Value.Bet.PredictedAwayGoals,
+			Points:             entry.Value.Points,
+		})
+	}
+	return records, nil
+}
+
+func (r *InMemoryBetRepository) ClaimScoring(ctx context.Context, scoring *models.MatchScoring) (bool, error) {
+	r.scoringMu.Lock()
+	defer r.scoringMu.Unlock()
+	if _, ok := r.scoringVersions[scoring.GameID]; !ok {
+		r.scoringVersions[scoring.GameID] = make(map[string]map[int]bool)
+	}
+	versions, ok := r.scoringVersions[scoring.GameID][scoring.MatchID]
+	if !ok {
+		versions = make(map[int]bool)
+		r.scoringVersions[scoring.GameID][scoring.MatchID] = versions
+	}
+	if versions[scoring.Version] {
+		return false, nil
+	}
+	versions[scoring.Version] = true
+	return true, nil
+}
+
+func (r *InMemoryBetRepository) GetScoringVersions(ctx context.Context, gameId string) (map[string]int, error) {
+	r.scoringMu.Lock()
+	defer r.scoringMu.Unlock()
+	lastVersions := make(map[string]int)
+	for matchId, versions := range r.scoringVersions[gameId] {
+		for version := range versions {
+			if version > lastVersions[matchId] {
+				lastVersions[matchId] = version
+			}
+		}
+	}
+	return lastVersions, nil
+}
+
+func (r *InMemoryBetRepository) SaveScores(ctx context.Context, gameId string, match models.Match, players []models.Player, scores map[string]int) error {
+	for _, player := range players {
+		points, scored := scores[player.GetID()]
+		if !scored {
+			continue
+		}
+		if err := r.SaveScore(gameId, match, player, points); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package repositories
+
+import (
+	"context"
+	"ligain/backend/models"
+	"sync"
+)
+
+// BetHistoryRepository stores every change of the bets, the bet table only keeping the latest prediction
+type BetHistoryRepository interface {
+	// RecordBetChange appends a change to the history of the bets, and sets its id
+	RecordBetChange(ctx context.Context, change *models.BetChange) error
+	// GetBetHistory returns the changes of the bets of a game, oldest first.
+	// Only the changes of playerID and on matchID are returned, unless they're empty
+	GetBetHistory(ctx context.Context, gameID string, playerID string, matchID string) ([]*models.BetChange, error)
+	// GetPlayerBetHistory returns the changes of the bets of a player in every game, oldest first
+	GetPlayerBetHistory(ctx context.Context, playerID string) ([]*models.BetChange, error)
+}
+
+// InMemoryBetHistoryRepository implements BetHistoryRepository using in-memory storage
+type InMemoryBetHistoryRepository struct {
+	mu      sync.RWMutex
+	changes []*models.BetChange
+}
+
+// NewInMemoryBetHistoryRepository creates a new in-memory bet history repository
+func NewInMemoryBetHistoryRepository() *InMemoryBetHistoryRepository {
+	return &InMemoryBetHistoryRepository{
+		changes: make([]*models.BetChange, 0),
+	}
+}
+
+func (r *InMemoryBetHistoryRepository) RecordBetChange(ctx context.Context, change *models.BetChange) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	change.ID = int64(len(r.changes)) + 1
+	stored := *change
+	r.changes = append(r.changes, &stored)
+	return nil
+}
+
+func (r *InMemoryBetHistoryRepository) GetBetHistory(ctx context.Context, gameID string, playerID string, matchID string) ([]*models.BetChange, error) {
+	return r.filter(func(change *models.BetChange) bool {
+		return change.GameID == gameID &&
+			(playerID == "" || change.PlayerID == playerID) &&
+			(matchID == "" || change.MatchID == matchID)
+	}), nil
+}
+
+func (r *InMemoryBetHistoryRepository) GetPlayerBetHistory(ctx context.Context, playerID string) ([]*models.BetChange, error) {
+	return r.filter(func(change *models.BetChange) bool {
+		return change.PlayerID == playerID
+	}), nil
+}
+
+// filter returns copies of the changes matching keep, oldest first
+func (r *InMemoryBetHistoryRepository) filter(keep func(change *models.BetChange) bool) []*models.BetChange {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]*models.BetChange, 0)
+	for _, change := range r.changes {
+		if keep(change) {
+			copied := *change
+			result = append(result, &copied)
+		}
+	}
+	return result
+}
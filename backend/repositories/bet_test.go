@@ -1,6 +1,7 @@
 package repositories
 
 import (
+	"context"
 	"ligain/backend/models"
 	"ligain/backend/testutils"
 	"testing"
@@ -35,7 +36,7 @@ func TestInMemoryBetRepository_SaveAndGetBets(t *testing.T) {
 	bet := models.NewBet(match, 2, 1)
 
 	// Test saving a bet
-	betId, savedBet, err := repo.SaveBet("test-id", bet, player)
+	betId, savedBet, err := repo.SaveBet(context.Background(), "test-id", bet, player)
 	if err != nil {
 		t.Errorf("Failed to save bet: %v", err)
 	}
@@ -66,7 +67,7 @@ func TestInMemoryBetRepository_UpdateBet(t *testing.T) {
 
 	// Save initial bet
 	initialBet := models.NewBet(match, 2, 1)
-	_, savedBet, err := repo.SaveBet("test-id", initialBet, player)
+	_, savedBet, err := repo.SaveBet(context.Background(), "test-id", initialBet, player)
 	if err != nil {
 		t.Errorf("Failed to save initial bet: %v", err)
 	}
@@ -76,7 +77,7 @@ func TestInMemoryBetRepository_UpdateBet(t *testing.T) {
 
 	// Update the bet
 	updatedBet := models.NewBet(match, 3, 2)
-	_, savedBet, err = repo.SaveBet("test-id", updatedBet, player)
+	_, savedBet, err = repo.SaveBet(context.Background(), "test-id", updatedBet, player)
 	if err != nil {
 		t.Errorf("Failed to update bet: %v", err)
 	}
@@ -107,14 +108,14 @@ func TestInMemoryBetRepository_GetBetsForMatch(t *testing.T) {
 	// Save bets for both players
 	bet1 := models.NewBet(match, 2, 1)
 	bet2 := models.NewBet(match, 1, 1)
-	_, savedBet1, err := repo.SaveBet("test-id", bet1, player1)
+	_, savedBet1, err := repo.SaveBet(context.Background(), "test-id", bet1, player1)
 	if err != nil {
 		t.Errorf("Failed to save bet1: %v", err)
 	}
 	if savedBet1 == nil {
 		t.Error("Expected non-nil saved bet1")
 	}
-	_, savedBet2, err := repo.SaveBet("test-id", bet2, player2)
+	_, savedBet2, err := repo.SaveBet(context.Background(), "test-id", bet2, player2)
 	if err != nil {
 		t.Errorf("Failed to save bet2: %v", err)
 	}
@@ -182,7 +183,7 @@ func TestInMemoryBetRepository_SaveAndGetScore(t *testing.T) {
 	bet := models.NewBet(match, 2, 1)
 
 	// Save bet first
-	betId, savedBet, err := repo.SaveBet("test-id", bet, player)
+	betId, savedBet, err := repo.SaveBet(context.Background(), "test-id", bet, player)
 	if err != nil {
 		t.Errorf("Failed to save bet: %v", err)
 	}
@@ -213,7 +214,7 @@ func TestInMemoryBetRepository_GetScoreNotFound(t *testing.T) {
 	bet := models.NewBet(match, 2, 1)
 
 	// Save bet without score
-	betId, savedBet, err := repo.SaveBet("test-id", bet, player)
+	betId, savedBet, err := repo.SaveBet(context.Background(), "test-id", bet, player)
 	if err != nil {
 		t.Errorf("Failed to save bet: %v", err)
 	}
@@ -237,14 +238,14 @@ func TestInMemoryBetRepository_GetScores(t *testing.T) {
 	// Save bets and scores
 	bet1 := models.NewBet(match, 2, 1)
 	bet2 := models.NewBet(match, 1, 1)
-	_, savedBet1, err := repo.SaveBet("test-id", bet1, player1)
+	_, savedBet1, err := repo.SaveBet(context.Background(), "test-id", bet1, player1)
 	if err != nil {
 		t.Errorf("Failed to save bet1: %v", err)
 	}
 	if savedBet1 == nil {
 		t.Error("Expected non-nil saved bet1")
 	}
-	_, savedBet2, err := repo.SaveBet("test-id", bet2, player2)
+	_, savedBet2, err := repo.SaveBet(context.Background(), "test-id", bet2, player2)
 	if err != nil {
 		t.Errorf("Failed to save bet2: %v", err)
 	}
@@ -291,14 +292,14 @@ func TestInMemoryBetRepository_GetScoresByMatchAndPlayer(t *testing.T) {
 	// Save bets and scores
 	bet1 := models.NewBet(match, 2, 1)
 	bet2 := models.NewBet(match, 1, 1)
-	_, savedBet1, err := repo.SaveBet("test-id", bet1, player1)
+	_, savedBet1, err := repo.SaveBet(context.Background(), "test-id", bet1, player1)
 	if err != nil {
 		t.Errorf("Failed to save bet1: %v", err)
 	}
 	if savedBet1 == nil {
 		t.Error("Expected non-nil saved bet1")
 	}
-	_, savedBet2, err := repo.SaveBet("test-id", bet2, player2)
+	_, savedBet2, err := repo.SaveBet(context.Background(), "test-id", bet2, player2)
 	if err != nil {
 		t.Errorf("Failed to save bet2: %v", err)
 	}
@@ -3,12 +3,15 @@ package repositories
 import (
 	"container/list"
 	"errors"
+	"sync"
 )
 
 var ErrNotFound = errors.New("cache: key not found")
 
-// Cache is a generic LRU cache implementation that can be used by any repository
+// Cache is a generic LRU cache implementation that can be used by any repository.
+// It's safe for concurrent use, as the requests and the cache invalidation use it from several goroutines
 type Cache[K comparable, V any] struct {
+	mu       sync.Mutex
 	capacity int
 	items    map[K]*list.Element
 	list     *list.List
@@ -30,6 +33,9 @@ func NewCache[K comparable, V any](capacity int) *Cache[K, V] {
 
 // Get retrieves a value from the cache
 func (c *Cache[K, V]) Get(key K) (V, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	if element, found := c.items[key]; found {
 		c.list.MoveToFront(element)
 		return element.Value.(*cacheEntry[K, V]).value, nil
@@ -43,6 +49,9 @@ func (c *Cache[K, V]) GetAll() []struct {
 	Key   K
 	Value V
 } {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	entries := make([]struct {
 		Key   K
 		Value V
@@ -62,6 +71,9 @@ func (c *Cache[K, V]) GetAll() []struct {
 
 // Set adds or updates a value in the cache
 func (c *Cache[K, V]) Set(key K, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	if element, found := c.items[key]; found {
 		c.list.MoveToFront(element)
 		element.Value.(*cacheEntry[K, V]).value = value
@@ -86,6 +98,9 @@ func (c *Cache[K, V]) Set(key K, value V) {
 
 // Delete removes a value from the cache
 func (c *Cache[K, V]) Delete(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	if element, found := c.items[key]; found {
 		c.list.Remove(element)
 		delete(c.items, key)
@@ -94,17 +109,26 @@ func (c *Cache[K, V]) Delete(key K) {
 
 // Clear removes all values from the cache
 func (c *Cache[K, V]) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	c.items = make(map[K]*list.Element)
 	c.list = list.New()
 }
 
 // Len returns the number of items in the cache
 func (c *Cache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	return c.list.Len()
 }
 
-// Range iterates over all entries in the cache
+// Range iterates over all entries in the cache. f must not use the cache, which is locked meanwhile
 func (c *Cache[K, V]) Range(f func(key K, value V) bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	for element := c.list.Front(); element != nil; element = element.Next() {
 		entry := element.Value.(*cacheEntry[K, V])
 		if !f(entry.key, entry.value) {
@@ -0,0 +1,187 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"ligain/backend/models"
+	"sort"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// ErrCommentNotFound is returned when a comment doesn't exist
+var ErrCommentNotFound = errors.New("comment not found")
+
+// CommentRepository stores the comments and reactions posted on the matches of a game
+type CommentRepository interface {
+	// CreateComment saves a new comment and sets its id
+	CreateComment(ctx context.Context, comment *models.MatchComment) error
+	// GetComment returns a comment by id, or ErrCommentNotFound
+	GetComment(ctx context.Context, commentID string) (*models.MatchComment, error)
+	// GetComments returns the comments of a match in a game, oldest first
+	GetComments(ctx context.Context, gameID, matchID string) ([]*models.MatchComment, error)
+	// GetPlayerComments returns the comments a player posted in every game, oldest first
+	GetPlayerComments(ctx context.Context, playerID string) ([]*models.MatchComment, error)
+	// DeleteComment removes a comment, or returns ErrCommentNotFound
+	DeleteComment(ctx context.Context, commentID string) error
+
+	// AddReaction saves a reaction. Adding the same reaction twice does nothing
+	AddReaction(ctx context.Context, reaction *models.MatchReaction) error
+	// RemoveReaction removes a reaction. Removing a missing reaction does nothing
+	RemoveReaction(ctx context.Context, gameID, matchID, playerID, emoji string) error
+	// GetReactions returns the reactions on a match in a game, oldest first
+	GetReactions(ctx context.Context, gameID, matchID string) ([]*models.MatchReaction, error)
+	// GetPlayerReactions returns the reactions of a player in every game, oldest first
+	GetPlayerReactions(ctx context.Context, playerID string) ([]*models.MatchReaction, error)
+}
+
+// InMemoryCommentRepository implements CommentRepository using in-memory storage
+type InMemoryCommentRepository struct {
+	mu        sync.RWMutex
+	comments  map[string]*models.MatchComment // commentID -> comment
+	order     []string                        // commentIDs in posting order
+	reactions []*models.MatchReaction
+}
+
+// NewInMemoryCommentRepository creates a new in-memory comment repository
+func NewInMemoryCommentRepository() *InMemoryCommentRepository {
+	return &InMemoryCommentRepository{
+		comments: make(map[string]*models.MatchComment),
+	}
+}
+
+func (r *InMemoryCommentRepository) CreateComment(ctx context.Context, comment *models.MatchComment) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	comment.ID = uuid.New().String()
+	stored := *comment
+	r.comments[comment.ID] = &stored
+	r.order = append(r.order, comment.ID)
+	return nil
+}
+
+func (r *InMemoryCommentRepository) GetComment(ctx context.Context, commentID string) (*models.MatchComment, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	comment, exists := r.comments[commentID]
+	if !exists {
+		return nil, ErrCommentNotFound
+	}
+	result := *comment
+	return &result, nil
+}
+
+func (r *InMemoryCommentRepository) GetComments(ctx context.Context, gameID, matchID string) ([]*models.MatchComment, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	comments := make([]*models.MatchComment, 0)
+	for _, commentID := range r.order {
+		comment, exists := r.comments[commentID]
+		if exists && comment.GameID == gameID && comment.MatchID == matchID {
+			result := *comment
+			comments = append(comments, &result)
+		}
+	}
+	sort.SliceStable(comments, func(i, j int) bool {
+		return comments[i].CreatedAt.Before(comments[j].CreatedAt)
+	})
+	return comments, nil
+}
+
+func (r *InMemoryCommentRepository) GetPlayerComments(ctx context.Context, playerID string) ([]*models.MatchComment, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	comments := make([]*models.MatchComment, 0)
+	for _, commentID := range r.order {
+		comment, exists := r.comments[commentID]
+		if exists && comment.PlayerID == playerID {
+			result := *comment
+			comments = append(comments, &result)
+		}
+	}
+	sort.SliceStable(comments, func(i, j int) bool {
+		return comments[i].CreatedAt.Before(comments[j].CreatedAt)
+	})
+	return comments, nil
+}
+
+func (r *InMemoryCommentRepository) DeleteComment(ctx context.Context, commentID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.comments[commentID]; !exists {
+		return ErrCommentNotFound
+	}
+	delete(r.comments, commentID)
+	for i, id := range r.order {
+		if id == commentID {
+			r.order = append(r.order[:i], r.order[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (r *InMemoryCommentRepository) AddReaction(ctx context.Context, reaction *models.MatchReaction) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.reactions {
+		if sameReaction(existing, reaction.GameID, reaction.MatchID, reaction.PlayerID, reaction.Emoji) {
+			return nil
+		}
+	}
+	stored := *reaction
+	r.reactions = append(r.reactions, &stored)
+	return nil
+}
+
+func (r *InMemoryCommentRepository) RemoveReaction(ctx context.Context, gameID, matchID, playerID, emoji string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, existing := range r.reactions {
+		if sameReaction(existing, gameID, matchID, playerID, emoji) {
+			r.reactions = append(r.reactions[:i], r.reactions[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (r *InMemoryCommentRepository) GetReactions(ctx context.Context, gameID, matchID string) ([]*models.MatchReaction, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	reactions := make([]*models.MatchReaction, 0)
+	for _, reaction := range r.reactions {
+		if reaction.GameID == gameID && reaction.MatchID == matchID {
+			result := *reaction
+			reactions = append(reactions, &result)
+		}
+	}
+	return reactions, nil
+}
+
+func (r *InMemoryCommentRepository) GetPlayerReactions(ctx context.Context, playerID string) ([]*models.MatchReaction, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	reactions := make([]*models.MatchReaction, 0)
+	for _, reaction := range r.reactions {
+		if reaction.PlayerID == playerID {
+			result := *reaction
+			reactions = append(reactions, &result)
+		}
+	}
+	return reactions, nil
+}
+
+func sameReaction(reaction *models.MatchReaction, gameID, matchID, playerID, emoji string) bool {
+	return reaction.GameID == gameID && reaction.MatchID == matchID && reaction.PlayerID == playerID && reaction.Emoji == emoji
+}
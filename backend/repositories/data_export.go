@@ -0,0 +1,122 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"ligain/backend/models"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrDataExportNotFound is returned when a data export doesn't exist
+var ErrDataExportNotFound = errors.New("data export not found")
+
+// DataExportRepository stores the data export requests of the players
+type DataExportRepository interface {
+	// CreateExport saves a new export and sets its id
+	CreateExport(ctx context.Context, export *models.DataExport) error
+	// GetExport returns an export by id, or ErrDataExportNotFound
+	GetExport(ctx context.Context, exportID string) (*models.DataExport, error)
+	// GetLatestExport returns the last export a player requested, or nil if they never requested one
+	GetLatestExport(ctx context.Context, playerID string) (*models.DataExport, error)
+	// UpdateExport saves the status, archive and dates of an export
+	UpdateExport(ctx context.Context, export *models.DataExport) error
+	// GetExpiredExports returns the exports whose archive expired before the given time
+	GetExpiredExports(ctx context.Context, before time.Time) ([]*models.DataExport, error)
+	// DeleteExport removes an export. Removing a missing export does nothing
+	DeleteExport(ctx context.Context, exportID string) error
+}
+
+// InMemoryDataExportRepository implements DataExportRepository using in-memory storage
+type InMemoryDataExportRepository struct {
+	mu      sync.RWMutex
+	exports map[string]*models.DataExport // exportID -> export
+	order   []string                      // exportIDs in creation order
+}
+
+// NewInMemoryDataExportRepository creates a new in-memory data export repository
+func NewInMemoryDataExportRepository() *InMemoryDataExportRepository {
+	return &InMemoryDataExportRepository{
+		exports: make(map[string]*models.DataExport),
+	}
+}
+
+func (r *InMemoryDataExportRepository) CreateExport(ctx context.Context, export *models.DataExport) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	export.ID = uuid.New().String()
+	stored := *export
+	r.exports[export.ID] = &stored
+	r.order = append(r.order, export.ID)
+	return nil
+}
+
+func (r *InMemoryDataExportRepository) GetExport(ctx context.Context, exportID string) (*models.DataExport, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	export, exists := r.exports[exportID]
+	if !exists {
+		return nil, ErrDataExportNotFound
+	}
+	result := *export
+	return &result, nil
+}
+
+func (r *InMemoryDataExportRepository) GetLatestExport(ctx context.Context, playerID string) (*models.DataExport, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for i := len(r.order) - 1; i >= 0; i-- {
+		export, exists := r.exports[r.order[i]]
+		if exists && export.PlayerID == playerID {
+			result := *export
+			return &result, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *InMemoryDataExportRepository) UpdateExport(ctx context.Context, export *models.DataExport) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.exports[export.ID]; !exists {
+		return ErrDataExportNotFound
+	}
+	stored := *export
+	r.exports[export.ID] = &stored
+	return nil
+}
+
+func (r *InMemoryDataExportRepository) GetExpiredExports(ctx context.Context, before time.Time) ([]*models.DataExport, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	expired := make([]*models.DataExport, 0)
+	for _, exportID := range r.order {
+		export, exists := r.exports[exportID]
+		if exists && export.IsExpired(before) {
+			result := *export
+			expired = append(expired, &result)
+		}
+	}
+	return expired, nil
+}
+
+func (r *InMemoryDataExportRepository) DeleteExport(ctx context.Context, exportID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.exports, exportID)
+	for i, id := range r.order {
+		if id == exportID {
+			r.order = append(r.order[:i], r.order[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
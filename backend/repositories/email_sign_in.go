@@ -0,0 +1,115 @@
+package repositories
+
+import (
+	"context"
+	"ligain/backend/models"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EmailSignInRepository stores the codes emailed to sign in, until they expire
+type EmailSignInRepository interface {
+	// CreateSignInCode stores a new code and sets its ID
+	CreateSignInCode(ctx context.Context, code *models.EmailSignInCode) error
+	// GetSignInCodesSince returns the codes sent to the email after the given time, oldest first
+	GetSignInCodesSince(ctx context.Context, email string, since time.Time) ([]*models.EmailSignInCode, error)
+	// GetSignInCodeByLinkHash returns the code sent with the given link token, or nil if there is none
+	GetSignInCodeByLinkHash(ctx context.Context, linkTokenHash string) (*models.EmailSignInCode, error)
+	// IncrementAttempts records a wrong code entered against the code
+	IncrementAttempts(ctx context.Context, codeID string) error
+	// MarkSignInCodeUsed consumes the code. It returns false if the code was already used
+	MarkSignInCodeUsed(ctx context.Context, codeID string, usedAt time.Time) (bool, error)
+	// DeleteExpiredSignInCodes removes the codes expired before the given time
+	DeleteExpiredSignInCodes(ctx context.Context, before time.Time) error
+}
+
+// InMemoryEmailSignInRepository implements EmailSignInRepository using in-memory storage
+type InMemoryEmailSignInRepository struct {
+	mu    sync.RWMutex
+	codes []*models.EmailSignInCode // oldest first
+}
+
+// NewInMemoryEmailSignInRepository creates a new in-memory email sign-in repository
+func NewInMemoryEmailSignInRepository() *InMemoryEmailSignInRepository {
+	return &InMemoryEmailSignInRepository{
+		codes: make([]*models.EmailSignInCode, 0),
+	}
+}
+
+func (r *InMemoryEmailSignInRepository) CreateSignInCode(ctx context.Context, code *models.EmailSignInCode) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	code.ID = uuid.New().String()
+	stored := *code
+	r.codes = append(r.codes, &stored)
+	return nil
+}
+
+func (r *InMemoryEmailSignInRepository) GetSignInCodesSince(ctx context.Context, email string, since time.Time) ([]*models.EmailSignInCode, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	codes := make([]*models.EmailSignInCode, 0)
+	for _, code := range r.codes {
+		if code.Email == email && code.CreatedAt.After(since) {
+			codeCopy := *code
+			codes = append(codes, &codeCopy)
+		}
+	}
+	return codes, nil
+}
+
+func (r *InMemoryEmailSignInRepository) GetSignInCodeByLinkHash(ctx context.Context, linkTokenHash string) (*models.EmailSignInCode, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, code := range r.codes {
+		if code.LinkTokenHash == linkTokenHash {
+			codeCopy := *code
+			return &codeCopy, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *InMemoryEmailSignInRepository) IncrementAttempts(ctx context.Context, codeID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, code := range r.codes {
+		if code.ID == codeID {
+			code.Attempts++
+		}
+	}
+	return nil
+}
+
+func (r *InMemoryEmailSignInRepository) MarkSignInCodeUsed(ctx context.Context, codeID string, usedAt time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, code := range r.codes {
+		if code.ID == codeID && code.UsedAt == nil {
+			code.UsedAt = &usedAt
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *InMemoryEmailSignInRepository) DeleteExpiredSignInCodes(ctx context.Context, before time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	kept := r.codes[:0]
+	for _, code := range r.codes {
+		if code.ExpiresAt.After(before) {
+			kept = append(kept, code)
+		}
+	}
+	r.codes = kept
+	return nil
+}
@@ -1,6 +1,7 @@
 package repositories
 
 import (
+	"context"
 	"fmt"
 	"ligain/backend/models"
 	"strconv"
@@ -17,6 +18,34 @@ type GameRepository interface {
 	SaveWithId(gameId string, game models.Game) error
 	// GetAllGames returns all games in the repository
 	GetAllGames() (map[string]models.Game, error)
+	// SaveGameStatus saves the status of a game within the transaction of ctx, the cached game is left as it is
+	SaveGameStatus(ctx context.Context, gameId string, status models.GameStatus) error
+}
+
+// GameCacheEvicter is implemented by the game repositories keeping the games in memory in front of a database
+type GameCacheEvicter interface {
+	// EvictGame drops the cached state of a game, so that it's loaded again on next access
+	EvictGame(gameId string)
+	// ClearCache drops the cached state of every game
+	ClearCache()
+}
+
+// GameHistoryRecorder is implemented by the game repositories keeping the history of the games, see GameEventRepository.
+// The pending events of a game are recorded each time it's saved
+type GameHistoryRecorder interface {
+	// RecordEvents records the pending events of a game without saving anything else, like after live match updates
+	RecordEvents(ctx context.Context, gameId string, game models.Game) error
+	// RebaseGame loads a game again after it was changed outside of its events, and takes a new snapshot of it.
+	// The history before stays as it was
+	RebaseGame(ctx context.Context, gameId string) error
+}
+
+// GameChangeHandler is told of the changes made to the games in the database, by any instance or script
+type GameChangeHandler interface {
+	// GameChanged is called when the state of a game changed
+	GameChanged(gameId string)
+	// AllGamesChanged is called when changes may have been missed, like while reconnecting to the database
+	AllGamesChanged()
 }
 
 type InMemoryGameRepository struct {
@@ -24,7 +53,7 @@ type InMemoryGameRepository struct {
 	lastId int
 }
 
-func NewInMemoryGameRepository() GameRepository {
+func NewInMemoryGameRepository() *InMemoryGameRepository {
 	return &InMemoryGameRepository{
 		cache:  NewCache[string, models.Game](gameCacheSize),
 		lastId: 1,
@@ -38,7 +67,9 @@ func (r *InMemoryGameRepository) CreateGame(game models.Game) (string, error) {
 	return gameId, nil
 }
 
+// SaveWithId keeps the game in memory. No history is kept, so the pending events of the game are dropped
 func (r *InMemoryGameRepository) SaveWithId(gameId string, game models.Game) error {
+	dropEvents(game)
 	r.cache.Set(gameId, game)
 	return nil
 }
@@ -51,6 +82,16 @@ func (r *InMemoryGameRepository) GetGame(gameId string) (models.Game, error) {
 	return nil, fmt.Errorf("game %s not found", gameId)
 }
 
+// DeleteGame removes a game from the repository
+func (r *InMemoryGameRepository) DeleteGame(gameId string) {
+	r.cache.Delete(gameId)
+}
+
+// Clear removes all the games from the repository
+func (r *InMemoryGameRepository) Clear() {
+	r.cache.Clear()
+}
+
 func (r *InMemoryGameRepository) GetAllGames() (map[string]models.Game, error) {
 	games := make(map[string]models.Game)
 	for _, entry := range r.cache.GetAll() {
@@ -61,3 +102,20 @@ func (r *InMemoryGameRepository) GetAllGames() (map[string]models.Game, error) {
 	}
 	return games, nil
 }
+
+// SaveGameStatus only checks that the game exists, since the status of the games kept in memory is held by the games themselves
+func (r *InMemoryGameRepository) SaveGameStatus(ctx context.Context, gameId string, status models.GameStatus) error {
+	game, err := r.cache.Get(gameId)
+	if err != nil {
+		return fmt.Errorf("game %s not found", gameId)
+	}
+	dropEvents(game)
+	return nil
+}
+
+// dropEvents forgets the pending events of a game, so that they don't pile up when no history is kept
+func dropEvents(game models.Game) {
+	if eventSourced, ok := game.(models.EventSourcedGame); ok {
+		eventSourced.TakeEvents()
+	}
+}
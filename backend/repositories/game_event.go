@@ -0,0 +1,116 @@
+package repositories
+
+import (
+	"context"
+	"ligain/backend/models"
+	"sync"
+	"time"
+)
+
+// GameEventRepository stores the append-only history of the games, and the snapshots of their state
+type GameEventRepository interface {
+	// AppendEvents appends events to the history of a game, oldest first, and sets their game id, sequence and date
+	AppendEvents(ctx context.Context, gameID string, events []*models.GameEvent) error
+	// GetEvents returns the events of a game with a sequence greater than afterSequence, oldest first.
+	// Only the events up to toSequence are returned, unless it's 0. At most limit events are returned, unless it's 0
+	GetEvents(ctx context.Context, gameID string, afterSequence int64, toSequence int64, limit int) ([]*models.GameEvent, error)
+	// GetLastSequence returns the sequence of the last event of a game, 0 when it has none
+	GetLastSequence(ctx context.Context, gameID string) (int64, error)
+	// SaveSnapshot saves the state of a game at a sequence of its history
+	SaveSnapshot(ctx context.Context, snapshot *models.GameSnapshot) error
+	// GetLatestSnapshot returns the latest snapshot of a game taken at or before atSequence, any snapshot when it's 0.
+	// Returns nil when there's none
+	GetLatestSnapshot(ctx context.Context, gameID string, atSequence int64) (*models.GameSnapshot, error)
+}
+
+// InMemoryGameEventRepository implements GameEventRepository using in-memory storage
+type InMemoryGameEventRepository struct {
+	mu        sync.RWMutex
+	events    map[string][]*models.GameEvent    // gameID -> events, oldest first
+	snapshots map[string][]*models.GameSnapshot // gameID -> snapshots, oldest first
+}
+
+// NewInMemoryGameEventRepository creates a new in-memory game event repository
+func NewInMemoryGameEventRepository() *InMemoryGameEventRepository {
+	return &InMemoryGameEventRepository{
+		events:    make(map[string][]*models.GameEvent),
+		snapshots: make(map[string][]*models.GameSnapshot),
+	}
+}
+
+func (r *InMemoryGameEventRepository) AppendEvents(ctx context.Context, gameID string, events []*models.GameEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, event := range events {
+		event.GameID = gameID
+		event.Sequence = int64(len(r.events[gameID])) + 1
+		event.CreatedAt = time.Now()
+		stored := *event
+		r.events[gameID] = append(r.events[gameID], &stored)
+	}
+	return nil
+}
+
+func (r *InMemoryGameEventRepository) GetEvents(ctx context.Context, gameID string, afterSequence int64, toSequence int64, limit int) ([]*models.GameEvent, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]*models.GameEvent, 0)
+	for _, event := range r.events[gameID] {
+		if event.Sequence <= afterSequence || (toSequence > 0 && event.Sequence > toSequence) {
+			continue
+		}
+		if limit > 0 && len(result) >= limit {
+			break
+		}
+		copied := *event
+		result = append(result, &copied)
+	}
+	return result, nil
+}
+
+func (r *InMemoryGameEventRepository) GetLastSequence(ctx context.Context, gameID string) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return int64(len(r.events[gameID])), nil
+}
+
+func (r *InMemoryGameEventRepository) SaveSnapshot(ctx context.Context, snapshot *models.GameSnapshot) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := *snapshot
+	if stored.CreatedAt.IsZero() {
+		stored.CreatedAt = time.Now()
+	}
+	snapshots := r.snapshots[snapshot.GameID]
+	for i, existing := range snapshots {
+		if existing.Sequence == snapshot.Sequence {
+			snapshots[i] = &stored
+			return nil
+		}
+	}
+	r.snapshots[snapshot.GameID] = append(snapshots, &stored)
+	return nil
+}
+
+func (r *InMemoryGameEventRepository) GetLatestSnapshot(ctx context.Context, gameID string, atSequence int64) (*models.GameSnapshot, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var latest *models.GameSnapshot
+	for _, snapshot := range r.snapshots[gameID] {
+		if atSequence > 0 && snapshot.Sequence > atSequence {
+			continue
+		}
+		if latest == nil || snapshot.Sequence > latest.Sequence {
+			latest = snapshot
+		}
+	}
+	if latest == nil {
+		return nil, nil
+	}
+	copied := *latest
+	return &copied, nil
+}
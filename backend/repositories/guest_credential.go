@@ -0,0 +1,43 @@
+package repositories
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// GuestCredentialRepository stores the hash of the secret guests present to sign in again
+type GuestCredentialRepository interface {
+	// GetGuestSecretHash returns the secret hash of a guest, or an empty hash for a guest created before secrets existed
+	GetGuestSecretHash(ctx context.Context, playerID string) (string, error)
+	// SaveGuestSecretHash sets the secret hash of a guest, replacing the previous one
+	SaveGuestSecretHash(ctx context.Context, playerID string, secretHash string, createdAt time.Time) error
+}
+
+// InMemoryGuestCredentialRepository implements GuestCredentialRepository using in-memory storage
+type InMemoryGuestCredentialRepository struct {
+	mu     sync.RWMutex
+	hashes map[string]string // playerID -> secret hash
+}
+
+// NewInMemoryGuestCredentialRepository creates a new in-memory guest credential repository
+func NewInMemoryGuestCredentialRepository() *InMemoryGuestCredentialRepository {
+	return &InMemoryGuestCredentialRepository{
+		hashes: make(map[string]string),
+	}
+}
+
+func (r *InMemoryGuestCredentialRepository) GetGuestSecretHash(ctx context.Context, playerID string) (string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.hashes[playerID], nil
+}
+
+func (r *InMemoryGuestCredentialRepository) SaveGuestSecretHash(ctx context.Context, playerID string, secretHash string, createdAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.hashes[playerID] = secretHash
+	return nil
+}
@@ -0,0 +1,73 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrLeadershipLost is returned by Lease.Check once another instance may have become the leader
+var ErrLeadershipLost = errors.New("leadership lost")
+
+// LeaderLock elects the single instance doing a work among the replicas of the backend
+type LeaderLock interface {
+	// TryAcquire takes the lock when no other instance holds it. The lock is held until the lease is
+	// released, or until the instance holding it dies or loses its connection to the database
+	TryAcquire(ctx context.Context) (Lease, bool, error)
+}
+
+// Lease is a held LeaderLock
+type Lease interface {
+	// Check returns an error once the lock may be held by another instance
+	Check(ctx context.Context) error
+	// Release gives the lock back so that another instance can take it
+	Release()
+}
+
+// InMemoryLeaderLock is a LeaderLock shared by the instances of a single process
+type InMemoryLeaderLock struct {
+	mu    sync.Mutex
+	lease *inMemoryLease
+}
+
+func NewInMemoryLeaderLock() *InMemoryLeaderLock {
+	return &InMemoryLeaderLock{}
+}
+
+func (l *InMemoryLeaderLock) TryAcquire(ctx context.Context) (Lease, bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.lease != nil {
+		return nil, false, nil
+	}
+	l.lease = &inMemoryLease{lock: l}
+	return l.lease, true, nil
+}
+
+// Revoke takes the lock away from its holder, as if it had lost its connection to the database
+func (l *InMemoryLeaderLock) Revoke() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.lease = nil
+}
+
+type inMemoryLease struct {
+	lock *InMemoryLeaderLock
+}
+
+func (l *inMemoryLease) Check(ctx context.Context) error {
+	l.lock.mu.Lock()
+	defer l.lock.mu.Unlock()
+	if l.lock.lease != l {
+		return ErrLeadershipLost
+	}
+	return nil
+}
+
+func (l *inMemoryLease) Release() {
+	l.lock.mu.Lock()
+	defer l.lock.mu.Unlock()
+	if l.lock.lease == l {
+		l.lock.lease = nil
+	}
+}
@@ -37,6 +37,8 @@ type PlayerRepository interface {
 	UpdateAvatarSignedURL(ctx context.Context, playerID string, signedURL string, expiresAt time.Time) error
 	// ClearAvatar removes all avatar fields
 	ClearAvatar(ctx context.Context, playerID string) error
+	// GetPlayersWithAvatarURLExpiringBefore returns the players with an avatar whose signed URL expires before the given time
+	GetPlayersWithAvatarURLExpiringBefore(ctx context.Context, before time.Time) ([]*models.PlayerData, error)
 }
 
 // InMemoryPlayerRepository is a simple in-memory implementation of PlayerRepository
@@ -193,6 +195,20 @@ func (r *InMemoryPlayerRepository) UpdateAvatarSignedURL(ctx context.Context, pl
 	return nil
 }
 
+func (r *InMemoryPlayerRepository) GetPlayersWithAvatarURLExpiringBefore(ctx context.Context, before time.Time) ([]*models.PlayerData, error) {
+	players := make([]*models.PlayerData, 0)
+	for _, player := range r.players {
+		playerData, ok := player.(*models.PlayerData)
+		if !ok || playerData.AvatarObjectKey == nil {
+			continue
+		}
+		if playerData.AvatarSignedURLExpiresAt == nil || playerData.AvatarSignedURLExpiresAt.Before(before) {
+			players = append(players, playerData)
+		}
+	}
+	return players, nil
+}
+
 func (r *InMemoryPlayerRepository) ClearAvatar(ctx context.Context, playerID string) error {
 	player, exists := r.players[playerID]
 	if !exists {
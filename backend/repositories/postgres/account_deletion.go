@@ -0,0 +1,196 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"ligain/backend/models"
+	"ligain/backend/repositories"
+	"time"
+)
+
+type PostgresAccountDeletionRepository struct {
+	db *sql.DB
+}
+
+// executor returns the appropriate DBExecutor (transaction or db connection).
+func (r *PostgresAccountDeletionRepository) executor(ctx context.Context) DBExecutor {
+	if tx := TxFromContext(ctx); tx != nil {
+		return tx
+	}
+	return r.db
+}
+
+func NewPostgresAccountDeletionRepository(db *sql.DB) repositories.AccountDeletionRepository {
+	return &PostgresAccountDeletionRepository{db: db}
+}
+
+// accountDeletionColumns are the columns read by scanAccountDeletion, in order
+const accountDeletionColumns = `player_id, original_name, avatar_object_key, deleted_at, purge_after`
+
+func (r *PostgresAccountDeletionRepository) CreateDeletion(ctx context.Context, deletion *models.AccountDeletion) error {
+	query := `
+		INSERT INTO player_deletion (player_id, original_name, avatar_object_key, deleted_at, purge_after)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	_, err := r.executor(ctx).ExecContext(ctx, query,
+		deletion.PlayerID,
+		deletion.OriginalName,
+		deletion.AvatarObjectKey,
+		deletion.DeletedAt,
+		deletion.PurgeAfter,
+	)
+	if err != nil {
+		return fmt.Errorf("error creating account deletion: %v", err)
+	}
+
+	return nil
+}
+
+func (r *PostgresAccountDeletionRepository) GetDeletion(ctx context.Context, playerID string) (*models.AccountDeletion, error) {
+	query := `SELECT ` + accountDeletionColumns + ` FROM player_deletion WHERE player_id = $1`
+
+	deletion, err := scanAccountDeletion(r.executor(ctx).QueryRowContext(ctx, query, playerID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting account deletion: %v", err)
+	}
+
+	return deletion, nil
+}
+
+func (r *PostgresAccountDeletionRepository) GetDeletionByOriginalName(ctx context.Context, name string) (*models.AccountDeletion, error) {
+	query := `SELECT ` + accountDeletionColumns + ` FROM player_deletion WHERE original_name = $1 ORDER BY deleted_at DESC LIMIT 1`
+
+	deletion, err := scanAccountDeletion(r.executor(ctx).QueryRowContext(ctx, query, name))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting account deletion by name: %v", err)
+	}
+
+	return deletion, nil
+}
+
+func (r *PostgresAccountDeletionRepository) DeleteDeletion(ctx context.Context, playerID string) error {
+	if _, err := r.executor(ctx).ExecContext(ctx, `DELETE FROM player_deletion WHERE player_id = $1`, playerID); err != nil {
+		return fmt.Errorf("error deleting account deletion: %v", err)
+	}
+	return nil
+}
+
+func (r *PostgresAccountDeletionRepository) GetDeletionsToPurge(ctx context.Context, now time.Time) ([]*models.AccountDeletion, error) {
+	query := `SELECT ` + accountDeletionColumns + ` FROM player_deletion WHERE purge_after <= $1 ORDER BY purge_after`
+
+	rows, err := r.executor(ctx).QueryContext(ctx, query, now)
+	if err != nil {
+		return nil, fmt.Errorf("error getting account deletions to purge: %v", err)
+	}
+	defer rows.Close()
+
+	deletions := make([]*models.AccountDeletion, 0)
+	for rows.Next() {
+		deletion, err := scanAccountDeletion(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning account deletion: %v", err)
+		}
+		deletions = append(deletions, deletion)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating account deletions: %v", err)
+	}
+
+	return deletions, nil
+}
+
+func (r *PostgresAccountDeletionRepository) PurgePlayer(ctx context.Context, playerID string) ([]string, error) {
+	exportKeys, err := r.deleteDataExports(ctx, playerID)
+	if err != nil {
+		return nil, err
+	}
+
+	statements := []string{
+		`DELETE FROM match_reaction WHERE player_id = $1`,
+		`DELETE FROM match_comment WHERE player_id = $1`,
+		`DELETE FROM player_achievement WHERE player_id = $1`,
+		`DELETE FROM player_rating WHERE player_id = $1`,
+		`DELETE FROM player_rating_change WHERE player_id = $1`,
+		`DELETE FROM refresh_token WHERE player_id = $1`,
+		`DELETE FROM auth_tokens WHERE player_id = $1`,
+		`DELETE FROM guest_credential WHERE player_id = $1`,
+		// The bet history stays as the proof of the bets, without the device it was sent from
+		`UPDATE bet_history SET device_name = NULL, ip_address = NULL WHERE player_id = $1`,
+		// The identities of the accounts merged into the player would lead to the anonymized player otherwise
+		`DELETE FROM player_merge WHERE target_player_id = $1`,
+		`UPDATE player
+		SET email = NULL, provider = NULL, provider_id = NULL,
+			avatar_object_key = NULL, avatar_signed_url = NULL, avatar_signed_url_expires_at = NULL,
+			updated_at = NOW()
+		WHERE id = $1`,
+		`DELETE FROM player_deletion WHERE player_id = $1`,
+	}
+	for _, statement := range statements {
+		if _, err := r.executor(ctx).ExecContext(ctx, statement, playerID); err != nil {
+			return nil, fmt.Errorf("error purging player: %v", err)
+		}
+	}
+
+	// The activity feed keeps the names of the players, so that it still makes sense after they leave
+	if _, err := r.executor(ctx).ExecContext(ctx,
+		`UPDATE game_activity SET player_name = $2 WHERE player_id = $1`,
+		playerID, models.DeletedPlayerName,
+	); err != nil {
+		return nil, fmt.Errorf("error anonymizing player activity: %v", err)
+	}
+
+	return exportKeys, nil
+}
+
+// deleteDataExports deletes the data exports of a player, and returns the object keys of their archives
+func (r *PostgresAccountDeletionRepository) deleteDataExports(ctx context.Context, playerID string) ([]string, error) {
+	rows, err := r.executor(ctx).QueryContext(ctx,
+		`DELETE FROM data_export WHERE player_id = $1 RETURNING object_key`,
+		playerID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error deleting data exports: %v", err)
+	}
+	defer rows.Close()
+
+	keys := make([]string, 0)
+	for rows.Next() {
+		var key sql.NullString
+		if err := rows.Scan(&key); err != nil {
+			return nil, fmt.Errorf("error scanning data export: %v", err)
+		}
+		if key.Valid {
+			keys = append(keys, key.String)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating data exports: %v", err)
+	}
+
+	return keys, nil
+}
+
+// scanAccountDeletion reads a row selected with accountDeletionColumns
+func scanAccountDeletion(row interface{ Scan(dest ...any) error }) (*models.AccountDeletion, error) {
+	var deletion models.AccountDeletion
+	if err := row.Scan(
+		&deletion.PlayerID,
+		&deletion.OriginalName,
+		&deletion.AvatarObjectKey,
+		&deletion.DeletedAt,
+		&deletion.PurgeAfter,
+	); err != nil {
+		return nil, err
+	}
+
+	return &deletion, nil
+}
@@ -0,0 +1,136 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"ligain/backend/models"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountDeletionRepository_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	runTestWithTimeout(t, func(t *testing.T) {
+		testDB := setupTestDB(t)
+		defer testDB.Close()
+
+		deletionRepo := NewPostgresAccountDeletionRepository(testDB.db)
+		playerRepo := NewPostgresPlayerRepository(testDB.db)
+		ctx := context.Background()
+		now := time.Now().UTC().Truncate(time.Second)
+
+		player := &models.PlayerData{
+			Name:       models.DeletedPlayerName,
+			Email:      stringPtr("leaver@example.com"),
+			Provider:   stringPtr("google"),
+			ProviderID: stringPtr("google-leaver"),
+		}
+		require.NoError(t, playerRepo.CreatePlayer(ctx, player))
+
+		gameID := createTestGame(t, testDB.db)
+		matchID := createTestMatch(t, testDB.db)
+		_, err := testDB.db.Exec(`INSERT INTO game_player (game_id, player_id) VALUES ($1, $2)`, gameID, player.ID)
+		require.NoError(t, err)
+		_, err = testDB.db.Exec(`
+			INSERT INTO bet (game_id, match_id, player_id, predicted_home_goals, predicted_away_goals)
+			VALUES ($1, $2, $3, 2, 1)
+		`, gameID, matchID, player.ID)
+		require.NoError(t, err)
+		_, err = testDB.db.Exec(`INSERT INTO score (game_id, match_id, player_id, points) VALUES ($1, $2, $3, 500)`, gameID, matchID, player.ID)
+		require.NoError(t, err)
+		_, err = testDB.db.Exec(`
+			INSERT INTO match_comment (game_id, match_local_id, player_id, content)
+			VALUES ($1, 'match1', $2, 'See you')
+		`, gameID, player.ID)
+		require.NoError(t, err)
+		_, err = testDB.db.Exec(`
+			INSERT INTO game_activity (game_id, type, player_id, player_name)
+			VALUES ($1, 'player_joined', $2, 'Leaver')
+		`, gameID, player.ID)
+		require.NoError(t, err)
+
+		exportKey := "exports/leaver/export.zip"
+		exportRepo := NewPostgresDataExportRepository(testDB.db)
+		require.NoError(t, exportRepo.CreateExport(ctx, &models.DataExport{
+			PlayerID:  player.ID,
+			Status:    models.DataExportReady,
+			ObjectKey: exportKey,
+			CreatedAt: now,
+		}))
+		require.NoError(t, exportRepo.CreateExport(ctx, &models.DataExport{
+			PlayerID:  player.ID,
+			Status:    models.DataExportFailed,
+			CreatedAt: now,
+		}))
+
+		avatarKey := "avatars/leaver/avatar.webp"
+		deletion := &models.AccountDeletion{
+			PlayerID:        player.ID,
+			OriginalName:    "Leaver",
+			AvatarObjectKey: &avatarKey,
+			DeletedAt:       now,
+			PurgeAfter:      now.Add(time.Hour),
+		}
+		require.NoError(t, deletionRepo.CreateDeletion(ctx, deletion))
+
+		t.Run("Lookups", func(t *testing.T) {
+			stored, err := deletionRepo.GetDeletion(ctx, player.ID)
+			require.NoError(t, err)
+			require.NotNil(t, stored)
+			require.Equal(t, "Leaver", stored.OriginalName)
+			require.Equal(t, avatarKey, *stored.AvatarObjectKey)
+			require.True(t, now.Add(time.Hour).Equal(stored.PurgeAfter))
+
+			byName, err := deletionRepo.GetDeletionByOriginalName(ctx, "Leaver")
+			require.NoError(t, err)
+			require.NotNil(t, byName)
+			require.Equal(t, player.ID, byName.PlayerID)
+
+			toPurge, err := deletionRepo.GetDeletionsToPurge(ctx, now)
+			require.NoError(t, err)
+			require.Empty(t, toPurge)
+			toPurge, err = deletionRepo.GetDeletionsToPurge(ctx, now.Add(time.Hour))
+			require.NoError(t, err)
+			require.Len(t, toPurge, 1)
+		})
+
+		t.Run("Purge Keeps Bets And Scores", func(t *testing.T) {
+			exportKeys, err := deletionRepo.PurgePlayer(ctx, player.ID)
+			require.NoError(t, err)
+			require.Equal(t, []string{exportKey}, exportKeys)
+
+			stored, err := deletionRepo.GetDeletion(ctx, player.ID)
+			require.NoError(t, err)
+			require.Nil(t, stored)
+
+			purged, err := playerRepo.GetPlayerByID(ctx, player.ID)
+			require.NoError(t, err)
+			require.NotNil(t, purged)
+			require.Equal(t, models.DeletedPlayerName, purged.Name)
+			require.Nil(t, purged.Email)
+			require.Nil(t, purged.Provider)
+			require.Nil(t, purged.ProviderID)
+
+			var count int
+			require.NoError(t, testDB.db.QueryRow(`SELECT COUNT(*) FROM bet WHERE player_id = $1`, player.ID).Scan(&count))
+			require.Equal(t, 1, count)
+			require.NoError(t, testDB.db.QueryRow(`SELECT COUNT(*) FROM score WHERE player_id = $1`, player.ID).Scan(&count))
+			require.Equal(t, 1, count)
+			require.NoError(t, testDB.db.QueryRow(`SELECT COUNT(*) FROM game_player WHERE player_id = $1`, player.ID).Scan(&count))
+			require.Equal(t, 1, count)
+			require.NoError(t, testDB.db.QueryRow(`SELECT COUNT(*) FROM match_comment WHERE player_id = $1`, player.ID).Scan(&count))
+			require.Equal(t, 0, count)
+			require.NoError(t, testDB.db.QueryRow(`SELECT COUNT(*) FROM data_export WHERE player_id = $1`, player.ID).Scan(&count))
+			require.Equal(t, 0, count)
+
+			var activityName string
+			require.NoError(t, testDB.db.QueryRow(`SELECT player_name FROM game_activity WHERE player_id = $1`, player.ID).Scan(&activityName))
+			require.Equal(t, models.DeletedPlayerName, activityName)
+		})
+	}, 30*time.Second)
+}
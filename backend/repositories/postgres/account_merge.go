@@ -0,0 +1,261 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"ligain/backend/models"
+	"ligain/backend/repositories"
+	"time"
+)
+
+type PostgresAccountMergeRepository struct {
+	db *sql.DB
+}
+
+// executor returns the appropriate DBExecutor (transaction or db connection).
+func (r *PostgresAccountMergeRepository) executor(ctx context.Context) DBExecutor {
+	if tx := TxFromContext(ctx); tx != nil {
+		return tx
+	}
+	return r.db
+}
+
+func NewPostgresAccountMergeRepository(db *sql.DB) repositories.AccountMergeRepository {
+	return &PostgresAccountMergeRepository{db: db}
+}
+
+func (r *PostgresAccountMergeRepository) MergeBets(ctx context.Context, sourceID, targetID string) (int, []models.BetConflict, error) {
+	conflicts, losingBetIDs, err := r.getBetConflicts(ctx, sourceID, targetID)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if len(losingBetIDs) > 0 {
+		// The scores reference the bets, so they go first
+		if _, err := r.executor(ctx).ExecContext(ctx, `
+			DELETE FROM score sc
+			USING bet b
+			WHERE b.id = ANY($1)
+			AND sc.game_id = b.game_id AND sc.match_id = b.match_id AND sc.player_id = b.player_id
+		`, losingBetIDs); err != nil {
+			return 0, nil, fmt.Errorf("error deleting scores of conflicting bets: %v", err)
+		}
+		if _, err := r.executor(ctx).ExecContext(ctx, `DELETE FROM bet WHERE id = ANY($1)`, losingBetIDs); err != nil {
+			return 0, nil, fmt.Errorf("error deleting conflicting bets: %v", err)
+		}
+	}
+
+	// Scores can exist without a bet, a score backed by a bet wins over one that isn't, the target's one otherwise
+	err = r.execAll(ctx, "error merging scores", sourceID, targetID,
+		`DELETE FROM score t
+		WHERE t.player_id = $2 AND t.bet_id IS NULL
+		AND EXISTS (
+			SELECT 1 FROM score s
+			WHERE s.player_id = $1 AND s.game_id = t.game_id AND s.match_id = t.match_id AND s.bet_id IS NOT NULL
+		)`,
+		`DELETE FROM score s
+		WHERE s.player_id = $1
+		AND EXISTS (
+			SELECT 1 FROM score t
+			WHERE t.player_id = $2 AND t.game_id = s.game_id AND t.match_id = s.match_id
+		)`,
+	)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	result, err := r.executor(ctx).ExecContext(ctx, `UPDATE bet SET player_id = $2 WHERE player_id = $1`, sourceID, targetID)
+	if err != nil {
+		return 0, nil, fmt.Errorf("error moving bets: %v", err)
+	}
+	movedBets, err := result.RowsAffected()
+	if err != nil {
+		return 0, nil, fmt.Errorf("error moving bets: %v", err)
+	}
+
+	if _, err := r.executor(ctx).ExecContext(ctx, `UPDATE score SET player_id = $2 WHERE player_id = $1`, sourceID, targetID); err != nil {
+		return 0, nil, fmt.Errorf("error moving scores: %v", err)
+	}
+	// The history of the dropped bets is kept, it's the one of the merged account
+	if _, err := r.executor(ctx).ExecContext(ctx, `UPDATE bet_history SET player_id = $2 WHERE player_id = $1`, sourceID, targetID); err != nil {
+		return 0, nil, fmt.Errorf("error moving bet history: %v", err)
+	}
+
+	return int(movedBets), conflicts, nil
+}
+
+// getBetConflicts returns the matches both players bet on in the same game, and the ids of the bets to drop
+func (r *PostgresAccountMergeRepository) getBetConflicts(ctx context.Context, sourceID, targetID string) ([]models.BetConflict, []string, error) {
+	query := `
+		SELECT s.game_id, m.local_id, s.id, t.id,
+			COALESCE(s.updated_at, s.created_at) > COALESCE(t.updated_at, t.created_at)
+		FROM bet s
+		JOIN bet t ON t.game_id = s.game_id AND t.match_id = s.match_id AND t.player_id = $2
+		JOIN match m ON m.id = s.match_id
+		WHERE s.player_id = $1
+		ORDER BY s.game_id, m.match_date
+	`
+
+	rows, err := r.executor(ctx).QueryContext(ctx, query, sourceID, targetID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error getting conflicting bets: %v", err)
+	}
+	defer rows.Close()
+
+	conflicts := []models.BetConflict{}
+	var losingBetIDs []string
+	for rows.Next() {
+		var conflict models.BetConflict
+		var sourceBetID, targetBetID string
+		if err := rows.Scan(&conflict.GameID, &conflict.MatchID, &sourceBetID, &targetBetID, &conflict.KeptMergedBet); err != nil {
+			return nil, nil, fmt.Errorf("error scanning conflicting bet: %v", err)
+		}
+		conflicts = append(conflicts, conflict)
+		if conflict.KeptMergedBet {
+			losingBetIDs = append(losingBetIDs, targetBetID)
+		} else {
+			losingBetIDs = append(losingBetIDs, sourceBetID)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("error iterating conflicting bets: %v", err)
+	}
+
+	return conflicts, losingBetIDs, nil
+}
+
+func (r *PostgresAccountMergeRepository) MergeGameData(ctx context.Context, sourceID, targetID string) ([]string, error) {
+	rows, err := r.executor(ctx).QueryContext(ctx, `SELECT game_id FROM game_player WHERE player_id = $1 ORDER BY created_at`, sourceID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting games of merged player: %v", err)
+	}
+	defer rows.Close()
+
+	gameIDs := []string{}
+	for rows.Next() {
+		var gameID string
+		if err := rows.Scan(&gameID); err != nil {
+			return nil, fmt.Errorf("error scanning game of merged player: %v", err)
+		}
+		gameIDs = append(gameIDs, gameID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating games of merged player: %v", err)
+	}
+
+	// Rows the target player already has are dropped instead of moved, so that the unique constraints hold
+	err = r.execAll(ctx, "error merging game data", sourceID, targetID,
+		`DELETE FROM game_player s
+		WHERE s.player_id = $1
+		AND EXISTS (SELECT 1 FROM game_player t WHERE t.player_id = $2 AND t.game_id = s.game_id)`,
+		`UPDATE game_player SET player_id = $2, updated_at = NOW() WHERE player_id = $1`,
+		`DELETE FROM player_achievement s
+		WHERE s.player_id = $1
+		AND EXISTS (SELECT 1 FROM player_achievement t WHERE t.player_id = $2 AND t.game_id = s.game_id AND t.code = s.code)`,
+		`UPDATE player_achievement SET player_id = $2 WHERE player_id = $1`,
+		`UPDATE match_comment SET player_id = $2 WHERE player_id = $1`,
+		`DELETE FROM match_reaction s
+		WHERE s.player_id = $1
+		AND EXISTS (
+			SELECT 1 FROM match_reaction t
+			WHERE t.player_id = $2 AND t.game_id = s.game_id AND t.match_local_id = s.match_local_id AND t.emoji = s.emoji
+		)`,
+		`UPDATE match_reaction SET player_id = $2 WHERE player_id = $1`,
+		// The feed keeps the name the player had, only the link to the player moves
+		`UPDATE game_activity SET player_id = $2 WHERE player_id = $1`,
+		// The rating backed by the most matches is kept
+		`DELETE FROM player_rating t
+		WHERE t.player_id = $2
+		AND EXISTS (SELECT 1 FROM player_rating s WHERE s.player_id = $1 AND s.matches_rated > t.matches_rated)`,
+		`DELETE FROM player_rating s
+		WHERE s.player_id = $1
+		AND EXISTS (SELECT 1 FROM player_rating t WHERE t.player_id = $2)`,
+		`UPDATE player_rating SET player_id = $2 WHERE player_id = $1`,
+		`DELETE FROM player_rating_change s
+		WHERE s.player_id = $1
+		AND EXISTS (
+			SELECT 1 FROM player_rating_change t
+			WHERE t.player_id = $2 AND t.game_id = s.game_id AND t.match_local_id = s.match_local_id
+		)`,
+		`UPDATE player_rating_change SET player_id = $2 WHERE player_id = $1`,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return gameIDs, nil
+}
+
+func (r *PostgresAccountMergeRepository) MoveSessions(ctx context.Context, sourceID, targetID string) error {
+	return r.execAll(ctx, "error moving sessions", sourceID, targetID,
+		`UPDATE refresh_token SET player_id = $2 WHERE player_id = $1`,
+		`UPDATE auth_tokens SET player_id = $2 WHERE player_id = $1`,
+	)
+}
+
+func (r *PostgresAccountMergeRepository) CompleteMerge(ctx context.Context, source, target *models.PlayerData, mergedAt time.Time) error {
+	// The OAuth identity of the source moves to the target when it's a guest, otherwise it's kept with the merge record
+	var mergedProvider, mergedProviderID *string
+	if target.Provider != nil {
+		mergedProvider, mergedProviderID = source.Provider, source.ProviderID
+	}
+
+	err := r.execAll(ctx, "error recording merge", source.ID, target.ID,
+		`UPDATE player_merge SET target_player_id = $2 WHERE target_player_id = $1`,
+	)
+	if err != nil {
+		return err
+	}
+	_, err = r.executor(ctx).ExecContext(ctx, `
+		INSERT INTO player_merge (source_player_id, target_player_id, provider, provider_id, merged_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`, source.ID, target.ID, mergedProvider, mergedProviderID, mergedAt)
+	if err != nil {
+		return fmt.Errorf("error recording merge: %v", err)
+	}
+
+	// The source goes first, so that its email and identity are free to move
+	result, err := r.executor(ctx).ExecContext(ctx, `DELETE FROM player WHERE id = $1`, source.ID)
+	if err != nil {
+		return fmt.Errorf("error deleting merged player: %v", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error deleting merged player: %v", err)
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	query := `
+		UPDATE player
+		SET email = COALESCE(email, $2),
+			provider_id = CASE WHEN provider IS NULL THEN $4 ELSE provider_id END,
+			provider = COALESCE(provider, $3),
+			avatar_signed_url = CASE WHEN avatar_object_key IS NULL THEN $6 ELSE avatar_signed_url END,
+			avatar_signed_url_expires_at = CASE WHEN avatar_object_key IS NULL THEN $7 ELSE avatar_signed_url_expires_at END,
+			avatar_object_key = COALESCE(avatar_object_key, $5),
+			updated_at = $8
+		WHERE id = $1
+	`
+	_, err = r.executor(ctx).ExecContext(ctx, query, target.ID,
+		source.Email, source.Provider, source.ProviderID,
+		source.AvatarObjectKey, source.AvatarSignedURL, source.AvatarSignedURLExpiresAt,
+		mergedAt)
+	if err != nil {
+		return fmt.Errorf("error updating kept player: %v", err)
+	}
+
+	return nil
+}
+
+// execAll runs the statements in order with the source and target player ids as arguments
+func (r *PostgresAccountMergeRepository) execAll(ctx context.Context, errorMessage string, sourceID, targetID string, statements ...string) error {
+	for _, statement := range statements {
+		if _, err := r.executor(ctx).ExecContext(ctx, statement, sourceID, targetID); err != nil {
+			return fmt.Errorf("%s: %v", errorMessage, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"ligain/backend/models"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountMergeRepository_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	runTestWithTimeout(t, func(t *testing.T) {
+		testDB := setupTestDB(t)
+		defer testDB.Close()
+
+		mergeRepo := NewPostgresAccountMergeRepository(testDB.db)
+		playerRepo := NewPostgresPlayerRepository(testDB.db)
+		uow := NewUnitOfWork(testDB.db)
+		ctx := context.Background()
+
+		exec := func(query string, args ...interface{}) {
+			_, err := testDB.db.Exec(query, args...)
+			require.NoError(t, err)
+		}
+
+		sourceID := "123e4567-e89b-12d3-a456-426614174601"
+		targetID := "123e4567-e89b-12d3-a456-426614174602"
+		gameID := "123e4567-e89b-12d3-a456-426614174603"
+		otherGameID := "123e4567-e89b-12d3-a456-426614174604"
+		firstMatchID := "123e4567-e89b-12d3-a456-426614174605"
+		secondMatchID := "123e4567-e89b-12d3-a456-426614174606"
+
+		exec(`INSERT INTO player (id, name, provider, provider_id, email, avatar_object_key) VALUES ($1, 'Apple Me', 'apple', 'apple_1', 'me@example.com', 'avatars/source.jpg')`, sourceID)
+		exec(`INSERT INTO player (id, name, provider, provider_id) VALUES ($1, 'Google Me', 'google', 'google_1')`, targetID)
+		exec(`INSERT INTO game (id, season_year, competition_name, status, game_name) VALUES ($1, '2025/2026', 'Ligue 1', 'scheduled', 'Shared'), ($2, '2025/2026', 'Ligue 1', 'scheduled', 'Other')`, gameID, otherGameID)
+		exec(`INSERT INTO match (id, local_id, home_team_id, away_team_id, match_date, match_status, season_code, competition_code, matchday)
+			VALUES ($1, 'match-1', 'PSG', 'Lyon', NOW(), 'finished', '2025/2026', 'Ligue 1', 1),
+			($2, 'match-2', 'Nice', 'Lens', NOW(), 'finished', '2025/2026', 'Ligue 1', 1)`, firstMatchID, secondMatchID)
+		exec(`INSERT INTO game_player (game_id, player_id) VALUES ($1, $3), ($2, $3), ($1, $4)`, gameID, otherGameID, sourceID, targetID)
+
+		// Both bet on the first match of the shared game, the source's bet is the latest one
+		now := time.Now().UTC()
+		exec(`INSERT INTO bet (id, game_id, match_id, player_id, predicted_home_goals, predicted_away_goals, updated_at) VALUES
+			('123e4567-e89b-12d3-a456-426614174611', $1, $3, $4, 2, 1, $6),
+			('123e4567-e89b-12d3-a456-426614174612', $1, $3, $5, 0, 0, $7),
+			('123e4567-e89b-12d3-a456-426614174613', $2, $3, $4, 1, 1, $6),
+			('123e4567-e89b-12d3-a456-426614174614', $1, $8, $4, 3, 0, $6)`,
+			gameID, otherGameID, firstMatchID, sourceID, targetID, now, now.Add(-time.Hour), secondMatchID)
+		exec(`INSERT INTO score (game_id, match_id, player_id, bet_id, points) VALUES
+			($1, $2, $3, '123e4567-e89b-12d3-a456-426614174611', 500),
+			($1, $2, $4, '123e4567-e89b-12d3-a456-426614174612', 0)`, gameID, firstMatchID, sourceID, targetID)
+		exec(`INSERT INTO refresh_token (player_id, family_id, token_hash, expires_at) VALUES ($1, '123e4567-e89b-12d3-a456-426614174621', 'hash-source', $2)`, sourceID, now.Add(time.Hour))
+
+		source, err := playerRepo.GetPlayerByID(ctx, sourceID)
+		require.NoError(t, err)
+		target, err := playerRepo.GetPlayerByID(ctx, targetID)
+		require.NoError(t, err)
+
+		// A dry run sees the merge but leaves nothing behind
+		var dryRunBets int
+		err = NewDryRunUnitOfWork(testDB.db).WithinTx(ctx, func(txCtx context.Context) error {
+			var err error
+			dryRunBets, _, err = mergeRepo.MergeBets(txCtx, sourceID, targetID)
+			return err
+		})
+		require.NoError(t, err)
+		require.Equal(t, 3, dryRunBets)
+		var sourceBets int
+		require.NoError(t, testDB.db.QueryRow(`SELECT COUNT(*) FROM bet WHERE player_id = $1`, sourceID).Scan(&sourceBets))
+		require.Equal(t, 3, sourceBets)
+
+		var movedBets int
+		var conflicts []models.BetConflict
+		var gameIDs []string
+		err = uow.WithinTx(ctx, func(txCtx context.Context) error {
+			var err error
+			if movedBets, conflicts, err = mergeRepo.MergeBets(txCtx, sourceID, targetID); err != nil {
+				return err
+			}
+			if gameIDs, err = mergeRepo.MergeGameData(txCtx, sourceID, targetID); err != nil {
+				return err
+			}
+			if err := mergeRepo.MoveSessions(txCtx, sourceID, targetID); err != nil {
+				return err
+			}
+			return mergeRepo.CompleteMerge(txCtx, source, target, now)
+		})
+		require.NoError(t, err)
+
+		t.Run("Bets and Scores", func(t *testing.T) {
+			require.Equal(t, 3, movedBets)
+			require.Equal(t, []models.BetConflict{{GameID: gameID, MatchID: "match-1", KeptMergedBet: true}}, conflicts)
+
+			var home, points int
+			err := testDB.db.QueryRow(`
+				SELECT b.predicted_home_goals, s.points FROM bet b
+				JOIN score s ON s.bet_id = b.id
+				WHERE b.game_id = $1 AND b.match_id = $2 AND b.player_id = $3`, gameID, firstMatchID, targetID).Scan(&home, &points)
+			require.NoError(t, err)
+			require.Equal(t, 2, home)
+			require.Equal(t, 500, points)
+
+			var remaining int
+			require.NoError(t, testDB.db.QueryRow(`SELECT COUNT(*) FROM bet WHERE player_id = $1`, targetID).Scan(&remaining))
+			require.Equal(t, 3, remaining)
+		})
+
+		t.Run("Memberships and Sessions", func(t *testing.T) {
+			require.ElementsMatch(t, []string{gameID, otherGameID}, gameIDs)
+
+			var memberships int
+			require.NoError(t, testDB.db.QueryRow(`SELECT COUNT(*) FROM game_player WHERE player_id = $1`, targetID).Scan(&memberships))
+			require.Equal(t, 2, memberships)
+
+			var owner string
+			require.NoError(t, testDB.db.QueryRow(`SELECT player_id FROM refresh_token WHERE token_hash = 'hash-source'`).Scan(&owner))
+			require.Equal(t, targetID, owner)
+		})
+
+		t.Run("Profile and Identity", func(t *testing.T) {
+			merged, err := playerRepo.GetPlayerByID(ctx, sourceID)
+			require.Error(t, err)
+			require.Nil(t, merged)
+
+			kept, err := playerRepo.GetPlayerByID(ctx, targetID)
+			require.NoError(t, err)
+			require.Equal(t, "Google Me", kept.Name)
+			require.Equal(t, "google", *kept.Provider)
+			require.Equal(t, "me@example.com", *kept.Email)
+			require.Equal(t, "avatars/source.jpg", *kept.AvatarObjectKey)
+
+			// Signing in with Apple leads to the kept account
+			byApple, err := playerRepo.GetPlayerByProvider(ctx, "apple", "apple_1")
+			require.NoError(t, err)
+			require.NotNil(t, byApple)
+			require.Equal(t, targetID, byApple.ID)
+		})
+	}, 30*time.Second)
+}
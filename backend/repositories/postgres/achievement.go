@@ -0,0 +1,110 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"ligain/backend/models"
+	"ligain/backend/repositories"
+)
+
+type PostgresAchievementRepository struct {
+	db *sql.DB
+}
+
+// executor returns the appropriate DBExecutor (transaction or db connection).
+func (r *PostgresAchievementRepository) executor(ctx context.Context) DBExecutor {
+	if tx := TxFromContext(ctx); tx != nil {
+		return tx
+	}
+	return r.db
+}
+
+func NewPostgresAchievementRepository(db *sql.DB) repositories.AchievementRepository {
+	return &PostgresAchievementRepository{db: db}
+}
+
+func (r *PostgresAchievementRepository) SaveAchievement(ctx context.Context, achievement *models.Achievement) (bool, error) {
+	query := `
+		INSERT INTO player_achievement (player_id, game_id, code, match_local_id, awarded_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (player_id, game_id, code) DO NOTHING
+		RETURNING id
+	`
+
+	err := r.executor(ctx).QueryRowContext(ctx, query,
+		achievement.PlayerID,
+		achievement.GameID,
+		string(achievement.Code),
+		achievement.MatchID,
+		achievement.AwardedAt,
+	).Scan(&achievement.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("error saving achievement: %v", err)
+	}
+
+	return true, nil
+}
+
+func (r *PostgresAchievementRepository) GetPlayerAchievements(ctx context.Context, playerID string) ([]*models.Achievement, error) {
+	query := `
+		SELECT id, player_id, game_id, code, match_local_id, awarded_at
+		FROM player_achievement
+		WHERE player_id = $1
+		ORDER BY awarded_at, id
+	`
+	return r.queryAchievements(ctx, query, playerID)
+}
+
+func (r *PostgresAchievementRepository) GetMatchAchievements(ctx context.Context, gameID, matchID string) ([]*models.Achievement, error) {
+	query := `
+		SELECT id, player_id, game_id, code, match_local_id, awarded_at
+		FROM player_achievement
+		WHERE game_id = $1 AND match_local_id = $2
+		ORDER BY awarded_at, id
+	`
+	return r.queryAchievements(ctx, query, gameID, matchID)
+}
+
+func (r *PostgresAchievementRepository) DeleteAchievement(ctx context.Context, achievementID string) error {
+	if _, err := r.executor(ctx).ExecContext(ctx, `DELETE FROM player_achievement WHERE id = $1`, achievementID); err != nil {
+		return fmt.Errorf("error deleting achievement: %v", err)
+	}
+	return nil
+}
+
+// queryAchievements returns the achievements selected by a query with the columns of GetPlayerAchievements
+func (r *PostgresAchievementRepository) queryAchievements(ctx context.Context, query string, args ...any) ([]*models.Achievement, error) {
+	rows, err := r.executor(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error getting achievements: %v", err)
+	}
+	defer rows.Close()
+
+	achievements := make([]*models.Achievement, 0)
+	for rows.Next() {
+		var achievement models.Achievement
+		var code string
+		if err := rows.Scan(
+			&achievement.ID,
+			&achievement.PlayerID,
+			&achievement.GameID,
+			&code,
+			&achievement.MatchID,
+			&achievement.AwardedAt,
+		); err != nil {
+			return nil, fmt.Errorf("error scanning achievement: %v", err)
+		}
+		achievement.Code = models.AchievementCode(code)
+		achievements = append(achievements, &achievement)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating achievements: %v", err)
+	}
+
+	return achievements, nil
+}
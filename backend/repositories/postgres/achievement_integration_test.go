@@ -0,0 +1,63 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"ligain/backend/models"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAchievementRepository_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	runTestWithTimeout(t, func(t *testing.T) {
+		testDB := setupTestDB(t)
+		defer testDB.Close()
+
+		achievementRepo := NewPostgresAchievementRepository(testDB.db)
+		ctx := context.Background()
+
+		gameID := "123e4567-e89b-12d3-a456-426614174101"
+		playerID := "123e4567-e89b-12d3-a456-426614174102"
+		_, err := testDB.db.Exec(`INSERT INTO game (id, season_year, competition_name, status, game_name) VALUES ($1, $2, $3, $4, $5)`, gameID, "2024", "Test League", "not started", "Test Game")
+		require.NoError(t, err)
+		_, err = testDB.db.Exec(`INSERT INTO player (id, name) VALUES ($1, $2)`, playerID, "Achiever")
+		require.NoError(t, err)
+
+		t.Run("Save and Get Achievements", func(t *testing.T) {
+			awardedAt := time.Now().UTC().Truncate(time.Second)
+			achievement := models.NewAchievement(playerID, gameID, models.AchievementFirstPerfectScore, "match-1", awardedAt)
+
+			saved, err := achievementRepo.SaveAchievement(ctx, achievement)
+			require.NoError(t, err)
+			require.True(t, saved)
+			require.NotEmpty(t, achievement.ID)
+
+			achievements, err := achievementRepo.GetPlayerAchievements(ctx, playerID)
+			require.NoError(t, err)
+			require.Len(t, achievements, 1)
+			require.Equal(t, models.AchievementFirstPerfectScore, achievements[0].Code)
+			require.Equal(t, gameID, achievements[0].GameID)
+			require.Equal(t, "match-1", achievements[0].MatchID)
+			require.True(t, awardedAt.Equal(achievements[0].AwardedAt))
+		})
+
+		t.Run("Achievement is only awarded once per game", func(t *testing.T) {
+			achievement := models.NewAchievement(playerID, gameID, models.AchievementFirstPerfectScore, "match-2", time.Now())
+
+			saved, err := achievementRepo.SaveAchievement(ctx, achievement)
+			require.NoError(t, err)
+			require.False(t, saved)
+
+			achievements, err := achievementRepo.GetPlayerAchievements(ctx, playerID)
+			require.NoError(t, err)
+			require.Len(t, achievements, 1)
+			require.Equal(t, "match-1", achievements[0].MatchID)
+		})
+	}, 30*time.Second)
+}
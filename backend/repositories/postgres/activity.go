@@ -0,0 +1,118 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"ligain/backend/models"
+	"ligain/backend/repositories"
+)
+
+type PostgresActivityRepository struct {
+	db *sql.DB
+}
+
+// executor returns the appropriate DBExecutor (transaction or db connection).
+func (r *PostgresActivityRepository) executor(ctx context.Context) DBExecutor {
+	if tx := TxFromContext(ctx); tx != nil {
+		return tx
+	}
+	return r.db
+}
+
+func NewPostgresActivityRepository(db *sql.DB) repositories.ActivityRepository {
+	return &PostgresActivityRepository{db: db}
+}
+
+func (r *PostgresActivityRepository) SaveActivity(ctx context.Context, activity *models.Activity) error {
+	details, err := json.Marshal(activity.Details)
+	if err != nil {
+		return fmt.Errorf("error encoding activity details: %v", err)
+	}
+
+	query := `
+		INSERT INTO game_activity (game_id, type, player_id, player_name, match_local_id, details, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id
+	`
+
+	err = r.executor(ctx).QueryRowContext(ctx, query,
+		activity.GameID,
+		string(activity.Type),
+		nullIfEmpty(activity.PlayerID),
+		nullIfEmpty(activity.PlayerName),
+		nullIfEmpty(activity.MatchID),
+		details,
+		activity.CreatedAt,
+	).Scan(&activity.ID)
+	if err != nil {
+		return fmt.Errorf("error saving activity: %v", err)
+	}
+
+	return nil
+}
+
+func (r *PostgresActivityRepository) GetActivities(ctx context.Context, gameID string, beforeID int64, limit int) ([]*models.Activity, error) {
+	query := `
+		SELECT id, game_id, type, player_id, player_name, match_local_id, details, created_at
+		FROM game_activity
+		WHERE game_id = $1 AND ($2 = 0 OR id < $2)
+		ORDER BY id DESC
+		LIMIT $3
+	`
+	return r.queryActivities(ctx, query, gameID, beforeID, limit)
+}
+
+func (r *PostgresActivityRepository) GetLatestMatchActivity(ctx context.Context, gameID, matchID string, activityType models.ActivityType) (*models.Activity, error) {
+	query := `
+		SELECT id, game_id, type, player_id, player_name, match_local_id, details, created_at
+		FROM game_activity
+		WHERE game_id = $1 AND match_local_id = $2 AND type = $3
+		ORDER BY id DESC
+		LIMIT 1
+	`
+	activities, err := r.queryActivities(ctx, query, gameID, matchID, string(activityType))
+	if err != nil || len(activities) == 0 {
+		return nil, err
+	}
+	return activities[0], nil
+}
+
+// queryActivities returns the activities selected by a query with the columns of GetActivities
+func (r *PostgresActivityRepository) queryActivities(ctx context.Context, query string, args ...any) ([]*models.Activity, error) {
+	rows, err := r.executor(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error getting activities: %v", err)
+	}
+	defer rows.Close()
+
+	activities := make([]*models.Activity, 0)
+	for rows.Next() {
+		var activity models.Activity
+		var activityType string
+		var playerID, playerName, matchID sql.NullString
+		var details []byte
+		if err := rows.Scan(&activity.ID, &activity.GameID, &activityType, &playerID, &playerName, &matchID, &details, &activity.CreatedAt); err != nil {
+			return nil, fmt.Errorf("error scanning activity: %v", err)
+		}
+		if err := json.Unmarshal(details, &activity.Details); err != nil {
+			return nil, fmt.Errorf("error decoding activity details: %v", err)
+		}
+		activity.Type = models.ActivityType(activityType)
+		activity.PlayerID = playerID.String
+		activity.PlayerName = playerName.String
+		activity.MatchID = matchID.String
+		activities = append(activities, &activity)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating activities: %v", err)
+	}
+
+	return activities, nil
+}
+
+// nullIfEmpty stores empty strings as NULL
+func nullIfEmpty(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: value != ""}
+}
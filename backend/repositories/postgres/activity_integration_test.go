@@ -0,0 +1,61 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"ligain/backend/models"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestActivityRepository_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	runTestWithTimeout(t, func(t *testing.T) {
+		testDB := setupTestDB(t)
+		defer testDB.Close()
+
+		activityRepo := NewPostgresActivityRepository(testDB.db)
+		ctx := context.Background()
+
+		gameID := "123e4567-e89b-12d3-a456-426614174301"
+		playerID := "123e4567-e89b-12d3-a456-426614174302"
+		_, err := testDB.db.Exec(`INSERT INTO game (id, season_year, competition_name, status, game_name) VALUES ($1, $2, $3, $4, $5)`, gameID, "2024", "Test League", "not started", "Test Game")
+		require.NoError(t, err)
+
+		t.Run("Save and Paginate Activities", func(t *testing.T) {
+			createdAt := time.Now().UTC().Truncate(time.Second)
+			joined := models.NewPlayerActivity(gameID, models.ActivityPlayerJoined, &models.PlayerData{ID: playerID, Name: "Joiner"})
+			joined.CreatedAt = createdAt
+			require.NoError(t, activityRepo.SaveActivity(ctx, joined))
+			require.NotZero(t, joined.ID)
+
+			match := models.NewSeasonMatch("Team1", "Team2", "2024", "Test League", createdAt, 1)
+			match.Finish(2, 1)
+			scored := models.NewMatchActivity(gameID, models.ActivityMatchScored, match)
+			scored.Details.Points = map[string]int{playerID: 500}
+			scored.CreatedAt = createdAt
+			require.NoError(t, activityRepo.SaveActivity(ctx, scored))
+
+			activities, err := activityRepo.GetActivities(ctx, gameID, 0, 10)
+			require.NoError(t, err)
+			require.Len(t, activities, 2)
+			require.Equal(t, models.ActivityMatchScored, activities[0].Type)
+			require.Empty(t, activities[0].PlayerID)
+			require.Equal(t, match.Id(), activities[0].MatchID)
+			require.Equal(t, 2, *activities[0].Details.HomeGoals)
+			require.Equal(t, 500, activities[0].Details.Points[playerID])
+			require.Equal(t, "Joiner", activities[1].PlayerName)
+			require.True(t, createdAt.Equal(activities[1].CreatedAt))
+
+			older, err := activityRepo.GetActivities(ctx, gameID, scored.ID, 10)
+			require.NoError(t, err)
+			require.Len(t, older, 1)
+			require.Equal(t, joined.ID, older[0].ID)
+		})
+	}, 30*time.Second)
+}
@@ -0,0 +1,199 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"ligain/backend/models"
+	"ligain/backend/repositories"
+	"strings"
+)
+
+type PostgresAdminRepository struct {
+	db *sql.DB
+}
+
+// executor returns the appropriate DBExecutor (transaction or db connection).
+func (r *PostgresAdminRepository) executor(ctx context.Context) DBExecutor {
+	if tx := TxFromContext(ctx); tx != nil {
+		return tx
+	}
+	return r.db
+}
+
+func NewPostgresAdminRepository(db *sql.DB) repositories.AdminRepository {
+	return &PostgresAdminRepository{db: db}
+}
+
+func (r *PostgresAdminRepository) SearchPlayers(ctx context.Context, query string, limit int) ([]*models.PlayerData, error) {
+	sqlQuery := `
+		SELECT id, name, email, provider, provider_id, created_at, updated_at,
+			avatar_object_key, avatar_signed_url, avatar_signed_url_expires_at, role
+		FROM player
+		WHERE $1 = '' OR id::text = $1 OR name ILIKE $2 OR email ILIKE $2
+		ORDER BY name
+		LIMIT $3
+	`
+
+	rows, err := r.executor(ctx).QueryContext(ctx, sqlQuery, query, containsPattern(query), limit)
+	if err != nil {
+		return nil, fmt.Errorf("error searching players: %v", err)
+	}
+	defer rows.Close()
+
+	players := make([]*models.PlayerData, 0)
+	for rows.Next() {
+		var player models.PlayerData
+		if err := rows.Scan(
+			&player.ID, &player.Name, &player.Email, &player.Provider, &player.ProviderID,
+			&player.CreatedAt, &player.UpdatedAt,
+			&player.AvatarObjectKey, &player.AvatarSignedURL, &player.AvatarSignedURLExpiresAt, &player.Role); err != nil {
+			return nil, fmt.Errorf("error scanning player: %v", err)
+		}
+		players = append(players, &player)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating players: %v", err)
+	}
+
+	return players, nil
+}
+
+func (r *PostgresAdminRepository) SearchGames(ctx context.Context, query string, limit int) ([]*models.AdminGameSummary, error) {
+	sqlQuery := `
+		SELECT g.id, g.game_name, g.competition_name, g.season_year, g.status, g.created_at,
+			(SELECT COUNT(*) FROM game_player gp WHERE gp.game_id = g.id),
+			COALESCE((
+				SELECT gc.code FROM game_codes gc
+				WHERE gc.game_id = g.id AND gc.expires_at > NOW()
+				ORDER BY gc.created_at DESC
+				LIMIT 1
+			), '')
+		FROM game g
+		WHERE $1 = '' OR g.id::text = $1 OR g.game_name ILIKE $2
+			OR EXISTS (
+				SELECT 1 FROM game_codes gc
+				WHERE gc.game_id = g.id AND gc.code = UPPER($1) AND gc.expires_at > NOW()
+			)
+		ORDER BY g.created_at DESC
+		LIMIT $3
+	`
+
+	rows, err := r.executor(ctx).QueryContext(ctx, sqlQuery, query, containsPattern(query), limit)
+	if err != nil {
+		return nil, fmt.Errorf("error searching games: %v", err)
+	}
+	defer rows.Close()
+
+	games := make([]*models.AdminGameSummary, 0)
+	for rows.Next() {
+		var game models.AdminGameSummary
+		if err := rows.Scan(
+			&game.ID, &game.Name, &game.CompetitionName, &game.SeasonYear, &game.Status, &game.CreatedAt,
+			&game.PlayerCount, &game.Code); err != nil {
+			return nil, fmt.Errorf("error scanning game: %v", err)
+		}
+		games = append(games, &game)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating games: %v", err)
+	}
+
+	return games, nil
+}
+
+func (r *PostgresAdminRepository) SetPlayerRole(ctx context.Context, playerID string, role string) error {
+	result, err := r.executor(ctx).ExecContext(ctx,
+		`UPDATE player SET role = $2, updated_at = NOW() WHERE id = $1`, playerID, role)
+	if err != nil {
+		return fmt.Errorf("error setting player role: %v", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error getting rows affected: %v", err)
+	}
+	if rowsAffected == 0 {
+		return repositories.ErrPlayerNotFound
+	}
+
+	return nil
+}
+
+func (r *PostgresAdminRepository) SaveAuditEntry(ctx context.Context, entry *models.AdminAuditEntry) error {
+	details, err := json.Marshal(entry.Details)
+	if err != nil {
+		return fmt.Errorf("error encoding audit entry details: %v", err)
+	}
+	if entry.Details == nil {
+		details = []byte("{}")
+	}
+
+	query := `
+		INSERT INTO admin_audit_log (admin_id, admin_name, action, target_type, target_id, details, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id
+	`
+
+	err = r.executor(ctx).QueryRowContext(ctx, query,
+		nullIfEmpty(entry.AdminID),
+		entry.AdminName,
+		string(entry.Action),
+		entry.TargetType,
+		entry.TargetID,
+		details,
+		entry.CreatedAt,
+	).Scan(&entry.ID)
+	if err != nil {
+		return fmt.Errorf("error saving audit entry: %v", err)
+	}
+
+	return nil
+}
+
+func (r *PostgresAdminRepository) GetAuditEntries(ctx context.Context, targetType string, targetID string, beforeID int64, limit int) ([]*models.AdminAuditEntry, error) {
+	query := `
+		SELECT id, admin_id, admin_name, action, target_type, target_id, details, created_at
+		FROM admin_audit_log
+		WHERE ($1 = '' OR (target_type = $1 AND target_id = $2)) AND ($3 = 0 OR id < $3)
+		ORDER BY id DESC
+		LIMIT $4
+	`
+
+	rows, err := r.executor(ctx).QueryContext(ctx, query, targetType, targetID, beforeID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error getting audit entries: %v", err)
+	}
+	defer rows.Close()
+
+	entries := make([]*models.AdminAuditEntry, 0)
+	for rows.Next() {
+		var entry models.AdminAuditEntry
+		var action string
+		var adminID sql.NullString
+		var details []byte
+		if err := rows.Scan(&entry.ID, &adminID, &entry.AdminName, &action, &entry.TargetType, &entry.TargetID, &details, &entry.CreatedAt); err != nil {
+			return nil, fmt.Errorf("error scanning audit entry: %v", err)
+		}
+		if err := json.Unmarshal(details, &entry.Details); err != nil {
+			return nil, fmt.Errorf("error decoding audit entry details: %v", err)
+		}
+		if len(entry.Details) == 0 {
+			entry.Details = nil
+		}
+		entry.Action = models.AdminAction(action)
+		entry.AdminID = adminID.String
+		entries = append(entries, &entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating audit entries: %v", err)
+	}
+
+	return entries, nil
+}
+
+// containsPattern returns the ILIKE pattern matching the texts that contain the query
+func containsPattern(query string) string {
+	escaper := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return "%" + escaper.Replace(query) + "%"
+}
@@ -0,0 +1,106 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"ligain/backend/models"
+	"ligain/backend/repositories"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdminRepository_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	runTestWithTimeout(t, func(t *testing.T) {
+		testDB := setupTestDB(t)
+		defer testDB.Close()
+
+		adminRepo := NewPostgresAdminRepository(testDB.db)
+		ctx := context.Background()
+
+		gameID := "123e4567-e89b-12d3-a456-426614174401"
+		adminID := "123e4567-e89b-12d3-a456-426614174402"
+		playerID := "123e4567-e89b-12d3-a456-426614174403"
+		exec := func(query string, args ...any) {
+			_, err := testDB.db.Exec(query, args...)
+			require.NoError(t, err)
+		}
+		exec(`INSERT INTO game (id, season_year, competition_name, status, game_name) VALUES ($1, '2024', 'Test League', 'scheduled', 'Sunday 100%_League')`, gameID)
+		exec(`INSERT INTO player (id, name, role) VALUES ($1, 'Admin', 'admin'), ($2, 'Regular Joe', 'player')`, adminID, playerID)
+		exec(`INSERT INTO game_player (game_id, player_id) VALUES ($1, $2)`, gameID, playerID)
+		exec(`INSERT INTO game_codes (game_id, code, expires_at) VALUES ($1, 'WXYZ', $2)`, gameID, time.Now().Add(24*time.Hour))
+
+		t.Run("Search Players", func(t *testing.T) {
+			players, err := adminRepo.SearchPlayers(ctx, "joe", 10)
+			require.NoError(t, err)
+			require.Len(t, players, 1)
+			require.Equal(t, playerID, players[0].ID)
+			require.Equal(t, models.PlayerRolePlayer, players[0].Role)
+
+			players, err = adminRepo.SearchPlayers(ctx, "", 10)
+			require.NoError(t, err)
+			require.Len(t, players, 2)
+		})
+
+		t.Run("Search Games", func(t *testing.T) {
+			for _, query := range []string{"", "100%_", "wxyz", gameID} {
+				games, err := adminRepo.SearchGames(ctx, query, 10)
+				require.NoError(t, err)
+				require.Len(t, games, 1, "query %q", query)
+				require.Equal(t, gameID, games[0].ID)
+				require.Equal(t, 1, games[0].PlayerCount)
+				require.Equal(t, "WXYZ", games[0].Code)
+			}
+
+			// Wildcards in the query are matched literally
+			games, err := adminRepo.SearchGames(ctx, "100%x", 10)
+			require.NoError(t, err)
+			require.Empty(t, games)
+		})
+
+		t.Run("Set Player Role", func(t *testing.T) {
+			require.NoError(t, adminRepo.SetPlayerRole(ctx, playerID, models.PlayerRoleAdmin))
+			players, err := adminRepo.SearchPlayers(ctx, "joe", 10)
+			require.NoError(t, err)
+			require.True(t, players[0].IsAdmin())
+
+			err = adminRepo.SetPlayerRole(ctx, "123e4567-e89b-12d3-a456-426614174499", models.PlayerRoleAdmin)
+			require.ErrorIs(t, err, repositories.ErrPlayerNotFound)
+		})
+
+		t.Run("Save and Paginate Audit Entries", func(t *testing.T) {
+			createdAt := time.Now().UTC().Truncate(time.Second)
+			entries := []*models.AdminAuditEntry{
+				{AdminID: adminID, AdminName: "Admin", Action: models.AdminActionFinishGame, TargetType: models.AdminTargetGame, TargetID: gameID, CreatedAt: createdAt},
+				{AdminID: adminID, AdminName: "Admin", Action: models.AdminActionSetPlayerRole, TargetType: models.AdminTargetPlayer, TargetID: playerID, Details: map[string]any{"role": "admin"}, CreatedAt: createdAt},
+				{AdminID: adminID, AdminName: "Admin", Action: models.AdminActionUnfinishGame, TargetType: models.AdminTargetGame, TargetID: gameID, CreatedAt: createdAt},
+			}
+			for _, entry := range entries {
+				require.NoError(t, adminRepo.SaveAuditEntry(ctx, entry))
+				require.NotZero(t, entry.ID)
+			}
+
+			all, err := adminRepo.GetAuditEntries(ctx, "", "", 0, 10)
+			require.NoError(t, err)
+			require.Len(t, all, 3)
+			require.Equal(t, models.AdminActionUnfinishGame, all[0].Action)
+			require.Equal(t, "admin", all[1].Details["role"])
+			require.True(t, createdAt.Equal(all[2].CreatedAt))
+
+			gameEntries, err := adminRepo.GetAuditEntries(ctx, models.AdminTargetGame, gameID, 0, 1)
+			require.NoError(t, err)
+			require.Len(t, gameEntries, 1)
+			require.Equal(t, entries[2].ID, gameEntries[0].ID)
+
+			older, err := adminRepo.GetAuditEntries(ctx, models.AdminTargetGame, gameID, gameEntries[0].ID, 10)
+			require.NoError(t, err)
+			require.Len(t, older, 1)
+			require.Equal(t, entries[0].ID, older[0].ID)
+		})
+	}, 30*time.Second)
+}
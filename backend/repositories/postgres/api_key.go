@@ -0,0 +1,149 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"ligain/backend/models"
+	"ligain/backend/repositories"
+	"strings"
+	"time"
+)
+
+type PostgresAPIKeyRepository struct {
+	db *sql.DB
+}
+
+// executor returns the appropriate DBExecutor (transaction or db connection).
+func (r *PostgresAPIKeyRepository) executor(ctx context.Context) DBExecutor {
+	if tx := TxFromContext(ctx); tx != nil {
+		return tx
+	}
+	return r.db
+}
+
+func NewPostgresAPIKeyRepository(db *sql.DB) repositories.APIKeyRepository {
+	return &PostgresAPIKeyRepository{db: db}
+}
+
+// apiKeyColumns are the columns read by scanAPIKey, in order
+const apiKeyColumns = `id, name, key_prefix, key_hash, scopes, rate_limit_per_minute, created_at, expires_at, revoked_at`
+
+func (r *PostgresAPIKeyRepository) CreateAPIKey(ctx context.Context, key *models.APIKey) error {
+	query := `
+		INSERT INTO api_key (name, key_prefix, key_hash, scopes, rate_limit_per_minute, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id
+	`
+
+	err := r.executor(ctx).QueryRowContext(ctx, query,
+		key.Name,
+		key.Prefix,
+		key.KeyHash,
+		strings.Join(key.Scopes, ","),
+		key.RateLimitPerMinute,
+		key.CreatedAt,
+		key.ExpiresAt,
+	).Scan(&key.ID)
+	if err != nil {
+		return fmt.Errorf("error creating api key: %v", err)
+	}
+
+	return nil
+}
+
+func (r *PostgresAPIKeyRepository) GetAPIKeyByHash(ctx context.Context, keyHash string) (*models.APIKey, error) {
+	query := `SELECT ` + apiKeyColumns + ` FROM api_key WHERE key_hash = $1`
+
+	key, err := scanAPIKey(r.executor(ctx).QueryRowContext(ctx, query, keyHash))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting api key: %v", err)
+	}
+
+	return key, nil
+}
+
+func (r *PostgresAPIKeyRepository) GetAPIKeysByName(ctx context.Context, name string) ([]*models.APIKey, error) {
+	query := `SELECT ` + apiKeyColumns + ` FROM api_key WHERE name = $1 ORDER BY created_at`
+	return r.queryAPIKeys(ctx, query, name)
+}
+
+func (r *PostgresAPIKeyRepository) ListAPIKeys(ctx context.Context) ([]*models.APIKey, error) {
+	query := `SELECT ` + apiKeyColumns + ` FROM api_key ORDER BY name, created_at`
+	return r.queryAPIKeys(ctx, query)
+}
+
+func (r *PostgresAPIKeyRepository) queryAPIKeys(ctx context.Context, query string, args ...any) ([]*models.APIKey, error) {
+	rows, err := r.executor(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error getting api keys: %v", err)
+	}
+	defer rows.Close()
+
+	keys := make([]*models.APIKey, 0)
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning api key: %v", err)
+		}
+		keys = append(keys, key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating api keys: %v", err)
+	}
+
+	return keys, nil
+}
+
+func (r *PostgresAPIKeyRepository) SetAPIKeyExpiry(ctx context.Context, keyID string, expiresAt time.Time) error {
+	return r.updateAPIKey(ctx, `UPDATE api_key SET expires_at = $2 WHERE id = $1`, keyID, expiresAt)
+}
+
+func (r *PostgresAPIKeyRepository) RevokeAPIKey(ctx context.Context, keyID string, revokedAt time.Time) error {
+	return r.updateAPIKey(ctx, `UPDATE api_key SET revoked_at = $2 WHERE id = $1`, keyID, revokedAt)
+}
+
+func (r *PostgresAPIKeyRepository) updateAPIKey(ctx context.Context, query string, keyID string, at time.Time) error {
+	result, err := r.executor(ctx).ExecContext(ctx, query, keyID, at)
+	if err != nil {
+		return fmt.Errorf("error updating api key: %v", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error getting rows affected: %v", err)
+	}
+	if rowsAffected == 0 {
+		return repositories.ErrAPIKeyNotFound
+	}
+
+	return nil
+}
+
+// scanAPIKey reads a row selected with apiKeyColumns
+func scanAPIKey(row interface{ Scan(dest ...any) error }) (*models.APIKey, error) {
+	var key models.APIKey
+	var scopes string
+	if err := row.Scan(
+		&key.ID,
+		&key.Name,
+		&key.Prefix,
+		&key.KeyHash,
+		&scopes,
+		&key.RateLimitPerMinute,
+		&key.CreatedAt,
+		&key.ExpiresAt,
+		&key.RevokedAt,
+	); err != nil {
+		return nil, err
+	}
+	key.Scopes = make([]string, 0)
+	if scopes != "" {
+		key.Scopes = strings.Split(scopes, ",")
+	}
+
+	return &key, nil
+}
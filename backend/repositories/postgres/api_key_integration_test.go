@@ -0,0 +1,95 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"ligain/backend/models"
+	"ligain/backend/repositories"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIKeyRepository_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	runTestWithTimeout(t, func(t *testing.T) {
+		testDB := setupTestDB(t)
+		defer testDB.Close()
+
+		repo := NewPostgresAPIKeyRepository(testDB.db)
+		ctx := context.Background()
+		now := time.Now().UTC().Truncate(time.Second)
+
+		ios := &models.APIKey{
+			Name:               "ios",
+			Prefix:             "lgn_abcd",
+			KeyHash:            "ios-hash",
+			Scopes:             []string{models.APIKeyScopeRead, models.APIKeyScopeWrite},
+			RateLimitPerMinute: 600,
+			CreatedAt:          now.Add(-time.Hour),
+		}
+		iosRotated := &models.APIKey{
+			Name:      "ios",
+			Prefix:    "lgn_efgh",
+			KeyHash:   "ios-rotated-hash",
+			Scopes:    []string{models.APIKeyScopeRead, models.APIKeyScopeWrite},
+			CreatedAt: now,
+		}
+		partner := &models.APIKey{
+			Name:      "partner",
+			Prefix:    "lgn_ijkl",
+			KeyHash:   "partner-hash",
+			Scopes:    []string{models.APIKeyScopeRead},
+			CreatedAt: now,
+		}
+		for _, key := range []*models.APIKey{ios, iosRotated, partner} {
+			require.NoError(t, repo.CreateAPIKey(ctx, key))
+			require.NotEmpty(t, key.ID)
+		}
+
+		t.Run("Lookups", func(t *testing.T) {
+			stored, err := repo.GetAPIKeyByHash(ctx, "ios-hash")
+			require.NoError(t, err)
+			require.NotNil(t, stored)
+			require.Equal(t, ios.ID, stored.ID)
+			require.Equal(t, []string{"read", "write"}, stored.Scopes)
+			require.Equal(t, 600, stored.RateLimitPerMinute)
+			require.Nil(t, stored.ExpiresAt)
+
+			unknown, err := repo.GetAPIKeyByHash(ctx, "unknown")
+			require.NoError(t, err)
+			require.Nil(t, unknown)
+
+			byName, err := repo.GetAPIKeysByName(ctx, "ios")
+			require.NoError(t, err)
+			require.Len(t, byName, 2)
+			require.Equal(t, ios.ID, byName[0].ID)
+
+			all, err := repo.ListAPIKeys(ctx)
+			require.NoError(t, err)
+			require.Len(t, all, 3)
+			require.Equal(t, "partner", all[2].Name)
+		})
+
+		t.Run("Expiry And Revocation", func(t *testing.T) {
+			require.NoError(t, repo.SetAPIKeyExpiry(ctx, ios.ID, now.Add(time.Hour)))
+			require.NoError(t, repo.RevokeAPIKey(ctx, partner.ID, now))
+
+			stored, err := repo.GetAPIKeyByHash(ctx, "ios-hash")
+			require.NoError(t, err)
+			require.True(t, now.Add(time.Hour).Equal(*stored.ExpiresAt))
+			require.True(t, stored.IsActive(now))
+			require.False(t, stored.IsActive(now.Add(time.Hour)))
+
+			revoked, err := repo.GetAPIKeyByHash(ctx, "partner-hash")
+			require.NoError(t, err)
+			require.False(t, revoked.IsActive(now))
+
+			require.ErrorIs(t, repo.RevokeAPIKey(ctx, "00000000-0000-0000-0000-000000000000", now), repositories.ErrAPIKeyNotFound)
+		})
+	}, 30*time.Second)
+}
@@ -1,6 +1,7 @@
 package postgres
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"ligain/backend/models"
@@ -21,7 +22,15 @@ func NewPostgresBetRepository(db DBExecutor) repositories.BetRepository {
 	}
 }
 
-func (r *PostgresBetRepository) SaveBet(gameId string, bet *models.Bet, player models.Player) (string, *models.Bet, error) {
+// executor returns the appropriate DBExecutor (transaction or db connection).
+func (r *PostgresBetRepository) executor(ctx context.Context) DBExecutor {
+	if tx := TxFromContext(ctx); tx != nil {
+		return tx
+	}
+	return r.db
+}
+
+func (r *PostgresBetRepository) SaveBet(ctx context.Context, gameId string, bet *models.Bet, player models.Player) (string, *models.Bet, error) {
 	query := `
 		WITH match_id AS (
 			SELECT id FROM match 
@@ -40,7 +49,8 @@ func (r *PostgresBetRepository) SaveBet(gameId string, bet *models.Bet, player m
 		RETURNING id`
 
 	var id string
-	err := r.db.QueryRow(
+	err := r.executor(ctx).QueryRowContext(
+		ctx,
 		query,
 		bet.Match.GetHomeTeam(),
 		bet.Match.GetAwayTeam(),
@@ -322,3 +332,126 @@ func (r *PostgresBetRepository) GetScoresByMatchAndPlayer(gameId string) (map[st
 	}
 	return scores, nil
 }
+
+func (r *PostgresBetRepository) GetPlayerBets(playerId string) ([]*models.PlayerBetRecord, error) {
+	rows, err := r.db.Query(`
+		SELECT b.game_id, m.local_id, m.home_team_id, m.away_team_id,
+			b.predicted_home_goals, b.predicted_away_goals, s.points, b.updated_at
+		FROM bet b
+		JOIN match m ON b.match_id = m.id
+		LEFT JOIN score s ON s.game_id = b.game_id AND s.match_id = b.match_id AND s.player_id = b.player_id
+		WHERE b.player_id = $1
+		ORDER BY m.match_date, b.game_id`,
+		playerId)
+	if err != nil {
+		return nil, fmt.Errorf("error getting player bets: %v", err)
+	}
+	defer rows.Close()
+
+	records := make([]*models.PlayerBetRecord, 0)
+	for rows.Next() {
+		var record models.PlayerBetRecord
+		var points sql.NullInt32
+		var updatedAt sql.NullTime
+		err := rows.Scan(&record.GameID, &record.MatchID, &record.HomeTeam, &record.AwayTeam,
+			&record.PredictedHomeGoals, &record.PredictedAwayGoals, &points, &updatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning player bet row: %v", err)
+		}
+		if points.Valid {
+			value := int(points.Int32)
+			record.Points = &value
+		}
+		if updatedAt.Valid {
+			record.UpdatedAt = &updatedAt.Time
+		}
+		records = append(records, &record)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating player bet rows: %v", err)
+	}
+	return records, nil
+}
+
+func (r *PostgresBetRepository) ClaimScoring(ctx context.Context, scoring *models.MatchScoring) (bool, error) {
+	var matchId string
+	err := r.executor(ctx).QueryRowContext(ctx, `SELECT id FROM match WHERE local_id = $1`, scoring.MatchID).Scan(&matchId)
+	if err == sql.ErrNoRows {
+		return false, fmt.Errorf("match %s not found", scoring.MatchID)
+	}
+	if err != nil {
+		return false, fmt.Errorf("error claiming scoring: %v", err)
+	}
+
+	result, err := r.executor(ctx).ExecContext(ctx, `
+		INSERT INTO match_scoring (game_id, match_id, version, scored_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (game_id, match_id, version) DO NOTHING`,
+		scoring.GameID, matchId, scoring.Version, scoring.ScoredAt)
+	if err != nil {
+		return false, fmt.Errorf("error claiming scoring: %v", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("error claiming scoring: %v", err)
+	}
+	return rows == 1, nil
+}
+
+func (r *PostgresBetRepository) GetScoringVersions(ctx context.Context, gameId string) (map[string]int, error) {
+	rows, err := r.executor(ctx).QueryContext(ctx, `
+		SELECT m.local_id, MAX(ms.version)
+		FROM match_scoring ms
+		JOIN match m ON ms.match_id = m.id
+		WHERE ms.game_id = $1
+		GROUP BY m.local_id`,
+		gameId)
+	if err != nil {
+		return nil, fmt.Errorf("error getting scoring versions: %v", err)
+	}
+	defer rows.Close()
+
+	versions := make(map[string]int)
+	for rows.Next() {
+		var matchId string
+		var version int
+		if err := rows.Scan(&matchId, &version); err != nil {
+			return nil, fmt.Errorf("error scanning scoring version row: %v", err)
+		}
+		versions[matchId] = version
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating scoring version rows: %v", err)
+	}
+	return versions, nil
+}
+
+func (r *PostgresBetRepository) SaveScores(ctx context.Context, gameId string, match models.Match, players []models.Player, scores map[string]int) error {
+	for _, player := range players {
+		points, scored := scores[player.GetID()]
+		if !scored {
+			continue
+		}
+		_, err := r.executor(ctx).ExecContext(ctx, `
+			WITH match_id AS (
+				SELECT id FROM match
+				WHERE local_id = $1
+			)
+			INSERT INTO score (game_id, match_id, player_id, bet_id, points)
+			SELECT $3, m.id, $2, b.id, $4
+			FROM match_id m
+			LEFT JOIN bet b ON b.match_id = m.id
+			AND b.player_id = $2
+			AND b.game_id = $3
+			ON CONFLICT (game_id, match_id, player_id) DO UPDATE
+			SET points = EXCLUDED.points, updated_at = NOW()`,
+			match.Id(),
+			player.GetID(),
+			gameId,
+			points)
+		if err != nil {
+			return fmt.Errorf("error saving score of player %s: %v", player.GetID(), err)
+		}
+	}
+	return nil
+}
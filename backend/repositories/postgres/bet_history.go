@@ -0,0 +1,124 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"ligain/backend/models"
+	"ligain/backend/repositories"
+)
+
+type PostgresBetHistoryRepository struct {
+	db *sql.DB
+}
+
+// executor returns the appropriate DBExecutor (transaction or db connection).
+func (r *PostgresBetHistoryRepository) executor(ctx context.Context) DBExecutor {
+	if tx := TxFromContext(ctx); tx != nil {
+		return tx
+	}
+	return r.db
+}
+
+func NewPostgresBetHistoryRepository(db *sql.DB) repositories.BetHistoryRepository {
+	return &PostgresBetHistoryRepository{db: db}
+}
+
+func (r *PostgresBetHistoryRepository) RecordBetChange(ctx context.Context, change *models.BetChange) error {
+	query := `
+		INSERT INTO bet_history (
+			game_id, match_local_id, player_id, predicted_home_goals, predicted_away_goals,
+			placed_at, kickoff_at, device_name, platform, app_version, ip_address
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING id
+	`
+
+	err := r.executor(ctx).QueryRowContext(ctx, query,
+		change.GameID,
+		change.MatchID,
+		change.PlayerID,
+		change.PredictedHomeGoals,
+		change.PredictedAwayGoals,
+		change.PlacedAt,
+		change.KickoffAt,
+		nullIfEmpty(change.Device.Name),
+		nullIfEmpty(change.Device.Platform),
+		nullIfEmpty(change.Device.AppVersion),
+		nullIfEmpty(change.Device.IPAddress),
+	).Scan(&change.ID)
+	if err != nil {
+		return fmt.Errorf("error recording bet change: %v", err)
+	}
+
+	return nil
+}
+
+func (r *PostgresBetHistoryRepository) GetBetHistory(ctx context.Context, gameID string, playerID string, matchID string) ([]*models.BetChange, error) {
+	query := `
+		SELECT id, game_id, match_local_id, player_id, predicted_home_goals, predicted_away_goals,
+			placed_at, kickoff_at, device_name, platform, app_version, ip_address
+		FROM bet_history
+		WHERE game_id = $1
+		AND ($2 = '' OR player_id::text = $2)
+		AND ($3 = '' OR match_local_id = $3)
+		ORDER BY id
+	`
+
+	return r.queryChanges(ctx, query, gameID, playerID, matchID)
+}
+
+func (r *PostgresBetHistoryRepository) GetPlayerBetHistory(ctx context.Context, playerID string) ([]*models.BetChange, error) {
+	query := `
+		SELECT id, game_id, match_local_id, player_id, predicted_home_goals, predicted_away_goals,
+			placed_at, kickoff_at, device_name, platform, app_version, ip_address
+		FROM bet_history
+		WHERE player_id = $1
+		ORDER BY id
+	`
+
+	return r.queryChanges(ctx, query, playerID)
+}
+
+// queryChanges runs a query selecting the columns of bet_history, in their order in the table
+func (r *PostgresBetHistoryRepository) queryChanges(ctx context.Context, query string, args ...any) ([]*models.BetChange, error) {
+	rows, err := r.executor(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error getting bet history: %v", err)
+	}
+	defer rows.Close()
+
+	changes := make([]*models.BetChange, 0)
+	for rows.Next() {
+		var change models.BetChange
+		var deviceName, platform, appVersion, ipAddress sql.NullString
+		if err := rows.Scan(
+			&change.ID,
+			&change.GameID,
+			&change.MatchID,
+			&change.PlayerID,
+			&change.PredictedHomeGoals,
+			&change.PredictedAwayGoals,
+			&change.PlacedAt,
+			&change.KickoffAt,
+			&deviceName,
+			&platform,
+			&appVersion,
+			&ipAddress,
+		); err != nil {
+			return nil, fmt.Errorf("error scanning bet change: %v", err)
+		}
+		change.Device = models.DeviceInfo{
+			Name:       deviceName.String,
+			Platform:   platform.String,
+			AppVersion: appVersion.String,
+			IPAddress:  ipAddress.String,
+		}
+		changes = append(changes, &change)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating bet history: %v", err)
+	}
+
+	return changes, nil
+}
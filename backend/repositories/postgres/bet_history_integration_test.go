@@ -0,0 +1,85 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"ligain/backend/models"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBetHistoryRepository_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	runTestWithTimeout(t, func(t *testing.T) {
+		testDB := setupTestDB(t)
+		defer testDB.Close()
+
+		historyRepo := NewPostgresBetHistoryRepository(testDB.db)
+		deletionRepo := NewPostgresAccountDeletionRepository(testDB.db)
+		ctx := context.Background()
+
+		gameID := "123e4567-e89b-12d3-a456-426614174601"
+		playerID := "123e4567-e89b-12d3-a456-426614174602"
+		otherPlayerID := "123e4567-e89b-12d3-a456-426614174603"
+		_, err := testDB.db.Exec(`INSERT INTO game (id, season_year, competition_name, status, game_name) VALUES ($1, '2024', 'Test League', 'scheduled', 'Test Game')`, gameID)
+		require.NoError(t, err)
+		_, err = testDB.db.Exec(`INSERT INTO player (id, name) VALUES ($1, 'Bettor'), ($2, 'Other')`, playerID, otherPlayerID)
+		require.NoError(t, err)
+
+		kickoff := time.Now().UTC().Truncate(time.Second).Add(24 * time.Hour)
+		newChange := func(playerID, matchID string, homeGoals int, placedAt time.Time) *models.BetChange {
+			return &models.BetChange{
+				GameID:             gameID,
+				MatchID:            matchID,
+				PlayerID:           playerID,
+				PredictedHomeGoals: homeGoals,
+				PredictedAwayGoals: 0,
+				PlacedAt:           placedAt,
+				KickoffAt:          kickoff,
+				Device:             models.DeviceInfo{Name: "Pixel", Platform: "android", AppVersion: "2.1.0", IPAddress: "192.0.2.1"},
+			}
+		}
+
+		t.Run("Record and Get Bet History", func(t *testing.T) {
+			first := newChange(playerID, "match-1", 2, kickoff.Add(-2*time.Hour))
+			require.NoError(t, historyRepo.RecordBetChange(ctx, first))
+			require.NotZero(t, first.ID)
+			require.NoError(t, historyRepo.RecordBetChange(ctx, newChange(playerID, "match-1", 3, kickoff.Add(-time.Hour))))
+			require.NoError(t, historyRepo.RecordBetChange(ctx, newChange(playerID, "match-2", 1, kickoff.Add(-time.Hour))))
+			require.NoError(t, historyRepo.RecordBetChange(ctx, newChange(otherPlayerID, "match-1", 0, kickoff.Add(-time.Hour))))
+
+			changes, err := historyRepo.GetBetHistory(ctx, gameID, playerID, "match-1")
+			require.NoError(t, err)
+			require.Len(t, changes, 2)
+			require.Equal(t, first.ID, changes[0].ID)
+			require.Equal(t, 2, changes[0].PredictedHomeGoals)
+			require.True(t, changes[0].PlacedAt.Equal(kickoff.Add(-2*time.Hour)))
+			require.True(t, changes[0].KickoffAt.Equal(kickoff))
+			require.Equal(t, "192.0.2.1", changes[0].Device.IPAddress)
+			require.Equal(t, 3, changes[1].PredictedHomeGoals)
+
+			changes, err = historyRepo.GetBetHistory(ctx, gameID, "", "match-1")
+			require.NoError(t, err)
+			require.Len(t, changes, 3)
+
+			changes, err = historyRepo.GetPlayerBetHistory(ctx, playerID)
+			require.NoError(t, err)
+			require.Len(t, changes, 3)
+		})
+
+		t.Run("Purge Keeps History Without Device", func(t *testing.T) {
+			_, err := deletionRepo.PurgePlayer(ctx, playerID)
+			require.NoError(t, err)
+
+			changes, err := historyRepo.GetPlayerBetHistory(ctx, playerID)
+			require.NoError(t, err)
+			require.Len(t, changes, 3)
+			require.Equal(t, models.DeviceInfo{Platform: "android", AppVersion: "2.1.0"}, changes[0].Device)
+		})
+	}, 30*time.Second)
+}
@@ -1,6 +1,7 @@
 package postgres
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"testing"
@@ -53,7 +54,7 @@ func TestBetRepository_Integration(t *testing.T) {
 				player := newTestPlayer("TestPlayer")
 
 				// Use repository SaveBet method instead of raw SQL
-				_, _, err = betRepo.SaveBet("123e4567-e89b-12d3-a456-426614174000", bet, player)
+				_, _, err = betRepo.SaveBet(context.Background(), "123e4567-e89b-12d3-a456-426614174000", bet, player)
 				if err != nil {
 					t.Errorf("Failed to save bet: %v", err)
 				}
@@ -99,11 +100,11 @@ func TestBetRepository_Integration(t *testing.T) {
 				bet2 := models.NewBet(match, 1, 1)
 				player1 := newTestPlayer("Player1")
 				player2 := newTestPlayer("Player2")
-				_, _, err = betRepo.SaveBet("223e4567-e89b-12d3-a456-426614174000", bet1, player1)
+				_, _, err = betRepo.SaveBet(context.Background(), "223e4567-e89b-12d3-a456-426614174000", bet1, player1)
 				if err != nil {
 					t.Errorf("Failed to save bet1: %v", err)
 				}
-				_, _, err = betRepo.SaveBet("223e4567-e89b-12d3-a456-426614174000", bet2, player2)
+				_, _, err = betRepo.SaveBet(context.Background(), "223e4567-e89b-12d3-a456-426614174000", bet2, player2)
 				if err != nil {
 					t.Errorf("Failed to save bet2: %v", err)
 				}
@@ -144,7 +145,7 @@ func TestBetRepository_Integration(t *testing.T) {
 				player := newTestPlayer("TestPlayer")
 
 				// Try to save bet
-				_, _, err = betRepo.SaveBet("323e4567-e89b-12d3-a456-426614174000", bet, player)
+				_, _, err = betRepo.SaveBet(context.Background(), "323e4567-e89b-12d3-a456-426614174000", bet, player)
 				require.NoError(t, err)
 			})
 		})
@@ -197,7 +198,7 @@ func TestBetRepository_Integration(t *testing.T) {
 				player := models.NewSimplePlayer("423e4567-e89b-12d3-a456-426614174001", "TestPlayer")
 
 				// Save bet
-				betId, _, err := betRepo.SaveBet("423e4567-e89b-12d3-a456-426614174000", bet, player)
+				betId, _, err := betRepo.SaveBet(context.Background(), "423e4567-e89b-12d3-a456-426614174000", bet, player)
 				if err != nil {
 					t.Errorf("Failed to save bet: %v", err)
 				}
@@ -240,7 +241,7 @@ func TestBetRepository_Integration(t *testing.T) {
 				player := models.NewSimplePlayer("523e4567-e89b-12d3-a456-426614174001", "TestPlayer")
 
 				// Save bet
-				betId, _, err := betRepo.SaveBet("523e4567-e89b-12d3-a456-426614174000", bet, player)
+				betId, _, err := betRepo.SaveBet(context.Background(), "523e4567-e89b-12d3-a456-426614174000", bet, player)
 				if err != nil {
 					t.Errorf("Failed to save bet: %v", err)
 				}
@@ -283,11 +284,11 @@ func TestBetRepository_Integration(t *testing.T) {
 				player2 := models.NewSimplePlayer("623e4567-e89b-12d3-a456-426614174002", "Player2")
 
 				// Save bets and scores
-				_, _, err = betRepo.SaveBet(gameId, bet1, player1)
+				_, _, err = betRepo.SaveBet(context.Background(), gameId, bet1, player1)
 				if err != nil {
 					t.Errorf("Failed to save bet1: %v", err)
 				}
-				_, _, err = betRepo.SaveBet(gameId, bet2, player2)
+				_, _, err = betRepo.SaveBet(context.Background(), gameId, bet2, player2)
 				if err != nil {
 					t.Errorf("Failed to save bet2: %v", err)
 				}
@@ -341,11 +342,11 @@ func TestBetRepository_Integration(t *testing.T) {
 				player2 := models.NewSimplePlayer("723e4567-e89b-12d3-a456-426614174002", "Player2")
 
 				// Save bets and scores
-				_, _, err = betRepo.SaveBet("723e4567-e89b-12d3-a456-426614174000", bet1, player1)
+				_, _, err = betRepo.SaveBet(context.Background(), "723e4567-e89b-12d3-a456-426614174000", bet1, player1)
 				if err != nil {
 					t.Errorf("Failed to save bet1: %v", err)
 				}
-				_, _, err = betRepo.SaveBet("723e4567-e89b-12d3-a456-426614174000", bet2, player2)
+				_, _, err = betRepo.SaveBet(context.Background(), "723e4567-e89b-12d3-a456-426614174000", bet2, player2)
 				if err != nil {
 					t.Errorf("Failed to save bet2: %v", err)
 				}
@@ -451,5 +452,54 @@ func TestBetRepository_Integration(t *testing.T) {
 				require.Equal(t, -100, points)
 			})
 		})
+
+		t.Run("Claim Scoring Once", func(t *testing.T) {
+			testDB.withTransaction(t, func(tx *sql.Tx) {
+				_, err := tx.Exec(`
+					INSERT INTO game (id, season_year, competition_name, status, game_name)
+					VALUES ('aaae4567-e89b-12d3-a456-426614174000', '2024', 'Test League', 'started', 'Test Game')`)
+				require.NoError(t, err)
+
+				_, err = tx.Exec(`
+					INSERT INTO player (id, name)
+					VALUES ('aaae4567-e89b-12d3-a456-426614174001', 'ScoredPlayer')`)
+				require.NoError(t, err)
+
+				_, err = tx.Exec(`
+					INSERT INTO match (id, local_id, home_team_id, away_team_id, match_date, match_status, season_code, competition_code, matchday)
+					VALUES ('aaae4567-e89b-12d3-a456-426614174002', 'Test League-2024-Scored-FC-0', 'Scored', 'FC', $1, 'finished', '2024', 'Test League', 0)`, betTestTime)
+				require.NoError(t, err)
+
+				betRepo := NewPostgresBetRepository(tx)
+				ctx := context.Background()
+				gameID := "aaae4567-e89b-12d3-a456-426614174000"
+				match := models.NewSeasonMatch("Scored", "FC", "2024", "Test League", betTestTime, 0)
+				player := models.NewSimplePlayer("aaae4567-e89b-12d3-a456-426614174001", "ScoredPlayer")
+
+				scoring := models.NewMatchScoring(gameID, match.Id(), models.InitialScoringVersion, betTestTime)
+				claimed, err := betRepo.ClaimScoring(ctx, scoring)
+				require.NoError(t, err)
+				require.True(t, claimed)
+				require.NoError(t, betRepo.SaveScores(ctx, gameID, match, []models.Player{player}, map[string]int{player.GetID(): 300}))
+
+				// The same scoring is only claimed once
+				claimed, err = betRepo.ClaimScoring(ctx, scoring)
+				require.NoError(t, err)
+				require.False(t, claimed)
+
+				// Scoring the match again takes the next version
+				claimed, err = betRepo.ClaimScoring(ctx, models.NewMatchScoring(gameID, match.Id(), models.InitialScoringVersion+1, betTestTime))
+				require.NoError(t, err)
+				require.True(t, claimed)
+
+				versions, err := betRepo.GetScoringVersions(ctx, gameID)
+				require.NoError(t, err)
+				require.Equal(t, map[string]int{match.Id(): 2}, versions)
+
+				scores, err := betRepo.GetScoresByMatchAndPlayer(gameID)
+				require.NoError(t, err)
+				require.Equal(t, 300, scores[match.Id()][player.GetID()])
+			})
+		})
 	}, 10*time.Second)
 }
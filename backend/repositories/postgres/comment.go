@@ -0,0 +1,235 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"ligain/backend/models"
+	"ligain/backend/repositories"
+)
+
+type PostgresCommentRepository struct {
+	db *sql.DB
+}
+
+// executor returns the appropriate DBExecutor (transaction or db connection).
+func (r *PostgresCommentRepository) executor(ctx context.Context) DBExecutor {
+	if tx := TxFromContext(ctx); tx != nil {
+		return tx
+	}
+	return r.db
+}
+
+func NewPostgresCommentRepository(db *sql.DB) repositories.CommentRepository {
+	return &PostgresCommentRepository{db: db}
+}
+
+func (r *PostgresCommentRepository) CreateComment(ctx context.Context, comment *models.MatchComment) error {
+	query := `
+		INSERT INTO match_comment (game_id, match_local_id, player_id, content, mentions_bet, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id
+	`
+
+	err := r.executor(ctx).QueryRowContext(ctx, query,
+		comment.GameID,
+		comment.MatchID,
+		comment.PlayerID,
+		comment.Content,
+		comment.MentionsBet,
+		comment.CreatedAt,
+	).Scan(&comment.ID)
+	if err != nil {
+		return fmt.Errorf("error creating comment: %v", err)
+	}
+
+	return nil
+}
+
+func (r *PostgresCommentRepository) GetComment(ctx context.Context, commentID string) (*models.MatchComment, error) {
+	query := `
+		SELECT c.id, c.game_id, c.match_local_id, c.player_id, p.name, c.content, c.mentions_bet, c.created_at
+		FROM match_comment c
+		JOIN player p ON c.player_id = p.id
+		WHERE c.id = $1
+	`
+
+	var comment models.MatchComment
+	err := r.executor(ctx).QueryRowContext(ctx, query, commentID).Scan(
+		&comment.ID,
+		&comment.GameID,
+		&comment.MatchID,
+		&comment.PlayerID,
+		&comment.PlayerName,
+		&comment.Content,
+		&comment.MentionsBet,
+		&comment.CreatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repositories.ErrCommentNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting comment: %v", err)
+	}
+
+	return &comment, nil
+}
+
+func (r *PostgresCommentRepository) GetComments(ctx context.Context, gameID, matchID string) ([]*models.MatchComment, error) {
+	query := `
+		SELECT c.id, c.game_id, c.match_local_id, c.player_id, p.name, c.content, c.mentions_bet, c.created_at
+		FROM match_comment c
+		JOIN player p ON c.player_id = p.id
+		WHERE c.game_id = $1 AND c.match_local_id = $2
+		ORDER BY c.created_at, c.id
+	`
+
+	return r.queryComments(ctx, query, gameID, matchID)
+}
+
+func (r *PostgresCommentRepository) GetPlayerComments(ctx context.Context, playerID string) ([]*models.MatchComment, error) {
+	query := `
+		SELECT c.id, c.game_id, c.match_local_id, c.player_id, p.name, c.content, c.mentions_bet, c.created_at
+		FROM match_comment c
+		JOIN player p ON c.player_id = p.id
+		WHERE c.player_id = $1
+		ORDER BY c.created_at, c.id
+	`
+
+	return r.queryComments(ctx, query, playerID)
+}
+
+// queryComments runs a query selecting comments joined with the name of their player
+func (r *PostgresCommentRepository) queryComments(ctx context.Context, query string, args ...interface{}) ([]*models.MatchComment, error) {
+	rows, err := r.executor(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error getting comments: %v", err)
+	}
+	defer rows.Close()
+
+	comments := make([]*models.MatchComment, 0)
+	for rows.Next() {
+		var comment models.MatchComment
+		if err := rows.Scan(
+			&comment.ID,
+			&comment.GameID,
+			&comment.MatchID,
+			&comment.PlayerID,
+			&comment.PlayerName,
+			&comment.Content,
+			&comment.MentionsBet,
+			&comment.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("error scanning comment: %v", err)
+		}
+		comments = append(comments, &comment)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating comments: %v", err)
+	}
+
+	return comments, nil
+}
+
+func (r *PostgresCommentRepository) DeleteComment(ctx context.Context, commentID string) error {
+	result, err := r.executor(ctx).ExecContext(ctx, `DELETE FROM match_comment WHERE id = $1`, commentID)
+	if err != nil {
+		return fmt.Errorf("error deleting comment: %v", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error getting rows affected: %v", err)
+	}
+	if rowsAffected == 0 {
+		return repositories.ErrCommentNotFound
+	}
+
+	return nil
+}
+
+func (r *PostgresCommentRepository) AddReaction(ctx context.Context, reaction *models.MatchReaction) error {
+	query := `
+		INSERT INTO match_reaction (game_id, match_local_id, player_id, emoji, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (game_id, match_local_id, player_id, emoji) DO NOTHING
+	`
+
+	_, err := r.executor(ctx).ExecContext(ctx, query,
+		reaction.GameID,
+		reaction.MatchID,
+		reaction.PlayerID,
+		reaction.Emoji,
+		reaction.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("error adding reaction: %v", err)
+	}
+
+	return nil
+}
+
+func (r *PostgresCommentRepository) RemoveReaction(ctx context.Context, gameID, matchID, playerID, emoji string) error {
+	query := `
+		DELETE FROM match_reaction
+		WHERE game_id = $1 AND match_local_id = $2 AND player_id = $3 AND emoji = $4
+	`
+
+	_, err := r.executor(ctx).ExecContext(ctx, query, gameID, matchID, playerID, emoji)
+	if err != nil {
+		return fmt.Errorf("error removing reaction: %v", err)
+	}
+
+	return nil
+}
+
+func (r *PostgresCommentRepository) GetReactions(ctx context.Context, gameID, matchID string) ([]*models.MatchReaction, error) {
+	query := `
+		SELECT game_id, match_local_id, player_id, emoji, created_at
+		FROM match_reaction
+		WHERE game_id = $1 AND match_local_id = $2
+		ORDER BY created_at
+	`
+
+	return r.queryReactions(ctx, query, gameID, matchID)
+}
+
+func (r *PostgresCommentRepository) GetPlayerReactions(ctx context.Context, playerID string) ([]*models.MatchReaction, error) {
+	query := `
+		SELECT game_id, match_local_id, player_id, emoji, created_at
+		FROM match_reaction
+		WHERE player_id = $1
+		ORDER BY created_at
+	`
+
+	return r.queryReactions(ctx, query, playerID)
+}
+
+// queryReactions runs a query selecting reactions
+func (r *PostgresCommentRepository) queryReactions(ctx context.Context, query string, args ...interface{}) ([]*models.MatchReaction, error) {
+	rows, err := r.executor(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error getting reactions: %v", err)
+	}
+	defer rows.Close()
+
+	reactions := make([]*models.MatchReaction, 0)
+	for rows.Next() {
+		var reaction models.MatchReaction
+		if err := rows.Scan(
+			&reaction.GameID,
+			&reaction.MatchID,
+			&reaction.PlayerID,
+			&reaction.Emoji,
+			&reaction.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("error scanning reaction: %v", err)
+		}
+		reactions = append(reactions, &reaction)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating reactions: %v", err)
+	}
+
+	return reactions, nil
+}
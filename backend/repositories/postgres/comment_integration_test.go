@@ -0,0 +1,74 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"ligain/backend/models"
+	"ligain/backend/repositories"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommentRepository_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	runTestWithTimeout(t, func(t *testing.T) {
+		testDB := setupTestDB(t)
+		defer testDB.Close()
+
+		commentRepo := NewPostgresCommentRepository(testDB.db)
+		ctx := context.Background()
+
+		gameID := "123e4567-e89b-12d3-a456-426614174201"
+		playerID := "123e4567-e89b-12d3-a456-426614174202"
+		_, err := testDB.db.Exec(`INSERT INTO game (id, season_year, competition_name, status, game_name) VALUES ($1, $2, $3, $4, $5)`, gameID, "2024", "Test League", "not started", "Test Game")
+		require.NoError(t, err)
+		_, err = testDB.db.Exec(`INSERT INTO player (id, name) VALUES ($1, $2)`, playerID, "Commenter")
+		require.NoError(t, err)
+
+		t.Run("Create, Get and Delete Comments", func(t *testing.T) {
+			createdAt := time.Now().UTC().Truncate(time.Second)
+			first := &models.MatchComment{GameID: gameID, MatchID: "match-1", PlayerID: playerID, Content: "2-1 for sure", MentionsBet: true, CreatedAt: createdAt}
+			second := &models.MatchComment{GameID: gameID, MatchID: "match-1", PlayerID: playerID, Content: "Let's go", CreatedAt: createdAt.Add(time.Minute)}
+			require.NoError(t, commentRepo.CreateComment(ctx, first))
+			require.NoError(t, commentRepo.CreateComment(ctx, second))
+			require.NotEmpty(t, first.ID)
+
+			comments, err := commentRepo.GetComments(ctx, gameID, "match-1")
+			require.NoError(t, err)
+			require.Len(t, comments, 2)
+			require.Equal(t, first.ID, comments[0].ID)
+			require.Equal(t, "Commenter", comments[0].PlayerName)
+			require.True(t, comments[0].MentionsBet)
+			require.True(t, createdAt.Equal(comments[0].CreatedAt))
+
+			comment, err := commentRepo.GetComment(ctx, second.ID)
+			require.NoError(t, err)
+			require.Equal(t, "Let's go", comment.Content)
+
+			require.NoError(t, commentRepo.DeleteComment(ctx, second.ID))
+			_, err = commentRepo.GetComment(ctx, second.ID)
+			require.ErrorIs(t, err, repositories.ErrCommentNotFound)
+		})
+
+		t.Run("Add and Remove Reactions", func(t *testing.T) {
+			reaction := &models.MatchReaction{GameID: gameID, MatchID: "match-1", PlayerID: playerID, Emoji: "🔥", CreatedAt: time.Now()}
+			require.NoError(t, commentRepo.AddReaction(ctx, reaction))
+			require.NoError(t, commentRepo.AddReaction(ctx, reaction))
+
+			reactions, err := commentRepo.GetReactions(ctx, gameID, "match-1")
+			require.NoError(t, err)
+			require.Len(t, reactions, 1)
+			require.Equal(t, "🔥", reactions[0].Emoji)
+
+			require.NoError(t, commentRepo.RemoveReaction(ctx, gameID, "match-1", playerID, "🔥"))
+			reactions, err = commentRepo.GetReactions(ctx, gameID, "match-1")
+			require.NoError(t, err)
+			require.Empty(t, reactions)
+		})
+	}, 30*time.Second)
+}
@@ -0,0 +1,161 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"ligain/backend/models"
+	"ligain/backend/repositories"
+	"time"
+)
+
+type PostgresDataExportRepository struct {
+	db *sql.DB
+}
+
+// executor returns the appropriate DBExecutor (transaction or db connection).
+func (r *PostgresDataExportRepository) executor(ctx context.Context) DBExecutor {
+	if tx := TxFromContext(ctx); tx != nil {
+		return tx
+	}
+	return r.db
+}
+
+func NewPostgresDataExportRepository(db *sql.DB) repositories.DataExportRepository {
+	return &PostgresDataExportRepository{db: db}
+}
+
+// dataExportColumns are the columns read by scanDataExport, in order
+const dataExportColumns = `id, player_id, status, object_key, created_at, completed_at, expires_at`
+
+func (r *PostgresDataExportRepository) CreateExport(ctx context.Context, export *models.DataExport) error {
+	query := `
+		INSERT INTO data_export (player_id, status, object_key, created_at, completed_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id
+	`
+
+	err := r.executor(ctx).QueryRowContext(ctx, query,
+		export.PlayerID,
+		string(export.Status),
+		nullIfEmpty(export.ObjectKey),
+		export.CreatedAt,
+		export.CompletedAt,
+		export.ExpiresAt,
+	).Scan(&export.ID)
+	if err != nil {
+		return fmt.Errorf("error creating data export: %v", err)
+	}
+
+	return nil
+}
+
+func (r *PostgresDataExportRepository) GetExport(ctx context.Context, exportID string) (*models.DataExport, error) {
+	query := `SELECT ` + dataExportColumns + ` FROM data_export WHERE id = $1`
+
+	export, err := scanDataExport(r.executor(ctx).QueryRowContext(ctx, query, exportID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repositories.ErrDataExportNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting data export: %v", err)
+	}
+
+	return export, nil
+}
+
+func (r *PostgresDataExportRepository) GetLatestExport(ctx context.Context, playerID string) (*models.DataExport, error) {
+	query := `SELECT ` + dataExportColumns + ` FROM data_export WHERE player_id = $1 ORDER BY created_at DESC LIMIT 1`
+
+	export, err := scanDataExport(r.executor(ctx).QueryRowContext(ctx, query, playerID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting latest data export: %v", err)
+	}
+
+	return export, nil
+}
+
+func (r *PostgresDataExportRepository) UpdateExport(ctx context.Context, export *models.DataExport) error {
+	query := `
+		UPDATE data_export
+		SET status = $2, object_key = $3, completed_at = $4, expires_at = $5
+		WHERE id = $1
+	`
+
+	result, err := r.executor(ctx).ExecContext(ctx, query,
+		export.ID,
+		string(export.Status),
+		nullIfEmpty(export.ObjectKey),
+		export.CompletedAt,
+		export.ExpiresAt,
+	)
+	if err != nil {
+		return fmt.Errorf("error updating data export: %v", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error getting rows affected: %v", err)
+	}
+	if rowsAffected == 0 {
+		return repositories.ErrDataExportNotFound
+	}
+
+	return nil
+}
+
+func (r *PostgresDataExportRepository) GetExpiredExports(ctx context.Context, before time.Time) ([]*models.DataExport, error) {
+	query := `SELECT ` + dataExportColumns + ` FROM data_export WHERE expires_at <= $1 ORDER BY expires_at`
+
+	rows, err := r.executor(ctx).QueryContext(ctx, query, before)
+	if err != nil {
+		return nil, fmt.Errorf("error getting expired data exports: %v", err)
+	}
+	defer rows.Close()
+
+	exports := make([]*models.DataExport, 0)
+	for rows.Next() {
+		export, err := scanDataExport(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning data export: %v", err)
+		}
+		exports = append(exports, export)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating data exports: %v", err)
+	}
+
+	return exports, nil
+}
+
+func (r *PostgresDataExportRepository) DeleteExport(ctx context.Context, exportID string) error {
+	if _, err := r.executor(ctx).ExecContext(ctx, `DELETE FROM data_export WHERE id = $1`, exportID); err != nil {
+		return fmt.Errorf("error deleting data export: %v", err)
+	}
+	return nil
+}
+
+// scanDataExport reads a row selected with dataExportColumns
+func scanDataExport(row interface{ Scan(dest ...any) error }) (*models.DataExport, error) {
+	var export models.DataExport
+	var status string
+	var objectKey sql.NullString
+	if err := row.Scan(
+		&export.ID,
+		&export.PlayerID,
+		&status,
+		&objectKey,
+		&export.CreatedAt,
+		&export.CompletedAt,
+		&export.ExpiresAt,
+	); err != nil {
+		return nil, err
+	}
+	export.Status = models.DataExportStatus(status)
+	export.ObjectKey = objectKey.String
+
+	return &export, nil
+}
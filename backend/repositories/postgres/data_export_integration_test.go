@@ -0,0 +1,82 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"ligain/backend/models"
+	"ligain/backend/repositories"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDataExportRepository_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	runTestWithTimeout(t, func(t *testing.T) {
+		testDB := setupTestDB(t)
+		defer testDB.Close()
+
+		exportRepo := NewPostgresDataExportRepository(testDB.db)
+		ctx := context.Background()
+
+		playerID := "123e4567-e89b-12d3-a456-426614174501"
+		_, err := testDB.db.Exec(`INSERT INTO player (id, name) VALUES ($1, $2)`, playerID, "Exporter")
+		require.NoError(t, err)
+
+		now := time.Now().UTC().Truncate(time.Second)
+
+		t.Run("Create, Complete and Expire", func(t *testing.T) {
+			latest, err := exportRepo.GetLatestExport(ctx, playerID)
+			require.NoError(t, err)
+			require.Nil(t, latest)
+
+			export := &models.DataExport{PlayerID: playerID, Status: models.DataExportPending, CreatedAt: now}
+			require.NoError(t, exportRepo.CreateExport(ctx, export))
+			require.NotEmpty(t, export.ID)
+
+			stored, err := exportRepo.GetExport(ctx, export.ID)
+			require.NoError(t, err)
+			require.Equal(t, models.DataExportPending, stored.Status)
+			require.Empty(t, stored.ObjectKey)
+			require.Nil(t, stored.ExpiresAt)
+
+			completedAt := now.Add(time.Minute)
+			expiresAt := now.Add(time.Hour)
+			export.Status = models.DataExportReady
+			export.ObjectKey = "exports/" + playerID + "/" + export.ID + ".zip"
+			export.CompletedAt = &completedAt
+			export.ExpiresAt = &expiresAt
+			require.NoError(t, exportRepo.UpdateExport(ctx, export))
+
+			latest, err = exportRepo.GetLatestExport(ctx, playerID)
+			require.NoError(t, err)
+			require.NotNil(t, latest)
+			require.Equal(t, export.ID, latest.ID)
+			require.Equal(t, models.DataExportReady, latest.Status)
+			require.Equal(t, export.ObjectKey, latest.ObjectKey)
+			require.True(t, expiresAt.Equal(*latest.ExpiresAt))
+
+			expired, err := exportRepo.GetExpiredExports(ctx, now)
+			require.NoError(t, err)
+			require.Empty(t, expired)
+
+			expired, err = exportRepo.GetExpiredExports(ctx, expiresAt)
+			require.NoError(t, err)
+			require.Len(t, expired, 1)
+			require.Equal(t, export.ID, expired[0].ID)
+
+			require.NoError(t, exportRepo.DeleteExport(ctx, export.ID))
+			_, err = exportRepo.GetExport(ctx, export.ID)
+			require.ErrorIs(t, err, repositories.ErrDataExportNotFound)
+		})
+
+		t.Run("Update Missing Export", func(t *testing.T) {
+			err := exportRepo.UpdateExport(ctx, &models.DataExport{ID: "123e4567-e89b-12d3-a456-426614174599", Status: models.DataExportFailed})
+			require.ErrorIs(t, err, repositories.ErrDataExportNotFound)
+		})
+	}, 30*time.Second)
+}
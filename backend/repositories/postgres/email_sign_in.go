@@ -0,0 +1,139 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"ligain/backend/models"
+	"ligain/backend/repositories"
+	"time"
+)
+
+type PostgresEmailSignInRepository struct {
+	db *sql.DB
+}
+
+// executor returns the appropriate DBExecutor (transaction or db connection).
+func (r *PostgresEmailSignInRepository) executor(ctx context.Context) DBExecutor {
+	if tx := TxFromContext(ctx); tx != nil {
+		return tx
+	}
+	return r.db
+}
+
+func NewPostgresEmailSignInRepository(db *sql.DB) repositories.EmailSignInRepository {
+	return &PostgresEmailSignInRepository{db: db}
+}
+
+// emailSignInCodeColumns are the columns read by scanEmailSignInCode, in order
+const emailSignInCodeColumns = `id, email, code_hash, link_token_hash, attempts, created_at, expires_at, used_at`
+
+func (r *PostgresEmailSignInRepository) CreateSignInCode(ctx context.Context, code *models.EmailSignInCode) error {
+	query := `
+		INSERT INTO email_sign_in_code (email, code_hash, link_token_hash, attempts, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id
+	`
+
+	err := r.executor(ctx).QueryRowContext(ctx, query,
+		code.Email,
+		code.CodeHash,
+		code.LinkTokenHash,
+		code.Attempts,
+		code.CreatedAt,
+		code.ExpiresAt,
+	).Scan(&code.ID)
+	if err != nil {
+		return fmt.Errorf("error creating email sign-in code: %v", err)
+	}
+
+	return nil
+}
+
+func (r *PostgresEmailSignInRepository) GetSignInCodesSince(ctx context.Context, email string, since time.Time) ([]*models.EmailSignInCode, error) {
+	query := `SELECT ` + emailSignInCodeColumns + ` FROM email_sign_in_code WHERE email = $1 AND created_at > $2 ORDER BY created_at`
+
+	rows, err := r.executor(ctx).QueryContext(ctx, query, email, since)
+	if err != nil {
+		return nil, fmt.Errorf("error getting email sign-in codes: %v", err)
+	}
+	defer rows.Close()
+
+	codes := make([]*models.EmailSignInCode, 0)
+	for rows.Next() {
+		code, err := scanEmailSignInCode(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning email sign-in code: %v", err)
+		}
+		codes = append(codes, code)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating email sign-in codes: %v", err)
+	}
+
+	return codes, nil
+}
+
+func (r *PostgresEmailSignInRepository) GetSignInCodeByLinkHash(ctx context.Context, linkTokenHash string) (*models.EmailSignInCode, error) {
+	query := `SELECT ` + emailSignInCodeColumns + ` FROM email_sign_in_code WHERE link_token_hash = $1`
+
+	code, err := scanEmailSignInCode(r.executor(ctx).QueryRowContext(ctx, query, linkTokenHash))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting email sign-in code by link: %v", err)
+	}
+
+	return code, nil
+}
+
+func (r *PostgresEmailSignInRepository) IncrementAttempts(ctx context.Context, codeID string) error {
+	if _, err := r.executor(ctx).ExecContext(ctx, `UPDATE email_sign_in_code SET attempts = attempts + 1 WHERE id = $1`, codeID); err != nil {
+		return fmt.Errorf("error incrementing email sign-in attempts: %v", err)
+	}
+	return nil
+}
+
+func (r *PostgresEmailSignInRepository) MarkSignInCodeUsed(ctx context.Context, codeID string, usedAt time.Time) (bool, error) {
+	result, err := r.executor(ctx).ExecContext(ctx,
+		`UPDATE email_sign_in_code SET used_at = $2 WHERE id = $1 AND used_at IS NULL`,
+		codeID, usedAt,
+	)
+	if err != nil {
+		return false, fmt.Errorf("error marking email sign-in code used: %v", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("error getting rows affected: %v", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+func (r *PostgresEmailSignInRepository) DeleteExpiredSignInCodes(ctx context.Context, before time.Time) error {
+	if _, err := r.executor(ctx).ExecContext(ctx, `DELETE FROM email_sign_in_code WHERE expires_at <= $1`, before); err != nil {
+		return fmt.Errorf("error deleting expired email sign-in codes: %v", err)
+	}
+	return nil
+}
+
+// scanEmailSignInCode reads a row selected with emailSignInCodeColumns
+func scanEmailSignInCode(row interface{ Scan(dest ...any) error }) (*models.EmailSignInCode, error) {
+	var code models.EmailSignInCode
+	if err := row.Scan(
+		&code.ID,
+		&code.Email,
+		&code.CodeHash,
+		&code.LinkTokenHash,
+		&code.Attempts,
+		&code.CreatedAt,
+		&code.ExpiresAt,
+		&code.UsedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	return &code, nil
+}
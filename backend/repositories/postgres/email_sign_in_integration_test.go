@@ -0,0 +1,92 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"ligain/backend/models"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmailSignInRepository_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	runTestWithTimeout(t, func(t *testing.T) {
+		testDB := setupTestDB(t)
+		defer testDB.Close()
+
+		repo := NewPostgresEmailSignInRepository(testDB.db)
+		ctx := context.Background()
+		now := time.Now().UTC().Truncate(time.Second)
+
+		older := &models.EmailSignInCode{
+			Email:         "friend@example.com",
+			CodeHash:      "older-code-hash",
+			LinkTokenHash: "older-link-hash",
+			CreatedAt:     now.Add(-20 * time.Minute),
+			ExpiresAt:     now.Add(-5 * time.Minute),
+		}
+		latest := &models.EmailSignInCode{
+			Email:         "friend@example.com",
+			CodeHash:      "latest-code-hash",
+			LinkTokenHash: "latest-link-hash",
+			CreatedAt:     now,
+			ExpiresAt:     now.Add(15 * time.Minute),
+		}
+		require.NoError(t, repo.CreateSignInCode(ctx, older))
+		require.NoError(t, repo.CreateSignInCode(ctx, latest))
+		require.NotEmpty(t, latest.ID)
+
+		t.Run("Lookups", func(t *testing.T) {
+			codes, err := repo.GetSignInCodesSince(ctx, "friend@example.com", now.Add(-time.Hour))
+			require.NoError(t, err)
+			require.Len(t, codes, 2)
+			require.Equal(t, older.ID, codes[0].ID)
+			require.Equal(t, latest.ID, codes[1].ID)
+
+			codes, err = repo.GetSignInCodesSince(ctx, "friend@example.com", now.Add(-time.Minute))
+			require.NoError(t, err)
+			require.Len(t, codes, 1)
+
+			byLink, err := repo.GetSignInCodeByLinkHash(ctx, "latest-link-hash")
+			require.NoError(t, err)
+			require.NotNil(t, byLink)
+			require.Equal(t, "latest-code-hash", byLink.CodeHash)
+			require.Nil(t, byLink.UsedAt)
+
+			unknown, err := repo.GetSignInCodeByLinkHash(ctx, "unknown")
+			require.NoError(t, err)
+			require.Nil(t, unknown)
+		})
+
+		t.Run("Attempts And Use", func(t *testing.T) {
+			require.NoError(t, repo.IncrementAttempts(ctx, latest.ID))
+			require.NoError(t, repo.IncrementAttempts(ctx, latest.ID))
+
+			used, err := repo.MarkSignInCodeUsed(ctx, latest.ID, now)
+			require.NoError(t, err)
+			require.True(t, used)
+			used, err = repo.MarkSignInCodeUsed(ctx, latest.ID, now)
+			require.NoError(t, err)
+			require.False(t, used)
+
+			stored, err := repo.GetSignInCodeByLinkHash(ctx, "latest-link-hash")
+			require.NoError(t, err)
+			require.Equal(t, 2, stored.Attempts)
+			require.NotNil(t, stored.UsedAt)
+		})
+
+		t.Run("Delete Expired", func(t *testing.T) {
+			require.NoError(t, repo.DeleteExpiredSignInCodes(ctx, now))
+
+			codes, err := repo.GetSignInCodesSince(ctx, "friend@example.com", now.Add(-time.Hour))
+			require.NoError(t, err)
+			require.Len(t, codes, 1)
+			require.Equal(t, latest.ID, codes[0].ID)
+		})
+	}, 30*time.Second)
+}
@@ -1,39 +1,48 @@
 package postgres
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"ligain/backend/models"
 	"ligain/backend/repositories"
 	rules "ligain/backend/rules"
+	"strings"
 
 	log "github.com/sirupsen/logrus"
 )
 
+// gameSnapshotInterval is the number of events recorded for a game before a new snapshot of it is taken
+const gameSnapshotInterval = 100
+
 type PostgresGameRepository struct {
 	*PostgresRepository
 	matchRepo repositories.MatchRepository
 	betRepo   repositories.BetRepository
-	cache     repositories.GameRepository // In-memory cache
+	// eventRepo holds the history of the games, they're rebuilt from it when it exists
+	eventRepo repositories.GameEventRepository
+	cache     *repositories.InMemoryGameRepository // In-memory cache
 }
 
 func NewPostgresGameRepository(db *sql.DB) (repositories.GameRepository, error) {
 	baseRepo := NewPostgresRepository(db)
 	matchRepo := NewPostgresMatchRepository(db)
 	betRepo := NewPostgresBetRepository(db)
+	eventRepo := NewPostgresGameEventRepository(db)
 	cache := repositories.NewInMemoryGameRepository()
 	return &PostgresGameRepository{
 		PostgresRepository: baseRepo,
 		matchRepo:          matchRepo,
 		betRepo:            betRepo,
+		eventRepo:          eventRepo,
 		cache:              cache,
 	}, nil
 }
 
 func (r *PostgresGameRepository) CreateGame(game models.Game) (string, error) {
 	query := `
-		INSERT INTO game (season_year, competition_name, status, game_name)
-		VALUES ($1, $2, $3, $4)
+		INSERT INTO game (season_year, competition_name, status, game_name, bet_cutoff_minutes, bet_cutoff_matchday, tiebreakers)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
 		RETURNING id`
 
 	var id string
@@ -43,6 +52,9 @@ func (r *PostgresGameRepository) CreateGame(game models.Game) (string, error) {
 		game.GetCompetitionName(),
 		game.GetGameStatus(),
 		game.GetName(),
+		game.GetBetCutoff().Minutes,
+		game.GetBetCutoff().Matchday,
+		strings.Join(game.GetTiebreakers(), ","),
 	).Scan(&id)
 
 	if err != nil {
@@ -64,10 +76,78 @@ func (r *PostgresGameRepository) GetGame(gameId string) (models.Game, error) {
 		return cachedGame, nil
 	}
 
-	// Cache miss, load from database
+	// Cache miss, rebuild the game from its history, or load it from the tables when it has none
 	log.WithField("gameId", gameId).Debug("Game not found in cache, loading from database")
 
-	seasonYear, competitionName, name, status, err := r.getGameDetails(gameId)
+	// A history which can't be replayed is an error, the tables may hold a different game
+	gameImpl, err := r.replayGame(context.Background(), gameId)
+	if err != nil {
+		return nil, fmt.Errorf("error rebuilding game %s from its history: %v", gameId, err)
+	}
+	if gameImpl == nil {
+		gameImpl, err = r.loadGameWithSnapshot(context.Background(), gameId)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Store in cache for future requests
+	if err := r.cache.SaveWithId(gameId, gameImpl); err != nil {
+		log.WithError(err).Warn("Failed to cache game")
+	}
+
+	return gameImpl, nil
+}
+
+// replayGame rebuilds a game from its latest snapshot and the events recorded after it.
+// Returns nil when the game has no snapshot yet
+func (r *PostgresGameRepository) replayGame(ctx context.Context, gameId string) (models.Game, error) {
+	snapshot, err := r.eventRepo.GetLatestSnapshot(ctx, gameId, 0)
+	if err != nil || snapshot == nil {
+		return nil, err
+	}
+	events, err := r.eventRepo.GetEvents(ctx, gameId, snapshot.Sequence, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	// The history only holds the ids of the players, their names are the current ones
+	gamePlayers, err := r.getGamePlayers(gameId)
+	if err != nil {
+		return nil, err
+	}
+	players := make(map[string]models.Player)
+	for _, player := range gamePlayers {
+		players[player.GetID()] = player
+	}
+
+	game, err := rules.ReplayGame(snapshot.State, events, players, &rules.ScorerOriginal{})
+	if err != nil {
+		return nil, err
+	}
+	return game, nil
+}
+
+// loadGameWithSnapshot loads a game from the tables, and takes a snapshot of it at the end of its history,
+// so that the game is rebuilt from its history from now on
+func (r *PostgresGameRepository) loadGameWithSnapshot(ctx context.Context, gameId string) (models.Game, error) {
+	gameImpl, err := r.loadGame(gameId)
+	if err != nil {
+		return nil, err
+	}
+	lastSequence, err := r.eventRepo.GetLastSequence(ctx, gameId)
+	if err == nil {
+		err = r.snapshotGame(ctx, gameId, gameImpl, lastSequence)
+	}
+	if err != nil {
+		log.WithError(err).Warnf("Failed to take a snapshot of game %s", gameId)
+	}
+	return gameImpl, nil
+}
+
+// loadGame builds a game from the game, match, bet, player and score tables
+func (r *PostgresGameRepository) loadGame(gameId string) (models.Game, error) {
+	seasonYear, competitionName, name, status, cutoff, tiebreakerNames, err := r.getGameDetails(gameId)
 	if err != nil {
 		log.Errorf("error getting game details: %v", err)
 		return nil, err
@@ -137,11 +217,18 @@ func (r *PostgresGameRepository) GetGame(gameId string) (models.Game, error) {
 	if status == "finished" {
 		gameImpl.Finish()
 	}
-
-	// Store in cache for future requests
-	if err := r.cache.SaveWithId(gameId, gameImpl); err != nil {
-		log.WithError(err).Warn("Failed to cache game")
+	if game, ok := gameImpl.(*rules.GameImpl); ok {
+		game.SetBetCutoff(cutoff)
+		if tiebreakerNames != nil {
+			tiebreakers, err := rules.ParseTiebreakers(tiebreakerNames)
+			if err != nil {
+				return nil, fmt.Errorf("invalid tiebreakers for game %s: %v", gameId, err)
+			}
+			game.SetTiebreakers(tiebreakers)
+		}
 	}
+	// Loading the game isn't a change of it
+	dropPendingEvents(gameImpl)
 
 	return gameImpl, nil
 }
@@ -186,29 +273,42 @@ func (r *PostgresGameRepository) GetAllGames() (map[string]models.Game, error) {
 	return games, nil
 }
 
-func (r *PostgresGameRepository) getGameDetails(gameId string) (string, string, string, string, error) {
+// getGameDetails returns the settings of a game. The tiebreakers are nil for the games keeping the default ones
+func (r *PostgresGameRepository) getGameDetails(gameId string) (string, string, string, string, models.BetCutoff, []string, error) {
 	query := `
-		SELECT g.season_year, g.competition_name, g.game_name, g.status
+		SELECT g.season_year, g.competition_name, g.game_name, g.status, g.bet_cutoff_minutes, g.bet_cutoff_matchday, g.tiebreakers
 		FROM game g
 		WHERE g.id = $1::uuid`
 
 	var seasonYear, competitionName, name, status string
+	var cutoff models.BetCutoff
+	var tiebreakers sql.NullString
 	err := r.db.QueryRow(query, gameId).Scan(
 		&seasonYear,
 		&competitionName,
 		&name,
 		&status,
+		&cutoff.Minutes,
+		&cutoff.Matchday,
+		&tiebreakers,
 	)
 
 	if err == sql.ErrNoRows {
 		log.Errorf("The postgres query returned no rows for game %s", gameId)
-		return "", "", "", "", fmt.Errorf("game %s not found", gameId)
+		return "", "", "", "", models.BetCutoff{}, nil, fmt.Errorf("game %s not found", gameId)
 	}
 	if err != nil {
-		return "", "", "", "", fmt.Errorf("error getting game: %v", err)
+		return "", "", "", "", models.BetCutoff{}, nil, fmt.Errorf("error getting game: %v", err)
 	}
 
-	return seasonYear, competitionName, name, status, nil
+	var tiebreakerNames []string
+	if tiebreakers.Valid {
+		tiebreakerNames = []string{}
+		if tiebreakers.String != "" {
+			tiebreakerNames = strings.Split(tiebreakers.String, ",")
+		}
+	}
+	return seasonYear, competitionName, name, status, cutoff, tiebreakerNames, nil
 }
 
 func (r *PostgresGameRepository) getMatchesAndBets(gameId string) ([]models.Match, []models.Match, map[string]map[string]*models.Bet, []models.Player, error) {
@@ -344,15 +444,23 @@ func (r *PostgresGameRepository) createBet(match models.Match, predictedHomeGoal
 	return models.NewBet(match, predictedHomeGoals, predictedAwayGoals)
 }
 
+// SaveWithId saves the game and records its pending events
 func (r *PostgresGameRepository) SaveWithId(gameId string, game models.Game) error {
+	if err := r.RecordEvents(context.Background(), gameId, game); err != nil {
+		return err
+	}
+
 	query := `
-		INSERT INTO game (id, season_year, competition_name, status, game_name)
-		VALUES ($1, $2, $3, $4, $5)
+		INSERT INTO game (id, season_year, competition_name, status, game_name, bet_cutoff_minutes, bet_cutoff_matchday, tiebreakers)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 		ON CONFLICT (id) DO UPDATE SET
 			season_year = EXCLUDED.season_year,
 			competition_name = EXCLUDED.competition_name,
 			status = EXCLUDED.status,
 			game_name = EXCLUDED.game_name,
+			bet_cutoff_minutes = EXCLUDED.bet_cutoff_minutes,
+			bet_cutoff_matchday = EXCLUDED.bet_cutoff_matchday,
+			tiebreakers = EXCLUDED.tiebreakers,
 			updated_at = NOW()`
 
 	_, err := r.db.Exec(
@@ -362,6 +470,9 @@ func (r *PostgresGameRepository) SaveWithId(gameId string, game models.Game) err
 		game.GetCompetitionName(),
 		game.GetGameStatus(),
 		game.GetName(),
+		game.GetBetCutoff().Minutes,
+		game.GetBetCutoff().Matchday,
+		strings.Join(game.GetTiebreakers(), ","),
 	)
 
 	if err != nil {
@@ -408,8 +519,107 @@ func (r *PostgresGameRepository) getGamePlayers(gameId string) ([]models.Player,
 	return players, nil
 }
 
+// EvictGame drops the cached state of a game, so that it's loaded again from the database
+func (r *PostgresGameRepository) EvictGame(gameId string) {
+	r.cache.DeleteGame(gameId)
+}
+
 // ClearCache clears the in-memory cache
 func (r *PostgresGameRepository) ClearCache() {
-	// The InMemoryGameRepository doesn't have a clear method, but we can create a new one
-	r.cache = repositories.NewInMemoryGameRepository()
+	r.cache.Clear()
+}
+
+// executor returns the appropriate DBExecutor (transaction or db connection).
+func (r *PostgresGameRepository) executor(ctx context.Context) DBExecutor {
+	if tx := TxFromContext(ctx); tx != nil {
+		return tx
+	}
+	return r.db
+}
+
+func (r *PostgresGameRepository) SaveGameStatus(ctx context.Context, gameId string, status models.GameStatus) error {
+	result, err := r.executor(ctx).ExecContext(ctx,
+		`UPDATE game SET status = $2, updated_at = NOW() WHERE id = $1`,
+		gameId, status,
+	)
+	if err != nil {
+		return fmt.Errorf("error saving game status: %v", err)
+	}
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return fmt.Errorf("game %s not found", gameId)
+	}
+	return nil
+}
+
+// RecordEvents implements GameHistoryRecorder, within the transaction of ctx when there's one.
+// A new snapshot is taken every gameSnapshotInterval events. The first one is always taken when the game is loaded
+// from the tables, so that its history starts from what the tables hold. When the events can't be recorded,
+// the cached game is evicted, so that it's rebuilt from the history as it was saved
+func (r *PostgresGameRepository) RecordEvents(ctx context.Context, gameId string, game models.Game) error {
+	eventSourced, ok := game.(models.EventSourcedGame)
+	if !ok {
+		return nil
+	}
+	events := eventSourced.TakeEvents()
+	if len(events) == 0 {
+		return nil
+	}
+	if err := r.eventRepo.AppendEvents(ctx, gameId, events); err != nil {
+		r.EvictGame(gameId)
+		return fmt.Errorf("error recording game events: %v", err)
+	}
+
+	lastSequence := events[len(events)-1].Sequence
+	snapshot, err := r.eventRepo.GetLatestSnapshot(ctx, gameId, 0)
+	if err == nil && snapshot != nil && lastSequence-snapshot.Sequence >= gameSnapshotInterval {
+		err = r.snapshotGame(ctx, gameId, game, lastSequence)
+	}
+	// The history is complete without the snapshot, the game is only replayed from an older one
+	if err != nil {
+		log.WithError(err).Warnf("Failed to take a snapshot of game %s", gameId)
+	}
+	return nil
+}
+
+// RebaseGame implements GameHistoryRecorder. The game is loaded from the tables, and a rebase event is recorded
+// along with a snapshot at its sequence, which the game is rebuilt from from now on
+func (r *PostgresGameRepository) RebaseGame(ctx context.Context, gameId string) error {
+	r.EvictGame(gameId)
+	game, err := r.loadGame(gameId)
+	if err != nil {
+		return err
+	}
+
+	rebased := []*models.GameEvent{models.NewGameStatusEvent(models.GameEventHistoryRebased)}
+	if err := r.eventRepo.AppendEvents(ctx, gameId, rebased); err != nil {
+		return fmt.Errorf("error recording game rebase: %v", err)
+	}
+	if err := r.snapshotGame(ctx, gameId, game, rebased[0].Sequence); err != nil {
+		return err
+	}
+
+	if err := r.cache.SaveWithId(gameId, game); err != nil {
+		log.WithError(err).Warn("Failed to cache game")
+	}
+	return nil
+}
+
+// snapshotGame saves the state of a game at a sequence of its history
+func (r *PostgresGameRepository) snapshotGame(ctx context.Context, gameId string, game models.Game, sequence int64) error {
+	eventSourced, ok := game.(models.EventSourcedGame)
+	if !ok {
+		return nil
+	}
+	return r.eventRepo.SaveSnapshot(ctx, &models.GameSnapshot{
+		GameID:   gameId,
+		Sequence: sequence,
+		State:    eventSourced.State(),
+	})
+}
+
+// dropPendingEvents forgets the pending events of a game
+func dropPendingEvents(game models.Game) {
+	if eventSourced, ok := game.(models.EventSourcedGame); ok {
+		eventSourced.TakeEvents()
+	}
 }
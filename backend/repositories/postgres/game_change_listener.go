@@ -0,0 +1,135 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"ligain/backend/repositories"
+	"time"
+
+	"github.com/jackc/pgx/v5/stdlib"
+	log "github.com/sirupsen/logrus"
+)
+
+// gameChangedChannel is the channel the triggers of the game tables notify with a gameChange
+const gameChangedChannel = "game_changed"
+
+// gameChange is the payload of the notifications of the game_changed channel
+type gameChange struct {
+	GameID string `json:"game_id"`
+	// Sender is the application_name of the connection which made the change
+	Sender string `json:"sender"`
+}
+
+// defaultListenRetryDelay is how long the listener waits before listening again after losing its connection
+const defaultListenRetryDelay = 5 * time.Second
+
+// GameChangeListener listens to the changes the triggers of the game tables notify, and passes them to its handler.
+// It holds a connection of the pool for as long as it listens
+type GameChangeListener struct {
+	db         *sql.DB
+	handler    repositories.GameChangeHandler
+	retryDelay time.Duration
+	// instance is the application_name of the connections of the pool, the changes they send are skipped
+	// since the instance already keeps its cache in line with its own changes. Empty when the pool isn't named
+	instance string
+}
+
+func NewGameChangeListener(db *sql.DB, handler repositories.GameChangeHandler) *GameChangeListener {
+	return &GameChangeListener{
+		db:         db,
+		handler:    handler,
+		retryDelay: defaultListenRetryDelay,
+	}
+}
+
+// Start listens to the changes until the context is cancelled. The changes made once Start returns are
+// all passed to the handler: when the connection is lost, the handler is told that every game may have changed
+func (l *GameChangeListener) Start(ctx context.Context) error {
+	conn, err := l.listen(ctx)
+	if err != nil {
+		return err
+	}
+	go l.run(ctx, conn)
+	return nil
+}
+
+func (l *GameChangeListener) listen(ctx context.Context) (*sql.Conn, error) {
+	conn, err := l.db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error getting connection to listen to game changes: %v", err)
+	}
+	if err := conn.QueryRowContext(ctx, "SELECT current_setting('application_name')").Scan(&l.instance); err != nil {
+		discardConn(conn)
+		return nil, fmt.Errorf("error getting the name of the instance: %v", err)
+	}
+	if _, err := conn.ExecContext(ctx, "LISTEN "+gameChangedChannel); err != nil {
+		discardConn(conn)
+		return nil, fmt.Errorf("error listening to game changes: %v", err)
+	}
+	return conn, nil
+}
+
+func (l *GameChangeListener) run(ctx context.Context, conn *sql.Conn) {
+	for {
+		err := l.wait(ctx, conn)
+		discardConn(conn)
+		if ctx.Err() != nil {
+			return
+		}
+		log.Errorf("Stopped receiving game changes, listening again: %v", err)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(l.retryDelay):
+			}
+			if conn, err = l.listen(ctx); err == nil {
+				break
+			}
+			log.Errorf("Failed to listen to game changes: %v", err)
+		}
+		// The changes made while reconnecting were missed
+		l.handler.AllGamesChanged()
+	}
+}
+
+// wait passes the notifications to the handler until the connection fails or the context is cancelled
+func (l *GameChangeListener) wait(ctx context.Context, conn *sql.Conn) error {
+	return conn.Raw(func(driverConn any) error {
+		pgxConn := driverConn.(*stdlib.Conn).Conn()
+		for {
+			notification, err := pgxConn.WaitForNotification(ctx)
+			if err != nil {
+				return err
+			}
+			l.handle(notification.Payload)
+		}
+	})
+}
+
+// handle passes a change to the handler, unless the instance made it
+func (l *GameChangeListener) handle(payload string) {
+	var change gameChange
+	if err := json.Unmarshal([]byte(payload), &change); err != nil {
+		// The changed game is unknown, every game may have changed
+		log.Errorf("Invalid game change %q: %v", payload, err)
+		l.handler.AllGamesChanged()
+		return
+	}
+	if l.instance != "" && change.Sender == l.instance {
+		return
+	}
+	l.handler.GameChanged(change.GameID)
+}
+
+// discardConn closes the underlying connection before releasing it, so that a connection still listening
+// or holding a session lock isn't given back to the pool
+func discardConn(conn *sql.Conn) {
+	_ = conn.Raw(func(driverConn any) error {
+		return driverConn.(*stdlib.Conn).Close()
+	})
+	_ = conn.Close()
+}
@@ -0,0 +1,231 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"ligain/backend/models"
+	"ligain/backend/rules"
+
+	"github.com/stretchr/testify/require"
+)
+
+// recordingGameChangeHandler evicts the changes from the cache of a game repository, and records them
+type recordingGameChangeHandler struct {
+	gameRepo   *PostgresGameRepository
+	changed    chan string
+	allChanged chan struct{}
+}
+
+func (h *recordingGameChangeHandler) GameChanged(gameId string) {
+	h.gameRepo.EvictGame(gameId)
+	h.changed <- gameId
+}
+
+func (h *recordingGameChangeHandler) AllGamesChanged() {
+	h.gameRepo.ClearCache()
+	h.allChanged <- struct{}{}
+}
+
+func waitForGameChange(t *testing.T, changed chan string, gameID string) {
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case changedID := <-changed:
+			if changedID == gameID {
+				return
+			}
+		case <-timeout:
+			t.Fatalf("game %s change not received", gameID)
+		}
+	}
+}
+
+func TestGameChangeListener_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	runTestWithTimeout(t, func(t *testing.T) {
+		testDB := setupTestDB(t)
+		defer testDB.Close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		// Two instances of the backend, each with its own game cache
+		writerRepo, err := NewPostgresGameRepository(testDB.db)
+		require.NoError(t, err)
+		readerGameRepo, err := NewPostgresGameRepository(testDB.db)
+		require.NoError(t, err)
+		readerRepo := readerGameRepo.(*PostgresGameRepository)
+
+		handler := &recordingGameChangeHandler{
+			gameRepo:   readerRepo,
+			changed:    make(chan string, 100),
+			allChanged: make(chan struct{}, 1),
+		}
+		listener := NewGameChangeListener(testDB.db, handler)
+		listener.retryDelay = 100 * time.Millisecond
+		require.NoError(t, listener.Start(ctx))
+
+		game := rules.NewFreshGame("2025/2026", "Ligue 1", "Before", []models.Player{}, []models.Match{}, &rules.ScorerOriginal{})
+		gameID, err := writerRepo.CreateGame(game)
+		require.NoError(t, err)
+		waitForGameChange(t, handler.changed, gameID)
+
+		t.Run("Game Update Reaches The Other Instance", func(t *testing.T) {
+			cached, err := readerRepo.GetGame(gameID)
+			require.NoError(t, err)
+			require.Equal(t, "Before", cached.GetName())
+
+			renamed := rules.NewFreshGame("2025/2026", "Ligue 1", "After", []models.Player{}, []models.Match{}, &rules.ScorerOriginal{})
+			require.NoError(t, writerRepo.SaveWithId(gameID, renamed))
+			waitForGameChange(t, handler.changed, gameID)
+
+			fresh, err := readerRepo.GetGame(gameID)
+			require.NoError(t, err)
+			require.Equal(t, "After", fresh.GetName())
+		})
+
+		t.Run("Membership Change By A Script Reaches The Instance", func(t *testing.T) {
+			cached, err := readerRepo.GetGame(gameID)
+			require.NoError(t, err)
+			require.Empty(t, cached.GetPlayers())
+
+			player := &models.PlayerData{Name: "Scripted"}
+			require.NoError(t, NewPostgresPlayerRepository(testDB.db).CreatePlayer(ctx, player))
+			_, err = testDB.db.Exec(`INSERT INTO game_player (game_id, player_id) VALUES ($1, $2)`, gameID, player.ID)
+			require.NoError(t, err)
+			waitForGameChange(t, handler.changed, gameID)
+
+			fresh, err := readerRepo.GetGame(gameID)
+			require.NoError(t, err)
+			require.Len(t, fresh.GetPlayers(), 1)
+			require.Equal(t, "Scripted", fresh.GetPlayers()[0].GetName())
+		})
+
+		t.Run("Bet Saved Again Unchanged Isn't Notified", func(t *testing.T) {
+			_, err := testDB.db.Exec(`
+				INSERT INTO match (local_id, home_team_id, away_team_id, match_date, match_status, season_code, competition_code, matchday)
+				VALUES ('Ligue 1-2025/2026-Lens-Lille-1', 'Lens', 'Lille', NOW(), 'scheduled', '2025/2026', 'Ligue 1', 1)
+			`)
+			require.NoError(t, err)
+			saveBet := func(homeGoals int) {
+				_, err := testDB.db.Exec(`
+					INSERT INTO bet (game_id, match_id, player_id, predicted_home_goals, predicted_away_goals)
+					SELECT $1, m.id, gp.player_id, $2, 0
+					FROM match m JOIN game_player gp ON gp.game_id = $1
+					WHERE m.local_id = 'Ligue 1-2025/2026-Lens-Lille-1'
+					ON CONFLICT (game_id, match_id, player_id) DO UPDATE
+					SET predicted_home_goals = $2, predicted_away_goals = 0, updated_at = NOW()
+				`, gameID, homeGoals)
+				require.NoError(t, err)
+			}
+
+			saveBet(2)
+			waitForGameChange(t, handler.changed, gameID)
+
+			// The notifications arrive in order, so the next one is the change of the other game
+			otherGame := rules.NewFreshGame("2025/2026", "Ligue 1", "Other", []models.Player{}, []models.Match{}, &rules.ScorerOriginal{})
+			saveBet(2)
+			otherID, err := writerRepo.CreateGame(otherGame)
+			require.NoError(t, err)
+			select {
+			case changedID := <-handler.changed:
+				require.Equal(t, otherID, changedID)
+			case <-time.After(5 * time.Second):
+				t.Fatal("change of the other game not received")
+			}
+		})
+
+		t.Run("Own Changes Are Skipped", func(t *testing.T) {
+			// An instance whose pool is named, with the listener of the reader
+			instanceDB, err := OpenDB(testDB.url)
+			require.NoError(t, err)
+			defer instanceDB.Close()
+			instanceRepo, err := NewPostgresGameRepository(instanceDB)
+			require.NoError(t, err)
+			ownHandler := &recordingGameChangeHandler{
+				gameRepo:   readerRepo,
+				changed:    make(chan string, 100),
+				allChanged: make(chan struct{}, 1),
+			}
+			instanceCtx, instanceCancel := context.WithCancel(ctx)
+			defer instanceCancel()
+			require.NoError(t, NewGameChangeListener(instanceDB, ownHandler).Start(instanceCtx))
+
+			ownGame := rules.NewFreshGame("2025/2026", "Ligue 1", "Own", []models.Player{}, []models.Match{}, &rules.ScorerOriginal{})
+			_, err = instanceRepo.CreateGame(ownGame)
+			require.NoError(t, err)
+			foreignGame := rules.NewFreshGame("2025/2026", "Ligue 1", "Foreign", []models.Player{}, []models.Match{}, &rules.ScorerOriginal{})
+			foreignID, err := writerRepo.CreateGame(foreignGame)
+			require.NoError(t, err)
+
+			select {
+			case changedID := <-ownHandler.changed:
+				require.Equal(t, foreignID, changedID)
+			case <-time.After(5 * time.Second):
+				t.Fatal("change of the other instance not received")
+			}
+		})
+
+		t.Run("Live Match Update Reaches The Other Instance", func(t *testing.T) {
+			_, err := testDB.db.Exec(`
+				INSERT INTO match (local_id, home_team_id, away_team_id, match_date, match_status, season_code, competition_code, matchday)
+				VALUES ('Ligue 1-2025/2026-Nice-Monaco-2', 'Nice', 'Monaco', NOW(), 'scheduled', '2025/2026', 'Ligue 1', 2)
+			`)
+			require.NoError(t, err)
+			liveGame := rules.NewFreshGame("2025/2026", "Ligue 1", "Live", []models.Player{}, []models.Match{}, &rules.ScorerOriginal{})
+			liveID, err := writerRepo.CreateGame(liveGame)
+			require.NoError(t, err)
+			waitForGameChange(t, handler.changed, liveID)
+
+			// The watching instance and the other one both hold the game before kickoff
+			watched, err := writerRepo.GetGame(liveID)
+			require.NoError(t, err)
+			cached, err := readerRepo.GetGame(liveID)
+			require.NoError(t, err)
+			match, err := cached.GetMatchById("Ligue 1-2025/2026-Nice-Monaco-2")
+			require.NoError(t, err)
+			require.False(t, match.IsInProgress())
+
+			// Only the history of the game holds the live update
+			match, err = watched.GetMatchById("Ligue 1-2025/2026-Nice-Monaco-2")
+			require.NoError(t, err)
+			live := *models.AsSeasonMatch(match)
+			live.Start()
+			live.HomeGoals = 1
+			require.NoError(t, watched.UpdateMatch(&live))
+			require.NoError(t, writerRepo.(*PostgresGameRepository).RecordEvents(ctx, liveID, watched))
+			waitForGameChange(t, handler.changed, liveID)
+
+			fresh, err := readerRepo.GetGame(liveID)
+			require.NoError(t, err)
+			match, err = fresh.GetMatchById("Ligue 1-2025/2026-Nice-Monaco-2")
+			require.NoError(t, err)
+			require.True(t, match.IsInProgress())
+			require.Equal(t, 1, match.GetHomeGoals())
+		})
+
+		t.Run("Lost Connection Drops The Whole Cache", func(t *testing.T) {
+			_, err := testDB.db.Exec(`
+				SELECT pg_terminate_backend(pid) FROM pg_stat_activity
+				WHERE query = 'LISTEN game_changed' AND pid != pg_backend_pid()
+			`)
+			require.NoError(t, err)
+
+			select {
+			case <-handler.allChanged:
+			case <-time.After(5 * time.Second):
+				t.Fatal("listener didn't listen again")
+			}
+
+			// The changes made after listening again are received
+			renamed := rules.NewFreshGame("2025/2026", "Ligue 1", "Again", []models.Player{}, []models.Match{}, &rules.ScorerOriginal{})
+			require.NoError(t, writerRepo.SaveWithId(gameID, renamed))
+			waitForGameChange(t, handler.changed, gameID)
+		})
+	}, 60*time.Second)
+}
@@ -0,0 +1,162 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"ligain/backend/models"
+	"ligain/backend/repositories"
+)
+
+type PostgresGameEventRepository struct {
+	db DBExecutor
+}
+
+func NewPostgresGameEventRepository(db DBExecutor) repositories.GameEventRepository {
+	return &PostgresGameEventRepository{db: db}
+}
+
+// executor returns the appropriate DBExecutor (transaction or db connection).
+func (r *PostgresGameEventRepository) executor(ctx context.Context) DBExecutor {
+	if tx := TxFromContext(ctx); tx != nil {
+		return tx
+	}
+	return r.db
+}
+
+// AppendEvents implements GameEventRepository, within the transaction of ctx when there's one, or its own.
+// The sequence is computed under a per-game advisory lock held until the end of the transaction, so that
+// two writers appending to the same game at once are serialized instead of colliding on the sequence
+func (r *PostgresGameEventRepository) AppendEvents(ctx context.Context, gameID string, events []*models.GameEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	db, ok := r.db.(*sql.DB)
+	if TxFromContext(ctx) != nil || !ok {
+		return r.appendEvents(ctx, gameID, events)
+	}
+
+	return NewUnitOfWork(db).WithinTx(ctx, func(txCtx context.Context) error {
+		return r.appendEvents(txCtx, gameID, events)
+	})
+}
+
+func (r *PostgresGameEventRepository) appendEvents(ctx context.Context, gameID string, events []*models.GameEvent) error {
+	if _, err := r.executor(ctx).ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext('game_event:' || $1))`, gameID); err != nil {
+		return fmt.Errorf("error locking game events: %v", err)
+	}
+	lastSequence, err := r.GetLastSequence(ctx, gameID)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO game_event (game_id, sequence, type, payload)
+		VALUES ($1, $2, $3, $4)
+		RETURNING created_at
+	`
+	for i, event := range events {
+		payload, err := json.Marshal(event.Payload)
+		if err != nil {
+			return fmt.Errorf("error encoding game event payload: %v", err)
+		}
+		sequence := lastSequence + int64(i) + 1
+		if err := r.executor(ctx).QueryRowContext(ctx, query, gameID, sequence, string(event.Type), payload).Scan(&event.CreatedAt); err != nil {
+			return fmt.Errorf("error appending game event: %v", err)
+		}
+		event.GameID = gameID
+		event.Sequence = sequence
+	}
+	return nil
+}
+
+func (r *PostgresGameEventRepository) GetEvents(ctx context.Context, gameID string, afterSequence int64, toSequence int64, limit int) ([]*models.GameEvent, error) {
+	query := `
+		SELECT game_id, sequence, type, payload, created_at
+		FROM game_event
+		WHERE game_id = $1 AND sequence > $2 AND ($3 = 0 OR sequence <= $3)
+		ORDER BY sequence
+		LIMIT NULLIF($4, 0)
+	`
+
+	rows, err := r.executor(ctx).QueryContext(ctx, query, gameID, afterSequence, toSequence, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error getting game events: %v", err)
+	}
+	defer rows.Close()
+
+	events := make([]*models.GameEvent, 0)
+	for rows.Next() {
+		var event models.GameEvent
+		var eventType string
+		var payload []byte
+		if err := rows.Scan(&event.GameID, &event.Sequence, &eventType, &payload, &event.CreatedAt); err != nil {
+			return nil, fmt.Errorf("error scanning game event: %v", err)
+		}
+		if err := json.Unmarshal(payload, &event.Payload); err != nil {
+			return nil, fmt.Errorf("error decoding game event payload: %v", err)
+		}
+		event.Type = models.GameEventType(eventType)
+		events = append(events, &event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating game events: %v", err)
+	}
+
+	return events, nil
+}
+
+func (r *PostgresGameEventRepository) GetLastSequence(ctx context.Context, gameID string) (int64, error) {
+	var lastSequence int64
+	err := r.executor(ctx).QueryRowContext(ctx,
+		`SELECT COALESCE(MAX(sequence), 0) FROM game_event WHERE game_id = $1`,
+		gameID,
+	).Scan(&lastSequence)
+	if err != nil {
+		return 0, fmt.Errorf("error getting last game event: %v", err)
+	}
+	return lastSequence, nil
+}
+
+func (r *PostgresGameEventRepository) SaveSnapshot(ctx context.Context, snapshot *models.GameSnapshot) error {
+	state, err := json.Marshal(snapshot.State)
+	if err != nil {
+		return fmt.Errorf("error encoding game snapshot: %v", err)
+	}
+
+	query := `
+		INSERT INTO game_snapshot (game_id, sequence, state)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (game_id, sequence) DO UPDATE SET state = EXCLUDED.state, created_at = NOW()
+		RETURNING created_at
+	`
+	if err := r.executor(ctx).QueryRowContext(ctx, query, snapshot.GameID, snapshot.Sequence, state).Scan(&snapshot.CreatedAt); err != nil {
+		return fmt.Errorf("error saving game snapshot: %v", err)
+	}
+	return nil
+}
+
+func (r *PostgresGameEventRepository) GetLatestSnapshot(ctx context.Context, gameID string, atSequence int64) (*models.GameSnapshot, error) {
+	query := `
+		SELECT game_id, sequence, state, created_at
+		FROM game_snapshot
+		WHERE game_id = $1 AND ($2 = 0 OR sequence <= $2)
+		ORDER BY sequence DESC
+		LIMIT 1
+	`
+
+	var snapshot models.GameSnapshot
+	var state []byte
+	err := r.executor(ctx).QueryRowContext(ctx, query, gameID, atSequence).Scan(&snapshot.GameID, &snapshot.Sequence, &state, &snapshot.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting game snapshot: %v", err)
+	}
+	if err := json.Unmarshal(state, &snapshot.State); err != nil {
+		return nil, fmt.Errorf("error decoding game snapshot: %v", err)
+	}
+	return &snapshot, nil
+}
@@ -0,0 +1,180 @@
+package postgres
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"ligain/backend/models"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGameEventRepository_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	runTestWithTimeout(t, func(t *testing.T) {
+		testDB := setupTestDB(t)
+		defer testDB.Close()
+
+		eventRepo := NewPostgresGameEventRepository(testDB.db)
+		ctx := context.Background()
+
+		gameID := "123e4567-e89b-12d3-a456-426614174501"
+		playerID := "123e4567-e89b-12d3-a456-426614174502"
+		exec := func(query string, args ...any) {
+			_, err := testDB.db.Exec(query, args...)
+			require.NoError(t, err)
+		}
+		exec(`INSERT INTO game (id, season_year, competition_name, status, game_name) VALUES ($1, '2024', 'Premier League', 'scheduled', 'History Game')`, gameID)
+		exec(`INSERT INTO player (id, name) VALUES ($1, 'Player1')`, playerID)
+		exec(`INSERT INTO game_player (game_id, player_id) VALUES ($1, $2)`, gameID, playerID)
+
+		t.Run("Append and Get Events", func(t *testing.T) {
+			lastSequence, err := eventRepo.GetLastSequence(ctx, gameID)
+			require.NoError(t, err)
+			require.Equal(t, int64(0), lastSequence)
+
+			events := []*models.GameEvent{
+				models.NewPlayerGameEvent(models.GameEventPlayerJoined, playerID),
+				models.NewGameStatusEvent(models.GameEventGameFinished),
+			}
+			require.NoError(t, eventRepo.AppendEvents(ctx, gameID, events))
+			require.Equal(t, int64(2), events[1].Sequence)
+
+			stored, err := eventRepo.GetEvents(ctx, gameID, 0, 0, 0)
+			require.NoError(t, err)
+			require.Len(t, stored, 2)
+			require.Equal(t, models.GameEventPlayerJoined, stored[0].Type)
+			require.Equal(t, playerID, stored[0].Payload.PlayerID)
+
+			stored, err = eventRepo.GetEvents(ctx, gameID, 0, 1, 0)
+			require.NoError(t, err)
+			require.Len(t, stored, 1)
+
+			stored, err = eventRepo.GetEvents(ctx, gameID, 1, 0, 10)
+			require.NoError(t, err)
+			require.Len(t, stored, 1)
+			require.Equal(t, models.GameEventGameFinished, stored[0].Type)
+
+			require.NoError(t, eventRepo.AppendEvents(ctx, gameID, []*models.GameEvent{models.NewGameStatusEvent(models.GameEventGameReopened)}))
+			lastSequence, err = eventRepo.GetLastSequence(ctx, gameID)
+			require.NoError(t, err)
+			require.Equal(t, int64(3), lastSequence)
+		})
+
+		t.Run("Concurrent Appends Are Serialized", func(t *testing.T) {
+			lastSequence, err := eventRepo.GetLastSequence(ctx, gameID)
+			require.NoError(t, err)
+
+			// Every writer appends in its own transaction, like the bets placed at the same moment
+			const writers = 10
+			uow := NewUnitOfWork(testDB.db)
+			errs := make(chan error, writers)
+			var wg sync.WaitGroup
+			for i := 0; i < writers; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					errs <- uow.WithinTx(ctx, func(txCtx context.Context) error {
+						return eventRepo.AppendEvents(txCtx, gameID, []*models.GameEvent{
+							models.NewPlayerGameEvent(models.GameEventPlayerJoined, playerID),
+							models.NewPlayerGameEvent(models.GameEventPlayerLeft, playerID),
+						})
+					})
+				}()
+			}
+			wg.Wait()
+			close(errs)
+			for err := range errs {
+				require.NoError(t, err)
+			}
+
+			stored, err := eventRepo.GetEvents(ctx, gameID, lastSequence, 0, 0)
+			require.NoError(t, err)
+			require.Len(t, stored, 2*writers)
+			for i, event := range stored {
+				require.Equal(t, lastSequence+int64(i)+1, event.Sequence)
+			}
+		})
+
+		t.Run("Save and Get Snapshots", func(t *testing.T) {
+			snapshot, err := eventRepo.GetLatestSnapshot(ctx, gameID, 0)
+			require.NoError(t, err)
+			require.Nil(t, snapshot)
+
+			for _, sequence := range []int64{1, 3} {
+				require.NoError(t, eventRepo.SaveSnapshot(ctx, &models.GameSnapshot{
+					GameID:   gameID,
+					Sequence: sequence,
+					State:    &models.GameState{Name: "History Game", PlayerIDs: []string{playerID}},
+				}))
+			}
+
+			snapshot, err = eventRepo.GetLatestSnapshot(ctx, gameID, 0)
+			require.NoError(t, err)
+			require.Equal(t, int64(3), snapshot.Sequence)
+
+			snapshot, err = eventRepo.GetLatestSnapshot(ctx, gameID, 2)
+			require.NoError(t, err)
+			require.Equal(t, int64(1), snapshot.Sequence)
+			require.Equal(t, []string{playerID}, snapshot.State.PlayerIDs)
+		})
+	}, 30*time.Second)
+}
+
+func TestGameRepository_RebuildsGamesFromHistory_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	runTestWithTimeout(t, func(t *testing.T) {
+		testDB := setupTestDB(t)
+		defer testDB.Close()
+
+		repo, err := NewPostgresGameRepository(testDB.db)
+		require.NoError(t, err)
+		gameRepo := repo.(*PostgresGameRepository)
+		eventRepo := NewPostgresGameEventRepository(testDB.db)
+		ctx := context.Background()
+
+		gameID := "123e4567-e89b-12d3-a456-426614174511"
+		playerID := "123e4567-e89b-12d3-a456-426614174512"
+		exec := func(query string, args ...any) {
+			_, err := testDB.db.Exec(query, args...)
+			require.NoError(t, err)
+		}
+		exec(`INSERT INTO game (id, season_year, competition_name, status, game_name) VALUES ($1, '2024', 'Premier League', 'scheduled', 'History Game')`, gameID)
+		exec(`INSERT INTO player (id, name) VALUES ($1, 'Player1')`, playerID)
+		exec(`INSERT INTO game_player (game_id, player_id) VALUES ($1, $2)`, gameID, playerID)
+
+		// Loading the game from the tables takes the first snapshot of its history
+		game, err := gameRepo.GetGame(gameID)
+		require.NoError(t, err)
+		require.Len(t, game.GetPlayers(), 1)
+		snapshot, err := eventRepo.GetLatestSnapshot(ctx, gameID, 0)
+		require.NoError(t, err)
+		require.NotNil(t, snapshot)
+		require.Equal(t, int64(0), snapshot.Sequence)
+
+		require.NoError(t, game.RemovePlayer(game.GetPlayers()[0]))
+		require.NoError(t, gameRepo.RecordEvents(ctx, gameID, game))
+
+		// The player is still in the tables, only the history knows they left
+		gameRepo.EvictGame(gameID)
+		game, err = gameRepo.GetGame(gameID)
+		require.NoError(t, err)
+		require.Empty(t, game.GetPlayers())
+
+		// Once rebased, the game is the one of the tables again
+		require.NoError(t, gameRepo.RebaseGame(ctx, gameID))
+		gameRepo.EvictGame(gameID)
+		game, err = gameRepo.GetGame(gameID)
+		require.NoError(t, err)
+		require.Len(t, game.GetPlayers(), 1)
+		require.Equal(t, "Player1", game.GetPlayers()[0].GetName())
+	}, 30*time.Second)
+}
@@ -74,6 +74,43 @@ func TestGameRepository_Integration(t *testing.T) {
 			})
 		})
 
+		t.Run("Save and Load Bet Cutoff", func(t *testing.T) {
+			game := rules.NewFreshGame("2024", "Premier League", "Cutoff Game", []models.Player{}, []models.Match{}, &rules.ScorerOriginal{})
+			cutoff := models.BetCutoff{Minutes: 60, Matchday: true}
+			game.SetBetCutoff(cutoff)
+			gameID, err := gameRepo.CreateGame(game)
+			require.NoError(t, err)
+
+			// Loaded from the tables first, then rebuilt from the snapshot taken meanwhile
+			repo := gameRepo.(*PostgresGameRepository)
+			repo.EvictGame(gameID)
+			loaded, err := gameRepo.GetGame(gameID)
+			require.NoError(t, err)
+			require.Equal(t, cutoff, loaded.GetBetCutoff())
+			repo.EvictGame(gameID)
+			replayed, err := gameRepo.GetGame(gameID)
+			require.NoError(t, err)
+			require.Equal(t, cutoff, replayed.GetBetCutoff())
+		})
+
+		t.Run("Save and Load Tiebreakers", func(t *testing.T) {
+			game := rules.NewFreshGame("2024", "Premier League", "Tiebreakers Game", []models.Player{}, []models.Match{}, &rules.ScorerOriginal{})
+			game.SetTiebreakers([]rules.Tiebreaker{rules.TiebreakerHeadToHead, rules.TiebreakerExactScores})
+			gameID, err := gameRepo.CreateGame(game)
+			require.NoError(t, err)
+
+			// Loaded from the tables first, then rebuilt from the snapshot taken meanwhile
+			repo := gameRepo.(*PostgresGameRepository)
+			repo.EvictGame(gameID)
+			loaded, err := gameRepo.GetGame(gameID)
+			require.NoError(t, err)
+			require.Equal(t, []string{"head_to_head", "exact_scores"}, loaded.GetTiebreakers())
+			repo.EvictGame(gameID)
+			replayed, err := gameRepo.GetGame(gameID)
+			require.NoError(t, err)
+			require.Equal(t, []string{"head_to_head", "exact_scores"}, replayed.GetTiebreakers())
+		})
+
 		t.Run("SQL Scanning Issues Prevention - getMatchesAndBets with Odds", func(t *testing.T) {
 			// This test specifically verifies that the SQL scanning issue we fixed doesn't occur
 			// in the getMatchesAndBets method when matches have odds data
@@ -122,7 +159,7 @@ func TestGameRepository_Integration(t *testing.T) {
 			// Create a bet for one of the matches
 			betRepo := NewPostgresBetRepository(testDB.db)
 			bet := models.NewBet(match1, 2, 1)
-			_, _, err = betRepo.SaveBet(gameID, bet, player)
+			_, _, err = betRepo.SaveBet(context.Background(), gameID, bet, player)
 			require.NoError(t, err)
 
 			// Test the getMatchesAndBets method - this should NOT fail with SQL scanning errors
@@ -214,28 +251,28 @@ func TestGameRepository_RestoreGameState(t *testing.T) {
 
 		// Player 1 bets
 		pastBet1 := models.NewBet(pastMatch, 2, 1) // Correct prediction
-		_, _, err = postgresBetRepo.SaveBet(gameId, pastBet1, player1)
+		_, _, err = postgresBetRepo.SaveBet(context.Background(), gameId, pastBet1, player1)
 		require.NoError(t, err)
 
 		currentBet1 := models.NewBet(currentMatch, 1, 1)
-		_, _, err = postgresBetRepo.SaveBet(gameId, currentBet1, player1)
+		_, _, err = postgresBetRepo.SaveBet(context.Background(), gameId, currentBet1, player1)
 		require.NoError(t, err)
 
 		futureBet1 := models.NewBet(futureMatch, 2, 0)
-		_, _, err = postgresBetRepo.SaveBet(gameId, futureBet1, player1)
+		_, _, err = postgresBetRepo.SaveBet(context.Background(), gameId, futureBet1, player1)
 		require.NoError(t, err)
 
 		// Player 2 bets
 		pastBet2 := models.NewBet(pastMatch, 1, 2) // Wrong prediction
-		_, _, err = postgresBetRepo.SaveBet(gameId, pastBet2, player2)
+		_, _, err = postgresBetRepo.SaveBet(context.Background(), gameId, pastBet2, player2)
 		require.NoError(t, err)
 
 		currentBet2 := models.NewBet(currentMatch, 2, 1)
-		_, _, err = postgresBetRepo.SaveBet(gameId, currentBet2, player2)
+		_, _, err = postgresBetRepo.SaveBet(context.Background(), gameId, currentBet2, player2)
 		require.NoError(t, err)
 
 		futureBet2 := models.NewBet(futureMatch, 1, 1)
-		_, _, err = postgresBetRepo.SaveBet(gameId, futureBet2, player2)
+		_, _, err = postgresBetRepo.SaveBet(context.Background(), gameId, futureBet2, player2)
 		require.NoError(t, err)
 
 		err = postgresBetRepo.SaveScore(gameId, pastMatch, player1, 3) // Player 1 got 3 points for correct prediction
@@ -270,7 +307,7 @@ func TestGameRepository_RestoreGameState(t *testing.T) {
 				}
 				require.NotNil(t, restoredPlayer1, "restored player1 should not be nil")
 				// Use the testing method to get all bets for verification
-				incomingMatches := restoredGame.GetIncomingMatchesForTesting()
+				incomingMatches := restoredGame.GetIncomingMatchesWithAllBets()
 
 				// Verify past match
 				pastMatchResult, exists := pastResults[pastMatch.Id()]
@@ -399,12 +436,12 @@ func TestGameRepository_LoadPlayersFromBothTables(t *testing.T) {
 
 		// Player 1 bets
 		bet1 := models.NewBet(futureMatch, 2, 1)
-		_, _, err = postgresBetRepo.SaveBet(gameId, bet1, player1)
+		_, _, err = postgresBetRepo.SaveBet(context.Background(), gameId, bet1, player1)
 		require.NoError(t, err)
 
 		// Player 2 bets
 		bet2 := models.NewBet(futureMatch, 1, 1)
-		_, _, err = postgresBetRepo.SaveBet(gameId, bet2, player2)
+		_, _, err = postgresBetRepo.SaveBet(context.Background(), gameId, bet2, player2)
 		require.NoError(t, err)
 
 		// Player 3 doesn't bet (this is the key test case)
@@ -439,7 +476,7 @@ func TestGameRepository_LoadPlayersFromBothTables(t *testing.T) {
 				require.NoError(t, err, "Player3 should be able to place a bet even though they haven't bet yet")
 
 				// Verify incoming matches have bets from Player1 and Player2 only
-				incomingMatches := restoredGame.GetIncomingMatchesForTesting()
+				incomingMatches := restoredGame.GetIncomingMatchesWithAllBets()
 				matchResult, exists := incomingMatches[futureMatch.Id()]
 				require.True(t, exists, "Future match should exist in incoming matches")
 				require.Equal(t, 2, len(matchResult.Bets), "Only Player1 and Player2 should have bets")
@@ -576,11 +613,11 @@ func TestGameRepository_GetAllGames_Integration(t *testing.T) {
 			// Insert bets
 			betRepo := NewPostgresBetRepository(testDB.db)
 			bet1 := models.NewBet(match1, 2, 1)
-			_, _, err = betRepo.SaveBet(gameID, bet1, &models.PlayerData{ID: player1ID, Name: "Player 1"})
+			_, _, err = betRepo.SaveBet(context.Background(), gameID, bet1, &models.PlayerData{ID: player1ID, Name: "Player 1"})
 			require.NoError(t, err)
 
 			bet2 := models.NewBet(match2, 1, 2)
-			_, _, err = betRepo.SaveBet(gameID, bet2, &models.PlayerData{ID: player2ID, Name: "Player 2"})
+			_, _, err = betRepo.SaveBet(context.Background(), gameID, bet2, &models.PlayerData{ID: player2ID, Name: "Player 2"})
 			require.NoError(t, err)
 
 			// Get all games
@@ -0,0 +1,55 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"ligain/backend/repositories"
+	"time"
+)
+
+type PostgresGuestCredentialRepository struct {
+	db *sql.DB
+}
+
+// executor returns the appropriate DBExecutor (transaction or db connection).
+func (r *PostgresGuestCredentialRepository) executor(ctx context.Context) DBExecutor {
+	if tx := TxFromContext(ctx); tx != nil {
+		return tx
+	}
+	return r.db
+}
+
+func NewPostgresGuestCredentialRepository(db *sql.DB) repositories.GuestCredentialRepository {
+	return &PostgresGuestCredentialRepository{db: db}
+}
+
+func (r *PostgresGuestCredentialRepository) GetGuestSecretHash(ctx context.Context, playerID string) (string, error) {
+	query := `SELECT secret_hash FROM guest_credential WHERE player_id = $1`
+
+	var secretHash string
+	err := r.executor(ctx).QueryRowContext(ctx, query, playerID).Scan(&secretHash)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("error getting guest secret: %v", err)
+	}
+
+	return secretHash, nil
+}
+
+func (r *PostgresGuestCredentialRepository) SaveGuestSecretHash(ctx context.Context, playerID string, secretHash string, createdAt time.Time) error {
+	query := `
+		INSERT INTO guest_credential (player_id, secret_hash, created_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (player_id) DO UPDATE SET secret_hash = EXCLUDED.secret_hash, created_at = EXCLUDED.created_at
+	`
+
+	if _, err := r.executor(ctx).ExecContext(ctx, query, playerID, secretHash, createdAt); err != nil {
+		return fmt.Errorf("error saving guest secret: %v", err)
+	}
+
+	return nil
+}
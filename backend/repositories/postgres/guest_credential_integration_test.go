@@ -0,0 +1,43 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGuestCredentialRepository_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	runTestWithTimeout(t, func(t *testing.T) {
+		testDB := setupTestDB(t)
+		defer testDB.Close()
+
+		guestCredRepo := NewPostgresGuestCredentialRepository(testDB.db)
+		ctx := context.Background()
+
+		playerID := "123e4567-e89b-12d3-a456-426614174501"
+		_, err := testDB.db.Exec(`INSERT INTO player (id, name) VALUES ($1, $2)`, playerID, "Guesty")
+		require.NoError(t, err)
+
+		t.Run("Legacy Guest Has No Secret", func(t *testing.T) {
+			secretHash, err := guestCredRepo.GetGuestSecretHash(ctx, playerID)
+			require.NoError(t, err)
+			require.Empty(t, secretHash)
+		})
+
+		t.Run("Save and Replace Secret", func(t *testing.T) {
+			now := time.Now().UTC()
+			require.NoError(t, guestCredRepo.SaveGuestSecretHash(ctx, playerID, "first-hash", now))
+			require.NoError(t, guestCredRepo.SaveGuestSecretHash(ctx, playerID, "second-hash", now.Add(time.Minute)))
+
+			secretHash, err := guestCredRepo.GetGuestSecretHash(ctx, playerID)
+			require.NoError(t, err)
+			require.Equal(t, "second-hash", secretHash)
+		})
+	}, 30*time.Second)
+}
@@ -39,6 +39,7 @@ func newTestPlayer(name string) models.Player {
 
 type testDB struct {
 	db        *sql.DB
+	url       string
 	container testcontainers.Container
 }
 
@@ -113,6 +114,7 @@ func setupTestDB(t *testing.T) *testDB {
 	// Create test database instance
 	testDB := &testDB{
 		db:        db,
+		url:       dbURL,
 		container: container,
 	}
 
@@ -126,12 +128,33 @@ func (db *testDB) cleanup(t *testing.T) {
 	log.Println("Starting database cleanup...")
 	// Drop all tables
 	_, err := db.db.Exec(`
+		DROP TABLE IF EXISTS bet_history CASCADE;
+		DROP TABLE IF EXISTS game_snapshot CASCADE;
+		DROP TABLE IF EXISTS game_event CASCADE;
+		DROP TABLE IF EXISTS match_scoring CASCADE;
+		DROP TABLE IF EXISTS job_run CASCADE;
+		DROP TABLE IF EXISTS admin_audit_log CASCADE;
+		DROP TABLE IF EXISTS rate_limit_bucket CASCADE;
+		DROP TABLE IF EXISTS api_key CASCADE;
+		DROP TABLE IF EXISTS email_sign_in_code CASCADE;
+		DROP TABLE IF EXISTS player_deletion CASCADE;
+		DROP TABLE IF EXISTS data_export CASCADE;
+		DROP TABLE IF EXISTS player_merge CASCADE;
+		DROP TABLE IF EXISTS guest_credential CASCADE;
+		DROP TABLE IF EXISTS refresh_token CASCADE;
+		DROP TABLE IF EXISTS game_activity CASCADE;
+		DROP TABLE IF EXISTS match_reaction CASCADE;
+		DROP TABLE IF EXISTS match_comment CASCADE;
+		DROP TABLE IF EXISTS player_rating_change CASCADE;
+		DROP TABLE IF EXISTS player_rating CASCADE;
+		DROP TABLE IF EXISTS player_achievement CASCADE;
 		DROP TABLE IF EXISTS score CASCADE;
 		DROP TABLE IF EXISTS bet CASCADE;
 		DROP TABLE IF EXISTS match CASCADE;
 		DROP TABLE IF EXISTS player CASCADE;
 		DROP TABLE IF EXISTS game CASCADE;
 		DROP TABLE IF EXISTS schema_migrations CASCADE;
+		DROP FUNCTION IF EXISTS notify_game_changed() CASCADE;
 	`)
 	if err != nil {
 		t.Fatalf("Failed to clean up test database: %v", err)
@@ -0,0 +1,108 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"ligain/backend/scheduler"
+	"time"
+)
+
+// PostgresJobStore implements scheduler.Store in postgres, so that each run of a job happens on a single instance
+type PostgresJobStore struct {
+	db *sql.DB
+}
+
+func NewPostgresJobStore(db *sql.DB) scheduler.Store {
+	return &PostgresJobStore{db: db}
+}
+
+// Lock takes a transaction-level advisory lock, held by a transaction open while the job runs. Rolling the
+// transaction back releases the lock, even when the connection goes back to the pool
+func (s *PostgresJobStore) Lock(ctx context.Context, job string) (func(), bool, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, false, fmt.Errorf("error starting job lock transaction: %v", err)
+	}
+
+	var locked bool
+	if err := tx.QueryRowContext(ctx, `SELECT pg_try_advisory_xact_lock(hashtext('job:' || $1))`, job).Scan(&locked); err != nil {
+		_ = tx.Rollback()
+		return nil, false, fmt.Errorf("error locking job: %v", err)
+	}
+	if !locked {
+		_ = tx.Rollback()
+		return nil, false, nil
+	}
+	return func() { _ = tx.Rollback() }, true, nil
+}
+
+func (s *PostgresJobStore) StartRun(ctx context.Context, run *scheduler.Run) (bool, error) {
+	err := s.db.QueryRowContext(ctx, `
+		INSERT INTO job_run (job_name, instance, scheduled_at, started_at, status)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (job_name, scheduled_at) DO NOTHING
+		RETURNING id
+	`, run.Job, run.Instance, run.ScheduledAt, run.StartedAt, run.Status).Scan(&run.ID)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("error starting job run: %v", err)
+	}
+	return true, nil
+}
+
+func (s *PostgresJobStore) FinishRun(ctx context.Context, run *scheduler.Run) error {
+	if _, err := s.db.ExecContext(ctx,
+		`UPDATE job_run SET finished_at = $2, status = $3, error = $4 WHERE id = $1`,
+		run.ID, run.FinishedAt, run.Status, run.Error,
+	); err != nil {
+		return fmt.Errorf("error finishing job run: %v", err)
+	}
+	return nil
+}
+
+func (s *PostgresJobStore) ListRuns(ctx context.Context, job string, limit int) ([]scheduler.Run, error) {
+	query := `
+		SELECT id, job_name, instance, scheduled_at, started_at, finished_at, status, error
+		FROM job_run
+		WHERE job_name = $1
+		ORDER BY started_at DESC, id DESC
+	`
+	args := []any{job}
+	if limit > 0 {
+		query += ` LIMIT $2`
+		args = append(args, limit)
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error listing job runs: %v", err)
+	}
+	defer rows.Close()
+
+	runs := make([]scheduler.Run, 0)
+	for rows.Next() {
+		var run scheduler.Run
+		var finishedAt sql.NullTime
+		if err := rows.Scan(&run.ID, &run.Job, &run.Instance, &run.ScheduledAt, &run.StartedAt, &finishedAt, &run.Status, &run.Error); err != nil {
+			return nil, fmt.Errorf("error scanning job run: %v", err)
+		}
+		if finishedAt.Valid {
+			run.FinishedAt = &finishedAt.Time
+		}
+		runs = append(runs, run)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating job runs: %v", err)
+	}
+	return runs, nil
+}
+
+func (s *PostgresJobStore) PruneRuns(ctx context.Context, before time.Time) error {
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM job_run WHERE started_at < $1`, before); err != nil {
+		return fmt.Errorf("error pruning job runs: %v", err)
+	}
+	return nil
+}
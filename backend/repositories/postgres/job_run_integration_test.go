@@ -0,0 +1,96 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"ligain/backend/scheduler"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobStore_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	runTestWithTimeout(t, func(t *testing.T) {
+		testDB := setupTestDB(t)
+		defer testDB.Close()
+
+		store := NewPostgresJobStore(testDB.db)
+		ctx := context.Background()
+		now := time.Now().UTC().Truncate(time.Second)
+
+		t.Run("Lock Is Held By A Single Runner", func(t *testing.T) {
+			unlock, locked, err := store.Lock(ctx, "cleanup")
+			require.NoError(t, err)
+			require.True(t, locked)
+
+			_, locked, err = store.Lock(ctx, "cleanup")
+			require.NoError(t, err)
+			require.False(t, locked)
+
+			// Other jobs have their own lock
+			unlockOther, locked, err := store.Lock(ctx, "purge")
+			require.NoError(t, err)
+			require.True(t, locked)
+			unlockOther()
+
+			unlock()
+			unlock, locked, err = store.Lock(ctx, "cleanup")
+			require.NoError(t, err)
+			require.True(t, locked)
+			unlock()
+		})
+
+		t.Run("Run Starts Once Per Scheduled Time", func(t *testing.T) {
+			run := &scheduler.Run{Job: "cleanup", Instance: "a", ScheduledAt: now, StartedAt: now, Status: scheduler.RunStatusRunning}
+			started, err := store.StartRun(ctx, run)
+			require.NoError(t, err)
+			require.True(t, started)
+			require.NotZero(t, run.ID)
+
+			late := &scheduler.Run{Job: "cleanup", Instance: "b", ScheduledAt: now, StartedAt: now.Add(time.Second), Status: scheduler.RunStatusRunning}
+			started, err = store.StartRun(ctx, late)
+			require.NoError(t, err)
+			require.False(t, started)
+
+			finishedAt := now.Add(2 * time.Second)
+			run.FinishedAt = &finishedAt
+			run.Status = scheduler.RunStatusFailed
+			run.Error = "boom"
+			require.NoError(t, store.FinishRun(ctx, run))
+
+			next := &scheduler.Run{Job: "cleanup", Instance: "b", ScheduledAt: now.Add(time.Hour), StartedAt: now.Add(time.Hour), Status: scheduler.RunStatusRunning}
+			started, err = store.StartRun(ctx, next)
+			require.NoError(t, err)
+			require.True(t, started)
+
+			runs, err := store.ListRuns(ctx, "cleanup", 10)
+			require.NoError(t, err)
+			require.Len(t, runs, 2)
+			require.Equal(t, next.ID, runs[0].ID)
+			require.Nil(t, runs[0].FinishedAt)
+			require.Equal(t, "a", runs[1].Instance)
+			require.Equal(t, scheduler.RunStatusFailed, runs[1].Status)
+			require.Equal(t, "boom", runs[1].Error)
+			require.NotNil(t, runs[1].FinishedAt)
+			require.True(t, finishedAt.Equal(*runs[1].FinishedAt))
+
+			runs, err = store.ListRuns(ctx, "cleanup", 1)
+			require.NoError(t, err)
+			require.Len(t, runs, 1)
+		})
+
+		t.Run("Prune", func(t *testing.T) {
+			require.NoError(t, store.PruneRuns(ctx, now.Add(time.Minute)))
+
+			runs, err := store.ListRuns(ctx, "cleanup", 0)
+			require.NoError(t, err)
+			require.Len(t, runs, 1)
+			require.True(t, now.Add(time.Hour).Equal(runs[0].ScheduledAt))
+		})
+	}, 30*time.Second)
+}
@@ -0,0 +1,70 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"ligain/backend/repositories"
+	"sync"
+)
+
+// PostgresLeaderLock implements repositories.LeaderLock with a session-level advisory lock, held by a
+// connection taken out of the pool. The lock is released by postgres as soon as that connection is lost,
+// so another instance can take over when the leader dies
+type PostgresLeaderLock struct {
+	db   *sql.DB
+	name string
+}
+
+func NewPostgresLeaderLock(db *sql.DB, name string) repositories.LeaderLock {
+	return &PostgresLeaderLock{db: db, name: name}
+}
+
+func (l *PostgresLeaderLock) TryAcquire(ctx context.Context) (repositories.Lease, bool, error) {
+	conn, err := l.db.Conn(ctx)
+	if err != nil {
+		return nil, false, fmt.Errorf("error getting connection to lock leader %s: %v", l.name, err)
+	}
+
+	var locked bool
+	if err := conn.QueryRowContext(ctx, `SELECT pg_try_advisory_lock(hashtext('leader:' || $1))`, l.name).Scan(&locked); err != nil {
+		discardConn(conn)
+		return nil, false, fmt.Errorf("error locking leader %s: %v", l.name, err)
+	}
+	if !locked {
+		_ = conn.Close()
+		return nil, false, nil
+	}
+	return &postgresLease{conn: conn}, true, nil
+}
+
+type postgresLease struct {
+	mu       sync.Mutex
+	conn     *sql.Conn
+	released bool
+}
+
+// Check makes sure the connection holding the lock is still alive: a session lock lasts as long as its session
+func (l *postgresLease) Check(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.released {
+		return repositories.ErrLeadershipLost
+	}
+	if err := l.conn.PingContext(ctx); err != nil {
+		return fmt.Errorf("%w: %v", repositories.ErrLeadershipLost, err)
+	}
+	return nil
+}
+
+// Release closes the session holding the lock rather than unlocking it, which also works when the
+// connection is broken
+func (l *postgresLease) Release() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.released {
+		return
+	}
+	l.released = true
+	discardConn(l.conn)
+}
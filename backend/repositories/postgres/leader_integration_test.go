@@ -0,0 +1,74 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"ligain/backend/repositories"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostgresLeaderLock_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	runTestWithTimeout(t, func(t *testing.T) {
+		testDB := setupTestDB(t)
+		defer testDB.Close()
+
+		ctx := context.Background()
+		// Two instances of the backend competing for the same lock
+		first := NewPostgresLeaderLock(testDB.db, "match-watcher")
+		second := NewPostgresLeaderLock(testDB.db, "match-watcher")
+
+		t.Run("Single Leader", func(t *testing.T) {
+			lease, acquired, err := first.TryAcquire(ctx)
+			require.NoError(t, err)
+			require.True(t, acquired)
+			require.NoError(t, lease.Check(ctx))
+
+			_, acquired, err = second.TryAcquire(ctx)
+			require.NoError(t, err)
+			require.False(t, acquired)
+
+			// Other locks are independent
+			other, acquired, err := NewPostgresLeaderLock(testDB.db, "other").TryAcquire(ctx)
+			require.NoError(t, err)
+			require.True(t, acquired)
+			other.Release()
+
+			lease.Release()
+			require.ErrorIs(t, lease.Check(ctx), repositories.ErrLeadershipLost)
+
+			lease, acquired, err = second.TryAcquire(ctx)
+			require.NoError(t, err)
+			require.True(t, acquired)
+			lease.Release()
+		})
+
+		t.Run("Lost Connection Hands Over", func(t *testing.T) {
+			lease, acquired, err := first.TryAcquire(ctx)
+			require.NoError(t, err)
+			require.True(t, acquired)
+			defer lease.Release()
+
+			_, err = testDB.db.Exec(`
+				SELECT pg_terminate_backend(pid) FROM pg_locks
+				WHERE locktype = 'advisory' AND pid != pg_backend_pid()
+			`)
+			require.NoError(t, err)
+
+			require.Eventually(t, func() bool {
+				return lease.Check(ctx) != nil
+			}, 5*time.Second, 50*time.Millisecond)
+
+			taken, acquired, err := second.TryAcquire(ctx)
+			require.NoError(t, err)
+			require.True(t, acquired)
+			taken.Release()
+		})
+	}, 30*time.Second)
+}
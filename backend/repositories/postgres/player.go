@@ -67,13 +67,13 @@ func (r *PostgresPlayerRepository) GetPlayerByID(ctx context.Context, id string)
 	var player models.PlayerData
 	query := `
 		SELECT id, name, email, provider, provider_id, created_at, updated_at,
-			avatar_object_key, avatar_signed_url, avatar_signed_url_expires_at
+			avatar_object_key, avatar_signed_url, avatar_signed_url_expires_at, role
 		FROM player WHERE id = $1
 	`
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
 		&player.ID, &player.Name, &player.Email, &player.Provider, &player.ProviderID,
 		&player.CreatedAt, &player.UpdatedAt,
-		&player.AvatarObjectKey, &player.AvatarSignedURL, &player.AvatarSignedURLExpiresAt)
+		&player.AvatarObjectKey, &player.AvatarSignedURL, &player.AvatarSignedURLExpiresAt, &player.Role)
 	if err != nil {
 		return nil, err
 	}
@@ -84,13 +84,13 @@ func (r *PostgresPlayerRepository) GetPlayerByEmail(ctx context.Context, email s
 	var player models.PlayerData
 	query := `
 		SELECT id, name, email, provider, provider_id, created_at, updated_at,
-			avatar_object_key, avatar_signed_url, avatar_signed_url_expires_at
+			avatar_object_key, avatar_signed_url, avatar_signed_url_expires_at, role
 		FROM player WHERE email = $1
 	`
 	err := r.db.QueryRowContext(ctx, query, email).Scan(
 		&player.ID, &player.Name, &player.Email, &player.Provider, &player.ProviderID,
 		&player.CreatedAt, &player.UpdatedAt,
-		&player.AvatarObjectKey, &player.AvatarSignedURL, &player.AvatarSignedURLExpiresAt)
+		&player.AvatarObjectKey, &player.AvatarSignedURL, &player.AvatarSignedURLExpiresAt, &player.Role)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
@@ -100,17 +100,25 @@ func (r *PostgresPlayerRepository) GetPlayerByEmail(ctx context.Context, email s
 	return &player, nil
 }
 
+// GetPlayerByProvider also finds the player an account with this identity was merged into
 func (r *PostgresPlayerRepository) GetPlayerByProvider(ctx context.Context, provider, providerID string) (*models.PlayerData, error) {
 	var player models.PlayerData
 	query := `
 		SELECT id, name, email, provider, provider_id, created_at, updated_at,
-			avatar_object_key, avatar_signed_url, avatar_signed_url_expires_at
+			avatar_object_key, avatar_signed_url, avatar_signed_url_expires_at, role
 		FROM player WHERE provider = $1 AND provider_id = $2
+		UNION ALL
+		SELECT p.id, p.name, p.email, p.provider, p.provider_id, p.created_at, p.updated_at,
+			p.avatar_object_key, p.avatar_signed_url, p.avatar_signed_url_expires_at, p.role
+		FROM player_merge m
+		JOIN player p ON p.id = m.target_player_id
+		WHERE m.provider = $1 AND m.provider_id = $2
+		LIMIT 1
 	`
 	err := r.db.QueryRowContext(ctx, query, provider, providerID).Scan(
 		&player.ID, &player.Name, &player.Email, &player.Provider, &player.ProviderID,
 		&player.CreatedAt, &player.UpdatedAt,
-		&player.AvatarObjectKey, &player.AvatarSignedURL, &player.AvatarSignedURLExpiresAt)
+		&player.AvatarObjectKey, &player.AvatarSignedURL, &player.AvatarSignedURLExpiresAt, &player.Role)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
@@ -124,13 +132,13 @@ func (r *PostgresPlayerRepository) GetPlayerByName(ctx context.Context, name str
 	var player models.PlayerData
 	query := `
 		SELECT id, name, email, provider, provider_id, created_at, updated_at,
-			avatar_object_key, avatar_signed_url, avatar_signed_url_expires_at
+			avatar_object_key, avatar_signed_url, avatar_signed_url_expires_at, role
 		FROM player WHERE name = $1
 	`
 	err := r.db.QueryRowContext(ctx, query, name).Scan(
 		&player.ID, &player.Name, &player.Email, &player.Provider, &player.ProviderID,
 		&player.CreatedAt, &player.UpdatedAt,
-		&player.AvatarObjectKey, &player.AvatarSignedURL, &player.AvatarSignedURLExpiresAt)
+		&player.AvatarObjectKey, &player.AvatarSignedURL, &player.AvatarSignedURLExpiresAt, &player.Role)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
@@ -314,3 +322,27 @@ func (r *PostgresPlayerRepository) ClearAvatar(ctx context.Context, playerID str
 	}
 	return nil
 }
+
+func (r *PostgresPlayerRepository) GetPlayersWithAvatarURLExpiringBefore(ctx context.Context, before time.Time) ([]*models.PlayerData, error) {
+	query := `
+		SELECT id, name, avatar_object_key, avatar_signed_url, avatar_signed_url_expires_at
+		FROM player
+		WHERE avatar_object_key IS NOT NULL
+			AND (avatar_signed_url_expires_at IS NULL OR avatar_signed_url_expires_at < $1)
+	`
+	rows, err := r.db.QueryContext(ctx, query, before)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	players := make([]*models.PlayerData, 0)
+	for rows.Next() {
+		var player models.PlayerData
+		if err := rows.Scan(&player.ID, &player.Name, &player.AvatarObjectKey, &player.AvatarSignedURL, &player.AvatarSignedURLExpiresAt); err != nil {
+			return nil, err
+		}
+		players = append(players, &player)
+	}
+	return players, rows.Err()
+}
@@ -173,6 +173,28 @@ func TestPlayerRepository_Integration(t *testing.T) {
 			require.WithinDuration(t, expiresAt, *retrieved.AvatarSignedURLExpiresAt, time.Second)
 		})
 
+		t.Run("GetPlayersWithAvatarURLExpiringBefore returns the avatars to refresh", func(t *testing.T) {
+			ctx := context.Background()
+			expiring := &models.PlayerData{Name: "ExpiringAvatarPlayer"}
+			require.NoError(t, playerRepo.CreatePlayer(ctx, expiring))
+			require.NoError(t, playerRepo.UpdateAvatar(ctx, expiring.ID, "avatars/expiring/avatar.jpg", "https://example.com/expiring", time.Now().Add(time.Minute)))
+			fresh := &models.PlayerData{Name: "FreshAvatarPlayer"}
+			require.NoError(t, playerRepo.CreatePlayer(ctx, fresh))
+			require.NoError(t, playerRepo.UpdateAvatar(ctx, fresh.ID, "avatars/fresh/avatar.jpg", "https://example.com/fresh", time.Now().Add(7*24*time.Hour)))
+			noAvatar := &models.PlayerData{Name: "NoAvatarPlayer"}
+			require.NoError(t, playerRepo.CreatePlayer(ctx, noAvatar))
+
+			players, err := playerRepo.GetPlayersWithAvatarURLExpiringBefore(ctx, time.Now().Add(10*time.Minute))
+			require.NoError(t, err)
+			playerIDs := make([]string, 0, len(players))
+			for _, player := range players {
+				playerIDs = append(playerIDs, player.ID)
+			}
+			require.Contains(t, playerIDs, expiring.ID)
+			require.NotContains(t, playerIDs, fresh.ID)
+			require.NotContains(t, playerIDs, noAvatar.ID)
+		})
+
 		t.Run("UpdateAvatarSignedURL refreshes signed URL only", func(t *testing.T) {
 			// Create player with avatar via UpdateAvatar
 			player := &models.PlayerData{
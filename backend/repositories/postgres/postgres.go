@@ -3,6 +3,11 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
 )
 
 // DBExecutor interface allows using either *sql.DB or *sql.Tx
@@ -23,3 +28,14 @@ type PostgresRepository struct {
 func NewPostgresRepository(db DBExecutor) *PostgresRepository {
 	return &PostgresRepository{db: db}
 }
+
+// OpenDB opens a pool of connections to the database, named after the instance with a unique application_name.
+// The notifications of the game changes carry the name, so that the instance skips its own changes
+func OpenDB(databaseURL string) (*sql.DB, error) {
+	config, err := pgx.ParseConfig(databaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing database URL: %v", err)
+	}
+	config.RuntimeParams["application_name"] = "ligain-backend-" + uuid.NewString()
+	return stdlib.OpenDB(*config), nil
+}
@@ -0,0 +1,65 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"ligain/backend/ratelimit"
+	"time"
+)
+
+// PostgresRateLimitStore implements ratelimit.Store in postgres, so that the instances share their buckets
+type PostgresRateLimitStore struct {
+	db *sql.DB
+}
+
+func NewPostgresRateLimitStore(db *sql.DB) ratelimit.Store {
+	return &PostgresRateLimitStore{db: db}
+}
+
+// Take locks the bucket while it's updated, so that concurrent requests of a client on several instances
+// can't take the same token
+func (s *PostgresRateLimitStore) Take(ctx context.Context, key string, policy ratelimit.Policy, now time.Time) (ratelimit.Result, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return ratelimit.Result{}, fmt.Errorf("error starting rate limit transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	bucketKey := policy.Name + ":" + key
+	bucket := ratelimit.NewBucket(policy, now)
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO rate_limit_bucket (bucket_key, tokens, refilled_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (bucket_key) DO NOTHING
+	`, bucketKey, bucket.Tokens, bucket.RefilledAt); err != nil {
+		return ratelimit.Result{}, fmt.Errorf("error creating rate limit bucket: %v", err)
+	}
+
+	if err := tx.QueryRowContext(ctx,
+		`SELECT tokens, refilled_at FROM rate_limit_bucket WHERE bucket_key = $1 FOR UPDATE`,
+		bucketKey,
+	).Scan(&bucket.Tokens, &bucket.RefilledAt); err != nil {
+		return ratelimit.Result{}, fmt.Errorf("error getting rate limit bucket: %v", err)
+	}
+
+	result := bucket.Take(policy, now)
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE rate_limit_bucket SET tokens = $2, refilled_at = $3 WHERE bucket_key = $1`,
+		bucketKey, bucket.Tokens, bucket.RefilledAt,
+	); err != nil {
+		return ratelimit.Result{}, fmt.Errorf("error updating rate limit bucket: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return ratelimit.Result{}, fmt.Errorf("error committing rate limit transaction: %v", err)
+	}
+	return result, nil
+}
+
+func (s *PostgresRateLimitStore) Prune(ctx context.Context, unusedSince time.Time) error {
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM rate_limit_bucket WHERE refilled_at < $1`, unusedSince); err != nil {
+		return fmt.Errorf("error pruning rate limit buckets: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package postgres
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"ligain/backend/ratelimit"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimitStore_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	runTestWithTimeout(t, func(t *testing.T) {
+		testDB := setupTestDB(t)
+		defer testDB.Close()
+
+		store := NewPostgresRateLimitStore(testDB.db)
+		ctx := context.Background()
+		now := time.Now().UTC().Truncate(time.Second)
+		policy := ratelimit.Policy{Name: "join", Limit: 5, Period: time.Minute}
+
+		t.Run("Concurrent Requests Share The Bucket", func(t *testing.T) {
+			var wg sync.WaitGroup
+			var mu sync.Mutex
+			allowed := 0
+			for i := 0; i < 10; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					result, err := store.Take(ctx, "player:1", policy, now)
+					require.NoError(t, err)
+					if result.Allowed {
+						mu.Lock()
+						allowed++
+						mu.Unlock()
+					}
+				}()
+			}
+			wg.Wait()
+			require.Equal(t, 5, allowed)
+
+			// A token is back after a fifth of the period
+			result, err := store.Take(ctx, "player:1", policy, now.Add(12*time.Second))
+			require.NoError(t, err)
+			require.True(t, result.Allowed)
+			require.Equal(t, 0, result.Remaining)
+		})
+
+		t.Run("Prune", func(t *testing.T) {
+			_, err := store.Take(ctx, "player:2", policy, now.Add(time.Hour))
+			require.NoError(t, err)
+
+			require.NoError(t, store.Prune(ctx, now.Add(time.Minute)))
+
+			var count int
+			require.NoError(t, testDB.db.QueryRow(`SELECT COUNT(*) FROM rate_limit_bucket`).Scan(&count))
+			require.Equal(t, 1, count)
+		})
+	}, 30*time.Second)
+}
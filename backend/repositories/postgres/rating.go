@@ -0,0 +1,177 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"ligain/backend/models"
+	"ligain/backend/repositories"
+)
+
+type PostgresRatingRepository struct {
+	db *sql.DB
+}
+
+// executor returns the appropriate DBExecutor (transaction or db connection).
+func (r *PostgresRatingRepository) executor(ctx context.Context) DBExecutor {
+	if tx := TxFromContext(ctx); tx != nil {
+		return tx
+	}
+	return r.db
+}
+
+func NewPostgresRatingRepository(db *sql.DB) repositories.RatingRepository {
+	return &PostgresRatingRepository{db: db}
+}
+
+func (r *PostgresRatingRepository) GetRatings(ctx context.Context, playerIDs []string) (map[string]*models.PlayerRating, error) {
+	query := `
+		SELECT pr.player_id, p.name, pr.rating, pr.matches_rated, pr.updated_at
+		FROM player_rating pr
+		JOIN player p ON pr.player_id = p.id
+		WHERE pr.player_id = ANY($1)
+	`
+
+	rows, err := r.executor(ctx).QueryContext(ctx, query, playerIDs)
+	if err != nil {
+		return nil, fmt.Errorf("error getting ratings: %v", err)
+	}
+	defer rows.Close()
+
+	ratings := make(map[string]*models.PlayerRating)
+	for rows.Next() {
+		rating, err := scanPlayerRating(rows)
+		if err != nil {
+			return nil, err
+		}
+		ratings[rating.PlayerID] = rating
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating ratings: %v", err)
+	}
+
+	return ratings, nil
+}
+
+func (r *PostgresRatingRepository) SaveRatings(ctx context.Context, ratings []*models.PlayerRating) error {
+	query := `
+		INSERT INTO player_rating (player_id, rating, matches_rated, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (player_id) DO UPDATE
+		SET rating = EXCLUDED.rating,
+			matches_rated = EXCLUDED.matches_rated,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	for _, rating := range ratings {
+		_, err := r.executor(ctx).ExecContext(ctx, query, rating.PlayerID, rating.Rating, rating.MatchesRated, rating.UpdatedAt)
+		if err != nil {
+			return fmt.Errorf("error saving rating of player %s: %v", rating.PlayerID, err)
+		}
+	}
+
+	return nil
+}
+
+func (r *PostgresRatingRepository) GetTopRatings(ctx context.Context, limit int) ([]*models.PlayerRating, error) {
+	query := `
+		SELECT pr.player_id, p.name, pr.rating, pr.matches_rated, pr.updated_at
+		FROM player_rating pr
+		JOIN player p ON pr.player_id = p.id
+		ORDER BY pr.rating DESC, pr.player_id
+		LIMIT $1
+	`
+
+	rows, err := r.executor(ctx).QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error getting top ratings: %v", err)
+	}
+	defer rows.Close()
+
+	ratings := make([]*models.PlayerRating, 0)
+	for rows.Next() {
+		rating, err := scanPlayerRating(rows)
+		if err != nil {
+			return nil, err
+		}
+		ratings = append(ratings, rating)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating top ratings: %v", err)
+	}
+
+	return ratings, nil
+}
+
+func (r *PostgresRatingRepository) GetMatchRatingChanges(ctx context.Context, gameID, matchID string) (map[string]*models.RatingChange, error) {
+	query := `
+		SELECT game_id, match_local_id, player_id, version, delta
+		FROM player_rating_change
+		WHERE game_id = $1 AND match_local_id = $2
+	`
+
+	rows, err := r.executor(ctx).QueryContext(ctx, query, gameID, matchID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting rating changes: %v", err)
+	}
+	defer rows.Close()
+
+	changes := make(map[string]*models.RatingChange)
+	for rows.Next() {
+		var change models.RatingChange
+		if err := rows.Scan(&change.GameID, &change.MatchID, &change.PlayerID, &change.Version, &change.Delta); err != nil {
+			return nil, fmt.Errorf("error scanning rating change: %v", err)
+		}
+		changes[change.PlayerID] = &change
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rating changes: %v", err)
+	}
+
+	return changes, nil
+}
+
+// SaveMatchRatings implements RatingRepository, within the transaction of ctx or a transaction of its own
+func (r *PostgresRatingRepository) SaveMatchRatings(ctx context.Context, gameID, matchID string, ratings []*models.PlayerRating, changes []*models.RatingChange) error {
+	if TxFromContext(ctx) != nil {
+		return r.saveMatchRatings(ctx, gameID, matchID, ratings, changes)
+	}
+
+	return NewUnitOfWork(r.db).WithinTx(ctx, func(txCtx context.Context) error {
+		return r.saveMatchRatings(txCtx, gameID, matchID, ratings, changes)
+	})
+}
+
+func (r *PostgresRatingRepository) saveMatchRatings(ctx context.Context, gameID, matchID string, ratings []*models.PlayerRating, changes []*models.RatingChange) error {
+	if err := r.SaveRatings(ctx, ratings); err != nil {
+		return err
+	}
+
+	_, err := r.executor(ctx).ExecContext(ctx,
+		`DELETE FROM player_rating_change WHERE game_id = $1 AND match_local_id = $2`,
+		gameID, matchID,
+	)
+	if err != nil {
+		return fmt.Errorf("error deleting rating changes: %v", err)
+	}
+	query := `
+		INSERT INTO player_rating_change (game_id, match_local_id, player_id, version, delta)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+	for _, change := range changes {
+		_, err := r.executor(ctx).ExecContext(ctx, query, gameID, matchID, change.PlayerID, change.Version, change.Delta)
+		if err != nil {
+			return fmt.Errorf("error saving rating change of player %s: %v", change.PlayerID, err)
+		}
+	}
+
+	return nil
+}
+
+func scanPlayerRating(rows *sql.Rows) (*models.PlayerRating, error) {
+	var rating models.PlayerRating
+	if err := rows.Scan(&rating.PlayerID, &rating.PlayerName, &rating.Rating, &rating.MatchesRated, &rating.UpdatedAt); err != nil {
+		return nil, fmt.Errorf("error scanning rating: %v", err)
+	}
+	return &rating, nil
+}
@@ -0,0 +1,61 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"ligain/backend/models"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRatingRepository_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	runTestWithTimeout(t, func(t *testing.T) {
+		testDB := setupTestDB(t)
+		defer testDB.Close()
+
+		ratingRepo := NewPostgresRatingRepository(testDB.db)
+		ctx := context.Background()
+
+		player1 := "123e4567-e89b-12d3-a456-426614174201"
+		player2 := "123e4567-e89b-12d3-a456-426614174202"
+		for _, player := range []struct{ id, name string }{{player1, "Rated One"}, {player2, "Rated Two"}} {
+			_, err := testDB.db.Exec(`INSERT INTO player (id, name) VALUES ($1, $2)`, player.id, player.name)
+			require.NoError(t, err)
+		}
+
+		t.Run("Save and Get Ratings", func(t *testing.T) {
+			now := time.Now().UTC().Truncate(time.Second)
+			err := ratingRepo.SaveRatings(ctx, []*models.PlayerRating{
+				{PlayerID: player1, Rating: 1510.5, MatchesRated: 1, UpdatedAt: now},
+				{PlayerID: player2, Rating: 1489.5, MatchesRated: 1, UpdatedAt: now},
+			})
+			require.NoError(t, err)
+
+			ratings, err := ratingRepo.GetRatings(ctx, []string{player1})
+			require.NoError(t, err)
+			require.Len(t, ratings, 1)
+			require.Equal(t, 1510.5, ratings[player1].Rating)
+			require.Equal(t, "Rated One", ratings[player1].PlayerName)
+		})
+
+		t.Run("Save replaces existing rating", func(t *testing.T) {
+			err := ratingRepo.SaveRatings(ctx, []*models.PlayerRating{
+				{PlayerID: player2, Rating: 1530, MatchesRated: 2, UpdatedAt: time.Now()},
+			})
+			require.NoError(t, err)
+
+			top, err := ratingRepo.GetTopRatings(ctx, 10)
+			require.NoError(t, err)
+			require.Len(t, top, 2)
+			require.Equal(t, player2, top[0].PlayerID)
+			require.Equal(t, 2, top[0].MatchesRated)
+			require.Equal(t, player1, top[1].PlayerID)
+		})
+	}, 30*time.Second)
+}
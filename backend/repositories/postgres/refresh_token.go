@@ -0,0 +1,167 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"ligain/backend/models"
+	"ligain/backend/repositories"
+	"time"
+)
+
+type PostgresRefreshTokenRepository struct {
+	db *sql.DB
+}
+
+// executor returns the appropriate DBExecutor (transaction or db connection).
+func (r *PostgresRefreshTokenRepository) executor(ctx context.Context) DBExecutor {
+	if tx := TxFromContext(ctx); tx != nil {
+		return tx
+	}
+	return r.db
+}
+
+func NewPostgresRefreshTokenRepository(db *sql.DB) repositories.RefreshTokenRepository {
+	return &PostgresRefreshTokenRepository{db: db}
+}
+
+func (r *PostgresRefreshTokenRepository) CreateRefreshToken(ctx context.Context, token *models.RefreshToken) error {
+	query := `
+		INSERT INTO refresh_token (player_id, family_id, token_hash, device_name, platform, app_version, ip_address, session_started_at, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id
+	`
+
+	err := r.executor(ctx).QueryRowContext(ctx, query,
+		token.PlayerID,
+		token.FamilyID,
+		token.TokenHash,
+		nullIfEmpty(token.Device.Name),
+		nullIfEmpty(token.Device.Platform),
+		nullIfEmpty(token.Device.AppVersion),
+		nullIfEmpty(token.Device.IPAddress),
+		token.SessionStartedAt,
+		token.ExpiresAt,
+		token.CreatedAt,
+	).Scan(&token.ID)
+	if err != nil {
+		return fmt.Errorf("error creating refresh token: %v", err)
+	}
+
+	return nil
+}
+
+const refreshTokenColumns = `id, player_id, family_id, token_hash, device_name, platform, app_version, ip_address, session_started_at, expires_at, created_at, revoked_at`
+
+func (r *PostgresRefreshTokenRepository) GetRefreshToken(ctx context.Context, tokenHash string) (*models.RefreshToken, error) {
+	query := `SELECT ` + refreshTokenColumns + ` FROM refresh_token WHERE token_hash = $1`
+
+	token, err := scanRefreshToken(r.executor(ctx).QueryRowContext(ctx, query, tokenHash))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting refresh token: %v", err)
+	}
+
+	return token, nil
+}
+
+func (r *PostgresRefreshTokenRepository) GetActiveRefreshTokens(ctx context.Context, playerID string, now time.Time) ([]*models.RefreshToken, error) {
+	query := `
+		SELECT ` + refreshTokenColumns + `
+		FROM refresh_token
+		WHERE player_id = $1 AND revoked_at IS NULL AND expires_at > $2
+		ORDER BY created_at DESC
+	`
+
+	rows, err := r.executor(ctx).QueryContext(ctx, query, playerID, now)
+	if err != nil {
+		return nil, fmt.Errorf("error getting active refresh tokens: %v", err)
+	}
+	defer rows.Close()
+
+	tokens := make([]*models.RefreshToken, 0)
+	for rows.Next() {
+		token, err := scanRefreshToken(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning refresh token: %v", err)
+		}
+		tokens = append(tokens, token)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating refresh tokens: %v", err)
+	}
+
+	return tokens, nil
+}
+
+func (r *PostgresRefreshTokenRepository) RevokeRefreshToken(ctx context.Context, tokenID string, revokedAt time.Time) (bool, error) {
+	query := `UPDATE refresh_token SET revoked_at = $2 WHERE id = $1 AND revoked_at IS NULL`
+
+	result, err := r.executor(ctx).ExecContext(ctx, query, tokenID, revokedAt)
+	if err != nil {
+		return false, fmt.Errorf("error revoking refresh token: %v", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("error getting rows affected: %v", err)
+	}
+
+	return rowsAffected == 1, nil
+}
+
+func (r *PostgresRefreshTokenRepository) RevokeRefreshTokenFamily(ctx context.Context, familyID string, revokedAt time.Time) error {
+	query := `UPDATE refresh_token SET revoked_at = $2 WHERE family_id = $1 AND revoked_at IS NULL`
+
+	if _, err := r.executor(ctx).ExecContext(ctx, query, familyID, revokedAt); err != nil {
+		return fmt.Errorf("error revoking refresh token family: %v", err)
+	}
+
+	return nil
+}
+
+func (r *PostgresRefreshTokenRepository) DeleteExpiredRefreshTokens(ctx context.Context, before time.Time) error {
+	query := `DELETE FROM refresh_token WHERE expires_at < $1`
+
+	if _, err := r.executor(ctx).ExecContext(ctx, query, before); err != nil {
+		return fmt.Errorf("error deleting expired refresh tokens: %v", err)
+	}
+
+	return nil
+}
+
+// scanRefreshToken scans a row selected with refreshTokenColumns
+func scanRefreshToken(row interface{ Scan(dest ...any) error }) (*models.RefreshToken, error) {
+	var token models.RefreshToken
+	var deviceName, platform, appVersion, ipAddress sql.NullString
+	var revokedAt sql.NullTime
+	err := row.Scan(
+		&token.ID,
+		&token.PlayerID,
+		&token.FamilyID,
+		&token.TokenHash,
+		&deviceName,
+		&platform,
+		&appVersion,
+		&ipAddress,
+		&token.SessionStartedAt,
+		&token.ExpiresAt,
+		&token.CreatedAt,
+		&revokedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	token.Device = models.DeviceInfo{
+		Name:       deviceName.String,
+		Platform:   platform.String,
+		AppVersion: appVersion.String,
+		IPAddress:  ipAddress.String,
+	}
+	if revokedAt.Valid {
+		token.RevokedAt = &revokedAt.Time
+	}
+	return &token, nil
+}
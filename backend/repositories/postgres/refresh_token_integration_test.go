@@ -0,0 +1,99 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"ligain/backend/models"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRefreshTokenRepository_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	runTestWithTimeout(t, func(t *testing.T) {
+		testDB := setupTestDB(t)
+		defer testDB.Close()
+
+		refreshRepo := NewPostgresRefreshTokenRepository(testDB.db)
+		ctx := context.Background()
+
+		playerID := "123e4567-e89b-12d3-a456-426614174401"
+		familyID := "123e4567-e89b-12d3-a456-426614174402"
+		_, err := testDB.db.Exec(`INSERT INTO player (id, name) VALUES ($1, $2)`, playerID, "Refresher")
+		require.NoError(t, err)
+
+		now := time.Now().UTC().Truncate(time.Second)
+		newToken := func(hash string, expiresAt time.Time) *models.RefreshToken {
+			token := &models.RefreshToken{
+				PlayerID:         playerID,
+				FamilyID:         familyID,
+				TokenHash:        hash,
+				Device:           models.DeviceInfo{Name: "Pixel", Platform: "android", AppVersion: "1.4.0", IPAddress: "10.0.0.1"},
+				SessionStartedAt: now,
+				ExpiresAt:        expiresAt,
+				CreatedAt:        now,
+			}
+			require.NoError(t, refreshRepo.CreateRefreshToken(ctx, token))
+			require.NotEmpty(t, token.ID)
+			return token
+		}
+
+		t.Run("Rotate and Revoke Family", func(t *testing.T) {
+			first := newToken("hash-first", now.Add(time.Hour))
+			second := newToken("hash-second", now.Add(time.Hour))
+
+			stored, err := refreshRepo.GetRefreshToken(ctx, "hash-first")
+			require.NoError(t, err)
+			require.NotNil(t, stored)
+			require.Equal(t, playerID, stored.PlayerID)
+			require.Equal(t, "Pixel", stored.Device.Name)
+			require.Equal(t, "10.0.0.1", stored.Device.IPAddress)
+			require.True(t, now.Equal(stored.SessionStartedAt))
+			require.False(t, stored.IsRevoked())
+
+			revoked, err := refreshRepo.RevokeRefreshToken(ctx, first.ID, now)
+			require.NoError(t, err)
+			require.True(t, revoked)
+
+			active, err := refreshRepo.GetActiveRefreshTokens(ctx, playerID, now)
+			require.NoError(t, err)
+			require.Len(t, active, 1)
+			require.Equal(t, second.ID, active[0].ID)
+
+			// A token can only be revoked once, so that concurrent refreshes can't both succeed
+			revoked, err = refreshRepo.RevokeRefreshToken(ctx, first.ID, now)
+			require.NoError(t, err)
+			require.False(t, revoked)
+
+			require.NoError(t, refreshRepo.RevokeRefreshTokenFamily(ctx, familyID, now))
+			stored, err = refreshRepo.GetRefreshToken(ctx, second.TokenHash)
+			require.NoError(t, err)
+			require.True(t, stored.IsRevoked())
+		})
+
+		t.Run("Missing Token", func(t *testing.T) {
+			stored, err := refreshRepo.GetRefreshToken(ctx, "hash-missing")
+			require.NoError(t, err)
+			require.Nil(t, stored)
+		})
+
+		t.Run("Delete Expired Tokens", func(t *testing.T) {
+			newToken("hash-expired", now.Add(-time.Minute))
+			newToken("hash-valid", now.Add(time.Hour))
+
+			require.NoError(t, refreshRepo.DeleteExpiredRefreshTokens(ctx, now))
+
+			stored, err := refreshRepo.GetRefreshToken(ctx, "hash-expired")
+			require.NoError(t, err)
+			require.Nil(t, stored)
+			stored, err = refreshRepo.GetRefreshToken(ctx, "hash-valid")
+			require.NoError(t, err)
+			require.NotNil(t, stored)
+		})
+	}, 30*time.Second)
+}
@@ -42,3 +42,25 @@ func TxFromContext(ctx context.Context) *sql.Tx {
 	tx, _ := ctx.Value(txCtxKey{}).(*sql.Tx)
 	return tx
 }
+
+// DryRunUnitOfWork implements UnitOfWork with transactions that are always rolled back,
+// so that fn can be run to see what it would change without changing anything
+type DryRunUnitOfWork struct {
+	db *sql.DB
+}
+
+// NewDryRunUnitOfWork creates a new DryRunUnitOfWork.
+func NewDryRunUnitOfWork(db *sql.DB) *DryRunUnitOfWork {
+	return &DryRunUnitOfWork{db: db}
+}
+
+// WithinTx executes fn within a transaction, then rolls it back.
+func (u *DryRunUnitOfWork) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	tx, err := u.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	return fn(context.WithValue(ctx, txCtxKey{}, tx))
+}
@@ -0,0 +1,109 @@
+package repositories
+
+import (
+	"context"
+	"ligain/backend/models"
+	"sort"
+	"sync"
+)
+
+// RatingRepository stores the cross-game skill rating of players
+type RatingRepository interface {
+	// GetRatings returns the ratings of the given players, keyed by player id. Players never rated are missing
+	GetRatings(ctx context.Context, playerIDs []string) (map[string]*models.PlayerRating, error)
+	// SaveRatings creates or replaces the ratings
+	SaveRatings(ctx context.Context, ratings []*models.PlayerRating) error
+	// GetTopRatings returns the best rated players, best first
+	GetTopRatings(ctx context.Context, limit int) ([]*models.PlayerRating, error)
+	// GetMatchRatingChanges returns the changes the last scoring of a match made to the ratings, keyed by player id
+	GetMatchRatingChanges(ctx context.Context, gameID, matchID string) (map[string]*models.RatingChange, error)
+	// SaveMatchRatings saves the ratings along with the changes a scoring of a match made to them, at once.
+	// The changes replace the ones of the previous scoring of the match
+	SaveMatchRatings(ctx context.Context, gameID, matchID string, ratings []*models.PlayerRating, changes []*models.RatingChange) error
+}
+
+// InMemoryRatingRepository implements RatingRepository using in-memory storage
+type InMemoryRatingRepository struct {
+	mu      sync.RWMutex
+	ratings map[string]models.PlayerRating            // playerID -> rating
+	changes map[string]map[string]models.RatingChange // gameID:matchID -> playerID -> change
+}
+
+// NewInMemoryRatingRepository creates a new in-memory rating repository
+func NewInMemoryRatingRepository() *InMemoryRatingRepository {
+	return &InMemoryRatingRepository{
+		ratings: make(map[string]models.PlayerRating),
+		changes: make(map[string]map[string]models.RatingChange),
+	}
+}
+
+func (r *InMemoryRatingRepository) GetRatings(ctx context.Context, playerIDs []string) (map[string]*models.PlayerRating, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ratings := make(map[string]*models.PlayerRating)
+	for _, playerID := range playerIDs {
+		if rating, exists := r.ratings[playerID]; exists {
+			ratings[playerID] = &rating
+		}
+	}
+	return ratings, nil
+}
+
+func (r *InMemoryRatingRepository) SaveRatings(ctx context.Context, ratings []*models.PlayerRating) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, rating := range ratings {
+		r.ratings[rating.PlayerID] = *rating
+	}
+	return nil
+}
+
+func (r *InMemoryRatingRepository) GetTopRatings(ctx context.Context, limit int) ([]*models.PlayerRating, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ratings := make([]*models.PlayerRating, 0, len(r.ratings))
+	for _, rating := range r.ratings {
+		rating := rating
+		ratings = append(ratings, &rating)
+	}
+	sort.Slice(ratings, func(i, j int) bool {
+		if ratings[i].Rating != ratings[j].Rating {
+			return ratings[i].Rating > ratings[j].Rating
+		}
+		return ratings[i].PlayerID < ratings[j].PlayerID
+	})
+	if len(ratings) > limit {
+		ratings = ratings[:limit]
+	}
+	return ratings, nil
+}
+
+func (r *InMemoryRatingRepository) GetMatchRatingChanges(ctx context.Context, gameID, matchID string) (map[string]*models.RatingChange, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	changes := make(map[string]*models.RatingChange)
+	for playerID, change := range r.changes[gameID+":"+matchID] {
+		change := change
+		changes[playerID] = &change
+	}
+	return changes, nil
+}
+
+func (r *InMemoryRatingRepository) SaveMatchRatings(ctx context.Context, gameID, matchID string, ratings []*models.PlayerRating, changes []*models.RatingChange) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, rating := range ratings {
+		r.ratings[rating.PlayerID] = *rating
+	}
+	matchChanges := make(map[string]models.RatingChange)
+	for _, change := range changes {
+		matchChanges[change.PlayerID] = *change
+	}
+	r.changes[gameID+":"+matchID] = matchChanges
+	return nil
+}
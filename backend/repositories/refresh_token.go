@@ -0,0 +1,123 @@
+package repositories
+
+import (
+	"context"
+	"ligain/backend/models"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RefreshTokenRepository stores the refresh tokens of the players, by hash
+type RefreshTokenRepository interface {
+	// CreateRefreshToken stores a refresh token and sets its id
+	CreateRefreshToken(ctx context.Context, token *models.RefreshToken) error
+	// GetRefreshToken returns the refresh token with the given hash, or nil if it doesn't exist
+	GetRefreshToken(ctx context.Context, tokenHash string) (*models.RefreshToken, error)
+	// GetActiveRefreshTokens returns the tokens of a player that are neither revoked nor expired, newest first.
+	// Rotation revokes the previous token, so there is one per session
+	GetActiveRefreshTokens(ctx context.Context, playerID string, now time.Time) ([]*models.RefreshToken, error)
+	// RevokeRefreshToken revokes a token. It returns false if the token was already revoked,
+	// so that two concurrent refreshes with the same token can't both succeed
+	RevokeRefreshToken(ctx context.Context, tokenID string, revokedAt time.Time) (bool, error)
+	// RevokeRefreshTokenFamily revokes every token of a family that is not revoked yet
+	RevokeRefreshTokenFamily(ctx context.Context, familyID string, revokedAt time.Time) error
+	// DeleteExpiredRefreshTokens removes the tokens that expired before the given time
+	DeleteExpiredRefreshTokens(ctx context.Context, before time.Time) error
+}
+
+// InMemoryRefreshTokenRepository implements RefreshTokenRepository using in-memory storage
+type InMemoryRefreshTokenRepository struct {
+	mu     sync.RWMutex
+	tokens map[string]*models.RefreshToken // tokenHash -> token
+}
+
+// NewInMemoryRefreshTokenRepository creates a new in-memory refresh token repository
+func NewInMemoryRefreshTokenRepository() *InMemoryRefreshTokenRepository {
+	return &InMemoryRefreshTokenRepository{
+		tokens: make(map[string]*models.RefreshToken),
+	}
+}
+
+func (r *InMemoryRefreshTokenRepository) CreateRefreshToken(ctx context.Context, token *models.RefreshToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	token.ID = uuid.New().String()
+	stored := *token
+	r.tokens[token.TokenHash] = &stored
+	return nil
+}
+
+func (r *InMemoryRefreshTokenRepository) GetRefreshToken(ctx context.Context, tokenHash string) (*models.RefreshToken, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	token, exists := r.tokens[tokenHash]
+	if !exists {
+		return nil, nil
+	}
+	result := *token
+	return &result, nil
+}
+
+func (r *InMemoryRefreshTokenRepository) GetActiveRefreshTokens(ctx context.Context, playerID string, now time.Time) ([]*models.RefreshToken, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tokens := make([]*models.RefreshToken, 0)
+	for _, token := range r.tokens {
+		if token.PlayerID == playerID && token.RevokedAt == nil && now.Before(token.ExpiresAt) {
+			result := *token
+			tokens = append(tokens, &result)
+		}
+	}
+	sort.Slice(tokens, func(i, j int) bool {
+		return tokens[i].CreatedAt.After(tokens[j].CreatedAt)
+	})
+	return tokens, nil
+}
+
+func (r *InMemoryRefreshTokenRepository) RevokeRefreshToken(ctx context.Context, tokenID string, revokedAt time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, token := range r.tokens {
+		if token.ID == tokenID {
+			if token.RevokedAt != nil {
+				return false, nil
+			}
+			revoked := revokedAt
+			token.RevokedAt = &revoked
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *InMemoryRefreshTokenRepository) RevokeRefreshTokenFamily(ctx context.Context, familyID string, revokedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, token := range r.tokens {
+		if token.FamilyID == familyID && token.RevokedAt == nil {
+			revoked := revokedAt
+			token.RevokedAt = &revoked
+		}
+	}
+	return nil
+}
+
+func (r *InMemoryRefreshTokenRepository) DeleteExpiredRefreshTokens(ctx context.Context, before time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for hash, token := range r.tokens {
+		if token.ExpiresAt.Before(before) {
+			delete(r.tokens, hash)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package routes
+
+import (
+	"errors"
+	"ligain/backend/middleware"
+	"ligain/backend/models"
+	"ligain/backend/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+// AccountMergeHandler handles the merge of the accounts a player created on different devices
+type AccountMergeHandler struct {
+	mergeService services.AccountMergeService
+	authService  services.AuthServiceInterface
+}
+
+// NewAccountMergeHandler creates a new AccountMergeHandler
+func NewAccountMergeHandler(mergeService services.AccountMergeService, authService services.AuthServiceInterface) *AccountMergeHandler {
+	return &AccountMergeHandler{
+		mergeService: mergeService,
+		authService:  authService,
+	}
+}
+
+// SetupRoutes registers the account merge routes on the router
+func (h *AccountMergeHandler) SetupRoutes(router *gin.Engine) {
+	router.POST("/api/auth/merge", middleware.PlayerAuth(h.authService), h.mergeAccount)
+}
+
+// mergeAccount moves the account the body's access token belongs to into the authenticated player's account.
+// The app gets that token by signing in to the other account first
+func (h *AccountMergeHandler) mergeAccount(c *gin.Context) {
+	player, ok := getAuthenticatedPlayer(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		AccessToken string `json:"accessToken" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "accessToken of the account to merge is required"})
+		return
+	}
+
+	summary, err := h.mergeService.MergeAccount(c.Request.Context(), player.GetID(), req.AccessToken)
+	if err != nil {
+		var invalidMergeErr *models.InvalidMergeError
+		var playerNotFoundErr *models.PlayerNotFoundError
+		switch {
+		case errors.As(err, &invalidMergeErr):
+			c.JSON(http.StatusBadRequest, gin.H{"error": invalidMergeErr.Error()})
+		case errors.As(err, &playerNotFoundErr):
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Player not found"})
+		default:
+			log.Errorf("Failed to merge account into player %s: %v", player.GetID(), err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to merge accounts"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"player":    toPlayerResponse(summary.Player),
+		"movedBets": summary.MovedBets,
+		"conflicts": summary.Conflicts,
+		"gameIds":   summary.GameIDs,
+	})
+}
@@ -0,0 +1,106 @@
+package routes
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"ligain/backend/models"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockAccountMergeService struct {
+	mock.Mock
+}
+
+func (m *MockAccountMergeService) MergeAccount(ctx context.Context, playerID string, otherAccessToken string) (*models.MergeSummary, error) {
+	args := m.Called(ctx, playerID, otherAccessToken)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.MergeSummary), args.Error(1)
+}
+
+func (m *MockAccountMergeService) MergePlayers(ctx context.Context, sourceID string, targetID string) (*models.MergeSummary, error) {
+	args := m.Called(ctx, sourceID, targetID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.MergeSummary), args.Error(1)
+}
+
+func setupAccountMergeRouter(mergeService *MockAccountMergeService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	authService := &MockAuthService{player: &models.PlayerData{ID: "kept-player", Name: "Kept"}}
+	router := gin.New()
+	NewAccountMergeHandler(mergeService, authService).SetupRoutes(router)
+	return router
+}
+
+func postMerge(router *gin.Engine, body string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("POST", "/api/auth/merge", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer test-token")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestMergeAccountHandler(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mergeService := &MockAccountMergeService{}
+		mergeService.On("MergeAccount", mock.Anything, "kept-player", "other-token").Return(&models.MergeSummary{
+			Player:    &models.PlayerData{ID: "kept-player", Name: "Kept"},
+			MovedBets: 2,
+			Conflicts: []models.BetConflict{{GameID: "game1", MatchID: "match1", KeptMergedBet: true}},
+			GameIDs:   []string{"game1"},
+		}, nil)
+
+		w := postMerge(setupAccountMergeRouter(mergeService), `{"accessToken": "other-token"}`)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response struct {
+			Player    map[string]interface{} `json:"player"`
+			MovedBets int                    `json:"movedBets"`
+			Conflicts []models.BetConflict   `json:"conflicts"`
+			GameIDs   []string               `json:"gameIds"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "kept-player", response.Player["id"])
+		assert.Equal(t, 2, response.MovedBets)
+		assert.True(t, response.Conflicts[0].KeptMergedBet)
+		assert.Equal(t, []string{"game1"}, response.GameIDs)
+	})
+
+	t.Run("MissingToken", func(t *testing.T) {
+		mergeService := &MockAccountMergeService{}
+
+		w := postMerge(setupAccountMergeRouter(mergeService), `{}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mergeService.AssertNotCalled(t, "MergeAccount", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("InvalidMerge", func(t *testing.T) {
+		mergeService := &MockAccountMergeService{}
+		mergeService.On("MergeAccount", mock.Anything, "kept-player", "kept-token").Return(nil, &models.InvalidMergeError{Reason: "both accounts are the same"})
+
+		w := postMerge(setupAccountMergeRouter(mergeService), `{"accessToken": "kept-token"}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "both accounts are the same")
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		mergeService := &MockAccountMergeService{}
+		mergeService.On("MergeAccount", mock.Anything, "kept-player", "other-token").Return(nil, errors.New("db error"))
+
+		w := postMerge(setupAccountMergeRouter(mergeService), `{"accessToken": "other-token"}`)
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
@@ -0,0 +1,81 @@
+package routes
+
+import (
+	"errors"
+	"ligain/backend/middleware"
+	"ligain/backend/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+// ActivityHandler handles the activity feed routes
+type ActivityHandler struct {
+	activityService services.ActivityService
+	authService     services.AuthServiceInterface
+}
+
+// NewActivityHandler creates a new ActivityHandler
+func NewActivityHandler(activityService services.ActivityService, authService services.AuthServiceInterface) *ActivityHandler {
+	return &ActivityHandler{
+		activityService: activityService,
+		authService:     authService,
+	}
+}
+
+// SetupRoutes registers activity routes on the router
+func (h *ActivityHandler) SetupRoutes(router *gin.Engine) {
+	router.GET("/api/game/:game-id/activity", middleware.PlayerAuth(h.authService), h.getActivityFeed)
+}
+
+// getActivityFeed returns a page of the activity feed of a game, newest first.
+// The "before" query parameter is the id of the last activity of the previous page
+func (h *ActivityHandler) getActivityFeed(c *gin.Context) {
+	player, ok := getAuthenticatedPlayer(c)
+	if !ok {
+		return
+	}
+
+	var beforeID int64
+	if beforeParam := c.Query("before"); beforeParam != "" {
+		parsed, err := strconv.ParseInt(beforeParam, 10, 64)
+		if err != nil || parsed <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "before must be a positive integer"})
+			return
+		}
+		beforeID = parsed
+	}
+	limit := services.DefaultActivityPageSize
+	if limitParam := c.Query("limit"); limitParam != "" {
+		parsed, err := strconv.Atoi(limitParam)
+		if err != nil || parsed <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive integer"})
+			return
+		}
+		limit = parsed
+	}
+
+	activities, err := h.activityService.GetFeed(c.Request.Context(), c.Param("game-id"), player, beforeID, limit)
+	if errors.Is(err, services.ErrPlayerNotInGame) {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		log.Errorf("Failed to get activity feed: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get activity feed"})
+		return
+	}
+
+	// There is no next page once the feed returned less than a full page
+	var nextBefore *int64
+	if len(activities) > 0 && len(activities) >= min(limit, services.MaxActivityPageSize) {
+		nextBefore = &activities[len(activities)-1].ID
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"activities": activities,
+		"nextBefore": nextBefore,
+	})
+}
@@ -0,0 +1,82 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"ligain/backend/models"
+	"ligain/backend/repositories"
+	"ligain/backend/services"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupActivityRouter(t *testing.T, activityCount int) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	player := &models.PlayerData{ID: "player-1", Name: "First"}
+	gamePlayerRepo := repositories.NewInMemoryGamePlayerRepository(repositories.NewInMemoryPlayerRepository())
+	require.NoError(t, gamePlayerRepo.AddPlayerToGame(context.Background(), "game-1", player.ID))
+
+	activityService := services.NewActivityService(repositories.NewInMemoryActivityRepository(), gamePlayerRepo)
+	for i := 0; i < activityCount; i++ {
+		require.NoError(t, activityService.OnActivity(models.NewPlayerActivity("game-1", models.ActivityPlayerJoined, player)))
+	}
+
+	handler := NewActivityHandler(activityService, &MockAuthService{player: player})
+	router := gin.New()
+	handler.SetupRoutes(router)
+	return router
+}
+
+type activityFeedResponse struct {
+	Activities []models.Activity `json:"activities"`
+	NextBefore *int64            `json:"nextBefore"`
+}
+
+func getActivityFeed(t *testing.T, router *gin.Engine, path string) (int, activityFeedResponse) {
+	req, _ := http.NewRequest("GET", path, nil)
+	req.Header.Set("Authorization", "Bearer test-token")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var response activityFeedResponse
+	if w.Code == http.StatusOK {
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	}
+	return w.Code, response
+}
+
+func TestGetActivityFeed_Pagination(t *testing.T) {
+	router := setupActivityRouter(t, 3)
+
+	code, firstPage := getActivityFeed(t, router, "/api/game/game-1/activity?limit=2")
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, firstPage.Activities, 2)
+	assert.Equal(t, models.ActivityPlayerJoined, firstPage.Activities[0].Type)
+	require.NotNil(t, firstPage.NextBefore)
+	assert.Equal(t, firstPage.Activities[1].ID, *firstPage.NextBefore)
+
+	code, secondPage := getActivityFeed(t, router, fmt.Sprintf("/api/game/game-1/activity?limit=2&before=%d", *firstPage.NextBefore))
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, secondPage.Activities, 1)
+	assert.Nil(t, secondPage.NextBefore)
+}
+
+func TestGetActivityFeed_Errors(t *testing.T) {
+	router := setupActivityRouter(t, 0)
+
+	code, _ := getActivityFeed(t, router, "/api/game/game-1/activity?before=abc")
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	code, _ = getActivityFeed(t, router, "/api/game/game-1/activity?limit=0")
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	code, _ = getActivityFeed(t, router, "/api/game/game-2/activity")
+	assert.Equal(t, http.StatusForbidden, code)
+}
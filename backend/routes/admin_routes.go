@@ -0,0 +1,332 @@
+package routes
+
+import (
+	"errors"
+	"ligain/backend/middleware"
+	"ligain/backend/models"
+	"ligain/backend/repositories"
+	"ligain/backend/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+// AdminHandler handles the operator API, used to inspect and fix the production state without SQL
+type AdminHandler struct {
+	adminService services.AdminService
+	authService  services.AuthServiceInterface
+}
+
+// NewAdminHandler creates a new AdminHandler
+func NewAdminHandler(adminService services.AdminService, authService services.AuthServiceInterface) *AdminHandler {
+	return &AdminHandler{
+		adminService: adminService,
+		authService:  authService,
+	}
+}
+
+// ForceMatchResultRequest is the final score forced on a match
+type ForceMatchResultRequest struct {
+	HomeGoals *int `json:"homeGoals" binding:"required"`
+	AwayGoals *int `json:"awayGoals" binding:"required"`
+}
+
+// SetPlayerRoleRequest is the role given to a player
+type SetPlayerRoleRequest struct {
+	Role string `json:"role" binding:"required"`
+}
+
+// SetupRoutes registers the admin routes on the router. They are all restricted to the admins
+func (h *AdminHandler) SetupRoutes(router *gin.Engine) {
+	admin := router.Group("/api/admin", middleware.PlayerAuth(h.authService), middleware.AdminAuth(h.authService))
+	{
+		admin.GET("/players", h.searchPlayers)
+		admin.PUT("/players/:player-id/role", h.setPlayerRole)
+
+		admin.GET("/games", h.searchGames)
+		admin.GET("/games/:game-id", h.getGame)
+		admin.GET("/games/:game-id/events", h.getGameEvents)
+		admin.POST("/games/:game-id/rescore", h.rescoreGame)
+		admin.POST("/games/:game-id/finish", h.finishGame)
+		admin.POST("/games/:game-id/unfinish", h.unfinishGame)
+		admin.POST("/games/:game-id/code", h.resetJoinCode)
+
+		admin.PUT("/matches/:match-id/result", h.forceMatchResult)
+
+		admin.GET("/audit", h.getAuditLog)
+	}
+}
+
+// searchPlayers returns the players matching the "q" query parameter, every player when it's empty
+func (h *AdminHandler) searchPlayers(c *gin.Context) {
+	limit, ok := parseLimit(c)
+	if !ok {
+		return
+	}
+
+	players, err := h.adminService.SearchPlayers(c.Request.Context(), c.Query("q"), limit)
+	if err != nil {
+		log.Errorf("Failed to search players: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search players"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"players": players})
+}
+
+func (h *AdminHandler) setPlayerRole(c *gin.Context) {
+	var req SetPlayerRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err := h.adminService.SetPlayerRole(c.Request.Context(), middleware.Admin(c), c.Param("player-id"), req.Role)
+	if errors.Is(err, services.ErrInvalidPlayerRole) || errors.Is(err, services.ErrAdminSelfDemotion) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, repositories.ErrPlayerNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		log.Errorf("Failed to set player role: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to set player role"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"playerId": c.Param("player-id"), "role": req.Role})
+}
+
+// searchGames returns the games matching the "q" query parameter, every game when it's empty
+func (h *AdminHandler) searchGames(c *gin.Context) {
+	limit, ok := parseLimit(c)
+	if !ok {
+		return
+	}
+
+	games, err := h.adminService.SearchGames(c.Request.Context(), c.Query("q"), limit)
+	if err != nil {
+		log.Errorf("Failed to search games: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search games"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"games": games})
+}
+
+// getGame returns the full state of a game, with the bets of every player even before kickoff.
+// The "at" query parameter is the sequence of an event of the game, to get the game as it was right after it
+func (h *AdminHandler) getGame(c *gin.Context) {
+	at, ok := parseSequence(c, "at")
+	if !ok {
+		return
+	}
+
+	var details *services.AdminGameDetails
+	var err error
+	if at > 0 {
+		details, err = h.adminService.GetGameDetailsAt(c.Request.Context(), c.Param("game-id"), at)
+	} else {
+		details, err = h.adminService.GetGameDetails(c.Request.Context(), c.Param("game-id"))
+	}
+	if err != nil {
+		h.handleGameError(c, err, "Failed to get game")
+		return
+	}
+
+	playerIDToName := make(map[string]string)
+	players := make([]models.SimplePlayer, 0, len(details.Players))
+	for _, p := range details.Players {
+		playerIDToName[p.GetID()] = p.GetName()
+		players = append(players, models.SimplePlayer{ID: p.GetID(), Name: p.GetName()})
+	}
+
+	standings := make([]Standing, 0, len(details.Standings))
+	for _, standing := range details.Standings {
+		standings = append(standings, Standing{
+			PlayerID:    standing.Player.GetID(),
+			PlayerName:  standing.Player.GetName(),
+			Rank:        standing.Rank,
+			Points:      standing.Points,
+			ExactScores: standing.ExactScores,
+			MissedBets:  standing.MissedBets,
+		})
+	}
+
+	incomingMatches := make(map[string]any)
+	for id, matchResult := range details.IncomingMatches {
+		incomingMatches[id] = convertMatchResultToJSON(matchResult, playerIDToName)
+	}
+	pastMatches := make(map[string]any)
+	for id, matchResult := range details.PastMatches {
+		pastMatches[id] = convertMatchResultToJSON(matchResult, playerIDToName)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"game":            details.Summary,
+		"players":         players,
+		"standings":       standings,
+		"incomingMatches": incomingMatches,
+		"pastMatches":     pastMatches,
+	})
+}
+
+// getGameEvents returns a page of the history of a game, oldest first. "after" is the sequence of the last event of the previous page
+func (h *AdminHandler) getGameEvents(c *gin.Context) {
+	after, ok := parseSequence(c, "after")
+	if !ok {
+		return
+	}
+	limit, ok := parseLimit(c)
+	if !ok {
+		return
+	}
+
+	events, err := h.adminService.GetGameEvents(c.Request.Context(), c.Param("game-id"), after, limit)
+	if err != nil {
+		h.handleGameError(c, err, "Failed to get game events")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"events": events})
+}
+
+func (h *AdminHandler) rescoreGame(c *gin.Context) {
+	if err := h.adminService.RescoreGame(c.Request.Context(), middleware.Admin(c), c.Param("game-id")); err != nil {
+		h.handleGameError(c, err, "Failed to rescore game")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"gameId": c.Param("game-id")})
+}
+
+func (h *AdminHandler) finishGame(c *gin.Context) {
+	if err := h.adminService.FinishGame(c.Request.Context(), middleware.Admin(c), c.Param("game-id")); err != nil {
+		h.handleGameError(c, err, "Failed to finish game")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"gameId": c.Param("game-id"), "status": models.GameStatusFinished})
+}
+
+func (h *AdminHandler) unfinishGame(c *gin.Context) {
+	if err := h.adminService.UnfinishGame(c.Request.Context(), middleware.Admin(c), c.Param("game-id")); err != nil {
+		h.handleGameError(c, err, "Failed to unfinish game")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"gameId": c.Param("game-id"), "status": models.GameStatusScheduled})
+}
+
+func (h *AdminHandler) resetJoinCode(c *gin.Context) {
+	code, err := h.adminService.ResetJoinCode(c.Request.Context(), middleware.Admin(c), c.Param("game-id"))
+	if err != nil {
+		h.handleGameError(c, err, "Failed to reset join code")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"gameId": c.Param("game-id"), "code": code})
+}
+
+// forceMatchResult finishes a match with the given score in every running game, scoring it again where it was already scored
+func (h *AdminHandler) forceMatchResult(c *gin.Context) {
+	var req ForceMatchResultRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	gameIDs, err := h.adminService.ForceMatchResult(c.Request.Context(), middleware.Admin(c), c.Param("match-id"), *req.HomeGoals, *req.AwayGoals)
+	if errors.Is(err, services.ErrInvalidMatchResult) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, services.ErrAdminMatchNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		// Some games may have been updated already, so they are returned along with the error
+		log.Errorf("Failed to force match result: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to force match result in every game", "gameIds": gameIDs})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"matchId": c.Param("match-id"), "gameIds": gameIDs})
+}
+
+// getAuditLog returns a page of the audit log, newest first. The "targetType" and "targetId" query parameters
+// restrict it to one game, match or player, and "before" is the id of the last entry of the previous page
+func (h *AdminHandler) getAuditLog(c *gin.Context) {
+	targetType := c.Query("targetType")
+	targetID := c.Query("targetId")
+	if (targetType == "") != (targetID == "") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "targetType and targetId must be given together"})
+		return
+	}
+
+	var beforeID int64
+	if beforeParam := c.Query("before"); beforeParam != "" {
+		parsed, err := strconv.ParseInt(beforeParam, 10, 64)
+		if err != nil || parsed <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "before must be a positive integer"})
+			return
+		}
+		beforeID = parsed
+	}
+	limit, ok := parseLimit(c)
+	if !ok {
+		return
+	}
+
+	entries, err := h.adminService.GetAuditLog(c.Request.Context(), targetType, targetID, beforeID, limit)
+	if err != nil {
+		log.Errorf("Failed to get audit log: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get audit log"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"entries": entries})
+}
+
+// handleGameError responds to the errors of the actions on a game
+func (h *AdminHandler) handleGameError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, services.ErrAdminGameNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Game not found"})
+	case errors.Is(err, services.ErrGameHistoryNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrGameAlreadyFinished), errors.Is(err, services.ErrGameNotFinished):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		log.Errorf("%s: %v", message, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
+
+// parseLimit reads the optional "limit" query parameter, 0 meaning the default limit
+func parseLimit(c *gin.Context) (int, bool) {
+	limitParam := c.Query("limit")
+	if limitParam == "" {
+		return 0, true
+	}
+	limit, err := strconv.Atoi(limitParam)
+	if err != nil || limit <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive integer"})
+		return 0, false
+	}
+	return limit, true
+}
+
+// parseSequence reads an optional query parameter holding the sequence of a game event, 0 when it's missing
+func parseSequence(c *gin.Context, name string) (int64, bool) {
+	param := c.Query(name)
+	if param == "" {
+		return 0, true
+	}
+	sequence, err := strconv.ParseInt(param, 10, 64)
+	if err != nil || sequence <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": name + " must be a positive integer"})
+		return 0, false
+	}
+	return sequence, true
+}
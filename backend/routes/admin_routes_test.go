@@ -0,0 +1,191 @@
+package routes
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"ligain/backend/models"
+	"ligain/backend/repositories"
+	"ligain/backend/rules"
+	"ligain/backend/services"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupAdminRouter creates a game with one bet on an incoming match, and a router signed in as the given player
+func setupAdminRouter(t *testing.T, player *models.PlayerData) (*gin.Engine, string, string) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+
+	playerRepo := repositories.NewInMemoryPlayerRepository()
+	gameRepo := repositories.NewInMemoryGameRepository()
+	gameCodeRepo := repositories.NewInMemoryGameCodeRepository()
+	gamePlayerRepo := repositories.NewInMemoryGamePlayerRepository(playerRepo)
+	betRepo := repositories.NewInMemoryBetRepository()
+
+	bettor := &models.PlayerData{ID: "bettor", Name: "Bettor"}
+	require.NoError(t, playerRepo.UpdatePlayer(ctx, bettor))
+	require.NoError(t, playerRepo.UpdatePlayer(ctx, player))
+
+	match := models.NewSeasonMatchWithKnownOdds("Team1", "Team2", "2024", "Premier League", time.Now().Add(24*time.Hour), 1, 1.5, 2.5, 3.0)
+	game := rules.NewFreshGame("2024", "Premier League", "Admin Game", []models.Player{bettor}, []models.Match{match}, &rules.ScorerOriginal{})
+	require.NoError(t, game.AddPlayerBet(bettor, models.NewBet(match, 2, 1)))
+	gameID, err := gameRepo.CreateGame(game)
+	require.NoError(t, err)
+	require.NoError(t, gamePlayerRepo.AddPlayerToGame(ctx, gameID, bettor.ID))
+
+	registry, err := services.NewGameServiceRegistry(gameRepo, betRepo, gamePlayerRepo, nil)
+	require.NoError(t, err)
+	adminRepo := repositories.NewInMemoryAdminRepository(playerRepo, gameRepo, gameCodeRepo, gamePlayerRepo)
+	adminService := services.NewAdminService(repositories.NewNoopUnitOfWork(), adminRepo, gameRepo, repositories.NewInMemoryGameEventRepository(), gameCodeRepo, betRepo, repositories.NewInMemoryMatchRepository(), registry, nil)
+
+	router := gin.New()
+	NewAdminHandler(adminService, &MockAuthService{player: player}).SetupRoutes(router)
+	return router, gameID, match.Id()
+}
+
+func sendAdminRouteRequest(router *gin.Engine, method string, path string, body any) *httptest.ResponseRecorder {
+	var reader *bytes.Reader
+	if body != nil {
+		payload, _ := json.Marshal(body)
+		reader = bytes.NewReader(payload)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+	req, _ := http.NewRequest(method, path, reader)
+	req.Header.Set("Authorization", "Bearer test-token")
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func newTestAdmin() *models.PlayerData {
+	return &models.PlayerData{ID: "admin", Name: "Admin", Role: models.PlayerRoleAdmin}
+}
+
+func TestAdminRoutes_RequireAdminRole(t *testing.T) {
+	router, gameID, _ := setupAdminRouter(t, &models.PlayerData{ID: "player", Name: "Player", Role: models.PlayerRolePlayer})
+
+	w := sendAdminRouteRequest(router, "GET", "/api/admin/games/"+gameID, nil)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestAdminRoutes_GetGameShowsHiddenBets(t *testing.T) {
+	router, gameID, matchID := setupAdminRouter(t, newTestAdmin())
+
+	w := sendAdminRouteRequest(router, "GET", "/api/admin/games/"+gameID, nil)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var response struct {
+		Game            models.AdminGameSummary `json:"game"`
+		IncomingMatches map[string]struct {
+			Bets map[string]any `json:"bets"`
+		} `json:"incomingMatches"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "Admin Game", response.Game.Name)
+	require.Contains(t, response.IncomingMatches, matchID)
+	assert.Contains(t, response.IncomingMatches[matchID].Bets, "bettor")
+
+	w = sendAdminRouteRequest(router, "GET", "/api/admin/games/unknown", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestAdminRoutes_GameHistory(t *testing.T) {
+	router, gameID, _ := setupAdminRouter(t, newTestAdmin())
+
+	w := sendAdminRouteRequest(router, "GET", "/api/admin/games/"+gameID+"/events", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	var response struct {
+		Events []models.GameEvent `json:"events"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Empty(t, response.Events)
+
+	w = sendAdminRouteRequest(router, "GET", "/api/admin/games/"+gameID+"/events?after=-1", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = sendAdminRouteRequest(router, "GET", "/api/admin/games/"+gameID+"?at=abc", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// The in-memory games don't keep their history
+	w = sendAdminRouteRequest(router, "GET", "/api/admin/games/"+gameID+"?at=1", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestAdminRoutes_ForceMatchResult(t *testing.T) {
+	router, gameID, matchID := setupAdminRouter(t, newTestAdmin())
+	path := "/api/admin/matches/" + matchID + "/result"
+
+	w := sendAdminRouteRequest(router, "PUT", path, gin.H{"homeGoals": 2})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = sendAdminRouteRequest(router, "PUT", path, gin.H{"homeGoals": -2, "awayGoals": 0})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = sendAdminRouteRequest(router, "PUT", "/api/admin/matches/unknown/result", gin.H{"homeGoals": 2, "awayGoals": 1})
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = sendAdminRouteRequest(router, "PUT", path, gin.H{"homeGoals": 0, "awayGoals": 0})
+	require.Equal(t, http.StatusOK, w.Code)
+	var response struct {
+		GameIDs []string `json:"gameIds"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, []string{gameID}, response.GameIDs)
+
+	w = sendAdminRouteRequest(router, "GET", "/api/admin/audit?targetType=match&targetId="+matchID, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	var audit struct {
+		Entries []models.AdminAuditEntry `json:"entries"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &audit))
+	require.Len(t, audit.Entries, 1)
+	assert.Equal(t, models.AdminActionForceMatchResult, audit.Entries[0].Action)
+}
+
+func TestAdminRoutes_FinishGame(t *testing.T) {
+	router, gameID, _ := setupAdminRouter(t, newTestAdmin())
+
+	w := sendAdminRouteRequest(router, "POST", "/api/admin/games/"+gameID+"/unfinish", nil)
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	w = sendAdminRouteRequest(router, "POST", "/api/admin/games/"+gameID+"/finish", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = sendAdminRouteRequest(router, "POST", "/api/admin/games/"+gameID+"/finish", nil)
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestAdminRoutes_SetPlayerRole(t *testing.T) {
+	router, _, _ := setupAdminRouter(t, newTestAdmin())
+
+	w := sendAdminRouteRequest(router, "PUT", "/api/admin/players/bettor/role", gin.H{"role": models.PlayerRoleAdmin})
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = sendAdminRouteRequest(router, "PUT", "/api/admin/players/bettor/role", gin.H{"role": "owner"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = sendAdminRouteRequest(router, "PUT", "/api/admin/players/admin/role", gin.H{"role": models.PlayerRolePlayer})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = sendAdminRouteRequest(router, "PUT", "/api/admin/players/unknown/role", gin.H{"role": models.PlayerRoleAdmin})
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestAdminRoutes_AuditLogErrors(t *testing.T) {
+	router, _, _ := setupAdminRouter(t, newTestAdmin())
+
+	for _, query := range []string{"targetType=game", "before=abc", "before=0", "limit=-1"} {
+		w := sendAdminRouteRequest(router, "GET", "/api/admin/audit?"+query, nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+}
@@ -1,10 +1,13 @@
 package routes
 
 import (
+	"context"
 	"ligain/backend/middleware"
 	"ligain/backend/models"
 	"ligain/backend/services"
+	"math"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"errors"
@@ -28,9 +31,15 @@ func (h *AuthHandler) SetupRoutes(router *gin.Engine) {
 	{
 		auth.POST("/signin", h.SignIn)
 		auth.POST("/signin/guest", h.SignInGuest)
+		auth.POST("/refresh", h.Refresh)
+		auth.POST("/link", middleware.PlayerAuth(h.authService), h.LinkProvider)
+		auth.POST("/guest/secret", middleware.PlayerAuth(h.authService), h.RegenerateGuestSecret)
 		auth.POST("/signout", middleware.PlayerAuth(h.authService), h.SignOut)
-		auth.GET("/me", middleware.PlayerAuthWithRefresh(h.authService), h.GetCurrentPlayer)
+		auth.GET("/me", middleware.PlayerAuth(h.authService), h.GetCurrentPlayer)
 		auth.DELETE("/account", middleware.PlayerAuth(h.authService), h.DeleteAccount)
+		auth.GET("/sessions", middleware.PlayerAuth(h.authService), h.ListSessions)
+		auth.DELETE("/sessions", middleware.PlayerAuth(h.authService), h.RevokeOtherSessions)
+		auth.DELETE("/sessions/:session-id", middleware.PlayerAuth(h.authService), h.RevokeSession)
 	}
 }
 
@@ -78,7 +87,7 @@ func (h *AuthHandler) SignIn(c *gin.Context) {
 	}
 
 	log.Infof("🔐 SignIn - Calling authService.Authenticate")
-	resp, err := h.authService.Authenticate(c.Request.Context(), &req)
+	resp, err := h.authService.Authenticate(requestContextWithDevice(c), &req)
 	if err != nil {
 		var needNameErr *models.NeedDisplayNameError
 		if errors.As(err, &needNameErr) {
@@ -105,6 +114,8 @@ func (h *AuthHandler) SignInGuest(c *gin.Context) {
 
 	var req struct {
 		Name string `json:"name" binding:"required"`
+		// Secret is the guest secret, needed to sign in again as an existing guest
+		Secret string `json:"secret"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
 		log.Errorf("❌ SignInGuest - JSON binding error: %v", err)
@@ -121,9 +132,15 @@ func (h *AuthHandler) SignInGuest(c *gin.Context) {
 	}
 
 	log.Infof("🔐 SignInGuest - Calling authService.AuthenticateGuest")
-	response, err := h.authService.AuthenticateGuest(c.Request.Context(), req.Name)
+	response, err := h.authService.AuthenticateGuest(requestContextWithDevice(c), req.Name, req.Secret)
 	if err != nil {
 		log.Errorf("❌ SignInGuest - Authentication error: %v", err)
+		var tooManyAttemptsErr *models.TooManyAttemptsError
+		if errors.As(err, &tooManyAttemptsErr) {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(tooManyAttemptsErr.RetryAfter.Seconds()))))
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
 		return
 	}
@@ -132,7 +149,86 @@ func (h *AuthHandler) SignInGuest(c *gin.Context) {
 	c.JSON(http.StatusOK, toAuthResponse(response))
 }
 
+// Refresh exchanges a refresh token for a new access token and a new refresh token
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var req struct {
+		RefreshToken string `json:"refreshToken" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Refresh token is required"})
+		return
+	}
+
+	resp, err := h.authService.RefreshToken(requestContextWithDevice(c), req.RefreshToken)
+	if err != nil {
+		log.Errorf("❌ Refresh - Token refresh failed: %v", err)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired refresh token"})
+		return
+	}
+
+	c.JSON(http.StatusOK, toAuthResponse(resp))
+}
+
+// RegenerateGuestSecret gives the authenticated guest a new secret, replacing the previous one
+// Guests created before secrets existed use it to get one from a device they are still signed in on
+func (h *AuthHandler) RegenerateGuestSecret(c *gin.Context) {
+	player, ok := getAuthenticatedPlayer(c)
+	if !ok {
+		return
+	}
+
+	secret, err := h.authService.RegenerateGuestSecret(c.Request.Context(), player.GetID())
+	if err != nil {
+		var alreadyLinkedErr *models.AccountAlreadyLinkedError
+		if errors.As(err, &alreadyLinkedErr) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		log.Errorf("❌ RegenerateGuestSecret - Error regenerating secret of player %s: %v", player.GetID(), err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to regenerate guest secret"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"guestSecret": secret})
+}
+
+// LinkProvider upgrades the authenticated guest to a Google or Apple account
+// The response carries new tokens, since every session of the guest is ended
+func (h *AuthHandler) LinkProvider(c *gin.Context) {
+	player, ok := getAuthenticatedPlayer(c)
+	if !ok {
+		return
+	}
+
+	var req models.AuthRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.Provider == "" || req.Token == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Missing required fields"})
+		return
+	}
+
+	resp, err := h.authService.LinkProvider(requestContextWithDevice(c), player.GetID(), &req)
+	if err != nil {
+		log.Errorf("❌ LinkProvider - Error linking %s to player %s: %v", req.Provider, player.GetID(), err)
+		var unsupportedErr *models.UnsupportedProviderError
+		var alreadyLinkedErr *models.AccountAlreadyLinkedError
+		var identityInUseErr *models.IdentityInUseError
+		switch {
+		case errors.As(err, &unsupportedErr):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.As(err, &alreadyLinkedErr), errors.As(err, &identityInUseErr):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	log.Infof("✅ LinkProvider - Player %s linked to %s", resp.Player.ID, req.Provider)
+	c.JSON(http.StatusOK, toAuthResponse(resp))
+}
+
 // SignOut handles user logout
+// The refresh token given in the body is revoked, with every token rotated from it
 func (h *AuthHandler) SignOut(c *gin.Context) {
 	authHeader := c.GetHeader("Authorization")
 	if authHeader == "" {
@@ -148,6 +244,19 @@ func (h *AuthHandler) SignOut(c *gin.Context) {
 	}
 
 	token := tokenParts[1]
+	var req struct {
+		RefreshToken string `json:"refreshToken"`
+	}
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+			return
+		}
+	}
+	if req.RefreshToken != "" {
+		token = req.RefreshToken
+	}
+
 	err := h.authService.Logout(c.Request.Context(), token)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to logout"})
@@ -172,15 +281,112 @@ func (h *AuthHandler) GetCurrentPlayer(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"player": toPlayerResponse(playerData)})
+	// The access token only carries the id and the name, load the rest of the profile
+	current, err := h.authService.GetPlayer(c.Request.Context(), playerData.ID)
+	if err != nil {
+		var playerNotFoundErr *models.PlayerNotFoundError
+		if errors.As(err, &playerNotFoundErr) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Player not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get player"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"player": toPlayerResponse(current)})
+}
+
+// ListSessions returns the devices the authenticated player is signed in on
+func (h *AuthHandler) ListSessions(c *gin.Context) {
+	player, ok := getAuthenticatedPlayer(c)
+	if !ok {
+		return
+	}
+
+	sessions, err := h.authService.ListSessions(c.Request.Context(), player.GetID(), bearerToken(c))
+	if err != nil {
+		log.Errorf("❌ ListSessions - Error listing sessions: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list sessions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"sessions": sessions})
+}
+
+// RevokeSession signs the authenticated player out of one of their devices
+func (h *AuthHandler) RevokeSession(c *gin.Context) {
+	player, ok := getAuthenticatedPlayer(c)
+	if !ok {
+		return
+	}
+
+	err := h.authService.RevokeSession(c.Request.Context(), player.GetID(), c.Param("session-id"))
+	if err != nil {
+		if errors.Is(err, services.ErrSessionNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		log.Errorf("❌ RevokeSession - Error revoking session: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke session"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Session revoked"})
+}
+
+// RevokeOtherSessions signs the authenticated player out of every device but the current one
+func (h *AuthHandler) RevokeOtherSessions(c *gin.Context) {
+	player, ok := getAuthenticatedPlayer(c)
+	if !ok {
+		return
+	}
+
+	revoked, err := h.authService.RevokeOtherSessions(c.Request.Context(), player.GetID(), bearerToken(c))
+	if err != nil {
+		log.Errorf("❌ RevokeOtherSessions - Error revoking sessions: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke sessions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"revoked": revoked})
+}
+
+// bearerToken returns the token of the Authorization header, already checked by the PlayerAuth middleware
+func bearerToken(c *gin.Context) string {
+	return strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+}
+
+// requestContextWithDevice returns the request context, carrying the device the request comes from
+// The app sends its version in X-App-Version, and optionally its platform and device name
+func requestContextWithDevice(c *gin.Context) context.Context {
+	return services.WithDeviceInfo(c.Request.Context(), models.DeviceInfo{
+		Name:       truncate(c.GetHeader("X-Device-Name"), models.MaxDeviceNameLength),
+		Platform:   truncate(c.GetHeader("X-Platform"), 20),
+		AppVersion: truncate(c.GetHeader("X-App-Version"), 20),
+		IPAddress:  c.ClientIP(),
+	})
+}
+
+// truncate cuts a header value to the length of its column
+func truncate(value string, maxLength int) string {
+	if len([]rune(value)) <= maxLength {
+		return value
+	}
+	return string([]rune(value)[:maxLength])
 }
 
 // toAuthResponse creates a consistent auth response with proper field naming
 func toAuthResponse(resp *models.AuthResponse) gin.H {
-	return gin.H{
-		"player": toPlayerResponse(&resp.Player),
-		"token":  resp.Token,
+	response := gin.H{
+		"player":       toPlayerResponse(&resp.Player),
+		"token":        resp.Token,
+		"expiresAt":    resp.ExpiresAt,
+		"refreshToken": resp.RefreshToken,
+	}
+	if resp.GuestSecret != "" {
+		response["guestSecret"] = resp.GuestSecret
 	}
+	return response
 }
 
 // toPlayerResponse converts a PlayerData to the API response format
@@ -204,7 +410,7 @@ func toPlayerResponse(player *models.PlayerData) map[string]interface{} {
 	return response
 }
 
-// DeleteAccount handles permanent account deletion
+// DeleteAccount handles account deletion. The account can be restored by signing in again during the grace period
 func (h *AuthHandler) DeleteAccount(c *gin.Context) {
 	log.Infof("🗑️ DeleteAccount - Request received from %s", c.ClientIP())
 
@@ -241,5 +447,8 @@ func (h *AuthHandler) DeleteAccount(c *gin.Context) {
 	}
 
 	log.Infof("✅ DeleteAccount - Account deleted successfully for player: %s", playerData.Name)
-	c.JSON(http.StatusOK, gin.H{"message": "Account deleted successfully"})
+	c.JSON(http.StatusOK, gin.H{
+		"message":         "Account deleted successfully",
+		"gracePeriodDays": int(services.AccountDeletionGracePeriod.Hours() / 24),
+	})
 }
@@ -22,8 +22,8 @@ func (m *MockAuthServiceForDelete) Authenticate(ctx context.Context, req *models
 	return args.Get(0).(*models.AuthResponse), args.Error(1)
 }
 
-func (m *MockAuthServiceForDelete) AuthenticateGuest(ctx context.Context, displayName string) (*models.AuthResponse, error) {
-	args := m.Called(ctx, displayName)
+func (m *MockAuthServiceForDelete) AuthenticateGuest(ctx context.Context, displayName string, secret string) (*models.AuthResponse, error) {
+	args := m.Called(ctx, displayName, secret)
 	return args.Get(0).(*models.AuthResponse), args.Error(1)
 }
 
@@ -40,9 +40,34 @@ func (m *MockAuthServiceForDelete) RefreshToken(ctx context.Context, expiredToke
 	return args.Get(0).(*models.AuthResponse), args.Error(1)
 }
 
-func (m *MockAuthServiceForDelete) GetAuthTokenDirectly(ctx context.Context, token string) (*models.AuthToken, error) {
-	args := m.Called(ctx, token)
-	return args.Get(0).(*models.AuthToken), args.Error(1)
+func (m *MockAuthServiceForDelete) GetPlayer(ctx context.Context, playerID string) (*models.PlayerData, error) {
+	args := m.Called(ctx, playerID)
+	return args.Get(0).(*models.PlayerData), args.Error(1)
+}
+
+func (m *MockAuthServiceForDelete) RegenerateGuestSecret(ctx context.Context, playerID string) (string, error) {
+	args := m.Called(ctx, playerID)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockAuthServiceForDelete) LinkProvider(ctx context.Context, playerID string, req *models.AuthRequest) (*models.AuthResponse, error) {
+	args := m.Called(ctx, playerID, req)
+	return args.Get(0).(*models.AuthResponse), args.Error(1)
+}
+
+func (m *MockAuthServiceForDelete) ListSessions(ctx context.Context, playerID string, currentToken string) ([]*models.Session, error) {
+	args := m.Called(ctx, playerID, currentToken)
+	return args.Get(0).([]*models.Session), args.Error(1)
+}
+
+func (m *MockAuthServiceForDelete) RevokeSession(ctx context.Context, playerID string, sessionID string) error {
+	args := m.Called(ctx, playerID, sessionID)
+	return args.Error(0)
+}
+
+func (m *MockAuthServiceForDelete) RevokeOtherSessions(ctx context.Context, playerID string, currentToken string) (int, error) {
+	args := m.Called(ctx, playerID, currentToken)
+	return args.Int(0), args.Error(1)
 }
 
 func (m *MockAuthServiceForDelete) RefreshTokenByPlayerID(ctx context.Context, playerID string) (*models.AuthResponse, error) {
@@ -50,6 +75,11 @@ func (m *MockAuthServiceForDelete) RefreshTokenByPlayerID(ctx context.Context, p
 	return args.Get(0).(*models.AuthResponse), args.Error(1)
 }
 
+func (m *MockAuthServiceForDelete) RenewAccessToken(ctx context.Context, expiredToken string) (*models.AuthResponse, error) {
+	args := m.Called(ctx, expiredToken)
+	return args.Get(0).(*models.AuthResponse), args.Error(1)
+}
+
 func (m *MockAuthServiceForDelete) Logout(ctx context.Context, token string) error {
 	args := m.Called(ctx, token)
 	return args.Error(0)
@@ -109,6 +139,7 @@ func TestAuthHandler_DeleteAccount_Success(t *testing.T) {
 	// Assert
 	assert.Equal(t, http.StatusOK, w.Code)
 	assert.Contains(t, w.Body.String(), "Account deleted successfully")
+	assert.Contains(t, w.Body.String(), `"gracePeriodDays":30`)
 	mockAuthService.AssertExpectations(t)
 }
 
@@ -0,0 +1,107 @@
+package routes
+
+import (
+	"encoding/json"
+	"ligain/backend/models"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func serveGuestSignIn(authService *MockAuthServiceForDelete, body string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	NewAuthHandler(authService).SetupRoutes(router)
+	req, _ := http.NewRequest("POST", "/api/auth/signin/guest", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestSignInGuestHandler_Secret(t *testing.T) {
+	t.Run("NewGuestReceivesSecret", func(t *testing.T) {
+		authService := new(MockAuthServiceForDelete)
+		authService.On("AuthenticateGuest", mock.Anything, "Guesty", "").Return(&models.AuthResponse{
+			Player:      models.PlayerData{ID: "guest-id", Name: "Guesty"},
+			Token:       "guest-token",
+			GuestSecret: "ABCD-EFGH-IJKL-MNOP",
+		}, nil)
+
+		w := serveGuestSignIn(authService, `{"name":"Guesty"}`)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "ABCD-EFGH-IJKL-MNOP", response["guestSecret"])
+	})
+
+	t.Run("SecretIsForwarded", func(t *testing.T) {
+		authService := new(MockAuthServiceForDelete)
+		authService.On("AuthenticateGuest", mock.Anything, "Guesty", "ABCD-EFGH-IJKL-MNOP").Return(&models.AuthResponse{
+			Player: models.PlayerData{ID: "guest-id", Name: "Guesty"},
+			Token:  "guest-token",
+		}, nil)
+
+		w := serveGuestSignIn(authService, `{"name":"Guesty","secret":"ABCD-EFGH-IJKL-MNOP"}`)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		_, hasSecret := response["guestSecret"]
+		assert.False(t, hasSecret)
+	})
+
+	t.Run("InvalidSecret", func(t *testing.T) {
+		authService := new(MockAuthServiceForDelete)
+		authService.On("AuthenticateGuest", mock.Anything, "Guesty", "wrong").
+			Return((*models.AuthResponse)(nil), &models.InvalidGuestSecretError{})
+
+		w := serveGuestSignIn(authService, `{"name":"Guesty","secret":"wrong"}`)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("TooManyAttempts", func(t *testing.T) {
+		authService := new(MockAuthServiceForDelete)
+		authService.On("AuthenticateGuest", mock.Anything, "Guesty", "wrong").
+			Return((*models.AuthResponse)(nil), &models.TooManyAttemptsError{RetryAfter: 90*time.Second + time.Millisecond})
+
+		w := serveGuestSignIn(authService, `{"name":"Guesty","secret":"wrong"}`)
+
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Equal(t, "91", w.Header().Get("Retry-After"))
+	})
+}
+
+func TestAuthHandler_RegenerateGuestSecret(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		authService := new(MockAuthServiceForDelete)
+		router := setupAuthenticatedAuthRouter(authService)
+		authService.On("RegenerateGuestSecret", mock.Anything, "test-player-id").Return("ABCD-EFGH-IJKL-MNOP", nil)
+
+		w := serveAuthenticatedRequest(router, "POST", "/api/auth/guest/secret")
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "ABCD-EFGH-IJKL-MNOP", response["guestSecret"])
+	})
+
+	t.Run("LinkedAccount", func(t *testing.T) {
+		authService := new(MockAuthServiceForDelete)
+		router := setupAuthenticatedAuthRouter(authService)
+		authService.On("RegenerateGuestSecret", mock.Anything, "test-player-id").Return("", &models.AccountAlreadyLinkedError{Provider: "google"})
+
+		w := serveAuthenticatedRequest(router, "POST", "/api/auth/guest/secret")
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+}
@@ -0,0 +1,73 @@
+package routes
+
+import (
+	"encoding/json"
+	"ligain/backend/models"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func serveLinkRequest(authService *MockAuthServiceForDelete, body string) *httptest.ResponseRecorder {
+	router := setupAuthenticatedAuthRouter(authService)
+	req, _ := http.NewRequest("POST", "/api/auth/link", strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer access-token")
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestAuthHandler_LinkProvider(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		authService := new(MockAuthServiceForDelete)
+		provider := "google"
+		authService.On("LinkProvider", mock.Anything, "test-player-id", mock.MatchedBy(func(req *models.AuthRequest) bool {
+			return req.Provider == "google" && req.Token == "google-id-token"
+		})).Return(&models.AuthResponse{
+			Player:       models.PlayerData{ID: "test-player-id", Name: "Test Player", Provider: &provider},
+			Token:        "linked-access-token",
+			RefreshToken: "linked-refresh-token",
+		}, nil)
+
+		w := serveLinkRequest(authService, `{"provider":"google","token":"google-id-token"}`)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "linked-access-token", response["token"])
+		assert.Equal(t, "linked-refresh-token", response["refreshToken"])
+		assert.Equal(t, "google", response["player"].(map[string]interface{})["provider"])
+	})
+
+	t.Run("MissingFields", func(t *testing.T) {
+		w := serveLinkRequest(new(MockAuthServiceForDelete), `{"provider":"google"}`)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("AlreadyLinked", func(t *testing.T) {
+		authService := new(MockAuthServiceForDelete)
+		authService.On("LinkProvider", mock.Anything, "test-player-id", mock.Anything).
+			Return((*models.AuthResponse)(nil), &models.AccountAlreadyLinkedError{Provider: "apple"})
+
+		w := serveLinkRequest(authService, `{"provider":"google","token":"google-id-token"}`)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("IdentityInUse", func(t *testing.T) {
+		authService := new(MockAuthServiceForDelete)
+		authService.On("LinkProvider", mock.Anything, "test-player-id", mock.Anything).
+			Return((*models.AuthResponse)(nil), &models.IdentityInUseError{Provider: "google"})
+
+		w := serveLinkRequest(authService, `{"provider":"google","token":"google-id-token"}`)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+}
@@ -0,0 +1,102 @@
+package routes
+
+import (
+	"encoding/json"
+	"errors"
+	"ligain/backend/models"
+	"ligain/backend/services"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupAuthenticatedAuthRouter(authService *MockAuthServiceForDelete) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	authService.On("ValidateToken", mock.Anything, "access-token").Return(&models.PlayerData{ID: "test-player-id", Name: "Test Player"}, nil)
+	router := gin.New()
+	NewAuthHandler(authService).SetupRoutes(router)
+	return router
+}
+
+func serveAuthenticatedRequest(router *gin.Engine, method, path string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(method, path, nil)
+	req.Header.Set("Authorization", "Bearer access-token")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestAuthHandler_ListSessions(t *testing.T) {
+	authService := new(MockAuthServiceForDelete)
+	router := setupAuthenticatedAuthRouter(authService)
+	authService.On("ListSessions", mock.Anything, "test-player-id", "access-token").Return([]*models.Session{
+		{ID: "session-1", DeviceInfo: models.DeviceInfo{Name: "Pixel", Platform: "android"}, Current: true},
+		{ID: "session-2", DeviceInfo: models.DeviceInfo{Name: "iPad", Platform: "ios"}},
+	}, nil)
+
+	w := serveAuthenticatedRequest(router, "GET", "/api/auth/sessions")
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var response struct {
+		Sessions []map[string]interface{} `json:"sessions"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response.Sessions, 2)
+	assert.Equal(t, "session-1", response.Sessions[0]["id"])
+	assert.Equal(t, "Pixel", response.Sessions[0]["deviceName"])
+	assert.Equal(t, true, response.Sessions[0]["current"])
+	assert.Equal(t, false, response.Sessions[1]["current"])
+}
+
+func TestAuthHandler_RevokeSession(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		authService := new(MockAuthServiceForDelete)
+		router := setupAuthenticatedAuthRouter(authService)
+		authService.On("RevokeSession", mock.Anything, "test-player-id", "session-2").Return(nil)
+
+		w := serveAuthenticatedRequest(router, "DELETE", "/api/auth/sessions/session-2")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		authService.AssertExpectations(t)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		authService := new(MockAuthServiceForDelete)
+		router := setupAuthenticatedAuthRouter(authService)
+		authService.On("RevokeSession", mock.Anything, "test-player-id", "unknown").Return(services.ErrSessionNotFound)
+
+		w := serveAuthenticatedRequest(router, "DELETE", "/api/auth/sessions/unknown")
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestAuthHandler_RevokeOtherSessions(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		authService := new(MockAuthServiceForDelete)
+		router := setupAuthenticatedAuthRouter(authService)
+		authService.On("RevokeOtherSessions", mock.Anything, "test-player-id", "access-token").Return(2, nil)
+
+		w := serveAuthenticatedRequest(router, "DELETE", "/api/auth/sessions")
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, float64(2), response["revoked"])
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		authService := new(MockAuthServiceForDelete)
+		router := setupAuthenticatedAuthRouter(authService)
+		authService.On("RevokeOtherSessions", mock.Anything, "test-player-id", "access-token").Return(0, errors.New("database down"))
+
+		w := serveAuthenticatedRequest(router, "DELETE", "/api/auth/sessions")
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
@@ -7,9 +7,11 @@ import (
 	"fmt"
 	"ligain/backend/middleware"
 	"ligain/backend/models"
+	"ligain/backend/ratelimit"
+	"ligain/backend/repositories"
+	"ligain/backend/services"
 	"net/http"
 	"net/http/httptest"
-	"os"
 	"strings"
 	"testing"
 	"time"
@@ -142,6 +144,10 @@ func (m *MockPlayerRepository) UpdateAvatarSignedURL(ctx context.Context, player
 	return nil
 }
 
+func (m *MockPlayerRepository) GetPlayersWithAvatarURLExpiringBefore(ctx context.Context, before time.Time) ([]*models.PlayerData, error) {
+	return nil, nil
+}
+
 func (m *MockPlayerRepository) ClearAvatar(ctx context.Context, playerID string) error {
 	return nil
 }
@@ -213,6 +219,10 @@ func (m *MockPlayerRepositoryWithErrors) UpdateAvatarSignedURL(ctx context.Conte
 	return fmt.Errorf("mock error")
 }
 
+func (m *MockPlayerRepositoryWithErrors) GetPlayersWithAvatarURLExpiringBefore(ctx context.Context, before time.Time) ([]*models.PlayerData, error) {
+	return nil, fmt.Errorf("mock error")
+}
+
 func (m *MockPlayerRepositoryWithErrors) ClearAvatar(ctx context.Context, playerID string) error {
 	return fmt.Errorf("mock error")
 }
@@ -286,7 +296,7 @@ func (m *MockAuthService) GetOrCreatePlayer(ctx context.Context, verifiedUser ma
 	return m.player, nil
 }
 
-func (m *MockAuthService) AuthenticateGuest(ctx context.Context, displayName string) (*models.AuthResponse, error) {
+func (m *MockAuthService) AuthenticateGuest(ctx context.Context, displayName string, secret string) (*models.AuthResponse, error) {
 	if m.shouldFail {
 		return nil, errors.New("mock guest authentication failed")
 	}
@@ -316,23 +326,61 @@ func (m *MockAuthService) RefreshToken(ctx context.Context, expiredToken string)
 	}
 
 	return &models.AuthResponse{
-		Player: *m.player,
-		Token:  "mock-refreshed-token",
+		Player:       *m.player,
+		Token:        "mock-refreshed-token",
+		ExpiresAt:    frozenTime.Add(15 * time.Minute),
+		RefreshToken: "mock-rotated-refresh-token",
 	}, nil
 }
 
-func (m *MockAuthService) GetAuthTokenDirectly(ctx context.Context, token string) (*models.AuthToken, error) {
+func (m *MockAuthService) GetPlayer(ctx context.Context, playerID string) (*models.PlayerData, error) {
 	if m.shouldFail {
-		return nil, errors.New("mock get token failed")
+		return nil, errors.New("mock get player failed")
 	}
-	if token == "" {
-		return nil, nil
+	if m.player == nil {
+		return nil, &models.PlayerNotFoundError{Reason: "player not found"}
 	}
-	return &models.AuthToken{
-		PlayerID:  "test-player-id",
-		Token:     token,
-		ExpiresAt: frozenTime.Add(1 * time.Hour),
-	}, nil
+	return m.player, nil
+}
+
+func (m *MockAuthService) RegenerateGuestSecret(ctx context.Context, playerID string) (string, error) {
+	if m.shouldFail {
+		return "", errors.New("mock regenerate guest secret failed")
+	}
+	return "ABCD-EFGH-IJKL-MNOP", nil
+}
+
+func (m *MockAuthService) LinkProvider(ctx context.Context, playerID string, req *models.AuthRequest) (*models.AuthResponse, error) {
+	if m.shouldFail {
+		return nil, errors.New("mock link provider failed")
+	}
+	if m.player == nil {
+		return nil, &models.PlayerNotFoundError{Reason: "player not found"}
+	}
+	linked := *m.player
+	linked.Provider = &req.Provider
+	return &models.AuthResponse{Player: linked, Token: "mock-linked-token", RefreshToken: "mock-linked-refresh-token"}, nil
+}
+
+func (m *MockAuthService) ListSessions(ctx context.Context, playerID string, currentToken string) ([]*models.Session, error) {
+	if m.shouldFail {
+		return nil, errors.New("mock list sessions failed")
+	}
+	return []*models.Session{{ID: "current-session", Current: true}}, nil
+}
+
+func (m *MockAuthService) RevokeSession(ctx context.Context, playerID string, sessionID string) error {
+	if m.shouldFail {
+		return errors.New("mock revoke session failed")
+	}
+	return nil
+}
+
+func (m *MockAuthService) RevokeOtherSessions(ctx context.Context, playerID string, currentToken string) (int, error) {
+	if m.shouldFail {
+		return 0, errors.New("mock revoke sessions failed")
+	}
+	return 0, nil
 }
 
 func (m *MockAuthService) RefreshTokenByPlayerID(ctx context.Context, playerID string) (*models.AuthResponse, error) {
@@ -355,6 +403,10 @@ func (m *MockAuthService) RefreshTokenByPlayerID(ctx context.Context, playerID s
 	}, nil
 }
 
+func (m *MockAuthService) RenewAccessToken(ctx context.Context, expiredToken string) (*models.AuthResponse, error) {
+	return nil, &models.TokenExpiredError{Reason: "mock renewal"}
+}
+
 func (m *MockAuthService) UpdateDisplayName(ctx context.Context, playerID string, displayName string) (*models.PlayerData, error) {
 	if m.shouldFail {
 		return nil, errors.New("mock update display name failed")
@@ -463,17 +515,11 @@ func TestSignInHandler(t *testing.T) {
 
 			// Add API key middleware if requested
 			if tt.includeAPIKey {
-				// Set environment variable for API key
-				originalAPIKey := os.Getenv("API_KEY")
-				defer os.Setenv("API_KEY", originalAPIKey)
-
-				if tt.apiKey != "" {
-					os.Setenv("API_KEY", "test_api_key")
-				} else {
-					os.Unsetenv("API_KEY")
-				}
+				keyService := services.NewAPIKeyService(repositories.NewInMemoryAPIKeyRepository())
+				_, err := keyService.ImportKey(context.Background(), "test", "test_api_key", []string{models.APIKeyScopeRead, models.APIKeyScopeWrite}, 0)
+				assert.NoError(t, err)
 
-				router.Use(middleware.APIKeyAuth())
+				router.Use(middleware.APIKeyAuth(keyService, ratelimit.NewInMemoryStore()))
 			}
 
 			router.POST("/signin", authHandler.SignIn)
@@ -573,6 +619,59 @@ func TestSignOutHandler(t *testing.T) {
 	}
 }
 
+func TestRefreshHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+		authService    *MockAuthService
+	}{
+		{
+			name:           "successful refresh",
+			body:           `{"refreshToken":"mock-refresh-token"}`,
+			expectedStatus: http.StatusOK,
+			authService:    &MockAuthService{player: &models.PlayerData{ID: "test-player-id", Name: "Test User"}},
+		},
+		{
+			name:           "missing refresh token",
+			body:           `{}`,
+			expectedStatus: http.StatusBadRequest,
+			authService:    &MockAuthService{},
+		},
+		{
+			name:           "rejected refresh token",
+			body:           `{"refreshToken":"reused-refresh-token"}`,
+			expectedStatus: http.StatusUnauthorized,
+			authService:    &MockAuthService{shouldFail: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			NewAuthHandler(tt.authService).SetupRoutes(router)
+
+			req, err := http.NewRequest("POST", "/api/auth/refresh", strings.NewReader(tt.body))
+			assert.NoError(t, err)
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var response map[string]interface{}
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, "mock-refreshed-token", response["token"])
+				assert.Equal(t, "mock-rotated-refresh-token", response["refreshToken"])
+				assert.NotEmpty(t, response["expiresAt"])
+			}
+		})
+	}
+}
+
 // stringPtr is a helper function to create a pointer to a string
 func stringPtr(s string) *string {
 	return &s
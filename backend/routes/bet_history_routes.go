@@ -0,0 +1,75 @@
+package routes
+
+import (
+	"errors"
+	"ligain/backend/middleware"
+	"ligain/backend/models"
+	"ligain/backend/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+// BetHistoryHandler handles the history of the bets of a game, used to settle disputes about when a bet was placed
+type BetHistoryHandler struct {
+	betHistoryService services.BetHistoryService
+	authService       services.AuthServiceInterface
+}
+
+// NewBetHistoryHandler creates a new BetHistoryHandler
+func NewBetHistoryHandler(betHistoryService services.BetHistoryService, authService services.AuthServiceInterface) *BetHistoryHandler {
+	return &BetHistoryHandler{
+		betHistoryService: betHistoryService,
+		authService:       authService,
+	}
+}
+
+// SetupRoutes registers the bet history routes on the router
+func (h *BetHistoryHandler) SetupRoutes(router *gin.Engine) {
+	router.GET("/api/game/:game-id/players/:player-id/bets/history", middleware.PlayerAuth(h.authService), h.getPlayerBetHistory)
+	router.GET("/api/game/:game-id/matches/:match-id/bets/history", middleware.PlayerAuth(h.authService), h.getMatchBetHistory)
+}
+
+// BetChangeResponse is a change of a bet, with whether it was saved before the match kicked off
+type BetChangeResponse struct {
+	*models.BetChange
+	PlacedBeforeKickoff bool `json:"placedBeforeKickoff"`
+}
+
+// getPlayerBetHistory returns the changes of the bets of a player, on the "matchId" query parameter only when it's set
+func (h *BetHistoryHandler) getPlayerBetHistory(c *gin.Context) {
+	h.getBetHistory(c, c.Param("player-id"), c.Query("matchId"))
+}
+
+// getMatchBetHistory returns the changes of the bets of every player on a match
+func (h *BetHistoryHandler) getMatchBetHistory(c *gin.Context) {
+	h.getBetHistory(c, "", c.Param("match-id"))
+}
+
+func (h *BetHistoryHandler) getBetHistory(c *gin.Context, playerID string, matchID string) {
+	player, ok := getAuthenticatedPlayer(c)
+	if !ok {
+		return
+	}
+
+	changes, err := h.betHistoryService.GetBetHistory(c.Request.Context(), c.Param("game-id"), player, playerID, matchID)
+	switch {
+	case errors.Is(err, services.ErrPlayerNotInGame):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, services.ErrMatchNotInGame):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		log.Errorf("Failed to get bet history: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get bet history"})
+		return
+	}
+
+	history := make([]BetChangeResponse, 0, len(changes))
+	for _, change := range changes {
+		history = append(history, BetChangeResponse{BetChange: change, PlacedBeforeKickoff: change.PlacedBeforeKickoff()})
+	}
+	c.JSON(http.StatusOK, gin.H{"history": history})
+}
@@ -0,0 +1,92 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"ligain/backend/models"
+	"ligain/backend/repositories"
+	"ligain/backend/rules"
+	"ligain/backend/services"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupBetHistoryRouter creates a game where the first player bet on an incoming match, and a router signed in as the given player
+func setupBetHistoryRouter(t *testing.T, player *models.PlayerData) (*gin.Engine, string, string) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+
+	match := models.NewSeasonMatch("Team1", "Team2", "2024", "Premier League", time.Now().Add(24*time.Hour), 1)
+	players := []models.Player{
+		&models.PlayerData{ID: "player-1", Name: "First"},
+		&models.PlayerData{ID: "player-2", Name: "Second"},
+	}
+	gameRepo := repositories.NewInMemoryGameRepository()
+	gameID, err := gameRepo.CreateGame(rules.NewFreshGame("2024", "Premier League", "Test Game", players, []models.Match{match}, &rules.ScorerOriginal{}))
+	require.NoError(t, err)
+
+	gamePlayerRepo := repositories.NewInMemoryGamePlayerRepository(repositories.NewInMemoryPlayerRepository())
+	for _, p := range players {
+		require.NoError(t, gamePlayerRepo.AddPlayerToGame(ctx, gameID, p.GetID()))
+	}
+
+	betHistoryService := services.NewBetHistoryService(repositories.NewInMemoryBetHistoryRepository(), gameRepo, gamePlayerRepo)
+	device := services.WithDeviceInfo(ctx, models.DeviceInfo{Platform: "ios", IPAddress: "192.0.2.1"})
+	require.NoError(t, betHistoryService.RecordBet(device, gameID, players[0], models.NewBet(match, 2, 1), time.Now()))
+
+	router := gin.New()
+	NewBetHistoryHandler(betHistoryService, &MockAuthService{player: player}).SetupRoutes(router)
+	return router, gameID, match.Id()
+}
+
+func TestBetHistoryRoutes_GetPlayerBetHistory(t *testing.T) {
+	router, gameID, matchID := setupBetHistoryRouter(t, &models.PlayerData{ID: "player-1", Name: "First"})
+
+	w := performCommentRequest(router, "GET", "/api/game/"+gameID+"/players/player-1/bets/history?matchId="+url.QueryEscape(matchID), nil)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		History []struct {
+			PlayerID            string            `json:"playerId"`
+			PredictedHomeGoals  int               `json:"predictedHomeGoals"`
+			PlacedBeforeKickoff bool              `json:"placedBeforeKickoff"`
+			Device              models.DeviceInfo `json:"device"`
+		} `json:"history"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response.History, 1)
+	assert.Equal(t, 2, response.History[0].PredictedHomeGoals)
+	assert.True(t, response.History[0].PlacedBeforeKickoff)
+	assert.Equal(t, "192.0.2.1", response.History[0].Device.IPAddress)
+}
+
+func TestBetHistoryRoutes_GetMatchBetHistory(t *testing.T) {
+	router, gameID, matchID := setupBetHistoryRouter(t, &models.PlayerData{ID: "player-2", Name: "Second"})
+	basePath := "/api/game/" + gameID + "/matches/"
+
+	// The bet of the other player is hidden until kickoff
+	w := performCommentRequest(router, "GET", basePath+url.PathEscape(matchID)+"/bets/history", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	var response struct {
+		History []any `json:"history"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Empty(t, response.History)
+
+	w = performCommentRequest(router, "GET", basePath+"unknown/bets/history", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestBetHistoryRoutes_RequireGameMembership(t *testing.T) {
+	router, gameID, matchID := setupBetHistoryRouter(t, &models.PlayerData{ID: "outsider", Name: "Outsider"})
+
+	w := performCommentRequest(router, "GET", "/api/game/"+gameID+"/matches/"+url.PathEscape(matchID)+"/bets/history", nil)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
@@ -0,0 +1,164 @@
+package routes
+
+import (
+	"errors"
+	"ligain/backend/middleware"
+	"ligain/backend/models"
+	"ligain/backend/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+// CommentHandler handles the comments and reactions posted on the matches of a game
+type CommentHandler struct {
+	commentService services.CommentService
+	authService    services.AuthServiceInterface
+}
+
+// NewCommentHandler creates a new CommentHandler
+func NewCommentHandler(commentService services.CommentService, authService services.AuthServiceInterface) *CommentHandler {
+	return &CommentHandler{
+		commentService: commentService,
+		authService:    authService,
+	}
+}
+
+// SetupRoutes registers comment routes on the router
+func (h *CommentHandler) SetupRoutes(router *gin.Engine) {
+	router.GET("/api/game/:game-id/matches/:match-id/comments", middleware.PlayerAuth(h.authService), h.getComments)
+	router.POST("/api/game/:game-id/matches/:match-id/comments", middleware.PlayerAuth(h.authService), h.addComment)
+	router.DELETE("/api/game/:game-id/matches/:match-id/comments/:comment-id", middleware.PlayerAuth(h.authService), h.deleteComment)
+	router.PUT("/api/game/:game-id/matches/:match-id/reactions/:emoji", middleware.PlayerAuth(h.authService), h.addReaction)
+	router.DELETE("/api/game/:game-id/matches/:match-id/reactions/:emoji", middleware.PlayerAuth(h.authService), h.removeReaction)
+}
+
+// AddCommentRequest represents the request body to post a comment
+type AddCommentRequest struct {
+	Content     string `json:"content" binding:"required"`
+	MentionsBet bool   `json:"mentionsBet"`
+}
+
+// getComments returns the comments and the reactions of a match
+func (h *CommentHandler) getComments(c *gin.Context) {
+	player, ok := getAuthenticatedPlayer(c)
+	if !ok {
+		return
+	}
+	gameID := c.Param("game-id")
+	matchID := c.Param("match-id")
+
+	comments, err := h.commentService.GetComments(c.Request.Context(), gameID, matchID, player)
+	if err != nil {
+		h.handleError(c, err, "Failed to get comments")
+		return
+	}
+	reactions, err := h.commentService.GetReactions(c.Request.Context(), gameID, matchID, player)
+	if err != nil {
+		h.handleError(c, err, "Failed to get reactions")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"comments":  comments,
+		"reactions": reactions,
+	})
+}
+
+// addComment posts a comment on a match
+func (h *CommentHandler) addComment(c *gin.Context) {
+	player, ok := getAuthenticatedPlayer(c)
+	if !ok {
+		return
+	}
+
+	var request AddCommentRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	comment, err := h.commentService.AddComment(c.Request.Context(), c.Param("game-id"), c.Param("match-id"), player, request.Content, request.MentionsBet)
+	if err != nil {
+		h.handleError(c, err, "Failed to add comment")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"comment": comment})
+}
+
+// deleteComment removes a comment of the authenticated player
+func (h *CommentHandler) deleteComment(c *gin.Context) {
+	player, ok := getAuthenticatedPlayer(c)
+	if !ok {
+		return
+	}
+
+	err := h.commentService.DeleteComment(c.Request.Context(), c.Param("game-id"), c.Param("match-id"), c.Param("comment-id"), player)
+	if err != nil {
+		h.handleError(c, err, "Failed to delete comment")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// addReaction reacts to a match with an emoji
+func (h *CommentHandler) addReaction(c *gin.Context) {
+	player, ok := getAuthenticatedPlayer(c)
+	if !ok {
+		return
+	}
+
+	err := h.commentService.AddReaction(c.Request.Context(), c.Param("game-id"), c.Param("match-id"), player, c.Param("emoji"))
+	if err != nil {
+		h.handleError(c, err, "Failed to add reaction")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// removeReaction removes a reaction of the authenticated player
+func (h *CommentHandler) removeReaction(c *gin.Context) {
+	player, ok := getAuthenticatedPlayer(c)
+	if !ok {
+		return
+	}
+
+	err := h.commentService.RemoveReaction(c.Request.Context(), c.Param("game-id"), c.Param("match-id"), player, c.Param("emoji"))
+	if err != nil {
+		h.handleError(c, err, "Failed to remove reaction")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// handleError maps the comment service errors to HTTP responses
+func (h *CommentHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, services.ErrPlayerNotInGame):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrNotCommentAuthor):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrMatchNotInGame), errors.Is(err, services.ErrCommentNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrInvalidComment), errors.Is(err, services.ErrReactionNotAllowed):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		log.Errorf("%s: %v", message, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
+
+// getAuthenticatedPlayer returns the player set by the auth middleware
+func getAuthenticatedPlayer(c *gin.Context) (models.Player, bool) {
+	player, exists := c.Get("player")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Player not found in context"})
+		return nil, false
+	}
+	return player.(models.Player), true
+}
@@ -0,0 +1,114 @@
+package routes
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"ligain/backend/models"
+	"ligain/backend/repositories"
+	"ligain/backend/rules"
+	"ligain/backend/services"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupCommentRouter(t *testing.T, player *models.PlayerData) (*gin.Engine, string, string) {
+	gin.SetMode(gin.TestMode)
+
+	match := models.NewSeasonMatch("Team1", "Team2", "2024", "Premier League", time.Now().Add(24*time.Hour), 1)
+	players := []models.Player{
+		&models.PlayerData{ID: "player-1", Name: "First"},
+		&models.PlayerData{ID: "player-2", Name: "Second"},
+	}
+	gameRepo := repositories.NewInMemoryGameRepository()
+	gameID, err := gameRepo.CreateGame(rules.NewFreshGame("2024", "Premier League", "Test Game", players, []models.Match{match}, &rules.ScorerOriginal{}))
+	require.NoError(t, err)
+
+	gamePlayerRepo := repositories.NewInMemoryGamePlayerRepository(repositories.NewInMemoryPlayerRepository())
+	for _, p := range players {
+		require.NoError(t, gamePlayerRepo.AddPlayerToGame(context.Background(), gameID, p.GetID()))
+	}
+
+	commentService := services.NewCommentService(repositories.NewInMemoryCommentRepository(), gameRepo, gamePlayerRepo)
+	handler := NewCommentHandler(commentService, &MockAuthService{player: player})
+	router := gin.New()
+	handler.SetupRoutes(router)
+	return router, gameID, match.Id()
+}
+
+func performCommentRequest(router *gin.Engine, method, path string, body interface{}) *httptest.ResponseRecorder {
+	var reader *bytes.Reader
+	if body != nil {
+		payload, _ := json.Marshal(body)
+		reader = bytes.NewReader(payload)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+	req, _ := http.NewRequest(method, path, reader)
+	req.Header.Set("Authorization", "Bearer test-token")
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestCommentRoutes_PostAndListComments(t *testing.T) {
+	router, gameID, matchID := setupCommentRouter(t, &models.PlayerData{ID: "player-1", Name: "First"})
+	basePath := "/api/game/" + gameID + "/matches/" + url.PathEscape(matchID)
+
+	w := performCommentRequest(router, "POST", basePath+"/comments", AddCommentRequest{Content: "I say 3-0"})
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	var created struct {
+		Comment models.MatchComment `json:"comment"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	assert.NotEmpty(t, created.Comment.ID)
+	assert.True(t, created.Comment.MentionsBet)
+
+	w = performCommentRequest(router, "PUT", basePath+"/reactions/"+url.PathEscape("🔥"), nil)
+	require.Equal(t, http.StatusNoContent, w.Code)
+
+	w = performCommentRequest(router, "GET", basePath+"/comments", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Comments  []models.MatchComment    `json:"comments"`
+		Reactions []models.ReactionSummary `json:"reactions"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response.Comments, 1)
+	assert.Equal(t, "I say 3-0", response.Comments[0].Content)
+	assert.Equal(t, []models.ReactionSummary{{Emoji: "🔥", Count: 1, ReactedByMe: true}}, response.Reactions)
+
+	w = performCommentRequest(router, "DELETE", basePath+"/comments/"+created.Comment.ID, nil)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+}
+
+func TestCommentRoutes_Errors(t *testing.T) {
+	router, gameID, matchID := setupCommentRouter(t, &models.PlayerData{ID: "player-1", Name: "First"})
+	basePath := "/api/game/" + gameID + "/matches/" + url.PathEscape(matchID)
+
+	w := performCommentRequest(router, "POST", basePath+"/comments", map[string]string{})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = performCommentRequest(router, "PUT", basePath+"/reactions/pizza", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = performCommentRequest(router, "GET", "/api/game/"+gameID+"/matches/unknown/comments", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = performCommentRequest(router, "DELETE", basePath+"/comments/unknown", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	outsiderRouter, outsiderGameID, _ := setupCommentRouter(t, &models.PlayerData{ID: "outsider", Name: "Outsider"})
+	w = performCommentRequest(outsiderRouter, "GET", "/api/game/"+outsiderGameID+"/matches/"+url.PathEscape(matchID)+"/comments", nil)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
@@ -0,0 +1,70 @@
+package routes
+
+import (
+	"errors"
+	"ligain/backend/middleware"
+	"ligain/backend/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+// DataExportHandler handles the requests of the players for a copy of their personal data
+type DataExportHandler struct {
+	exportService services.DataExportService
+	authService   services.AuthServiceInterface
+}
+
+// NewDataExportHandler creates a new DataExportHandler
+func NewDataExportHandler(exportService services.DataExportService, authService services.AuthServiceInterface) *DataExportHandler {
+	return &DataExportHandler{
+		exportService: exportService,
+		authService:   authService,
+	}
+}
+
+// SetupRoutes registers the data export routes on the router
+func (h *DataExportHandler) SetupRoutes(router *gin.Engine) {
+	router.POST("/api/players/me/export", middleware.PlayerAuth(h.authService), h.requestExport)
+	router.GET("/api/players/me/export/:export-id", middleware.PlayerAuth(h.authService), h.getExport)
+}
+
+// requestExport starts building the archive of the authenticated player's data.
+// The app polls getExport until the archive is ready
+func (h *DataExportHandler) requestExport(c *gin.Context) {
+	player, ok := getAuthenticatedPlayer(c)
+	if !ok {
+		return
+	}
+
+	export, err := h.exportService.RequestExport(c.Request.Context(), player.GetID())
+	if err != nil {
+		log.Errorf("Failed to request data export for player %s: %v", player.GetID(), err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to request data export"})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"export": export})
+}
+
+// getExport returns the status of an export of the authenticated player, with a download URL once it's ready
+func (h *DataExportHandler) getExport(c *gin.Context) {
+	player, ok := getAuthenticatedPlayer(c)
+	if !ok {
+		return
+	}
+
+	export, err := h.exportService.GetExport(c.Request.Context(), player.GetID(), c.Param("export-id"))
+	if err != nil {
+		if errors.Is(err, services.ErrDataExportNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Data export not found"})
+			return
+		}
+		log.Errorf("Failed to get data export for player %s: %v", player.GetID(), err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get data export"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"export": export})
+}
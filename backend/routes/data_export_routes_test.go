@@ -0,0 +1,131 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"ligain/backend/models"
+	"ligain/backend/services"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockDataExportService struct {
+	mock.Mock
+}
+
+func (m *MockDataExportService) RequestExport(ctx context.Context, playerID string) (*models.DataExport, error) {
+	args := m.Called(ctx, playerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.DataExport), args.Error(1)
+}
+
+func (m *MockDataExportService) GetExport(ctx context.Context, playerID string, exportID string) (*models.DataExport, error) {
+	args := m.Called(ctx, playerID, exportID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.DataExport), args.Error(1)
+}
+
+func (m *MockDataExportService) CleanupExpiredExports(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+func setupDataExportRouter(exportService *MockDataExportService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	authService := &MockAuthService{player: &models.PlayerData{ID: "exporter", Name: "Exporter"}}
+	router := gin.New()
+	NewDataExportHandler(exportService, authService).SetupRoutes(router)
+	return router
+}
+
+func sendDataExportRequest(router *gin.Engine, method string, path string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(method, path, nil)
+	req.Header.Set("Authorization", "Bearer test-token")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestRequestDataExportHandler(t *testing.T) {
+	t.Run("Accepted", func(t *testing.T) {
+		exportService := &MockDataExportService{}
+		exportService.On("RequestExport", mock.Anything, "exporter").Return(&models.DataExport{
+			ID:        "export1",
+			PlayerID:  "exporter",
+			Status:    models.DataExportPending,
+			CreatedAt: time.Now(),
+		}, nil)
+
+		w := sendDataExportRequest(setupDataExportRouter(exportService), "POST", "/api/players/me/export")
+		assert.Equal(t, http.StatusAccepted, w.Code)
+
+		var response struct {
+			Export map[string]interface{} `json:"export"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "export1", response.Export["id"])
+		assert.Equal(t, "pending", response.Export["status"])
+		assert.NotContains(t, response.Export, "downloadUrl")
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		exportService := &MockDataExportService{}
+		exportService.On("RequestExport", mock.Anything, "exporter").Return(nil, errors.New("database down"))
+
+		w := sendDataExportRequest(setupDataExportRouter(exportService), "POST", "/api/players/me/export")
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
+
+func TestGetDataExportHandler(t *testing.T) {
+	t.Run("Ready", func(t *testing.T) {
+		expiresAt := time.Now().Add(services.DataExportRetention)
+		exportService := &MockDataExportService{}
+		exportService.On("GetExport", mock.Anything, "exporter", "export1").Return(&models.DataExport{
+			ID:          "export1",
+			Status:      models.DataExportReady,
+			ObjectKey:   "exports/exporter/export1.zip",
+			ExpiresAt:   &expiresAt,
+			DownloadURL: "https://storage.example.com/exports/exporter/export1.zip?signature=abc",
+		}, nil)
+
+		w := sendDataExportRequest(setupDataExportRouter(exportService), "GET", "/api/players/me/export/export1")
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response struct {
+			Export map[string]interface{} `json:"export"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "ready", response.Export["status"])
+		assert.Equal(t, "https://storage.example.com/exports/exporter/export1.zip?signature=abc", response.Export["downloadUrl"])
+		assert.NotContains(t, response.Export, "objectKey")
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		exportService := &MockDataExportService{}
+		exportService.On("GetExport", mock.Anything, "exporter", "unknown").Return(nil, services.ErrDataExportNotFound)
+
+		w := sendDataExportRequest(setupDataExportRouter(exportService), "GET", "/api/players/me/export/unknown")
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("Unauthenticated", func(t *testing.T) {
+		router := setupDataExportRouter(&MockDataExportService{})
+		req, _ := http.NewRequest("GET", "/api/players/me/export/export1", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
@@ -0,0 +1,97 @@
+package routes
+
+import (
+	"errors"
+	"ligain/backend/models"
+	"ligain/backend/services"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+// EmailSignInHandler handles the passwordless sign-in with a code or a link sent by email,
+// for the players who can't use Google or Apple sign-in
+type EmailSignInHandler struct {
+	emailSignInService services.EmailSignInService
+}
+
+// NewEmailSignInHandler creates a new EmailSignInHandler
+func NewEmailSignInHandler(emailSignInService services.EmailSignInService) *EmailSignInHandler {
+	return &EmailSignInHandler{
+		emailSignInService: emailSignInService,
+	}
+}
+
+// SetupRoutes registers the email sign-in routes on the router
+func (h *EmailSignInHandler) SetupRoutes(router *gin.Engine) {
+	router.POST("/api/auth/email/request", h.requestSignIn)
+	router.POST("/api/auth/email/verify", h.verifySignIn)
+}
+
+// requestSignIn emails a sign-in code and link. It answers the same whether the email belongs to a player or not
+func (h *EmailSignInHandler) requestSignIn(c *gin.Context) {
+	var req struct {
+		Email string `json:"email" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	if err := h.emailSignInService.RequestSignIn(c.Request.Context(), req.Email); err != nil {
+		if errors.Is(err, services.ErrInvalidEmail) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		var tooManyAttemptsErr *models.TooManyAttemptsError
+		if errors.As(err, &tooManyAttemptsErr) {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(tooManyAttemptsErr.RetryAfter.Seconds()))))
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+			return
+		}
+		log.Errorf("❌ Email sign-in request failed: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to send the sign-in email"})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"expiresInSeconds": int(services.EmailSignInCodeTTL.Seconds())})
+}
+
+// verifySignIn signs in with the emailed code or link token. Like the other sign-ins, a new player is asked for a display name first
+func (h *EmailSignInHandler) verifySignIn(c *gin.Context) {
+	var req models.EmailSignInVerifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	if req.Token == "" && (req.Email == "" || req.Code == "") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Either a token, or an email and a code are required"})
+		return
+	}
+
+	resp, err := h.emailSignInService.VerifySignIn(requestContextWithDevice(c), &req)
+	if err != nil {
+		var needNameErr *models.NeedDisplayNameError
+		if errors.As(err, &needNameErr) {
+			c.JSON(http.StatusOK, gin.H{
+				"status":        "need_display_name",
+				"suggestedName": needNameErr.SuggestedName,
+				"error":         needNameErr.Reason,
+			})
+			return
+		}
+		if errors.Is(err, services.ErrInvalidEmail) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		log.Errorf("❌ Email sign-in verification failed: %v", err)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	log.Infof("✅ Email sign-in successful for user: %s", resp.Player.Name)
+	c.JSON(http.StatusOK, toAuthResponse(resp))
+}
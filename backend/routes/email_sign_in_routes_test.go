@@ -0,0 +1,126 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"ligain/backend/models"
+	"ligain/backend/services"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockEmailSignInService struct {
+	mock.Mock
+}
+
+func (m *MockEmailSignInService) RequestSignIn(ctx context.Context, email string) error {
+	args := m.Called(ctx, email)
+	return args.Error(0)
+}
+
+func (m *MockEmailSignInService) VerifySignIn(ctx context.Context, req *models.EmailSignInVerifyRequest) (*models.AuthResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.AuthResponse), args.Error(1)
+}
+
+func (m *MockEmailSignInService) CleanupExpiredCodes(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+func sendEmailSignInRequest(service *MockEmailSignInService, path string, body string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	NewEmailSignInHandler(service).SetupRoutes(router)
+
+	req, _ := http.NewRequest("POST", path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestRequestEmailSignInHandler(t *testing.T) {
+	t.Run("Accepted", func(t *testing.T) {
+		service := &MockEmailSignInService{}
+		service.On("RequestSignIn", mock.Anything, "friend@example.com").Return(nil)
+
+		w := sendEmailSignInRequest(service, "/api/auth/email/request", `{"email":"friend@example.com"}`)
+		assert.Equal(t, http.StatusAccepted, w.Code)
+		assert.JSONEq(t, `{"expiresInSeconds":900}`, w.Body.String())
+	})
+
+	t.Run("Invalid Email", func(t *testing.T) {
+		service := &MockEmailSignInService{}
+		service.On("RequestSignIn", mock.Anything, "nope").Return(services.ErrInvalidEmail)
+
+		w := sendEmailSignInRequest(service, "/api/auth/email/request", `{"email":"nope"}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Throttled", func(t *testing.T) {
+		service := &MockEmailSignInService{}
+		service.On("RequestSignIn", mock.Anything, "friend@example.com").Return(&models.TooManyAttemptsError{RetryAfter: 90 * time.Second})
+
+		w := sendEmailSignInRequest(service, "/api/auth/email/request", `{"email":"friend@example.com"}`)
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Equal(t, "90", w.Header().Get("Retry-After"))
+	})
+
+	t.Run("Missing Email", func(t *testing.T) {
+		w := sendEmailSignInRequest(&MockEmailSignInService{}, "/api/auth/email/request", `{}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestVerifyEmailSignInHandler(t *testing.T) {
+	t.Run("Signed In", func(t *testing.T) {
+		service := &MockEmailSignInService{}
+		service.On("VerifySignIn", mock.Anything, &models.EmailSignInVerifyRequest{Email: "friend@example.com", Code: "123456"}).Return(&models.AuthResponse{
+			Player:       models.PlayerData{ID: "friend", Name: "Friend"},
+			Token:        "access-token",
+			RefreshToken: "refresh-token",
+		}, nil)
+
+		w := sendEmailSignInRequest(service, "/api/auth/email/verify", `{"email":"friend@example.com","code":"123456"}`)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "access-token", response["token"])
+		assert.Equal(t, "refresh-token", response["refreshToken"])
+	})
+
+	t.Run("Need Display Name", func(t *testing.T) {
+		service := &MockEmailSignInService{}
+		service.On("VerifySignIn", mock.Anything, mock.Anything).Return(nil, &models.NeedDisplayNameError{Reason: "display name is required for new users"})
+
+		w := sendEmailSignInRequest(service, "/api/auth/email/verify", `{"token":"link-token"}`)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"status":"need_display_name"`)
+	})
+
+	t.Run("Invalid Code", func(t *testing.T) {
+		service := &MockEmailSignInService{}
+		service.On("VerifySignIn", mock.Anything, mock.Anything).Return(nil, &models.InvalidSignInCodeError{})
+
+		w := sendEmailSignInRequest(service, "/api/auth/email/verify", `{"email":"friend@example.com","code":"000000"}`)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("Missing Code", func(t *testing.T) {
+		w := sendEmailSignInRequest(&MockEmailSignInService{}, "/api/auth/email/verify", `{"email":"friend@example.com"}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
@@ -1,6 +1,7 @@
 package routes
 
 import (
+	"errors"
 	"fmt"
 	"ligain/backend/middleware"
 	"ligain/backend/services"
@@ -88,7 +89,7 @@ func (h *GameHandler) createGame(c *gin.Context) {
 	// Create the game
 	response, err := h.creationService.CreateGame(&request, player.(models.Player))
 	if err != nil {
-		if err == services.ErrInvalidCompetition || err == services.ErrInvalidSeasonYear || err == services.ErrPlayerGameLimit {
+		if err == services.ErrInvalidCompetition || err == services.ErrInvalidSeasonYear || err == services.ErrPlayerGameLimit || errors.Is(err, services.ErrInvalidBetCutoff) || errors.Is(err, services.ErrInvalidTiebreakers) {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
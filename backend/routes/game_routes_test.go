@@ -105,7 +105,7 @@ func (m *MockGameAuthService) CleanupExpiredTokens(ctx context.Context) error {
 }
 
 // Remove defaultGuestAuth and put the logic directly in the method
-func (m *MockGameAuthService) AuthenticateGuest(ctx context.Context, displayName string) (*models.AuthResponse, error) {
+func (m *MockGameAuthService) AuthenticateGuest(ctx context.Context, displayName string, secret string) (*models.AuthResponse, error) {
 	if displayName == "" {
 		return nil, errors.New("display name is required")
 	}
@@ -131,12 +131,43 @@ func (m *MockGameAuthService) RefreshToken(ctx context.Context, expiredToken str
 	return args.Get(0).(*models.AuthResponse), args.Error(1)
 }
 
-func (m *MockGameAuthService) GetAuthTokenDirectly(ctx context.Context, token string) (*models.AuthToken, error) {
-	args := m.Called(ctx, token)
+func (m *MockGameAuthService) GetPlayer(ctx context.Context, playerID string) (*models.PlayerData, error) {
+	args := m.Called(ctx, playerID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).(*models.AuthToken), args.Error(1)
+	return args.Get(0).(*models.PlayerData), args.Error(1)
+}
+
+func (m *MockGameAuthService) RegenerateGuestSecret(ctx context.Context, playerID string) (string, error) {
+	args := m.Called(ctx, playerID)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockGameAuthService) LinkProvider(ctx context.Context, playerID string, req *models.AuthRequest) (*models.AuthResponse, error) {
+	args := m.Called(ctx, playerID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.AuthResponse), args.Error(1)
+}
+
+func (m *MockGameAuthService) ListSessions(ctx context.Context, playerID string, currentToken string) ([]*models.Session, error) {
+	args := m.Called(ctx, playerID, currentToken)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Session), args.Error(1)
+}
+
+func (m *MockGameAuthService) RevokeSession(ctx context.Context, playerID string, sessionID string) error {
+	args := m.Called(ctx, playerID, sessionID)
+	return args.Error(0)
+}
+
+func (m *MockGameAuthService) RevokeOtherSessions(ctx context.Context, playerID string, currentToken string) (int, error) {
+	args := m.Called(ctx, playerID, currentToken)
+	return args.Int(0), args.Error(1)
 }
 
 func (m *MockGameAuthService) RefreshTokenByPlayerID(ctx context.Context, playerID string) (*models.AuthResponse, error) {
@@ -147,6 +178,14 @@ func (m *MockGameAuthService) RefreshTokenByPlayerID(ctx context.Context, player
 	return args.Get(0).(*models.AuthResponse), args.Error(1)
 }
 
+func (m *MockGameAuthService) RenewAccessToken(ctx context.Context, expiredToken string) (*models.AuthResponse, error) {
+	args := m.Called(ctx, expiredToken)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.AuthResponse), args.Error(1)
+}
+
 func (m *MockGameAuthService) DeleteAccount(ctx context.Context, playerID string) error {
 	args := m.Called(ctx, playerID)
 	return args.Error(0)
@@ -0,0 +1,55 @@
+package routes
+
+import (
+	"ligain/backend/middleware"
+	"ligain/backend/scheduler"
+	"ligain/backend/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+// defaultJobRunLimit is the number of runs listed per job, unless the limit query parameter says otherwise
+const defaultJobRunLimit = 10
+
+// JobHandler shows the background jobs and their last runs to the admins
+type JobHandler struct {
+	scheduler   *scheduler.Scheduler
+	authService services.AuthServiceInterface
+}
+
+// NewJobHandler creates a new JobHandler
+func NewJobHandler(scheduler *scheduler.Scheduler, authService services.AuthServiceInterface) *JobHandler {
+	return &JobHandler{
+		scheduler:   scheduler,
+		authService: authService,
+	}
+}
+
+// SetupRoutes registers the job routes under the admin API
+func (h *JobHandler) SetupRoutes(router *gin.Engine) {
+	admin := router.Group("/api/admin", middleware.PlayerAuth(h.authService), middleware.AdminAuth(h.authService))
+	{
+		admin.GET("/jobs", h.getJobs)
+	}
+}
+
+func (h *JobHandler) getJobs(c *gin.Context) {
+	limit, ok := parseLimit(c)
+	if !ok {
+		return
+	}
+	if limit == 0 {
+		limit = defaultJobRunLimit
+	}
+
+	jobs, err := h.scheduler.Jobs(c.Request.Context(), limit)
+	if err != nil {
+		log.Errorf("Failed to get jobs: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get jobs"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"jobs": jobs})
+}
@@ -0,0 +1,54 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"ligain/backend/models"
+	"ligain/backend/scheduler"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupJobRouter(t *testing.T, player *models.PlayerData) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	jobScheduler := scheduler.NewScheduler(scheduler.NewInMemoryStore(), "instance-1")
+	require.NoError(t, jobScheduler.Register("cleanup-game-codes", "@hourly", func(ctx context.Context) error { return nil }))
+	_, err := jobScheduler.RunJob(context.Background(), "cleanup-game-codes")
+	require.NoError(t, err)
+
+	router := gin.New()
+	NewJobHandler(jobScheduler, &MockAuthService{player: player}).SetupRoutes(router)
+	return router
+}
+
+func TestJobRoutes_GetJobs(t *testing.T) {
+	router := setupJobRouter(t, newTestAdmin())
+
+	w := sendAdminRouteRequest(router, "GET", "/api/admin/jobs", nil)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var response struct {
+		Jobs []scheduler.JobInfo `json:"jobs"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response.Jobs, 1)
+	assert.Equal(t, "cleanup-game-codes", response.Jobs[0].Name)
+	require.Len(t, response.Jobs[0].Runs, 1)
+	assert.Equal(t, scheduler.RunStatusSucceeded, response.Jobs[0].Runs[0].Status)
+
+	w = sendAdminRouteRequest(router, "GET", "/api/admin/jobs?limit=0", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestJobRoutes_RequireAdminRole(t *testing.T) {
+	router := setupJobRouter(t, &models.PlayerData{ID: "player", Name: "Player", Role: models.PlayerRolePlayer})
+
+	w := sendAdminRouteRequest(router, "GET", "/api/admin/jobs", nil)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
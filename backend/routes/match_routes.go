@@ -80,33 +80,37 @@ func (h *MatchHandler) getAuthenticatedPlayer(c *gin.Context) (models.Player, er
 }
 
 // convertMatchResultToJSON converts a MatchResult to a JSON-friendly structure
-func (h *MatchHandler) convertMatchResultToJSON(matchResult *models.MatchResult, playerIDToName map[string]string) map[string]any {
+func convertMatchResultToJSON(matchResult *models.MatchResult, playerIDToName map[string]string) map[string]any {
 	result := map[string]any{
 		"match": matchResult.Match,
 	}
 	if matchResult.Bets != nil {
-		simplifiedBets := h.simplifyBets(matchResult.Bets, playerIDToName)
+		simplifiedBets := simplifyBets(matchResult.Bets, playerIDToName)
 		result["bets"] = simplifiedBets
 	} else {
 		result["bets"] = nil
 	}
 
 	if matchResult.Scores != nil {
-		result["scores"] = h.simplifyScores(matchResult.Scores, matchResult.ScoreBreakdowns, playerIDToName)
+		result["scores"] = simplifyScores(matchResult.Scores, matchResult.ScoreBreakdowns, playerIDToName)
 	} else {
 		result["scores"] = nil
 	}
 
 	if matchResult.PlayerBetStatus != nil {
-		result["playerBetStatuses"] = h.simplifyPlayerBetStatuses(matchResult.PlayerBetStatus, playerIDToName)
+		result["playerBetStatuses"] = simplifyPlayerBetStatuses(matchResult.PlayerBetStatus, playerIDToName)
 	} else {
 		result["playerBetStatuses"] = nil
 	}
 
+	if !matchResult.BettingClosesAt.IsZero() {
+		result["bettingClosesAt"] = matchResult.BettingClosesAt
+	}
+
 	return result
 }
 
-func (h *MatchHandler) simplifyBets(bets map[string]*models.Bet, playerIDToName map[string]string) map[string]SimplifiedBet {
+func simplifyBets(bets map[string]*models.Bet, playerIDToName map[string]string) map[string]SimplifiedBet {
 	simplifiedBets := make(map[string]SimplifiedBet)
 	for playerID, bet := range bets {
 		simplifiedBets[playerID] = SimplifiedBet{
@@ -119,7 +123,7 @@ func (h *MatchHandler) simplifyBets(bets map[string]*models.Bet, playerIDToName
 	return simplifiedBets
 }
 
-func (h *MatchHandler) simplifyScores(scores map[string]int, breakdowns map[string]models.ScoreBreakdown, playerIDToName map[string]string) map[string]SimplifiedScore {
+func simplifyScores(scores map[string]int, breakdowns map[string]models.ScoreBreakdown, playerIDToName map[string]string) map[string]SimplifiedScore {
 	simplifiedScores := make(map[string]SimplifiedScore)
 	for playerID, score := range scores {
 		ss := SimplifiedScore{
@@ -137,7 +141,7 @@ func (h *MatchHandler) simplifyScores(scores map[string]int, breakdowns map[stri
 	return simplifiedScores
 }
 
-func (h *MatchHandler) simplifyPlayerBetStatuses(
+func simplifyPlayerBetStatuses(
 	statuses map[string]bool,
 	playerIDToName map[string]string,
 ) map[string]SimplifiedPlayerBetStatus {
@@ -156,6 +160,8 @@ func (h *MatchHandler) simplifyPlayerBetStatuses(
 func (h *MatchHandler) SetupRoutes(router *gin.Engine) {
 	router.GET("/api/game/:game-id/matches", middleware.PlayerAuth(h.authService), h.getMatches)
 	router.POST("/api/game/:game-id/bet", middleware.PlayerAuth(h.authService), h.saveBet)
+	router.GET("/api/game/:game-id/leaderboard", middleware.PlayerAuth(h.authService), h.getLeaderboard)
+	router.GET("/api/game/:game-id/leaderboard/provisional", middleware.PlayerAuth(h.authService), h.getProvisionalLeaderboard)
 }
 
 func (h *MatchHandler) getMatches(c *gin.Context) {
@@ -196,12 +202,12 @@ func (h *MatchHandler) getMatches(c *gin.Context) {
 	// Convert MatchResults to JSON-friendly format
 	jsonIncomingMatches := make(map[string]any)
 	for id, matchResult := range incomingMatches {
-		jsonIncomingMatches[id] = h.convertMatchResultToJSON(matchResult, playerIDToName)
+		jsonIncomingMatches[id] = convertMatchResultToJSON(matchResult, playerIDToName)
 	}
 
 	jsonPastMatches := make(map[string]any)
 	for id, matchResult := range pastMatches {
-		jsonPastMatches[id] = h.convertMatchResultToJSON(matchResult, playerIDToName)
+		jsonPastMatches[id] = convertMatchResultToJSON(matchResult, playerIDToName)
 	}
 
 	c.JSON(http.StatusOK, gin.H{
@@ -210,6 +216,135 @@ func (h *MatchHandler) getMatches(c *gin.Context) {
 	})
 }
 
+// Standing represents the rank of a player and the statistics used to break ties
+type Standing struct {
+	PlayerID    string `json:"playerId"`
+	PlayerName  string `json:"playerName"`
+	Rank        int    `json:"rank"`
+	Points      int    `json:"points"`
+	ExactScores int    `json:"exactScores"`
+	MissedBets  int    `json:"missedBets"`
+}
+
+// ProvisionalStanding represents the confirmed and provisional points of a player
+type ProvisionalStanding struct {
+	PlayerID          string `json:"playerId"`
+	PlayerName        string `json:"playerName"`
+	Rank              int    `json:"rank"`
+	ConfirmedPoints   int    `json:"confirmedPoints"`
+	ProvisionalPoints int    `json:"provisionalPoints"`
+}
+
+// getLeaderboard returns the standings of the game, ties being broken by the game's tiebreakers
+func (h *MatchHandler) getLeaderboard(c *gin.Context) {
+	gameId := c.Param("game-id")
+	if gameId == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "game-id is required"})
+		return
+	}
+
+	player, err := h.getAuthenticatedPlayer(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	gameService, err := h.gameCreationService.GetGameService(gameId, player)
+	if err != nil {
+		if errors.Is(err, services.ErrPlayerNotInGame) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		log.Errorf("Failed to get game service: %v", err)
+		c.JSON(http.StatusNotFound, gin.H{"error": "Your game was not found"})
+		return
+	}
+
+	gameStandings, err := gameService.GetStandings()
+	if err != nil {
+		log.Errorf("Failed to get standings: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get standings"})
+		return
+	}
+
+	standings := make([]Standing, 0, len(gameStandings))
+	for _, standing := range gameStandings {
+		standings = append(standings, Standing{
+			PlayerID:    standing.Player.GetID(),
+			PlayerName:  standing.Player.GetName(),
+			Rank:        standing.Rank,
+			Points:      standing.Points,
+			ExactScores: standing.ExactScores,
+			MissedBets:  standing.MissedBets,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"standings": standings,
+	})
+}
+
+// getProvisionalLeaderboard returns the standings as if every in-progress match ended with its live score
+func (h *MatchHandler) getProvisionalLeaderboard(c *gin.Context) {
+	gameId := c.Param("game-id")
+	if gameId == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "game-id is required"})
+		return
+	}
+
+	player, err := h.getAuthenticatedPlayer(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	gameService, err := h.gameCreationService.GetGameService(gameId, player)
+	if err != nil {
+		if errors.Is(err, services.ErrPlayerNotInGame) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		log.Errorf("Failed to get game service: %v", err)
+		c.JSON(http.StatusNotFound, gin.H{"error": "Your game was not found"})
+		return
+	}
+
+	leaderboard, err := gameService.GetProvisionalLeaderboard()
+	if err != nil {
+		log.Errorf("Failed to get provisional leaderboard: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get provisional leaderboard"})
+		return
+	}
+
+	playerIDToName := make(map[string]string)
+	for _, p := range gameService.GetPlayers() {
+		playerIDToName[p.GetID()] = p.GetName()
+	}
+
+	standings := make([]ProvisionalStanding, 0, len(leaderboard.Standings))
+	for _, standing := range leaderboard.Standings {
+		playerID := standing.Player.GetID()
+		standings = append(standings, ProvisionalStanding{
+			PlayerID:          playerID,
+			PlayerName:        playerIDToName[playerID],
+			Rank:              standing.Rank,
+			ConfirmedPoints:   leaderboard.ConfirmedPoints[playerID],
+			ProvisionalPoints: leaderboard.ProvisionalPoints[playerID],
+		})
+	}
+
+	jsonLiveMatches := make(map[string]any)
+	for id, matchResult := range leaderboard.LiveMatches {
+		jsonLiveMatches[id] = convertMatchResultToJSON(matchResult, playerIDToName)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"provisional": leaderboard.Provisional,
+		"standings":   standings,
+		"liveMatches": jsonLiveMatches,
+	})
+}
+
 type SaveBetRequest struct {
 	MatchID            string `json:"matchId" binding:"required"`
 	PredictedHomeGoals *int   `json:"predictedHomeGoals" binding:"required"`
@@ -279,7 +414,7 @@ func (h *MatchHandler) saveBet(c *gin.Context) {
 	match := matchResult.Match
 
 	bet := models.NewBet(match, *request.PredictedHomeGoals, *request.PredictedAwayGoals)
-	updateErr := gameService.UpdatePlayerBet(player, bet, h.timeFunc())
+	updateErr := gameService.UpdatePlayerBet(requestContextWithDevice(c), player, bet, h.timeFunc())
 	if updateErr != nil {
 		log.Error("Failed to update player bet", updateErr)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": updateErr.Error()})
@@ -9,6 +9,7 @@ import (
 	"ligain/backend/middleware"
 	"ligain/backend/models"
 	"ligain/backend/repositories"
+	"ligain/backend/rules"
 	"ligain/backend/services"
 	"net/http"
 	"net/http/httptest"
@@ -18,6 +19,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 var testTime = time.Date(2024, 3, 15, 15, 0, 0, 0, time.UTC)
@@ -60,6 +62,18 @@ func (m *MockGame) CheckPlayerBetValidity(player models.Player, bet *models.Bet,
 	return nil
 }
 
+func (m *MockGame) GetBetCutoff() models.BetCutoff {
+	return models.BetCutoff{}
+}
+
+func (m *MockGame) GetTiebreakers() []string {
+	return nil
+}
+
+func (m *MockGame) BettingClosesAt(match models.Match) time.Time {
+	return match.GetDate()
+}
+
 func (m *MockGame) AddPlayerBet(player models.Player, bet *models.Bet) error {
 	if m.bets == nil {
 		m.bets = make(map[string]map[string]*models.Bet)
@@ -110,10 +124,22 @@ func (m *MockGame) GetWinner() []models.Player {
 	return nil
 }
 
-func (m *MockGame) GetIncomingMatchesForTesting() map[string]*models.MatchResult {
+func (m *MockGame) GetStandings() []models.Standing {
+	return []models.Standing{}
+}
+
+func (m *MockGame) RankPlayers(points map[string]int) []models.Standing {
+	return []models.Standing{}
+}
+
+func (m *MockGame) GetIncomingMatchesWithAllBets() map[string]*models.MatchResult {
 	return m.incomingMatches
 }
 
+func (m *MockGame) GetProvisionalResults() map[string]*models.MatchResult {
+	return make(map[string]*models.MatchResult)
+}
+
 func (m *MockGame) AddPlayer(player models.Player) error {
 	return nil
 }
@@ -138,6 +164,14 @@ func (m *MockGame) Finish() {
 	// No-op for test
 }
 
+func (m *MockGame) Reopen() {
+	// No-op for test
+}
+
+func (m *MockGame) RescoreMatch(match models.Match) (map[string]int, error) {
+	return nil, nil
+}
+
 // MockBetAuthService for bet tests
 // Only implements ValidateToken
 // (other methods can panic if called)
@@ -170,7 +204,7 @@ func (m *MockBetAuthService) GetOrCreatePlayer(ctx context.Context, verifiedUser
 	return testPlayer, nil
 }
 
-func (m *MockBetAuthService) AuthenticateGuest(ctx context.Context, displayName string) (*models.AuthResponse, error) {
+func (m *MockBetAuthService) AuthenticateGuest(ctx context.Context, displayName string, secret string) (*models.AuthResponse, error) {
 	if displayName == "" {
 		return nil, errors.New("display name is required")
 	}
@@ -195,7 +229,27 @@ func (m *MockBetAuthService) RefreshToken(ctx context.Context, expiredToken stri
 	panic("not implemented")
 }
 
-func (m *MockBetAuthService) GetAuthTokenDirectly(ctx context.Context, token string) (*models.AuthToken, error) {
+func (m *MockBetAuthService) GetPlayer(ctx context.Context, playerID string) (*models.PlayerData, error) {
+	panic("not implemented")
+}
+
+func (m *MockBetAuthService) RegenerateGuestSecret(ctx context.Context, playerID string) (string, error) {
+	panic("not implemented")
+}
+
+func (m *MockBetAuthService) LinkProvider(ctx context.Context, playerID string, req *models.AuthRequest) (*models.AuthResponse, error) {
+	panic("not implemented")
+}
+
+func (m *MockBetAuthService) ListSessions(ctx context.Context, playerID string, currentToken string) ([]*models.Session, error) {
+	panic("not implemented")
+}
+
+func (m *MockBetAuthService) RevokeSession(ctx context.Context, playerID string, sessionID string) error {
+	panic("not implemented")
+}
+
+func (m *MockBetAuthService) RevokeOtherSessions(ctx context.Context, playerID string, currentToken string) (int, error) {
 	panic("not implemented")
 }
 
@@ -203,6 +257,10 @@ func (m *MockBetAuthService) RefreshTokenByPlayerID(ctx context.Context, playerI
 	panic("not implemented")
 }
 
+func (m *MockBetAuthService) RenewAccessToken(ctx context.Context, expiredToken string) (*models.AuthResponse, error) {
+	panic("not implemented")
+}
+
 func (m *MockBetAuthService) DeleteAccount(ctx context.Context, playerID string) error {
 	panic("not implemented")
 }
@@ -232,6 +290,8 @@ func setupTestRouterWithTime(timeFunc func() time.Time) (*gin.Engine, *MockGame)
 	// Add middleware to routes manually for testing
 	router.GET("/api/game/:game-id/matches", middleware.PlayerAuth(mockAuthService), handler.getMatches)
 	router.POST("/api/game/:game-id/bet", middleware.PlayerAuth(mockAuthService), handler.saveBet)
+	router.GET("/api/game/:game-id/leaderboard", middleware.PlayerAuth(mockAuthService), handler.getLeaderboard)
+	router.GET("/api/game/:game-id/leaderboard/provisional", middleware.PlayerAuth(mockAuthService), handler.getProvisionalLeaderboard)
 
 	return router, game
 }
@@ -673,6 +733,41 @@ func TestSaveBet_TooLateToBet(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, w.Code, "bet submitted after match date should be rejected")
 }
 
+func TestGetMatches_BettingClosesAt(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	gameID := "123e4567-e89b-12d3-a456-426614174000"
+	player := &models.PlayerData{ID: "player1-id", Name: "Player1"}
+
+	match := models.NewSeasonMatch("Team1", "Team2", "2024", "Premier League", testTime, 1)
+	game := rules.NewFreshGame("2024", "Premier League", "Test Game", []models.Player{player}, []models.Match{match}, &rules.ScorerOriginal{})
+	game.SetBetCutoff(models.BetCutoff{Minutes: 30})
+	gameRepo := repositories.NewInMemoryGameRepository()
+	require.NoError(t, gameRepo.SaveWithId(gameID, game))
+	gamePlayerRepo := repositories.NewInMemoryGamePlayerRepository(repositories.NewInMemoryPlayerRepository())
+	gameService := services.NewGameService(gameID, gameRepo, repositories.NewInMemoryBetRepository(), gamePlayerRepo)
+
+	mockGameCreationService := &MockGameCreationService{}
+	mockGameCreationService.On("GetGameService", gameID, mock.AnythingOfType("*models.PlayerData")).Return(gameService, nil)
+	mockAuthService := &MockBetAuthService{}
+	router := gin.New()
+	router.GET("/api/game/:game-id/matches", middleware.PlayerAuth(mockAuthService), NewMatchHandler(mockGameCreationService, mockAuthService).getMatches)
+
+	req := httptest.NewRequest("GET", "/api/game/"+gameID+"/matches", nil)
+	req.Header.Set("Authorization", "Bearer testtoken")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		IncomingMatches map[string]struct {
+			BettingClosesAt time.Time `json:"bettingClosesAt"`
+		} `json:"incomingMatches"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Contains(t, response.IncomingMatches, match.Id())
+	assert.True(t, testTime.Add(-30*time.Minute).Equal(response.IncomingMatches[match.Id()].BettingClosesAt))
+}
+
 func TestGetMatches_PlayerNotInGame_ServiceLevel(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	router := gin.New()
@@ -704,3 +799,86 @@ func TestGetMatches_PlayerNotInGame_ServiceLevel(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, "player is not in the game", response["error"])
 }
+
+func TestGetProvisionalLeaderboard(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+	gameID := "123e4567-e89b-12d3-a456-426614174000"
+
+	playerRepo := repositories.NewInMemoryPlayerRepository()
+	alice := &models.PlayerData{Name: "Alice"}
+	bob := &models.PlayerData{Name: "Bob"}
+	require.NoError(t, playerRepo.CreatePlayer(ctx, alice))
+	require.NoError(t, playerRepo.CreatePlayer(ctx, bob))
+
+	// No favorite with these odds, so only the clairvoyant multiplier applies
+	finishedMatch := models.NewSeasonMatchWithKnownOdds("Team1", "Team2", "2024", "Premier League", testTime.Add(-48*time.Hour), 1, 2.0, 3.0, 2.5)
+	liveMatch := models.NewSeasonMatchWithKnownOdds("Team3", "Team4", "2024", "Premier League", testTime.Add(-1*time.Hour), 2, 2.0, 3.0, 2.5)
+	game := rules.NewFreshGame("2024", "Premier League", "Test Game", []models.Player{alice, bob}, []models.Match{finishedMatch, liveMatch}, &rules.ScorerOriginal{})
+
+	// Alice is perfect on the finished match (500), Bob only has the result (300)
+	require.NoError(t, game.AddPlayerBet(alice, models.NewBet(finishedMatch, 1, 0)))
+	require.NoError(t, game.AddPlayerBet(bob, models.NewBet(finishedMatch, 3, 0)))
+	finishedMatch.Finish(1, 0)
+	require.NoError(t, game.UpdateMatch(finishedMatch))
+	scores, err := game.CalculateMatchScores(finishedMatch)
+	require.NoError(t, err)
+	game.ApplyMatchScores(finishedMatch, scores)
+
+	// Bob is alone to be perfect on the live score (500 * 1.1), which puts him ahead until the final whistle
+	require.NoError(t, game.AddPlayerBet(alice, models.NewBet(liveMatch, 2, 0)))
+	require.NoError(t, game.AddPlayerBet(bob, models.NewBet(liveMatch, 0, 2)))
+	liveMatch.Start()
+	liveMatch.AwayGoals = 2
+	require.NoError(t, game.UpdateMatch(liveMatch))
+
+	gameRepo := repositories.NewInMemoryGameRepository()
+	require.NoError(t, gameRepo.SaveWithId(gameID, game))
+	gamePlayerRepo := repositories.NewInMemoryGamePlayerRepository(playerRepo)
+	require.NoError(t, gamePlayerRepo.AddPlayerToGame(ctx, gameID, alice.ID))
+	require.NoError(t, gamePlayerRepo.AddPlayerToGame(ctx, gameID, bob.ID))
+	gameService := services.NewGameService(gameID, gameRepo, repositories.NewInMemoryBetRepository(), gamePlayerRepo)
+
+	mockGameCreationService := &MockGameCreationService{}
+	mockGameCreationService.On("GetGameService", gameID, mock.AnythingOfType("*models.PlayerData")).Return(gameService, nil)
+	mockAuthService := &MockBetAuthService{}
+	router := gin.New()
+	router.GET("/api/game/:game-id/leaderboard/provisional", middleware.PlayerAuth(mockAuthService), NewMatchHandler(mockGameCreationService, mockAuthService).getProvisionalLeaderboard)
+
+	req := httptest.NewRequest("GET", "/api/game/"+gameID+"/leaderboard/provisional", nil)
+	req.Header.Set("Authorization", "Bearer testtoken")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Provisional bool                  `json:"provisional"`
+		Standings   []ProvisionalStanding `json:"standings"`
+		LiveMatches map[string]any        `json:"liveMatches"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.True(t, response.Provisional)
+	assert.Equal(t, []ProvisionalStanding{
+		{PlayerID: bob.ID, PlayerName: "Bob", Rank: 1, ConfirmedPoints: 300, ProvisionalPoints: 850},
+		{PlayerID: alice.ID, PlayerName: "Alice", Rank: 2, ConfirmedPoints: 500, ProvisionalPoints: 500},
+	}, response.Standings)
+	assert.Len(t, response.LiveMatches, 1)
+	assert.Contains(t, response.LiveMatches, liveMatch.Id())
+}
+
+func TestGetLeaderboard(t *testing.T) {
+	router, _ := setupTestRouter()
+
+	req := httptest.NewRequest("GET", "/api/game/123e4567-e89b-12d3-a456-426614174000/leaderboard", nil)
+	req.Header.Set("Authorization", "Bearer testtoken")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]any
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.NotNil(t, response["standings"])
+}
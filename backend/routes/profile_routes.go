@@ -19,8 +19,10 @@ const (
 
 // ProfileHandler handles profile-related HTTP requests
 type ProfileHandler struct {
-	profileService services.ProfileService
-	authService    services.AuthServiceInterface
+	profileService     services.ProfileService
+	authService        services.AuthServiceInterface
+	achievementService services.AchievementService
+	ratingService      services.RatingService
 }
 
 // NewProfileHandler creates a new ProfileHandler
@@ -34,6 +36,21 @@ func NewProfileHandler(
 	}
 }
 
+// NewProfileHandlerWithPlayerStats creates a ProfileHandler that also exposes the badges and the rating of the players
+func NewProfileHandlerWithPlayerStats(
+	profileService services.ProfileService,
+	authService services.AuthServiceInterface,
+	achievementService services.AchievementService,
+	ratingService services.RatingService,
+) *ProfileHandler {
+	return &ProfileHandler{
+		profileService:     profileService,
+		authService:        authService,
+		achievementService: achievementService,
+		ratingService:      ratingService,
+	}
+}
+
 // SetupRoutes registers profile routes on the router
 func (h *ProfileHandler) SetupRoutes(router *gin.Engine) {
 	players := router.Group("/api/players")
@@ -74,7 +91,41 @@ func (h *ProfileHandler) GetPlayer(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"player": h.toPlayerResponse(player)})
+	response := h.toPlayerResponse(player)
+	if h.ratingService != nil {
+		rating, err := h.ratingService.GetPlayerRating(c.Request.Context(), playerID)
+		if err != nil {
+			log.Errorf("GetPlayer - Error fetching rating of player %s: %v", playerID, err)
+		} else if rating != nil {
+			response["rating"] = rating.Rating
+		}
+	}
+	if h.achievementService != nil {
+		achievements, err := h.achievementService.GetPlayerAchievements(c.Request.Context(), playerID)
+		if err != nil {
+			// Badges are secondary, the profile is still returned without them
+			log.Errorf("GetPlayer - Error fetching achievements of player %s: %v", playerID, err)
+		} else {
+			response["badges"] = toBadgesResponse(achievements)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"player": response})
+}
+
+// toBadgesResponse converts achievements to the API response format
+func toBadgesResponse(achievements []*models.Achievement) []map[string]interface{} {
+	badges := make([]map[string]interface{}, 0, len(achievements))
+	for _, achievement := range achievements {
+		badges = append(badges, map[string]interface{}{
+			"code":       achievement.Code,
+			"title":      achievement.Code.Title(),
+			"game_id":    achievement.GameID,
+			"match_id":   achievement.MatchID,
+			"awarded_at": achievement.AwardedAt,
+		})
+	}
+	return badges
 }
 
 // UploadAvatar handles avatar upload for the current user
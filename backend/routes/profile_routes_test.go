@@ -11,6 +11,7 @@ import (
 	"io"
 	"ligain/backend/middleware"
 	"ligain/backend/models"
+	"ligain/backend/repositories"
 	"ligain/backend/services"
 	"mime/multipart"
 	"net/http"
@@ -73,6 +74,10 @@ func (m *MockProfileService) GetPlayerProfile(ctx context.Context, playerID stri
 	return m.getPlayerResult, nil
 }
 
+func (m *MockProfileService) RefreshExpiringAvatarURLs(ctx context.Context) (int, error) {
+	return 0, nil
+}
+
 // Helper to create a test JPEG image for upload tests
 func createTestJPEGForUpload(width, height int) []byte {
 	img := image.NewRGBA(image.Rect(0, 0, width, height))
@@ -187,6 +192,50 @@ func TestGetPlayer_NoAvatar(t *testing.T) {
 	assert.False(t, hasAvatarURL)
 }
 
+func TestGetPlayer_WithBadgesAndRating(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	profileService := NewMockProfileService()
+	profileService.getPlayerResult = &models.PlayerData{
+		ID:   "player-1",
+		Name: "Test Player",
+	}
+	authService := &MockAuthService{player: &models.PlayerData{ID: "player-1", Name: "Test Player"}}
+
+	achievementRepo := repositories.NewInMemoryAchievementRepository()
+	_, err := achievementRepo.SaveAchievement(context.Background(), models.NewAchievement("player-1", "game-1", models.AchievementUpsetCalled, "match-1", time.Now()))
+	require.NoError(t, err)
+	achievementService := services.NewAchievementService(achievementRepo)
+	ratingRepo := repositories.NewInMemoryRatingRepository()
+	require.NoError(t, ratingRepo.SaveRatings(context.Background(), []*models.PlayerRating{{PlayerID: "player-1", Rating: 1523.5, MatchesRated: 3}}))
+	ratingService := services.NewRatingService(ratingRepo)
+
+	handler := NewProfileHandlerWithPlayerStats(profileService, authService, achievementService, ratingService)
+	router := gin.New()
+	router.GET("/players/:id", middleware.PlayerAuth(authService), handler.GetPlayer)
+
+	req, _ := http.NewRequest("GET", "/players/player-1", nil)
+	req.Header.Set("Authorization", "Bearer test-token")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+
+	player := response["player"].(map[string]interface{})
+	badges := player["badges"].([]interface{})
+	require.Len(t, badges, 1)
+	badge := badges[0].(map[string]interface{})
+	assert.Equal(t, "upset_called", badge["code"])
+	assert.Equal(t, "Called an upset", badge["title"])
+	assert.Equal(t, "game-1", badge["game_id"])
+	assert.Equal(t, "match-1", badge["match_id"])
+	assert.Equal(t, 1523.5, player["rating"])
+}
+
 func TestGetPlayer_RefreshesExpiredURL(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
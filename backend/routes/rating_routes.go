@@ -0,0 +1,72 @@
+package routes
+
+import (
+	"ligain/backend/middleware"
+	"ligain/backend/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+// RatingHandler handles the cross-game rating routes
+type RatingHandler struct {
+	ratingService services.RatingService
+	authService   services.AuthServiceInterface
+}
+
+// NewRatingHandler creates a new RatingHandler
+func NewRatingHandler(ratingService services.RatingService, authService services.AuthServiceInterface) *RatingHandler {
+	return &RatingHandler{
+		ratingService: ratingService,
+		authService:   authService,
+	}
+}
+
+// SetupRoutes registers rating routes on the router
+func (h *RatingHandler) SetupRoutes(router *gin.Engine) {
+	router.GET("/api/leaderboard/global", middleware.PlayerAuth(h.authService), h.getGlobalLeaderboard)
+}
+
+// GlobalStanding represents the rank of a player on the global leaderboard
+type GlobalStanding struct {
+	Rank         int     `json:"rank"`
+	PlayerID     string  `json:"playerId"`
+	PlayerName   string  `json:"playerName"`
+	Rating       float64 `json:"rating"`
+	MatchesRated int     `json:"matchesRated"`
+}
+
+// getGlobalLeaderboard returns the best rated players across every game
+func (h *RatingHandler) getGlobalLeaderboard(c *gin.Context) {
+	limit := services.DefaultGlobalLeaderboardSize
+	if limitParam := c.Query("limit"); limitParam != "" {
+		parsed, err := strconv.Atoi(limitParam)
+		if err != nil || parsed <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive integer"})
+			return
+		}
+		limit = parsed
+	}
+
+	ratings, err := h.ratingService.GetGlobalLeaderboard(c.Request.Context(), limit)
+	if err != nil {
+		log.Errorf("Failed to get global leaderboard: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get global leaderboard"})
+		return
+	}
+
+	standings := make([]GlobalStanding, 0, len(ratings))
+	for i, rating := range ratings {
+		standings = append(standings, GlobalStanding{
+			Rank:         i + 1,
+			PlayerID:     rating.PlayerID,
+			PlayerName:   rating.PlayerName,
+			Rating:       rating.Rating,
+			MatchesRated: rating.MatchesRated,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"leaderboard": standings})
+}
@@ -0,0 +1,68 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"ligain/backend/models"
+	"ligain/backend/repositories"
+	"ligain/backend/services"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupRatingRouter(t *testing.T) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	ratingRepo := repositories.NewInMemoryRatingRepository()
+	err := ratingRepo.SaveRatings(context.Background(), []*models.PlayerRating{
+		{PlayerID: "player-1", PlayerName: "First", Rating: 1550, MatchesRated: 4, UpdatedAt: time.Now()},
+		{PlayerID: "player-2", PlayerName: "Second", Rating: 1480, MatchesRated: 4, UpdatedAt: time.Now()},
+		{PlayerID: "player-3", PlayerName: "Third", Rating: 1420, MatchesRated: 2, UpdatedAt: time.Now()},
+	})
+	require.NoError(t, err)
+
+	authService := &MockAuthService{player: &models.PlayerData{ID: "player-1", Name: "First"}}
+	handler := NewRatingHandler(services.NewRatingService(ratingRepo), authService)
+	router := gin.New()
+	handler.SetupRoutes(router)
+	return router
+}
+
+func TestGetGlobalLeaderboard(t *testing.T) {
+	router := setupRatingRouter(t)
+
+	req, _ := http.NewRequest("GET", "/api/leaderboard/global?limit=2", nil)
+	req.Header.Set("Authorization", "Bearer test-token")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Leaderboard []GlobalStanding `json:"leaderboard"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response.Leaderboard, 2)
+	assert.Equal(t, GlobalStanding{Rank: 1, PlayerID: "player-1", PlayerName: "First", Rating: 1550, MatchesRated: 4}, response.Leaderboard[0])
+	assert.Equal(t, "player-2", response.Leaderboard[1].PlayerID)
+	assert.Equal(t, 2, response.Leaderboard[1].Rank)
+}
+
+func TestGetGlobalLeaderboard_InvalidLimit(t *testing.T) {
+	router := setupRatingRouter(t)
+
+	req, _ := http.NewRequest("GET", "/api/leaderboard/global?limit=abc", nil)
+	req.Header.Set("Authorization", "Bearer test-token")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,114 @@
+package rules
+
+import (
+	"ligain/backend/models"
+	"sort"
+)
+
+const (
+	// correctOutcomeStreakLength is the number of correct outcomes in a row needed for the streak achievement
+	correctOutcomeStreakLength = 5
+	// upsetRiskMultiplier is the minimum risk multiplier of a match for its correct prediction to count as an upset
+	upsetRiskMultiplier = 2.0
+)
+
+// EvaluateAchievements returns the achievements each player qualifies for with the result of the match, keyed by player id
+// The scores of the match must already be applied to the game, so that it's part of the past results.
+// Achievements already awarded are returned again: deduplication is up to the caller.
+func EvaluateAchievements(game models.Game, match models.Match) map[string][]models.AchievementCode {
+	awards := make(map[string][]models.AchievementCode)
+	pastResults := game.GetPastResults()
+	result, exists := pastResults[match.Id()]
+	if !exists {
+		return awards
+	}
+	history := sortedResults(pastResults)
+
+	for _, player := range game.GetPlayers() {
+		playerID := player.GetID()
+		var codes []models.AchievementCode
+
+		bet := result.Bets[playerID]
+		if bet != nil {
+			if isBetPerfectAgainstMatch(bet, result.Match) {
+				codes = append(codes, models.AchievementFirstPerfectScore)
+			}
+			if isBetCorrectAgainstMatch(bet, result.Match) && result.ScoreBreakdowns[playerID].RiskMultiplier >= upsetRiskMultiplier {
+				codes = append(codes, models.AchievementUpsetCalled)
+			}
+		}
+		if correctOutcomeStreak(history, match.Id(), playerID) >= correctOutcomeStreakLength {
+			codes = append(codes, models.AchievementCorrectOutcomeStreak)
+		}
+		if hasBetOnWholeMatchday(game, player, history, result.Match) {
+			codes = append(codes, models.AchievementFullMatchday)
+		}
+
+		if len(codes) > 0 {
+			awards[playerID] = codes
+		}
+	}
+	return awards
+}
+
+// sortedResults returns the results ordered by match date, then by match id
+func sortedResults(results map[string]*models.MatchResult) []*models.MatchResult {
+	sorted := make([]*models.MatchResult, 0, len(results))
+	for _, result := range results {
+		sorted = append(sorted, result)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if !sorted[i].Match.GetDate().Equal(sorted[j].Match.GetDate()) {
+			return sorted[i].Match.GetDate().Before(sorted[j].Match.GetDate())
+		}
+		return sorted[i].Match.Id() < sorted[j].Match.Id()
+	})
+	return sorted
+}
+
+// correctOutcomeStreak counts the correct outcomes in a row the player predicted, up to the given match
+func correctOutcomeStreak(history []*models.MatchResult, matchID string, playerID string) int {
+	end := -1
+	for i, result := range history {
+		if result.Match.Id() == matchID {
+			end = i
+			break
+		}
+	}
+	streak := 0
+	for i := end; i >= 0; i-- {
+		bet := history[i].Bets[playerID]
+		if bet == nil || !isBetCorrectAgainstMatch(bet, history[i].Match) {
+			break
+		}
+		streak++
+	}
+	return streak
+}
+
+// hasBetOnWholeMatchday checks that the matchday of the match is over in the game, and that the player bet on each of its matches
+func hasBetOnWholeMatchday(game models.Game, player models.Player, history []*models.MatchResult, match models.Match) bool {
+	matchday, ok := matchdayOf(match)
+	if !ok {
+		return false
+	}
+	for _, incoming := range game.GetIncomingMatches(player) {
+		if incomingMatchday, ok := matchdayOf(incoming.Match); ok && incomingMatchday == matchday {
+			return false
+		}
+	}
+	for _, result := range history {
+		if resultMatchday, ok := matchdayOf(result.Match); ok && resultMatchday == matchday && result.Bets[player.GetID()] == nil {
+			return false
+		}
+	}
+	return true
+}
+
+func matchdayOf(match models.Match) (int, bool) {
+	seasonMatch, ok := match.(*models.SeasonMatch)
+	if !ok {
+		return 0, false
+	}
+	return seasonMatch.Matchday, true
+}
@@ -0,0 +1,97 @@
+package rules
+
+import (
+	"ligain/backend/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// scoreMatch finishes the match and applies its scores to the game, like the game service does
+func scoreMatch(t *testing.T, game *GameImpl, match *models.SeasonMatch, homeGoals, awayGoals int) {
+	match.Finish(homeGoals, awayGoals)
+	require.NoError(t, game.UpdateMatch(match))
+	scores, err := game.CalculateMatchScores(match)
+	require.NoError(t, err)
+	game.ApplyMatchScores(match, scores)
+}
+
+func TestEvaluateAchievements_PerfectScoreUpsetAndFullMatchday(t *testing.T) {
+	alice, bob, carol := newTestPlayer("Alice"), newTestPlayer("Bob"), newTestPlayer("Carol")
+	// The home team is the clear favorite, so an away win is an upset
+	upset := models.NewSeasonMatchWithKnownOdds("Team1", "Team2", "2024", "Premier League", testTime, 1, 1.5, 4.0, 3.5)
+	nextMatchday := models.NewSeasonMatch("Team3", "Team4", "2024", "Premier League", testTime.Add(7*24*time.Hour), 2)
+	game := NewFreshGame("2024", "Premier League", "Test Game", []models.Player{alice, bob, carol}, []models.Match{upset, nextMatchday}, &ScorerOriginal{})
+
+	require.NoError(t, game.AddPlayerBet(alice, models.NewBet(upset, 0, 1)))
+	require.NoError(t, game.AddPlayerBet(bob, models.NewBet(upset, 2, 0)))
+	scoreMatch(t, game, upset, 0, 1)
+
+	awards := EvaluateAchievements(game, upset)
+
+	assert.ElementsMatch(t, []models.AchievementCode{
+		models.AchievementFirstPerfectScore,
+		models.AchievementUpsetCalled,
+		models.AchievementFullMatchday,
+	}, awards["Alice"])
+	assert.Equal(t, []models.AchievementCode{models.AchievementFullMatchday}, awards["Bob"])
+	assert.NotContains(t, awards, "Carol")
+}
+
+func TestEvaluateAchievements_FullMatchdayWaitsForTheLastMatch(t *testing.T) {
+	alice := newTestPlayer("Alice")
+	match1 := models.NewSeasonMatch("Team1", "Team2", "2024", "Premier League", testTime, 1)
+	match2 := models.NewSeasonMatch("Team3", "Team4", "2024", "Premier League", testTime.Add(2*time.Hour), 1)
+	game := NewFreshGame("2024", "Premier League", "Test Game", []models.Player{alice}, []models.Match{match1, match2}, &ScorerOriginal{})
+
+	require.NoError(t, game.AddPlayerBet(alice, models.NewBet(match1, 1, 0)))
+	require.NoError(t, game.AddPlayerBet(alice, models.NewBet(match2, 1, 0)))
+
+	scoreMatch(t, game, match1, 0, 0)
+	assert.Empty(t, EvaluateAchievements(game, match1))
+
+	scoreMatch(t, game, match2, 0, 0)
+	assert.Equal(t, []models.AchievementCode{models.AchievementFullMatchday}, EvaluateAchievements(game, match2)["Alice"])
+}
+
+func TestEvaluateAchievements_CorrectOutcomeStreak(t *testing.T) {
+	alice, bob := newTestPlayer("Alice"), newTestPlayer("Bob")
+	matches := make([]*models.SeasonMatch, 0, 6)
+	gameMatches := make([]models.Match, 0, 6)
+	for i := 0; i < 6; i++ {
+		match := models.NewSeasonMatch("Home", "Away", "2024", "Premier League", testTime.Add(time.Duration(i)*24*time.Hour), i+1)
+		matches = append(matches, match)
+		gameMatches = append(gameMatches, match)
+	}
+	game := NewFreshGame("2024", "Premier League", "Test Game", []models.Player{alice, bob}, gameMatches, &ScorerOriginal{})
+
+	for i, match := range matches {
+		require.NoError(t, game.AddPlayerBet(alice, models.NewBet(match, 3, 0)))
+		if i == 2 {
+			// Bob breaks his streak on the third match
+			require.NoError(t, game.AddPlayerBet(bob, models.NewBet(match, 0, 1)))
+		} else {
+			require.NoError(t, game.AddPlayerBet(bob, models.NewBet(match, 2, 0)))
+		}
+	}
+
+	for i, match := range matches {
+		scoreMatch(t, game, match, 1, 0)
+		awards := EvaluateAchievements(game, match)
+		if i < 4 {
+			assert.NotContains(t, awards["Alice"], models.AchievementCorrectOutcomeStreak, "match %d", i)
+		} else {
+			assert.Contains(t, awards["Alice"], models.AchievementCorrectOutcomeStreak, "match %d", i)
+		}
+		assert.NotContains(t, awards["Bob"], models.AchievementCorrectOutcomeStreak, "match %d", i)
+	}
+}
+
+func TestEvaluateAchievements_UnknownMatch(t *testing.T) {
+	match := models.NewSeasonMatch("Team1", "Team2", "2024", "Premier League", testTime, 1)
+	game := NewFreshGame("2024", "Premier League", "Test Game", []models.Player{newTestPlayer("Alice")}, []models.Match{match}, &ScorerOriginal{})
+
+	assert.Empty(t, EvaluateAchievements(game, match))
+}
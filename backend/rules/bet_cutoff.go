@@ -0,0 +1,41 @@
+package rules
+
+import (
+	"ligain/backend/models"
+	"time"
+)
+
+// SetBetCutoff changes how long before kickoff the game stops taking bets
+func (g *GameImpl) SetBetCutoff(cutoff models.BetCutoff) {
+	g.betCutoff = cutoff
+}
+
+func (g *GameImpl) GetBetCutoff() models.BetCutoff {
+	return g.betCutoff
+}
+
+// BettingClosesAt returns when the bets close on the match. With a matchday cutoff, it's counted from the first
+// kickoff of the matchday among the matches of the game, already played or not
+func (g *GameImpl) BettingClosesAt(match models.Match) time.Time {
+	return g.betCutoff.ClosesAt(g.firstKickoff(match))
+}
+
+// firstKickoff returns the kickoff the cutoff of the match is counted from
+func (g *GameImpl) firstKickoff(match models.Match) time.Time {
+	kickoff := match.GetDate()
+	if !g.betCutoff.Matchday {
+		return kickoff
+	}
+	matchday, ok := matchdayOf(match)
+	if !ok {
+		return kickoff
+	}
+	for _, matches := range []map[string]models.Match{g.incomingMatches, g.pastMatches} {
+		for _, other := range matches {
+			if otherMatchday, ok := matchdayOf(other); ok && otherMatchday == matchday && other.GetDate().Before(kickoff) {
+				kickoff = other.GetDate()
+			}
+		}
+	}
+	return kickoff
+}
@@ -0,0 +1,71 @@
+package rules
+
+import (
+	"ligain/backend/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBettingClosesAt_AtKickoffByDefault(t *testing.T) {
+	player := newTestPlayer("Player1")
+	match := models.NewSeasonMatch("Team1", "Team2", "2024", "Premier League", testTime, 1)
+	game := NewFreshGame("2024", "Premier League", "Test Game", []models.Player{player}, []models.Match{match}, &ScorerTest{})
+
+	assert.Equal(t, testTime, game.BettingClosesAt(match))
+	assert.NoError(t, game.CheckPlayerBetValidity(player, models.NewBet(match, 1, 0), testTime))
+	assert.Error(t, game.CheckPlayerBetValidity(player, models.NewBet(match, 1, 0), testTime.Add(time.Second)))
+}
+
+func TestBettingClosesAt_MinutesBeforeKickoff(t *testing.T) {
+	player := newTestPlayer("Player1")
+	match := models.NewSeasonMatch("Team1", "Team2", "2024", "Premier League", testTime, 1)
+	game := NewFreshGame("2024", "Premier League", "Test Game", []models.Player{player}, []models.Match{match}, &ScorerTest{})
+	game.SetBetCutoff(models.BetCutoff{Minutes: 60})
+
+	assert.Equal(t, testTime.Add(-time.Hour), game.BettingClosesAt(match))
+	assert.NoError(t, game.CheckPlayerBetValidity(player, models.NewBet(match, 1, 0), testTime.Add(-time.Hour)))
+	assert.Error(t, game.CheckPlayerBetValidity(player, models.NewBet(match, 1, 0), testTime.Add(-30*time.Minute)))
+	assert.Equal(t, testTime.Add(-time.Hour), game.GetIncomingMatches(player)[match.Id()].BettingClosesAt)
+}
+
+func TestBettingClosesAt_StartOfMatchday(t *testing.T) {
+	player := newTestPlayer("Player1")
+	friday := models.NewSeasonMatch("Team1", "Team2", "2024", "Premier League", testTime, 1)
+	sunday := models.NewSeasonMatch("Team3", "Team4", "2024", "Premier League", testTime.Add(48*time.Hour), 1)
+	nextMatchday := models.NewSeasonMatch("Team1", "Team3", "2024", "Premier League", testTime.Add(7*24*time.Hour), 2)
+	game := NewFreshGame("2024", "Premier League", "Test Game", []models.Player{player}, []models.Match{friday, sunday, nextMatchday}, &ScorerTest{})
+	game.SetBetCutoff(models.BetCutoff{Minutes: 5, Matchday: true})
+
+	closesAt := testTime.Add(-5 * time.Minute)
+	assert.Equal(t, closesAt, game.BettingClosesAt(friday))
+	assert.Equal(t, closesAt, game.BettingClosesAt(sunday), "the whole matchday closes with its first match")
+	assert.Equal(t, nextMatchday.GetDate().Add(-5*time.Minute), game.BettingClosesAt(nextMatchday))
+	assert.Error(t, game.CheckPlayerBetValidity(player, models.NewBet(sunday, 1, 0), testTime.Add(time.Hour)))
+
+	// The first match keeps closing the matchday once it's played
+	friday.Finish(1, 0)
+	game.ApplyMatchScores(friday, map[string]int{})
+	assert.Equal(t, closesAt, game.BettingClosesAt(sunday))
+}
+
+func TestBetCutoff_KeptInState(t *testing.T) {
+	match := models.NewSeasonMatch("Team1", "Team2", "2024", "Premier League", testTime, 1)
+	game := NewFreshGame("2024", "Premier League", "Test Game", nil, []models.Match{match}, &ScorerTest{})
+	cutoff := models.BetCutoff{Minutes: 15, Matchday: true}
+	game.SetBetCutoff(cutoff)
+
+	replayed, err := ReplayGame(game.State(), nil, nil, &ScorerTest{})
+
+	require.NoError(t, err)
+	assert.Equal(t, cutoff, replayed.GetBetCutoff())
+}
+
+func TestBetCutoff_Validate(t *testing.T) {
+	assert.NoError(t, models.BetCutoff{}.Validate())
+	assert.NoError(t, models.BetCutoff{Minutes: models.MaxBetCutoffMinutes, Matchday: true}.Validate())
+	assert.Error(t, models.BetCutoff{Minutes: -1}.Validate())
+	assert.Error(t, models.BetCutoff{Minutes: models.MaxBetCutoffMinutes + 1}.Validate())
+}
@@ -3,7 +3,7 @@ package rules
 import (
 	"fmt"
 	"ligain/backend/models"
-	"math"
+	"sync"
 	"time"
 )
 
@@ -24,6 +24,15 @@ type GameImpl struct {
 	gameStatus  models.GameStatus
 	scorer      Scorer
 	scores      map[string]map[string]int
+	// Tiebreakers are applied in order to separate players with the same points
+	tiebreakers []Tiebreaker
+	// betCutoff is how long before kickoff the bets close
+	betCutoff models.BetCutoff
+	// events are the changes made to the game not taken yet by TakeEvents, oldest first.
+	// The cached game is shared by the requests, so they're guarded by eventsMu
+	events    []*models.GameEvent
+	eventsMu  sync.Mutex
+	replaying bool
 }
 
 func NewFreshGame(seasonCode, competitionCode, name string, players []models.Player, incomingMatches []models.Match, scorer Scorer) *GameImpl {
@@ -39,6 +48,7 @@ func NewFreshGame(seasonCode, competitionCode, name string, players []models.Pla
 		bets:            make(map[string]map[string]*models.Bet),
 		scorer:          scorer,
 		scores:          make(map[string]map[string]int),
+		tiebreakers:     DefaultTiebreakers,
 	}
 	for _, match := range incomingMatches {
 		g.incomingMatches[match.Id()] = match
@@ -76,7 +86,7 @@ func (g *GameImpl) CheckPlayerBetValidity(player models.Player, bet *models.Bet,
 	if !containsPlayerByID(g.players, player) {
 		return fmt.Errorf("player %v not found", player)
 	}
-	if datetime.After(bet.Match.GetDate()) {
+	if datetime.After(g.BettingClosesAt(bet.Match)) {
 		return fmt.Errorf("too late to bet on match %v", bet.Match.Id())
 	}
 	return nil
@@ -95,6 +105,7 @@ func (g *GameImpl) CalculateMatchScores(match models.Match) (map[string]int, err
 }
 
 func (g *GameImpl) ApplyMatchScores(match models.Match, scores map[string]int) {
+	g.record(models.NewMatchGameEvent(models.GameEventMatchScored, match, copyPoints(scores)))
 	g.updatePlayersPoints(match, scores)
 	g.finishMatch(match)
 }
@@ -109,10 +120,13 @@ func (g *GameImpl) finishMatch(match models.Match) {
 }
 
 func (g *GameImpl) UpdateMatch(match models.Match) error {
-	_, exists := g.incomingMatches[match.Id()]
+	lastState, exists := g.incomingMatches[match.Id()]
 	if !exists {
 		return fmt.Errorf("match not found")
 	}
+	if !sameMatchState(models.AsSeasonMatch(lastState), models.AsSeasonMatch(match)) {
+		g.record(models.NewMatchGameEvent(models.GameEventMatchUpdated, match, nil))
+	}
 	g.incomingMatches[match.Id()] = match
 	return nil
 }
@@ -126,6 +140,7 @@ func (g *GameImpl) AddPlayerBet(player models.Player, bet *models.Bet) error {
 		g.bets[bet.Match.Id()] = make(map[string]*models.Bet)
 	}
 	g.bets[bet.Match.Id()][player.GetID()] = bet
+	g.record(models.NewBetPlacedEvent(player.GetID(), bet))
 	return nil
 }
 
@@ -137,6 +152,7 @@ func (g *GameImpl) AddPlayer(player models.Player) error {
 
 	// Add the player to the game
 	g.players = append(g.players, player)
+	g.record(models.NewPlayerGameEvent(models.GameEventPlayerJoined, player.GetID()))
 
 	return nil
 }
@@ -145,6 +161,7 @@ func (g *GameImpl) RemovePlayer(player models.Player) error {
 	for i, p := range g.players {
 		if p.GetID() == player.GetID() {
 			g.players = append(g.players[:i], g.players[i+1:]...)
+			g.record(models.NewPlayerGameEvent(models.GameEventPlayerLeft, player.GetID()))
 			return nil
 		}
 	}
@@ -152,10 +169,40 @@ func (g *GameImpl) RemovePlayer(player models.Player) error {
 }
 
 func (g *GameImpl) scoreMatch(match models.Match) map[string]int {
+	playerIDs, betList := g.playerBets(match)
+	scores := g.scorer.Score(match, betList)
+	scoresMap := make(map[string]int)
+	for i, score := range scores {
+		scoresMap[playerIDs[i]] = score
+	}
+	return scoresMap
+}
+
+// scoreMatchWithBreakdown works like scoreMatch, but also returns the breakdown of each score
+// It doesn't check that the match is finished, so it can be used on a live score
+func (g *GameImpl) scoreMatchWithBreakdown(match models.Match) (map[string]int, map[string]models.ScoreBreakdown) {
+	playerIDs, betList := g.playerBets(match)
+	breakdowns := g.scorer.ScoreWithBreakdown(match, betList)
+	scoresMap := make(map[string]int)
+	breakdownsMap := make(map[string]models.ScoreBreakdown)
+	for i, bd := range breakdowns {
+		scoresMap[playerIDs[i]] = bd.Total
+		breakdownsMap[playerIDs[i]] = models.ScoreBreakdown{
+			BaseScore:             bd.BaseScore,
+			RiskMultiplier:        bd.RiskMultiplier,
+			ClairvoyantMultiplier: bd.ClairvoyantMultiplier,
+		}
+	}
+	return scoresMap, breakdownsMap
+}
+
+// playerBets returns the ids of the players and their bet on the match, in the same order
+// A player who didn't bet has a nil bet
+func (g *GameImpl) playerBets(match models.Match) ([]string, []*models.Bet) {
 	bets := g.bets[match.Id()]
 
-	playerIDs := make([]string, 0, len(bets))
-	betList := make([]*models.Bet, 0, len(bets))
+	playerIDs := make([]string, 0, len(g.players))
+	betList := make([]*models.Bet, 0, len(g.players))
 	for _, player := range g.players {
 		playerIDs = append(playerIDs, player.GetID())
 		bet, hasBet := bets[player.GetID()]
@@ -165,12 +212,7 @@ func (g *GameImpl) scoreMatch(match models.Match) map[string]int {
 			betList = append(betList, nil)
 		}
 	}
-	scores := g.scorer.Score(match, betList)
-	scoresMap := make(map[string]int)
-	for i, score := range scores {
-		scoresMap[playerIDs[i]] = score
-	}
-	return scoresMap
+	return playerIDs, betList
 }
 
 func (g *GameImpl) updatePlayersPoints(match models.Match, scores map[string]int) {
@@ -271,18 +313,19 @@ func (g *GameImpl) GetIncomingMatches(player models.Player) map[string]*models.M
 
 		matchResult := models.NewMatchWithBetsWithIDs(match, playerBets)
 		matchResult.PlayerBetStatus = playerBetStatus
+		matchResult.BettingClosesAt = g.BettingClosesAt(match)
 		matches[match.Id()] = matchResult
 	}
 	return matches
 }
 
-// GetIncomingMatchesForTesting returns all incoming matches with all bets (for testing only)
-func (g *GameImpl) GetIncomingMatchesForTesting() map[string]*models.MatchResult {
+// GetIncomingMatchesWithAllBets returns all incoming matches with the bets of every player, even the ones still hidden to the others.
+// It's meant for operators and tests, never for the players
+func (g *GameImpl) GetIncomingMatchesWithAllBets() map[string]*models.MatchResult {
 	matches := make(map[string]*models.MatchResult)
 	for _, match := range g.incomingMatches {
 		playerBets := make(map[string]*models.Bet)
 		if bets, exists := g.bets[match.Id()]; exists {
-			// Show all bets for testing purposes
 			for playerID, bet := range bets {
 				playerBets[playerID] = bet
 			}
@@ -292,6 +335,27 @@ func (g *GameImpl) GetIncomingMatchesForTesting() map[string]*models.MatchResult
 	return matches
 }
 
+// GetProvisionalResults returns the result each in-progress match would have if it ended with its current score
+// All bets are visible since the matches have started. Nothing is applied to the game.
+func (g *GameImpl) GetProvisionalResults() map[string]*models.MatchResult {
+	results := make(map[string]*models.MatchResult)
+	for _, match := range g.incomingMatches {
+		if !match.IsInProgress() {
+			continue
+		}
+		playerBets := make(map[string]*models.Bet)
+		for playerID, bet := range g.bets[match.Id()] {
+			playerBets[playerID] = bet
+		}
+		scores, breakdowns := g.scoreMatchWithBreakdown(match)
+		result := models.NewMatchWithBetsWithIDs(match, playerBets)
+		result.Scores = scores
+		result.ScoreBreakdowns = breakdowns
+		results[match.Id()] = result
+	}
+	return results
+}
+
 func (g *GameImpl) GetPlayersPoints() map[string]int {
 	points := make(map[string]int)
 	for _, matchPoints := range g.playersPoints {
@@ -307,31 +371,50 @@ func (g *GameImpl) IsFinished() bool {
 }
 
 func (g *GameImpl) Finish() {
+	if g.gameStatus != models.GameStatusFinished {
+		g.record(models.NewGameStatusEvent(models.GameEventGameFinished))
+	}
 	g.gameStatus = models.GameStatusFinished
 }
 
+// Reopen puts a finished game back in progress
+func (g *GameImpl) Reopen() {
+	if g.gameStatus == models.GameStatusFinished {
+		g.record(models.NewGameStatusEvent(models.GameEventGameReopened))
+	}
+	g.gameStatus = models.GameStatusScheduled
+}
+
+// RescoreMatch replaces the result of a past match, and scores it again with the current bets and scorer.
+// The new points replace the ones the players had on the match, and are returned
+func (g *GameImpl) RescoreMatch(match models.Match) (map[string]int, error) {
+	_, exists := g.pastMatches[match.Id()]
+	if !exists {
+		return nil, fmt.Errorf("match not found")
+	}
+	if !match.IsFinished() {
+		return nil, fmt.Errorf("match is not finished")
+	}
+	g.pastMatches[match.Id()] = match
+	scores := g.scoreMatch(match)
+	g.playersPoints[match.Id()] = make(map[string]int)
+	g.updatePlayersPoints(match, scores)
+	g.record(models.NewMatchGameEvent(models.GameEventMatchScored, match, copyPoints(scores)))
+	return scores, nil
+}
+
 func (g *GameImpl) GetPlayers() []models.Player {
 	return g.players
 }
 
+// GetWinner returns the players ranked first, in a deterministic order
 func (g *GameImpl) GetWinner() []models.Player {
-	bestScore := math.MinInt32
 	winners := make([]models.Player, 0)
-	totalPlayerPoints := g.GetPlayersPoints()
-
-	// Create a map for faster player lookup
-	playerMap := make(map[string]models.Player)
-	for _, player := range g.players {
-		playerMap[player.GetID()] = player
-	}
-
-	for playerID, points := range totalPlayerPoints {
-		if points > bestScore {
-			bestScore = points
-			winners = []models.Player{playerMap[playerID]}
-		} else if points == bestScore {
-			winners = append(winners, playerMap[playerID])
+	for _, standing := range g.GetStandings() {
+		if standing.Rank != 1 {
+			break
 		}
+		winners = append(winners, standing.Player)
 	}
 	return winners
 }
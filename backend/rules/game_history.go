@@ -0,0 +1,219 @@
+package rules
+
+import (
+	"errors"
+	"fmt"
+	"ligain/backend/models"
+	"sort"
+)
+
+// ErrHistoryRebased is returned when replaying a game past a point where it was changed outside of its events
+var ErrHistoryRebased = errors.New("the history of the game was rebased, it must be rebuilt from a later snapshot")
+
+// record keeps the event of a change of the game until it's taken by TakeEvents. Nothing is kept while replaying
+func (g *GameImpl) record(event *models.GameEvent) {
+	if g.replaying {
+		return
+	}
+	g.eventsMu.Lock()
+	defer g.eventsMu.Unlock()
+	g.events = append(g.events, event)
+}
+
+// TakeEvents implements models.EventSourcedGame
+func (g *GameImpl) TakeEvents() []*models.GameEvent {
+	g.eventsMu.Lock()
+	defer g.eventsMu.Unlock()
+	events := g.events
+	g.events = nil
+	return events
+}
+
+// State implements models.EventSourcedGame. The matches are sorted by id, so that the same state is always saved the same way
+func (g *GameImpl) State() *models.GameState {
+	state := &models.GameState{
+		SeasonYear:      g.seasonCode,
+		CompetitionName: g.competitionCode,
+		Name:            g.name,
+		Status:          g.gameStatus,
+		BetCutoff:       g.betCutoff,
+		Tiebreakers:     g.GetTiebreakers(),
+		PlayerIDs:       make([]string, 0, len(g.players)),
+		IncomingMatches: sortedSeasonMatches(g.incomingMatches),
+		PastMatches:     sortedSeasonMatches(g.pastMatches),
+		Bets:            make(map[string]map[string]models.GameStateBet),
+		Points:          make(map[string]map[string]int),
+	}
+	for _, player := range g.players {
+		state.PlayerIDs = append(state.PlayerIDs, player.GetID())
+	}
+	for matchID, bets := range g.bets {
+		state.Bets[matchID] = make(map[string]models.GameStateBet)
+		for playerID, bet := range bets {
+			state.Bets[matchID][playerID] = models.GameStateBet{
+				PredictedHomeGoals: bet.PredictedHomeGoals,
+				PredictedAwayGoals: bet.PredictedAwayGoals,
+			}
+		}
+	}
+	for matchID, points := range g.playersPoints {
+		state.Points[matchID] = copyPoints(points)
+	}
+	return state
+}
+
+// ReplayGame rebuilds a game from a snapshot of its state and the events recorded after it, oldest first.
+// The players are looked up by id for their names, the ones missing keep an empty name
+func ReplayGame(state *models.GameState, events []*models.GameEvent, players map[string]models.Player, scorer Scorer) (*GameImpl, error) {
+	g := NewFreshGame(state.SeasonYear, state.CompetitionName, state.Name, make([]models.Player, 0, len(state.PlayerIDs)), nil, scorer)
+	g.gameStatus = state.Status
+	g.betCutoff = state.BetCutoff
+	if state.Tiebreakers != nil {
+		tiebreakers, err := ParseTiebreakers(state.Tiebreakers)
+		if err != nil {
+			return nil, err
+		}
+		g.tiebreakers = tiebreakers
+	}
+	for _, playerID := range state.PlayerIDs {
+		g.players = append(g.players, replayedPlayer(playerID, players))
+	}
+	for _, match := range state.IncomingMatches {
+		g.incomingMatches[match.Id()] = copySeasonMatch(match)
+	}
+	for _, match := range state.PastMatches {
+		g.pastMatches[match.Id()] = copySeasonMatch(match)
+	}
+	for matchID, bets := range state.Bets {
+		match, err := g.knownMatch(matchID)
+		if err != nil {
+			return nil, err
+		}
+		g.bets[matchID] = make(map[string]*models.Bet)
+		for playerID, bet := range bets {
+			g.bets[matchID][playerID] = models.NewBet(match, bet.PredictedHomeGoals, bet.PredictedAwayGoals)
+		}
+	}
+	for matchID, points := range state.Points {
+		g.playersPoints[matchID] = copyPoints(points)
+	}
+
+	for _, event := range events {
+		if err := g.applyEvent(event, players); err != nil {
+			return nil, fmt.Errorf("error replaying event %d of type %s: %w", event.Sequence, event.Type, err)
+		}
+	}
+	return g, nil
+}
+
+// applyEvent applies a recorded change to the game, without recording it again
+func (g *GameImpl) applyEvent(event *models.GameEvent, players map[string]models.Player) error {
+	g.replaying = true
+	defer func() { g.replaying = false }()
+
+	payload := event.Payload
+	switch event.Type {
+	case models.GameEventPlayerJoined:
+		player := replayedPlayer(payload.PlayerID, players)
+		if !containsPlayerByID(g.players, player) {
+			g.players = append(g.players, player)
+		}
+	case models.GameEventPlayerLeft:
+		_ = g.RemovePlayer(models.NewSimplePlayer(payload.PlayerID, ""))
+	case models.GameEventBetPlaced:
+		if payload.Match == nil || payload.PredictedHomeGoals == nil || payload.PredictedAwayGoals == nil {
+			return fmt.Errorf("bet without match or prediction")
+		}
+		match, err := g.knownMatch(payload.Match.Id())
+		if err != nil {
+			return err
+		}
+		if _, exists := g.bets[match.Id()]; !exists {
+			g.bets[match.Id()] = make(map[string]*models.Bet)
+		}
+		g.bets[match.Id()][payload.PlayerID] = models.NewBet(match, *payload.PredictedHomeGoals, *payload.PredictedAwayGoals)
+	case models.GameEventMatchUpdated:
+		if payload.Match == nil {
+			return fmt.Errorf("match update without match")
+		}
+		if _, scored := g.pastMatches[payload.Match.Id()]; scored {
+			return fmt.Errorf("match %s is already scored", payload.Match.Id())
+		}
+		g.incomingMatches[payload.Match.Id()] = copySeasonMatch(payload.Match)
+	case models.GameEventMatchScored:
+		if payload.Match == nil {
+			return fmt.Errorf("match scoring without match")
+		}
+		match := copySeasonMatch(payload.Match)
+		g.playersPoints[match.Id()] = copyPoints(payload.Points)
+		if _, incoming := g.incomingMatches[match.Id()]; incoming {
+			g.finishMatch(match)
+		} else {
+			g.pastMatches[match.Id()] = match
+		}
+	case models.GameEventGameFinished:
+		g.Finish()
+	case models.GameEventGameReopened:
+		g.Reopen()
+	case models.GameEventHistoryRebased:
+		return ErrHistoryRebased
+	default:
+		return fmt.Errorf("unknown event type %s", event.Type)
+	}
+	return nil
+}
+
+// knownMatch returns the match of the game with the given id, whether it's incoming or past
+func (g *GameImpl) knownMatch(matchID string) (models.Match, error) {
+	if match, exists := g.incomingMatches[matchID]; exists {
+		return match, nil
+	}
+	if match, exists := g.pastMatches[matchID]; exists {
+		return match, nil
+	}
+	return nil, fmt.Errorf("match %s not found", matchID)
+}
+
+// replayedPlayer returns the player with the given id, or a player without name when it's unknown
+func replayedPlayer(playerID string, players map[string]models.Player) models.Player {
+	if player, exists := players[playerID]; exists {
+		return player
+	}
+	return models.NewSimplePlayer(playerID, "")
+}
+
+// sameMatchState tells whether two states of a match are the same, so that an update changing nothing isn't recorded
+func sameMatchState(a, b *models.SeasonMatch) bool {
+	if !a.Date.Equal(b.Date) {
+		return false
+	}
+	aWithoutDate, bWithoutDate := *a, *b
+	aWithoutDate.Date, bWithoutDate.Date = b.Date, b.Date
+	return aWithoutDate == bWithoutDate
+}
+
+func sortedSeasonMatches(matches map[string]models.Match) []*models.SeasonMatch {
+	ids := make([]string, 0, len(matches))
+	for id := range matches {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	sorted := make([]*models.SeasonMatch, 0, len(ids))
+	for _, id := range ids {
+		sorted = append(sorted, models.AsSeasonMatch(matches[id]))
+	}
+	return sorted
+}
+
+func copySeasonMatch(match *models.SeasonMatch) *models.SeasonMatch {
+	copied := *match
+	return &copied
+}
+
+func copyPoints(points map[string]int) map[string]int {
+	copied := make(map[string]int, len(points))
+	for playerID, score := range points {
+		copied[playerID] = score
+	}
+	return copied
+}
@@ -0,0 +1,128 @@
+package rules
+
+import (
+	"ligain/backend/models"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGameHistory_ReplayRebuildsTheGame(t *testing.T) {
+	player1, player2, player3 := newTestPlayer("Player1"), newTestPlayer("Player2"), newTestPlayer("Player3")
+	match := models.NewSeasonMatchWithKnownOdds("Team1", "Team2", "2024", "Premier League", testTime, 1, 1.5, 2.5, 3.0)
+	otherMatch := models.NewSeasonMatchWithKnownOdds("Team3", "Team4", "2024", "Premier League", testTime, 1, 1.5, 2.5, 3.0)
+	game := NewFreshGame("2024", "Premier League", "Test Game", []models.Player{player1, player2}, []models.Match{match, otherMatch}, &ScorerTest{})
+	initialState := game.State()
+
+	require.NoError(t, game.AddPlayer(player3))
+	require.NoError(t, game.AddPlayerBet(player1, models.NewBet(match, 2, 1)))
+	require.NoError(t, game.AddPlayerBet(player2, models.NewBet(match, 0, 1)))
+	require.NoError(t, game.AddPlayerBet(player1, models.NewBet(otherMatch, 1, 1)))
+	require.NoError(t, game.AddPlayerBet(player2, models.NewBet(otherMatch, 2, 0)))
+	require.NoError(t, game.RemovePlayer(player3))
+
+	live := models.NewSeasonMatchWithKnownOdds("Team1", "Team2", "2024", "Premier League", testTime, 1, 1.5, 2.5, 3.0)
+	live.Start()
+	require.NoError(t, game.UpdateMatch(live))
+	// The same state again isn't recorded
+	require.NoError(t, game.UpdateMatch(models.AsSeasonMatch(live)))
+
+	finished := models.NewFinishedSeasonMatch("Team1", "Team2", 2, 1, "2024", "Premier League", testTime, 1, 1.5, 2.5, 3.0)
+	scores, err := game.CalculateMatchScores(finished)
+	require.NoError(t, err)
+	game.ApplyMatchScores(finished, scores)
+
+	// The result is corrected afterwards
+	corrected := models.NewFinishedSeasonMatch("Team1", "Team2", 0, 1, "2024", "Premier League", testTime, 1, 1.5, 2.5, 3.0)
+	_, err = game.RescoreMatch(corrected)
+	require.NoError(t, err)
+
+	otherFinished := models.NewFinishedSeasonMatch("Team3", "Team4", 1, 1, "2024", "Premier League", testTime, 1, 1.5, 2.5, 3.0)
+	scores, err = game.CalculateMatchScores(otherFinished)
+	require.NoError(t, err)
+	game.ApplyMatchScores(otherFinished, scores)
+
+	events := game.TakeEvents()
+	types := make([]models.GameEventType, 0, len(events))
+	for _, event := range events {
+		types = append(types, event.Type)
+	}
+	assert.Equal(t, []models.GameEventType{
+		models.GameEventPlayerJoined,
+		models.GameEventBetPlaced,
+		models.GameEventBetPlaced,
+		models.GameEventBetPlaced,
+		models.GameEventBetPlaced,
+		models.GameEventPlayerLeft,
+		models.GameEventMatchUpdated,
+		models.GameEventMatchScored,
+		models.GameEventMatchScored,
+		models.GameEventMatchScored,
+		models.GameEventGameFinished,
+	}, types)
+	assert.Empty(t, game.TakeEvents())
+
+	players := map[string]models.Player{player1.GetID(): player1, player2.GetID(): player2}
+	replayed, err := ReplayGame(initialState, events, players, &ScorerTest{})
+	require.NoError(t, err)
+	assert.Equal(t, game.State(), replayed.State())
+	assert.Equal(t, game.GetPlayersPoints(), replayed.GetPlayersPoints())
+	assert.True(t, replayed.IsFinished())
+	assert.Equal(t, "Player1", replayed.GetPlayers()[0].GetName())
+	assert.Empty(t, replayed.TakeEvents(), "replaying must not record the events again")
+
+	// Replaying only the first events gives the game as it was then
+	replayed, err = ReplayGame(initialState, events[:7], players, &ScorerTest{})
+	require.NoError(t, err)
+	liveMatch, err := replayed.GetMatchById(match.Id())
+	require.NoError(t, err)
+	assert.True(t, liveMatch.IsInProgress())
+	assert.Empty(t, replayed.GetPlayersPoints())
+}
+
+func TestGameHistory_ReplayStopsAtRebase(t *testing.T) {
+	game := NewFreshGame("2024", "Premier League", "Test Game", []models.Player{newTestPlayer("Player1")}, nil, &ScorerTest{})
+	rebased := models.NewGameStatusEvent(models.GameEventHistoryRebased)
+
+	_, err := ReplayGame(game.State(), []*models.GameEvent{rebased}, nil, &ScorerTest{})
+	assert.ErrorIs(t, err, ErrHistoryRebased)
+}
+
+func TestGameHistory_EventsAreTakenOnce(t *testing.T) {
+	game := NewFreshGame("2024", "Premier League", "Test Game", []models.Player{newTestPlayer("Player1")}, nil, &ScorerTest{})
+
+	// Requests sharing the cached game record and take events at the same time
+	const recorders = 10
+	const eventsPerRecorder = 100
+	taken := make(chan int)
+	done := make(chan struct{})
+	go func() {
+		count := 0
+		for {
+			select {
+			case <-done:
+				taken <- count + len(game.TakeEvents())
+				return
+			default:
+				count += len(game.TakeEvents())
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < recorders; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < eventsPerRecorder; j++ {
+				game.record(models.NewGameStatusEvent(models.GameEventGameFinished))
+			}
+		}()
+	}
+	wg.Wait()
+	close(done)
+
+	assert.Equal(t, recorders*eventsPerRecorder, <-taken)
+}
@@ -334,6 +334,67 @@ func TestGameFinishAndWinner(t *testing.T) {
 	}
 }
 
+func TestRescoreMatch(t *testing.T) {
+	players := []models.Player{newTestPlayer("Player1"), newTestPlayer("Player2")}
+	match := models.NewSeasonMatch("Team1", "Team2", "2024", "Premier League", testTime, 1)
+	otherMatch := models.NewSeasonMatch("Team3", "Team4", "2024", "Premier League", testTime, 1)
+	scorer := &ScorerTest{}
+
+	game := NewFreshGame("2024", "Premier League", "Test Game", players, []models.Match{match, otherMatch}, scorer)
+	game.AddPlayerBet(players[0], models.NewBet(match, 2, 1))
+	game.AddPlayerBet(players[1], models.NewBet(match, 1, 1))
+
+	// A match still to be played can't be rescored
+	if _, err := game.RescoreMatch(otherMatch); err == nil {
+		t.Error("Expected an error rescoring an incoming match")
+	}
+
+	match.Finish(2, 1)
+	scores, _ := game.CalculateMatchScores(match)
+	game.ApplyMatchScores(match, scores)
+
+	// The result was wrong, the match actually ended in a draw
+	match.Finish(1, 1)
+	scores, err := game.RescoreMatch(match)
+	if err != nil {
+		t.Fatalf("Expected no error rescoring the match, got %v", err)
+	}
+	if scores[players[0].GetID()] != 0 || scores[players[1].GetID()] != 500 {
+		t.Errorf("Expected the draw to be rewarded, got %v", scores)
+	}
+
+	totalPoints := game.GetPlayersPoints()
+	if totalPoints[players[0].GetID()] != 0 {
+		t.Errorf("Expected the points of Player1 to be replaced, got %d", totalPoints[players[0].GetID()])
+	}
+	if totalPoints[players[1].GetID()] != 500 {
+		t.Errorf("Expected 500 total points for Player2, got %d", totalPoints[players[1].GetID()])
+	}
+	pastResult := game.GetPastResults()[match.Id()]
+	if pastResult.Match.GetHomeGoals() != 1 || pastResult.Match.GetAwayGoals() != 1 {
+		t.Errorf("Expected the corrected result to be kept, got %d - %d", pastResult.Match.GetHomeGoals(), pastResult.Match.GetAwayGoals())
+	}
+}
+
+func TestReopen(t *testing.T) {
+	players := []models.Player{newTestPlayer("Player1")}
+	match := models.NewSeasonMatch("Team1", "Team2", "2024", "Premier League", testTime, 1)
+	game := NewFreshGame("2024", "Premier League", "Test Game", players, []models.Match{match}, &ScorerTest{})
+
+	game.Finish()
+	if !game.IsFinished() {
+		t.Fatal("Expected game to be finished")
+	}
+
+	game.Reopen()
+	if game.IsFinished() {
+		t.Error("Expected game to be back in progress")
+	}
+	if game.GetGameStatus() != models.GameStatusScheduled {
+		t.Errorf("Expected game status 'in progress', got %s", game.GetGameStatus())
+	}
+}
+
 func TestUpdateMatch(t *testing.T) {
 	players := []models.Player{newTestPlayer("Player1")}
 	match := models.NewSeasonMatch("Team1", "Team2", "2024", "Premier League", testTime, 1)
@@ -502,7 +563,7 @@ func TestRemovePlayer_WithBets(t *testing.T) {
 	}
 
 	// Verify the bet is still in the game (bets are not automatically removed)
-	incomingMatches := game.GetIncomingMatchesForTesting()
+	incomingMatches := game.GetIncomingMatchesWithAllBets()
 	matchResult := incomingMatches[match.Id()]
 	if matchResult == nil {
 		t.Fatal("Expected match result not to be nil")
@@ -728,3 +789,57 @@ func TestGetIncomingMatchesInProgressShowsAllBets(t *testing.T) {
 		t.Errorf("player3 has no bet, should not appear in Bets")
 	}
 }
+
+func TestGetProvisionalResults(t *testing.T) {
+	player1 := newTestPlayer("Player1")
+	player2 := newTestPlayer("Player2")
+	players := []models.Player{player1, player2}
+
+	liveMatch := models.NewSeasonMatch("Team1", "Team2", "2024", "Ligue 1", testTime.Add(-1*time.Hour), 1)
+	scheduledMatch := models.NewSeasonMatch("Team3", "Team4", "2024", "Ligue 1", testTime.Add(1*time.Hour), 1)
+	game := NewFreshGame("2024", "Ligue 1", "Test", players, []models.Match{liveMatch, scheduledMatch}, &ScorerTest{})
+
+	game.AddPlayerBet(player1, models.NewBet(liveMatch, 1, 0))
+	game.AddPlayerBet(player2, models.NewBet(liveMatch, 0, 0))
+
+	if len(game.GetProvisionalResults()) != 0 {
+		t.Fatalf("expected no provisional results before kickoff")
+	}
+
+	// Goal for the home team
+	liveMatch.Start()
+	liveMatch.HomeGoals = 1
+	game.UpdateMatch(liveMatch)
+
+	results := game.GetProvisionalResults()
+	if len(results) != 1 {
+		t.Fatalf("expected 1 provisional result, got %d", len(results))
+	}
+	result := results[liveMatch.Id()]
+	if result.Scores[player1.GetID()] != 500 {
+		t.Errorf("expected 500 provisional points for player1, got %d", result.Scores[player1.GetID()])
+	}
+	if result.Scores[player2.GetID()] != 0 {
+		t.Errorf("expected 0 provisional points for player2, got %d", result.Scores[player2.GetID()])
+	}
+	if result.ScoreBreakdowns[player1.GetID()].BaseScore != 500 {
+		t.Errorf("expected breakdown base score 500, got %d", result.ScoreBreakdowns[player1.GetID()].BaseScore)
+	}
+
+	// Equalizer
+	liveMatch.AwayGoals = 1
+	game.UpdateMatch(liveMatch)
+
+	result = game.GetProvisionalResults()[liveMatch.Id()]
+	if result.Scores[player1.GetID()] != 0 || result.Scores[player2.GetID()] != 500 {
+		t.Errorf("expected provisional scores to follow the equalizer, got %v", result.Scores)
+	}
+
+	// Nothing was applied to the game
+	if len(game.GetPastResults()) != 0 {
+		t.Errorf("expected no past results, got %d", len(game.GetPastResults()))
+	}
+	if len(game.GetPlayersPoints()) != 0 {
+		t.Errorf("expected no confirmed points, got %v", game.GetPlayersPoints())
+	}
+}
@@ -0,0 +1,63 @@
+package rules
+
+import (
+	"ligain/backend/models"
+	"math"
+)
+
+// ratingK is the maximum rating a player can win or lose on a single match
+const ratingK = 32.0
+
+// RateMatch returns how much the rating of each player changes with the result of a match, keyed by player id
+// Every pair of players is compared like an Elo duel, won by the player who scored more points on the match.
+// The changes are divided by the number of opponents so that big games don't move ratings more than small ones,
+// and by the risk multiplier of the match so that lucky upsets count less than usual results.
+// Players without a rating are considered at models.InitialRating.
+func RateMatch(ratings map[string]float64, result *models.MatchResult) map[string]float64 {
+	deltas := make(map[string]float64)
+	if len(result.Scores) < 2 {
+		return deltas
+	}
+
+	k := ratingK / float64(len(result.Scores)-1) / matchRiskMultiplier(result)
+	for playerID, score := range result.Scores {
+		rating := ratingOrInitial(ratings, playerID)
+		delta := 0.0
+		for opponentID, opponentScore := range result.Scores {
+			if opponentID == playerID {
+				continue
+			}
+			expected := 1 / (1 + math.Pow(10, (ratingOrInitial(ratings, opponentID)-rating)/400))
+			delta += k * (duelOutcome(score, opponentScore) - expected)
+		}
+		deltas[playerID] = delta
+	}
+	return deltas
+}
+
+// matchRiskMultiplier returns the risk multiplier applied to the correct predictions of the match
+// It's the same for every player who predicted the right outcome, and 1 if nobody did
+func matchRiskMultiplier(result *models.MatchResult) float64 {
+	multiplier := 1.0
+	for _, breakdown := range result.ScoreBreakdowns {
+		multiplier = math.Max(multiplier, breakdown.RiskMultiplier)
+	}
+	return multiplier
+}
+
+func duelOutcome(score, opponentScore int) float64 {
+	if score > opponentScore {
+		return 1
+	}
+	if score < opponentScore {
+		return 0
+	}
+	return 0.5
+}
+
+func ratingOrInitial(ratings map[string]float64, playerID string) float64 {
+	if rating, exists := ratings[playerID]; exists {
+		return rating
+	}
+	return models.InitialRating
+}
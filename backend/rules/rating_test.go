@@ -0,0 +1,80 @@
+package rules
+
+import (
+	"ligain/backend/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateMatch_WinnerGainsWhatLoserLoses(t *testing.T) {
+	result := &models.MatchResult{
+		Scores: map[string]int{"Alice": 500, "Bob": 0},
+	}
+
+	deltas := RateMatch(map[string]float64{}, result)
+
+	assert.InDelta(t, 16, deltas["Alice"], 0.001)
+	assert.InDelta(t, -16, deltas["Bob"], 0.001)
+}
+
+func TestRateMatch_TieBetweenEqualPlayersChangesNothing(t *testing.T) {
+	result := &models.MatchResult{
+		Scores: map[string]int{"Alice": 300, "Bob": 300},
+	}
+
+	deltas := RateMatch(map[string]float64{"Alice": 1600, "Bob": 1600}, result)
+
+	assert.InDelta(t, 0, deltas["Alice"], 0.001)
+	assert.InDelta(t, 0, deltas["Bob"], 0.001)
+}
+
+func TestRateMatch_BeatingAStrongerPlayerEarnsMore(t *testing.T) {
+	result := &models.MatchResult{
+		Scores: map[string]int{"Alice": 500, "Bob": 0},
+	}
+
+	againstEqual := RateMatch(map[string]float64{"Alice": 1500, "Bob": 1500}, result)
+	againstStronger := RateMatch(map[string]float64{"Alice": 1500, "Bob": 1800}, result)
+
+	assert.Greater(t, againstStronger["Alice"], againstEqual["Alice"])
+}
+
+func TestRateMatch_NormalizesForGameSize(t *testing.T) {
+	twoPlayers := &models.MatchResult{
+		Scores: map[string]int{"Alice": 500, "Bob": 0},
+	}
+	fivePlayers := &models.MatchResult{
+		Scores: map[string]int{"Alice": 500, "Bob": 0, "Carol": 0, "Dave": 0, "Eve": 0},
+	}
+
+	// Winning against everyone moves the rating by the same amount whatever the size of the game
+	assert.InDelta(t, RateMatch(nil, twoPlayers)["Alice"], RateMatch(nil, fivePlayers)["Alice"], 0.001)
+}
+
+func TestRateMatch_NormalizesForRiskMultiplier(t *testing.T) {
+	usual := &models.MatchResult{
+		Scores: map[string]int{"Alice": 500, "Bob": 0},
+		ScoreBreakdowns: map[string]models.ScoreBreakdown{
+			"Alice": {BaseScore: 500, RiskMultiplier: 1.0, ClairvoyantMultiplier: 1.0},
+			"Bob":   {BaseScore: 0, RiskMultiplier: 1.0, ClairvoyantMultiplier: 1.0},
+		},
+	}
+	upset := &models.MatchResult{
+		Scores: map[string]int{"Alice": 1000, "Bob": 0},
+		ScoreBreakdowns: map[string]models.ScoreBreakdown{
+			"Alice": {BaseScore: 500, RiskMultiplier: 2.0, ClairvoyantMultiplier: 1.0},
+			"Bob":   {BaseScore: 0, RiskMultiplier: 1.0, ClairvoyantMultiplier: 1.0},
+		},
+	}
+
+	assert.InDelta(t, RateMatch(nil, usual)["Alice"]/2, RateMatch(nil, upset)["Alice"], 0.001)
+}
+
+func TestRateMatch_SinglePlayerIsNotRated(t *testing.T) {
+	result := &models.MatchResult{
+		Scores: map[string]int{"Alice": 500},
+	}
+
+	assert.Empty(t, RateMatch(nil, result))
+}
@@ -0,0 +1,190 @@
+package rules
+
+import (
+	"fmt"
+	"ligain/backend/models"
+	"sort"
+)
+
+// Tiebreaker is a criterion used to separate players who have the same number of points
+type Tiebreaker string
+
+const (
+	// TiebreakerExactScores favors the player with the most exact score predictions
+	TiebreakerExactScores Tiebreaker = "exact_scores"
+	// TiebreakerMissedBets favors the player who missed the fewest bets
+	TiebreakerMissedBets Tiebreaker = "missed_bets"
+	// TiebreakerHeadToHead favors the player who outscored the other tied players on the most matches
+	TiebreakerHeadToHead Tiebreaker = "head_to_head"
+)
+
+// DefaultTiebreakers are applied in order when a game doesn't configure its own
+var DefaultTiebreakers = []Tiebreaker{TiebreakerExactScores, TiebreakerMissedBets, TiebreakerHeadToHead}
+
+// ParseTiebreakers converts tiebreaker names into tiebreakers, rejecting unknown or duplicated ones
+func ParseTiebreakers(names []string) ([]Tiebreaker, error) {
+	tiebreakers := make([]Tiebreaker, 0, len(names))
+	seen := make(map[Tiebreaker]bool)
+	for _, name := range names {
+		tiebreaker := Tiebreaker(name)
+		switch tiebreaker {
+		case TiebreakerExactScores, TiebreakerMissedBets, TiebreakerHeadToHead:
+		default:
+			return nil, fmt.Errorf("unknown tiebreaker %q", name)
+		}
+		if seen[tiebreaker] {
+			return nil, fmt.Errorf("tiebreaker %q is used twice", name)
+		}
+		seen[tiebreaker] = true
+		tiebreakers = append(tiebreakers, tiebreaker)
+	}
+	return tiebreakers, nil
+}
+
+// SetTiebreakers changes the tiebreakers of the game, applied in the given order
+func (g *GameImpl) SetTiebreakers(tiebreakers []Tiebreaker) {
+	g.tiebreakers = tiebreakers
+}
+
+// GetTiebreakers returns the names of the tiebreakers of the game, in the order they're applied
+func (g *GameImpl) GetTiebreakers() []string {
+	names := make([]string, 0, len(g.tiebreakers))
+	for _, tiebreaker := range g.tiebreakers {
+		names = append(names, string(tiebreaker))
+	}
+	return names
+}
+
+// GetStandings ranks the players on their points, breaking ties with the game's tiebreakers
+func (g *GameImpl) GetStandings() []models.Standing {
+	return g.RankPlayers(g.GetPlayersPoints())
+}
+
+// RankPlayers ranks the players of the game on the given points, breaking ties with the game's tiebreakers
+// Players still tied after every tiebreaker share the same rank, and are ordered by id so the result is deterministic
+func (g *GameImpl) RankPlayers(points map[string]int) []models.Standing {
+	standings := make([]models.Standing, 0, len(g.players))
+	for _, player := range g.players {
+		standings = append(standings, models.Standing{
+			Player:      player,
+			Points:      points[player.GetID()],
+			ExactScores: g.countExactScores(player.GetID()),
+			MissedBets:  g.countMissedBets(player.GetID()),
+		})
+	}
+	sort.SliceStable(standings, func(i, j int) bool {
+		if standings[i].Points != standings[j].Points {
+			return standings[i].Points > standings[j].Points
+		}
+		return standings[i].Player.GetID() < standings[j].Player.GetID()
+	})
+
+	ranked := make([]models.Standing, 0, len(standings))
+	for _, group := range groupStandings(standings, func(s models.Standing) int { return s.Points }) {
+		for _, tied := range g.breakTies(group, g.tiebreakers) {
+			rank := len(ranked) + 1
+			for _, standing := range tied {
+				standing.Rank = rank
+				ranked = append(ranked, standing)
+			}
+		}
+	}
+	return ranked
+}
+
+// breakTies splits a group of tied players into ordered subgroups, applying the tiebreakers one after the other
+func (g *GameImpl) breakTies(group []models.Standing, tiebreakers []Tiebreaker) [][]models.Standing {
+	if len(group) <= 1 || len(tiebreakers) == 0 {
+		return [][]models.Standing{group}
+	}
+	values := g.tiebreakerValues(group, tiebreakers[0])
+	sort.SliceStable(group, func(i, j int) bool {
+		return values[group[i].Player.GetID()] > values[group[j].Player.GetID()]
+	})
+
+	result := make([][]models.Standing, 0)
+	for _, subgroup := range groupStandings(group, func(s models.Standing) int { return values[s.Player.GetID()] }) {
+		result = append(result, g.breakTies(subgroup, tiebreakers[1:])...)
+	}
+	return result
+}
+
+// tiebreakerValues returns the value of the tiebreaker for each player of the group, keyed by player id
+// A higher value is always better
+func (g *GameImpl) tiebreakerValues(group []models.Standing, tiebreaker Tiebreaker) map[string]int {
+	values := make(map[string]int)
+	switch tiebreaker {
+	case TiebreakerExactScores:
+		for _, standing := range group {
+			values[standing.Player.GetID()] = standing.ExactScores
+		}
+	case TiebreakerMissedBets:
+		for _, standing := range group {
+			values[standing.Player.GetID()] = -standing.MissedBets
+		}
+	case TiebreakerHeadToHead:
+		values = g.countHeadToHeadWins(group)
+	}
+	return values
+}
+
+// countHeadToHeadWins counts, for each player of the group, the past matches on which they scored strictly
+// more points than every other player of the group
+func (g *GameImpl) countHeadToHeadWins(group []models.Standing) map[string]int {
+	wins := make(map[string]int)
+	for matchID := range g.pastMatches {
+		matchPoints := g.playersPoints[matchID]
+		bestPlayerID := ""
+		bestPoints := 0
+		isTied := false
+		for i, standing := range group {
+			playerPoints := matchPoints[standing.Player.GetID()]
+			if i == 0 || playerPoints > bestPoints {
+				bestPlayerID = standing.Player.GetID()
+				bestPoints = playerPoints
+				isTied = false
+			} else if playerPoints == bestPoints {
+				isTied = true
+			}
+		}
+		if !isTied && bestPlayerID != "" {
+			wins[bestPlayerID]++
+		}
+	}
+	return wins
+}
+
+// countExactScores counts the past matches on which the player predicted the exact score
+func (g *GameImpl) countExactScores(playerID string) int {
+	count := 0
+	for matchID, match := range g.pastMatches {
+		bet, hasBet := g.bets[matchID][playerID]
+		if hasBet && bet != nil && isBetPerfectAgainstMatch(bet, match) {
+			count++
+		}
+	}
+	return count
+}
+
+// countMissedBets counts the past matches on which the player didn't bet
+func (g *GameImpl) countMissedBets(playerID string) int {
+	count := 0
+	for matchID := range g.pastMatches {
+		if bet, hasBet := g.bets[matchID][playerID]; !hasBet || bet == nil {
+			count++
+		}
+	}
+	return count
+}
+
+// groupStandings splits sorted standings into consecutive groups sharing the same key
+func groupStandings(standings []models.Standing, key func(models.Standing) int) [][]models.Standing {
+	groups := make([][]models.Standing, 0)
+	for i, standing := range standings {
+		if i == 0 || key(standing) != key(standings[i-1]) {
+			groups = append(groups, []models.Standing{})
+		}
+		groups[len(groups)-1] = append(groups[len(groups)-1], standing)
+	}
+	return groups
+}
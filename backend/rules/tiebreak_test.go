@@ -0,0 +1,187 @@
+package rules
+
+import (
+	"ligain/backend/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newFinishedMatch returns a finished match, scheduled after testTime
+func newFinishedMatch(homeTeam, awayTeam string, matchday, homeGoals, awayGoals int) *models.SeasonMatch {
+	match := models.NewSeasonMatch(homeTeam, awayTeam, "2024", "Premier League", testTime, matchday)
+	match.Finish(homeGoals, awayGoals)
+	return match
+}
+
+func standingIDs(standings []models.Standing) []string {
+	ids := make([]string, 0, len(standings))
+	for _, standing := range standings {
+		ids = append(ids, standing.Player.GetID())
+	}
+	return ids
+}
+
+func standingRanks(standings []models.Standing) []int {
+	ranks := make([]int, 0, len(standings))
+	for _, standing := range standings {
+		ranks = append(ranks, standing.Rank)
+	}
+	return ranks
+}
+
+func TestRankPlayers_ExactScoresBreakTies(t *testing.T) {
+	players := []models.Player{newTestPlayer("Alice"), newTestPlayer("Bob"), newTestPlayer("Carol")}
+	match1 := newFinishedMatch("Team1", "Team2", 1, 2, 1)
+	match2 := newFinishedMatch("Team3", "Team4", 2, 0, 0)
+	bets := map[string]map[string]*models.Bet{
+		match1.Id(): {"Alice": models.NewBet(match1, 1, 0), "Bob": models.NewBet(match1, 2, 1), "Carol": models.NewBet(match1, 0, 1)},
+		match2.Id(): {"Alice": models.NewBet(match2, 1, 1), "Bob": models.NewBet(match2, 2, 0), "Carol": models.NewBet(match2, 0, 0)},
+	}
+	scores := map[string]map[string]int{
+		match1.Id(): {"Alice": 500, "Bob": 500, "Carol": 0},
+		match2.Id(): {"Alice": 500, "Bob": 0, "Carol": 500},
+	}
+	game := NewStartedGame("2024", "Premier League", "Test Game", players, nil, []models.Match{match1, match2}, &ScorerTest{}, bets, scores)
+
+	standings := game.GetStandings()
+
+	assert.Equal(t, []string{"Alice", "Bob", "Carol"}, standingIDs(standings))
+	assert.Equal(t, []int{1, 2, 2}, standingRanks(standings))
+	assert.Equal(t, 1000, standings[0].Points)
+	assert.Equal(t, 1, standings[1].ExactScores)
+	assert.Equal(t, 1, standings[2].ExactScores)
+	assert.Equal(t, []models.Player{players[0]}, game.GetWinner())
+}
+
+func TestRankPlayers_MissedBetsBreakTies(t *testing.T) {
+	players := []models.Player{newTestPlayer("Alice"), newTestPlayer("Bob")}
+	match1 := newFinishedMatch("Team1", "Team2", 1, 2, 1)
+	match2 := newFinishedMatch("Team3", "Team4", 2, 0, 0)
+	bets := map[string]map[string]*models.Bet{
+		match1.Id(): {"Alice": models.NewBet(match1, 1, 0), "Bob": models.NewBet(match1, 3, 0)},
+		match2.Id(): {"Bob": models.NewBet(match2, 2, 0)},
+	}
+	scores := map[string]map[string]int{
+		match1.Id(): {"Alice": 500, "Bob": 500},
+		match2.Id(): {"Alice": 0, "Bob": 0},
+	}
+	game := NewStartedGame("2024", "Premier League", "Test Game", players, nil, []models.Match{match1, match2}, &ScorerTest{}, bets, scores)
+
+	standings := game.GetStandings()
+
+	assert.Equal(t, []string{"Bob", "Alice"}, standingIDs(standings))
+	assert.Equal(t, []int{1, 2}, standingRanks(standings))
+	assert.Equal(t, 1, standings[1].MissedBets)
+}
+
+func TestRankPlayers_HeadToHeadBreakTies(t *testing.T) {
+	players := []models.Player{newTestPlayer("Alice"), newTestPlayer("Bob")}
+	match1 := newFinishedMatch("Team1", "Team2", 1, 2, 1)
+	match2 := newFinishedMatch("Team3", "Team4", 2, 0, 0)
+	match3 := newFinishedMatch("Team5", "Team6", 3, 1, 3)
+	bets := map[string]map[string]*models.Bet{
+		match1.Id(): {"Alice": models.NewBet(match1, 1, 0), "Bob": models.NewBet(match1, 3, 0)},
+		match2.Id(): {"Alice": models.NewBet(match2, 1, 1), "Bob": models.NewBet(match2, 2, 0)},
+		match3.Id(): {"Alice": models.NewBet(match3, 1, 0), "Bob": models.NewBet(match3, 0, 1)},
+	}
+	scores := map[string]map[string]int{
+		match1.Id(): {"Alice": 300, "Bob": 400},
+		match2.Id(): {"Alice": 450, "Bob": 0},
+		match3.Id(): {"Alice": 0, "Bob": 350},
+	}
+	game := NewStartedGame("2024", "Premier League", "Test Game", players, nil, []models.Match{match1, match2, match3}, &ScorerTest{}, bets, scores)
+
+	standings := game.GetStandings()
+
+	assert.Equal(t, []string{"Bob", "Alice"}, standingIDs(standings))
+	assert.Equal(t, []int{1, 2}, standingRanks(standings))
+}
+
+func TestRankPlayers_UnbreakableTieSharesRank(t *testing.T) {
+	players := []models.Player{newTestPlayer("Carol"), newTestPlayer("Bob"), newTestPlayer("Alice")}
+	match := newFinishedMatch("Team1", "Team2", 1, 2, 1)
+	bets := map[string]map[string]*models.Bet{
+		match.Id(): {"Alice": models.NewBet(match, 2, 1), "Bob": models.NewBet(match, 2, 1), "Carol": models.NewBet(match, 0, 1)},
+	}
+	scores := map[string]map[string]int{
+		match.Id(): {"Alice": 500, "Bob": 500, "Carol": 0},
+	}
+	game := NewStartedGame("2024", "Premier League", "Test Game", players, nil, []models.Match{match}, &ScorerTest{}, bets, scores)
+
+	for i := 0; i < 10; i++ {
+		standings := game.GetStandings()
+		assert.Equal(t, []string{"Alice", "Bob", "Carol"}, standingIDs(standings))
+		assert.Equal(t, []int{1, 1, 3}, standingRanks(standings))
+
+		winners := game.GetWinner()
+		require.Len(t, winners, 2)
+		assert.Equal(t, "Alice", winners[0].GetID())
+		assert.Equal(t, "Bob", winners[1].GetID())
+	}
+}
+
+func TestRankPlayers_CustomTiebreakers(t *testing.T) {
+	players := []models.Player{newTestPlayer("Alice"), newTestPlayer("Bob")}
+	match1 := newFinishedMatch("Team1", "Team2", 1, 2, 1)
+	match2 := newFinishedMatch("Team3", "Team4", 2, 0, 0)
+	// Alice has an exact score but missed a bet, Bob bet on every match without an exact score
+	bets := map[string]map[string]*models.Bet{
+		match1.Id(): {"Alice": models.NewBet(match1, 2, 1), "Bob": models.NewBet(match1, 3, 0)},
+		match2.Id(): {"Bob": models.NewBet(match2, 2, 0)},
+	}
+	scores := map[string]map[string]int{
+		match1.Id(): {"Alice": 500, "Bob": 500},
+		match2.Id(): {"Alice": 0, "Bob": 0},
+	}
+	game := NewStartedGame("2024", "Premier League", "Test Game", players, nil, []models.Match{match1, match2}, &ScorerTest{}, bets, scores)
+	assert.Equal(t, []string{"Alice", "Bob"}, standingIDs(game.GetStandings()))
+
+	game.(*GameImpl).SetTiebreakers([]Tiebreaker{TiebreakerMissedBets, TiebreakerExactScores})
+	assert.Equal(t, []string{"Bob", "Alice"}, standingIDs(game.GetStandings()))
+
+	game.(*GameImpl).SetTiebreakers(nil)
+	assert.Equal(t, []int{1, 1}, standingRanks(game.GetStandings()))
+}
+
+func TestRankPlayers_UsesGivenPoints(t *testing.T) {
+	players := []models.Player{newTestPlayer("Alice"), newTestPlayer("Bob")}
+	game := NewFreshGame("2024", "Premier League", "Test Game", players, nil, &ScorerTest{})
+
+	standings := game.RankPlayers(map[string]int{"Bob": 300})
+
+	assert.Equal(t, []string{"Bob", "Alice"}, standingIDs(standings))
+	assert.Equal(t, []int{1, 2}, standingRanks(standings))
+	assert.Equal(t, 0, standings[1].Points)
+}
+
+func TestParseTiebreakers(t *testing.T) {
+	tiebreakers, err := ParseTiebreakers([]string{"head_to_head", "exact_scores"})
+	require.NoError(t, err)
+	assert.Equal(t, []Tiebreaker{TiebreakerHeadToHead, TiebreakerExactScores}, tiebreakers)
+
+	_, err = ParseTiebreakers([]string{"coin_flip"})
+	assert.Error(t, err)
+
+	_, err = ParseTiebreakers([]string{"missed_bets", "missed_bets"})
+	assert.Error(t, err)
+}
+
+func TestTiebreakers_KeptInState(t *testing.T) {
+	game := NewFreshGame("2024", "Premier League", "Test Game", nil, nil, &ScorerTest{})
+	assert.Equal(t, []string{"exact_scores", "missed_bets", "head_to_head"}, game.GetTiebreakers())
+	game.SetTiebreakers([]Tiebreaker{TiebreakerHeadToHead, TiebreakerMissedBets})
+
+	replayed, err := ReplayGame(game.State(), nil, nil, &ScorerTest{})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"head_to_head", "missed_bets"}, replayed.GetTiebreakers())
+
+	// The snapshots taken before the tiebreakers were kept replay with the default ones
+	state := game.State()
+	state.Tiebreakers = nil
+	replayed, err = ReplayGame(state, nil, nil, &ScorerTest{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"exact_scores", "missed_bets", "head_to_head"}, replayed.GetTiebreakers())
+}
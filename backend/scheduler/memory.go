@@ -0,0 +1,103 @@
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// maxRunsPerJob bounds the history an InMemoryStore keeps, the oldest runs being dropped first
+const maxRunsPerJob = 100
+
+// InMemoryStore implements Store in memory, so the runs are only shared by the schedulers of the same instance
+type InMemoryStore struct {
+	mu     sync.Mutex
+	locked map[string]bool
+	runs   map[string][]Run // job -> runs, the oldest first
+	nextID int64
+}
+
+// NewInMemoryStore creates a new in-memory store
+func NewInMemoryStore() *InMemoryStore {
+	return &InMemoryStore{
+		locked: make(map[string]bool),
+		runs:   make(map[string][]Run),
+	}
+}
+
+func (s *InMemoryStore) Lock(ctx context.Context, job string) (func(), bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.locked[job] {
+		return nil, false, nil
+	}
+	s.locked[job] = true
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		delete(s.locked, job)
+	}, true, nil
+}
+
+func (s *InMemoryStore) StartRun(ctx context.Context, run *Run) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	runs := s.runs[run.Job]
+	for _, previous := range runs {
+		if previous.ScheduledAt.Equal(run.ScheduledAt) {
+			return false, nil
+		}
+	}
+
+	s.nextID++
+	run.ID = s.nextID
+	runs = append(runs, *run)
+	if len(runs) > maxRunsPerJob {
+		runs = runs[len(runs)-maxRunsPerJob:]
+	}
+	s.runs[run.Job] = runs
+	return true, nil
+}
+
+func (s *InMemoryStore) FinishRun(ctx context.Context, run *Run) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	runs := s.runs[run.Job]
+	for i := range runs {
+		if runs[i].ID == run.ID {
+			runs[i] = *run
+		}
+	}
+	return nil
+}
+
+func (s *InMemoryStore) ListRuns(ctx context.Context, job string, limit int) ([]Run, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	runs := s.runs[job]
+	latest := make([]Run, 0, len(runs))
+	for i := len(runs) - 1; i >= 0 && (limit <= 0 || len(latest) < limit); i-- {
+		latest = append(latest, runs[i])
+	}
+	return latest, nil
+}
+
+func (s *InMemoryStore) PruneRuns(ctx context.Context, before time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for job, runs := range s.runs {
+		kept := make([]Run, 0, len(runs))
+		for _, run := range runs {
+			if !run.StartedAt.Before(before) {
+				kept = append(kept, run)
+			}
+		}
+		s.runs[job] = kept
+	}
+	return nil
+}
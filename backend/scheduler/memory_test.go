@@ -0,0 +1,42 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInMemoryStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewInMemoryStore()
+
+	for i := 0; i < maxRunsPerJob+5; i++ {
+		scheduledAt := frozenTime.Add(time.Duration(i) * time.Hour)
+		run := &Run{Job: "cleanup", ScheduledAt: scheduledAt, StartedAt: scheduledAt, Status: RunStatusRunning}
+		started, err := store.StartRun(ctx, run)
+		require.NoError(t, err)
+		require.True(t, started)
+
+		run.Status = RunStatusSucceeded
+		require.NoError(t, store.FinishRun(ctx, run))
+	}
+
+	// The oldest runs are dropped past the limit, the latest are listed first
+	runs, err := store.ListRuns(ctx, "cleanup", 0)
+	require.NoError(t, err)
+	require.Len(t, runs, maxRunsPerJob)
+	assert.Equal(t, frozenTime.Add(time.Duration(maxRunsPerJob+4)*time.Hour), runs[0].ScheduledAt)
+	assert.Equal(t, RunStatusSucceeded, runs[0].Status)
+
+	runs, err = store.ListRuns(ctx, "cleanup", 3)
+	require.NoError(t, err)
+	assert.Len(t, runs, 3)
+
+	require.NoError(t, store.PruneRuns(ctx, frozenTime.Add(time.Duration(maxRunsPerJob+3)*time.Hour)))
+	runs, err = store.ListRuns(ctx, "cleanup", 0)
+	require.NoError(t, err)
+	assert.Len(t, runs, 2)
+}
@@ -0,0 +1,173 @@
+package scheduler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule tells when a job runs
+type Schedule interface {
+	// Next returns the first time the job runs strictly after the given time
+	Next(after time.Time) time.Time
+}
+
+// ParseSchedule parses a cron spec of 5 fields: minute, hour, day of month, month and day of week, in UTC.
+// Fields accept *, values, ranges like 1-5, lists like 1,15 and steps like */10. The descriptors @hourly,
+// @daily, @weekly and @monthly, and intervals like "@every 10m" are accepted too
+func ParseSchedule(spec string) (Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	if interval, found := strings.CutPrefix(spec, "@every "); found {
+		duration, err := time.ParseDuration(strings.TrimSpace(interval))
+		if err != nil {
+			return nil, fmt.Errorf("invalid interval in %q: %v", spec, err)
+		}
+		if duration < time.Second {
+			return nil, fmt.Errorf("invalid interval in %q: must be at least 1s", spec)
+		}
+		return intervalSchedule{interval: duration}, nil
+	}
+
+	switch spec {
+	case "@hourly":
+		spec = "0 * * * *"
+	case "@daily":
+		spec = "0 0 * * *"
+	case "@weekly":
+		spec = "0 0 * * 0"
+	case "@monthly":
+		spec = "0 0 1 * *"
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid schedule %q: expected 5 fields, got %d", spec, len(fields))
+	}
+
+	schedule := cronSchedule{}
+	var err error
+	if schedule.minutes, err = parseField(fields[0], 0, 59); err != nil {
+		return nil, fmt.Errorf("invalid minute in %q: %v", spec, err)
+	}
+	if schedule.hours, err = parseField(fields[1], 0, 23); err != nil {
+		return nil, fmt.Errorf("invalid hour in %q: %v", spec, err)
+	}
+	if schedule.days, err = parseField(fields[2], 1, 31); err != nil {
+		return nil, fmt.Errorf("invalid day of month in %q: %v", spec, err)
+	}
+	if schedule.months, err = parseField(fields[3], 1, 12); err != nil {
+		return nil, fmt.Errorf("invalid month in %q: %v", spec, err)
+	}
+	// Sunday is both 0 and 7, like in cron
+	if schedule.weekdays, err = parseField(fields[4], 0, 7); err != nil {
+		return nil, fmt.Errorf("invalid day of week in %q: %v", spec, err)
+	}
+	if schedule.weekdays&(1<<7) != 0 {
+		schedule.weekdays |= 1
+	}
+	schedule.anyDay = strings.HasPrefix(fields[2], "*")
+	schedule.anyWeekday = strings.HasPrefix(fields[4], "*")
+	return schedule, nil
+}
+
+// parseField returns the values a field matches, as a bit set
+func parseField(field string, min, max int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		valueRange, stepText, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepText); err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step %q", stepText)
+			}
+		}
+
+		low, high := min, max
+		if valueRange != "*" {
+			lowText, highText, isRange := strings.Cut(valueRange, "-")
+			var err error
+			if low, err = strconv.Atoi(lowText); err != nil {
+				return 0, fmt.Errorf("invalid value %q", lowText)
+			}
+			high = low
+			if isRange {
+				if high, err = strconv.Atoi(highText); err != nil {
+					return 0, fmt.Errorf("invalid value %q", highText)
+				}
+			} else if hasStep {
+				// 5/15 means from 5 to the end, every 15
+				high = max
+			}
+		}
+		if low < min || high > max || low > high {
+			return 0, fmt.Errorf("%q is out of the range %d-%d", part, min, max)
+		}
+
+		for value := low; value <= high; value += step {
+			bits |= 1 << uint(value)
+		}
+	}
+	return bits, nil
+}
+
+// cronSchedule runs a job at the minutes matching all of its fields
+type cronSchedule struct {
+	minutes  uint64
+	hours    uint64
+	days     uint64
+	months   uint64
+	weekdays uint64
+	// When both the day of month and the day of week are restricted, a day matching either is enough, like in cron
+	anyDay     bool
+	anyWeekday bool
+}
+
+// maxScheduleYears bounds the search of the next run, for specs like "0 0 31 2 *" that never match
+const maxScheduleYears = 5
+
+func (s cronSchedule) Next(after time.Time) time.Time {
+	t := after.UTC().Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(maxScheduleYears, 0, 0)
+
+	for t.Before(limit) {
+		if s.months&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+			continue
+		}
+		if !s.matchesDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, time.UTC)
+			continue
+		}
+		if s.hours&(1<<uint(t.Hour())) == 0 {
+			t = t.Truncate(time.Hour).Add(time.Hour)
+			continue
+		}
+		if s.minutes&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+func (s cronSchedule) matchesDay(t time.Time) bool {
+	dayMatches := s.days&(1<<uint(t.Day())) != 0
+	weekdayMatches := s.weekdays&(1<<uint(t.Weekday())) != 0
+	if !s.anyDay && !s.anyWeekday {
+		return dayMatches || weekdayMatches
+	}
+	return dayMatches && weekdayMatches
+}
+
+// intervalSchedule runs a job at every multiple of its interval since the zero time, so that the instances
+// agree on the times a job is due. Intervals dividing a day start at midnight UTC
+type intervalSchedule struct {
+	interval time.Duration
+}
+
+func (s intervalSchedule) Next(after time.Time) time.Time {
+	return after.UTC().Truncate(s.interval).Add(s.interval)
+}
@@ -0,0 +1,72 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// frozenTime is a Monday
+var frozenTime = time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+
+func TestParseSchedule_Next(t *testing.T) {
+	tests := []struct {
+		spec  string
+		after time.Time
+		next  time.Time
+	}{
+		{"@hourly", frozenTime, frozenTime.Add(time.Hour)},
+		{"@hourly", frozenTime.Add(59 * time.Minute), frozenTime.Add(time.Hour)},
+		{"15 * * * *", frozenTime, frozenTime.Add(15 * time.Minute)},
+		{"*/20 * * * *", frozenTime.Add(41 * time.Minute), frozenTime.Add(time.Hour)},
+		{"5/20 * * * *", frozenTime, frozenTime.Add(5 * time.Minute)},
+		{"0 3 * * *", frozenTime, time.Date(2024, 1, 16, 3, 0, 0, 0, time.UTC)},
+		{"30 9-17/4 * * *", frozenTime, time.Date(2024, 1, 15, 13, 30, 0, 0, time.UTC)},
+		{"0 0 1,15 * *", frozenTime, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"@weekly", frozenTime, time.Date(2024, 1, 21, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", frozenTime, time.Date(2024, 1, 21, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 3 *", frozenTime, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", frozenTime, time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+		// Either the day of month or the day of week, when both are restricted
+		{"0 0 20 * 3", frozenTime, time.Date(2024, 1, 17, 0, 0, 0, 0, time.UTC)},
+		{"@every 10m", frozenTime.Add(25 * time.Minute), frozenTime.Add(30 * time.Minute)},
+		{"@every 10m", frozenTime.Add(30 * time.Minute), frozenTime.Add(40 * time.Minute)},
+		// The times are in UTC whatever the location of the given time
+		{"0 3 * * *", frozenTime.In(time.FixedZone("CET", 3600)), time.Date(2024, 1, 16, 3, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		schedule, err := ParseSchedule(tt.spec)
+		require.NoError(t, err, tt.spec)
+		assert.Equal(t, tt.next, schedule.Next(tt.after), "%s after %s", tt.spec, tt.after)
+	}
+}
+
+func TestParseSchedule_NeverMatches(t *testing.T) {
+	schedule, err := ParseSchedule("0 0 31 2 *")
+	require.NoError(t, err)
+	assert.True(t, schedule.Next(frozenTime).IsZero())
+}
+
+func TestParseSchedule_Invalid(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+		"@yearly",
+		"@every soon",
+		"@every 10ms",
+	} {
+		_, err := ParseSchedule(spec)
+		assert.Error(t, err, spec)
+	}
+}
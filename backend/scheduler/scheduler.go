@@ -0,0 +1,284 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	RunStatusRunning   = "running"
+	RunStatusSucceeded = "succeeded"
+	RunStatusFailed    = "failed"
+)
+
+// Run is a run of a job, kept in the run history
+type Run struct {
+	ID  int64  `json:"id"`
+	Job string `json:"job"`
+	// Instance is the instance of the backend that ran the job
+	Instance string `json:"instance"`
+	// ScheduledAt is the time the job was due, which is the same on every instance
+	ScheduledAt time.Time  `json:"scheduledAt"`
+	StartedAt   time.Time  `json:"startedAt"`
+	FinishedAt  *time.Time `json:"finishedAt,omitempty"`
+	Status      string     `json:"status"`
+	Error       string     `json:"error,omitempty"`
+}
+
+// Store keeps the run history of the jobs and their locks. Implementations can be in memory for a single instance,
+// or shared between the instances so that each run of a job happens on a single one of them
+type Store interface {
+	// Lock takes the lock of the job, until unlock is called. locked is false when another runner holds it
+	Lock(ctx context.Context, job string) (unlock func(), locked bool, err error)
+	// StartRun records the start of the run and sets its ID. started is false when the job already ran at its
+	// ScheduledAt time, so that an instance whose clock is late doesn't run it again
+	StartRun(ctx context.Context, run *Run) (started bool, err error)
+	// FinishRun records the status of the run once it's over
+	FinishRun(ctx context.Context, run *Run) error
+	// ListRuns returns the last runs of the job, the latest first
+	ListRuns(ctx context.Context, job string, limit int) ([]Run, error)
+	// PruneRuns removes the runs started before the given time
+	PruneRuns(ctx context.Context, before time.Time) error
+}
+
+// JobFunc is the work of a job. The context is cancelled when the scheduler stops
+type JobFunc func(ctx context.Context) error
+
+// job is a registered job
+type job struct {
+	name     string
+	spec     string
+	schedule Schedule
+	run      JobFunc
+	// store is the shared store, or the local one for the jobs that run on every instance
+	store         Store
+	everyInstance bool
+}
+
+// JobInfo describes a registered job and its last runs
+type JobInfo struct {
+	Name          string    `json:"name"`
+	Schedule      string    `json:"schedule"`
+	EveryInstance bool      `json:"everyInstance"`
+	NextRunAt     time.Time `json:"nextRunAt"`
+	Runs          []Run     `json:"runs"`
+}
+
+// Scheduler runs the periodic jobs of the backend in the background. The jobs registered with Register run on a
+// single instance at a time, the ones registered with RegisterEveryInstance run on each instance, for the state
+// an instance keeps in memory
+type Scheduler struct {
+	store      Store
+	localStore Store
+	instance   string
+	timeFunc   func() time.Time
+
+	mu      sync.Mutex
+	jobs    map[string]*job
+	started bool
+}
+
+// NewScheduler creates a scheduler keeping its run history in the store. instance names the instance
+// in the history
+func NewScheduler(store Store, instance string) *Scheduler {
+	return NewSchedulerWithTimeFunc(store, instance, time.Now)
+}
+
+// NewSchedulerWithTimeFunc creates a scheduler with a custom time function, for testing
+func NewSchedulerWithTimeFunc(store Store, instance string, timeFunc func() time.Time) *Scheduler {
+	return &Scheduler{
+		store:      store,
+		localStore: NewInMemoryStore(),
+		instance:   instance,
+		timeFunc:   timeFunc,
+		jobs:       make(map[string]*job),
+	}
+}
+
+// Register adds a job run on a single instance at a time, on the schedule of the cron spec
+func (s *Scheduler) Register(name string, spec string, run JobFunc) error {
+	return s.register(name, spec, run, false)
+}
+
+// RegisterEveryInstance adds a job run on each instance, like the pruning of state kept in memory
+func (s *Scheduler) RegisterEveryInstance(name string, spec string, run JobFunc) error {
+	return s.register(name, spec, run, true)
+}
+
+func (s *Scheduler) register(name string, spec string, run JobFunc, everyInstance bool) error {
+	schedule, err := ParseSchedule(spec)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.started {
+		return fmt.Errorf("job %s registered after the scheduler started", name)
+	}
+	if _, exists := s.jobs[name]; exists {
+		return fmt.Errorf("job %s is already registered", name)
+	}
+
+	store := s.store
+	if everyInstance {
+		store = s.localStore
+	}
+	s.jobs[name] = &job{
+		name:          name,
+		spec:          spec,
+		schedule:      schedule,
+		run:           run,
+		store:         store,
+		everyInstance: everyInstance,
+	}
+	return nil
+}
+
+// Start runs each job on its schedule until the context is cancelled. The runs missed while no instance
+// was up are skipped
+func (s *Scheduler) Start(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.started {
+		return
+	}
+	s.started = true
+
+	for _, j := range s.jobs {
+		go s.loop(ctx, j)
+	}
+	log.Infof("Scheduler started with %d jobs", len(s.jobs))
+}
+
+func (s *Scheduler) loop(ctx context.Context, j *job) {
+	for {
+		scheduledAt := j.schedule.Next(s.timeFunc())
+		if scheduledAt.IsZero() {
+			log.Errorf("Job %s has no next run for schedule %q", j.name, j.spec)
+			return
+		}
+
+		timer := time.NewTimer(scheduledAt.Sub(s.timeFunc()))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		if _, err := s.runJob(ctx, j, scheduledAt); err != nil {
+			log.Errorf("Failed to run job %s: %v", j.name, err)
+		}
+	}
+}
+
+// RunJob runs the job now, outside of its schedule. The run is nil when another instance is running the job
+func (s *Scheduler) RunJob(ctx context.Context, name string) (*Run, error) {
+	s.mu.Lock()
+	j, exists := s.jobs[name]
+	s.mu.Unlock()
+	if !exists {
+		return nil, fmt.Errorf("job %s is not registered", name)
+	}
+	return s.runJob(ctx, j, s.timeFunc().UTC())
+}
+
+// runJob runs the job for its scheduled time, unless another runner holds its lock or already ran it.
+// The error is about the run history; the error of the job itself is in the run
+func (s *Scheduler) runJob(ctx context.Context, j *job, scheduledAt time.Time) (*Run, error) {
+	unlock, locked, err := j.store.Lock(ctx, j.name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock job: %v", err)
+	}
+	if !locked {
+		log.Debugf("Job %s is running on another instance", j.name)
+		return nil, nil
+	}
+	defer unlock()
+
+	run := &Run{
+		Job:         j.name,
+		Instance:    s.instance,
+		ScheduledAt: scheduledAt,
+		StartedAt:   s.timeFunc(),
+		Status:      RunStatusRunning,
+	}
+	started, err := j.store.StartRun(ctx, run)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record run: %v", err)
+	}
+	if !started {
+		log.Debugf("Job %s already ran for %s", j.name, scheduledAt.Format(time.RFC3339))
+		return nil, nil
+	}
+
+	jobErr := runSafely(ctx, j)
+	finishedAt := s.timeFunc()
+	run.FinishedAt = &finishedAt
+	run.Status = RunStatusSucceeded
+	if jobErr != nil {
+		run.Status = RunStatusFailed
+		run.Error = jobErr.Error()
+	}
+
+	// Logged in a structured format for Cloud Monitoring, like the requests
+	log.WithFields(log.Fields{
+		"job":         j.name,
+		"status":      run.Status,
+		"duration_ms": float64(finishedAt.Sub(run.StartedAt).Milliseconds()),
+		"instance":    s.instance,
+		"metric_type": "job_run",
+	}).Info("job run completed")
+	if jobErr != nil {
+		log.Errorf("Job %s failed: %v", j.name, jobErr)
+	}
+
+	if err := j.store.FinishRun(ctx, run); err != nil {
+		return run, fmt.Errorf("failed to record end of run: %v", err)
+	}
+	return run, nil
+}
+
+// runSafely turns the panics of a job into errors, so that a failing job doesn't stop the backend
+func runSafely(ctx context.Context, j *job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return j.run(ctx)
+}
+
+// Jobs describes the registered jobs with their last runs, by name
+func (s *Scheduler) Jobs(ctx context.Context, runLimit int) ([]JobInfo, error) {
+	s.mu.Lock()
+	jobs := make([]*job, 0, len(s.jobs))
+	for _, j := range s.jobs {
+		jobs = append(jobs, j)
+	}
+	s.mu.Unlock()
+	sort.Slice(jobs, func(i, k int) bool { return jobs[i].name < jobs[k].name })
+
+	now := s.timeFunc()
+	infos := make([]JobInfo, 0, len(jobs))
+	for _, j := range jobs {
+		runs, err := j.store.ListRuns(ctx, j.name, runLimit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list runs of job %s: %v", j.name, err)
+		}
+		infos = append(infos, JobInfo{
+			Name:          j.name,
+			Schedule:      j.spec,
+			EveryInstance: j.everyInstance,
+			NextRunAt:     j.schedule.Next(now),
+			Runs:          runs,
+		})
+	}
+	return infos, nil
+}
@@ -0,0 +1,155 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduler_RunJob(t *testing.T) {
+	ctx := context.Background()
+	s := NewSchedulerWithTimeFunc(NewInMemoryStore(), "instance-1", func() time.Time { return frozenTime })
+
+	calls := 0
+	require.NoError(t, s.Register("cleanup", "@hourly", func(ctx context.Context) error {
+		calls++
+		return nil
+	}))
+	require.NoError(t, s.Register("failing", "@hourly", func(ctx context.Context) error {
+		return errors.New("database is down")
+	}))
+	require.NoError(t, s.Register("panicking", "@hourly", func(ctx context.Context) error {
+		panic("nil map")
+	}))
+
+	run, err := s.RunJob(ctx, "cleanup")
+	require.NoError(t, err)
+	require.NotNil(t, run)
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, RunStatusSucceeded, run.Status)
+	assert.Equal(t, "instance-1", run.Instance)
+	require.NotNil(t, run.FinishedAt)
+
+	// A run happens once for its scheduled time
+	run, err = s.RunJob(ctx, "cleanup")
+	require.NoError(t, err)
+	assert.Nil(t, run)
+	assert.Equal(t, 1, calls)
+
+	run, err = s.RunJob(ctx, "failing")
+	require.NoError(t, err)
+	assert.Equal(t, RunStatusFailed, run.Status)
+	assert.Equal(t, "database is down", run.Error)
+
+	run, err = s.RunJob(ctx, "panicking")
+	require.NoError(t, err)
+	assert.Equal(t, RunStatusFailed, run.Status)
+	assert.Equal(t, "panic: nil map", run.Error)
+
+	_, err = s.RunJob(ctx, "unknown")
+	assert.Error(t, err)
+}
+
+func TestScheduler_SharedStoreRunsOnce(t *testing.T) {
+	ctx := context.Background()
+	store := NewInMemoryStore()
+	timeFunc := func() time.Time { return frozenTime }
+	first := NewSchedulerWithTimeFunc(store, "instance-1", timeFunc)
+	second := NewSchedulerWithTimeFunc(store, "instance-2", timeFunc)
+
+	var shared, local atomic.Int32
+	for _, s := range []*Scheduler{first, second} {
+		require.NoError(t, s.Register("purge", "@hourly", func(ctx context.Context) error {
+			shared.Add(1)
+			return nil
+		}))
+		require.NoError(t, s.RegisterEveryInstance("prune", "@hourly", func(ctx context.Context) error {
+			local.Add(1)
+			return nil
+		}))
+	}
+
+	for _, s := range []*Scheduler{first, second} {
+		_, err := s.RunJob(ctx, "purge")
+		require.NoError(t, err)
+		_, err = s.RunJob(ctx, "prune")
+		require.NoError(t, err)
+	}
+	assert.Equal(t, int32(1), shared.Load())
+	assert.Equal(t, int32(2), local.Load())
+
+	// A job whose lock is held by another runner is skipped
+	unlock, locked, err := store.Lock(ctx, "purge")
+	require.NoError(t, err)
+	require.True(t, locked)
+	first.timeFunc = func() time.Time { return frozenTime.Add(time.Hour) }
+	run, err := first.RunJob(ctx, "purge")
+	require.NoError(t, err)
+	assert.Nil(t, run)
+	unlock()
+	assert.Equal(t, int32(1), shared.Load())
+}
+
+func TestScheduler_Register(t *testing.T) {
+	s := NewScheduler(NewInMemoryStore(), "instance-1")
+	noop := func(ctx context.Context) error { return nil }
+
+	assert.Error(t, s.Register("cleanup", "every hour", noop))
+	require.NoError(t, s.Register("cleanup", "@hourly", noop))
+	assert.Error(t, s.RegisterEveryInstance("cleanup", "@hourly", noop))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s.Start(ctx)
+	assert.Error(t, s.Register("purge", "@daily", noop))
+}
+
+func TestScheduler_Start(t *testing.T) {
+	s := NewScheduler(NewInMemoryStore(), "instance-1")
+	ran := make(chan struct{}, 1)
+	require.NoError(t, s.Register("tick", "@every 1s", func(ctx context.Context) error {
+		select {
+		case ran <- struct{}{}:
+		default:
+		}
+		return nil
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s.Start(ctx)
+
+	select {
+	case <-ran:
+	case <-time.After(3 * time.Second):
+		t.Fatal("job didn't run on its schedule")
+	}
+}
+
+func TestScheduler_Jobs(t *testing.T) {
+	ctx := context.Background()
+	s := NewSchedulerWithTimeFunc(NewInMemoryStore(), "instance-1", func() time.Time { return frozenTime })
+	noop := func(ctx context.Context) error { return nil }
+	require.NoError(t, s.Register("purge", "0 3 * * *", noop))
+	require.NoError(t, s.RegisterEveryInstance("prune", "@hourly", noop))
+	_, err := s.RunJob(ctx, "purge")
+	require.NoError(t, err)
+
+	jobs, err := s.Jobs(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, jobs, 2)
+	assert.Equal(t, "prune", jobs[0].Name)
+	assert.True(t, jobs[0].EveryInstance)
+	assert.Empty(t, jobs[0].Runs)
+	assert.Equal(t, frozenTime.Add(time.Hour), jobs[0].NextRunAt)
+	assert.Equal(t, "purge", jobs[1].Name)
+	assert.Equal(t, "0 3 * * *", jobs[1].Schedule)
+	assert.Equal(t, time.Date(2024, 1, 16, 3, 0, 0, 0, time.UTC), jobs[1].NextRunAt)
+	require.Len(t, jobs[1].Runs, 1)
+	assert.Equal(t, RunStatusSucceeded, jobs[1].Runs[0].Status)
+}
@@ -0,0 +1,147 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"ligain/backend/models"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultAccessTokenTTL is how long an access token stays valid
+	DefaultAccessTokenTTL = 15 * time.Minute
+	// minSigningKeyLength is the minimum length of the HMAC key, as recommended for HS256
+	minSigningKeyLength = 32
+	accessTokenIssuer   = "ligain"
+)
+
+var (
+	errMalformedAccessToken = errors.New("malformed access token")
+	errInvalidSignature     = errors.New("invalid access token signature")
+)
+
+// accessTokenHeader is the only JWT header we issue and accept
+var accessTokenHeader = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+// accessTokenClaims are the claims of an access token
+// The name is carried by the token so that authenticated requests don't need to load the player,
+// and the session is the refresh token family the token was issued with
+type accessTokenClaims struct {
+	Issuer    string `json:"iss"`
+	Subject   string `json:"sub"`
+	Name      string `json:"name"`
+	SessionID string `json:"sid,omitempty"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// AccessTokenSigner issues and verifies the access tokens, as JWTs signed with HMAC-SHA256
+type AccessTokenSigner struct {
+	key []byte
+	ttl time.Duration
+}
+
+// NewAccessTokenSigner creates an AccessTokenSigner. The key must be at least 32 bytes long
+func NewAccessTokenSigner(key []byte, ttl time.Duration) (*AccessTokenSigner, error) {
+	if len(key) < minSigningKeyLength {
+		return nil, fmt.Errorf("signing key must be at least %d bytes long", minSigningKeyLength)
+	}
+	return &AccessTokenSigner{key: key, ttl: ttl}, nil
+}
+
+// Sign issues an access token for the player in a session, and returns it with its expiry
+func (s *AccessTokenSigner) Sign(player *models.PlayerData, sessionID string, now time.Time) (string, time.Time, error) {
+	expiresAt := now.Add(s.ttl)
+	payload, err := json.Marshal(accessTokenClaims{
+		Issuer:    accessTokenIssuer,
+		Subject:   player.ID,
+		Name:      player.Name,
+		SessionID: sessionID,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: expiresAt.Unix(),
+	})
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	unsigned := accessTokenHeader + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return unsigned + "." + s.signature(unsigned), expiresAt, nil
+}
+
+// Verify checks the signature and the expiry of an access token, and returns the player it was issued for.
+// The player only holds the id and the name carried by the token
+func (s *AccessTokenSigner) Verify(token string, now time.Time) (*models.PlayerData, error) {
+	claims, err := s.verifyClaims(token, now)
+	if err != nil {
+		return nil, err
+	}
+	return &models.PlayerData{ID: claims.Subject, Name: claims.Name}, nil
+}
+
+// SessionID checks an access token like Verify, and returns the session it was issued in
+func (s *AccessTokenSigner) SessionID(token string, now time.Time) (string, error) {
+	claims, err := s.verifyClaims(token, now)
+	if err != nil {
+		return "", err
+	}
+	return claims.SessionID, nil
+}
+
+// ExpiredSession checks the signature of an access token that may have expired,
+// and returns the player and the session it was issued for, along with when it expires
+func (s *AccessTokenSigner) ExpiredSession(token string) (*models.PlayerData, string, time.Time, error) {
+	claims, err := s.parseClaims(token)
+	if err != nil {
+		return nil, "", time.Time{}, err
+	}
+	return &models.PlayerData{ID: claims.Subject, Name: claims.Name}, claims.SessionID, time.Unix(claims.ExpiresAt, 0), nil
+}
+
+func (s *AccessTokenSigner) verifyClaims(token string, now time.Time) (*accessTokenClaims, error) {
+	claims, err := s.parseClaims(token)
+	if err != nil {
+		return nil, err
+	}
+	if !now.Before(time.Unix(claims.ExpiresAt, 0)) {
+		return nil, &models.TokenExpiredError{Reason: "access token expired"}
+	}
+	return claims, nil
+}
+
+// parseClaims checks the signature of an access token and returns its claims, whatever its expiry
+func (s *AccessTokenSigner) parseClaims(token string) (*accessTokenClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || parts[0] != accessTokenHeader {
+		return nil, errMalformedAccessToken
+	}
+	expected := s.signature(parts[0] + "." + parts[1])
+	if !hmac.Equal([]byte(expected), []byte(parts[2])) {
+		return nil, errInvalidSignature
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, errMalformedAccessToken
+	}
+	var claims accessTokenClaims
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Issuer != accessTokenIssuer || claims.Subject == "" {
+		return nil, errMalformedAccessToken
+	}
+
+	return &claims, nil
+}
+
+func (s *AccessTokenSigner) signature(unsigned string) string {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(unsigned))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// isAccessToken checks if a token has the shape of a signed access token, as opposed to a legacy opaque token
+func isAccessToken(token string) bool {
+	return strings.Count(token, ".") == 2
+}
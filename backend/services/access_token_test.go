@@ -0,0 +1,72 @@
+package services
+
+import (
+	"errors"
+	"ligain/backend/models"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testSigningKey = []byte("0123456789abcdef0123456789abcdef")
+
+func TestAccessTokenSigner_SignAndVerify(t *testing.T) {
+	signer, err := NewAccessTokenSigner(testSigningKey, DefaultAccessTokenTTL)
+	require.NoError(t, err)
+
+	token, expiresAt, err := signer.Sign(&models.PlayerData{ID: "player1", Name: "Player 1"}, "session1", frozenTime)
+	require.NoError(t, err)
+	assert.Equal(t, frozenTime.Add(DefaultAccessTokenTTL), expiresAt)
+	assert.True(t, isAccessToken(token))
+
+	player, err := signer.Verify(token, frozenTime.Add(DefaultAccessTokenTTL-time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, "player1", player.ID)
+	assert.Equal(t, "Player 1", player.Name)
+
+	sessionID, err := signer.SessionID(token, frozenTime)
+	require.NoError(t, err)
+	assert.Equal(t, "session1", sessionID)
+}
+
+func TestAccessTokenSigner_Verify_Rejected(t *testing.T) {
+	signer, err := NewAccessTokenSigner(testSigningKey, DefaultAccessTokenTTL)
+	require.NoError(t, err)
+	token, _, err := signer.Sign(&models.PlayerData{ID: "player1", Name: "Player 1"}, "session1", frozenTime)
+	require.NoError(t, err)
+	parts := strings.Split(token, ".")
+
+	t.Run("Expired", func(t *testing.T) {
+		_, err := signer.Verify(token, frozenTime.Add(DefaultAccessTokenTTL))
+		var tokenExpiredErr *models.TokenExpiredError
+		assert.True(t, errors.As(err, &tokenExpiredErr))
+	})
+
+	t.Run("TamperedPayload", func(t *testing.T) {
+		otherToken, _, err := signer.Sign(&models.PlayerData{ID: "player2", Name: "Player 2"}, "session2", frozenTime)
+		require.NoError(t, err)
+		forged := parts[0] + "." + strings.Split(otherToken, ".")[1] + "." + parts[2]
+		_, err = signer.Verify(forged, frozenTime)
+		assert.Equal(t, errInvalidSignature, err)
+	})
+
+	t.Run("OtherKey", func(t *testing.T) {
+		otherSigner, err := NewAccessTokenSigner([]byte("fedcba9876543210fedcba9876543210"), DefaultAccessTokenTTL)
+		require.NoError(t, err)
+		_, err = otherSigner.Verify(token, frozenTime)
+		assert.Equal(t, errInvalidSignature, err)
+	})
+
+	t.Run("Malformed", func(t *testing.T) {
+		_, err := signer.Verify("not.a.token", frozenTime)
+		assert.Equal(t, errMalformedAccessToken, err)
+	})
+}
+
+func TestNewAccessTokenSigner_ShortKey(t *testing.T) {
+	_, err := NewAccessTokenSigner([]byte("too short"), DefaultAccessTokenTTL)
+	assert.Error(t, err)
+}
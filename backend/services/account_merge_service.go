@@ -0,0 +1,148 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"ligain/backend/models"
+	"ligain/backend/repositories"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// AccountMergeService merges the accounts a player ended up with by signing in differently on several devices
+type AccountMergeService interface {
+	// MergeAccount moves everything owned by the account otherAccessToken was issued to into the player's account,
+	// then deletes that account. Holding an access token of the other account proves the player owns it
+	MergeAccount(ctx context.Context, playerID string, otherAccessToken string) (*models.MergeSummary, error)
+	// MergePlayers moves everything owned by the source player into the target player, then deletes the source player.
+	// Nothing proves both accounts belong to the same person, so it's only for the operators
+	MergePlayers(ctx context.Context, sourceID string, targetID string) (*models.MergeSummary, error)
+}
+
+// AccountMergeServiceImpl implements AccountMergeService
+type AccountMergeServiceImpl struct {
+	uow         repositories.UnitOfWork
+	mergeRepo   repositories.AccountMergeRepository
+	playerRepo  repositories.PlayerRepository
+	gameRepo    repositories.GameRepository
+	authService AuthServiceInterface
+	timeFunc    func() time.Time
+}
+
+// NewAccountMergeService creates a new AccountMergeService instance
+func NewAccountMergeService(
+	uow repositories.UnitOfWork,
+	mergeRepo repositories.AccountMergeRepository,
+	playerRepo repositories.PlayerRepository,
+	gameRepo repositories.GameRepository,
+	authService AuthServiceInterface,
+) *AccountMergeServiceImpl {
+	return NewAccountMergeServiceWithTimeFunc(uow, mergeRepo, playerRepo, gameRepo, authService, time.Now)
+}
+
+// NewAccountMergeServiceWithTimeFunc creates an AccountMergeService with a custom time function (for testing)
+func NewAccountMergeServiceWithTimeFunc(
+	uow repositories.UnitOfWork,
+	mergeRepo repositories.AccountMergeRepository,
+	playerRepo repositories.PlayerRepository,
+	gameRepo repositories.GameRepository,
+	authService AuthServiceInterface,
+	timeFunc func() time.Time,
+) *AccountMergeServiceImpl {
+	return &AccountMergeServiceImpl{
+		uow:         uow,
+		mergeRepo:   mergeRepo,
+		playerRepo:  playerRepo,
+		gameRepo:    gameRepo,
+		authService: authService,
+		timeFunc:    timeFunc,
+	}
+}
+
+// MergeAccount implements AccountMergeService. The player's account is the one kept, with its name and settings
+func (s *AccountMergeServiceImpl) MergeAccount(ctx context.Context, playerID string, otherAccessToken string) (*models.MergeSummary, error) {
+	source, err := s.authService.ValidateToken(ctx, otherAccessToken)
+	if err != nil {
+		return nil, &models.InvalidMergeError{Reason: "the account to merge could not be verified"}
+	}
+	return s.MergePlayers(ctx, source.ID, playerID)
+}
+
+// MergePlayers implements AccountMergeService. The target player is the one kept, with its name and settings
+func (s *AccountMergeServiceImpl) MergePlayers(ctx context.Context, sourceID string, targetID string) (*models.MergeSummary, error) {
+	if sourceID == targetID {
+		return nil, &models.InvalidMergeError{Reason: "both accounts are the same"}
+	}
+
+	target, err := s.playerRepo.GetPlayerByID(ctx, targetID)
+	if err != nil || target == nil {
+		return nil, &models.PlayerNotFoundError{Reason: "player not found"}
+	}
+	source, err := s.playerRepo.GetPlayerByID(ctx, sourceID)
+	if err != nil || source == nil {
+		return nil, &models.InvalidMergeError{Reason: "the account to merge doesn't exist anymore"}
+	}
+
+	summary := &models.MergeSummary{}
+	err = s.uow.WithinTx(ctx, func(txCtx context.Context) error {
+		movedBets, conflicts, err := s.mergeRepo.MergeBets(txCtx, source.ID, target.ID)
+		if err != nil {
+			return err
+		}
+		gameIDs, err := s.mergeRepo.MergeGameData(txCtx, source.ID, target.ID)
+		if err != nil {
+			return err
+		}
+		if err := s.mergeRepo.MoveSessions(txCtx, source.ID, target.ID); err != nil {
+			return err
+		}
+		if err := s.mergeRepo.CompleteMerge(txCtx, source, target, s.timeFunc()); err != nil {
+			return err
+		}
+
+		summary.MovedBets = movedBets
+		summary.Conflicts = conflicts
+		summary.GameIDs = gameIDs
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error merging player %s into %s: %w", source.ID, target.ID, err)
+	}
+
+	// Post-commit: the cached games and their history still hold the merged player and its bets
+	s.rebaseGames(ctx, summary)
+
+	summary.Player, err = s.playerRepo.GetPlayerByID(ctx, target.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting merged player: %v", err)
+	}
+
+	log.Infof("Merged player %s into %s: %d bets moved, %d conflicts, %d games", source.ID, target.ID, summary.MovedBets, len(summary.Conflicts), len(summary.GameIDs))
+	return summary, nil
+}
+
+// rebaseGames loads the games touched by the merge again, so that they hold the kept player.
+// Their history is rebased when it's kept, since the merge isn't one of their events
+func (s *AccountMergeServiceImpl) rebaseGames(ctx context.Context, summary *models.MergeSummary) {
+	gameIDs := append([]string{}, summary.GameIDs...)
+	for _, conflict := range summary.Conflicts {
+		gameIDs = append(gameIDs, conflict.GameID)
+	}
+
+	if recorder, ok := s.gameRepo.(repositories.GameHistoryRecorder); ok {
+		for _, gameID := range gameIDs {
+			if err := recorder.RebaseGame(ctx, gameID); err != nil {
+				log.WithError(err).Errorf("Failed to rebase the history of game %s after a merge", gameID)
+			}
+		}
+		return
+	}
+	evicter, ok := s.gameRepo.(repositories.GameCacheEvicter)
+	if !ok {
+		return
+	}
+	for _, gameID := range gameIDs {
+		evicter.EvictGame(gameID)
+	}
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"ligain/backend/models"
+	"ligain/backend/repositories"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockAccountMergeRepository struct {
+	mock.Mock
+}
+
+func (m *MockAccountMergeRepository) MergeBets(ctx context.Context, sourceID, targetID string) (int, []models.BetConflict, error) {
+	args := m.Called(ctx, sourceID, targetID)
+	return args.Int(0), args.Get(1).([]models.BetConflict), args.Error(2)
+}
+
+func (m *MockAccountMergeRepository) MergeGameData(ctx context.Context, sourceID, targetID string) ([]string, error) {
+	args := m.Called(ctx, sourceID, targetID)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockAccountMergeRepository) MoveSessions(ctx context.Context, sourceID, targetID string) error {
+	args := m.Called(ctx, sourceID, targetID)
+	return args.Error(0)
+}
+
+func (m *MockAccountMergeRepository) CompleteMerge(ctx context.Context, source, target *models.PlayerData, mergedAt time.Time) error {
+	args := m.Called(ctx, source, target, mergedAt)
+	return args.Error(0)
+}
+
+// evictingGameRepository records the games evicted from its cache
+type evictingGameRepository struct {
+	repositories.GameRepository
+	evicted []string
+	cleared int
+}
+
+func (r *evictingGameRepository) EvictGame(gameId string) {
+	r.evicted = append(r.evicted, gameId)
+}
+
+func (r *evictingGameRepository) ClearCache() {
+	r.cleared++
+}
+
+func TestAccountMergeService_MergeAccount(t *testing.T) {
+	ctx := context.Background()
+
+	setup := func(t *testing.T) (*AccountMergeServiceImpl, *MockAccountMergeRepository, *evictingGameRepository, *models.AuthResponse, *models.AuthResponse) {
+		playerRepo := NewMockPlayerRepository()
+		authService := NewAuthServiceWithTimeFunc(playerRepo, NewMockOAuthVerifier(), func() time.Time { return frozenTime })
+		kept, err := authService.AuthenticateGuest(ctx, "Kept", "")
+		require.NoError(t, err)
+		merged, err := authService.AuthenticateGuest(ctx, "Merged", "")
+		require.NoError(t, err)
+
+		mergeRepo := &MockAccountMergeRepository{}
+		gameRepo := &evictingGameRepository{GameRepository: repositories.NewInMemoryGameRepository()}
+		mergeService := NewAccountMergeServiceWithTimeFunc(repositories.NewNoopUnitOfWork(), mergeRepo, playerRepo, gameRepo, authService, func() time.Time { return frozenTime })
+		return mergeService, mergeRepo, gameRepo, kept, merged
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mergeService, mergeRepo, gameRepo, kept, merged := setup(t)
+		conflicts := []models.BetConflict{{GameID: "game2", MatchID: "match1", KeptMergedBet: true}}
+		mergeRepo.On("MergeBets", mock.Anything, merged.Player.ID, kept.Player.ID).Return(3, conflicts, nil)
+		mergeRepo.On("MergeGameData", mock.Anything, merged.Player.ID, kept.Player.ID).Return([]string{"game1"}, nil)
+		mergeRepo.On("MoveSessions", mock.Anything, merged.Player.ID, kept.Player.ID).Return(nil)
+		mergeRepo.On("CompleteMerge", mock.Anything, mock.MatchedBy(func(source *models.PlayerData) bool {
+			return source.ID == merged.Player.ID
+		}), mock.MatchedBy(func(target *models.PlayerData) bool {
+			return target.ID == kept.Player.ID
+		}), frozenTime).Return(nil)
+
+		summary, err := mergeService.MergeAccount(ctx, kept.Player.ID, merged.Token)
+		require.NoError(t, err)
+
+		assert.Equal(t, kept.Player.ID, summary.Player.ID)
+		assert.Equal(t, 3, summary.MovedBets)
+		assert.Equal(t, conflicts, summary.Conflicts)
+		assert.Equal(t, []string{"game1"}, summary.GameIDs)
+		assert.Equal(t, []string{"game1", "game2"}, gameRepo.evicted)
+		mergeRepo.AssertExpectations(t)
+	})
+
+	t.Run("SameAccount", func(t *testing.T) {
+		mergeService, mergeRepo, _, kept, _ := setup(t)
+
+		_, err := mergeService.MergeAccount(ctx, kept.Player.ID, kept.Token)
+		var invalidMergeErr *models.InvalidMergeError
+		assert.True(t, errors.As(err, &invalidMergeErr))
+		mergeRepo.AssertNotCalled(t, "MergeBets", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("InvalidToken", func(t *testing.T) {
+		mergeService, mergeRepo, _, kept, _ := setup(t)
+
+		_, err := mergeService.MergeAccount(ctx, kept.Player.ID, "not-a-token")
+		var invalidMergeErr *models.InvalidMergeError
+		assert.True(t, errors.As(err, &invalidMergeErr))
+		mergeRepo.AssertNotCalled(t, "MergeBets", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("MergePlayersUnknownPlayer", func(t *testing.T) {
+		mergeService, mergeRepo, _, _, merged := setup(t)
+
+		_, err := mergeService.MergePlayers(ctx, merged.Player.ID, "unknown")
+		var notFoundErr *models.PlayerNotFoundError
+		assert.True(t, errors.As(err, &notFoundErr))
+		mergeRepo.AssertNotCalled(t, "MergeBets", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("FailureStopsTheMerge", func(t *testing.T) {
+		mergeService, mergeRepo, gameRepo, kept, merged := setup(t)
+		mergeRepo.On("MergeBets", mock.Anything, merged.Player.ID, kept.Player.ID).Return(1, []models.BetConflict{}, nil)
+		mergeRepo.On("MergeGameData", mock.Anything, merged.Player.ID, kept.Player.ID).Return([]string(nil), errors.New("db error"))
+
+		_, err := mergeService.MergeAccount(ctx, kept.Player.ID, merged.Token)
+		assert.Error(t, err)
+		mergeRepo.AssertNotCalled(t, "MoveSessions", mock.Anything, mock.Anything, mock.Anything)
+		mergeRepo.AssertNotCalled(t, "CompleteMerge", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		assert.Empty(t, gameRepo.evicted)
+	})
+}
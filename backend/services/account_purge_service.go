@@ -0,0 +1,91 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"ligain/backend/repositories"
+	"ligain/backend/storage"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// AccountPurgeService removes for good the personal data of the accounts deleted longer than AccountDeletionGracePeriod ago
+type AccountPurgeService interface {
+	// PurgeDeletedAccounts purges the accounts whose grace period ended, and returns how many were purged
+	PurgeDeletedAccounts(ctx context.Context) (int, error)
+}
+
+// AccountPurgeServiceImpl implements AccountPurgeService
+type AccountPurgeServiceImpl struct {
+	uow          repositories.UnitOfWork
+	deletionRepo repositories.AccountDeletionRepository
+	// blobStorage holds the avatars and the data export archives, it's nil when they aren't enabled
+	blobStorage storage.BlobStorage
+	timeFunc    func() time.Time
+}
+
+// NewAccountPurgeService creates a new AccountPurgeService instance
+func NewAccountPurgeService(
+	uow repositories.UnitOfWork,
+	deletionRepo repositories.AccountDeletionRepository,
+	blobStorage storage.BlobStorage,
+) *AccountPurgeServiceImpl {
+	return NewAccountPurgeServiceWithTimeFunc(uow, deletionRepo, blobStorage, time.Now)
+}
+
+// NewAccountPurgeServiceWithTimeFunc creates an AccountPurgeService with a custom time function (for testing)
+func NewAccountPurgeServiceWithTimeFunc(
+	uow repositories.UnitOfWork,
+	deletionRepo repositories.AccountDeletionRepository,
+	blobStorage storage.BlobStorage,
+	timeFunc func() time.Time,
+) *AccountPurgeServiceImpl {
+	return &AccountPurgeServiceImpl{
+		uow:          uow,
+		deletionRepo: deletionRepo,
+		blobStorage:  blobStorage,
+		timeFunc:     timeFunc,
+	}
+}
+
+// PurgeDeletedAccounts implements AccountPurgeService. Each account is purged in its own transaction,
+// and its avatar and data export archives are deleted once the transaction committed
+func (s *AccountPurgeServiceImpl) PurgeDeletedAccounts(ctx context.Context) (int, error) {
+	deletions, err := s.deletionRepo.GetDeletionsToPurge(ctx, s.timeFunc())
+	if err != nil {
+		return 0, fmt.Errorf("error getting accounts to purge: %v", err)
+	}
+
+	purged := 0
+	for _, deletion := range deletions {
+		var exportKeys []string
+		err := s.uow.WithinTx(ctx, func(txCtx context.Context) error {
+			keys, err := s.deletionRepo.PurgePlayer(txCtx, deletion.PlayerID)
+			exportKeys = keys
+			return err
+		})
+		if err != nil {
+			return purged, fmt.Errorf("error purging player %s: %v", deletion.PlayerID, err)
+		}
+		purged++
+
+		if deletion.AvatarObjectKey != nil && s.blobStorage != nil {
+			if err := s.blobStorage.Delete(ctx, *deletion.AvatarObjectKey); err != nil {
+				log.Warnf("Failed to delete avatar %s of purged player %s: %v", *deletion.AvatarObjectKey, deletion.PlayerID, err)
+			}
+		}
+		if s.blobStorage != nil {
+			for _, key := range exportKeys {
+				if err := s.blobStorage.Delete(ctx, key); err != nil {
+					log.Warnf("Failed to delete data export %s of purged player %s: %v", key, deletion.PlayerID, err)
+				}
+			}
+		}
+	}
+
+	if purged > 0 {
+		log.Infof("Purged %d deleted accounts", purged)
+	}
+	return purged, nil
+}
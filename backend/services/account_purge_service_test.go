@@ -0,0 +1,49 @@
+package services
+
+import (
+	"context"
+	"ligain/backend/models"
+	"ligain/backend/repositories"
+	"ligain/backend/storage"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountPurgeService_PurgeDeletedAccounts(t *testing.T) {
+	ctx := context.Background()
+	now := frozenTime
+	deletionRepo := repositories.NewInMemoryAccountDeletionRepository()
+	blobStorage := storage.NewMockBlobStorage()
+	purgeService := NewAccountPurgeServiceWithTimeFunc(repositories.NewNoopUnitOfWork(), deletionRepo, blobStorage, func() time.Time { return now })
+
+	avatarKey := "avatars/leaver/avatar.webp"
+	require.NoError(t, blobStorage.Upload(ctx, avatarKey, []byte("webp"), AvatarContentType))
+	require.NoError(t, deletionRepo.CreateDeletion(ctx, &models.AccountDeletion{
+		PlayerID:        "leaver",
+		OriginalName:    "Leaver",
+		AvatarObjectKey: &avatarKey,
+		DeletedAt:       frozenTime,
+		PurgeAfter:      frozenTime.Add(AccountDeletionGracePeriod),
+	}))
+
+	// Nothing is purged during the grace period
+	purged, err := purgeService.PurgeDeletedAccounts(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, purged)
+	_, exists := blobStorage.GetObject(avatarKey)
+	assert.True(t, exists)
+
+	now = frozenTime.Add(AccountDeletionGracePeriod)
+	purged, err = purgeService.PurgeDeletedAccounts(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, purged)
+	_, exists = blobStorage.GetObject(avatarKey)
+	assert.False(t, exists)
+
+	deletion, err := deletionRepo.GetDeletion(ctx, "leaver")
+	require.NoError(t, err)
+	assert.Nil(t, deletion)
+}
@@ -0,0 +1,91 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"ligain/backend/models"
+	"ligain/backend/repositories"
+	"ligain/backend/rules"
+	"slices"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// AchievementService awards achievements after each scored match and exposes them
+type AchievementService interface {
+	ScoreObserver
+	// GetPlayerAchievements returns the achievements of a player in every game, oldest first
+	GetPlayerAchievements(ctx context.Context, playerID string) ([]*models.Achievement, error)
+}
+
+// AchievementServiceImpl implements AchievementService
+type AchievementServiceImpl struct {
+	achievementRepo repositories.AchievementRepository
+	timeFunc        func() time.Time
+}
+
+// NewAchievementService creates a new AchievementService instance
+func NewAchievementService(achievementRepo repositories.AchievementRepository) *AchievementServiceImpl {
+	return NewAchievementServiceWithTimeFunc(achievementRepo, time.Now)
+}
+
+// NewAchievementServiceWithTimeFunc creates an AchievementService with a custom time function (for testing)
+func NewAchievementServiceWithTimeFunc(achievementRepo repositories.AchievementRepository, timeFunc func() time.Time) *AchievementServiceImpl {
+	return &AchievementServiceImpl{
+		achievementRepo: achievementRepo,
+		timeFunc:        timeFunc,
+	}
+}
+
+// OnMatchScored implements ScoreObserver by awarding the achievements earned with the result of the match.
+// Each achievement is awarded once, so a scoring handled twice awards nothing more. When the match is scored again,
+// the achievements its previous result awarded and the corrected one doesn't earn are revoked
+func (s *AchievementServiceImpl) OnMatchScored(gameID string, game models.Game, match models.Match, scoring *models.MatchScoring) error {
+	ctx := context.Background()
+	now := s.timeFunc()
+
+	earned := rules.EvaluateAchievements(game, match)
+	if scoring.Version > models.InitialScoringVersion {
+		if err := s.revokeUnearned(ctx, gameID, match, earned); err != nil {
+			return err
+		}
+	}
+
+	for playerID, codes := range earned {
+		for _, code := range codes {
+			achievement := models.NewAchievement(playerID, gameID, code, match.Id(), now)
+			awarded, err := s.achievementRepo.SaveAchievement(ctx, achievement)
+			if err != nil {
+				return fmt.Errorf("error saving achievement %s for player %s: %v", code, playerID, err)
+			}
+			if awarded {
+				log.Infof("Player %s earned achievement %s in game %s", playerID, code, gameID)
+			}
+		}
+	}
+	return nil
+}
+
+// revokeUnearned revokes the achievements awarded with the result of a match which aren't earned anymore
+func (s *AchievementServiceImpl) revokeUnearned(ctx context.Context, gameID string, match models.Match, earned map[string][]models.AchievementCode) error {
+	awarded, err := s.achievementRepo.GetMatchAchievements(ctx, gameID, match.Id())
+	if err != nil {
+		return fmt.Errorf("error getting achievements of match %s: %v", match.Id(), err)
+	}
+	for _, achievement := range awarded {
+		if slices.Contains(earned[achievement.PlayerID], achievement.Code) {
+			continue
+		}
+		if err := s.achievementRepo.DeleteAchievement(ctx, achievement.ID); err != nil {
+			return fmt.Errorf("error revoking achievement %s for player %s: %v", achievement.Code, achievement.PlayerID, err)
+		}
+		log.Infof("Player %s lost achievement %s in game %s after match %s was scored again", achievement.PlayerID, achievement.Code, gameID, match.Id())
+	}
+	return nil
+}
+
+// GetPlayerAchievements implements AchievementService.GetPlayerAchievements
+func (s *AchievementServiceImpl) GetPlayerAchievements(ctx context.Context, playerID string) ([]*models.Achievement, error) {
+	return s.achievementRepo.GetPlayerAchievements(ctx, playerID)
+}
@@ -0,0 +1,99 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"ligain/backend/models"
+	"ligain/backend/repositories"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type failingScoreObserver struct {
+	calls int
+}
+
+func (o *failingScoreObserver) OnMatchScored(gameID string, game models.Game, match models.Match, scoring *models.MatchScoring) error {
+	o.calls++
+	return fmt.Errorf("observer failed")
+}
+
+func achievementCodes(achievements []*models.Achievement) []models.AchievementCode {
+	codes := make([]models.AchievementCode, 0, len(achievements))
+	for _, achievement := range achievements {
+		codes = append(codes, achievement.Code)
+	}
+	return codes
+}
+
+func TestAchievementService_AwardsAchievementsWhenMatchIsScored(t *testing.T) {
+	service, match, players := setupTestGameService()
+	player1 := players[0]
+	player2 := players[1]
+
+	awardedAt := time.Date(2024, 3, 16, 0, 0, 0, 0, time.UTC)
+	achievementService := NewAchievementServiceWithTimeFunc(repositories.NewInMemoryAchievementRepository(), func() time.Time { return awardedAt })
+	service.AddScoreObserver(achievementService)
+
+	require.NoError(t, service.UpdatePlayerBet(context.Background(), player1, models.NewBet(match, 2, 1), matchTime.Add(-1*time.Hour)))
+
+	finishedMatch := newTestSeasonMatchWithOdds("Team1", "Team2", matchTime, 1)
+	finishedMatch.Finish(2, 1)
+	require.NoError(t, service.HandleMatchUpdates(map[string]models.Match{match.Id(): finishedMatch}))
+
+	achievements, err := achievementService.GetPlayerAchievements(context.Background(), player1.GetID())
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []models.AchievementCode{models.AchievementFirstPerfectScore, models.AchievementFullMatchday}, achievementCodes(achievements))
+	for _, achievement := range achievements {
+		assert.Equal(t, "test-game", achievement.GameID)
+		assert.Equal(t, match.Id(), achievement.MatchID)
+		assert.Equal(t, awardedAt, achievement.AwardedAt)
+	}
+
+	// Player2 didn't bet, so they have nothing
+	achievements, err = achievementService.GetPlayerAchievements(context.Background(), player2.GetID())
+	require.NoError(t, err)
+	assert.Empty(t, achievements)
+}
+
+func TestAchievementService_AchievementsAreAwardedOnce(t *testing.T) {
+	service, match, players := setupTestGameService()
+	player1 := players[0]
+
+	achievementService := NewAchievementService(repositories.NewInMemoryAchievementRepository())
+	require.NoError(t, service.UpdatePlayerBet(context.Background(), player1, models.NewBet(match, 2, 1), matchTime.Add(-1*time.Hour)))
+
+	finishedMatch := newTestSeasonMatchWithOdds("Team1", "Team2", matchTime, 1)
+	finishedMatch.Finish(2, 1)
+	require.NoError(t, service.HandleMatchUpdates(map[string]models.Match{match.Id(): finishedMatch}))
+
+	game, err := service.getGame()
+	require.NoError(t, err)
+	scoring := models.NewMatchScoring("test-game", match.Id(), models.InitialScoringVersion, matchTime)
+	require.NoError(t, achievementService.OnMatchScored("test-game", game, finishedMatch, scoring))
+	require.NoError(t, achievementService.OnMatchScored("test-game", game, finishedMatch, scoring))
+
+	achievements, err := achievementService.GetPlayerAchievements(context.Background(), player1.GetID())
+	require.NoError(t, err)
+	assert.Len(t, achievements, 2)
+}
+
+func TestGameService_FailingScoreObserverDoesNotFailUpdate(t *testing.T) {
+	service, match, players := setupTestGameService()
+	observer := &failingScoreObserver{}
+	service.AddScoreObserver(observer)
+
+	require.NoError(t, service.UpdatePlayerBet(context.Background(), players[0], models.NewBet(match, 2, 1), matchTime.Add(-1*time.Hour)))
+
+	finishedMatch := newTestSeasonMatchWithOdds("Team1", "Team2", matchTime, 1)
+	finishedMatch.Finish(2, 1)
+	require.NoError(t, service.HandleMatchUpdates(map[string]models.Match{match.Id(): finishedMatch}))
+
+	assert.Equal(t, 1, observer.calls)
+	scores, err := service.betRepo.GetScores("test-game")
+	require.NoError(t, err)
+	assert.NotEmpty(t, scores)
+}
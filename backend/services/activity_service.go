@@ -0,0 +1,146 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"ligain/backend/models"
+	"ligain/backend/repositories"
+	"sort"
+	"time"
+)
+
+const (
+	// DefaultActivityPageSize is the number of activities returned when the client doesn't ask for a size
+	DefaultActivityPageSize = 20
+	// MaxActivityPageSize caps the number of activities returned at once
+	MaxActivityPageSize = 100
+)
+
+// ActivityService records the activity feed of the games and exposes it to their players
+type ActivityService interface {
+	ScoreObserver
+	ActivityObserver
+	// GetFeed returns a page of the activity feed of a game, newest first.
+	// Only the activities with an id lower than beforeID are returned, unless beforeID is 0
+	GetFeed(ctx context.Context, gameID string, player models.Player, beforeID int64, limit int) ([]*models.Activity, error)
+}
+
+// ActivityServiceImpl implements ActivityService
+type ActivityServiceImpl struct {
+	activityRepo   repositories.ActivityRepository
+	gamePlayerRepo repositories.GamePlayerRepository
+	timeFunc       func() time.Time
+}
+
+// NewActivityService creates a new ActivityService instance
+func NewActivityService(activityRepo repositories.ActivityRepository, gamePlayerRepo repositories.GamePlayerRepository) *ActivityServiceImpl {
+	return NewActivityServiceWithTimeFunc(activityRepo, gamePlayerRepo, time.Now)
+}
+
+// NewActivityServiceWithTimeFunc creates an ActivityService with a custom time function (for testing)
+func NewActivityServiceWithTimeFunc(activityRepo repositories.ActivityRepository, gamePlayerRepo repositories.GamePlayerRepository, timeFunc func() time.Time) *ActivityServiceImpl {
+	return &ActivityServiceImpl{
+		activityRepo:   activityRepo,
+		gamePlayerRepo: gamePlayerRepo,
+		timeFunc:       timeFunc,
+	}
+}
+
+// OnActivity implements ActivityObserver by saving the activity in the feed of its game
+func (s *ActivityServiceImpl) OnActivity(activity *models.Activity) error {
+	activity.CreatedAt = s.timeFunc()
+	if err := s.activityRepo.SaveActivity(context.Background(), activity); err != nil {
+		return fmt.Errorf("error saving %s activity for game %s: %v", activity.Type, activity.GameID, err)
+	}
+	return nil
+}
+
+// OnMatchScored implements ScoreObserver by recording the points earned on the match,
+// and the new leader if the match changed who is ranked first. A scoring already recorded is skipped.
+// When the match is scored again, the corrected points are recorded and compared with the points of the previous scoring
+func (s *ActivityServiceImpl) OnMatchScored(gameID string, game models.Game, match models.Match, scoring *models.MatchScoring) error {
+	result, exists := game.GetPastResults()[match.Id()]
+	if !exists {
+		return fmt.Errorf("match %s has no result in game %s", match.Id(), gameID)
+	}
+
+	previous, err := s.activityRepo.GetLatestMatchActivity(context.Background(), gameID, match.Id(), models.ActivityMatchScored)
+	if err != nil {
+		return fmt.Errorf("error getting scored activity of match %s: %v", match.Id(), err)
+	}
+	previousScores := make(map[string]int)
+	if previous != nil {
+		// The activities recorded before the scorings had versions have none, they belong to the first scoring
+		if max(previous.Details.ScoringVersion, models.InitialScoringVersion) >= scoring.Version {
+			return nil
+		}
+		previousScores = previous.Details.Points
+	}
+
+	scored := models.NewMatchActivity(gameID, models.ActivityMatchScored, match)
+	scored.Details.Points = result.Scores
+	scored.Details.ScoringVersion = scoring.Version
+	if err := s.OnActivity(scored); err != nil {
+		return err
+	}
+
+	previousPoints := game.GetPlayersPoints()
+	for playerID, points := range result.Scores {
+		previousPoints[playerID] -= points
+	}
+	for playerID, points := range previousScores {
+		previousPoints[playerID] += points
+	}
+	previousLeaders := leaderIDs(game.RankPlayers(previousPoints))
+	currentLeaders := leaderIDs(game.GetStandings())
+	if len(currentLeaders) == 0 || equalIDs(previousLeaders, currentLeaders) {
+		return nil
+	}
+
+	leaderChanged := models.NewMatchActivity(gameID, models.ActivityLeaderChanged, match)
+	leaderChanged.Details.LeaderIDs = currentLeaders
+	return s.OnActivity(leaderChanged)
+}
+
+// GetFeed implements ActivityService.GetFeed
+func (s *ActivityServiceImpl) GetFeed(ctx context.Context, gameID string, player models.Player, beforeID int64, limit int) ([]*models.Activity, error) {
+	isInGame, err := s.gamePlayerRepo.IsPlayerInGame(ctx, gameID, player.GetID())
+	if err != nil {
+		return nil, fmt.Errorf("error checking game access: %v", err)
+	}
+	if !isInGame {
+		return nil, ErrPlayerNotInGame
+	}
+
+	if limit <= 0 {
+		limit = DefaultActivityPageSize
+	}
+	if limit > MaxActivityPageSize {
+		limit = MaxActivityPageSize
+	}
+	return s.activityRepo.GetActivities(ctx, gameID, beforeID, limit)
+}
+
+// leaderIDs returns the sorted ids of the players ranked first, ignoring a leaderboard where nobody scored yet
+func leaderIDs(standings []models.Standing) []string {
+	ids := make([]string, 0)
+	for _, standing := range standings {
+		if standing.Rank == 1 && standing.Points > 0 {
+			ids = append(ids, standing.Player.GetID())
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func equalIDs(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,131 @@
+package services
+
+import (
+	"context"
+	"ligain/backend/models"
+	"ligain/backend/repositories"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func activityTypes(activities []*models.Activity) []models.ActivityType {
+	types := make([]models.ActivityType, 0, len(activities))
+	for _, activity := range activities {
+		types = append(types, activity.Type)
+	}
+	return types
+}
+
+func TestActivityService_RecordsGameActivity(t *testing.T) {
+	service, match, players := setupTestGameService()
+	player1 := players[0]
+	player2 := players[1]
+
+	recordedAt := time.Date(2024, 3, 16, 0, 0, 0, 0, time.UTC)
+	activityService := NewActivityServiceWithTimeFunc(repositories.NewInMemoryActivityRepository(), service.gamePlayerRepo, func() time.Time { return recordedAt })
+	service.AddScoreObserver(activityService)
+	service.AddActivityObserver(activityService)
+
+	require.NoError(t, service.UpdatePlayerBet(context.Background(), player1, models.NewBet(match, 2, 1), matchTime.Add(-1*time.Hour)))
+	require.NoError(t, service.UpdatePlayerBet(context.Background(), player2, models.NewBet(match, 0, 1), matchTime.Add(-1*time.Hour)))
+
+	kickedOff := newTestSeasonMatchWithOdds("Team1", "Team2", matchTime, 1)
+	kickedOff.Start()
+	require.NoError(t, service.HandleMatchUpdates(map[string]models.Match{match.Id(): kickedOff}))
+
+	scored := newTestSeasonMatchWithOdds("Team1", "Team2", matchTime, 1)
+	scored.Start()
+	scored.HomeGoals = 1
+	require.NoError(t, service.HandleMatchUpdates(map[string]models.Match{match.Id(): scored}))
+
+	finished := newTestSeasonMatchWithOdds("Team1", "Team2", matchTime, 1)
+	finished.Finish(2, 1)
+	require.NoError(t, service.HandleMatchUpdates(map[string]models.Match{match.Id(): finished}))
+
+	feed, err := activityService.GetFeed(context.Background(), "test-game", player1, 0, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []models.ActivityType{
+		models.ActivityLeaderChanged,
+		models.ActivityMatchScored,
+		models.ActivityGoal,
+		models.ActivityGoal,
+		models.ActivityMatchStarted,
+		models.ActivityBetPlaced,
+		models.ActivityBetPlaced,
+	}, activityTypes(feed))
+
+	leaderChanged := feed[0]
+	assert.Equal(t, []string{player1.GetID()}, leaderChanged.Details.LeaderIDs)
+	assert.Equal(t, recordedAt, leaderChanged.CreatedAt)
+
+	matchScored := feed[1]
+	assert.Equal(t, match.Id(), matchScored.MatchID)
+	assert.Equal(t, 2, *matchScored.Details.HomeGoals)
+	assert.Equal(t, 1, *matchScored.Details.AwayGoals)
+	assert.Greater(t, matchScored.Details.Points[player1.GetID()], 0)
+	assert.Equal(t, 0, matchScored.Details.Points[player2.GetID()])
+
+	betPlaced := feed[len(feed)-1]
+	assert.Equal(t, player1.GetID(), betPlaced.PlayerID)
+	assert.Equal(t, "Team1", betPlaced.Details.HomeTeam)
+	assert.Nil(t, betPlaced.Details.HomeGoals, "the scoreline of a bet must not be revealed")
+	assert.Nil(t, betPlaced.Details.AwayGoals)
+}
+
+func TestActivityService_FeedPagination(t *testing.T) {
+	gamePlayerRepo := repositories.NewInMemoryGamePlayerRepository(repositories.NewInMemoryPlayerRepository())
+	require.NoError(t, gamePlayerRepo.AddPlayerToGame(context.Background(), "game1", "player1"))
+	activityService := NewActivityService(repositories.NewInMemoryActivityRepository(), gamePlayerRepo)
+
+	player := &models.PlayerData{ID: "player1", Name: "Player 1"}
+	for i := 0; i < 5; i++ {
+		require.NoError(t, activityService.OnActivity(models.NewPlayerActivity("game1", models.ActivityPlayerJoined, player)))
+	}
+	require.NoError(t, activityService.OnActivity(models.NewPlayerActivity("game2", models.ActivityPlayerJoined, player)))
+
+	firstPage, err := activityService.GetFeed(context.Background(), "game1", player, 0, 3)
+	require.NoError(t, err)
+	require.Len(t, firstPage, 3)
+	assert.Greater(t, firstPage[0].ID, firstPage[1].ID)
+
+	secondPage, err := activityService.GetFeed(context.Background(), "game1", player, firstPage[2].ID, 3)
+	require.NoError(t, err)
+	require.Len(t, secondPage, 2)
+	assert.Less(t, secondPage[0].ID, firstPage[2].ID)
+
+	_, err = activityService.GetFeed(context.Background(), "game1", &models.PlayerData{ID: "outsider"}, 0, 3)
+	assert.ErrorIs(t, err, ErrPlayerNotInGame)
+}
+
+func TestGameMembershipService_RecordsJoinAndLeaveActivity(t *testing.T) {
+	mockGamePlayerRepo := new(MockGamePlayerRepository)
+	mockGameRepo := new(MockGameRepository)
+	mockGameCodeRepo := new(MockGameCodeRepository)
+	mockBetRepo := new(MockBetRepository)
+
+	registry := setupMembershipTestRegistry(t, mockGameRepo, mockBetRepo, mockGamePlayerRepo, nil)
+	service := NewGameMembershipService(&PassThroughUnitOfWork{}, mockGamePlayerRepo, mockGameRepo, mockGameCodeRepo, registry, nil)
+	activityRepo := repositories.NewInMemoryActivityRepository()
+	service.AddActivityObserver(NewActivityService(activityRepo, mockGamePlayerRepo))
+
+	player := &models.PlayerData{ID: "player1", Name: "Test Player"}
+	other := &models.PlayerData{ID: "player2", Name: "Other Player"}
+
+	mockGamePlayerRepo.On("IsPlayerInGame", mock.Anything, "game1", "player1").Return(false, nil).Once()
+	mockGamePlayerRepo.On("AddPlayerToGame", mock.Anything, "game1", "player1").Return(nil)
+	require.NoError(t, service.AddPlayerToGame("game1", player))
+
+	mockGamePlayerRepo.On("IsPlayerInGame", mock.Anything, "game1", "player1").Return(true, nil)
+	mockGamePlayerRepo.On("RemovePlayerFromGame", mock.Anything, "game1", "player1").Return(nil)
+	mockGamePlayerRepo.On("GetPlayersInGame", mock.Anything, "game1").Return([]models.Player{other}, nil)
+	require.NoError(t, service.RemovePlayerFromGame("game1", player))
+
+	activities, err := activityRepo.GetActivities(context.Background(), "game1", 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []models.ActivityType{models.ActivityPlayerLeft, models.ActivityPlayerJoined}, activityTypes(activities))
+	assert.Equal(t, "Test Player", activities[0].PlayerName)
+}
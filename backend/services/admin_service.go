@@ -0,0 +1,550 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"ligain/backend/models"
+	"ligain/backend/repositories"
+	"ligain/backend/rules"
+	"sort"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultAdminSearchLimit is the number of results of a search of the operator API when none is asked
+	DefaultAdminSearchLimit = 50
+	// MaxAdminSearchLimit is the largest number of results a search of the operator API returns
+	MaxAdminSearchLimit = 200
+)
+
+var (
+	// ErrAdminGameNotFound is returned when an admin acts on a game that doesn't exist
+	ErrAdminGameNotFound = errors.New("game not found")
+	// ErrAdminMatchNotFound is returned when a match is part of no running game
+	ErrAdminMatchNotFound = errors.New("match is not part of any running game")
+	// ErrInvalidMatchResult is returned when a forced result has negative goals
+	ErrInvalidMatchResult = errors.New("goals can't be negative")
+	// ErrGameAlreadyFinished is returned when finishing a finished game
+	ErrGameAlreadyFinished = errors.New("game is already finished")
+	// ErrGameNotFinished is returned when unfinishing a game still in progress
+	ErrGameNotFinished = errors.New("game is not finished")
+	// ErrInvalidPlayerRole is returned when giving a player an unknown role
+	ErrInvalidPlayerRole = errors.New("role must be player or admin")
+	// ErrAdminSelfDemotion is returned when an admin removes their own admin role, which could leave no admin at all
+	ErrAdminSelfDemotion = errors.New("admins can't remove their own admin role")
+	// ErrGameHistoryNotFound is returned when a game can't be rebuilt at a point of its history, which is older than its first snapshot
+	ErrGameHistoryNotFound = errors.New("game history not found at this point")
+)
+
+// AdminGameDetails is the full state of a game, as shown to the operators.
+// Unlike what the players see, every bet is visible, even before kickoff
+type AdminGameDetails struct {
+	Summary         *models.AdminGameSummary
+	Players         []models.Player
+	Standings       []models.Standing
+	IncomingMatches map[string]*models.MatchResult
+	PastMatches     map[string]*models.MatchResult
+}
+
+// AdminMatchGames is a match as the running games know it, and the games it's part of
+type AdminMatchGames struct {
+	Match models.Match
+	// IncomingIn are the games where the match isn't scored yet
+	IncomingIn []string
+	// ScoredIn are the games where the match is already scored
+	ScoredIn []string
+}
+
+// AdminService lets the operators inspect and fix the production state.
+// Every change is written to the audit log before it's done: a change that can't be logged is refused,
+// and the log holds every attempt, even the ones that failed afterwards
+type AdminService interface {
+	// SearchPlayers returns the players whose id is the query, or whose name or email contains it
+	SearchPlayers(ctx context.Context, query string, limit int) ([]*models.PlayerData, error)
+	// SearchGames returns the games whose id or join code is the query, or whose name contains it, newest first
+	SearchGames(ctx context.Context, query string, limit int) ([]*models.AdminGameSummary, error)
+	// GetGameDetails returns the players, standings, matches, bets and scores of a game
+	GetGameDetails(ctx context.Context, gameID string) (*AdminGameDetails, error)
+	// GetGameEvents returns the events of the history of a game with a sequence greater than afterSequence, oldest first
+	GetGameEvents(ctx context.Context, gameID string, afterSequence int64, limit int) ([]*models.GameEvent, error)
+	// GetGameDetailsAt works like GetGameDetails, with the game as it was once the events up to sequence were applied
+	GetGameDetailsAt(ctx context.Context, gameID string, sequence int64) (*AdminGameDetails, error)
+	// GetMatchGames returns the running games a match is part of, so that the effect of ForceMatchResult can be checked first
+	GetMatchGames(ctx context.Context, matchID string) (*AdminMatchGames, error)
+	// ForceMatchResult finishes a match with the given score in every running game, and returns the ids of those games.
+	// The games where the match is already scored are scored again with the new result
+	ForceMatchResult(ctx context.Context, admin *models.PlayerData, matchID string, homeGoals, awayGoals int) ([]string, error)
+	// RescoreGame scores every past match of a game again, with the current bets and scorer
+	RescoreGame(ctx context.Context, admin *models.PlayerData, gameID string) error
+	// FinishGame ends a game, which stops following its matches
+	FinishGame(ctx context.Context, admin *models.PlayerData, gameID string) error
+	// UnfinishGame puts a finished game back in progress, and follows its matches again
+	UnfinishGame(ctx context.Context, admin *models.PlayerData, gameID string) error
+	// ResetJoinCode replaces the join code of a game, and returns the new one
+	ResetJoinCode(ctx context.Context, admin *models.PlayerData, gameID string) (string, error)
+	// SetPlayerRole changes the role of a player
+	SetPlayerRole(ctx context.Context, admin *models.PlayerData, playerID string, role string) error
+	// GetAuditLog returns the audit log, newest first, optionally only about one target. See AdminRepository.GetAuditEntries
+	GetAuditLog(ctx context.Context, targetType string, targetID string, beforeID int64, limit int) ([]*models.AdminAuditEntry, error)
+}
+
+// AdminServiceImpl implements AdminService
+type AdminServiceImpl struct {
+	adminRepo    repositories.AdminRepository
+	gameRepo     repositories.GameRepository
+	eventRepo    repositories.GameEventRepository
+	gameCodeRepo repositories.GameCodeRepository
+	betRepo      repositories.BetRepository
+	matchRepo    repositories.MatchRepository
+	registry     GameServiceRegistryInterface
+	watcher      MatchWatcherService
+	scoring      matchScorer
+	timeFunc     func() time.Time
+	// scoreObservers are notified of the matches scored again, with the corrected scoring
+	scoreObservers []ScoreObserver
+}
+
+// NewAdminService creates a new AdminService instance. The watcher can be nil
+func NewAdminService(
+	uow repositories.UnitOfWork,
+	adminRepo repositories.AdminRepository,
+	gameRepo repositories.GameRepository,
+	eventRepo repositories.GameEventRepository,
+	gameCodeRepo repositories.GameCodeRepository,
+	betRepo repositories.BetRepository,
+	matchRepo repositories.MatchRepository,
+	registry GameServiceRegistryInterface,
+	watcher MatchWatcherService,
+	scoreObservers ...ScoreObserver,
+) *AdminServiceImpl {
+	return NewAdminServiceWithTimeFunc(uow, adminRepo, gameRepo, eventRepo, gameCodeRepo, betRepo, matchRepo, registry, watcher, time.Now, scoreObservers...)
+}
+
+// NewAdminServiceWithTimeFunc creates an AdminService with a custom time function (for testing)
+func NewAdminServiceWithTimeFunc(
+	uow repositories.UnitOfWork,
+	adminRepo repositories.AdminRepository,
+	gameRepo repositories.GameRepository,
+	eventRepo repositories.GameEventRepository,
+	gameCodeRepo repositories.GameCodeRepository,
+	betRepo repositories.BetRepository,
+	matchRepo repositories.MatchRepository,
+	registry GameServiceRegistryInterface,
+	watcher MatchWatcherService,
+	timeFunc func() time.Time,
+	scoreObservers ...ScoreObserver,
+) *AdminServiceImpl {
+	return &AdminServiceImpl{
+		adminRepo:      adminRepo,
+		gameRepo:       gameRepo,
+		eventRepo:      eventRepo,
+		gameCodeRepo:   gameCodeRepo,
+		betRepo:        betRepo,
+		matchRepo:      matchRepo,
+		scoring:        matchScorer{uow: uow, gameRepo: gameRepo, betRepo: betRepo},
+		registry:       registry,
+		watcher:        watcher,
+		timeFunc:       timeFunc,
+		scoreObservers: scoreObservers,
+	}
+}
+
+func (s *AdminServiceImpl) SearchPlayers(ctx context.Context, query string, limit int) ([]*models.PlayerData, error) {
+	return s.adminRepo.SearchPlayers(ctx, query, adminSearchLimit(limit))
+}
+
+func (s *AdminServiceImpl) SearchGames(ctx context.Context, query string, limit int) ([]*models.AdminGameSummary, error) {
+	return s.adminRepo.SearchGames(ctx, query, adminSearchLimit(limit))
+}
+
+func (s *AdminServiceImpl) GetGameDetails(ctx context.Context, gameID string) (*AdminGameDetails, error) {
+	game, err := s.getGame(gameID)
+	if err != nil {
+		return nil, err
+	}
+	return s.gameDetails(gameID, game)
+}
+
+func (s *AdminServiceImpl) GetGameEvents(ctx context.Context, gameID string, afterSequence int64, limit int) ([]*models.GameEvent, error) {
+	if _, err := s.getGame(gameID); err != nil {
+		return nil, err
+	}
+	return s.eventRepo.GetEvents(ctx, gameID, afterSequence, 0, adminSearchLimit(limit))
+}
+
+// GetGameDetailsAt implements AdminService. The game is replayed from the latest snapshot taken at or before the sequence,
+// with the current names of the players
+func (s *AdminServiceImpl) GetGameDetailsAt(ctx context.Context, gameID string, sequence int64) (*AdminGameDetails, error) {
+	current, err := s.getGame(gameID)
+	if err != nil {
+		return nil, err
+	}
+	if sequence <= 0 {
+		return nil, ErrGameHistoryNotFound
+	}
+
+	snapshot, err := s.eventRepo.GetLatestSnapshot(ctx, gameID, sequence)
+	if err != nil {
+		return nil, fmt.Errorf("error getting game snapshot: %v", err)
+	}
+	if snapshot == nil {
+		return nil, ErrGameHistoryNotFound
+	}
+	events, err := s.eventRepo.GetEvents(ctx, gameID, snapshot.Sequence, sequence, 0)
+	if err != nil {
+		return nil, fmt.Errorf("error getting game events: %v", err)
+	}
+
+	players := make(map[string]models.Player)
+	for _, player := range current.GetPlayers() {
+		players[player.GetID()] = player
+	}
+	game, err := rules.ReplayGame(snapshot.State, events, players, &rules.ScorerOriginal{})
+	if err != nil {
+		return nil, fmt.Errorf("error replaying game: %v", err)
+	}
+	return s.gameDetails(gameID, game)
+}
+
+// gameDetails returns the details of a game as shown to the operators
+func (s *AdminServiceImpl) gameDetails(gameID string, game models.Game) (*AdminGameDetails, error) {
+	players := game.GetPlayers()
+	summary := &models.AdminGameSummary{
+		ID:              gameID,
+		Name:            game.GetName(),
+		CompetitionName: game.GetCompetitionName(),
+		SeasonYear:      game.GetSeasonYear(),
+		Status:          string(game.GetGameStatus()),
+		PlayerCount:     len(players),
+	}
+	gameCode, err := s.gameCodeRepo.GetGameCodeByGameID(gameID)
+	if err != nil && !errors.Is(err, repositories.ErrGameCodeNotFound) {
+		return nil, fmt.Errorf("error getting join code: %v", err)
+	}
+	if gameCode != nil {
+		summary.Code = gameCode.Code
+	}
+
+	return &AdminGameDetails{
+		Summary:         summary,
+		Players:         players,
+		Standings:       game.GetStandings(),
+		IncomingMatches: game.GetIncomingMatchesWithAllBets(),
+		PastMatches:     game.GetPastResults(),
+	}, nil
+}
+
+// ForceMatchResult implements AdminService. The score observers are notified for the games where the match was incoming,
+// like when the watcher reports the result, and with the corrected scoring for the games where it was already scored
+func (s *AdminServiceImpl) ForceMatchResult(ctx context.Context, admin *models.PlayerData, matchID string, homeGoals, awayGoals int) ([]string, error) {
+	if homeGoals < 0 || awayGoals < 0 {
+		return nil, ErrInvalidMatchResult
+	}
+
+	matchGames, games, err := s.findMatchGames(matchID)
+	if err != nil {
+		return nil, err
+	}
+	seasonMatch, ok := matchGames.Match.(*models.SeasonMatch)
+	if !ok {
+		return nil, fmt.Errorf("unsupported match type %T", matchGames.Match)
+	}
+
+	err = s.audit(ctx, admin, models.AdminActionForceMatchResult, models.AdminTargetMatch, matchID, map[string]any{
+		"homeGoals":         homeGoals,
+		"awayGoals":         awayGoals,
+		"previousStatus":    seasonMatch.GetStatus(),
+		"previousHomeGoals": seasonMatch.GetHomeGoals(),
+		"previousAwayGoals": seasonMatch.GetAwayGoals(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	forced := *seasonMatch
+	forced.Finish(homeGoals, awayGoals)
+	if err := s.matchRepo.SaveMatch(&forced); err != nil {
+		return nil, fmt.Errorf("error saving match: %v", err)
+	}
+	// The provider mustn't overwrite the forced result. The leader, when it's another instance, leaves the match
+	// once it sees it saved as finished
+	if s.watcher != nil {
+		if err := s.watcher.Unwatch(matchID); err != nil {
+			log.WithError(err).Warnf("Failed to stop watching match %s", matchID)
+		}
+	}
+
+	var updateErrors []error
+	for _, gameID := range matchGames.IncomingIn {
+		gameService, exists := s.registry.Get(gameID)
+		if !exists {
+			updateErrors = append(updateErrors, fmt.Errorf("game %s: game service not found", gameID))
+			continue
+		}
+		if err := gameService.HandleMatchUpdates(map[string]models.Match{matchID: &forced}); err != nil {
+			updateErrors = append(updateErrors, fmt.Errorf("game %s: %w", gameID, err))
+		}
+	}
+	for _, gameID := range matchGames.ScoredIn {
+		if err := s.rescoreForcedMatch(ctx, gameID, games[gameID], &forced); err != nil {
+			updateErrors = append(updateErrors, fmt.Errorf("game %s: %w", gameID, err))
+		}
+	}
+
+	return append(matchGames.IncomingIn, matchGames.ScoredIn...), errors.Join(updateErrors...)
+}
+
+// rescoreForcedMatch scores a forced result again in a game where the match was already scored, and saves the game
+func (s *AdminServiceImpl) rescoreForcedMatch(ctx context.Context, gameID string, game models.Game, forced models.Match) error {
+	defer lockGame(gameID)()
+
+	if err := s.rescoreMatch(ctx, gameID, game, forced); err != nil {
+		return err
+	}
+	if err := s.gameRepo.SaveWithId(gameID, game); err != nil {
+		return fmt.Errorf("error saving game: %v", err)
+	}
+	return nil
+}
+
+func (s *AdminServiceImpl) GetMatchGames(ctx context.Context, matchID string) (*AdminMatchGames, error) {
+	matchGames, _, err := s.findMatchGames(matchID)
+	return matchGames, err
+}
+
+// findMatchGames looks for a match in the running games, and returns them along with the games it's part of
+func (s *AdminServiceImpl) findMatchGames(matchID string) (*AdminMatchGames, map[string]models.Game, error) {
+	games, err := s.gameRepo.GetAllGames()
+	if err != nil {
+		return nil, nil, fmt.Errorf("error getting running games: %v", err)
+	}
+
+	matchGames := &AdminMatchGames{IncomingIn: make([]string, 0), ScoredIn: make([]string, 0)}
+	for gameID, game := range games {
+		if incoming, err := game.GetMatchById(matchID); err == nil {
+			matchGames.Match = incoming
+			matchGames.IncomingIn = append(matchGames.IncomingIn, gameID)
+		} else if past, exists := game.GetPastResults()[matchID]; exists {
+			matchGames.Match = past.Match
+			matchGames.ScoredIn = append(matchGames.ScoredIn, gameID)
+		}
+	}
+	if matchGames.Match == nil {
+		return nil, nil, ErrAdminMatchNotFound
+	}
+	sort.Strings(matchGames.IncomingIn)
+	sort.Strings(matchGames.ScoredIn)
+	return matchGames, games, nil
+}
+
+// RescoreGame implements AdminService. Like ForceMatchResult, it notifies the score observers with the new scoring of each match
+func (s *AdminServiceImpl) RescoreGame(ctx context.Context, admin *models.PlayerData, gameID string) error {
+	defer lockGame(gameID)()
+
+	game, err := s.getGame(gameID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.audit(ctx, admin, models.AdminActionRescoreGame, models.AdminTargetGame, gameID, nil); err != nil {
+		return err
+	}
+
+	for matchID, result := range game.GetPastResults() {
+		if err := s.rescoreMatch(ctx, gameID, game, result.Match); err != nil {
+			return fmt.Errorf("error rescoring match %s: %w", matchID, err)
+		}
+	}
+
+	if err := s.gameRepo.SaveWithId(gameID, game); err != nil {
+		return fmt.Errorf("error saving game: %v", err)
+	}
+	return nil
+}
+
+func (s *AdminServiceImpl) FinishGame(ctx context.Context, admin *models.PlayerData, gameID string) error {
+	defer lockGame(gameID)()
+
+	game, err := s.getGame(gameID)
+	if err != nil {
+		return err
+	}
+	if game.IsFinished() {
+		return ErrGameAlreadyFinished
+	}
+
+	if err := s.audit(ctx, admin, models.AdminActionFinishGame, models.AdminTargetGame, gameID, nil); err != nil {
+		return err
+	}
+
+	game.Finish()
+	if err := s.gameRepo.SaveWithId(gameID, game); err != nil {
+		return fmt.Errorf("error saving finished game: %v", err)
+	}
+
+	if s.watcher != nil {
+		if err := s.watcher.Unsubscribe(gameID); err != nil {
+			log.WithError(err).Warnf("Failed to unsubscribe game %s from watcher", gameID)
+		}
+	}
+	return nil
+}
+
+func (s *AdminServiceImpl) UnfinishGame(ctx context.Context, admin *models.PlayerData, gameID string) error {
+	game, err := s.getGame(gameID)
+	if err != nil {
+		return err
+	}
+	if !game.IsFinished() {
+		return ErrGameNotFinished
+	}
+
+	if err := s.audit(ctx, admin, models.AdminActionUnfinishGame, models.AdminTargetGame, gameID, nil); err != nil {
+		return err
+	}
+
+	unlock := lockGame(gameID)
+	game.Reopen()
+	err = s.gameRepo.SaveWithId(gameID, game)
+	unlock()
+	if err != nil {
+		return fmt.Errorf("error saving reopened game: %v", err)
+	}
+
+	// The games finished before the last restart aren't in the registry anymore
+	gameService, exists := s.registry.Get(gameID)
+	if !exists {
+		if _, err := s.registry.Create(gameID); err != nil {
+			return fmt.Errorf("error creating game service: %v", err)
+		}
+		return nil
+	}
+	if s.watcher != nil {
+		if err := s.watcher.Subscribe(gameService); err != nil {
+			return fmt.Errorf("error subscribing game to watcher: %v", err)
+		}
+	}
+	return nil
+}
+
+func (s *AdminServiceImpl) ResetJoinCode(ctx context.Context, admin *models.PlayerData, gameID string) (string, error) {
+	if _, err := s.getGame(gameID); err != nil {
+		return "", err
+	}
+
+	code, err := generateUniqueGameCode(s.gameCodeRepo)
+	if err != nil {
+		return "", err
+	}
+
+	details := map[string]any{"code": code}
+	if previous, err := s.gameCodeRepo.GetGameCodeByGameID(gameID); err == nil {
+		details["previousCode"] = previous.Code
+	}
+	if err := s.audit(ctx, admin, models.AdminActionResetJoinCode, models.AdminTargetGame, gameID, details); err != nil {
+		return "", err
+	}
+
+	if err := s.gameCodeRepo.DeleteGameCodeByGameID(gameID); err != nil {
+		return "", fmt.Errorf("error deleting join code: %v", err)
+	}
+	if err := s.gameCodeRepo.CreateGameCode(models.NewGameCode(gameID, code, gameCodeExpiresAt(s.timeFunc()))); err != nil {
+		return "", fmt.Errorf("error creating join code: %v", err)
+	}
+	return code, nil
+}
+
+func (s *AdminServiceImpl) SetPlayerRole(ctx context.Context, admin *models.PlayerData, playerID string, role string) error {
+	if !models.IsValidPlayerRole(role) {
+		return ErrInvalidPlayerRole
+	}
+	if playerID == admin.ID && role != models.PlayerRoleAdmin {
+		return ErrAdminSelfDemotion
+	}
+
+	if err := s.audit(ctx, admin, models.AdminActionSetPlayerRole, models.AdminTargetPlayer, playerID, map[string]any{"role": role}); err != nil {
+		return err
+	}
+
+	return s.adminRepo.SetPlayerRole(ctx, playerID, role)
+}
+
+func (s *AdminServiceImpl) GetAuditLog(ctx context.Context, targetType string, targetID string, beforeID int64, limit int) ([]*models.AdminAuditEntry, error) {
+	return s.adminRepo.GetAuditEntries(ctx, targetType, targetID, beforeID, adminSearchLimit(limit))
+}
+
+// getGame loads a game, finished or not
+func (s *AdminServiceImpl) getGame(gameID string) (models.Game, error) {
+	game, err := s.gameRepo.GetGame(gameID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrAdminGameNotFound, err)
+	}
+	return game, nil
+}
+
+// rescoreMatch scores a past match of a game again and saves the new points, as the next scoring of the match.
+// The game itself is saved by the caller
+func (s *AdminServiceImpl) rescoreMatch(ctx context.Context, gameID string, game models.Game, match models.Match) error {
+	versions, err := s.betRepo.GetScoringVersions(ctx, gameID)
+	if err != nil {
+		return err
+	}
+
+	scoring := models.NewMatchScoring(gameID, match.Id(), versions[match.Id()]+1, s.timeFunc())
+	applied, err := s.scoring.apply(ctx, game, scoring, match, func() (map[string]int, error) {
+		return game.RescoreMatch(match)
+	})
+	if err != nil {
+		return err
+	}
+	if !applied {
+		return fmt.Errorf("match %s was scored again meanwhile", match.Id())
+	}
+	log.Infof("Match %s of game %s scored again with result %d - %d", match.Id(), gameID, match.GetHomeGoals(), match.GetAwayGoals())
+
+	// Scores are already saved, so a failing observer must not fail the update
+	for _, observer := range s.scoreObservers {
+		if err := observer.OnMatchScored(gameID, game, match, scoring); err != nil {
+			log.Errorf("Error notifying score observer for match %v: %v", match.Id(), err)
+		}
+	}
+	return nil
+}
+
+// audit writes an action to the audit log before it's done
+func (s *AdminServiceImpl) audit(ctx context.Context, admin *models.PlayerData, action models.AdminAction, targetType string, targetID string, details map[string]any) error {
+	entry := &models.AdminAuditEntry{
+		AdminID:    admin.ID,
+		AdminName:  admin.Name,
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Details:    details,
+		CreatedAt:  s.timeFunc(),
+	}
+	if err := s.adminRepo.SaveAuditEntry(ctx, entry); err != nil {
+		return fmt.Errorf("error writing audit log: %v", err)
+	}
+
+	log.WithFields(log.Fields{
+		"adminId":  admin.ID,
+		"action":   action,
+		"target":   targetType,
+		"targetId": targetID,
+	}).Info("Admin action")
+	return nil
+}
+
+// adminSearchLimit returns the number of results to return for the limit asked by an operator
+func adminSearchLimit(limit int) int {
+	if limit <= 0 {
+		return DefaultAdminSearchLimit
+	}
+	if limit > MaxAdminSearchLimit {
+		return MaxAdminSearchLimit
+	}
+	return limit
+}
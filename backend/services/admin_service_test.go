@@ -0,0 +1,408 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"ligain/backend/models"
+	"ligain/backend/repositories"
+	"ligain/backend/rules"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// adminTestFixture is a running game with two players betting on two matches, behind an AdminService
+type adminTestFixture struct {
+	service      *AdminServiceImpl
+	adminRepo    *repositories.InMemoryAdminRepository
+	gameRepo     repositories.GameRepository
+	eventRepo    *repositories.InMemoryGameEventRepository
+	gameCodeRepo repositories.GameCodeRepository
+	betRepo      *repositories.InMemoryBetRepository
+	registry     *GameServiceRegistry
+	gameID       string
+	match        *models.SeasonMatch
+	players      []models.Player
+	admin        *models.PlayerData
+}
+
+func setupAdminTest(t *testing.T) *adminTestFixture {
+	ctx := context.Background()
+	playerRepo := repositories.NewInMemoryPlayerRepository()
+	gameRepo := repositories.NewInMemoryGameRepository()
+	eventRepo := repositories.NewInMemoryGameEventRepository()
+	gameCodeRepo := repositories.NewInMemoryGameCodeRepository()
+	gamePlayerRepo := repositories.NewInMemoryGamePlayerRepository(playerRepo)
+	betRepo := repositories.NewInMemoryBetRepository()
+	adminRepo := repositories.NewInMemoryAdminRepository(playerRepo, gameRepo, gameCodeRepo, gamePlayerRepo)
+
+	match := newTestSeasonMatchWithOdds("Team1", "Team2", matchTime, 1)
+	otherMatch := newTestSeasonMatchWithOdds("Team3", "Team4", matchTime, 1)
+	players := []models.Player{newTestPlayer("Player1"), newTestPlayer("Player2")}
+	game := rules.NewFreshGame("2024", "Premier League", "Test Game", players, []models.Match{match, otherMatch}, &scorerMock{})
+	require.NoError(t, game.AddPlayerBet(players[0], models.NewBet(match, 2, 1)))
+	require.NoError(t, game.AddPlayerBet(players[1], models.NewBet(match, 1, 1)))
+
+	gameID, err := gameRepo.CreateGame(game)
+	require.NoError(t, err)
+	for _, player := range players {
+		require.NoError(t, playerRepo.UpdatePlayer(ctx, &models.PlayerData{ID: player.GetID(), Name: player.GetName()}))
+		require.NoError(t, gamePlayerRepo.AddPlayerToGame(ctx, gameID, player.GetID()))
+	}
+	require.NoError(t, gameCodeRepo.CreateGameCode(models.NewGameCode(gameID, "ABCD", gameCodeExpiresAt(frozenTime.AddDate(10, 0, 0)))))
+
+	admin := &models.PlayerData{ID: "admin", Name: "Admin", Role: models.PlayerRoleAdmin}
+	require.NoError(t, playerRepo.UpdatePlayer(ctx, admin))
+
+	registry, err := NewGameServiceRegistry(gameRepo, betRepo, gamePlayerRepo, nil)
+	require.NoError(t, err)
+
+	service := NewAdminServiceWithTimeFunc(repositories.NewNoopUnitOfWork(), adminRepo, gameRepo, eventRepo, gameCodeRepo, betRepo, repositories.NewInMemoryMatchRepository(), registry, nil, func() time.Time { return frozenTime })
+	return &adminTestFixture{
+		service:      service,
+		adminRepo:    adminRepo,
+		gameRepo:     gameRepo,
+		eventRepo:    eventRepo,
+		gameCodeRepo: gameCodeRepo,
+		betRepo:      betRepo,
+		registry:     registry,
+		gameID:       gameID,
+		match:        match,
+		players:      players,
+		admin:        admin,
+	}
+}
+
+func (f *adminTestFixture) auditActions(t *testing.T) []models.AdminAction {
+	entries, err := f.adminRepo.GetAuditEntries(context.Background(), "", "", 0, 100)
+	require.NoError(t, err)
+	actions := make([]models.AdminAction, 0, len(entries))
+	for _, entry := range entries {
+		actions = append(actions, entry.Action)
+	}
+	return actions
+}
+
+func TestAdminService_GetGameDetailsShowsEveryBet(t *testing.T) {
+	f := setupAdminTest(t)
+
+	details, err := f.service.GetGameDetails(context.Background(), f.gameID)
+
+	require.NoError(t, err)
+	assert.Equal(t, "Test Game", details.Summary.Name)
+	assert.Equal(t, "ABCD", details.Summary.Code)
+	assert.Equal(t, 2, details.Summary.PlayerCount)
+	require.Contains(t, details.IncomingMatches, f.match.Id())
+	assert.Len(t, details.IncomingMatches[f.match.Id()].Bets, 2)
+
+	_, err = f.service.GetGameDetails(context.Background(), "unknown")
+	assert.ErrorIs(t, err, ErrAdminGameNotFound)
+}
+
+func TestAdminService_GetGameDetailsAt(t *testing.T) {
+	f := setupAdminTest(t)
+	ctx := context.Background()
+	game, err := f.gameRepo.GetGame(f.gameID)
+	require.NoError(t, err)
+	require.NoError(t, f.eventRepo.SaveSnapshot(ctx, &models.GameSnapshot{GameID: f.gameID, State: game.(models.EventSourcedGame).State()}))
+	require.NoError(t, f.eventRepo.AppendEvents(ctx, f.gameID, []*models.GameEvent{
+		models.NewBetPlacedEvent("Player1", models.NewBet(f.match, 3, 0)),
+		models.NewPlayerGameEvent(models.GameEventPlayerLeft, "Player2"),
+	}))
+
+	details, err := f.service.GetGameDetailsAt(ctx, f.gameID, 1)
+
+	require.NoError(t, err)
+	assert.Len(t, details.Players, 2)
+	assert.Equal(t, "Player2", details.Players[1].GetName(), "the names are the current ones")
+	bet := details.IncomingMatches[f.match.Id()].Bets["Player1"]
+	require.NotNil(t, bet)
+	assert.Equal(t, 3, bet.PredictedHomeGoals)
+
+	details, err = f.service.GetGameDetailsAt(ctx, f.gameID, 2)
+	require.NoError(t, err)
+	assert.Len(t, details.Players, 1)
+
+	events, err := f.service.GetGameEvents(ctx, f.gameID, 1, 0)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, models.GameEventPlayerLeft, events[0].Type)
+
+	_, err = f.service.GetGameDetailsAt(ctx, "unknown", 1)
+	assert.ErrorIs(t, err, ErrAdminGameNotFound)
+}
+
+func TestAdminService_GetGameDetailsAtWithoutHistory(t *testing.T) {
+	f := setupAdminTest(t)
+
+	_, err := f.service.GetGameDetailsAt(context.Background(), f.gameID, 1)
+
+	assert.ErrorIs(t, err, ErrGameHistoryNotFound)
+}
+
+func TestAdminService_ForceMatchResult(t *testing.T) {
+	f := setupAdminTest(t)
+	ctx := context.Background()
+
+	gameIDs, err := f.service.ForceMatchResult(ctx, f.admin, f.match.Id(), 2, 1)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{f.gameID}, gameIDs)
+	game, err := f.gameRepo.GetGame(f.gameID)
+	require.NoError(t, err)
+	assert.Contains(t, game.GetPastResults(), f.match.Id())
+	assert.Equal(t, 500, game.GetPlayersPoints()["Player1"])
+	assert.Equal(t, 0, game.GetPlayersPoints()["Player2"])
+
+	entries, err := f.adminRepo.GetAuditEntries(ctx, models.AdminTargetMatch, f.match.Id(), 0, 10)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, models.AdminActionForceMatchResult, entries[0].Action)
+	assert.Equal(t, "admin", entries[0].AdminID)
+	assert.Equal(t, frozenTime, entries[0].CreatedAt)
+}
+
+func TestAdminService_ForceMatchResultCorrectsScoredMatch(t *testing.T) {
+	f := setupAdminTest(t)
+	ctx := context.Background()
+	_, err := f.service.ForceMatchResult(ctx, f.admin, f.match.Id(), 2, 1)
+	require.NoError(t, err)
+
+	// The result was wrong, the match actually ended in a draw
+	gameIDs, err := f.service.ForceMatchResult(ctx, f.admin, f.match.Id(), 1, 1)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{f.gameID}, gameIDs)
+	game, err := f.gameRepo.GetGame(f.gameID)
+	require.NoError(t, err)
+	assert.Equal(t, 0, game.GetPlayersPoints()["Player1"])
+	assert.Equal(t, 500, game.GetPlayersPoints()["Player2"])
+	scores, err := f.betRepo.GetScores(f.gameID)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"Player1": 0, "Player2": 500}, scores[f.match.Id()])
+}
+
+func TestAdminService_CorrectionNotifiesScoreObservers(t *testing.T) {
+	f := setupAdminTest(t)
+	ctx := context.Background()
+	ratingService := NewRatingServiceWithTimeFunc(repositories.NewInMemoryRatingRepository(), func() time.Time { return frozenTime })
+	achievementService := NewAchievementServiceWithTimeFunc(repositories.NewInMemoryAchievementRepository(), func() time.Time { return frozenTime })
+	activityRepo := repositories.NewInMemoryActivityRepository()
+	activityService := NewActivityServiceWithTimeFunc(activityRepo, repositories.NewInMemoryGamePlayerRepository(repositories.NewInMemoryPlayerRepository()), func() time.Time { return frozenTime })
+	gameService, exists := f.registry.Get(f.gameID)
+	require.True(t, exists)
+	for _, observer := range []ScoreObserver{ratingService, achievementService, activityService} {
+		gameService.(*GameServiceImpl).AddScoreObserver(observer)
+	}
+	f.service = NewAdminServiceWithTimeFunc(repositories.NewNoopUnitOfWork(), f.adminRepo, f.gameRepo, f.eventRepo, f.gameCodeRepo, f.betRepo, repositories.NewInMemoryMatchRepository(), f.registry, nil, func() time.Time { return frozenTime }, ratingService, achievementService, activityService)
+
+	_, err := f.service.ForceMatchResult(ctx, f.admin, f.match.Id(), 2, 1)
+	require.NoError(t, err)
+	rating1, err := ratingService.GetPlayerRating(ctx, "Player1")
+	require.NoError(t, err)
+	assert.Greater(t, rating1.Rating, models.InitialRating)
+
+	// The result was wrong, the match actually ended in a draw
+	_, err = f.service.ForceMatchResult(ctx, f.admin, f.match.Id(), 1, 1)
+	require.NoError(t, err)
+
+	// The change of the first result is replaced, not added to
+	ratings := make(map[string]*models.PlayerRating)
+	for _, playerID := range []string{"Player1", "Player2"} {
+		rating, err := ratingService.GetPlayerRating(ctx, playerID)
+		require.NoError(t, err)
+		assert.Equal(t, 1, rating.MatchesRated)
+		ratings[playerID] = rating
+	}
+	assert.Less(t, ratings["Player1"].Rating, models.InitialRating)
+	assert.InDelta(t, 2*models.InitialRating-rating1.Rating, ratings["Player1"].Rating, 0.001)
+	assert.InDelta(t, 2*models.InitialRating, ratings["Player1"].Rating+ratings["Player2"].Rating, 0.001)
+
+	// The perfect score moved to the other player
+	achievements, err := achievementService.GetPlayerAchievements(ctx, "Player1")
+	require.NoError(t, err)
+	assert.NotContains(t, achievementCodes(achievements), models.AchievementFirstPerfectScore)
+	achievements, err = achievementService.GetPlayerAchievements(ctx, "Player2")
+	require.NoError(t, err)
+	assert.Contains(t, achievementCodes(achievements), models.AchievementFirstPerfectScore)
+
+	scored, err := activityRepo.GetLatestMatchActivity(ctx, f.gameID, f.match.Id(), models.ActivityMatchScored)
+	require.NoError(t, err)
+	assert.Equal(t, 2, scored.Details.ScoringVersion)
+	assert.Equal(t, map[string]int{"Player1": 0, "Player2": 500}, scored.Details.Points)
+	leaderChanged, err := activityRepo.GetLatestMatchActivity(ctx, f.gameID, f.match.Id(), models.ActivityLeaderChanged)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Player2"}, leaderChanged.Details.LeaderIDs)
+
+	// A scoring handled twice changes nothing more
+	game, err := f.gameRepo.GetGame(f.gameID)
+	require.NoError(t, err)
+	scoring := models.NewMatchScoring(f.gameID, f.match.Id(), 2, frozenTime)
+	for _, observer := range []ScoreObserver{ratingService, achievementService, activityService} {
+		require.NoError(t, observer.OnMatchScored(f.gameID, game, game.GetPastResults()[f.match.Id()].Match, scoring))
+	}
+	rating2, err := ratingService.GetPlayerRating(ctx, "Player2")
+	require.NoError(t, err)
+	assert.Equal(t, ratings["Player2"], rating2)
+	activities, err := activityRepo.GetActivities(ctx, f.gameID, 0, 10)
+	require.NoError(t, err)
+	assert.Len(t, activities, 4)
+}
+
+func TestAdminService_GetMatchGames(t *testing.T) {
+	f := setupAdminTest(t)
+	ctx := context.Background()
+
+	matchGames, err := f.service.GetMatchGames(ctx, f.match.Id())
+	require.NoError(t, err)
+	assert.Equal(t, []string{f.gameID}, matchGames.IncomingIn)
+	assert.Empty(t, matchGames.ScoredIn)
+
+	_, err = f.service.ForceMatchResult(ctx, f.admin, f.match.Id(), 2, 1)
+	require.NoError(t, err)
+	matchGames, err = f.service.GetMatchGames(ctx, f.match.Id())
+	require.NoError(t, err)
+	assert.Empty(t, matchGames.IncomingIn)
+	assert.Equal(t, []string{f.gameID}, matchGames.ScoredIn)
+	assert.True(t, matchGames.Match.IsFinished())
+
+	_, err = f.service.GetMatchGames(ctx, "unknown")
+	assert.ErrorIs(t, err, ErrAdminMatchNotFound)
+}
+
+func TestAdminService_ForceMatchResultErrors(t *testing.T) {
+	f := setupAdminTest(t)
+	ctx := context.Background()
+
+	_, err := f.service.ForceMatchResult(ctx, f.admin, f.match.Id(), -1, 0)
+	assert.ErrorIs(t, err, ErrInvalidMatchResult)
+
+	_, err = f.service.ForceMatchResult(ctx, f.admin, "unknown", 1, 0)
+	assert.ErrorIs(t, err, ErrAdminMatchNotFound)
+
+	// Nothing was done, so nothing was logged
+	assert.Empty(t, f.auditActions(t))
+}
+
+func TestAdminService_RescoreGame(t *testing.T) {
+	f := setupAdminTest(t)
+	ctx := context.Background()
+	_, err := f.service.ForceMatchResult(ctx, f.admin, f.match.Id(), 2, 1)
+	require.NoError(t, err)
+
+	// Scores lost in the database are written again
+	require.NoError(t, f.betRepo.SaveScore(f.gameID, f.match, f.players[0], 0))
+
+	require.NoError(t, f.service.RescoreGame(ctx, f.admin, f.gameID))
+
+	scores, err := f.betRepo.GetScores(f.gameID)
+	require.NoError(t, err)
+	assert.Equal(t, 500, scores[f.match.Id()]["Player1"])
+	assert.Equal(t, []models.AdminAction{models.AdminActionRescoreGame, models.AdminActionForceMatchResult}, f.auditActions(t))
+
+	// The match was scored when its result was forced, then scored again
+	versions, err := f.betRepo.GetScoringVersions(ctx, f.gameID)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{f.match.Id(): 2}, versions)
+}
+
+func TestAdminService_FinishAndUnfinishGame(t *testing.T) {
+	f := setupAdminTest(t)
+	ctx := context.Background()
+
+	assert.ErrorIs(t, f.service.UnfinishGame(ctx, f.admin, f.gameID), ErrGameNotFinished)
+
+	require.NoError(t, f.service.FinishGame(ctx, f.admin, f.gameID))
+	game, err := f.gameRepo.GetGame(f.gameID)
+	require.NoError(t, err)
+	assert.True(t, game.IsFinished())
+	assert.ErrorIs(t, f.service.FinishGame(ctx, f.admin, f.gameID), ErrGameAlreadyFinished)
+
+	// A game finished before a restart isn't in the registry anymore
+	f.registry.Unregister(f.gameID)
+	require.NoError(t, f.service.UnfinishGame(ctx, f.admin, f.gameID))
+	assert.False(t, game.IsFinished())
+	_, exists := f.registry.Get(f.gameID)
+	assert.True(t, exists)
+
+	assert.Equal(t, []models.AdminAction{models.AdminActionUnfinishGame, models.AdminActionFinishGame}, f.auditActions(t))
+}
+
+func TestAdminService_ResetJoinCode(t *testing.T) {
+	f := setupAdminTest(t)
+	ctx := context.Background()
+	// Game codes expire against the wall clock
+	f.service.timeFunc = time.Now
+
+	code, err := f.service.ResetJoinCode(ctx, f.admin, f.gameID)
+
+	require.NoError(t, err)
+	assert.Len(t, code, 4)
+	assert.NotEqual(t, "ABCD", code)
+	_, err = f.gameCodeRepo.GetGameCodeByCode("ABCD")
+	assert.ErrorIs(t, err, repositories.ErrGameCodeNotFound)
+	gameCode, err := f.gameCodeRepo.GetGameCodeByGameID(f.gameID)
+	require.NoError(t, err)
+	assert.Equal(t, code, gameCode.Code)
+
+	entries, err := f.adminRepo.GetAuditEntries(ctx, models.AdminTargetGame, f.gameID, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "ABCD", entries[0].Details["previousCode"])
+	assert.Equal(t, code, entries[0].Details["code"])
+}
+
+func TestAdminService_SetPlayerRole(t *testing.T) {
+	f := setupAdminTest(t)
+	ctx := context.Background()
+
+	require.NoError(t, f.service.SetPlayerRole(ctx, f.admin, "Player1", models.PlayerRoleAdmin))
+	players, err := f.service.SearchPlayers(ctx, "player1", 0)
+	require.NoError(t, err)
+	require.Len(t, players, 1)
+	assert.True(t, players[0].IsAdmin())
+
+	assert.ErrorIs(t, f.service.SetPlayerRole(ctx, f.admin, "Player1", "owner"), ErrInvalidPlayerRole)
+	assert.ErrorIs(t, f.service.SetPlayerRole(ctx, f.admin, f.admin.ID, models.PlayerRolePlayer), ErrAdminSelfDemotion)
+	assert.ErrorIs(t, f.service.SetPlayerRole(ctx, f.admin, "unknown", models.PlayerRoleAdmin), repositories.ErrPlayerNotFound)
+}
+
+func TestAdminService_SearchGames(t *testing.T) {
+	f := setupAdminTest(t)
+	ctx := context.Background()
+
+	for _, query := range []string{"", "test", "abcd", f.gameID} {
+		games, err := f.service.SearchGames(ctx, query, 0)
+		require.NoError(t, err)
+		require.Len(t, games, 1, "query %q", query)
+		assert.Equal(t, f.gameID, games[0].ID)
+		assert.Equal(t, 2, games[0].PlayerCount)
+	}
+
+	games, err := f.service.SearchGames(ctx, "other", 0)
+	require.NoError(t, err)
+	assert.Empty(t, games)
+}
+
+// failingAuditRepository fails to write to the audit log
+type failingAuditRepository struct {
+	*repositories.InMemoryAdminRepository
+}
+
+func (r *failingAuditRepository) SaveAuditEntry(ctx context.Context, entry *models.AdminAuditEntry) error {
+	return errors.New("database is down")
+}
+
+func TestAdminService_RefusesActionsThatCantBeLogged(t *testing.T) {
+	f := setupAdminTest(t)
+	f.service.adminRepo = &failingAuditRepository{f.adminRepo}
+
+	err := f.service.FinishGame(context.Background(), f.admin, f.gameID)
+
+	assert.Error(t, err)
+	game, err := f.gameRepo.GetGame(f.gameID)
+	require.NoError(t, err)
+	assert.False(t, game.IsFinished())
+}
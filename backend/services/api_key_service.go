@@ -0,0 +1,279 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"ligain/backend/models"
+	"ligain/backend/repositories"
+	"sync"
+	"time"
+)
+
+const (
+	// APIKeyCacheTTL is how long an instance trusts the keys it already looked up.
+	// A revoked key can still be used for that long on the instances that cached it
+	APIKeyCacheTTL = time.Minute
+	// apiKeyPrefix starts the generated keys, so that they can be recognized in code and logs
+	apiKeyPrefix = "lgn_"
+	// apiKeyDisplayedPrefixLength is the length of the beginning of a key kept to tell it apart
+	apiKeyDisplayedPrefixLength = 8
+)
+
+var (
+	// ErrInvalidAPIKey is returned when an API key is unknown, expired or revoked
+	ErrInvalidAPIKey = errors.New("invalid API key")
+	// ErrAPIKeyNotFound is returned when an API key or a client with no active key is managed
+	ErrAPIKeyNotFound = errors.New("api key not found")
+)
+
+// APIKeyService authenticates the clients of the API, and manages their keys
+type APIKeyService interface {
+	// Authenticate returns the active key matching the raw key given by a client
+	Authenticate(ctx context.Context, rawKey string) (*models.APIKey, error)
+	// CreateKey creates a new key for a client, and returns the raw key which is shown only once
+	CreateKey(ctx context.Context, name string, scopes []string, rateLimitPerMinute int) (string, *models.APIKey, error)
+	// ImportKey stores an existing raw key for a client, unless it's already known
+	ImportKey(ctx context.Context, name string, rawKey string, scopes []string, rateLimitPerMinute int) (*models.APIKey, error)
+	// RotateKey creates a new key for a client with the scopes and the limit of its latest key.
+	// The previous keys of the client stay valid for the overlap, while the new one is rolled out
+	RotateKey(ctx context.Context, name string, overlap time.Duration) (string, *models.APIKey, error)
+	// RevokeKey stops accepting a key right away
+	RevokeKey(ctx context.Context, keyID string) error
+	// ListKeys returns every key, ordered by client
+	ListKeys(ctx context.Context) ([]*models.APIKey, error)
+}
+
+// APIKeyServiceImpl implements APIKeyService
+type APIKeyServiceImpl struct {
+	repo     repositories.APIKeyRepository
+	timeFunc func() time.Time
+
+	mu    sync.Mutex
+	cache map[string]cachedAPIKey // key hash -> key
+}
+
+// cachedAPIKey is a key looked up by Authenticate
+type cachedAPIKey struct {
+	key       *models.APIKey
+	fetchedAt time.Time
+}
+
+// NewAPIKeyService creates a new APIKeyService instance
+func NewAPIKeyService(repo repositories.APIKeyRepository) *APIKeyServiceImpl {
+	return NewAPIKeyServiceWithTimeFunc(repo, time.Now)
+}
+
+// NewAPIKeyServiceWithTimeFunc creates an APIKeyService with a custom time function (for testing)
+func NewAPIKeyServiceWithTimeFunc(repo repositories.APIKeyRepository, timeFunc func() time.Time) *APIKeyServiceImpl {
+	return &APIKeyServiceImpl{
+		repo:     repo,
+		timeFunc: timeFunc,
+		cache:    make(map[string]cachedAPIKey),
+	}
+}
+
+// Authenticate implements APIKeyService. Keys are cached for APIKeyCacheTTL, so that most requests don't hit the database
+func (s *APIKeyServiceImpl) Authenticate(ctx context.Context, rawKey string) (*models.APIKey, error) {
+	if rawKey == "" {
+		return nil, ErrInvalidAPIKey
+	}
+	now := s.timeFunc()
+	keyHash := hashAPIKey(rawKey)
+
+	s.mu.Lock()
+	cached, found := s.cache[keyHash]
+	s.mu.Unlock()
+
+	key := cached.key
+	if !found || now.Sub(cached.fetchedAt) >= APIKeyCacheTTL {
+		var err error
+		key, err = s.repo.GetAPIKeyByHash(ctx, keyHash)
+		if err != nil {
+			return nil, fmt.Errorf("error getting api key: %v", err)
+		}
+
+		s.mu.Lock()
+		if key != nil {
+			s.cache[keyHash] = cachedAPIKey{key: key, fetchedAt: now}
+		} else {
+			delete(s.cache, keyHash)
+		}
+		s.mu.Unlock()
+	}
+
+	if key == nil || !key.IsActive(now) {
+		return nil, ErrInvalidAPIKey
+	}
+	return key, nil
+}
+
+// CreateKey implements APIKeyService
+func (s *APIKeyServiceImpl) CreateKey(ctx context.Context, name string, scopes []string, rateLimitPerMinute int) (string, *models.APIKey, error) {
+	if name == "" {
+		return "", nil, errors.New("a client name is required")
+	}
+	if err := validateAPIKeyScopes(scopes); err != nil {
+		return "", nil, err
+	}
+
+	rawKey, err := generateAPIKey()
+	if err != nil {
+		return "", nil, fmt.Errorf("error generating api key: %v", err)
+	}
+	key, err := s.storeKey(ctx, name, rawKey, scopes, rateLimitPerMinute)
+	if err != nil {
+		return "", nil, err
+	}
+	return rawKey, key, nil
+}
+
+// ImportKey implements APIKeyService. It brings the key given to the apps before the key store existed into it
+func (s *APIKeyServiceImpl) ImportKey(ctx context.Context, name string, rawKey string, scopes []string, rateLimitPerMinute int) (*models.APIKey, error) {
+	if err := validateAPIKeyScopes(scopes); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repo.GetAPIKeyByHash(ctx, hashAPIKey(rawKey))
+	if err != nil {
+		return nil, fmt.Errorf("error getting api key: %v", err)
+	}
+	if existing != nil {
+		return existing, nil
+	}
+	return s.storeKey(ctx, name, rawKey, scopes, rateLimitPerMinute)
+}
+
+// storeKey stores the hash of a raw key
+func (s *APIKeyServiceImpl) storeKey(ctx context.Context, name string, rawKey string, scopes []string, rateLimitPerMinute int) (*models.APIKey, error) {
+	prefix := rawKey
+	if len(prefix) > apiKeyDisplayedPrefixLength {
+		prefix = prefix[:apiKeyDisplayedPrefixLength]
+	}
+
+	key := &models.APIKey{
+		Name:               name,
+		Prefix:             prefix,
+		KeyHash:            hashAPIKey(rawKey),
+		Scopes:             scopes,
+		RateLimitPerMinute: rateLimitPerMinute,
+		CreatedAt:          s.timeFunc(),
+	}
+	if err := s.repo.CreateAPIKey(ctx, key); err != nil {
+		return nil, fmt.Errorf("error creating api key: %v", err)
+	}
+	return key, nil
+}
+
+// RotateKey implements APIKeyService. The previous keys expiring sooner than the overlap keep their expiry
+func (s *APIKeyServiceImpl) RotateKey(ctx context.Context, name string, overlap time.Duration) (string, *models.APIKey, error) {
+	keys, err := s.repo.GetAPIKeysByName(ctx, name)
+	if err != nil {
+		return "", nil, fmt.Errorf("error getting api keys: %v", err)
+	}
+
+	now := s.timeFunc()
+	active := make([]*models.APIKey, 0, len(keys))
+	for _, key := range keys {
+		if key.IsActive(now) {
+			active = append(active, key)
+		}
+	}
+	if len(active) == 0 {
+		return "", nil, ErrAPIKeyNotFound
+	}
+	latest := active[len(active)-1]
+
+	rawKey, key, err := s.CreateKey(ctx, name, latest.Scopes, latest.RateLimitPerMinute)
+	if err != nil {
+		return "", nil, err
+	}
+
+	overlapEnd := now.Add(overlap)
+	for _, previous := range active {
+		if previous.ExpiresAt != nil && previous.ExpiresAt.Before(overlapEnd) {
+			continue
+		}
+		if err := s.repo.SetAPIKeyExpiry(ctx, previous.ID, overlapEnd); err != nil {
+			return "", nil, fmt.Errorf("error setting api key expiry: %v", err)
+		}
+	}
+	return rawKey, key, nil
+}
+
+// RevokeKey implements APIKeyService
+func (s *APIKeyServiceImpl) RevokeKey(ctx context.Context, keyID string) error {
+	err := s.repo.RevokeAPIKey(ctx, keyID, s.timeFunc())
+	if errors.Is(err, repositories.ErrAPIKeyNotFound) {
+		return ErrAPIKeyNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("error revoking api key: %v", err)
+	}
+
+	// This instance stops accepting the key right away, the others once their cache expires
+	s.mu.Lock()
+	for keyHash, cached := range s.cache {
+		if cached.key.ID == keyID {
+			delete(s.cache, keyHash)
+		}
+	}
+	s.mu.Unlock()
+	return nil
+}
+
+// ListKeys implements APIKeyService
+func (s *APIKeyServiceImpl) ListKeys(ctx context.Context) ([]*models.APIKey, error) {
+	keys, err := s.repo.ListAPIKeys(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error listing api keys: %v", err)
+	}
+	return keys, nil
+}
+
+// validateAPIKeyScopes checks that a key has known scopes, and at least one
+func validateAPIKeyScopes(scopes []string) error {
+	if len(scopes) == 0 {
+		return errors.New("at least one scope is required")
+	}
+	for _, scope := range scopes {
+		switch scope {
+		case models.APIKeyScopeRead, models.APIKeyScopeWrite, models.APIKeyScopeAdmin:
+		default:
+			return fmt.Errorf("unknown scope %q", scope)
+		}
+	}
+	return nil
+}
+
+// generateAPIKey generates a key carrying 256 random bits
+func generateAPIKey() (string, error) {
+	keyBytes := make([]byte, 32)
+	if _, err := rand.Read(keyBytes); err != nil {
+		return "", err
+	}
+	return apiKeyPrefix + base64.RawURLEncoding.EncodeToString(keyBytes), nil
+}
+
+// hashAPIKey hashes an API key. Generated keys are random enough for a plain hash
+func hashAPIKey(rawKey string) string {
+	hash := sha256.Sum256([]byte(rawKey))
+	return hex.EncodeToString(hash[:])
+}
+
+type apiKeyNameKey struct{}
+
+// WithAPIKeyName attaches the name of the client that made a request to its context
+func WithAPIKeyName(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, apiKeyNameKey{}, name)
+}
+
+// APIKeyNameFromContext returns the client name set with WithAPIKeyName, or an empty name
+func APIKeyNameFromContext(ctx context.Context) string {
+	name, _ := ctx.Value(apiKeyNameKey{}).(string)
+	return name
+}
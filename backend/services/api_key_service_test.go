@@ -0,0 +1,146 @@
+package services
+
+import (
+	"context"
+	"ligain/backend/models"
+	"ligain/backend/repositories"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var appScopes = []string{models.APIKeyScopeRead, models.APIKeyScopeWrite}
+
+func TestAPIKeyService_CreateAndAuthenticate(t *testing.T) {
+	ctx := context.Background()
+	service := NewAPIKeyServiceWithTimeFunc(repositories.NewInMemoryAPIKeyRepository(), func() time.Time { return frozenTime })
+
+	rawKey, key, err := service.CreateKey(ctx, "android", appScopes, 600)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(rawKey, apiKeyPrefix))
+	assert.Equal(t, rawKey[:apiKeyDisplayedPrefixLength], key.Prefix)
+	assert.NotContains(t, key.KeyHash, rawKey)
+
+	authenticated, err := service.Authenticate(ctx, rawKey)
+	require.NoError(t, err)
+	assert.Equal(t, key.ID, authenticated.ID)
+	assert.Equal(t, "android", authenticated.Name)
+	assert.Equal(t, 600, authenticated.RateLimitPerMinute)
+
+	_, err = service.Authenticate(ctx, "lgn_unknown")
+	assert.ErrorIs(t, err, ErrInvalidAPIKey)
+	_, err = service.Authenticate(ctx, "")
+	assert.ErrorIs(t, err, ErrInvalidAPIKey)
+}
+
+func TestAPIKeyService_Validation(t *testing.T) {
+	ctx := context.Background()
+	service := NewAPIKeyService(repositories.NewInMemoryAPIKeyRepository())
+
+	_, _, err := service.CreateKey(ctx, "", appScopes, 0)
+	assert.Error(t, err)
+	_, _, err = service.CreateKey(ctx, "partner", nil, 0)
+	assert.Error(t, err)
+	_, _, err = service.CreateKey(ctx, "partner", []string{"everything"}, 0)
+	assert.Error(t, err)
+}
+
+func TestAPIKeyService_ImportKey(t *testing.T) {
+	ctx := context.Background()
+	repo := repositories.NewInMemoryAPIKeyRepository()
+	service := NewAPIKeyService(repo)
+
+	first, err := service.ImportKey(ctx, "default", "legacy-key", appScopes, 0)
+	require.NoError(t, err)
+	// Importing again at the next start keeps the same key
+	second, err := service.ImportKey(ctx, "default", "legacy-key", appScopes, 0)
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, second.ID)
+
+	keys, err := service.ListKeys(ctx)
+	require.NoError(t, err)
+	assert.Len(t, keys, 1)
+
+	authenticated, err := service.Authenticate(ctx, "legacy-key")
+	require.NoError(t, err)
+	assert.Equal(t, "default", authenticated.Name)
+}
+
+func TestAPIKeyService_RotateKey(t *testing.T) {
+	ctx := context.Background()
+	now := frozenTime
+	service := NewAPIKeyServiceWithTimeFunc(repositories.NewInMemoryAPIKeyRepository(), func() time.Time { return now })
+
+	oldKey, _, err := service.CreateKey(ctx, "ios", appScopes, 600)
+	require.NoError(t, err)
+
+	now = frozenTime.Add(time.Minute)
+	newKey, rotated, err := service.RotateKey(ctx, "ios", 30*24*time.Hour)
+	require.NoError(t, err)
+	assert.NotEqual(t, oldKey, newKey)
+	assert.Equal(t, appScopes, rotated.Scopes)
+	assert.Equal(t, 600, rotated.RateLimitPerMinute)
+
+	// Both keys work during the overlap
+	_, err = service.Authenticate(ctx, oldKey)
+	require.NoError(t, err)
+	_, err = service.Authenticate(ctx, newKey)
+	require.NoError(t, err)
+
+	// Only the new one once it's over
+	now = frozenTime.Add(time.Minute + 30*24*time.Hour)
+	_, err = service.Authenticate(ctx, oldKey)
+	assert.ErrorIs(t, err, ErrInvalidAPIKey)
+	_, err = service.Authenticate(ctx, newKey)
+	require.NoError(t, err)
+
+	_, _, err = service.RotateKey(ctx, "unknown", time.Hour)
+	assert.ErrorIs(t, err, ErrAPIKeyNotFound)
+}
+
+func TestAPIKeyService_RevokeKey(t *testing.T) {
+	ctx := context.Background()
+	service := NewAPIKeyService(repositories.NewInMemoryAPIKeyRepository())
+
+	rawKey, key, err := service.CreateKey(ctx, "partner", []string{models.APIKeyScopeRead}, 60)
+	require.NoError(t, err)
+	_, err = service.Authenticate(ctx, rawKey)
+	require.NoError(t, err)
+
+	// The cached key is dropped right away on the instance revoking it
+	require.NoError(t, service.RevokeKey(ctx, key.ID))
+	_, err = service.Authenticate(ctx, rawKey)
+	assert.ErrorIs(t, err, ErrInvalidAPIKey)
+
+	assert.ErrorIs(t, service.RevokeKey(ctx, "unknown"), ErrAPIKeyNotFound)
+}
+
+func TestAPIKeyService_Cache(t *testing.T) {
+	ctx := context.Background()
+	now := frozenTime
+	repo := repositories.NewInMemoryAPIKeyRepository()
+	service := NewAPIKeyServiceWithTimeFunc(repo, func() time.Time { return now })
+	otherInstance := NewAPIKeyServiceWithTimeFunc(repo, func() time.Time { return now })
+
+	rawKey, key, err := service.CreateKey(ctx, "partner", []string{models.APIKeyScopeRead}, 60)
+	require.NoError(t, err)
+	_, err = service.Authenticate(ctx, rawKey)
+	require.NoError(t, err)
+
+	// Another instance revokes the key, this one keeps accepting it until its cache expires
+	require.NoError(t, otherInstance.RevokeKey(ctx, key.ID))
+	_, err = service.Authenticate(ctx, rawKey)
+	require.NoError(t, err)
+
+	now = frozenTime.Add(APIKeyCacheTTL)
+	_, err = service.Authenticate(ctx, rawKey)
+	assert.ErrorIs(t, err, ErrInvalidAPIKey)
+}
+
+func TestAPIKeyNameContext(t *testing.T) {
+	assert.Empty(t, APIKeyNameFromContext(context.Background()))
+	assert.Equal(t, "ios", APIKeyNameFromContext(WithAPIKeyName(context.Background(), "ios")))
+}
@@ -3,54 +3,149 @@ package services
 import (
 	"context"
 	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base32"
 	"encoding/hex"
 	"errors"
 	"fmt"
 	"ligain/backend/models"
+	"ligain/backend/ratelimit"
 	"ligain/backend/repositories"
+	"strings"
 	"time"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+// DefaultRefreshTokenTTL is how long a refresh token stays valid. Every refresh issues a new one
+const DefaultRefreshTokenTTL = 30 * 24 * time.Hour
+
+const (
+	// MaxGuestSignInAttempts is the number of secrets a client can try on a guest account within GuestSignInWindow
+	MaxGuestSignInAttempts = 5
+	// GuestSignInWindow is the period over which the guest sign-ins of a client are counted
+	GuestSignInWindow = 15 * time.Minute
+	// AccessTokenRenewalWindow is how long after its expiry an access token can still be renewed, for the app versions
+	// that don't know refresh tokens. The X-App-Version header telling them apart is sent by the client, so an older
+	// token, like a stolen one, needs a sign-in whatever the header says
+	AccessTokenRenewalWindow = 24 * time.Hour
+	// AccountDeletionGracePeriod is how long a deleted account can be restored by signing in again,
+	// before its personal data is purged
+	AccountDeletionGracePeriod = 30 * 24 * time.Hour
 )
 
+// ErrSessionNotFound is returned when a session doesn't exist, or doesn't belong to the player
+var ErrSessionNotFound = errors.New("session not found")
+
 // AuthServiceInterface defines the interface for authentication services
 type AuthServiceInterface interface {
 	Authenticate(ctx context.Context, req *models.AuthRequest) (*models.AuthResponse, error)
-	AuthenticateGuest(ctx context.Context, displayName string) (*models.AuthResponse, error)
+	// AuthenticateGuest signs a guest in by name. Signing in again as an existing guest needs the secret given at creation
+	AuthenticateGuest(ctx context.Context, displayName string, secret string) (*models.AuthResponse, error)
+	// RegenerateGuestSecret replaces the secret of a guest, and returns the new one
+	RegenerateGuestSecret(ctx context.Context, playerID string) (string, error)
+	// LinkProvider attaches a Google or Apple identity to a guest player, keeping their games, bets and scores.
+	// Every session of the guest is ended and new tokens are returned
+	LinkProvider(ctx context.Context, playerID string, req *models.AuthRequest) (*models.AuthResponse, error)
+	// ValidateToken verifies an access token. The returned player only holds the id and the name carried by the token
 	ValidateToken(ctx context.Context, token string) (*models.PlayerData, error)
-	RefreshToken(ctx context.Context, expiredToken string) (*models.AuthResponse, error)
-	GetAuthTokenDirectly(ctx context.Context, token string) (*models.AuthToken, error)
+	// RefreshToken rotates a refresh token, and returns a new access token with a new refresh token
+	RefreshToken(ctx context.Context, refreshToken string) (*models.AuthResponse, error)
 	RefreshTokenByPlayerID(ctx context.Context, playerID string) (*models.AuthResponse, error)
+	// RenewAccessToken signs a new access token for an expired one whose session is still active.
+	// It serves the app versions that don't know refresh tokens, the response has no refresh token
+	RenewAccessToken(ctx context.Context, expiredToken string) (*models.AuthResponse, error)
+	// GetPlayer returns the up to date data of an authenticated player
+	GetPlayer(ctx context.Context, playerID string) (*models.PlayerData, error)
+	// Logout revokes a refresh token and every token rotated from the same sign-in
 	Logout(ctx context.Context, token string) error
+	// ListSessions returns the signed in devices of a player, flagging the one of the given access token
+	ListSessions(ctx context.Context, playerID string, currentToken string) ([]*models.Session, error)
+	// RevokeSession signs a player out of one of their devices
+	RevokeSession(ctx context.Context, playerID string, sessionID string) error
+	// RevokeOtherSessions signs a player out of every device but the one of the given access token
+	RevokeOtherSessions(ctx context.Context, playerID string, currentToken string) (int, error)
 	CleanupExpiredTokens(ctx context.Context) error
 	GetOrCreatePlayer(ctx context.Context, verifiedUser map[string]interface{}, provider string, displayName string) (*models.PlayerData, error)
 	UpdateDisplayName(ctx context.Context, playerID string, newDisplayName string) (*models.PlayerData, error)
+	// DeleteAccount anonymizes a player and signs them out everywhere. Their bets and scores are kept for the other players,
+	// and signing in again within AccountDeletionGracePeriod restores the account
 	DeleteAccount(ctx context.Context, playerID string) error
 }
 
 // AuthService implements authentication with Google or Apple
 type AuthService struct {
-	playerRepo    repositories.PlayerRepository
-	oauthVerifier OAuthVerifierInterface
-	timeFunc      func() time.Time // For testing - allows injection of frozen time
+	playerRepo          repositories.PlayerRepository
+	refreshTokenRepo    repositories.RefreshTokenRepository
+	guestCredentialRepo repositories.GuestCredentialRepository
+	accountDeletionRepo repositories.AccountDeletionRepository
+	signer              *AccessTokenSigner
+	oauthVerifier       OAuthVerifierInterface
+	guestAttempts       ratelimit.Store
+	timeFunc            func() time.Time // For testing - allows injection of frozen time
 }
 
 // NewAuthService creates a new AuthService instance
+// Access tokens are signed with a random key and refresh tokens and guest secrets are kept in memory,
+// so nothing survives a restart: use NewAuthServiceWithTokens to share them between instances
 func NewAuthService(playerRepo repositories.PlayerRepository) *AuthService {
-	return &AuthService{
-		playerRepo:    playerRepo,
-		oauthVerifier: NewOAuthVerifier(),
-		timeFunc:      time.Now,
-	}
+	return NewAuthServiceWithTimeFunc(playerRepo, NewOAuthVerifier(), time.Now)
 }
 
 // NewAuthServiceWithTimeFunc creates an AuthService with a custom time function for testing
 func NewAuthServiceWithTimeFunc(playerRepo repositories.PlayerRepository, oauthVerifier OAuthVerifierInterface, timeFunc func() time.Time) *AuthService {
+	return NewAuthServiceWithTokens(
+		playerRepo,
+		repositories.NewInMemoryRefreshTokenRepository(),
+		repositories.NewInMemoryGuestCredentialRepository(),
+		repositories.NewInMemoryAccountDeletionRepository(),
+		newEphemeralSigner(),
+		oauthVerifier,
+		timeFunc,
+	)
+}
+
+// NewAuthServiceWithTokens creates an AuthService storing its refresh tokens, guest secrets and account deletions
+// in the given repositories, and signing its access tokens with the given signer
+func NewAuthServiceWithTokens(
+	playerRepo repositories.PlayerRepository,
+	refreshTokenRepo repositories.RefreshTokenRepository,
+	guestCredentialRepo repositories.GuestCredentialRepository,
+	accountDeletionRepo repositories.AccountDeletionRepository,
+	signer *AccessTokenSigner,
+	oauthVerifier OAuthVerifierInterface,
+	timeFunc func() time.Time,
+) *AuthService {
 	return &AuthService{
-		playerRepo:    playerRepo,
-		oauthVerifier: oauthVerifier,
-		timeFunc:      timeFunc,
+		playerRepo:          playerRepo,
+		refreshTokenRepo:    refreshTokenRepo,
+		guestCredentialRepo: guestCredentialRepo,
+		accountDeletionRepo: accountDeletionRepo,
+		signer:              signer,
+		oauthVerifier:       oauthVerifier,
+		guestAttempts:       ratelimit.NewInMemoryStore(),
+		timeFunc:            timeFunc,
 	}
 }
 
+// guestSignInPolicy limits the secrets a client can try on a guest account
+var guestSignInPolicy = ratelimit.Policy{Name: "guest-secret", Limit: MaxGuestSignInAttempts, Period: GuestSignInWindow}
+
+// SetGuestAttemptStore counts the guest sign-ins in the given store, shared by the instances, instead of in memory
+func (s *AuthService) SetGuestAttemptStore(store ratelimit.Store) {
+	s.guestAttempts = store
+}
+
+// newEphemeralSigner creates a signer with a random key, for a single instance that can lose its tokens
+func newEphemeralSigner() *AccessTokenSigner {
+	key := make([]byte, minSigningKeyLength)
+	_, _ = rand.Read(key)
+	signer, _ := NewAccessTokenSigner(key, DefaultAccessTokenTTL)
+	return signer
+}
+
 // Authenticate handles authentication with Google or Apple
 // This function only verifies the token and generates an auth token
 func (s *AuthService) Authenticate(ctx context.Context, req *models.AuthRequest) (*models.AuthResponse, error) {
@@ -75,23 +170,19 @@ func (s *AuthService) Authenticate(ctx context.Context, req *models.AuthRequest)
 		return nil, err
 	}
 
-	// Generate authentication token
-	token, err := s.generateAuthToken(ctx, player.ID)
-	if err != nil {
-		return nil, &models.GeneralAuthError{Reason: fmt.Sprintf("failed to generate auth token: %v", err)}
-	}
-
-	return &models.AuthResponse{
-		Player: *player,
-		Token:  token,
-	}, nil
+	// Generate authentication tokens
+	return s.issueTokens(ctx, player, nil)
 }
 
 // AuthenticateGuest handles guest authentication
-func (s *AuthService) AuthenticateGuest(ctx context.Context, displayName string) (*models.AuthResponse, error) {
+// A new guest gets a secret, which they must present to sign in again as the same guest
+func (s *AuthService) AuthenticateGuest(ctx context.Context, displayName string, secret string) (*models.AuthResponse, error) {
 	if displayName == "" {
 		return nil, &models.InvalidDisplayNameError{Reason: "display name cannot be empty for guest authentication"}
 	}
+	if isReservedDisplayName(displayName) {
+		return nil, &models.InvalidDisplayNameError{Reason: "display name is reserved"}
+	}
 
 	// For guests, we still need some way to prevent abuse with identical names
 	// Check if this exact display name is already taken by another guest user
@@ -99,18 +190,19 @@ func (s *AuthService) AuthenticateGuest(ctx context.Context, displayName string)
 	if err != nil {
 		return nil, &models.GeneralAuthError{Reason: fmt.Sprintf("failed to check existing player by name: %v", err)}
 	}
+	if existingPlayerByName == nil {
+		// A deleted guest is anonymized, it's found by the name it had until it's purged.
+		// It's only restored with its secret, like any other guest
+		existingPlayerByName, err = s.findDeletedPlayerByName(ctx, displayName)
+		if err != nil {
+			return nil, err
+		}
+	}
 	if existingPlayerByName != nil {
-		// If the player exists and is a guest (no provider and no email), allow re-authentication
-		if existingPlayerByName.Provider == nil && existingPlayerByName.Email == nil {
-			// Generate authentication token for existing guest
-			token, err := s.generateAuthToken(ctx, existingPlayerByName.ID)
-			if err != nil {
-				return nil, &models.GeneralAuthError{Reason: fmt.Sprintf("failed to generate auth token: %v", err)}
-			}
-			return &models.AuthResponse{
-				Player: *existingPlayerByName,
-				Token:  token,
-			}, nil
+		// If the player exists and is a guest (no provider and no email), allow re-authentication.
+		// Once linked to a provider, the account can't be reached by name anymore
+		if existingPlayerByName.IsGuest() {
+			return s.reauthenticateGuest(ctx, existingPlayerByName, secret)
 		}
 		// If it's an OAuth user with the same display name, still allow guest creation
 		// since display names are no longer unique
@@ -128,20 +220,187 @@ func (s *AuthService) AuthenticateGuest(ctx context.Context, displayName string)
 		return nil, &models.GeneralAuthError{Reason: fmt.Sprintf("failed to create guest player: %v", err)}
 	}
 
-	// Generate authentication token
-	token, err := s.generateAuthToken(ctx, player.ID)
+	// Generate authentication tokens, with the secret of the new guest
+	return s.issueTokensWithGuestSecret(ctx, player)
+}
+
+// reauthenticateGuest signs an existing guest in again, once their secret is checked.
+// A name is never enough: guests created before secrets existed can't sign in again until they claim a secret
+// from a session they're still signed in with, through RegenerateGuestSecret
+func (s *AuthService) reauthenticateGuest(ctx context.Context, player *models.PlayerData, secret string) (*models.AuthResponse, error) {
+	// The attempts are counted per address, so that guessing from one address doesn't lock the guest out everywhere
+	clientIP := deviceInfoFromContext(ctx).IPAddress
+	attempt, err := s.guestAttempts.Take(ctx, "ip:"+clientIP+":player:"+player.ID, guestSignInPolicy, s.timeFunc())
 	if err != nil {
-		return nil, &models.GeneralAuthError{Reason: fmt.Sprintf("failed to generate auth token: %v", err)}
+		log.Errorf("Failed to check the guest sign-in attempts of player %s: %v", player.ID, err)
+	} else if !attempt.Allowed {
+		log.Warnf("Too many guest sign-in attempts for player %s from %s", player.ID, clientIP)
+		return nil, &models.TooManyAttemptsError{RetryAfter: attempt.RetryAfter}
 	}
 
-	return &models.AuthResponse{
-		Player: *player,
-		Token:  token,
-	}, nil
+	secretHash, err := s.guestCredentialRepo.GetGuestSecretHash(ctx, player.ID)
+	if err != nil {
+		return nil, &models.GeneralAuthError{Reason: fmt.Sprintf("failed to get guest secret: %v", err)}
+	}
+	if secretHash == "" {
+		log.Warnf("Refused to sign in guest %s by name, they have no secret", player.ID)
+		return nil, &models.InvalidGuestSecretError{}
+	}
+
+	if subtle.ConstantTimeCompare([]byte(hashGuestSecret(secret)), []byte(secretHash)) != 1 {
+		return nil, &models.InvalidGuestSecretError{}
+	}
+
+	if player, err = s.restoreIfDeleted(ctx, player); err != nil {
+		return nil, err
+	}
+	return s.issueTokens(ctx, player, nil)
 }
 
-// ValidateToken validates an authentication token
+// RegenerateGuestSecret replaces the secret of a guest, for instance once they lost it or to give one to a legacy guest
+func (s *AuthService) RegenerateGuestSecret(ctx context.Context, playerID string) (string, error) {
+	player, err := s.GetPlayer(ctx, playerID)
+	if err != nil {
+		return "", err
+	}
+	if !player.IsGuest() {
+		// An account signed up by email has no provider
+		provider := ""
+		if player.Provider != nil {
+			provider = *player.Provider
+		}
+		return "", &models.AccountAlreadyLinkedError{Provider: provider}
+	}
+	return s.createGuestSecret(ctx, player.ID)
+}
+
+// issueTokensWithGuestSecret creates a new secret for the guest, and returns it with new tokens
+func (s *AuthService) issueTokensWithGuestSecret(ctx context.Context, player *models.PlayerData) (*models.AuthResponse, error) {
+	secret, err := s.createGuestSecret(ctx, player.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.issueTokens(ctx, player, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp.GuestSecret = secret
+	return resp, nil
+}
+
+// createGuestSecret generates a recovery code for a guest, like ABCD-EFGH-IJKL-MNOP, and stores its hash.
+// The code carries 80 random bits and attempts are limited, so a plain hash is enough
+func (s *AuthService) createGuestSecret(ctx context.Context, playerID string) (string, error) {
+	secretBytes := make([]byte, 10)
+	if _, err := rand.Read(secretBytes); err != nil {
+		return "", &models.GeneralAuthError{Reason: fmt.Sprintf("failed to generate guest secret: %v", err)}
+	}
+	encoded := base32.StdEncoding.EncodeToString(secretBytes)
+	groups := make([]string, 0, len(encoded)/4)
+	for i := 0; i < len(encoded); i += 4 {
+		groups = append(groups, encoded[i:i+4])
+	}
+	secret := strings.Join(groups, "-")
+
+	if err := s.guestCredentialRepo.SaveGuestSecretHash(ctx, playerID, hashGuestSecret(secret), s.timeFunc()); err != nil {
+		return "", &models.GeneralAuthError{Reason: fmt.Sprintf("failed to save guest secret: %v", err)}
+	}
+	return secret, nil
+}
+
+// hashGuestSecret hashes a guest secret, ignoring case, spaces and dashes so that it can be typed back by hand
+func hashGuestSecret(secret string) string {
+	normalized := strings.NewReplacer("-", "", " ", "").Replace(strings.ToUpper(secret))
+	hash := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(hash[:])
+}
+
+// LinkProvider attaches a Google or Apple identity to a guest player.
+// The player keeps their id, so their games, bets and scores are untouched.
+// Anyone knowing the guest name could have signed in as them, so every existing session is ended
+func (s *AuthService) LinkProvider(ctx context.Context, playerID string, req *models.AuthRequest) (*models.AuthResponse, error) {
+	if req.Provider != "google" && req.Provider != "apple" {
+		return nil, &models.UnsupportedProviderError{Provider: req.Provider}
+	}
+
+	player, err := s.playerRepo.GetPlayerByID(ctx, playerID)
+	if err != nil {
+		return nil, &models.GeneralAuthError{Reason: fmt.Sprintf("failed to get player by ID: %v", err)}
+	}
+	if player == nil {
+		return nil, &models.PlayerNotFoundError{Reason: "player not found"}
+	}
+	if !player.IsGuest() {
+		provider := ""
+		if player.Provider != nil {
+			provider = *player.Provider
+		}
+		return nil, &models.AccountAlreadyLinkedError{Provider: provider}
+	}
+
+	verifiedUser, err := s.oauthVerifier.VerifyToken(ctx, req.Provider, req.Token)
+	if err != nil {
+		return nil, &models.GeneralAuthError{Reason: fmt.Sprintf("failed to verify token: %v", err)}
+	}
+	userInfo, err := s.extractUserInfoFromOAuth(verifiedUser, req.Provider)
+	if err != nil {
+		return nil, err
+	}
+
+	// The identity must not already be used, merging two accounts is a separate flow
+	existingPlayer, err := s.findExistingPlayerByProvider(ctx, req.Provider, userInfo.providerID)
+	if err != nil {
+		return nil, err
+	}
+	if existingPlayer == nil {
+		existingPlayer, err = s.findExistingPlayerByEmail(ctx, userInfo.email)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if existingPlayer != nil && existingPlayer.ID != player.ID {
+		return nil, &models.IdentityInUseError{Provider: req.Provider}
+	}
+
+	player.Provider = &req.Provider
+	player.ProviderID = &userInfo.providerID
+	if userInfo.email != "" {
+		player.Email = &userInfo.email
+	}
+	updatedAt := s.timeFunc()
+	player.UpdatedAt = &updatedAt
+	if err := s.playerRepo.UpdatePlayer(ctx, player); err != nil {
+		return nil, &models.GeneralAuthError{Reason: fmt.Sprintf("failed to update player: %v", err)}
+	}
+	log.Infof("Guest player %s linked to %s", player.ID, req.Provider)
+
+	if _, err := s.revokeSessions(ctx, player.ID, ""); err != nil {
+		return nil, &models.GeneralAuthError{Reason: fmt.Sprintf("failed to end guest sessions: %v", err)}
+	}
+	return s.issueTokens(ctx, player, nil)
+}
+
+// ValidateToken validates an access token from its signature, without hitting the database
 func (s *AuthService) ValidateToken(ctx context.Context, token string) (*models.PlayerData, error) {
+	if !isAccessToken(token) {
+		return s.validateLegacyToken(ctx, token)
+	}
+
+	player, err := s.signer.Verify(token, s.timeFunc())
+	if err != nil {
+		var tokenExpiredErr *models.TokenExpiredError
+		if errors.As(err, &tokenExpiredErr) {
+			return nil, tokenExpiredErr
+		}
+		return nil, &models.PlayerNotFoundError{Reason: "invalid token"}
+	}
+	return player, nil
+}
+
+// validateLegacyToken validates an opaque token stored in auth_tokens.
+// Those were issued before signed access tokens, and are accepted until they expire
+func (s *AuthService) validateLegacyToken(ctx context.Context, token string) (*models.PlayerData, error) {
 	authToken, err := s.playerRepo.GetAuthToken(ctx, token)
 	if err != nil {
 		return nil, &models.GeneralAuthError{Reason: fmt.Sprintf("failed to get auth token: %v", err)}
@@ -164,12 +423,64 @@ func (s *AuthService) ValidateToken(ctx context.Context, token string) (*models.
 	if player == nil {
 		return nil, &models.PlayerNotFoundError{Reason: "player not found for token"}
 	}
+	if err := s.ensureNotDeleted(ctx, player.ID); err != nil {
+		return nil, err
+	}
 
 	return player, nil
 }
 
-// RefreshToken refreshes an authentication token (expired or not)
-func (s *AuthService) RefreshToken(ctx context.Context, token string) (*models.AuthResponse, error) {
+// RefreshToken rotates a refresh token: the token is revoked and a new one is issued in the same family.
+// Presenting a token that was already rotated revokes the whole family, since it means the token leaked
+func (s *AuthService) RefreshToken(ctx context.Context, refreshToken string) (*models.AuthResponse, error) {
+	stored, err := s.refreshTokenRepo.GetRefreshToken(ctx, hashRefreshToken(refreshToken))
+	if err != nil {
+		return nil, &models.GeneralAuthError{Reason: fmt.Sprintf("failed to get refresh token: %v", err)}
+	}
+	if stored == nil {
+		return s.exchangeLegacyToken(ctx, refreshToken)
+	}
+
+	now := s.timeFunc()
+	if stored.IsRevoked() {
+		return nil, s.revokeReusedFamily(ctx, stored, now)
+	}
+	if !now.Before(stored.ExpiresAt) {
+		return nil, &models.TokenExpiredError{Reason: "refresh token expired"}
+	}
+
+	revoked, err := s.refreshTokenRepo.RevokeRefreshToken(ctx, stored.ID, now)
+	if err != nil {
+		return nil, &models.GeneralAuthError{Reason: fmt.Sprintf("failed to revoke refresh token: %v", err)}
+	}
+	if !revoked {
+		// Another refresh used the token in the meantime
+		return nil, s.revokeReusedFamily(ctx, stored, now)
+	}
+
+	player, err := s.playerRepo.GetPlayerByID(ctx, stored.PlayerID)
+	if err != nil {
+		return nil, &models.GeneralAuthError{Reason: fmt.Sprintf("failed to get player by ID for refresh: %v", err)}
+	}
+	if player == nil {
+		return nil, &models.PlayerNotFoundError{Reason: "player not found for refresh"}
+	}
+
+	return s.issueTokens(ctx, player, stored)
+}
+
+// revokeReusedFamily revokes every token of the family of a reused refresh token
+func (s *AuthService) revokeReusedFamily(ctx context.Context, reused *models.RefreshToken, now time.Time) error {
+	log.Warnf("Refresh token reuse detected for player %s, revoking token family %s", reused.PlayerID, reused.FamilyID)
+	if err := s.refreshTokenRepo.RevokeRefreshTokenFamily(ctx, reused.FamilyID, now); err != nil {
+		return &models.GeneralAuthError{Reason: fmt.Sprintf("failed to revoke refresh token family: %v", err)}
+	}
+	return &models.RefreshTokenReusedError{FamilyID: reused.FamilyID}
+}
+
+// exchangeLegacyToken trades a legacy opaque token that is still valid for a new pair of tokens, and deletes it.
+// Expired legacy tokens are rejected, the player has to sign in again
+func (s *AuthService) exchangeLegacyToken(ctx context.Context, token string) (*models.AuthResponse, error) {
 	authToken, err := s.playerRepo.GetAuthToken(ctx, token)
 	if err != nil {
 		return nil, &models.GeneralAuthError{Reason: fmt.Sprintf("failed to get auth token for refresh: %v", err)}
@@ -179,6 +490,10 @@ func (s *AuthService) RefreshToken(ctx context.Context, token string) (*models.A
 		return nil, &models.PlayerNotFoundError{Reason: "invalid token for refresh"}
 	}
 
+	if s.timeFunc().After(authToken.ExpiresAt) {
+		return nil, &models.TokenExpiredError{Reason: "token expired"}
+	}
+
 	// Get the player data first to ensure they exist
 	player, err := s.playerRepo.GetPlayerByID(ctx, authToken.PlayerID)
 	if err != nil {
@@ -188,11 +503,8 @@ func (s *AuthService) RefreshToken(ctx context.Context, token string) (*models.A
 	if player == nil {
 		return nil, &models.PlayerNotFoundError{Reason: "player not found for refresh"}
 	}
-
-	// Generate a new token
-	newToken, err := s.generateAuthToken(ctx, authToken.PlayerID)
-	if err != nil {
-		return nil, &models.GeneralAuthError{Reason: fmt.Sprintf("failed to generate new auth token: %v", err)}
+	if err := s.ensureNotDeleted(ctx, player.ID); err != nil {
+		return nil, err
 	}
 
 	// Delete the old token
@@ -201,15 +513,56 @@ func (s *AuthService) RefreshToken(ctx context.Context, token string) (*models.A
 		return nil, &models.GeneralAuthError{Reason: fmt.Sprintf("failed to delete old auth token: %v", err)}
 	}
 
-	return &models.AuthResponse{
-		Player: *player,
-		Token:  newToken,
-	}, nil
+	return s.issueTokens(ctx, player, nil)
 }
 
-// GetAuthTokenDirectly gets an auth token without validation (for internal use)
-func (s *AuthService) GetAuthTokenDirectly(ctx context.Context, token string) (*models.AuthToken, error) {
-	return s.playerRepo.GetAuthToken(ctx, token)
+// RenewAccessToken signs a new access token in the session of an expired one, if it expired less than
+// AccessTokenRenewalWindow ago. The session must still have an active refresh token, so that signing out or
+// revoking it also ends the renewals
+func (s *AuthService) RenewAccessToken(ctx context.Context, expiredToken string) (*models.AuthResponse, error) {
+	if !isAccessToken(expiredToken) {
+		return nil, &models.PlayerNotFoundError{Reason: "invalid token for renewal"}
+	}
+	claimed, sessionID, expiresAt, err := s.signer.ExpiredSession(expiredToken)
+	if err != nil || sessionID == "" {
+		return nil, &models.PlayerNotFoundError{Reason: "invalid token for renewal"}
+	}
+
+	now := s.timeFunc()
+	if now.Sub(expiresAt) > AccessTokenRenewalWindow {
+		return nil, &models.TokenExpiredError{Reason: "access token expired too long ago to be renewed"}
+	}
+	active, err := s.refreshTokenRepo.GetActiveRefreshTokens(ctx, claimed.ID, now)
+	if err != nil {
+		return nil, &models.GeneralAuthError{Reason: fmt.Sprintf("failed to get sessions for renewal: %v", err)}
+	}
+	sessionActive := false
+	for _, token := range active {
+		if token.FamilyID == sessionID {
+			sessionActive = true
+			break
+		}
+	}
+	if !sessionActive {
+		return nil, &models.TokenExpiredError{Reason: "session ended"}
+	}
+
+	player, err := s.playerRepo.GetPlayerByID(ctx, claimed.ID)
+	if err != nil {
+		return nil, &models.GeneralAuthError{Reason: fmt.Sprintf("failed to get player by ID for renewal: %v", err)}
+	}
+	if player == nil {
+		return nil, &models.PlayerNotFoundError{Reason: "player not found for renewal"}
+	}
+	if err := s.ensureNotDeleted(ctx, player.ID); err != nil {
+		return nil, err
+	}
+
+	accessToken, expiresAt, err := s.signer.Sign(player, sessionID, now)
+	if err != nil {
+		return nil, &models.GeneralAuthError{Reason: fmt.Sprintf("failed to sign access token: %v", err)}
+	}
+	return &models.AuthResponse{Player: *player, Token: accessToken, ExpiresAt: expiresAt}, nil
 }
 
 // RefreshTokenByPlayerID refreshes a token using player ID directly
@@ -223,27 +576,133 @@ func (s *AuthService) RefreshTokenByPlayerID(ctx context.Context, playerID strin
 	if player == nil {
 		return nil, &models.PlayerNotFoundError{Reason: "player not found for refresh"}
 	}
+	if err := s.ensureNotDeleted(ctx, player.ID); err != nil {
+		return nil, err
+	}
+
+	// Generate new tokens, as a new sign-in
+	return s.issueTokens(ctx, player, nil)
+}
 
-	// Generate a new token
-	newToken, err := s.generateAuthToken(ctx, playerID)
+// GetPlayer returns the up to date data of an authenticated player
+func (s *AuthService) GetPlayer(ctx context.Context, playerID string) (*models.PlayerData, error) {
+	player, err := s.playerRepo.GetPlayerByID(ctx, playerID)
 	if err != nil {
-		return nil, &models.GeneralAuthError{Reason: fmt.Sprintf("failed to generate new auth token: %v", err)}
+		return nil, &models.GeneralAuthError{Reason: fmt.Sprintf("failed to get player by ID: %v", err)}
 	}
-
-	return &models.AuthResponse{
-		Player: *player,
-		Token:  newToken,
-	}, nil
+	if player == nil {
+		return nil, &models.PlayerNotFoundError{Reason: "player not found"}
+	}
+	return player, nil
 }
 
-// Logout invalidates a token
+// Logout ends the session of a refresh token or of an access token, or deletes a legacy opaque token.
+// Access tokens themselves can't be revoked, they expire on their own shortly after
 func (s *AuthService) Logout(ctx context.Context, token string) error {
+	if isAccessToken(token) {
+		sessionID, err := s.signer.SessionID(token, s.timeFunc())
+		if err != nil || sessionID == "" {
+			return nil
+		}
+		return s.refreshTokenRepo.RevokeRefreshTokenFamily(ctx, sessionID, s.timeFunc())
+	}
+
+	stored, err := s.refreshTokenRepo.GetRefreshToken(ctx, hashRefreshToken(token))
+	if err != nil {
+		return err
+	}
+	if stored != nil {
+		return s.refreshTokenRepo.RevokeRefreshTokenFamily(ctx, stored.FamilyID, s.timeFunc())
+	}
 	return s.playerRepo.DeleteAuthToken(ctx, token)
 }
 
+// ListSessions returns the sessions of a player, most recently used first.
+// The session of the given access token is flagged as the current one
+func (s *AuthService) ListSessions(ctx context.Context, playerID string, currentToken string) ([]*models.Session, error) {
+	tokens, err := s.refreshTokenRepo.GetActiveRefreshTokens(ctx, playerID, s.timeFunc())
+	if err != nil {
+		return nil, fmt.Errorf("error getting sessions: %v", err)
+	}
+
+	currentSessionID := s.sessionID(currentToken)
+	sessions := make([]*models.Session, 0, len(tokens))
+	for _, token := range tokens {
+		sessions = append(sessions, models.NewSessionFromRefreshToken(token, currentSessionID))
+	}
+	return sessions, nil
+}
+
+// RevokeSession ends a session of a player, or returns ErrSessionNotFound
+func (s *AuthService) RevokeSession(ctx context.Context, playerID string, sessionID string) error {
+	now := s.timeFunc()
+	tokens, err := s.refreshTokenRepo.GetActiveRefreshTokens(ctx, playerID, now)
+	if err != nil {
+		return fmt.Errorf("error getting sessions: %v", err)
+	}
+
+	for _, token := range tokens {
+		if token.FamilyID == sessionID {
+			if err := s.refreshTokenRepo.RevokeRefreshTokenFamily(ctx, sessionID, now); err != nil {
+				return fmt.Errorf("error revoking session: %v", err)
+			}
+			log.Infof("Player %s revoked session %s", playerID, sessionID)
+			return nil
+		}
+	}
+	return ErrSessionNotFound
+}
+
+// RevokeOtherSessions ends every session of a player but the one of the given access token,
+// and returns the number of sessions ended
+func (s *AuthService) RevokeOtherSessions(ctx context.Context, playerID string, currentToken string) (int, error) {
+	revoked, err := s.revokeSessions(ctx, playerID, s.sessionID(currentToken))
+	if err != nil {
+		return revoked, err
+	}
+	log.Infof("Player %s revoked %d other sessions", playerID, revoked)
+	return revoked, nil
+}
+
+// revokeSessions ends every session of a player but the kept one, and returns the number of sessions ended
+func (s *AuthService) revokeSessions(ctx context.Context, playerID string, keptSessionID string) (int, error) {
+	now := s.timeFunc()
+	tokens, err := s.refreshTokenRepo.GetActiveRefreshTokens(ctx, playerID, now)
+	if err != nil {
+		return 0, fmt.Errorf("error getting sessions: %v", err)
+	}
+
+	revoked := 0
+	for _, token := range tokens {
+		if token.FamilyID == keptSessionID {
+			continue
+		}
+		if err := s.refreshTokenRepo.RevokeRefreshTokenFamily(ctx, token.FamilyID, now); err != nil {
+			return revoked, fmt.Errorf("error revoking session: %v", err)
+		}
+		revoked++
+	}
+	return revoked, nil
+}
+
+// sessionID returns the session of an access token, or an empty id for a legacy or invalid token
+func (s *AuthService) sessionID(accessToken string) string {
+	if !isAccessToken(accessToken) {
+		return ""
+	}
+	sessionID, err := s.signer.SessionID(accessToken, s.timeFunc())
+	if err != nil {
+		return ""
+	}
+	return sessionID
+}
+
 // CleanupExpiredTokens removes expired tokens from the database
 func (s *AuthService) CleanupExpiredTokens(ctx context.Context) error {
-	return s.playerRepo.DeleteExpiredTokens(ctx)
+	if err := s.playerRepo.DeleteExpiredTokens(ctx); err != nil {
+		return err
+	}
+	return s.refreshTokenRepo.DeleteExpiredRefreshTokens(ctx, s.timeFunc())
 }
 
 // GetOrCreatePlayer handles player creation, updates, and account linking
@@ -262,6 +721,9 @@ func (s *AuthService) GetOrCreatePlayer(ctx context.Context, verifiedUser map[st
 	}
 
 	if existingPlayer != nil {
+		if existingPlayer, err = s.restoreIfDeleted(ctx, existingPlayer); err != nil {
+			return nil, err
+		}
 		return s.handleExistingPlayer(ctx, existingPlayer, displayName)
 	}
 
@@ -272,6 +734,9 @@ func (s *AuthService) GetOrCreatePlayer(ctx context.Context, verifiedUser map[st
 	}
 
 	if existingPlayerByEmail != nil {
+		if existingPlayerByEmail, err = s.restoreIfDeleted(ctx, existingPlayerByEmail); err != nil {
+			return nil, err
+		}
 		return s.linkExistingAccount(ctx, existingPlayerByEmail, provider, userInfo.providerID, displayName)
 	}
 
@@ -328,7 +793,7 @@ func (s *AuthService) findExistingPlayerByProvider(ctx context.Context, provider
 // handleExistingPlayer handles the case where a player already exists with this provider
 func (s *AuthService) handleExistingPlayer(ctx context.Context, existingPlayer *models.PlayerData, displayName string) (*models.PlayerData, error) {
 	// Only update name if provided and different
-	if displayName != "" && displayName != existingPlayer.Name {
+	if displayName != "" && displayName != existingPlayer.Name && !isReservedDisplayName(displayName) {
 		existingPlayer.Name = displayName
 		existingPlayer.UpdatedAt = &time.Time{}
 		*existingPlayer.UpdatedAt = s.timeFunc()
@@ -357,13 +822,16 @@ func (s *AuthService) findExistingPlayerByEmail(ctx context.Context, email strin
 
 // linkExistingAccount links an existing account to a new OAuth provider
 func (s *AuthService) linkExistingAccount(ctx context.Context, existingPlayer *models.PlayerData, provider, providerID, displayName string) (*models.PlayerData, error) {
-	existingPlayer.Provider = &provider
-	existingPlayer.ProviderID = &providerID
+	// Signing in by email only proves the address, the provider the player already signs in with is kept
+	if provider != models.EmailSignInProvider || existingPlayer.Provider == nil {
+		existingPlayer.Provider = &provider
+		existingPlayer.ProviderID = &providerID
+	}
 	existingPlayer.UpdatedAt = &time.Time{}
 	*existingPlayer.UpdatedAt = s.timeFunc()
 
 	// Update name if provided and different
-	if displayName != "" && displayName != existingPlayer.Name {
+	if displayName != "" && displayName != existingPlayer.Name && !isReservedDisplayName(displayName) {
 		existingPlayer.Name = displayName
 	}
 
@@ -425,6 +893,13 @@ func (s *AuthService) validateDisplayNameForNewUser(displayName, suggestedName s
 		}
 	}
 
+	if isReservedDisplayName(displayName) {
+		return &models.NeedDisplayNameError{
+			Reason:        "display name is reserved",
+			SuggestedName: suggestedName,
+		}
+	}
+
 	return nil
 }
 
@@ -442,6 +917,10 @@ func (s *AuthService) UpdateDisplayName(ctx context.Context, playerID string, ne
 		return nil, &models.InvalidDisplayNameError{Reason: "display name must be 20 characters or less"}
 	}
 
+	if isReservedDisplayName(newDisplayName) {
+		return nil, &models.InvalidDisplayNameError{Reason: "display name is reserved"}
+	}
+
 	// Get the current player
 	player, err := s.playerRepo.GetPlayerByID(ctx, playerID)
 	if err != nil {
@@ -465,32 +944,74 @@ func (s *AuthService) UpdateDisplayName(ctx context.Context, playerID string, ne
 	return player, nil
 }
 
-// generateAuthToken creates a new authentication token
-func (s *AuthService) generateAuthToken(ctx context.Context, playerID string) (string, error) {
-	// Generate a random token
+// issueTokens signs a new access token for the player, and creates a new refresh token.
+// The new token continues the session of the previous one, a nil previous token starts a new session as for a new sign-in
+func (s *AuthService) issueTokens(ctx context.Context, player *models.PlayerData, previous *models.RefreshToken) (*models.AuthResponse, error) {
+	now := s.timeFunc()
+	token := &models.RefreshToken{
+		PlayerID:         player.ID,
+		FamilyID:         uuid.New().String(),
+		Device:           deviceInfoFromContext(ctx),
+		SessionStartedAt: now,
+		ExpiresAt:        now.Add(DefaultRefreshTokenTTL),
+		CreatedAt:        now,
+	}
+	if previous != nil {
+		token.FamilyID = previous.FamilyID
+		token.SessionStartedAt = previous.SessionStartedAt
+		token.Device = mergeDeviceInfo(token.Device, previous.Device)
+	}
+
+	accessToken, expiresAt, err := s.signer.Sign(player, token.FamilyID, now)
+	if err != nil {
+		return nil, &models.GeneralAuthError{Reason: fmt.Sprintf("failed to sign access token: %v", err)}
+	}
+
+	// Generate a random refresh token, only its hash is stored
 	tokenBytes := make([]byte, 32)
 	if _, err := rand.Read(tokenBytes); err != nil {
-		return "", err
+		return nil, &models.GeneralAuthError{Reason: fmt.Sprintf("failed to generate refresh token: %v", err)}
 	}
-	token := hex.EncodeToString(tokenBytes)
+	refreshToken := hex.EncodeToString(tokenBytes)
+	token.TokenHash = hashRefreshToken(refreshToken)
 
-	// Create auth token record
-	authToken := &models.AuthToken{
-		PlayerID:  playerID,
-		Token:     token,
-		ExpiresAt: s.timeFunc().Add(24 * time.Hour), // 24 hour expiry
+	if err := s.refreshTokenRepo.CreateRefreshToken(ctx, token); err != nil {
+		return nil, &models.GeneralAuthError{Reason: fmt.Sprintf("failed to store refresh token: %v", err)}
 	}
 
-	// Store in database
-	err := s.playerRepo.CreateAuthToken(ctx, authToken)
-	if err != nil {
-		return "", err
+	return &models.AuthResponse{
+		Player:       *player,
+		Token:        accessToken,
+		ExpiresAt:    expiresAt,
+		RefreshToken: refreshToken,
+	}, nil
+}
+
+// mergeDeviceInfo returns the device of a refresh request, completed with what the session already knew
+func mergeDeviceInfo(current, previous models.DeviceInfo) models.DeviceInfo {
+	if current.Name == "" {
+		current.Name = previous.Name
 	}
+	if current.Platform == "" {
+		current.Platform = previous.Platform
+	}
+	if current.AppVersion == "" {
+		current.AppVersion = previous.AppVersion
+	}
+	if current.IPAddress == "" {
+		current.IPAddress = previous.IPAddress
+	}
+	return current
+}
 
-	return token, nil
+// hashRefreshToken hashes a refresh token before it's stored or looked up
+func hashRefreshToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
 }
 
-// DeleteAccount permanently deletes a player account and all associated data
+// DeleteAccount anonymizes a player account and ends its sessions. What's needed to restore it is kept
+// until the end of the grace period, then PurgeDeletedAccounts removes the personal data for good
 func (s *AuthService) DeleteAccount(ctx context.Context, playerID string) error {
 	// First verify the player exists
 	player, err := s.playerRepo.GetPlayerByID(ctx, playerID)
@@ -502,12 +1023,116 @@ func (s *AuthService) DeleteAccount(ctx context.Context, playerID string) error
 		return &models.PlayerNotFoundError{Reason: "player not found"}
 	}
 
-	// Delete the player (this will cascade delete all related data due to our foreign key constraints)
-	// This includes: auth_tokens, bets, scores, game_player entries
-	err = s.playerRepo.DeletePlayer(ctx, playerID)
+	existing, err := s.accountDeletionRepo.GetDeletion(ctx, playerID)
 	if err != nil {
+		return &models.GeneralAuthError{Reason: fmt.Sprintf("failed to get account deletion: %v", err)}
+	}
+	if existing != nil {
+		return nil
+	}
+
+	// The deletion is recorded first, so that the account can still be restored if anonymizing it fails halfway
+	now := s.timeFunc()
+	deletion := &models.AccountDeletion{
+		PlayerID:        playerID,
+		OriginalName:    player.Name,
+		AvatarObjectKey: player.AvatarObjectKey,
+		DeletedAt:       now,
+		PurgeAfter:      now.Add(AccountDeletionGracePeriod),
+	}
+	if err := s.accountDeletionRepo.CreateDeletion(ctx, deletion); err != nil {
 		return &models.GeneralAuthError{Reason: fmt.Sprintf("failed to delete player account: %v", err)}
 	}
 
+	player.Name = models.DeletedPlayerName
+	player.UpdatedAt = &now
+	if err := s.playerRepo.UpdatePlayer(ctx, player); err != nil {
+		return &models.GeneralAuthError{Reason: fmt.Sprintf("failed to anonymize player: %v", err)}
+	}
+	if player.AvatarObjectKey != nil {
+		if err := s.playerRepo.ClearAvatar(ctx, playerID); err != nil {
+			return &models.GeneralAuthError{Reason: fmt.Sprintf("failed to hide player avatar: %v", err)}
+		}
+	}
+
+	// Legacy tokens are rejected by ensureNotDeleted, and access tokens expire on their own shortly after
+	tokens, err := s.refreshTokenRepo.GetActiveRefreshTokens(ctx, playerID, now)
+	if err != nil {
+		return &models.GeneralAuthError{Reason: fmt.Sprintf("failed to get sessions: %v", err)}
+	}
+	for _, token := range tokens {
+		if err := s.refreshTokenRepo.RevokeRefreshTokenFamily(ctx, token.FamilyID, now); err != nil {
+			return &models.GeneralAuthError{Reason: fmt.Sprintf("failed to revoke session: %v", err)}
+		}
+	}
+
+	log.Infof("Player %s deleted their account, it will be purged after %s", playerID, deletion.PurgeAfter.Format(time.RFC3339))
 	return nil
 }
+
+// restoreIfDeleted gives back its name and avatar to an account pending deletion, as its player signed in again.
+// It must only be called once the player proved they own the account, with their guest secret, their provider
+// or an emailed code, never with a name alone
+func (s *AuthService) restoreIfDeleted(ctx context.Context, player *models.PlayerData) (*models.PlayerData, error) {
+	deletion, err := s.accountDeletionRepo.GetDeletion(ctx, player.ID)
+	if err != nil {
+		return nil, &models.GeneralAuthError{Reason: fmt.Sprintf("failed to get account deletion: %v", err)}
+	}
+	if deletion == nil {
+		return player, nil
+	}
+
+	now := s.timeFunc()
+	player.Name = deletion.OriginalName
+	player.UpdatedAt = &now
+	if err := s.playerRepo.UpdatePlayer(ctx, player); err != nil {
+		return nil, &models.GeneralAuthError{Reason: fmt.Sprintf("failed to restore player: %v", err)}
+	}
+	if deletion.AvatarObjectKey != nil {
+		// The signed URL is left expired, so that it's generated again the next time the profile is read
+		if err := s.playerRepo.UpdateAvatar(ctx, player.ID, *deletion.AvatarObjectKey, "", now); err != nil {
+			return nil, &models.GeneralAuthError{Reason: fmt.Sprintf("failed to restore player avatar: %v", err)}
+		}
+		player.AvatarObjectKey = deletion.AvatarObjectKey
+	}
+	if err := s.accountDeletionRepo.DeleteDeletion(ctx, player.ID); err != nil {
+		return nil, &models.GeneralAuthError{Reason: fmt.Sprintf("failed to restore player account: %v", err)}
+	}
+
+	log.Infof("Player %s signed in again and restored their account", player.ID)
+	return player, nil
+}
+
+// findDeletedPlayerByName returns the account pending deletion that had the given name, or nil if there is none
+func (s *AuthService) findDeletedPlayerByName(ctx context.Context, name string) (*models.PlayerData, error) {
+	deletion, err := s.accountDeletionRepo.GetDeletionByOriginalName(ctx, name)
+	if err != nil {
+		return nil, &models.GeneralAuthError{Reason: fmt.Sprintf("failed to get account deletion by name: %v", err)}
+	}
+	if deletion == nil {
+		return nil, nil
+	}
+
+	player, err := s.playerRepo.GetPlayerByID(ctx, deletion.PlayerID)
+	if err != nil {
+		return nil, &models.GeneralAuthError{Reason: fmt.Sprintf("failed to get player by ID: %v", err)}
+	}
+	return player, nil
+}
+
+// ensureNotDeleted rejects the tokens of a player whose account is pending deletion
+func (s *AuthService) ensureNotDeleted(ctx context.Context, playerID string) error {
+	deletion, err := s.accountDeletionRepo.GetDeletion(ctx, playerID)
+	if err != nil {
+		return &models.GeneralAuthError{Reason: fmt.Sprintf("failed to get account deletion: %v", err)}
+	}
+	if deletion != nil {
+		return &models.PlayerNotFoundError{Reason: "player account deleted"}
+	}
+	return nil
+}
+
+// isReservedDisplayName checks if a display name could be mistaken for a deleted player
+func isReservedDisplayName(displayName string) bool {
+	return strings.EqualFold(strings.TrimSpace(displayName), models.DeletedPlayerName)
+}
@@ -4,11 +4,13 @@ import (
 	"context"
 	"database/sql"
 	"ligain/backend/models"
+	"ligain/backend/repositories"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 // MockPlayerRepository for testing DeleteAccount
@@ -111,6 +113,14 @@ func (m *MockPlayerRepositoryForDelete) UpdateAvatarSignedURL(ctx context.Contex
 	return args.Error(0)
 }
 
+func (m *MockPlayerRepositoryForDelete) GetPlayersWithAvatarURLExpiringBefore(ctx context.Context, before time.Time) ([]*models.PlayerData, error) {
+	args := m.Called(ctx, before)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.PlayerData), args.Error(1)
+}
+
 func (m *MockPlayerRepositoryForDelete) ClearAvatar(ctx context.Context, playerID string) error {
 	args := m.Called(ctx, playerID)
 	return args.Error(0)
@@ -134,9 +144,11 @@ func TestAuthService_DeleteAccount_Success(t *testing.T) {
 		Name: "Test Player",
 	}
 
-	// Setup expectations
+	// Setup expectations, the player is anonymized instead of deleted
 	mockRepo.On("GetPlayerByID", ctx, playerID).Return(testPlayer, nil)
-	mockRepo.On("DeletePlayer", ctx, playerID).Return(nil)
+	mockRepo.On("UpdatePlayer", ctx, mock.MatchedBy(func(player *models.PlayerData) bool {
+		return player.ID == playerID && player.Name == models.DeletedPlayerName
+	})).Return(nil)
 
 	// Execute
 	err := authService.DeleteAccount(ctx, playerID)
@@ -216,7 +228,7 @@ func TestAuthService_DeleteAccount_DeleteFails(t *testing.T) {
 
 	// Setup expectations
 	mockRepo.On("GetPlayerByID", ctx, playerID).Return(testPlayer, nil)
-	mockRepo.On("DeletePlayer", ctx, playerID).Return(assert.AnError)
+	mockRepo.On("UpdatePlayer", ctx, mock.Anything).Return(assert.AnError)
 
 	// Execute
 	err := authService.DeleteAccount(ctx, playerID)
@@ -227,3 +239,114 @@ func TestAuthService_DeleteAccount_DeleteFails(t *testing.T) {
 	assert.ErrorAs(t, err, &generalAuthErr)
 	mockRepo.AssertExpectations(t)
 }
+
+func TestAuthService_DeleteAccount_GracePeriod(t *testing.T) {
+	ctx := context.Background()
+
+	setup := func(t *testing.T) (*AuthService, *MockPlayerRepository, *repositories.InMemoryAccountDeletionRepository, *MockOAuthVerifier) {
+		playerRepo := NewMockPlayerRepository()
+		deletionRepo := repositories.NewInMemoryAccountDeletionRepository()
+		oauthVerifier := NewMockOAuthVerifier()
+		authService := NewAuthServiceWithTokens(
+			playerRepo,
+			repositories.NewInMemoryRefreshTokenRepository(),
+			repositories.NewInMemoryGuestCredentialRepository(),
+			deletionRepo,
+			newEphemeralSigner(),
+			oauthVerifier,
+			func() time.Time { return frozenTime },
+		)
+		return authService, playerRepo, deletionRepo, oauthVerifier
+	}
+
+	t.Run("Anonymizes And Signs Out", func(t *testing.T) {
+		authService, playerRepo, deletionRepo, _ := setup(t)
+		guest, err := authService.AuthenticateGuest(ctx, "Leaver", "")
+		require.NoError(t, err)
+
+		require.NoError(t, authService.DeleteAccount(ctx, guest.Player.ID))
+
+		player, err := playerRepo.GetPlayerByID(ctx, guest.Player.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.DeletedPlayerName, player.Name)
+
+		deletion, err := deletionRepo.GetDeletion(ctx, guest.Player.ID)
+		require.NoError(t, err)
+		require.NotNil(t, deletion)
+		assert.Equal(t, "Leaver", deletion.OriginalName)
+		assert.Equal(t, frozenTime.Add(AccountDeletionGracePeriod), deletion.PurgeAfter)
+
+		_, err = authService.RefreshToken(ctx, guest.RefreshToken)
+		assert.Error(t, err)
+
+		// Deleting again keeps the original name
+		require.NoError(t, authService.DeleteAccount(ctx, guest.Player.ID))
+		deletion, err = deletionRepo.GetDeletion(ctx, guest.Player.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Leaver", deletion.OriginalName)
+	})
+
+	t.Run("Guest Restores By Signing In", func(t *testing.T) {
+		authService, _, deletionRepo, _ := setup(t)
+		guest, err := authService.AuthenticateGuest(ctx, "Leaver", "")
+		require.NoError(t, err)
+		require.NoError(t, authService.DeleteAccount(ctx, guest.Player.ID))
+
+		_, err = authService.AuthenticateGuest(ctx, "Leaver", "AAAA-AAAA-AAAA-AAAA")
+		var invalidSecretErr *models.InvalidGuestSecretError
+		assert.ErrorAs(t, err, &invalidSecretErr)
+
+		resp, err := authService.AuthenticateGuest(ctx, "Leaver", guest.GuestSecret)
+		require.NoError(t, err)
+		assert.Equal(t, guest.Player.ID, resp.Player.ID)
+		assert.Equal(t, "Leaver", resp.Player.Name)
+
+		deletion, err := deletionRepo.GetDeletion(ctx, guest.Player.ID)
+		require.NoError(t, err)
+		assert.Nil(t, deletion)
+	})
+
+	t.Run("Guest Is Never Restored By Name", func(t *testing.T) {
+		authService, playerRepo, deletionRepo, _ := setup(t)
+		// A guest created before secrets existed has no secret to prove they own the account
+		legacy := &models.PlayerData{Name: "Old Leaver"}
+		require.NoError(t, playerRepo.CreatePlayer(ctx, legacy))
+		require.NoError(t, authService.DeleteAccount(ctx, legacy.ID))
+
+		_, err := authService.AuthenticateGuest(ctx, "Old Leaver", "")
+		var invalidSecretErr *models.InvalidGuestSecretError
+		assert.ErrorAs(t, err, &invalidSecretErr)
+
+		deletion, err := deletionRepo.GetDeletion(ctx, legacy.ID)
+		require.NoError(t, err)
+		assert.NotNil(t, deletion, "the deletion must not be canceled")
+		player, err := playerRepo.GetPlayerByID(ctx, legacy.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.DeletedPlayerName, player.Name)
+	})
+
+	t.Run("OAuth Player Restores By Signing In", func(t *testing.T) {
+		authService, _, _, _ := setup(t)
+		signedUp, err := authService.Authenticate(ctx, &models.AuthRequest{Provider: "google", Token: "google-token", Name: "Googler"})
+		require.NoError(t, err)
+		require.NoError(t, authService.DeleteAccount(ctx, signedUp.Player.ID))
+
+		resp, err := authService.Authenticate(ctx, &models.AuthRequest{Provider: "google", Token: "google-token"})
+		require.NoError(t, err)
+		assert.Equal(t, signedUp.Player.ID, resp.Player.ID)
+		assert.Equal(t, "Googler", resp.Player.Name)
+	})
+
+	t.Run("Deleted Player Name Is Reserved", func(t *testing.T) {
+		authService, _, _, _ := setup(t)
+
+		_, err := authService.AuthenticateGuest(ctx, "deleted player", "")
+		var invalidNameErr *models.InvalidDisplayNameError
+		assert.ErrorAs(t, err, &invalidNameErr)
+
+		guest, err := authService.AuthenticateGuest(ctx, "Stayer", "")
+		require.NoError(t, err)
+		_, err = authService.UpdateDisplayName(ctx, guest.Player.ID, models.DeletedPlayerName)
+		assert.ErrorAs(t, err, &invalidNameErr)
+	})
+}
@@ -0,0 +1,134 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"ligain/backend/models"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthService_AuthenticateGuest_Secret(t *testing.T) {
+	ctx := context.Background()
+
+	setup := func(t *testing.T) (*AuthService, *models.AuthResponse, *time.Time) {
+		now := frozenTime
+		authService := NewAuthServiceWithTimeFunc(NewMockPlayerRepository(), NewMockOAuthVerifier(), func() time.Time { return now })
+		guest, err := authService.AuthenticateGuest(ctx, "Guesty", "")
+		require.NoError(t, err)
+		return authService, guest, &now
+	}
+
+	t.Run("NewGuestGetsSecret", func(t *testing.T) {
+		_, guest, _ := setup(t)
+
+		assert.Regexp(t, `^[A-Z2-7]{4}-[A-Z2-7]{4}-[A-Z2-7]{4}-[A-Z2-7]{4}$`, guest.GuestSecret)
+	})
+
+	t.Run("SignInAgainWithSecret", func(t *testing.T) {
+		authService, guest, _ := setup(t)
+
+		// The secret can be typed back without dashes and in lower case
+		typed := strings.ToLower(strings.ReplaceAll(guest.GuestSecret, "-", ""))
+		resp, err := authService.AuthenticateGuest(ctx, "Guesty", typed)
+		require.NoError(t, err)
+		assert.Equal(t, guest.Player.ID, resp.Player.ID)
+		assert.Empty(t, resp.GuestSecret)
+	})
+
+	t.Run("NameAloneIsRejected", func(t *testing.T) {
+		authService, _, _ := setup(t)
+
+		_, err := authService.AuthenticateGuest(ctx, "Guesty", "")
+		var invalidSecretErr *models.InvalidGuestSecretError
+		assert.True(t, errors.As(err, &invalidSecretErr))
+
+		_, err = authService.AuthenticateGuest(ctx, "Guesty", "AAAA-AAAA-AAAA-AAAA")
+		assert.True(t, errors.As(err, &invalidSecretErr))
+	})
+
+	t.Run("RateLimited", func(t *testing.T) {
+		authService, guest, now := setup(t)
+		attacker := WithDeviceInfo(ctx, models.DeviceInfo{IPAddress: "203.0.113.7"})
+		owner := WithDeviceInfo(ctx, models.DeviceInfo{IPAddress: "198.51.100.1"})
+
+		for i := 0; i < MaxGuestSignInAttempts; i++ {
+			_, err := authService.AuthenticateGuest(attacker, "Guesty", "wrong")
+			var invalidSecretErr *models.InvalidGuestSecretError
+			require.True(t, errors.As(err, &invalidSecretErr))
+		}
+
+		// Even the right secret is refused from the same address until an attempt is given back
+		_, err := authService.AuthenticateGuest(attacker, "Guesty", guest.GuestSecret)
+		var tooManyAttemptsErr *models.TooManyAttemptsError
+		require.True(t, errors.As(err, &tooManyAttemptsErr))
+		assert.Equal(t, GuestSignInWindow/MaxGuestSignInAttempts, tooManyAttemptsErr.RetryAfter)
+
+		// The guest isn't locked out from their own address
+		_, err = authService.AuthenticateGuest(owner, "Guesty", guest.GuestSecret)
+		assert.NoError(t, err)
+
+		*now = frozenTime.Add(GuestSignInWindow / MaxGuestSignInAttempts)
+		_, err = authService.AuthenticateGuest(attacker, "Guesty", guest.GuestSecret)
+		assert.NoError(t, err)
+	})
+
+	t.Run("LegacyGuestClaimsSecretFromSession", func(t *testing.T) {
+		mockRepo := NewMockPlayerRepository()
+		authService := NewAuthServiceWithTimeFunc(mockRepo, NewMockOAuthVerifier(), func() time.Time { return frozenTime })
+		legacy := &models.PlayerData{Name: "Old Guest"}
+		require.NoError(t, mockRepo.CreatePlayer(ctx, legacy))
+
+		// Without a secret, the name alone never signs the guest in
+		_, err := authService.AuthenticateGuest(ctx, "Old Guest", "")
+		var invalidSecretErr *models.InvalidGuestSecretError
+		assert.True(t, errors.As(err, &invalidSecretErr))
+
+		// The guest claims a secret from the session they're still signed in with
+		secret, err := authService.RegenerateGuestSecret(ctx, legacy.ID)
+		require.NoError(t, err)
+		resp, err := authService.AuthenticateGuest(ctx, "Old Guest", secret)
+		require.NoError(t, err)
+		assert.Equal(t, legacy.ID, resp.Player.ID)
+	})
+
+	t.Run("RegenerateSecret", func(t *testing.T) {
+		authService, guest, _ := setup(t)
+
+		secret, err := authService.RegenerateGuestSecret(ctx, guest.Player.ID)
+		require.NoError(t, err)
+		assert.NotEqual(t, guest.GuestSecret, secret)
+
+		_, err = authService.AuthenticateGuest(ctx, "Guesty", guest.GuestSecret)
+		assert.Error(t, err)
+		_, err = authService.AuthenticateGuest(ctx, "Guesty", secret)
+		assert.NoError(t, err)
+	})
+
+	t.Run("RegenerateSecretOfLinkedAccount", func(t *testing.T) {
+		authService, guest, _ := setup(t)
+		_, err := authService.LinkProvider(ctx, guest.Player.ID, &models.AuthRequest{Provider: "google", Token: "token"})
+		require.NoError(t, err)
+
+		_, err = authService.RegenerateGuestSecret(ctx, guest.Player.ID)
+		var alreadyLinkedErr *models.AccountAlreadyLinkedError
+		assert.True(t, errors.As(err, &alreadyLinkedErr))
+	})
+
+	t.Run("RegenerateSecretOfEmailAccount", func(t *testing.T) {
+		mockRepo := NewMockPlayerRepository()
+		authService := NewAuthServiceWithTimeFunc(mockRepo, NewMockOAuthVerifier(), func() time.Time { return frozenTime })
+		email := "player@example.com"
+		player := &models.PlayerData{Name: "Mailer", Email: &email}
+		require.NoError(t, mockRepo.CreatePlayer(ctx, player))
+
+		_, err := authService.RegenerateGuestSecret(ctx, player.ID)
+		var alreadyLinkedErr *models.AccountAlreadyLinkedError
+		require.True(t, errors.As(err, &alreadyLinkedErr))
+		assert.Empty(t, alreadyLinkedErr.Provider)
+	})
+}
@@ -0,0 +1,107 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"ligain/backend/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthService_LinkProvider(t *testing.T) {
+	ctx := context.Background()
+	googleRequest := &models.AuthRequest{Provider: "google", Token: "mock_google_token"}
+
+	setup := func(t *testing.T) (*AuthService, *MockPlayerRepository, *models.AuthResponse) {
+		mockRepo := NewMockPlayerRepository()
+		authService := NewAuthServiceWithTimeFunc(mockRepo, NewMockOAuthVerifier(), func() time.Time { return frozenTime })
+		guest, err := authService.AuthenticateGuest(ctx, "Guesty", "")
+		require.NoError(t, err)
+		return authService, mockRepo, guest
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		authService, mockRepo, guest := setup(t)
+
+		resp, err := authService.LinkProvider(ctx, guest.Player.ID, googleRequest)
+		require.NoError(t, err)
+
+		// Same player, so every game, bet and score is kept
+		assert.Equal(t, guest.Player.ID, resp.Player.ID)
+		assert.Equal(t, "Guesty", resp.Player.Name)
+		linked, err := mockRepo.GetPlayerByProvider(ctx, "google", "google_123")
+		require.NoError(t, err)
+		require.NotNil(t, linked)
+		assert.Equal(t, guest.Player.ID, linked.ID)
+		assert.Equal(t, "test@example.com", *linked.Email)
+		assert.False(t, linked.IsGuest())
+
+		// The guest session is ended, the new tokens are valid
+		_, err = authService.RefreshToken(ctx, guest.RefreshToken)
+		assert.Error(t, err)
+		_, err = authService.RefreshToken(ctx, resp.RefreshToken)
+		assert.NoError(t, err)
+	})
+
+	t.Run("NameNoLongerSignsIn", func(t *testing.T) {
+		authService, _, guest := setup(t)
+		_, err := authService.LinkProvider(ctx, guest.Player.ID, googleRequest)
+		require.NoError(t, err)
+
+		resp, err := authService.AuthenticateGuest(ctx, "Guesty", "")
+		require.NoError(t, err)
+		assert.NotEqual(t, guest.Player.ID, resp.Player.ID)
+		assert.True(t, resp.Player.IsGuest())
+	})
+
+	t.Run("AlreadyLinked", func(t *testing.T) {
+		authService, _, guest := setup(t)
+		_, err := authService.LinkProvider(ctx, guest.Player.ID, googleRequest)
+		require.NoError(t, err)
+
+		_, err = authService.LinkProvider(ctx, guest.Player.ID, &models.AuthRequest{Provider: "apple", Token: "mock_apple_token"})
+		var alreadyLinkedErr *models.AccountAlreadyLinkedError
+		require.True(t, errors.As(err, &alreadyLinkedErr))
+		assert.Equal(t, "google", alreadyLinkedErr.Provider)
+	})
+
+	t.Run("IdentityInUse", func(t *testing.T) {
+		authService, mockRepo, guest := setup(t)
+		provider, providerID, email := "google", "google_123", "test@example.com"
+		mockRepo.CreatePlayer(ctx, &models.PlayerData{ID: "other_player", Name: "Other", Provider: &provider, ProviderID: &providerID, Email: &email})
+
+		_, err := authService.LinkProvider(ctx, guest.Player.ID, googleRequest)
+		var identityInUseErr *models.IdentityInUseError
+		require.True(t, errors.As(err, &identityInUseErr))
+
+		player, err := mockRepo.GetPlayerByID(ctx, guest.Player.ID)
+		require.NoError(t, err)
+		assert.True(t, player.IsGuest())
+	})
+
+	t.Run("VerificationFailure", func(t *testing.T) {
+		mockRepo := NewMockPlayerRepository()
+		authService := NewAuthServiceWithTimeFunc(mockRepo, NewMockOAuthVerifierWithFailure(), func() time.Time { return frozenTime })
+		guest, err := authService.AuthenticateGuest(ctx, "Guesty", "")
+		require.NoError(t, err)
+
+		_, err = authService.LinkProvider(ctx, guest.Player.ID, googleRequest)
+		var generalAuthErr *models.GeneralAuthError
+		require.True(t, errors.As(err, &generalAuthErr))
+
+		player, err := mockRepo.GetPlayerByID(ctx, guest.Player.ID)
+		require.NoError(t, err)
+		assert.True(t, player.IsGuest())
+	})
+
+	t.Run("UnsupportedProvider", func(t *testing.T) {
+		authService, _, guest := setup(t)
+
+		_, err := authService.LinkProvider(ctx, guest.Player.ID, &models.AuthRequest{Provider: "facebook", Token: "token"})
+		var unsupportedErr *models.UnsupportedProviderError
+		assert.True(t, errors.As(err, &unsupportedErr))
+	})
+}
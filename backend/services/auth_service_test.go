@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // frozenTime is a fixed time for consistent testing
@@ -44,7 +45,7 @@ func (m *MockPlayerRepository) GetPlayers(gameId string) ([]models.Player, error
 
 func (m *MockPlayerRepository) CreatePlayer(ctx context.Context, player *models.PlayerData) error {
 	if player.ID == "" {
-		player.ID = "mock_id_" + frozenTime.String()
+		player.ID = fmt.Sprintf("mock_id_%d", len(m.players)+1)
 	}
 	m.players[player.ID] = player
 	return nil
@@ -144,6 +145,10 @@ func (m *MockPlayerRepository) UpdateAvatarSignedURL(ctx context.Context, player
 	return nil
 }
 
+func (m *MockPlayerRepository) GetPlayersWithAvatarURLExpiringBefore(ctx context.Context, before time.Time) ([]*models.PlayerData, error) {
+	return nil, nil
+}
+
 func (m *MockPlayerRepository) ClearAvatar(ctx context.Context, playerID string) error {
 	return nil
 }
@@ -436,41 +441,56 @@ func TestAuthService_Logout(t *testing.T) {
 	}
 }
 
-func TestAuthService_GenerateAuthToken(t *testing.T) {
+func TestAuthService_IssueTokens(t *testing.T) {
 	mockRepo := NewMockPlayerRepository()
 	authService := NewAuthServiceWithTimeFunc(mockRepo, NewMockOAuthVerifier(), func() time.Time { return frozenTime })
 
 	ctx := context.Background()
-	playerID := "test_player_id"
-
-	token, err := authService.generateAuthToken(ctx, playerID)
-	if err != nil {
-		t.Fatalf("Expected no error, got %v", err)
-	}
+	player := &models.PlayerData{ID: "test_player_id", Name: "Test Player"}
 
-	if token == "" {
-		t.Error("Expected non-empty token")
-	}
+	resp, err := authService.issueTokens(ctx, player, nil)
+	require.NoError(t, err)
+	assert.NotEmpty(t, resp.Token)
+	assert.NotEmpty(t, resp.RefreshToken)
+
+	// The access token is signed and expires after 15 minutes
+	assert.Equal(t, frozenTime.Add(DefaultAccessTokenTTL), resp.ExpiresAt)
+	validated, err := authService.ValidateToken(ctx, resp.Token)
+	require.NoError(t, err)
+	assert.Equal(t, player.ID, validated.ID)
+	assert.Equal(t, player.Name, validated.Name)
+
+	// Only the hash of the refresh token is stored
+	stored, err := authService.refreshTokenRepo.GetRefreshToken(ctx, hashRefreshToken(resp.RefreshToken))
+	require.NoError(t, err)
+	require.NotNil(t, stored)
+	assert.Equal(t, player.ID, stored.PlayerID)
+	assert.NotEmpty(t, stored.FamilyID)
+	assert.NotEqual(t, resp.RefreshToken, stored.TokenHash)
+	assert.Equal(t, frozenTime.Add(DefaultRefreshTokenTTL), stored.ExpiresAt)
+}
 
-	// Verify token was created in repository
-	authToken, err := mockRepo.GetAuthToken(ctx, token)
-	if err != nil {
-		t.Fatalf("Expected no error, got %v", err)
-	}
+func TestAuthService_ValidateToken_SignedToken(t *testing.T) {
+	mockRepo := NewMockPlayerRepository()
+	now := frozenTime
+	authService := NewAuthServiceWithTimeFunc(mockRepo, NewMockOAuthVerifier(), func() time.Time { return now })
 
-	if authToken == nil {
-		t.Error("Expected auth token to be created in repository")
-	}
+	ctx := context.Background()
+	resp, err := authService.issueTokens(ctx, &models.PlayerData{ID: "test_player_id", Name: "Test Player"}, nil)
+	require.NoError(t, err)
 
-	if authToken.PlayerID != playerID {
-		t.Errorf("Expected player ID %s, got %s", playerID, authToken.PlayerID)
-	}
+	t.Run("Tampered", func(t *testing.T) {
+		_, err := authService.ValidateToken(ctx, resp.Token+"x")
+		var playerNotFoundErr *models.PlayerNotFoundError
+		assert.True(t, errors.As(err, &playerNotFoundErr))
+	})
 
-	// Verify token expires in 24 hours
-	expectedExpiry := frozenTime.Add(24 * time.Hour)
-	if authToken.ExpiresAt != expectedExpiry {
-		t.Errorf("Expected token to expire at %v, got %v", expectedExpiry, authToken.ExpiresAt)
-	}
+	t.Run("Expired", func(t *testing.T) {
+		now = frozenTime.Add(DefaultAccessTokenTTL)
+		_, err := authService.ValidateToken(ctx, resp.Token)
+		var tokenExpiredErr *models.TokenExpiredError
+		assert.True(t, errors.As(err, &tokenExpiredErr))
+	})
 }
 
 func TestAuthService_UpdateDisplayName(t *testing.T) {
@@ -576,13 +596,26 @@ func TestRefreshToken(t *testing.T) {
 
 	// Test successful token refresh
 	t.Run("Success", func(t *testing.T) {
-		resp, err := authService.RefreshToken(ctx, "expired_token_123")
+		signIn, err := authService.issueTokens(ctx, player, nil)
+		require.NoError(t, err)
+
+		resp, err := authService.RefreshToken(ctx, signIn.RefreshToken)
 		assert.NoError(t, err)
 		assert.NotNil(t, resp)
 		assert.Equal(t, player.ID, resp.Player.ID)
 		assert.Equal(t, player.Name, resp.Player.Name)
 		assert.NotEmpty(t, resp.Token)
-		assert.NotEqual(t, "expired_token_123", resp.Token) // Should be a new token
+		assert.NotEqual(t, signIn.RefreshToken, resp.RefreshToken) // Should be a new token
+	})
+
+	// Test refresh with an expired legacy token
+	t.Run("ExpiredLegacyToken", func(t *testing.T) {
+		resp, err := authService.RefreshToken(ctx, "expired_token_123")
+		assert.Error(t, err)
+		assert.Nil(t, resp)
+
+		var tokenExpiredErr *models.TokenExpiredError
+		assert.True(t, errors.As(err, &tokenExpiredErr))
 	})
 
 	// Test refresh with invalid token
@@ -630,14 +663,144 @@ func TestRefreshTokenWithExpiredToken(t *testing.T) {
 	}
 	mockRepo.CreateAuthToken(ctx, expiredToken)
 
-	// Test refresh with expired token - should work now
+	// Expired tokens can't be refreshed, the player has to sign in again
 	resp, err := authService.RefreshToken(ctx, "expired_token_123")
-	assert.NoError(t, err)
-	assert.NotNil(t, resp)
-	assert.Equal(t, player.ID, resp.Player.ID)
-	assert.Equal(t, player.Name, resp.Player.Name)
-	assert.NotEmpty(t, resp.Token)
-	assert.NotEqual(t, "expired_token_123", resp.Token) // Should be a new token
+	assert.Error(t, err)
+	assert.Nil(t, resp)
+
+	var tokenExpiredErr *models.TokenExpiredError
+	assert.True(t, errors.As(err, &tokenExpiredErr))
+}
+
+// TestRefreshTokenRotation tests that refresh tokens are single use, and that reusing one revokes its family
+func TestRefreshTokenRotation(t *testing.T) {
+	mockRepo := NewMockPlayerRepository()
+	now := frozenTime
+	authService := NewAuthServiceWithTimeFunc(mockRepo, NewMockOAuthVerifier(), func() time.Time { return now })
+
+	ctx := context.Background()
+	player := &models.PlayerData{ID: "test_player_id", Name: "Test Player"}
+	mockRepo.CreatePlayer(ctx, player)
+
+	t.Run("ReuseRevokesFamily", func(t *testing.T) {
+		signIn, err := authService.issueTokens(ctx, player, nil)
+		require.NoError(t, err)
+
+		rotated, err := authService.RefreshToken(ctx, signIn.RefreshToken)
+		require.NoError(t, err)
+
+		// The first token was already rotated, presenting it again is a reuse
+		_, err = authService.RefreshToken(ctx, signIn.RefreshToken)
+		var reusedErr *models.RefreshTokenReusedError
+		require.True(t, errors.As(err, &reusedErr))
+
+		// The legitimate rotated token is revoked as well
+		_, err = authService.RefreshToken(ctx, rotated.RefreshToken)
+		assert.True(t, errors.As(err, &reusedErr))
+	})
+
+	t.Run("OtherFamiliesUntouched", func(t *testing.T) {
+		phone, err := authService.issueTokens(ctx, player, nil)
+		require.NoError(t, err)
+		tablet, err := authService.issueTokens(ctx, player, nil)
+		require.NoError(t, err)
+
+		_, err = authService.RefreshToken(ctx, phone.RefreshToken)
+		require.NoError(t, err)
+		_, err = authService.RefreshToken(ctx, phone.RefreshToken)
+		require.Error(t, err)
+
+		_, err = authService.RefreshToken(ctx, tablet.RefreshToken)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		signIn, err := authService.issueTokens(ctx, player, nil)
+		require.NoError(t, err)
+
+		now = frozenTime.Add(DefaultRefreshTokenTTL)
+		defer func() { now = frozenTime }()
+
+		_, err = authService.RefreshToken(ctx, signIn.RefreshToken)
+		var tokenExpiredErr *models.TokenExpiredError
+		assert.True(t, errors.As(err, &tokenExpiredErr))
+	})
+
+	t.Run("LogoutRevokesFamily", func(t *testing.T) {
+		signIn, err := authService.issueTokens(ctx, player, nil)
+		require.NoError(t, err)
+		rotated, err := authService.RefreshToken(ctx, signIn.RefreshToken)
+		require.NoError(t, err)
+
+		require.NoError(t, authService.Logout(ctx, rotated.RefreshToken))
+
+		_, err = authService.RefreshToken(ctx, rotated.RefreshToken)
+		assert.Error(t, err)
+	})
+}
+
+func TestRenewAccessToken(t *testing.T) {
+	mockRepo := NewMockPlayerRepository()
+	now := frozenTime
+	authService := NewAuthServiceWithTimeFunc(mockRepo, NewMockOAuthVerifier(), func() time.Time { return now })
+
+	ctx := context.Background()
+	player := &models.PlayerData{ID: "test_player_id", Name: "Test Player"}
+	mockRepo.CreatePlayer(ctx, player)
+	defer func() { now = frozenTime }()
+
+	t.Run("ActiveSession", func(t *testing.T) {
+		now = frozenTime
+		signIn, err := authService.issueTokens(ctx, player, nil)
+		require.NoError(t, err)
+		sessionID := authService.sessionID(signIn.Token)
+		require.NotEmpty(t, sessionID)
+
+		now = frozenTime.Add(DefaultAccessTokenTTL + time.Hour)
+		renewed, err := authService.RenewAccessToken(ctx, signIn.Token)
+		require.NoError(t, err)
+		assert.Empty(t, renewed.RefreshToken)
+		assert.Equal(t, now.Add(DefaultAccessTokenTTL), renewed.ExpiresAt)
+
+		validated, err := authService.ValidateToken(ctx, renewed.Token)
+		require.NoError(t, err)
+		assert.Equal(t, player.ID, validated.ID)
+		assert.Equal(t, sessionID, authService.sessionID(renewed.Token), "the renewed token stays in the session")
+	})
+
+	t.Run("SignedOut", func(t *testing.T) {
+		now = frozenTime
+		signIn, err := authService.issueTokens(ctx, player, nil)
+		require.NoError(t, err)
+		require.NoError(t, authService.Logout(ctx, signIn.RefreshToken))
+
+		now = frozenTime.Add(DefaultAccessTokenTTL + time.Hour)
+		_, err = authService.RenewAccessToken(ctx, signIn.Token)
+		var tokenExpiredErr *models.TokenExpiredError
+		assert.True(t, errors.As(err, &tokenExpiredErr))
+	})
+
+	t.Run("ExpiredTooLongAgo", func(t *testing.T) {
+		now = frozenTime
+		signIn, err := authService.issueTokens(ctx, player, nil)
+		require.NoError(t, err)
+
+		// The session is still active, but the token is too old to be renewed
+		now = frozenTime.Add(DefaultAccessTokenTTL + AccessTokenRenewalWindow + time.Second)
+		_, err = authService.RenewAccessToken(ctx, signIn.Token)
+		var tokenExpiredErr *models.TokenExpiredError
+		assert.True(t, errors.As(err, &tokenExpiredErr))
+	})
+
+	t.Run("Tampered", func(t *testing.T) {
+		now = frozenTime
+		signIn, err := authService.issueTokens(ctx, player, nil)
+		require.NoError(t, err)
+
+		_, err = authService.RenewAccessToken(ctx, signIn.Token+"x")
+		var playerNotFoundErr *models.PlayerNotFoundError
+		assert.True(t, errors.As(err, &playerNotFoundErr))
+	})
 }
 
 // TestRefreshTokenWithValidToken tests refresh when token is still valid
@@ -782,6 +945,10 @@ func (m *MockPlayerRepositoryWithErrors) UpdateAvatarSignedURL(ctx context.Conte
 	return fmt.Errorf("mock error")
 }
 
+func (m *MockPlayerRepositoryWithErrors) GetPlayersWithAvatarURLExpiringBefore(ctx context.Context, before time.Time) ([]*models.PlayerData, error) {
+	return nil, fmt.Errorf("mock error")
+}
+
 func (m *MockPlayerRepositoryWithErrors) ClearAvatar(ctx context.Context, playerID string) error {
 	return fmt.Errorf("mock error")
 }
@@ -790,3 +957,86 @@ func (m *MockPlayerRepositoryWithErrors) ClearAvatar(ctx context.Context, player
 func stringPtr(s string) *string {
 	return &s
 }
+
+// TestAuthService_Sessions tests listing and revoking the sessions of a player
+func TestAuthService_Sessions(t *testing.T) {
+	mockRepo := NewMockPlayerRepository()
+	now := frozenTime
+	authService := NewAuthServiceWithTimeFunc(mockRepo, NewMockOAuthVerifier(), func() time.Time { return now })
+
+	ctx := context.Background()
+	player := &models.PlayerData{ID: "test_player_id", Name: "Test Player"}
+	mockRepo.CreatePlayer(ctx, player)
+
+	phoneCtx := WithDeviceInfo(ctx, models.DeviceInfo{Name: "Pixel", Platform: "android", AppVersion: "1.4.0", IPAddress: "10.0.0.1"})
+	phone, err := authService.issueTokens(phoneCtx, player, nil)
+	require.NoError(t, err)
+	now = frozenTime.Add(time.Minute)
+	tablet, err := authService.issueTokens(WithDeviceInfo(ctx, models.DeviceInfo{Name: "iPad", Platform: "ios"}), player, nil)
+	require.NoError(t, err)
+
+	t.Run("RefreshKeepsSession", func(t *testing.T) {
+		now = frozenTime.Add(2 * time.Minute)
+		// The refresh comes from another network, without the device name
+		refreshed, err := authService.RefreshToken(WithDeviceInfo(ctx, models.DeviceInfo{AppVersion: "1.5.0", IPAddress: "10.0.0.2"}), phone.RefreshToken)
+		require.NoError(t, err)
+		phone = refreshed
+
+		sessions, err := authService.ListSessions(ctx, player.ID, phone.Token)
+		require.NoError(t, err)
+		require.Len(t, sessions, 2)
+
+		current := sessions[0]
+		assert.True(t, current.Current)
+		assert.Equal(t, "Pixel", current.Name)
+		assert.Equal(t, "android", current.Platform)
+		assert.Equal(t, "1.5.0", current.AppVersion)
+		assert.Equal(t, "10.0.0.2", current.IPAddress)
+		assert.Equal(t, frozenTime, current.CreatedAt)
+		assert.Equal(t, frozenTime.Add(2*time.Minute), current.LastUsedAt)
+
+		assert.False(t, sessions[1].Current)
+		assert.Equal(t, "iPad", sessions[1].Name)
+	})
+
+	t.Run("RevokeSession", func(t *testing.T) {
+		sessions, err := authService.ListSessions(ctx, player.ID, phone.Token)
+		require.NoError(t, err)
+
+		assert.ErrorIs(t, authService.RevokeSession(ctx, "other_player", sessions[1].ID), ErrSessionNotFound)
+		require.NoError(t, authService.RevokeSession(ctx, player.ID, sessions[1].ID))
+
+		_, err = authService.RefreshToken(ctx, tablet.RefreshToken)
+		assert.Error(t, err)
+		assert.ErrorIs(t, authService.RevokeSession(ctx, player.ID, sessions[1].ID), ErrSessionNotFound)
+	})
+
+	t.Run("RevokeOtherSessions", func(t *testing.T) {
+		laptop, err := authService.issueTokens(ctx, player, nil)
+		require.NoError(t, err)
+		other, err := authService.issueTokens(ctx, player, nil)
+		require.NoError(t, err)
+
+		revoked, err := authService.RevokeOtherSessions(ctx, player.ID, phone.Token)
+		require.NoError(t, err)
+		assert.Equal(t, 2, revoked)
+
+		_, err = authService.RefreshToken(ctx, laptop.RefreshToken)
+		assert.Error(t, err)
+		_, err = authService.RefreshToken(ctx, other.RefreshToken)
+		assert.Error(t, err)
+
+		sessions, err := authService.ListSessions(ctx, player.ID, phone.Token)
+		require.NoError(t, err)
+		require.Len(t, sessions, 1)
+		assert.True(t, sessions[0].Current)
+	})
+
+	t.Run("LogoutWithAccessToken", func(t *testing.T) {
+		require.NoError(t, authService.Logout(ctx, phone.Token))
+
+		sessions, err := authService.ListSessions(ctx, player.ID, phone.Token)
+		require.NoError(t, err)
+		assert.Empty(t, sessions)
+	})
+}
@@ -0,0 +1,118 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"ligain/backend/models"
+	"ligain/backend/repositories"
+	"time"
+)
+
+// BetHistoryService records every bet saved by the players, so that the history of the bets can settle disputes
+type BetHistoryService interface {
+	// GetBetHistory returns the changes of the bets of a game as seen by the player, oldest first.
+	// Only the changes of playerID and on matchID are returned, unless they're empty.
+	// The changes of the other players are only shown once their match has kicked off, without their device name nor IP address
+	GetBetHistory(ctx context.Context, gameID string, player models.Player, playerID string, matchID string) ([]*models.BetChange, error)
+}
+
+// BetHistoryServiceImpl implements BetHistoryService, and records the bets as a BetRecorder
+type BetHistoryServiceImpl struct {
+	historyRepo    repositories.BetHistoryRepository
+	gameRepo       repositories.GameRepository
+	gamePlayerRepo repositories.GamePlayerRepository
+	timeFunc       func() time.Time
+}
+
+// NewBetHistoryService creates a new BetHistoryService instance
+func NewBetHistoryService(
+	historyRepo repositories.BetHistoryRepository,
+	gameRepo repositories.GameRepository,
+	gamePlayerRepo repositories.GamePlayerRepository,
+) *BetHistoryServiceImpl {
+	return NewBetHistoryServiceWithTimeFunc(historyRepo, gameRepo, gamePlayerRepo, time.Now)
+}
+
+// NewBetHistoryServiceWithTimeFunc creates a BetHistoryService with a custom time function (for testing)
+func NewBetHistoryServiceWithTimeFunc(
+	historyRepo repositories.BetHistoryRepository,
+	gameRepo repositories.GameRepository,
+	gamePlayerRepo repositories.GamePlayerRepository,
+	timeFunc func() time.Time,
+) *BetHistoryServiceImpl {
+	return &BetHistoryServiceImpl{
+		historyRepo:    historyRepo,
+		gameRepo:       gameRepo,
+		gamePlayerRepo: gamePlayerRepo,
+		timeFunc:       timeFunc,
+	}
+}
+
+// RecordBet implements BetRecorder, the device is the one set with WithDeviceInfo
+func (s *BetHistoryServiceImpl) RecordBet(ctx context.Context, gameID string, player models.Player, bet *models.Bet, placedAt time.Time) error {
+	change := models.NewBetChange(gameID, player.GetID(), bet, placedAt, deviceInfoFromContext(ctx))
+	if err := s.historyRepo.RecordBetChange(ctx, change); err != nil {
+		return fmt.Errorf("error recording bet change: %v", err)
+	}
+	return nil
+}
+
+// GetBetHistory implements BetHistoryService.GetBetHistory
+func (s *BetHistoryServiceImpl) GetBetHistory(ctx context.Context, gameID string, player models.Player, playerID string, matchID string) ([]*models.BetChange, error) {
+	isInGame, err := s.gamePlayerRepo.IsPlayerInGame(ctx, gameID, player.GetID())
+	if err != nil {
+		return nil, fmt.Errorf("error checking game access: %v", err)
+	}
+	if !isInGame {
+		return nil, ErrPlayerNotInGame
+	}
+
+	game, err := s.gameRepo.GetGame(gameID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting game: %v", err)
+	}
+	if matchID != "" && findGameMatch(game, matchID) == nil {
+		return nil, ErrMatchNotInGame
+	}
+
+	changes, err := s.historyRepo.GetBetHistory(ctx, gameID, playerID, matchID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting bet history: %v", err)
+	}
+
+	started := make(map[string]bool)
+	visible := make([]*models.BetChange, 0, len(changes))
+	for _, change := range changes {
+		if change.PlayerID != player.GetID() {
+			if _, known := started[change.MatchID]; !known {
+				started[change.MatchID] = s.hasStarted(findGameMatch(game, change.MatchID))
+			}
+			if !started[change.MatchID] {
+				continue
+			}
+			change.Device.Name = ""
+			change.Device.IPAddress = ""
+		}
+		visible = append(visible, change)
+	}
+	return visible, nil
+}
+
+// hasStarted checks if the match has kicked off, from its status or its date. A match the game doesn't know is never started
+func (s *BetHistoryServiceImpl) hasStarted(match models.Match) bool {
+	if match == nil {
+		return false
+	}
+	return match.IsInProgress() || match.IsFinished() || !s.timeFunc().Before(match.GetDate())
+}
+
+// findGameMatch returns the match of the game with the given id, whether it's incoming or past, nil when it has none
+func findGameMatch(game models.Game, matchID string) models.Match {
+	if match, err := game.GetMatchById(matchID); err == nil {
+		return match
+	}
+	if result, exists := game.GetPastResults()[matchID]; exists {
+		return result.Match
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"ligain/backend/models"
+	"ligain/backend/repositories"
+	"ligain/backend/rules"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type betHistoryTestSetup struct {
+	service  *BetHistoryServiceImpl
+	registry *GameServiceRegistry
+	gameID   string
+	match    *models.SeasonMatch
+	alice    models.Player
+	bob      models.Player
+	outsider models.Player
+	now      time.Time
+}
+
+func setupBetHistoryService(t *testing.T) *betHistoryTestSetup {
+	setup := &betHistoryTestSetup{
+		match:    models.NewSeasonMatch("Team1", "Team2", "2024", "Premier League", matchTime, 1),
+		alice:    &models.PlayerData{ID: "alice", Name: "Alice"},
+		bob:      &models.PlayerData{ID: "bob", Name: "Bob"},
+		outsider: &models.PlayerData{ID: "outsider", Name: "Outsider"},
+		now:      matchTime.Add(-2 * time.Hour),
+	}
+
+	gameRepo := repositories.NewInMemoryGameRepository()
+	game := rules.NewFreshGame("2024", "Premier League", "Test Game", []models.Player{setup.alice, setup.bob}, []models.Match{setup.match}, &rules.ScorerOriginal{})
+	gameID, err := gameRepo.CreateGame(game)
+	require.NoError(t, err)
+	setup.gameID = gameID
+
+	gamePlayerRepo := repositories.NewInMemoryGamePlayerRepository(repositories.NewInMemoryPlayerRepository())
+	require.NoError(t, gamePlayerRepo.AddPlayerToGame(context.Background(), gameID, setup.alice.GetID()))
+	require.NoError(t, gamePlayerRepo.AddPlayerToGame(context.Background(), gameID, setup.bob.GetID()))
+
+	setup.registry, err = NewGameServiceRegistry(gameRepo, repositories.NewInMemoryBetRepository(), gamePlayerRepo, nil)
+	require.NoError(t, err)
+	setup.service = NewBetHistoryServiceWithTimeFunc(repositories.NewInMemoryBetHistoryRepository(), gameRepo, gamePlayerRepo, func() time.Time { return setup.now })
+	setup.registry.AddBetRecorder(setup.service)
+	return setup
+}
+
+// placeBet saves a bet through the game service, from the given device
+func (s *betHistoryTestSetup) placeBet(t *testing.T, player models.Player, homeGoals, awayGoals int, device models.DeviceInfo) {
+	gameService, exists := s.registry.Get(s.gameID)
+	require.True(t, exists)
+	ctx := WithDeviceInfo(context.Background(), device)
+	require.NoError(t, gameService.UpdatePlayerBet(ctx, player, models.NewBet(s.match, homeGoals, awayGoals), s.now))
+}
+
+func TestBetHistoryService_RecordsEveryChange(t *testing.T) {
+	setup := setupBetHistoryService(t)
+	ctx := context.Background()
+	phone := models.DeviceInfo{Name: "Pixel", Platform: "android", AppVersion: "2.1.0", IPAddress: "192.0.2.1"}
+
+	setup.placeBet(t, setup.alice, 2, 1, phone)
+	setup.now = setup.now.Add(time.Hour)
+	setup.placeBet(t, setup.alice, 1, 1, phone)
+
+	history, err := setup.service.GetBetHistory(ctx, setup.gameID, setup.alice, setup.alice.GetID(), "")
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, 2, history[0].PredictedHomeGoals)
+	assert.Equal(t, matchTime.Add(-2*time.Hour), history[0].PlacedAt)
+	assert.Equal(t, 1, history[1].PredictedHomeGoals)
+	assert.Equal(t, matchTime.Add(-time.Hour), history[1].PlacedAt)
+	assert.Equal(t, matchTime, history[1].KickoffAt)
+	assert.True(t, history[1].PlacedBeforeKickoff())
+	assert.Equal(t, phone, history[1].Device)
+}
+
+func TestBetHistoryService_OtherPlayersHiddenUntilKickoff(t *testing.T) {
+	setup := setupBetHistoryService(t)
+	ctx := context.Background()
+
+	setup.placeBet(t, setup.alice, 2, 1, models.DeviceInfo{Name: "Pixel", Platform: "android", IPAddress: "192.0.2.1"})
+	setup.placeBet(t, setup.bob, 0, 0, models.DeviceInfo{Platform: "ios"})
+
+	history, err := setup.service.GetBetHistory(ctx, setup.gameID, setup.bob, "", setup.match.Id())
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, setup.bob.GetID(), history[0].PlayerID)
+
+	setup.now = matchTime
+	history, err = setup.service.GetBetHistory(ctx, setup.gameID, setup.bob, setup.alice.GetID(), "")
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, 2, history[0].PredictedHomeGoals)
+	assert.Equal(t, models.DeviceInfo{Platform: "android"}, history[0].Device, "the device name and IP address of the other players are private")
+}
+
+func TestBetHistoryService_Errors(t *testing.T) {
+	setup := setupBetHistoryService(t)
+	ctx := context.Background()
+
+	_, err := setup.service.GetBetHistory(ctx, setup.gameID, setup.outsider, "", "")
+	assert.ErrorIs(t, err, ErrPlayerNotInGame)
+
+	_, err = setup.service.GetBetHistory(ctx, setup.gameID, setup.alice, "", "unknown")
+	assert.ErrorIs(t, err, ErrMatchNotInGame)
+}
+
+// failingBetHistoryRepository fails to record the changes of the bets
+type failingBetHistoryRepository struct {
+	repositories.BetHistoryRepository
+}
+
+func (r *failingBetHistoryRepository) RecordBetChange(ctx context.Context, change *models.BetChange) error {
+	return errors.New("insert failed")
+}
+
+func TestBetHistoryService_FailingRecordFailsTheBet(t *testing.T) {
+	setup := setupBetHistoryService(t)
+	setup.service.historyRepo = &failingBetHistoryRepository{repositories.NewInMemoryBetHistoryRepository()}
+
+	gameService, exists := setup.registry.Get(setup.gameID)
+	require.True(t, exists)
+	err := gameService.UpdatePlayerBet(context.Background(), setup.alice, models.NewBet(setup.match, 2, 1), setup.now)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "insert failed")
+
+	// The game doesn't hold a bet with no history
+	incoming := gameService.GetIncomingMatches(setup.alice)
+	require.Contains(t, incoming, setup.match.Id())
+	assert.NotContains(t, incoming[setup.match.Id()].Bets, setup.alice.GetID())
+}
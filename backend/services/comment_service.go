@@ -0,0 +1,257 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"ligain/backend/models"
+	"ligain/backend/repositories"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// MaxCommentLength is the maximum number of characters of a comment
+const MaxCommentLength = 500
+
+// AllowedReactions are the emojis players can react with
+var AllowedReactions = []string{"👍", "👎", "😂", "🔥", "😮", "😢", "😡", "⚽", "🎉", "👏"}
+
+var (
+	ErrMatchNotInGame     = errors.New("match is not part of the game")
+	ErrInvalidComment     = fmt.Errorf("comment must be between 1 and %d characters", MaxCommentLength)
+	ErrCommentNotFound    = errors.New("comment not found")
+	ErrNotCommentAuthor   = errors.New("only the author can delete a comment")
+	ErrReactionNotAllowed = errors.New("reaction is not allowed")
+)
+
+// CommentService handles the comments and reactions posted on the matches of a game
+type CommentService interface {
+	// GetComments returns the comments of a match as seen by the player.
+	// Before kickoff, the comments of the other players that mention a bet are hidden
+	GetComments(ctx context.Context, gameID, matchID string, player models.Player) ([]*models.MatchComment, error)
+	// AddComment posts a comment on a match. The comment mentions a bet if the player says so, or if it looks like a score
+	AddComment(ctx context.Context, gameID, matchID string, player models.Player, content string, mentionsBet bool) (*models.MatchComment, error)
+	// DeleteComment removes a comment of the player
+	DeleteComment(ctx context.Context, gameID, matchID, commentID string, player models.Player) error
+	// GetReactions returns how many players reacted to a match with each emoji, most used first
+	GetReactions(ctx context.Context, gameID, matchID string, player models.Player) ([]models.ReactionSummary, error)
+	// AddReaction reacts to a match with an emoji
+	AddReaction(ctx context.Context, gameID, matchID string, player models.Player, emoji string) error
+	// RemoveReaction removes a reaction of the player
+	RemoveReaction(ctx context.Context, gameID, matchID string, player models.Player, emoji string) error
+}
+
+// CommentServiceImpl implements CommentService
+type CommentServiceImpl struct {
+	commentRepo    repositories.CommentRepository
+	gameRepo       repositories.GameRepository
+	gamePlayerRepo repositories.GamePlayerRepository
+	timeFunc       func() time.Time
+}
+
+// NewCommentService creates a new CommentService instance
+func NewCommentService(
+	commentRepo repositories.CommentRepository,
+	gameRepo repositories.GameRepository,
+	gamePlayerRepo repositories.GamePlayerRepository,
+) *CommentServiceImpl {
+	return NewCommentServiceWithTimeFunc(commentRepo, gameRepo, gamePlayerRepo, time.Now)
+}
+
+// NewCommentServiceWithTimeFunc creates a CommentService with a custom time function (for testing)
+func NewCommentServiceWithTimeFunc(
+	commentRepo repositories.CommentRepository,
+	gameRepo repositories.GameRepository,
+	gamePlayerRepo repositories.GamePlayerRepository,
+	timeFunc func() time.Time,
+) *CommentServiceImpl {
+	return &CommentServiceImpl{
+		commentRepo:    commentRepo,
+		gameRepo:       gameRepo,
+		gamePlayerRepo: gamePlayerRepo,
+		timeFunc:       timeFunc,
+	}
+}
+
+// GetComments implements CommentService.GetComments
+func (s *CommentServiceImpl) GetComments(ctx context.Context, gameID, matchID string, player models.Player) ([]*models.MatchComment, error) {
+	match, err := s.getMatch(ctx, gameID, matchID, player)
+	if err != nil {
+		return nil, err
+	}
+
+	comments, err := s.commentRepo.GetComments(ctx, gameID, matchID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting comments: %v", err)
+	}
+
+	started := s.hasStarted(match)
+	for _, comment := range comments {
+		if comment.MentionsBet && !started && comment.PlayerID != player.GetID() {
+			comment.Content = ""
+			comment.Hidden = true
+		}
+	}
+	return comments, nil
+}
+
+// AddComment implements CommentService.AddComment
+func (s *CommentServiceImpl) AddComment(ctx context.Context, gameID, matchID string, player models.Player, content string, mentionsBet bool) (*models.MatchComment, error) {
+	content = strings.TrimSpace(content)
+	if content == "" || utf8.RuneCountInString(content) > MaxCommentLength {
+		return nil, ErrInvalidComment
+	}
+	if _, err := s.getMatch(ctx, gameID, matchID, player); err != nil {
+		return nil, err
+	}
+
+	comment := &models.MatchComment{
+		GameID:      gameID,
+		MatchID:     matchID,
+		PlayerID:    player.GetID(),
+		PlayerName:  player.GetName(),
+		Content:     content,
+		MentionsBet: mentionsBet || models.CommentMentionsBet(content),
+		CreatedAt:   s.timeFunc(),
+	}
+	if err := s.commentRepo.CreateComment(ctx, comment); err != nil {
+		return nil, fmt.Errorf("error saving comment: %v", err)
+	}
+	return comment, nil
+}
+
+// DeleteComment implements CommentService.DeleteComment
+func (s *CommentServiceImpl) DeleteComment(ctx context.Context, gameID, matchID, commentID string, player models.Player) error {
+	if _, err := s.getMatch(ctx, gameID, matchID, player); err != nil {
+		return err
+	}
+
+	comment, err := s.commentRepo.GetComment(ctx, commentID)
+	if errors.Is(err, repositories.ErrCommentNotFound) {
+		return ErrCommentNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("error getting comment: %v", err)
+	}
+	if comment.GameID != gameID || comment.MatchID != matchID {
+		return ErrCommentNotFound
+	}
+	if comment.PlayerID != player.GetID() {
+		return ErrNotCommentAuthor
+	}
+
+	if err := s.commentRepo.DeleteComment(ctx, commentID); err != nil {
+		return fmt.Errorf("error deleting comment: %v", err)
+	}
+	return nil
+}
+
+// GetReactions implements CommentService.GetReactions
+func (s *CommentServiceImpl) GetReactions(ctx context.Context, gameID, matchID string, player models.Player) ([]models.ReactionSummary, error) {
+	if _, err := s.getMatch(ctx, gameID, matchID, player); err != nil {
+		return nil, err
+	}
+
+	reactions, err := s.commentRepo.GetReactions(ctx, gameID, matchID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting reactions: %v", err)
+	}
+
+	summaries := make(map[string]*models.ReactionSummary)
+	for _, reaction := range reactions {
+		summary, exists := summaries[reaction.Emoji]
+		if !exists {
+			summary = &models.ReactionSummary{Emoji: reaction.Emoji}
+			summaries[reaction.Emoji] = summary
+		}
+		summary.Count++
+		if reaction.PlayerID == player.GetID() {
+			summary.ReactedByMe = true
+		}
+	}
+
+	result := make([]models.ReactionSummary, 0, len(summaries))
+	for _, summary := range summaries {
+		result = append(result, *summary)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].Emoji < result[j].Emoji
+	})
+	return result, nil
+}
+
+// AddReaction implements CommentService.AddReaction
+func (s *CommentServiceImpl) AddReaction(ctx context.Context, gameID, matchID string, player models.Player, emoji string) error {
+	if !isAllowedReaction(emoji) {
+		return ErrReactionNotAllowed
+	}
+	if _, err := s.getMatch(ctx, gameID, matchID, player); err != nil {
+		return err
+	}
+
+	reaction := &models.MatchReaction{
+		GameID:    gameID,
+		MatchID:   matchID,
+		PlayerID:  player.GetID(),
+		Emoji:     emoji,
+		CreatedAt: s.timeFunc(),
+	}
+	if err := s.commentRepo.AddReaction(ctx, reaction); err != nil {
+		return fmt.Errorf("error saving reaction: %v", err)
+	}
+	return nil
+}
+
+// RemoveReaction implements CommentService.RemoveReaction
+func (s *CommentServiceImpl) RemoveReaction(ctx context.Context, gameID, matchID string, player models.Player, emoji string) error {
+	if _, err := s.getMatch(ctx, gameID, matchID, player); err != nil {
+		return err
+	}
+
+	if err := s.commentRepo.RemoveReaction(ctx, gameID, matchID, player.GetID(), emoji); err != nil {
+		return fmt.Errorf("error removing reaction: %v", err)
+	}
+	return nil
+}
+
+// getMatch checks that the player is in the game, and returns the match if it's part of it
+func (s *CommentServiceImpl) getMatch(ctx context.Context, gameID, matchID string, player models.Player) (models.Match, error) {
+	isInGame, err := s.gamePlayerRepo.IsPlayerInGame(ctx, gameID, player.GetID())
+	if err != nil {
+		return nil, fmt.Errorf("error checking game access: %v", err)
+	}
+	if !isInGame {
+		return nil, ErrPlayerNotInGame
+	}
+
+	game, err := s.gameRepo.GetGame(gameID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting game: %v", err)
+	}
+	if match, err := game.GetMatchById(matchID); err == nil {
+		return match, nil
+	}
+	if result, exists := game.GetPastResults()[matchID]; exists {
+		return result.Match, nil
+	}
+	return nil, ErrMatchNotInGame
+}
+
+// hasStarted checks if the match has kicked off, from its status or its date
+func (s *CommentServiceImpl) hasStarted(match models.Match) bool {
+	return match.IsInProgress() || match.IsFinished() || !s.timeFunc().Before(match.GetDate())
+}
+
+func isAllowedReaction(emoji string) bool {
+	for _, allowed := range AllowedReactions {
+		if emoji == allowed {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,147 @@
+package services
+
+import (
+	"context"
+	"ligain/backend/models"
+	"ligain/backend/repositories"
+	"ligain/backend/rules"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type commentTestSetup struct {
+	service  *CommentServiceImpl
+	gameID   string
+	match    *models.SeasonMatch
+	alice    models.Player
+	bob      models.Player
+	outsider models.Player
+	now      time.Time
+}
+
+func setupCommentService(t *testing.T) *commentTestSetup {
+	setup := &commentTestSetup{
+		match:    models.NewSeasonMatch("Team1", "Team2", "2024", "Premier League", matchTime, 1),
+		alice:    &models.PlayerData{ID: "alice", Name: "Alice"},
+		bob:      &models.PlayerData{ID: "bob", Name: "Bob"},
+		outsider: &models.PlayerData{ID: "outsider", Name: "Outsider"},
+		now:      matchTime.Add(-2 * time.Hour),
+	}
+
+	gameRepo := repositories.NewInMemoryGameRepository()
+	game := rules.NewFreshGame("2024", "Premier League", "Test Game", []models.Player{setup.alice, setup.bob}, []models.Match{setup.match}, &rules.ScorerOriginal{})
+	gameID, err := gameRepo.CreateGame(game)
+	require.NoError(t, err)
+	setup.gameID = gameID
+
+	gamePlayerRepo := repositories.NewInMemoryGamePlayerRepository(repositories.NewInMemoryPlayerRepository())
+	require.NoError(t, gamePlayerRepo.AddPlayerToGame(context.Background(), gameID, setup.alice.GetID()))
+	require.NoError(t, gamePlayerRepo.AddPlayerToGame(context.Background(), gameID, setup.bob.GetID()))
+
+	setup.service = NewCommentServiceWithTimeFunc(repositories.NewInMemoryCommentRepository(), gameRepo, gamePlayerRepo, func() time.Time { return setup.now })
+	return setup
+}
+
+func TestCommentService_BetCommentsHiddenUntilKickoff(t *testing.T) {
+	setup := setupCommentService(t)
+	ctx := context.Background()
+
+	_, err := setup.service.AddComment(ctx, setup.gameID, setup.match.Id(), setup.alice, "Going for 2-1, easy", false)
+	require.NoError(t, err)
+	_, err = setup.service.AddComment(ctx, setup.gameID, setup.match.Id(), setup.alice, "I know something you don't", true)
+	require.NoError(t, err)
+	_, err = setup.service.AddComment(ctx, setup.gameID, setup.match.Id(), setup.bob, "Come on Team1!", false)
+	require.NoError(t, err)
+
+	comments, err := setup.service.GetComments(ctx, setup.gameID, setup.match.Id(), setup.bob)
+	require.NoError(t, err)
+	require.Len(t, comments, 3)
+	assert.True(t, comments[0].Hidden)
+	assert.Empty(t, comments[0].Content)
+	assert.True(t, comments[1].Hidden)
+	assert.False(t, comments[2].Hidden)
+	assert.Equal(t, "Come on Team1!", comments[2].Content)
+
+	comments, err = setup.service.GetComments(ctx, setup.gameID, setup.match.Id(), setup.alice)
+	require.NoError(t, err)
+	assert.False(t, comments[0].Hidden)
+	assert.Equal(t, "Going for 2-1, easy", comments[0].Content)
+
+	setup.now = matchTime
+	comments, err = setup.service.GetComments(ctx, setup.gameID, setup.match.Id(), setup.bob)
+	require.NoError(t, err)
+	assert.False(t, comments[0].Hidden)
+	assert.Equal(t, "Going for 2-1, easy", comments[0].Content)
+	assert.Equal(t, "Alice", comments[0].PlayerName)
+}
+
+func TestCommentService_Validation(t *testing.T) {
+	setup := setupCommentService(t)
+	ctx := context.Background()
+
+	_, err := setup.service.AddComment(ctx, setup.gameID, setup.match.Id(), setup.alice, "   ", false)
+	assert.ErrorIs(t, err, ErrInvalidComment)
+
+	tooLong := make([]rune, MaxCommentLength+1)
+	for i := range tooLong {
+		tooLong[i] = 'a'
+	}
+	_, err = setup.service.AddComment(ctx, setup.gameID, setup.match.Id(), setup.alice, string(tooLong), false)
+	assert.ErrorIs(t, err, ErrInvalidComment)
+
+	_, err = setup.service.AddComment(ctx, setup.gameID, "unknown-match", setup.alice, "Hello", false)
+	assert.ErrorIs(t, err, ErrMatchNotInGame)
+
+	_, err = setup.service.AddComment(ctx, setup.gameID, setup.match.Id(), setup.outsider, "Hello", false)
+	assert.ErrorIs(t, err, ErrPlayerNotInGame)
+
+	err = setup.service.AddReaction(ctx, setup.gameID, setup.match.Id(), setup.alice, "🍕")
+	assert.ErrorIs(t, err, ErrReactionNotAllowed)
+}
+
+func TestCommentService_DeleteComment(t *testing.T) {
+	setup := setupCommentService(t)
+	ctx := context.Background()
+
+	comment, err := setup.service.AddComment(ctx, setup.gameID, setup.match.Id(), setup.alice, "Hello", false)
+	require.NoError(t, err)
+
+	err = setup.service.DeleteComment(ctx, setup.gameID, setup.match.Id(), comment.ID, setup.bob)
+	assert.ErrorIs(t, err, ErrNotCommentAuthor)
+
+	require.NoError(t, setup.service.DeleteComment(ctx, setup.gameID, setup.match.Id(), comment.ID, setup.alice))
+
+	err = setup.service.DeleteComment(ctx, setup.gameID, setup.match.Id(), comment.ID, setup.alice)
+	assert.ErrorIs(t, err, ErrCommentNotFound)
+
+	comments, err := setup.service.GetComments(ctx, setup.gameID, setup.match.Id(), setup.alice)
+	require.NoError(t, err)
+	assert.Empty(t, comments)
+}
+
+func TestCommentService_Reactions(t *testing.T) {
+	setup := setupCommentService(t)
+	ctx := context.Background()
+
+	require.NoError(t, setup.service.AddReaction(ctx, setup.gameID, setup.match.Id(), setup.alice, "🔥"))
+	require.NoError(t, setup.service.AddReaction(ctx, setup.gameID, setup.match.Id(), setup.alice, "🔥"))
+	require.NoError(t, setup.service.AddReaction(ctx, setup.gameID, setup.match.Id(), setup.bob, "🔥"))
+	require.NoError(t, setup.service.AddReaction(ctx, setup.gameID, setup.match.Id(), setup.bob, "⚽"))
+
+	reactions, err := setup.service.GetReactions(ctx, setup.gameID, setup.match.Id(), setup.alice)
+	require.NoError(t, err)
+	assert.Equal(t, []models.ReactionSummary{
+		{Emoji: "🔥", Count: 2, ReactedByMe: true},
+		{Emoji: "⚽", Count: 1, ReactedByMe: false},
+	}, reactions)
+
+	require.NoError(t, setup.service.RemoveReaction(ctx, setup.gameID, setup.match.Id(), setup.alice, "🔥"))
+	reactions, err = setup.service.GetReactions(ctx, setup.gameID, setup.match.Id(), setup.alice)
+	require.NoError(t, err)
+	require.Len(t, reactions, 2)
+	assert.Equal(t, 1, reactions[0].Count)
+	assert.False(t, reactions[0].ReactedByMe)
+}
@@ -0,0 +1,339 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"ligain/backend/models"
+	"ligain/backend/repositories"
+	"ligain/backend/storage"
+	"path"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// DataExportRetention is how long the archive of a data export is kept before being deleted
+	DataExportRetention = 7 * 24 * time.Hour
+	// DataExportSignedURLTTL is the lifetime of the download URLs of the archives
+	DataExportSignedURLTTL = 24 * time.Hour
+	// DataExportContentType is the content type of the archives
+	DataExportContentType = "application/zip"
+	// dataExportBuildTimeout bounds the time spent building an archive in the background
+	dataExportBuildTimeout = 5 * time.Minute
+)
+
+// ErrDataExportNotFound is returned when a player asks for an export that doesn't exist or isn't theirs
+var ErrDataExportNotFound = errors.New("data export not found")
+
+// DataExportService builds archives of the personal data of the players
+type DataExportService interface {
+	// RequestExport starts building an archive of the player's data in the background.
+	// If an export of the player is still being built, it is returned instead of starting a new one.
+	// An export pending for longer than a build can take is failed, its build stopped with the instance running it
+	RequestExport(ctx context.Context, playerID string) (*models.DataExport, error)
+	// GetExport returns an export of the player, with a download URL once its archive is ready
+	GetExport(ctx context.Context, playerID string, exportID string) (*models.DataExport, error)
+	// CleanupExpiredExports deletes the exports whose archive expired
+	CleanupExpiredExports(ctx context.Context) error
+}
+
+// DataExportServiceImpl implements DataExportService
+type DataExportServiceImpl struct {
+	exportRepo       repositories.DataExportRepository
+	playerRepo       repositories.PlayerRepository
+	refreshTokenRepo repositories.RefreshTokenRepository
+	gamePlayerRepo   repositories.GamePlayerRepository
+	gameRepo         repositories.GameRepository
+	betRepo          repositories.BetRepository
+	betHistoryRepo   repositories.BetHistoryRepository
+	commentRepo      repositories.CommentRepository
+	blobStorage      storage.BlobStorage
+	timeFunc         func() time.Time
+	// builds tracks the archives being built in the background
+	builds sync.WaitGroup
+}
+
+// NewDataExportService creates a new DataExportService instance
+func NewDataExportService(
+	exportRepo repositories.DataExportRepository,
+	playerRepo repositories.PlayerRepository,
+	refreshTokenRepo repositories.RefreshTokenRepository,
+	gamePlayerRepo repositories.GamePlayerRepository,
+	gameRepo repositories.GameRepository,
+	betRepo repositories.BetRepository,
+	betHistoryRepo repositories.BetHistoryRepository,
+	commentRepo repositories.CommentRepository,
+	blobStorage storage.BlobStorage,
+) *DataExportServiceImpl {
+	return NewDataExportServiceWithTimeFunc(exportRepo, playerRepo, refreshTokenRepo, gamePlayerRepo, gameRepo, betRepo, betHistoryRepo, commentRepo, blobStorage, time.Now)
+}
+
+// NewDataExportServiceWithTimeFunc creates a DataExportService with a custom time function (for testing)
+func NewDataExportServiceWithTimeFunc(
+	exportRepo repositories.DataExportRepository,
+	playerRepo repositories.PlayerRepository,
+	refreshTokenRepo repositories.RefreshTokenRepository,
+	gamePlayerRepo repositories.GamePlayerRepository,
+	gameRepo repositories.GameRepository,
+	betRepo repositories.BetRepository,
+	betHistoryRepo repositories.BetHistoryRepository,
+	commentRepo repositories.CommentRepository,
+	blobStorage storage.BlobStorage,
+	timeFunc func() time.Time,
+) *DataExportServiceImpl {
+	return &DataExportServiceImpl{
+		exportRepo:       exportRepo,
+		playerRepo:       playerRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		gamePlayerRepo:   gamePlayerRepo,
+		gameRepo:         gameRepo,
+		betRepo:          betRepo,
+		betHistoryRepo:   betHistoryRepo,
+		commentRepo:      commentRepo,
+		blobStorage:      blobStorage,
+		timeFunc:         timeFunc,
+	}
+}
+
+// RequestExport implements DataExportService
+func (s *DataExportServiceImpl) RequestExport(ctx context.Context, playerID string) (*models.DataExport, error) {
+	latest, err := s.exportRepo.GetLatestExport(ctx, playerID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting latest data export: %v", err)
+	}
+	if latest != nil && latest.Status == models.DataExportPending {
+		if !isStaleExport(latest, s.timeFunc()) {
+			return latest, nil
+		}
+		failedAt := s.timeFunc()
+		latest.Status = models.DataExportFailed
+		latest.CompletedAt = &failedAt
+		if err := s.exportRepo.UpdateExport(ctx, latest); err != nil {
+			return nil, fmt.Errorf("error failing stale data export: %v", err)
+		}
+		log.Warnf("Data export %s was still pending after %v, marked as failed", latest.ID, dataExportBuildTimeout)
+	}
+
+	export := &models.DataExport{
+		PlayerID:  playerID,
+		Status:    models.DataExportPending,
+		CreatedAt: s.timeFunc(),
+	}
+	if err := s.exportRepo.CreateExport(ctx, export); err != nil {
+		return nil, fmt.Errorf("error creating data export: %v", err)
+	}
+
+	// The archive outlives the request, so it's built with its own context
+	s.builds.Add(1)
+	go func(export models.DataExport) {
+		defer s.builds.Done()
+		buildCtx, cancel := context.WithTimeout(context.Background(), dataExportBuildTimeout)
+		defer cancel()
+		s.buildExport(buildCtx, &export)
+	}(*export)
+
+	log.Infof("Player %s requested data export %s", playerID, export.ID)
+	return export, nil
+}
+
+// Wait blocks until the archives being built in the background are done
+func (s *DataExportServiceImpl) Wait() {
+	s.builds.Wait()
+}
+
+// GetExport implements DataExportService
+func (s *DataExportServiceImpl) GetExport(ctx context.Context, playerID string, exportID string) (*models.DataExport, error) {
+	export, err := s.exportRepo.GetExport(ctx, exportID)
+	if errors.Is(err, repositories.ErrDataExportNotFound) {
+		return nil, ErrDataExportNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting data export: %v", err)
+	}
+	if export.PlayerID != playerID {
+		return nil, ErrDataExportNotFound
+	}
+
+	now := s.timeFunc()
+	if isStaleExport(export, now) {
+		export.Status = models.DataExportFailed
+	}
+	if export.Status != models.DataExportReady || export.IsExpired(now) {
+		return export, nil
+	}
+
+	// The URL must not outlive the archive
+	ttl := DataExportSignedURLTTL
+	if untilExpiry := export.ExpiresAt.Sub(now); untilExpiry < ttl {
+		ttl = untilExpiry
+	}
+	export.DownloadURL, err = s.blobStorage.GenerateSignedURL(ctx, export.ObjectKey, ttl)
+	if err != nil {
+		return nil, fmt.Errorf("error generating data export URL: %v", err)
+	}
+	return export, nil
+}
+
+// CleanupExpiredExports implements DataExportService
+func (s *DataExportServiceImpl) CleanupExpiredExports(ctx context.Context) error {
+	expired, err := s.exportRepo.GetExpiredExports(ctx, s.timeFunc())
+	if err != nil {
+		return fmt.Errorf("error getting expired data exports: %v", err)
+	}
+
+	for _, export := range expired {
+		if export.ObjectKey != "" {
+			if err := s.blobStorage.Delete(ctx, export.ObjectKey); err != nil {
+				return fmt.Errorf("error deleting data export archive: %v", err)
+			}
+		}
+		if err := s.exportRepo.DeleteExport(ctx, export.ID); err != nil {
+			return fmt.Errorf("error deleting data export: %v", err)
+		}
+	}
+	if len(expired) > 0 {
+		log.Infof("Deleted %d expired data exports", len(expired))
+	}
+	return nil
+}
+
+// isStaleExport checks if an export is still pending after the time a build can take, which means it will never be done
+func isStaleExport(export *models.DataExport, now time.Time) bool {
+	return export.Status == models.DataExportPending && now.Sub(export.CreatedAt) >= dataExportBuildTimeout
+}
+
+// buildExport builds and uploads the archive of an export, then marks it ready, or failed if anything went wrong
+func (s *DataExportServiceImpl) buildExport(ctx context.Context, export *models.DataExport) {
+	objectKey := fmt.Sprintf("exports/%s/%s.zip", export.PlayerID, export.ID)
+
+	archive, err := s.buildArchive(ctx, export.PlayerID)
+	if err == nil {
+		err = s.blobStorage.Upload(ctx, objectKey, archive, DataExportContentType)
+	}
+
+	now := s.timeFunc()
+	export.CompletedAt = &now
+	if err != nil {
+		log.Errorf("Error building data export %s: %v", export.ID, err)
+		export.Status = models.DataExportFailed
+	} else {
+		expiresAt := now.Add(DataExportRetention)
+		export.Status = models.DataExportReady
+		export.ObjectKey = objectKey
+		export.ExpiresAt = &expiresAt
+	}
+
+	if err := s.exportRepo.UpdateExport(ctx, export); err != nil {
+		log.Errorf("Error saving data export %s: %v", export.ID, err)
+	}
+}
+
+// buildArchive gathers the data of a player into a zip with a data.json file and their avatar
+func (s *DataExportServiceImpl) buildArchive(ctx context.Context, playerID string) ([]byte, error) {
+	data, avatar, err := s.collectPlayerData(ctx, playerID)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	zipWriter := zip.NewWriter(&buf)
+
+	jsonWriter, err := zipWriter.Create("data.json")
+	if err != nil {
+		return nil, err
+	}
+	encoder := json.NewEncoder(jsonWriter)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(data); err != nil {
+		return nil, fmt.Errorf("error encoding player data: %v", err)
+	}
+
+	if data.Avatar != "" {
+		avatarWriter, err := zipWriter.Create(data.Avatar)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := avatarWriter.Write(avatar); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := zipWriter.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// collectPlayerData reads everything stored about a player, and the content of their avatar if they have one
+func (s *DataExportServiceImpl) collectPlayerData(ctx context.Context, playerID string) (*models.PlayerDataArchive, []byte, error) {
+	player, err := s.playerRepo.GetPlayerByID(ctx, playerID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error getting player: %v", err)
+	}
+	if player == nil {
+		return nil, nil, &models.PlayerNotFoundError{Reason: "player not found"}
+	}
+
+	now := s.timeFunc()
+	data := &models.PlayerDataArchive{
+		ExportedAt: now,
+		Profile:    player,
+		Sessions:   make([]*models.Session, 0),
+		Games:      make([]models.PlayerGameRecord, 0),
+	}
+
+	tokens, err := s.refreshTokenRepo.GetActiveRefreshTokens(ctx, playerID, now)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error getting sessions: %v", err)
+	}
+	for _, token := range tokens {
+		data.Sessions = append(data.Sessions, models.NewSessionFromRefreshToken(token, ""))
+	}
+
+	gameIDs, err := s.gamePlayerRepo.GetPlayerGames(ctx, playerID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error getting player games: %v", err)
+	}
+	for _, gameID := range gameIDs {
+		game, err := s.gameRepo.GetGame(gameID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error getting game %s: %v", gameID, err)
+		}
+		data.Games = append(data.Games, models.PlayerGameRecord{
+			GameID:          gameID,
+			Name:            game.GetName(),
+			CompetitionName: game.GetCompetitionName(),
+			SeasonYear:      game.GetSeasonYear(),
+		})
+	}
+
+	if data.Bets, err = s.betRepo.GetPlayerBets(playerID); err != nil {
+		return nil, nil, fmt.Errorf("error getting player bets: %v", err)
+	}
+	if data.BetHistory, err = s.betHistoryRepo.GetPlayerBetHistory(ctx, playerID); err != nil {
+		return nil, nil, fmt.Errorf("error getting player bet history: %v", err)
+	}
+	if data.Comments, err = s.commentRepo.GetPlayerComments(ctx, playerID); err != nil {
+		return nil, nil, fmt.Errorf("error getting player comments: %v", err)
+	}
+	if data.Reactions, err = s.commentRepo.GetPlayerReactions(ctx, playerID); err != nil {
+		return nil, nil, fmt.Errorf("error getting player reactions: %v", err)
+	}
+
+	var avatar []byte
+	if player.AvatarObjectKey != nil && *player.AvatarObjectKey != "" {
+		avatar, err = s.blobStorage.Download(ctx, *player.AvatarObjectKey)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error downloading avatar: %v", err)
+		}
+		data.Avatar = "avatar" + path.Ext(*player.AvatarObjectKey)
+	}
+
+	return data, avatar, nil
+}
@@ -0,0 +1,257 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"ligain/backend/models"
+	"ligain/backend/repositories"
+	"ligain/backend/rules"
+	"ligain/backend/storage"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type dataExportTestSetup struct {
+	service     *DataExportServiceImpl
+	exportRepo  *repositories.InMemoryDataExportRepository
+	blobStorage *storage.MockBlobStorage
+	player      *models.PlayerData
+	gameID      string
+	match       *models.SeasonMatch
+	now         time.Time
+}
+
+func setupDataExportService(t *testing.T) *dataExportTestSetup {
+	ctx := context.Background()
+	avatarKey := "avatars/exporter/avatar.webp"
+	setup := &dataExportTestSetup{
+		exportRepo:  repositories.NewInMemoryDataExportRepository(),
+		blobStorage: storage.NewMockBlobStorage(),
+		player:      &models.PlayerData{ID: "exporter", Name: "Exporter", AvatarObjectKey: &avatarKey},
+		match:       models.NewSeasonMatch("Team1", "Team2", "2024", "Premier League", matchTime, 1),
+		now:         frozenTime,
+	}
+	require.NoError(t, setup.blobStorage.Upload(ctx, avatarKey, []byte("webp"), AvatarContentType))
+
+	playerRepo := NewMockPlayerRepository()
+	require.NoError(t, playerRepo.CreatePlayer(ctx, setup.player))
+
+	gameRepo := repositories.NewInMemoryGameRepository()
+	game := rules.NewFreshGame("2024", "Premier League", "Export Game", []models.Player{setup.player}, []models.Match{setup.match}, &rules.ScorerOriginal{})
+	gameID, err := gameRepo.CreateGame(game)
+	require.NoError(t, err)
+	setup.gameID = gameID
+
+	gamePlayerRepo := repositories.NewInMemoryGamePlayerRepository(repositories.NewInMemoryPlayerRepository())
+	require.NoError(t, gamePlayerRepo.AddPlayerToGame(ctx, gameID, setup.player.ID))
+
+	betRepo := repositories.NewInMemoryBetRepository()
+	_, _, err = betRepo.SaveBet(context.Background(), gameID, models.NewBet(setup.match, 2, 1), setup.player)
+	require.NoError(t, err)
+
+	betHistoryRepo := repositories.NewInMemoryBetHistoryRepository()
+	require.NoError(t, betHistoryRepo.RecordBetChange(ctx, models.NewBetChange(gameID, setup.player.ID, models.NewBet(setup.match, 2, 1), frozenTime, models.DeviceInfo{Platform: "android", IPAddress: "192.0.2.1"})))
+
+	commentRepo := repositories.NewInMemoryCommentRepository()
+	require.NoError(t, commentRepo.CreateComment(ctx, &models.MatchComment{
+		GameID:    gameID,
+		MatchID:   setup.match.Id(),
+		PlayerID:  setup.player.ID,
+		Content:   "Team1 all the way",
+		CreatedAt: frozenTime,
+	}))
+
+	refreshTokenRepo := repositories.NewInMemoryRefreshTokenRepository()
+	require.NoError(t, refreshTokenRepo.CreateRefreshToken(ctx, &models.RefreshToken{
+		PlayerID:         setup.player.ID,
+		FamilyID:         "family1",
+		TokenHash:        "hash1",
+		Device:           models.DeviceInfo{Name: "Pixel", Platform: "android"},
+		SessionStartedAt: frozenTime,
+		ExpiresAt:        frozenTime.Add(time.Hour),
+		CreatedAt:        frozenTime,
+	}))
+
+	setup.service = NewDataExportServiceWithTimeFunc(setup.exportRepo, playerRepo, refreshTokenRepo, gamePlayerRepo, gameRepo, betRepo, betHistoryRepo, commentRepo, setup.blobStorage, func() time.Time { return setup.now })
+	return setup
+}
+
+// readArchive returns the files of a zip archive by name
+func readArchive(t *testing.T, archive []byte) map[string][]byte {
+	reader, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	require.NoError(t, err)
+
+	files := make(map[string][]byte)
+	for _, file := range reader.File {
+		content, err := file.Open()
+		require.NoError(t, err)
+		data, err := io.ReadAll(content)
+		require.NoError(t, err)
+		content.Close()
+		files[file.Name] = data
+	}
+	return files
+}
+
+func TestDataExportService_RequestExport(t *testing.T) {
+	setup := setupDataExportService(t)
+	ctx := context.Background()
+
+	export, err := setup.service.RequestExport(ctx, setup.player.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.DataExportPending, export.Status)
+	setup.service.Wait()
+
+	ready, err := setup.service.GetExport(ctx, setup.player.ID, export.ID)
+	require.NoError(t, err)
+	require.Equal(t, models.DataExportReady, ready.Status)
+	require.NotNil(t, ready.ExpiresAt)
+	assert.Equal(t, frozenTime.Add(DataExportRetention), *ready.ExpiresAt)
+	assert.Contains(t, ready.DownloadURL, ready.ObjectKey)
+	assert.Contains(t, ready.DownloadURL, "expires=86400")
+
+	archive, exists := setup.blobStorage.GetObject(ready.ObjectKey)
+	require.True(t, exists)
+	files := readArchive(t, archive)
+	assert.Equal(t, []byte("webp"), files["avatar.webp"])
+
+	var data models.PlayerDataArchive
+	require.NoError(t, json.Unmarshal(files["data.json"], &data))
+	assert.Equal(t, setup.player.ID, data.Profile.ID)
+	assert.Equal(t, "avatar.webp", data.Avatar)
+	require.Len(t, data.Sessions, 1)
+	assert.Equal(t, "Pixel", data.Sessions[0].Name)
+	require.Len(t, data.Games, 1)
+	assert.Equal(t, models.PlayerGameRecord{GameID: setup.gameID, Name: "Export Game", CompetitionName: "Premier League", SeasonYear: "2024"}, data.Games[0])
+	require.Len(t, data.Bets, 1)
+	assert.Equal(t, 2, data.Bets[0].PredictedHomeGoals)
+	assert.Equal(t, 1, data.Bets[0].PredictedAwayGoals)
+	require.Len(t, data.BetHistory, 1)
+	assert.Equal(t, "192.0.2.1", data.BetHistory[0].Device.IPAddress)
+	require.Len(t, data.Comments, 1)
+	assert.Equal(t, "Team1 all the way", data.Comments[0].Content)
+}
+
+func TestDataExportService_RequestExportWhilePending(t *testing.T) {
+	setup := setupDataExportService(t)
+	ctx := context.Background()
+
+	pending := &models.DataExport{PlayerID: setup.player.ID, Status: models.DataExportPending, CreatedAt: frozenTime}
+	require.NoError(t, setup.exportRepo.CreateExport(ctx, pending))
+
+	export, err := setup.service.RequestExport(ctx, setup.player.ID)
+	require.NoError(t, err)
+	assert.Equal(t, pending.ID, export.ID)
+}
+
+func TestDataExportService_RequestExportWhileStalePending(t *testing.T) {
+	setup := setupDataExportService(t)
+	ctx := context.Background()
+
+	// The instance building the export stopped before it was done
+	stale := &models.DataExport{PlayerID: setup.player.ID, Status: models.DataExportPending, CreatedAt: frozenTime}
+	require.NoError(t, setup.exportRepo.CreateExport(ctx, stale))
+	setup.now = frozenTime.Add(dataExportBuildTimeout)
+
+	shown, err := setup.service.GetExport(ctx, setup.player.ID, stale.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.DataExportFailed, shown.Status)
+
+	export, err := setup.service.RequestExport(ctx, setup.player.ID)
+	require.NoError(t, err)
+	assert.NotEqual(t, stale.ID, export.ID)
+	setup.service.Wait()
+
+	failed, err := setup.exportRepo.GetExport(ctx, stale.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.DataExportFailed, failed.Status)
+	require.NotNil(t, failed.CompletedAt)
+	assert.Equal(t, setup.now, *failed.CompletedAt)
+}
+
+func TestDataExportService_FailedBuild(t *testing.T) {
+	setup := setupDataExportService(t)
+	ctx := context.Background()
+	setup.blobStorage.UploadError = errors.New("storage unavailable")
+
+	export, err := setup.service.RequestExport(ctx, setup.player.ID)
+	require.NoError(t, err)
+	setup.service.Wait()
+
+	failed, err := setup.service.GetExport(ctx, setup.player.ID, export.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.DataExportFailed, failed.Status)
+	assert.Empty(t, failed.DownloadURL)
+
+	// A failed export can be requested again
+	retried, err := setup.service.RequestExport(ctx, setup.player.ID)
+	require.NoError(t, err)
+	assert.NotEqual(t, export.ID, retried.ID)
+	setup.service.Wait()
+}
+
+func TestDataExportService_GetExport(t *testing.T) {
+	setup := setupDataExportService(t)
+	ctx := context.Background()
+
+	export, err := setup.service.RequestExport(ctx, setup.player.ID)
+	require.NoError(t, err)
+	setup.service.Wait()
+
+	t.Run("Other Player", func(t *testing.T) {
+		_, err := setup.service.GetExport(ctx, "someone-else", export.ID)
+		assert.ErrorIs(t, err, ErrDataExportNotFound)
+	})
+
+	t.Run("Unknown Export", func(t *testing.T) {
+		_, err := setup.service.GetExport(ctx, setup.player.ID, "unknown")
+		assert.ErrorIs(t, err, ErrDataExportNotFound)
+	})
+
+	t.Run("URL Does Not Outlive Archive", func(t *testing.T) {
+		setup.now = frozenTime.Add(DataExportRetention - time.Hour)
+		defer func() { setup.now = frozenTime }()
+
+		ready, err := setup.service.GetExport(ctx, setup.player.ID, export.ID)
+		require.NoError(t, err)
+		assert.Contains(t, ready.DownloadURL, "expires=3600")
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		setup.now = frozenTime.Add(DataExportRetention)
+		defer func() { setup.now = frozenTime }()
+
+		expired, err := setup.service.GetExport(ctx, setup.player.ID, export.ID)
+		require.NoError(t, err)
+		assert.Empty(t, expired.DownloadURL)
+	})
+}
+
+func TestDataExportService_CleanupExpiredExports(t *testing.T) {
+	setup := setupDataExportService(t)
+	ctx := context.Background()
+
+	export, err := setup.service.RequestExport(ctx, setup.player.ID)
+	require.NoError(t, err)
+	setup.service.Wait()
+	ready, err := setup.exportRepo.GetExport(ctx, export.ID)
+	require.NoError(t, err)
+
+	require.NoError(t, setup.service.CleanupExpiredExports(ctx))
+	_, exists := setup.blobStorage.GetObject(ready.ObjectKey)
+	assert.True(t, exists)
+
+	setup.now = frozenTime.Add(DataExportRetention)
+	require.NoError(t, setup.service.CleanupExpiredExports(ctx))
+	_, exists = setup.blobStorage.GetObject(ready.ObjectKey)
+	assert.False(t, exists)
+	_, err = setup.exportRepo.GetExport(ctx, export.ID)
+	assert.ErrorIs(t, err, repositories.ErrDataExportNotFound)
+}
@@ -0,0 +1,20 @@
+package services
+
+import (
+	"context"
+	"ligain/backend/models"
+)
+
+type deviceInfoKey struct{}
+
+// WithDeviceInfo returns a context carrying the device a request comes from.
+// Sign-ins and refreshes made with this context record the device on the session
+func WithDeviceInfo(ctx context.Context, device models.DeviceInfo) context.Context {
+	return context.WithValue(ctx, deviceInfoKey{}, device)
+}
+
+// deviceInfoFromContext returns the device set with WithDeviceInfo, or an empty one
+func deviceInfoFromContext(ctx context.Context) models.DeviceInfo {
+	device, _ := ctx.Value(deviceInfoKey{}).(models.DeviceInfo)
+	return device
+}
@@ -0,0 +1,270 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"ligain/backend/mail"
+	"ligain/backend/models"
+	"ligain/backend/repositories"
+	"math/big"
+	netmail "net/mail"
+	"net/url"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// EmailSignInCodeTTL is how long an emailed code and its link can be used
+	EmailSignInCodeTTL = 15 * time.Minute
+	// MaxEmailSignInRequests is the number of codes an email can be sent within EmailSignInRequestWindow
+	MaxEmailSignInRequests = 3
+	// EmailSignInRequestWindow is the window over which the codes sent to an email are counted
+	EmailSignInRequestWindow = 15 * time.Minute
+	// MaxEmailSignInCodeAttempts is the number of wrong codes after which the latest code of an email stops working
+	MaxEmailSignInCodeAttempts = 5
+)
+
+// ErrInvalidEmail is returned when a sign-in is requested for a malformed email address
+var ErrInvalidEmail = errors.New("invalid email address")
+
+// EmailSignInService signs players in without a password, with a one-time code or link sent to their email
+type EmailSignInService interface {
+	// RequestSignIn emails a code and a link to sign in with. It doesn't tell whether the email belongs to a player
+	RequestSignIn(ctx context.Context, email string) error
+	// VerifySignIn checks the code or the link token, and signs the player of the email in.
+	// A player is created for an unknown email, which needs a display name: the same code can be sent again with it
+	VerifySignIn(ctx context.Context, req *models.EmailSignInVerifyRequest) (*models.AuthResponse, error)
+	// CleanupExpiredCodes removes the codes that can't be used anymore
+	CleanupExpiredCodes(ctx context.Context) error
+}
+
+// EmailSignInServiceImpl implements EmailSignInService
+type EmailSignInServiceImpl struct {
+	repo        repositories.EmailSignInRepository
+	sender      mail.Sender
+	authService *AuthService
+	// linkBaseURL is the deep link opening the app, the link token is added to it. No link is sent when it's empty
+	linkBaseURL string
+	timeFunc    func() time.Time
+}
+
+// NewEmailSignInService creates a new EmailSignInService instance
+func NewEmailSignInService(
+	repo repositories.EmailSignInRepository,
+	sender mail.Sender,
+	authService *AuthService,
+	linkBaseURL string,
+) *EmailSignInServiceImpl {
+	return NewEmailSignInServiceWithTimeFunc(repo, sender, authService, linkBaseURL, time.Now)
+}
+
+// NewEmailSignInServiceWithTimeFunc creates an EmailSignInService with a custom time function (for testing)
+func NewEmailSignInServiceWithTimeFunc(
+	repo repositories.EmailSignInRepository,
+	sender mail.Sender,
+	authService *AuthService,
+	linkBaseURL string,
+	timeFunc func() time.Time,
+) *EmailSignInServiceImpl {
+	return &EmailSignInServiceImpl{
+		repo:        repo,
+		sender:      sender,
+		authService: authService,
+		linkBaseURL: linkBaseURL,
+		timeFunc:    timeFunc,
+	}
+}
+
+// RequestSignIn implements EmailSignInService
+func (s *EmailSignInServiceImpl) RequestSignIn(ctx context.Context, email string) error {
+	email, err := normalizeEmail(email)
+	if err != nil {
+		return err
+	}
+
+	now := s.timeFunc()
+	recent, err := s.repo.GetSignInCodesSince(ctx, email, now.Add(-EmailSignInRequestWindow))
+	if err != nil {
+		return &models.GeneralAuthError{Reason: fmt.Sprintf("failed to get recent sign-in codes: %v", err)}
+	}
+	if len(recent) >= MaxEmailSignInRequests {
+		log.Warnf("Too many email sign-in requests for %s", email)
+		oldest := recent[len(recent)-MaxEmailSignInRequests]
+		return &models.TooManyAttemptsError{RetryAfter: oldest.CreatedAt.Add(EmailSignInRequestWindow).Sub(now)}
+	}
+
+	code, err := generateSignInCode()
+	if err != nil {
+		return &models.GeneralAuthError{Reason: fmt.Sprintf("failed to generate sign-in code: %v", err)}
+	}
+	linkToken, err := generateSignInLinkToken()
+	if err != nil {
+		return &models.GeneralAuthError{Reason: fmt.Sprintf("failed to generate sign-in link: %v", err)}
+	}
+
+	signInCode := &models.EmailSignInCode{
+		Email:         email,
+		CodeHash:      hashSignInSecret(code),
+		LinkTokenHash: hashSignInSecret(linkToken),
+		CreatedAt:     now,
+		ExpiresAt:     now.Add(EmailSignInCodeTTL),
+	}
+	if err := s.repo.CreateSignInCode(ctx, signInCode); err != nil {
+		return &models.GeneralAuthError{Reason: fmt.Sprintf("failed to save sign-in code: %v", err)}
+	}
+
+	if err := s.sender.Send(ctx, s.signInMessage(email, code, linkToken)); err != nil {
+		return &models.GeneralAuthError{Reason: fmt.Sprintf("failed to send sign-in email: %v", err)}
+	}
+	return nil
+}
+
+// signInMessage writes the email carrying the code, and the link when the app has one
+func (s *EmailSignInServiceImpl) signInMessage(email, code, linkToken string) mail.Message {
+	var body strings.Builder
+	body.WriteString("Your Ligain sign-in code is " + code + "\n\n")
+	if s.linkBaseURL != "" {
+		separator := "?"
+		if strings.Contains(s.linkBaseURL, "?") {
+			separator = "&"
+		}
+		link := s.linkBaseURL + separator + url.Values{"token": {linkToken}}.Encode()
+		body.WriteString("You can also open this link on your phone:\n" + link + "\n\n")
+	}
+	body.WriteString(fmt.Sprintf("It expires in %d minutes. If you didn't ask to sign in, you can ignore this email.\n", int(EmailSignInCodeTTL.Minutes())))
+
+	return mail.Message{
+		To:      email,
+		Subject: "Your Ligain sign-in code",
+		Body:    body.String(),
+	}
+}
+
+// VerifySignIn implements EmailSignInService. The code is only used up once the player is signed in,
+// so that a new player can send it again along with their display name
+func (s *EmailSignInServiceImpl) VerifySignIn(ctx context.Context, req *models.EmailSignInVerifyRequest) (*models.AuthResponse, error) {
+	now := s.timeFunc()
+
+	var signInCode *models.EmailSignInCode
+	var err error
+	if req.Token != "" {
+		signInCode, err = s.findCodeByLink(ctx, req.Token, now)
+	} else {
+		signInCode, err = s.findCodeByEmail(ctx, req.Email, req.Code, now)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	verifiedUser := map[string]interface{}{
+		"id":    signInCode.Email,
+		"email": signInCode.Email,
+	}
+	player, err := s.authService.GetOrCreatePlayer(ctx, verifiedUser, models.EmailSignInProvider, req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	used, err := s.repo.MarkSignInCodeUsed(ctx, signInCode.ID, now)
+	if err != nil {
+		return nil, &models.GeneralAuthError{Reason: fmt.Sprintf("failed to use sign-in code: %v", err)}
+	}
+	if !used {
+		return nil, &models.InvalidSignInCodeError{}
+	}
+
+	return s.authService.issueTokens(ctx, player, nil)
+}
+
+// findCodeByLink returns the usable code sent with the link token
+func (s *EmailSignInServiceImpl) findCodeByLink(ctx context.Context, linkToken string, now time.Time) (*models.EmailSignInCode, error) {
+	signInCode, err := s.repo.GetSignInCodeByLinkHash(ctx, hashSignInSecret(linkToken))
+	if err != nil {
+		return nil, &models.GeneralAuthError{Reason: fmt.Sprintf("failed to get sign-in code: %v", err)}
+	}
+	if signInCode == nil || !signInCode.IsUsable(now, MaxEmailSignInCodeAttempts) {
+		return nil, &models.InvalidSignInCodeError{}
+	}
+	return signInCode, nil
+}
+
+// findCodeByEmail checks the code against the latest one sent to the email. Each wrong code counts against it,
+// and with the limited number of codes an email is sent, guessing a six digit code is out of reach
+func (s *EmailSignInServiceImpl) findCodeByEmail(ctx context.Context, email, code string, now time.Time) (*models.EmailSignInCode, error) {
+	email, err := normalizeEmail(email)
+	if err != nil {
+		return nil, err
+	}
+
+	codes, err := s.repo.GetSignInCodesSince(ctx, email, now.Add(-EmailSignInCodeTTL))
+	if err != nil {
+		return nil, &models.GeneralAuthError{Reason: fmt.Sprintf("failed to get sign-in codes: %v", err)}
+	}
+	if len(codes) == 0 {
+		return nil, &models.InvalidSignInCodeError{}
+	}
+	latest := codes[len(codes)-1]
+	if !latest.IsUsable(now, MaxEmailSignInCodeAttempts) {
+		return nil, &models.InvalidSignInCodeError{}
+	}
+
+	if subtle.ConstantTimeCompare([]byte(hashSignInSecret(strings.TrimSpace(code))), []byte(latest.CodeHash)) != 1 {
+		if err := s.repo.IncrementAttempts(ctx, latest.ID); err != nil {
+			return nil, &models.GeneralAuthError{Reason: fmt.Sprintf("failed to record sign-in attempt: %v", err)}
+		}
+		return nil, &models.InvalidSignInCodeError{}
+	}
+	return latest, nil
+}
+
+// CleanupExpiredCodes implements EmailSignInService. Codes are kept for the request window at least,
+// since they count the recent requests of their email
+func (s *EmailSignInServiceImpl) CleanupExpiredCodes(ctx context.Context) error {
+	before := s.timeFunc().Add(-EmailSignInRequestWindow)
+	if err := s.repo.DeleteExpiredSignInCodes(ctx, before); err != nil {
+		return fmt.Errorf("error cleaning up email sign-in codes: %v", err)
+	}
+	return nil
+}
+
+// normalizeEmail lowercases a bare email address, and rejects anything else
+func normalizeEmail(email string) (string, error) {
+	email = strings.ToLower(strings.TrimSpace(email))
+	address, err := netmail.ParseAddress(email)
+	if err != nil || address.Address != email {
+		return "", ErrInvalidEmail
+	}
+	return email, nil
+}
+
+// generateSignInCode generates a six digit code, easy to type from another device
+func generateSignInCode() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1000000))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%06d", n.Int64()), nil
+}
+
+// generateSignInLinkToken generates the token of a magic link
+func generateSignInLinkToken() (string, error) {
+	tokenBytes := make([]byte, 32)
+	if _, err := rand.Read(tokenBytes); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(tokenBytes), nil
+}
+
+// hashSignInSecret hashes a code or a link token. They are short-lived and their attempts limited, so a plain hash is enough
+func hashSignInSecret(secret string) string {
+	hash := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(hash[:])
+}
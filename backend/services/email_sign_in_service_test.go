@@ -0,0 +1,241 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"ligain/backend/mail"
+	"ligain/backend/models"
+	"ligain/backend/repositories"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	signInCodePattern  = regexp.MustCompile(`sign-in code is (\d{6})`)
+	signInTokenPattern = regexp.MustCompile(`token=([A-Za-z0-9_-]+)`)
+)
+
+type emailSignInFixture struct {
+	service    *EmailSignInServiceImpl
+	playerRepo *MockPlayerRepository
+	sender     *mail.InMemorySender
+	now        *time.Time
+}
+
+func newEmailSignInFixture() *emailSignInFixture {
+	now := frozenTime
+	timeFunc := func() time.Time { return now }
+	playerRepo := NewMockPlayerRepository()
+	sender := mail.NewInMemorySender()
+	authService := NewAuthServiceWithTimeFunc(playerRepo, NewMockOAuthVerifier(), timeFunc)
+	service := NewEmailSignInServiceWithTimeFunc(
+		repositories.NewInMemoryEmailSignInRepository(),
+		sender,
+		authService,
+		"ligain://auth/email",
+		timeFunc,
+	)
+	return &emailSignInFixture{service: service, playerRepo: playerRepo, sender: sender, now: &now}
+}
+
+// lastCode returns the code and the link token of the latest email sent to the address
+func (f *emailSignInFixture) lastCode(t *testing.T, email string) (string, string) {
+	msg, ok := f.sender.LastMessageTo(email)
+	require.True(t, ok)
+	code := signInCodePattern.FindStringSubmatch(msg.Body)
+	require.Len(t, code, 2)
+	token := signInTokenPattern.FindStringSubmatch(msg.Body)
+	require.Len(t, token, 2)
+	return code[1], token[1]
+}
+
+func TestEmailSignInService_NewPlayer(t *testing.T) {
+	ctx := context.Background()
+	f := newEmailSignInFixture()
+
+	require.NoError(t, f.service.RequestSignIn(ctx, "  Friend@Example.com "))
+	code, _ := f.lastCode(t, "friend@example.com")
+
+	// A new player is asked for a display name, and can send the same code again with it
+	_, err := f.service.VerifySignIn(ctx, &models.EmailSignInVerifyRequest{Email: "friend@example.com", Code: code})
+	var needNameErr *models.NeedDisplayNameError
+	require.True(t, errors.As(err, &needNameErr))
+
+	resp, err := f.service.VerifySignIn(ctx, &models.EmailSignInVerifyRequest{Email: "friend@example.com", Code: code, Name: "Friend"})
+	require.NoError(t, err)
+	assert.NotEmpty(t, resp.Token)
+	assert.NotEmpty(t, resp.RefreshToken)
+	assert.Equal(t, "Friend", resp.Player.Name)
+	assert.Equal(t, models.EmailSignInProvider, *resp.Player.Provider)
+	assert.Equal(t, "friend@example.com", *resp.Player.Email)
+
+	// The code is used up
+	_, err = f.service.VerifySignIn(ctx, &models.EmailSignInVerifyRequest{Email: "friend@example.com", Code: code, Name: "Friend"})
+	var invalidCodeErr *models.InvalidSignInCodeError
+	assert.True(t, errors.As(err, &invalidCodeErr))
+
+	// Signing in again leads to the same player
+	*f.now = f.now.Add(time.Minute)
+	require.NoError(t, f.service.RequestSignIn(ctx, "friend@example.com"))
+	code, _ = f.lastCode(t, "friend@example.com")
+	again, err := f.service.VerifySignIn(ctx, &models.EmailSignInVerifyRequest{Email: "friend@example.com", Code: code})
+	require.NoError(t, err)
+	assert.Equal(t, resp.Player.ID, again.Player.ID)
+}
+
+func TestEmailSignInService_MagicLink(t *testing.T) {
+	ctx := context.Background()
+	f := newEmailSignInFixture()
+
+	require.NoError(t, f.service.RequestSignIn(ctx, "friend@example.com"))
+	_, token := f.lastCode(t, "friend@example.com")
+
+	resp, err := f.service.VerifySignIn(ctx, &models.EmailSignInVerifyRequest{Token: token, Name: "Friend"})
+	require.NoError(t, err)
+	assert.Equal(t, "friend@example.com", *resp.Player.Email)
+
+	_, err = f.service.VerifySignIn(ctx, &models.EmailSignInVerifyRequest{Token: token})
+	var invalidCodeErr *models.InvalidSignInCodeError
+	assert.True(t, errors.As(err, &invalidCodeErr))
+}
+
+func TestEmailSignInService_LinksExistingAccount(t *testing.T) {
+	ctx := context.Background()
+	f := newEmailSignInFixture()
+
+	googleProvider := "google"
+	googleID := "google_123"
+	email := "test@example.com"
+	existing := &models.PlayerData{Name: "Googler", Email: &email, Provider: &googleProvider, ProviderID: &googleID}
+	require.NoError(t, f.playerRepo.CreatePlayer(ctx, existing))
+
+	require.NoError(t, f.service.RequestSignIn(ctx, "Test@Example.com"))
+	code, _ := f.lastCode(t, "test@example.com")
+
+	resp, err := f.service.VerifySignIn(ctx, &models.EmailSignInVerifyRequest{Email: "test@example.com", Code: code})
+	require.NoError(t, err)
+	assert.Equal(t, existing.ID, resp.Player.ID)
+	assert.Equal(t, "Googler", resp.Player.Name)
+
+	// The player keeps signing in with Google too
+	stored, err := f.playerRepo.GetPlayerByID(ctx, existing.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "google", *stored.Provider)
+	assert.Equal(t, "google_123", *stored.ProviderID)
+}
+
+func TestEmailSignInService_WrongCodes(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("LatestCodeOnly", func(t *testing.T) {
+		f := newEmailSignInFixture()
+		require.NoError(t, f.service.RequestSignIn(ctx, "friend@example.com"))
+		first, _ := f.lastCode(t, "friend@example.com")
+		require.NoError(t, f.service.RequestSignIn(ctx, "friend@example.com"))
+		second, _ := f.lastCode(t, "friend@example.com")
+		if first == second {
+			t.Skip("both codes are the same")
+		}
+
+		_, err := f.service.VerifySignIn(ctx, &models.EmailSignInVerifyRequest{Email: "friend@example.com", Code: first, Name: "Friend"})
+		var invalidCodeErr *models.InvalidSignInCodeError
+		assert.True(t, errors.As(err, &invalidCodeErr))
+	})
+
+	t.Run("TooManyAttempts", func(t *testing.T) {
+		f := newEmailSignInFixture()
+		require.NoError(t, f.service.RequestSignIn(ctx, "friend@example.com"))
+		code, _ := f.lastCode(t, "friend@example.com")
+		wrong := "000000"
+		if code == wrong {
+			wrong = "111111"
+		}
+
+		for i := 0; i < MaxEmailSignInCodeAttempts; i++ {
+			_, err := f.service.VerifySignIn(ctx, &models.EmailSignInVerifyRequest{Email: "friend@example.com", Code: wrong})
+			var invalidCodeErr *models.InvalidSignInCodeError
+			require.True(t, errors.As(err, &invalidCodeErr))
+		}
+
+		// The right code doesn't work anymore
+		_, err := f.service.VerifySignIn(ctx, &models.EmailSignInVerifyRequest{Email: "friend@example.com", Code: code, Name: "Friend"})
+		var invalidCodeErr *models.InvalidSignInCodeError
+		assert.True(t, errors.As(err, &invalidCodeErr))
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		f := newEmailSignInFixture()
+		require.NoError(t, f.service.RequestSignIn(ctx, "friend@example.com"))
+		code, token := f.lastCode(t, "friend@example.com")
+
+		*f.now = f.now.Add(EmailSignInCodeTTL)
+		_, err := f.service.VerifySignIn(ctx, &models.EmailSignInVerifyRequest{Email: "friend@example.com", Code: code, Name: "Friend"})
+		var invalidCodeErr *models.InvalidSignInCodeError
+		assert.True(t, errors.As(err, &invalidCodeErr))
+		_, err = f.service.VerifySignIn(ctx, &models.EmailSignInVerifyRequest{Token: token, Name: "Friend"})
+		assert.True(t, errors.As(err, &invalidCodeErr))
+	})
+}
+
+func TestEmailSignInService_RequestThrottling(t *testing.T) {
+	ctx := context.Background()
+	f := newEmailSignInFixture()
+
+	for i := 0; i < MaxEmailSignInRequests; i++ {
+		*f.now = frozenTime.Add(time.Duration(i) * time.Minute)
+		require.NoError(t, f.service.RequestSignIn(ctx, "friend@example.com"))
+	}
+
+	err := f.service.RequestSignIn(ctx, "friend@example.com")
+	var tooManyErr *models.TooManyAttemptsError
+	require.True(t, errors.As(err, &tooManyErr))
+	// The oldest request leaves the window first
+	assert.Equal(t, EmailSignInRequestWindow-2*time.Minute, tooManyErr.RetryAfter)
+	assert.Len(t, f.sender.Messages(), MaxEmailSignInRequests)
+
+	// Other emails aren't affected
+	require.NoError(t, f.service.RequestSignIn(ctx, "other@example.com"))
+
+	*f.now = frozenTime.Add(EmailSignInRequestWindow)
+	require.NoError(t, f.service.RequestSignIn(ctx, "friend@example.com"))
+}
+
+func TestEmailSignInService_Validation(t *testing.T) {
+	ctx := context.Background()
+	f := newEmailSignInFixture()
+
+	for _, email := range []string{"", "not-an-email", "Friend <friend@example.com>"} {
+		assert.ErrorIs(t, f.service.RequestSignIn(ctx, email), ErrInvalidEmail, email)
+	}
+	assert.Empty(t, f.sender.Messages())
+
+	f.sender.Err = errors.New("smtp down")
+	var generalErr *models.GeneralAuthError
+	assert.True(t, errors.As(f.service.RequestSignIn(ctx, "friend@example.com"), &generalErr))
+}
+
+func TestEmailSignInService_CleanupExpiredCodes(t *testing.T) {
+	ctx := context.Background()
+	repo := repositories.NewInMemoryEmailSignInRepository()
+	now := frozenTime
+	service := NewEmailSignInServiceWithTimeFunc(repo, mail.NewInMemorySender(), nil, "", func() time.Time { return now })
+
+	require.NoError(t, service.RequestSignIn(ctx, "friend@example.com"))
+
+	// The code still counts against the requests of the email after it expired
+	now = frozenTime.Add(EmailSignInCodeTTL)
+	require.NoError(t, service.CleanupExpiredCodes(ctx))
+	codes, err := repo.GetSignInCodesSince(ctx, "friend@example.com", frozenTime.Add(-time.Second))
+	require.NoError(t, err)
+	assert.Len(t, codes, 1)
+
+	now = frozenTime.Add(EmailSignInCodeTTL + EmailSignInRequestWindow)
+	require.NoError(t, service.CleanupExpiredCodes(ctx))
+	codes, err = repo.GetSignInCodesSince(ctx, "friend@example.com", frozenTime.Add(-time.Second))
+	require.NoError(t, err)
+	assert.Empty(t, codes)
+}
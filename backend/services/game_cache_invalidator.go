@@ -0,0 +1,51 @@
+package services
+
+import (
+	"ligain/backend/repositories"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// GameCacheInvalidator keeps the games an instance serves fresh when other instances or the scripts change them:
+// it drops them from the game cache, and registers the games created elsewhere
+type GameCacheInvalidator struct {
+	gameRepo repositories.GameRepository
+	registry *GameServiceRegistry
+}
+
+// NewGameCacheInvalidator creates a new GameCacheInvalidator
+func NewGameCacheInvalidator(gameRepo repositories.GameRepository, registry *GameServiceRegistry) *GameCacheInvalidator {
+	return &GameCacheInvalidator{
+		gameRepo: gameRepo,
+		registry: registry,
+	}
+}
+
+// GameChanged implements repositories.GameChangeHandler
+func (i *GameCacheInvalidator) GameChanged(gameID string) {
+	if evicter, ok := i.gameRepo.(repositories.GameCacheEvicter); ok {
+		evicter.EvictGame(gameID)
+	}
+	if err := i.registry.Refresh(gameID); err != nil {
+		log.Warnf("Failed to refresh game %s after a change: %v", gameID, err)
+	}
+}
+
+// AllGamesChanged implements repositories.GameChangeHandler
+func (i *GameCacheInvalidator) AllGamesChanged() {
+	if evicter, ok := i.gameRepo.(repositories.GameCacheEvicter); ok {
+		evicter.ClearCache()
+	}
+
+	// Register the games created meanwhile
+	games, err := i.gameRepo.GetAllGames()
+	if err != nil {
+		log.Warnf("Failed to load the games after missing changes: %v", err)
+		return
+	}
+	for gameID := range games {
+		if err := i.registry.Refresh(gameID); err != nil {
+			log.Warnf("Failed to refresh game %s after missing changes: %v", gameID, err)
+		}
+	}
+}
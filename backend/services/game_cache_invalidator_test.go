@@ -0,0 +1,41 @@
+package services
+
+import (
+	"ligain/backend/models"
+	"ligain/backend/repositories"
+	"ligain/backend/rules"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGameCacheInvalidator(t *testing.T) {
+	gameRepo := &evictingGameRepository{GameRepository: repositories.NewInMemoryGameRepository()}
+	registry, err := NewGameServiceRegistry(gameRepo, repositories.NewInMemoryBetRepository(), nil, nil)
+	require.NoError(t, err)
+	invalidator := NewGameCacheInvalidator(gameRepo, registry)
+
+	// Games created by another instance, as seen in the database
+	game := rules.NewFreshGame("2025/2026", "Ligue 1", "Elsewhere", []models.Player{}, []models.Match{}, &rules.ScorerOriginal{})
+	require.NoError(t, gameRepo.SaveWithId("game-1", game))
+	require.NoError(t, gameRepo.SaveWithId("game-2", game))
+
+	invalidator.GameChanged("game-1")
+	assert.Equal(t, []string{"game-1"}, gameRepo.evicted)
+	_, exists := registry.Get("game-1")
+	assert.True(t, exists)
+	_, exists = registry.Get("game-2")
+	assert.False(t, exists)
+
+	// After missing changes, the whole cache is dropped and the running games are registered
+	invalidator.AllGamesChanged()
+	assert.Equal(t, 1, gameRepo.cleared)
+	_, exists = registry.Get("game-2")
+	assert.True(t, exists)
+
+	// A change of an unknown game is only logged
+	invalidator.GameChanged("unknown")
+	_, exists = registry.Get("unknown")
+	assert.False(t, exists)
+}
@@ -41,6 +41,10 @@ type CreateGameRequest struct {
 	SeasonYear      string `json:"seasonYear" binding:"required"`
 	CompetitionName string `json:"competitionName" binding:"required"`
 	Name            string `json:"name" binding:"required"`
+	// BetCutoff is how long before kickoff the bets close, at kickoff when it's not set
+	BetCutoff *models.BetCutoff `json:"betCutoff,omitempty"`
+	// Tiebreakers are applied in order to the players with the same points, the default ones when it's not set
+	Tiebreakers []string `json:"tiebreakers,omitempty"`
 }
 
 // CreateGameResponse represents the response when creating a new game
@@ -61,6 +65,7 @@ type PlayerGameInfo struct {
 	ID            string         `json:"id"`
 	Name          string         `json:"name"`
 	TotalScore    int            `json:"totalScore"`
+	Rank          int            `json:"rank"`
 	ScoresByMatch map[string]int `json:"scoresByMatch"`
 	AvatarURL     *string        `json:"avatarUrl,omitempty"`
 }
@@ -73,6 +78,8 @@ type PlayerGame struct {
 	Status          string           `json:"status"`
 	Players         []PlayerGameInfo `json:"players"`
 	Code            string           `json:"code"`
+	BetCutoff       models.BetCutoff `json:"betCutoff"`
+	Tiebreakers     []string         `json:"tiebreakers"`
 }
 
 var (
@@ -80,6 +87,8 @@ var (
 	ErrInvalidSeasonYear  = errors.New("only '2025/2026' is supported as season year")
 	ErrPlayerNotInGame    = errors.New("player is not in the game")
 	ErrPlayerGameLimit    = errors.New("player has reached the maximum limit of 5 games")
+	ErrInvalidBetCutoff   = errors.New("invalid bet cutoff")
+	ErrInvalidTiebreakers = errors.New("invalid tiebreakers")
 )
 
 // NewGameCreationServiceWithServices creates a GameCreationService with explicit service dependencies
@@ -139,6 +148,19 @@ func (s *GameCreationService) CreateGame(req *CreateGameRequest, player models.P
 	if req.Name == "" {
 		return nil, fmt.Errorf("game name is required")
 	}
+	if req.BetCutoff != nil {
+		if err := req.BetCutoff.Validate(); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidBetCutoff, err)
+		}
+	}
+	var tiebreakers []rules.Tiebreaker
+	if req.Tiebreakers != nil {
+		parsed, err := rules.ParseTiebreakers(req.Tiebreakers)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidTiebreakers, err)
+		}
+		tiebreakers = parsed
+	}
 
 	// Check if player has reached the game limit (5 games)
 	playerGames, err := s.gamePlayerRepo.GetPlayerGames(context.Background(), player.GetID())
@@ -164,6 +186,12 @@ func (s *GameCreationService) CreateGame(req *CreateGameRequest, player models.P
 		matches,
 		&rules.ScorerOriginal{},
 	)
+	if req.BetCutoff != nil {
+		game.SetBetCutoff(*req.BetCutoff)
+	}
+	if tiebreakers != nil {
+		game.SetTiebreakers(tiebreakers)
+	}
 
 	// Save the game to get its ID
 	gameID, err := s.gameRepo.CreateGame(game)
@@ -184,14 +212,13 @@ func (s *GameCreationService) CreateGame(req *CreateGameRequest, player models.P
 	}
 
 	// Generate a unique code for the game
-	code, err := s.generateUniqueCode()
+	code, err := generateUniqueGameCode(s.gameCodeRepo)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate unique code: %v", err)
 	}
 
 	// Create game code with 6 months expiration
-	expiresAt := s.timeFunc().AddDate(0, 6, 0)
-	gameCode := models.NewGameCode(gameID, code, expiresAt)
+	gameCode := models.NewGameCode(gameID, code, gameCodeExpiresAt(s.timeFunc()))
 
 	err = s.gameCodeRepo.CreateGameCode(gameCode)
 	if err != nil {
@@ -224,17 +251,17 @@ func (s *GameCreationService) CleanupExpiredCodes() error {
 	return s.gameCodeRepo.DeleteExpiredCodes()
 }
 
-// generateUniqueCode generates a unique 4-character alphanumeric code
-func (s *GameCreationService) generateUniqueCode() (string, error) {
+// generateUniqueGameCode generates a 4-character alphanumeric code that no game uses yet
+func generateUniqueGameCode(gameCodeRepo repositories.GameCodeRepository) (string, error) {
 	const maxAttempts = 10
 	const codeLength = 4
 	const charset = "ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
 
 	for attempt := 0; attempt < maxAttempts; attempt++ {
-		code := s.generateRandomCode(codeLength, charset)
+		code := generateRandomCode(codeLength, charset)
 
 		// Check if code already exists
-		exists, err := s.gameCodeRepo.CodeExists(code)
+		exists, err := gameCodeRepo.CodeExists(code)
 		if err != nil {
 			return "", fmt.Errorf("error checking code existence: %v", err)
 		}
@@ -248,7 +275,7 @@ func (s *GameCreationService) generateUniqueCode() (string, error) {
 }
 
 // generateRandomCode generates a random code of specified length using the given charset
-func (s *GameCreationService) generateRandomCode(length int, charset string) string {
+func generateRandomCode(length int, charset string) string {
 	code := make([]byte, length)
 	charsetLen := big.NewInt(int64(len(charset)))
 
@@ -259,3 +286,8 @@ func (s *GameCreationService) generateRandomCode(length int, charset string) str
 
 	return string(code)
 }
+
+// gameCodeExpiresAt returns when a join code created now expires
+func gameCodeExpiresAt(now time.Time) time.Time {
+	return now.AddDate(0, 6, 0)
+}
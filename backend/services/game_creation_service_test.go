@@ -54,6 +54,11 @@ func (m *MockGameRepository) GetAllGames() (map[string]models.Game, error) {
 	return args.Get(0).(map[string]models.Game), args.Error(1)
 }
 
+func (m *MockGameRepository) SaveGameStatus(ctx context.Context, gameId string, status models.GameStatus) error {
+	args := m.Called(gameId, status)
+	return args.Error(0)
+}
+
 // MockGameCodeRepository is a mock implementation of GameCodeRepository
 type MockGameCodeRepository struct {
 	mock.Mock
@@ -183,6 +188,7 @@ func (m *MockWatcher) Unsubscribe(gameID string) error {
 	args := m.Called(gameID)
 	return args.Error(0)
 }
+func (m *MockWatcher) Unwatch(matchID string) error    { return nil }
 func (m *MockWatcher) Start(ctx context.Context) error { return nil }
 func (m *MockWatcher) Stop() error                     { return nil }
 
@@ -492,6 +498,120 @@ func TestGameCreationService_CreateGame_InvalidSeasonYear(t *testing.T) {
 	assert.Nil(t, response)
 }
 
+func TestGameCreationService_CreateGame_WithBetCutoff(t *testing.T) {
+	mockGameRepo := new(MockGameRepository)
+	mockGameCodeRepo := new(MockGameCodeRepository)
+	mockGamePlayerRepo := new(MockGamePlayerRepository)
+	mockMatchRepo := new(MockMatchRepository)
+	mockBetRepo := new(MockBetRepository)
+	mockWatcher := new(MockWatcher)
+
+	service := setupCreationTestService(t, mockGameRepo, mockGameCodeRepo, mockGamePlayerRepo, mockBetRepo, mockMatchRepo, mockWatcher)
+
+	cutoff := models.BetCutoff{Minutes: 60, Matchday: true}
+	request := &CreateGameRequest{
+		SeasonYear:      "2025/2026",
+		CompetitionName: "Ligue 1",
+		Name:            "Test Game",
+		BetCutoff:       &cutoff,
+	}
+	player := &models.PlayerData{ID: "player1", Name: "Test Player"}
+
+	mockGamePlayerRepo.On("GetPlayerGames", mock.Anything, "player1").Return([]string{}, nil)
+	mockGameRepo.On("CreateGame", mock.MatchedBy(func(game models.Game) bool {
+		return game.GetBetCutoff() == cutoff
+	})).Return("test-game-id", nil)
+	mockGamePlayerRepo.On("AddPlayerToGame", mock.Anything, "test-game-id", "player1").Return(nil)
+	mockGameCodeRepo.On("CodeExists", mock.AnythingOfType("string")).Return(false, nil)
+	mockGameCodeRepo.On("CreateGameCode", mock.AnythingOfType("*models.GameCode")).Return(nil)
+	mockMatchRepo.On("GetMatchesByCompetitionAndSeason", "Ligue 1", "2025/2026").Return([]models.Match{}, nil)
+	mockWatcher.On("Subscribe", mock.AnythingOfType("*services.GameServiceImpl")).Return(nil)
+
+	response, err := service.CreateGame(request, player)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, response)
+	mockGameRepo.AssertExpectations(t)
+}
+
+func TestGameCreationService_CreateGame_InvalidBetCutoff(t *testing.T) {
+	mockGameRepo := new(MockGameRepository)
+	mockGameCodeRepo := new(MockGameCodeRepository)
+	mockGamePlayerRepo := new(MockGamePlayerRepository)
+	mockMatchRepo := new(MockMatchRepository)
+	mockBetRepo := new(MockBetRepository)
+
+	service := setupCreationTestService(t, mockGameRepo, mockGameCodeRepo, mockGamePlayerRepo, mockBetRepo, mockMatchRepo, nil)
+
+	request := &CreateGameRequest{
+		SeasonYear:      "2025/2026",
+		CompetitionName: "Ligue 1",
+		Name:            "Test Game",
+		BetCutoff:       &models.BetCutoff{Minutes: -5},
+	}
+	player := &models.PlayerData{ID: "player1", Name: "Test Player"}
+	response, err := service.CreateGame(request, player)
+	assert.ErrorIs(t, err, ErrInvalidBetCutoff)
+	assert.Nil(t, response)
+}
+
+func TestGameCreationService_CreateGame_WithTiebreakers(t *testing.T) {
+	mockGameRepo := new(MockGameRepository)
+	mockGameCodeRepo := new(MockGameCodeRepository)
+	mockGamePlayerRepo := new(MockGamePlayerRepository)
+	mockMatchRepo := new(MockMatchRepository)
+	mockBetRepo := new(MockBetRepository)
+	mockWatcher := new(MockWatcher)
+
+	service := setupCreationTestService(t, mockGameRepo, mockGameCodeRepo, mockGamePlayerRepo, mockBetRepo, mockMatchRepo, mockWatcher)
+
+	tiebreakers := []string{"head_to_head", "exact_scores"}
+	request := &CreateGameRequest{
+		SeasonYear:      "2025/2026",
+		CompetitionName: "Ligue 1",
+		Name:            "Test Game",
+		Tiebreakers:     tiebreakers,
+	}
+	player := &models.PlayerData{ID: "player1", Name: "Test Player"}
+
+	mockGamePlayerRepo.On("GetPlayerGames", mock.Anything, "player1").Return([]string{}, nil)
+	mockGameRepo.On("CreateGame", mock.MatchedBy(func(game models.Game) bool {
+		return assert.ObjectsAreEqual(tiebreakers, game.GetTiebreakers())
+	})).Return("test-game-id", nil)
+	mockGamePlayerRepo.On("AddPlayerToGame", mock.Anything, "test-game-id", "player1").Return(nil)
+	mockGameCodeRepo.On("CodeExists", mock.AnythingOfType("string")).Return(false, nil)
+	mockGameCodeRepo.On("CreateGameCode", mock.AnythingOfType("*models.GameCode")).Return(nil)
+	mockMatchRepo.On("GetMatchesByCompetitionAndSeason", "Ligue 1", "2025/2026").Return([]models.Match{}, nil)
+	mockWatcher.On("Subscribe", mock.AnythingOfType("*services.GameServiceImpl")).Return(nil)
+
+	response, err := service.CreateGame(request, player)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, response)
+	mockGameRepo.AssertExpectations(t)
+}
+
+func TestGameCreationService_CreateGame_InvalidTiebreakers(t *testing.T) {
+	mockGameRepo := new(MockGameRepository)
+	mockGameCodeRepo := new(MockGameCodeRepository)
+	mockGamePlayerRepo := new(MockGamePlayerRepository)
+	mockMatchRepo := new(MockMatchRepository)
+	mockBetRepo := new(MockBetRepository)
+
+	service := setupCreationTestService(t, mockGameRepo, mockGameCodeRepo, mockGamePlayerRepo, mockBetRepo, mockMatchRepo, nil)
+
+	request := &CreateGameRequest{
+		SeasonYear:      "2025/2026",
+		CompetitionName: "Ligue 1",
+		Name:            "Test Game",
+		Tiebreakers:     []string{"coin_flip"},
+	}
+	player := &models.PlayerData{ID: "player1", Name: "Test Player"}
+	response, err := service.CreateGame(request, player)
+	assert.ErrorIs(t, err, ErrInvalidTiebreakers)
+	assert.Nil(t, response)
+}
+
 func TestGameCreationService_CreateGame_MatchLoadingFails(t *testing.T) {
 	// Setup
 	mockGameRepo := new(MockGameRepository)
@@ -605,6 +725,18 @@ func (m *SimpleMockGame) CheckPlayerBetValidity(player models.Player, bet *model
 	return nil
 }
 
+func (m *SimpleMockGame) GetBetCutoff() models.BetCutoff {
+	return models.BetCutoff{}
+}
+
+func (m *SimpleMockGame) GetTiebreakers() []string {
+	return nil
+}
+
+func (m *SimpleMockGame) BettingClosesAt(match models.Match) time.Time {
+	return match.GetDate()
+}
+
 func (m *SimpleMockGame) AddPlayerBet(player models.Player, bet *models.Bet) error {
 	return nil
 }
@@ -644,7 +776,19 @@ func (m *SimpleMockGame) GetWinner() []models.Player {
 	return []models.Player{}
 }
 
-func (m *SimpleMockGame) GetIncomingMatchesForTesting() map[string]*models.MatchResult {
+func (m *SimpleMockGame) GetStandings() []models.Standing {
+	return []models.Standing{}
+}
+
+func (m *SimpleMockGame) RankPlayers(points map[string]int) []models.Standing {
+	return []models.Standing{}
+}
+
+func (m *SimpleMockGame) GetIncomingMatchesWithAllBets() map[string]*models.MatchResult {
+	return make(map[string]*models.MatchResult)
+}
+
+func (m *SimpleMockGame) GetProvisionalResults() map[string]*models.MatchResult {
 	return make(map[string]*models.MatchResult)
 }
 
@@ -653,6 +797,10 @@ func (m *SimpleMockGame) GetMatchById(matchId string) (models.Match, error) {
 }
 
 func (m *SimpleMockGame) Finish() {}
+func (m *SimpleMockGame) Reopen() {}
+func (m *SimpleMockGame) RescoreMatch(match models.Match) (map[string]int, error) {
+	return nil, nil
+}
 
 func TestGameCreationService_JoinGame_InvalidCode(t *testing.T) {
 	// Setup
@@ -911,10 +1059,13 @@ func (m *MockBetRepository) GetScoresByMatchAndPlayer(gameId string) (map[string
 	args := m.Called(gameId)
 	return args.Get(0).(map[string]map[string]int), args.Error(1)
 }
+func (m *MockBetRepository) GetPlayerBets(playerId string) ([]*models.PlayerBetRecord, error) {
+	return nil, nil
+}
 func (m *MockBetRepository) GetBets(gameId string, player models.Player) ([]*models.Bet, error) {
 	return nil, nil
 }
-func (m *MockBetRepository) SaveBet(gameId string, bet *models.Bet, player models.Player) (string, *models.Bet, error) {
+func (m *MockBetRepository) SaveBet(ctx context.Context, gameId string, bet *models.Bet, player models.Player) (string, *models.Bet, error) {
 	return "", nil, nil
 }
 func (m *MockBetRepository) GetBetsForMatch(match models.Match, gameId string) ([]*models.Bet, []models.Player, error) {
@@ -927,6 +1078,15 @@ func (m *MockBetRepository) SaveScore(gameId string, match models.Match, player
 	return nil
 }
 func (m *MockBetRepository) GetScore(gameId string, betId string) (int, error) { return 0, nil }
+func (m *MockBetRepository) ClaimScoring(ctx context.Context, scoring *models.MatchScoring) (bool, error) {
+	return true, nil
+}
+func (m *MockBetRepository) GetScoringVersions(ctx context.Context, gameId string) (map[string]int, error) {
+	return map[string]int{}, nil
+}
+func (m *MockBetRepository) SaveScores(ctx context.Context, gameId string, match models.Match, players []models.Player, scores map[string]int) error {
+	return nil
+}
 func (m *MockBetRepository) GetScores(gameId string) (map[string]map[string]int, error) {
 	return nil, nil
 }
@@ -1163,6 +1323,11 @@ func (m *SimpleMockGameFinished) GetName() string                  { return "Tes
 func (m *SimpleMockGameFinished) CheckPlayerBetValidity(player models.Player, bet *models.Bet, datetime time.Time) error {
 	return nil
 }
+func (m *SimpleMockGameFinished) GetBetCutoff() models.BetCutoff { return models.BetCutoff{} }
+func (m *SimpleMockGameFinished) GetTiebreakers() []string       { return nil }
+func (m *SimpleMockGameFinished) BettingClosesAt(match models.Match) time.Time {
+	return match.GetDate()
+}
 func (m *SimpleMockGameFinished) AddPlayerBet(player models.Player, bet *models.Bet) error {
 	return nil
 }
@@ -1177,7 +1342,15 @@ func (m *SimpleMockGameFinished) GetPlayersPoints() map[string]int
 func (m *SimpleMockGameFinished) GetPlayers() []models.Player                                { return []models.Player{} }
 func (m *SimpleMockGameFinished) IsFinished() bool                                           { return true }
 func (m *SimpleMockGameFinished) GetWinner() []models.Player                                 { return []models.Player{} }
-func (m *SimpleMockGameFinished) GetIncomingMatchesForTesting() map[string]*models.MatchResult {
+func (m *SimpleMockGameFinished) GetStandings() []models.Standing                            { return []models.Standing{} }
+func (m *SimpleMockGameFinished) RankPlayers(points map[string]int) []models.Standing {
+	return []models.Standing{}
+}
+func (m *SimpleMockGameFinished) GetIncomingMatchesWithAllBets() map[string]*models.MatchResult {
+	return make(map[string]*models.MatchResult)
+}
+
+func (m *SimpleMockGameFinished) GetProvisionalResults() map[string]*models.MatchResult {
 	return make(map[string]*models.MatchResult)
 }
 func (m *SimpleMockGameFinished) GetMatchById(matchId string) (models.Match, error) {
@@ -1185,6 +1358,10 @@ func (m *SimpleMockGameFinished) GetMatchById(matchId string) (models.Match, err
 }
 
 func (m *SimpleMockGameFinished) Finish() {}
+func (m *SimpleMockGameFinished) Reopen() {}
+func (m *SimpleMockGameFinished) RescoreMatch(match models.Match) (map[string]int, error) {
+	return nil, nil
+}
 
 // SimpleMockGameService is a simple mock that implements GameService without making repository calls
 type SimpleMockGameService struct {
@@ -1199,7 +1376,15 @@ func (m *SimpleMockGameService) GetMatchResults() map[string]*models.MatchResult
 	return make(map[string]*models.MatchResult)
 }
 
-func (m *SimpleMockGameService) UpdatePlayerBet(player models.Player, bet *models.Bet, now time.Time) error {
+func (m *SimpleMockGameService) GetProvisionalLeaderboard() (*models.ProvisionalLeaderboard, error) {
+	return models.NewProvisionalLeaderboard(nil, nil), nil
+}
+
+func (m *SimpleMockGameService) GetStandings() ([]models.Standing, error) {
+	return []models.Standing{}, nil
+}
+
+func (m *SimpleMockGameService) UpdatePlayerBet(ctx context.Context, player models.Player, bet *models.Bet, now time.Time) error {
 	return nil
 }
 
@@ -1527,6 +1712,7 @@ func TestGameCreationService_GetPlayerGames_FinishedGameStatus(t *testing.T) {
 
 	// Set up mock expectation for SaveWithId since the game will finish
 	mockGameRepo.On("SaveWithId", gameID, mock.AnythingOfType("*rules.GameImpl")).Return(nil)
+	mockGameRepo.On("SaveGameStatus", gameID, mock.AnythingOfType("models.GameStatus")).Return(nil)
 
 	err := gameService.HandleMatchUpdates(updates)
 	require.NoError(t, err)
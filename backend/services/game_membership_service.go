@@ -31,6 +31,8 @@ type GameMembershipService struct {
 	gameCodeRepo   repositories.GameCodeRepository
 	registry       GameServiceRegistryInterface
 	watcher        MatchWatcherService
+	// activityObservers are notified when a player joins or leaves a game
+	activityObservers []ActivityObserver
 }
 
 // NewGameMembershipService creates a new GameMembershipService instance
@@ -52,6 +54,11 @@ func NewGameMembershipService(
 	}
 }
 
+// AddActivityObserver registers an observer notified when a player joins or leaves a game
+func (s *GameMembershipService) AddActivityObserver(observer ActivityObserver) {
+	s.activityObservers = append(s.activityObservers, observer)
+}
+
 // AddPlayerToGame adds a player to a game if they're not already in it
 func (s *GameMembershipService) AddPlayerToGame(gameID string, player models.Player) error {
 	ctx := context.Background()
@@ -75,6 +82,8 @@ func (s *GameMembershipService) AddPlayerToGame(gameID string, player models.Pla
 	// Update cached game service if it exists
 	s.addPlayerToGameService(gameID, player)
 
+	s.notifyActivity(models.NewPlayerActivity(gameID, models.ActivityPlayerJoined, player))
+
 	return nil
 }
 
@@ -110,6 +119,8 @@ func (s *GameMembershipService) RemovePlayerFromGame(gameID string, player model
 		return err
 	}
 
+	s.notifyActivity(models.NewPlayerActivity(gameID, models.ActivityPlayerLeft, player))
+
 	// Post-commit: check if game should be deleted
 	players, err := s.gamePlayerRepo.GetPlayersInGame(ctx, gameID)
 	if err != nil {
@@ -142,6 +153,15 @@ func (s *GameMembershipService) LeaveGame(gameID string, player models.Player) e
 	return s.RemovePlayerFromGame(gameID, player)
 }
 
+// notifyActivity sends an activity to the observers. The membership change is already saved, so a failing observer is only logged
+func (s *GameMembershipService) notifyActivity(activity *models.Activity) {
+	for _, observer := range s.activityObservers {
+		if err := observer.OnActivity(activity); err != nil {
+			log.WithError(err).Errorf("error notifying activity observer of %s in game %s", activity.Type, activity.GameID)
+		}
+	}
+}
+
 // deleteGame marks a game as finished, persists it, unsubscribes from the watcher, and deletes the join code
 func (s *GameMembershipService) deleteGame(gameID string) error {
 	game, err := s.gameRepo.GetGame(gameID)
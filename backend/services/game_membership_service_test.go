@@ -516,7 +516,13 @@ func (m *MockGameServiceForRemovePlayer) GetIncomingMatches(player models.Player
 func (m *MockGameServiceForRemovePlayer) GetMatchResults() map[string]*models.MatchResult {
 	return nil
 }
-func (m *MockGameServiceForRemovePlayer) UpdatePlayerBet(player models.Player, bet *models.Bet, now time.Time) error {
+func (m *MockGameServiceForRemovePlayer) GetProvisionalLeaderboard() (*models.ProvisionalLeaderboard, error) {
+	return nil, nil
+}
+func (m *MockGameServiceForRemovePlayer) GetStandings() ([]models.Standing, error) {
+	return nil, nil
+}
+func (m *MockGameServiceForRemovePlayer) UpdatePlayerBet(ctx context.Context, player models.Player, bet *models.Bet, now time.Time) error {
 	return nil
 }
 func (m *MockGameServiceForRemovePlayer) GetPlayerBets(player models.Player) ([]*models.Bet, error) {
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"ligain/backend/models"
 	"ligain/backend/repositories"
+	"sort"
 
 	log "github.com/sirupsen/logrus"
 )
@@ -94,6 +95,7 @@ func (s *GameQueryService) GetPlayerGames(player models.Player) ([]PlayerGame, e
 				AvatarURL:     avatarURL,
 			})
 		}
+		rankPlayerInfos(game, playerInfos)
 
 		// Get the game code
 		gameCode, err := s.gameCodeRepo.GetGameCodeByGameID(gameID)
@@ -111,6 +113,8 @@ func (s *GameQueryService) GetPlayerGames(player models.Player) ([]PlayerGame, e
 			Status:          gameStatus,
 			Players:         playerInfos,
 			Code:            code,
+			BetCutoff:       game.GetBetCutoff(),
+			Tiebreakers:     game.GetTiebreakers(),
 		}
 
 		playerGames = append(playerGames, playerGame)
@@ -123,3 +127,29 @@ func (s *GameQueryService) GetPlayerGames(player models.Player) ([]PlayerGame, e
 func (s *GameQueryService) GetGame(gameID string) (models.Game, error) {
 	return s.gameRepo.GetGame(gameID)
 }
+
+// rankPlayerInfos sets the rank of each player using the game's tiebreakers, and sorts them by rank
+// Players the game doesn't know about are ranked last, keeping their order
+func rankPlayerInfos(game models.Game, playerInfos []PlayerGameInfo) {
+	points := make(map[string]int)
+	for _, info := range playerInfos {
+		points[info.ID] = info.TotalScore
+	}
+	ranks := make(map[string]int)
+	position := make(map[string]int)
+	for i, standing := range game.RankPlayers(points) {
+		ranks[standing.Player.GetID()] = standing.Rank
+		position[standing.Player.GetID()] = i
+	}
+	for i := range playerInfos {
+		rank, ranked := ranks[playerInfos[i].ID]
+		if !ranked {
+			rank = len(ranks) + 1
+			position[playerInfos[i].ID] = len(ranks)
+		}
+		playerInfos[i].Rank = rank
+	}
+	sort.SliceStable(playerInfos, func(i, j int) bool {
+		return position[playerInfos[i].ID] < position[playerInfos[j].ID]
+	})
+}
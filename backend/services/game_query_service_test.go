@@ -113,6 +113,47 @@ func TestGameQueryService_GetPlayerGames_WithPlayersAndScores(t *testing.T) {
 	mockBetRepo.AssertExpectations(t)
 }
 
+func TestGameQueryService_GetPlayerGames_PlayersAreRanked(t *testing.T) {
+	// Setup
+	mockGameRepo := new(MockGameRepository)
+	mockGamePlayerRepo := new(MockGamePlayerRepository)
+	mockGameCodeRepo := new(MockGameCodeRepository)
+	mockBetRepo := new(MockBetRepository)
+
+	service := NewGameQueryService(mockGameRepo, mockGamePlayerRepo, mockGameCodeRepo, mockBetRepo)
+
+	player1 := &models.PlayerData{ID: "player1", Name: "Test Player"}
+	player2 := &models.PlayerData{ID: "player2", Name: "Other Player"}
+	player3 := &models.PlayerData{ID: "player3", Name: "Third Player"}
+	players := []models.Player{player1, player2, player3}
+
+	realGame := rules.NewFreshGame("2025/2026", "Ligue 1", "Test Game", players, []models.Match{}, &rules.ScorerOriginal{})
+
+	mockGamePlayerRepo.On("GetPlayerGames", mock.Anything, "player1").Return([]string{"game1"}, nil)
+	mockGameRepo.On("GetGame", "game1").Return(realGame, nil)
+	mockGamePlayerRepo.On("GetPlayersInGame", mock.Anything, "game1").Return(players, nil)
+	mockBetRepo.On("GetScoresByMatchAndPlayer", "game1").Return(map[string]map[string]int{
+		"match1": {"player1": 10, "player2": 30, "player3": 30},
+	}, nil)
+	mockGameCodeRepo.On("GetGameCodeByGameID", "game1").Return(nil, errors.New("code not found"))
+
+	// Execute
+	playerGames, err := service.GetPlayerGames(player1)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, playerGames, 1)
+
+	gamePlayers := playerGames[0].Players
+	assert.Len(t, gamePlayers, 3)
+	assert.Equal(t, "player2", gamePlayers[0].ID)
+	assert.Equal(t, 1, gamePlayers[0].Rank)
+	assert.Equal(t, "player3", gamePlayers[1].ID)
+	assert.Equal(t, 1, gamePlayers[1].Rank)
+	assert.Equal(t, "player1", gamePlayers[2].ID)
+	assert.Equal(t, 3, gamePlayers[2].Rank)
+}
+
 func TestGameQueryService_GetPlayerGames_EmptyList(t *testing.T) {
 	// Setup
 	mockGameRepo := new(MockGameRepository)
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"ligain/backend/models"
 	"ligain/backend/repositories"
+	"reflect"
 	"time"
 
 	log "github.com/sirupsen/logrus"
@@ -14,7 +15,12 @@ import (
 type GameService interface {
 	GetIncomingMatches(player models.Player) map[string]*models.MatchResult
 	GetMatchResults() map[string]*models.MatchResult
-	UpdatePlayerBet(player models.Player, bet *models.Bet, now time.Time) error
+	// GetProvisionalLeaderboard returns the standings as if the in-progress matches ended with their live score
+	GetProvisionalLeaderboard() (*models.ProvisionalLeaderboard, error)
+	// GetStandings ranks the players on their confirmed points, using the game's tiebreakers
+	GetStandings() ([]models.Standing, error)
+	// UpdatePlayerBet saves a bet of the player, ctx carries the device it was sent from
+	UpdatePlayerBet(ctx context.Context, player models.Player, bet *models.Bet, now time.Time) error
 	GetPlayerBets(player models.Player) ([]*models.Bet, error)
 	GetPlayers() []models.Player
 	// GameUpdateHandler interface methods
@@ -31,6 +37,13 @@ type GameServiceImpl struct {
 	betRepo        repositories.BetRepository
 	gamePlayerRepo repositories.GamePlayerRepository
 	timeFunc       func() time.Time // Function to get current time (for testing)
+	// scoring applies the results of the matches to the game, once each
+	scoring        matchScorer
+	scoreObservers []ScoreObserver
+	// activityObservers are notified of the bets placed, and of the kickoffs and goals of the matches
+	activityObservers []ActivityObserver
+	// betRecorders record the bets saved by the players, in the transaction of the bet
+	betRecorders []BetRecorder
 }
 
 func NewGameService(gameId string, gameRepo repositories.GameRepository, betRepo repositories.BetRepository, gamePlayerRepo repositories.GamePlayerRepository) *GameServiceImpl {
@@ -40,6 +53,7 @@ func NewGameService(gameId string, gameRepo repositories.GameRepository, betRepo
 		betRepo:        betRepo,
 		gamePlayerRepo: gamePlayerRepo,
 		timeFunc:       time.Now, // Default to real time
+		scoring:        matchScorer{uow: repositories.NewNoopUnitOfWork(), gameRepo: gameRepo, betRepo: betRepo},
 	}
 }
 
@@ -51,6 +65,31 @@ func NewGameServiceWithTime(gameId string, gameRepo repositories.GameRepository,
 		betRepo:        betRepo,
 		gamePlayerRepo: gamePlayerRepo,
 		timeFunc:       timeFunc,
+		scoring:        matchScorer{uow: repositories.NewNoopUnitOfWork(), gameRepo: gameRepo, betRepo: betRepo},
+	}
+}
+
+// AddScoreObserver registers an observer notified each time the scores of a match are applied to the game
+func (g *GameServiceImpl) AddScoreObserver(observer ScoreObserver) {
+	g.scoreObservers = append(g.scoreObservers, observer)
+}
+
+// AddActivityObserver registers an observer notified of the activity of the game
+func (g *GameServiceImpl) AddActivityObserver(observer ActivityObserver) {
+	g.activityObservers = append(g.activityObservers, observer)
+}
+
+// AddBetRecorder registers a recorder of the bets saved by the players
+func (g *GameServiceImpl) AddBetRecorder(recorder BetRecorder) {
+	g.betRecorders = append(g.betRecorders, recorder)
+}
+
+// notifyActivity sends an activity to the observers. The activity feed is informative, so a failing observer is only logged
+func (g *GameServiceImpl) notifyActivity(activity *models.Activity) {
+	for _, observer := range g.activityObservers {
+		if err := observer.OnActivity(activity); err != nil {
+			log.Errorf("Error notifying activity observer of %s in game %v: %v", activity.Type, g.gameId, err)
+		}
 	}
 }
 
@@ -77,8 +116,32 @@ func (g *GameServiceImpl) GetIncomingMatches(player models.Player) map[string]*m
 	return game.GetIncomingMatches(player)
 }
 
+// GetProvisionalLeaderboard scores the in-progress matches against their live score without persisting anything.
+// Live scores are kept up to date by HandleMatchUpdates, so the leaderboard moves with every goal the watcher reports.
+func (g *GameServiceImpl) GetProvisionalLeaderboard() (*models.ProvisionalLeaderboard, error) {
+	game, err := g.getGame()
+	if err != nil {
+		log.Errorf("Error getting game: %v", err)
+		return nil, err
+	}
+	leaderboard := models.NewProvisionalLeaderboard(game.GetPlayersPoints(), game.GetProvisionalResults())
+	leaderboard.Standings = game.RankPlayers(leaderboard.ProvisionalPoints)
+	return leaderboard, nil
+}
+
+// GetStandings ranks the players on their confirmed points, using the game's tiebreakers
+func (g *GameServiceImpl) GetStandings() ([]models.Standing, error) {
+	game, err := g.getGame()
+	if err != nil {
+		log.Errorf("Error getting game: %v", err)
+		return nil, err
+	}
+	return game.GetStandings(), nil
+}
+
 // HandleMatchUpdates implements GameUpdateHandler interface
 func (g *GameServiceImpl) HandleMatchUpdates(updates map[string]models.Match) error {
+	defer lockGame(g.gameId)()
 	// Get current game state
 	game, err := g.getGame()
 	if err != nil {
@@ -95,7 +158,10 @@ func (g *GameServiceImpl) HandleMatchUpdates(updates map[string]models.Match) er
 			updateErrors = append(updateErrors, fmt.Errorf("match %s: get last match state: %w", match.Id(), err))
 			continue
 		}
-		match = g.adjustOdds(match, lastMatchState)
+		// The last state may be updated in place by the game, so keep what is needed to detect kickoffs and goals
+		wasStarted := lastMatchState.IsInProgress() || lastMatchState.IsFinished()
+		lastGoals := lastMatchState.GetHomeGoals() + lastMatchState.GetAwayGoals()
+		match = g.adjustOdds(game, match, lastMatchState)
 		if match.IsFinished() && !hasUsableOdds(match) {
 			err := fmt.Errorf("match %s: missing odds for finished match", match.Id())
 			log.Errorf("Error handling score update: %v", err)
@@ -108,6 +174,15 @@ func (g *GameServiceImpl) HandleMatchUpdates(updates map[string]models.Match) er
 			updateErrors = append(updateErrors, fmt.Errorf("match %s: update match: %w", match.Id(), err))
 			continue
 		}
+		if match.IsInProgress() && !wasStarted {
+			g.notifyActivity(models.NewMatchActivity(g.gameId, models.ActivityMatchStarted, match))
+		}
+		if (wasStarted || match.IsInProgress()) && match.GetHomeGoals()+match.GetAwayGoals() > lastGoals {
+			g.notifyActivity(models.NewMatchActivity(g.gameId, models.ActivityGoal, match))
+		}
+		if match.IsInProgress() {
+			log.Infof("Match %v is in progress with live score %d - %d, provisional leaderboard updated", match.Id(), match.GetHomeGoals(), match.GetAwayGoals())
+		}
 		if match.IsFinished() {
 			log.Infof("Match %v is finished with score %d - %d, handling score update", match.Id(), match.GetHomeGoals(), match.GetAwayGoals())
 			err = g.handleScoreUpdate(match)
@@ -119,6 +194,14 @@ func (g *GameServiceImpl) HandleMatchUpdates(updates map[string]models.Match) er
 		}
 	}
 
+	// The live updates of the matches are only recorded in the history of the game, the scored ones already are
+	if recorder, ok := g.gameRepo.(repositories.GameHistoryRecorder); ok {
+		if err := recorder.RecordEvents(context.Background(), g.gameId, game); err != nil {
+			log.Errorf("Error recording match updates: %v", err)
+			updateErrors = append(updateErrors, fmt.Errorf("record match updates: %w", err))
+		}
+	}
+
 	// Check if game is finished after processing updates
 	if game.IsFinished() {
 		winners := game.GetWinner()
@@ -139,6 +222,8 @@ func (g *GameServiceImpl) GetGameID() string {
 	return g.gameId
 }
 
+// handleScoreUpdate applies the first scoring of a finished match. The scores and the status of the game are saved
+// in a single transaction with the scoring, so a result handled twice, like after a crash, is only scored once
 func (g *GameServiceImpl) handleScoreUpdate(match models.Match) error {
 	game, err := g.getGame()
 	if err != nil {
@@ -151,41 +236,36 @@ func (g *GameServiceImpl) handleScoreUpdate(match models.Match) error {
 		log.Errorf("Error calculating match scores: %v", err)
 		return err
 	}
-	for playerID, score := range scores {
-		// Find the player by ID
-		var player models.Player
-		for _, p := range game.GetPlayers() {
-			if p.GetID() == playerID {
-				player = p
-				break
-			}
-		}
-		if player == nil {
-			log.Errorf("Player with ID %v not found", playerID)
-			continue
-		}
-
-		err = g.betRepo.SaveScore(g.gameId, match, player, score)
-		if err != nil {
-			log.Errorf("Error saving score for player %v: %v", player, err)
-			return err
-		}
-		log.Infof("Player %v has earned %v points for match %v", player, score, match.Id())
-	}
-
-	game.ApplyMatchScores(match, scores)
 
-	// Save the updated game
-	err = g.gameRepo.SaveWithId(g.gameId, game)
+	scoring := models.NewMatchScoring(g.gameId, match.Id(), models.InitialScoringVersion, g.timeFunc())
+	applied, err := g.scoring.apply(context.Background(), game, scoring, match, func() (map[string]int, error) {
+		game.ApplyMatchScores(match, scores)
+		return scores, nil
+	})
 	if err != nil {
-		log.Errorf("Error saving game after score update: %v", err)
+		log.Errorf("Error applying scores of match %v: %v", match.Id(), err)
 		return err
 	}
+	if !applied {
+		return nil
+	}
+	for playerID, score := range scores {
+		log.Infof("Player %v has earned %v points for match %v", playerID, score, match.Id())
+	}
+
+	// Scores are already saved, so a failing observer must not fail the update
+	for _, observer := range g.scoreObservers {
+		if err := observer.OnMatchScored(g.gameId, game, match, scoring); err != nil {
+			log.Errorf("Error notifying score observer for match %v: %v", match.Id(), err)
+		}
+	}
 
 	return nil
 }
 
-func (g *GameServiceImpl) UpdatePlayerBet(player models.Player, bet *models.Bet, now time.Time) error {
+func (g *GameServiceImpl) UpdatePlayerBet(ctx context.Context, player models.Player, bet *models.Bet, now time.Time) error {
+	defer lockGame(g.gameId)()
+
 	game, err := g.getGame()
 	if err != nil {
 		log.Errorf("Error getting game: %v", err)
@@ -197,19 +277,36 @@ func (g *GameServiceImpl) UpdatePlayerBet(player models.Player, bet *models.Bet,
 		log.Errorf("Error checking player bet validity: %v", err)
 		return err
 	}
-	_, savedBet, err := g.betRepo.SaveBet(g.gameId, bet, player)
+	// The bet, its history and the events of the game are saved in a single transaction, if saving fails the game is evicted
+	var savedBet *models.Bet
+	err = g.scoring.uow.WithinTx(ctx, func(txCtx context.Context) error {
+		_, savedBet, err = g.betRepo.SaveBet(txCtx, g.gameId, bet, player)
+		if err != nil {
+			return fmt.Errorf("error saving bet: %v", err)
+		}
+		for _, recorder := range g.betRecorders {
+			if err := recorder.RecordBet(txCtx, g.gameId, player, savedBet, now); err != nil {
+				return err
+			}
+		}
+		game.AddPlayerBet(player, savedBet)
+		if recorder, ok := g.gameRepo.(repositories.GameHistoryRecorder); ok {
+			return recorder.RecordEvents(txCtx, g.gameId, game)
+		}
+		return g.gameRepo.SaveWithId(g.gameId, game)
+	})
 	if err != nil {
 		log.Errorf("Error saving bet: %v", err)
+		g.scoring.evictGame(g.gameId)
 		return err
 	}
-	game.AddPlayerBet(player, savedBet)
 
-	// Save the updated game
-	err = g.gameRepo.SaveWithId(g.gameId, game)
-	if err != nil {
-		log.Errorf("Error saving game after bet update: %v", err)
-		return err
-	}
+	// The scoreline of the bet is deliberately left out of the feed
+	activity := models.NewPlayerActivity(g.gameId, models.ActivityBetPlaced, player)
+	activity.MatchID = bet.Match.Id()
+	activity.Details.HomeTeam = bet.Match.GetHomeTeam()
+	activity.Details.AwayTeam = bet.Match.GetAwayTeam()
+	g.notifyActivity(activity)
 
 	return nil
 }
@@ -230,6 +327,8 @@ func (g *GameServiceImpl) GetPlayers() []models.Player {
 }
 
 func (g *GameServiceImpl) AddPlayer(player models.Player) error {
+	defer lockGame(g.gameId)()
+
 	game, err := g.getGame()
 	if err != nil {
 		log.Errorf("Error getting game: %v", err)
@@ -252,6 +351,8 @@ func (g *GameServiceImpl) AddPlayer(player models.Player) error {
 }
 
 func (g *GameServiceImpl) RemovePlayer(player models.Player) error {
+	defer lockGame(g.gameId)()
+
 	game, err := g.getGame()
 	if err != nil {
 		log.Errorf("Error getting game: %v", err)
@@ -273,12 +374,12 @@ func (g *GameServiceImpl) RemovePlayer(player models.Player) error {
 	return nil
 }
 
-// adjustOdds ensures that odds are blocked 5minutes before the match starts.
-// We use 6 to ensure that the odds are always blocked at least 5 minutes before the match starts.
-func (g *GameServiceImpl) adjustOdds(match models.Match, lastMatchState models.Match) models.Match {
-	matchDate := match.GetDate()
+// adjustOdds ensures that odds are blocked 5minutes before the bets close on the match.
+// We use 6 to ensure that the odds are always blocked at least 5 minutes before the bets close.
+func (g *GameServiceImpl) adjustOdds(game models.Game, match models.Match, lastMatchState models.Match) models.Match {
+	closesAt := game.BettingClosesAt(match)
 	now := g.timeFunc()
-	if matchDate.Before(now.Add(6 * time.Minute)) {
+	if closesAt.Before(now.Add(6 * time.Minute)) {
 		match.SetHomeTeamOdds(lastMatchState.GetHomeTeamOdds())
 		match.SetAwayTeamOdds(lastMatchState.GetAwayTeamOdds())
 		match.SetDrawOdds(lastMatchState.GetDrawOdds())
@@ -289,3 +390,81 @@ func (g *GameServiceImpl) adjustOdds(match models.Match, lastMatchState models.M
 func hasUsableOdds(match models.Match) bool {
 	return match.GetHomeTeamOdds() > 0 && match.GetAwayTeamOdds() > 0 && match.GetDrawOdds() > 0
 }
+
+// ReconcileScores checks the game against the scores saved in the database. The past matches which were never scored,
+// like when the instance stopped before scoring them, are scored, with or without bets. The matches which kicked off
+// before the first match the game followed, bet on or scored, finished before the game and aren't scored. The cached
+// game is loaded again when its points differ from the saved scores
+func (g *GameServiceImpl) ReconcileScores(ctx context.Context) (*ScoreReconciliation, error) {
+	defer lockGame(g.gameId)()
+
+	game, err := g.getGame()
+	if err != nil {
+		return nil, err
+	}
+	versions, err := g.betRepo.GetScoringVersions(ctx, g.gameId)
+	if err != nil {
+		return nil, err
+	}
+
+	results := game.GetPastResults()
+	followedSince, followed := firstFollowedKickoff(results, versions)
+	reconciliation := &ScoreReconciliation{Scored: make([]string, 0)}
+	for matchID, result := range results {
+		if versions[matchID] != 0 || !followed || result.Match.GetDate().Before(followedSince) {
+			continue
+		}
+		match := result.Match
+		scoring := models.NewMatchScoring(g.gameId, matchID, models.InitialScoringVersion, g.timeFunc())
+		applied, err := g.scoring.apply(ctx, game, scoring, match, func() (map[string]int, error) {
+			return game.RescoreMatch(match)
+		})
+		if err != nil {
+			return reconciliation, fmt.Errorf("error scoring match %s: %w", matchID, err)
+		}
+		if !applied {
+			// Scored meanwhile by another instance, the game was evicted
+			return reconciliation, nil
+		}
+		reconciliation.Scored = append(reconciliation.Scored, matchID)
+		for _, observer := range g.scoreObservers {
+			if err := observer.OnMatchScored(g.gameId, game, match, scoring); err != nil {
+				log.Errorf("Error notifying score observer for match %v: %v", matchID, err)
+			}
+		}
+	}
+
+	saved, err := g.betRepo.GetScoresByMatchAndPlayer(g.gameId)
+	if err != nil {
+		return reconciliation, err
+	}
+	savedPoints := make(map[string]int)
+	for _, matchScores := range saved {
+		for playerID, points := range matchScores {
+			savedPoints[playerID] += points
+		}
+	}
+	if !reflect.DeepEqual(savedPoints, game.GetPlayersPoints()) {
+		log.Warnf("Points of game %s differ from the saved scores, loading it again", g.gameId)
+		reconciliation.Mismatched = true
+		g.scoring.evictGame(g.gameId)
+	}
+	return reconciliation, nil
+}
+
+// firstFollowedKickoff returns the kickoff of the first past match the game followed, which had bets or was scored.
+// It returns false when the game followed none
+func firstFollowedKickoff(results map[string]*models.MatchResult, versions map[string]int) (time.Time, bool) {
+	var first time.Time
+	followed := false
+	for matchID, result := range results {
+		if versions[matchID] == 0 && len(result.Bets) == 0 {
+			continue
+		}
+		if kickoff := result.Match.GetDate(); !followed || kickoff.Before(first) {
+			first = kickoff
+			followed = true
+		}
+	}
+	return first, followed
+}
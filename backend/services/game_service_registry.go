@@ -1,7 +1,10 @@
 package services
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"ligain/backend/models"
 	"ligain/backend/repositories"
 	"sync"
 
@@ -26,21 +29,43 @@ type GameServiceRegistry struct {
 	betRepo        repositories.BetRepository
 	gamePlayerRepo repositories.GamePlayerRepository
 	watcher        MatchWatcherService
-	gameServices   sync.Map
+	scoreObservers []ScoreObserver
+	betRecorders   []BetRecorder
+	// uow is the transaction the game services apply the scores of the matches in
+	uow          repositories.UnitOfWork
+	gameServices sync.Map
 }
 
 // NewGameServiceRegistry creates a new GameServiceRegistry instance and loads all existing games
+// The score observers are registered on every GameService the registry creates.
+// Observers that also implement ActivityObserver receive the activity of the games as well
 func NewGameServiceRegistry(
 	gameRepo repositories.GameRepository,
 	betRepo repositories.BetRepository,
 	gamePlayerRepo repositories.GamePlayerRepository,
 	watcher MatchWatcherService,
+	scoreObservers ...ScoreObserver,
+) (*GameServiceRegistry, error) {
+	return NewGameServiceRegistryWithUnitOfWork(repositories.NewNoopUnitOfWork(), gameRepo, betRepo, gamePlayerRepo, watcher, scoreObservers...)
+}
+
+// NewGameServiceRegistryWithUnitOfWork works like NewGameServiceRegistry, with the game services applying
+// the scores of the matches within the transactions of uow
+func NewGameServiceRegistryWithUnitOfWork(
+	uow repositories.UnitOfWork,
+	gameRepo repositories.GameRepository,
+	betRepo repositories.BetRepository,
+	gamePlayerRepo repositories.GamePlayerRepository,
+	watcher MatchWatcherService,
+	scoreObservers ...ScoreObserver,
 ) (*GameServiceRegistry, error) {
 	r := &GameServiceRegistry{
 		gameRepo:       gameRepo,
 		betRepo:        betRepo,
 		gamePlayerRepo: gamePlayerRepo,
 		watcher:        watcher,
+		scoreObservers: scoreObservers,
+		uow:            uow,
 	}
 	if err := r.loadAll(); err != nil {
 		return nil, err
@@ -50,7 +75,7 @@ func NewGameServiceRegistry(
 
 // Create creates a new GameService and registers it
 func (r *GameServiceRegistry) Create(gameID string) (GameService, error) {
-	gameService := NewGameService(gameID, r.gameRepo, r.betRepo, r.gamePlayerRepo)
+	gameService := r.newGameService(gameID)
 
 	// Subscribe to watcher if available
 	if r.watcher != nil {
@@ -82,6 +107,83 @@ func (r *GameServiceRegistry) Unregister(gameID string) {
 	r.gameServices.Delete(gameID)
 }
 
+// Refresh brings the registry in line with a game changed by another instance: a game created there is registered,
+// and a game finished there stops following its matches. Games finished here stay registered, so the same goes
+// for the ones finished elsewhere
+func (r *GameServiceRegistry) Refresh(gameID string) error {
+	game, err := r.gameRepo.GetGame(gameID)
+	if err != nil {
+		return fmt.Errorf("failed to load game %s: %v", gameID, err)
+	}
+
+	_, registered := r.Get(gameID)
+	finished := game.GetGameStatus() == models.GameStatusFinished
+	switch {
+	case !registered && !finished:
+		if _, err := r.Create(gameID); err != nil {
+			return err
+		}
+		log.Infof("Registered game %s created on another instance", gameID)
+	case registered && finished && r.watcher != nil:
+		if err := r.watcher.Unsubscribe(gameID); err != nil {
+			return fmt.Errorf("failed to unsubscribe game from watcher: %v", err)
+		}
+	}
+	return nil
+}
+
+// ReconcileScores checks every registered game against the scores saved in the database, see GameServiceImpl.ReconcileScores
+func (r *GameServiceRegistry) ReconcileScores(ctx context.Context) error {
+	var reconcileErrors []error
+	r.gameServices.Range(func(key, value any) bool {
+		gameService, ok := value.(*GameServiceImpl)
+		if !ok {
+			return true
+		}
+		reconciliation, err := gameService.ReconcileScores(ctx)
+		if err != nil {
+			reconcileErrors = append(reconcileErrors, fmt.Errorf("game %s: %w", key, err))
+		}
+		if reconciliation != nil && (len(reconciliation.Scored) > 0 || reconciliation.Mismatched) {
+			log.WithFields(log.Fields{
+				"gameId":     key,
+				"scored":     reconciliation.Scored,
+				"mismatched": reconciliation.Mismatched,
+			}).Warn("Scores of game reconciled")
+		}
+		return true
+	})
+	return errors.Join(reconcileErrors...)
+}
+
+// AddBetRecorder registers a recorder of the bets saved in every game, the ones already loaded included.
+// It must be called before the games are used
+func (r *GameServiceRegistry) AddBetRecorder(recorder BetRecorder) {
+	r.betRecorders = append(r.betRecorders, recorder)
+	r.gameServices.Range(func(_, gs any) bool {
+		if gameService, ok := gs.(*GameServiceImpl); ok {
+			gameService.AddBetRecorder(recorder)
+		}
+		return true
+	})
+}
+
+// newGameService creates a GameService with the registry's score and activity observers, and bet recorders
+func (r *GameServiceRegistry) newGameService(gameID string) *GameServiceImpl {
+	gameService := NewGameService(gameID, r.gameRepo, r.betRepo, r.gamePlayerRepo)
+	gameService.scoring.uow = r.uow
+	for _, observer := range r.scoreObservers {
+		gameService.AddScoreObserver(observer)
+		if activityObserver, ok := observer.(ActivityObserver); ok {
+			gameService.AddActivityObserver(activityObserver)
+		}
+	}
+	for _, recorder := range r.betRecorders {
+		gameService.AddBetRecorder(recorder)
+	}
+	return gameService
+}
+
 // loadAll loads all existing games from the repository
 func (r *GameServiceRegistry) loadAll() error {
 	games, err := r.gameRepo.GetAllGames()
@@ -90,7 +192,7 @@ func (r *GameServiceRegistry) loadAll() error {
 	}
 
 	for gameID := range games {
-		gameService := NewGameService(gameID, r.gameRepo, r.betRepo, r.gamePlayerRepo)
+		gameService := r.newGameService(gameID)
 		r.gameServices.Store(gameID, gameService)
 
 		// Subscribe to watcher if available
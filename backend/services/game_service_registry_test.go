@@ -100,6 +100,22 @@ func TestGameServiceRegistry_Create_NoWatcher(t *testing.T) {
 	assert.Equal(t, "game1", gameService.GetGameID())
 }
 
+func TestGameServiceRegistry_Create_RegistersScoreObservers(t *testing.T) {
+	mockGameRepo := new(MockGameRepository)
+	mockGameRepo.On("GetAllGames").Return(map[string]models.Game{}, nil)
+	observer := &failingScoreObserver{}
+
+	registry, err := NewGameServiceRegistry(mockGameRepo, new(MockBetRepository), new(MockGamePlayerRepository), nil, observer)
+	require.NoError(t, err)
+
+	// Execute
+	gameService, err := registry.Create("game1")
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, []ScoreObserver{observer}, gameService.(*GameServiceImpl).scoreObservers)
+}
+
 func TestGameServiceRegistry_Register(t *testing.T) {
 	registry, mockGameRepo, mockBetRepo, mockGamePlayerRepo := setupEmptyRegistry(t, nil)
 
@@ -292,3 +308,36 @@ func TestGameServiceRegistry_Get_NotExists(t *testing.T) {
 	assert.False(t, exists)
 	assert.Nil(t, gameService)
 }
+
+func TestGameServiceRegistry_Refresh(t *testing.T) {
+	mockWatcher := new(MockWatcher)
+	registry, mockGameRepo, _, _ := setupEmptyRegistry(t, mockWatcher)
+
+	running := rules.NewFreshGame("2025/2026", "Ligue 1", "Running", []models.Player{}, []models.Match{}, &rules.ScorerOriginal{})
+	finished := rules.NewFreshGame("2025/2026", "Ligue 1", "Finished", []models.Player{}, []models.Match{}, &rules.ScorerOriginal{})
+	finished.Finish()
+	mockGameRepo.On("GetGame", "created-elsewhere").Return(running, nil)
+	mockGameRepo.On("GetGame", "finished-elsewhere").Return(finished, nil)
+	mockGameRepo.On("GetGame", "unknown").Return(nil, errors.New("game not found"))
+	mockWatcher.On("Subscribe", mock.AnythingOfType("*services.GameServiceImpl")).Return(nil).Twice()
+	mockWatcher.On("Unsubscribe", "finished-elsewhere").Return(nil).Once()
+
+	// A game created on another instance is registered once
+	require.NoError(t, registry.Refresh("created-elsewhere"))
+	_, exists := registry.Get("created-elsewhere")
+	assert.True(t, exists)
+	require.NoError(t, registry.Refresh("created-elsewhere"))
+
+	// A game finished on another instance stays registered, but stops following its matches
+	_, err := registry.Create("finished-elsewhere")
+	require.NoError(t, err)
+	require.NoError(t, registry.Refresh("finished-elsewhere"))
+	_, exists = registry.Get("finished-elsewhere")
+	assert.True(t, exists)
+
+	assert.Error(t, registry.Refresh("unknown"))
+	_, exists = registry.Get("unknown")
+	assert.False(t, exists)
+
+	mockWatcher.AssertExpectations(t)
+}
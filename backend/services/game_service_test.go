@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"ligain/backend/models"
 	"ligain/backend/repositories"
@@ -41,6 +42,11 @@ func (r *gameRepositoryMock) GetAllGames() (map[string]models.Game, error) {
 	return args.Get(0).(map[string]models.Game), args.Error(1)
 }
 
+func (r *gameRepositoryMock) SaveGameStatus(ctx context.Context, gameId string, status models.GameStatus) error {
+	args := r.Called(gameId, status)
+	return args.Error(0)
+}
+
 type scorerMock struct{}
 
 func (s *scorerMock) Score(match models.Match, bets []*models.Bet) []int {
@@ -92,6 +98,11 @@ func (g *flakyUpdateGame) GetName() string { return "Test Game" }
 func (g *flakyUpdateGame) CheckPlayerBetValidity(player models.Player, bet *models.Bet, datetime time.Time) error {
 	return nil
 }
+func (g *flakyUpdateGame) GetBetCutoff() models.BetCutoff { return models.BetCutoff{} }
+func (g *flakyUpdateGame) GetTiebreakers() []string       { return nil }
+func (g *flakyUpdateGame) BettingClosesAt(match models.Match) time.Time {
+	return match.GetDate()
+}
 func (g *flakyUpdateGame) AddPlayerBet(player models.Player, bet *models.Bet) error { return nil }
 func (g *flakyUpdateGame) AddPlayer(player models.Player) error                     { return nil }
 func (g *flakyUpdateGame) RemovePlayer(player models.Player) error                  { return nil }
@@ -111,7 +122,15 @@ func (g *flakyUpdateGame) GetPlayersPoints() map[string]int { return make(map[st
 func (g *flakyUpdateGame) GetPlayers() []models.Player      { return nil }
 func (g *flakyUpdateGame) IsFinished() bool                 { return false }
 func (g *flakyUpdateGame) GetWinner() []models.Player       { return nil }
-func (g *flakyUpdateGame) GetIncomingMatchesForTesting() map[string]*models.MatchResult {
+func (g *flakyUpdateGame) GetStandings() []models.Standing  { return nil }
+func (g *flakyUpdateGame) RankPlayers(points map[string]int) []models.Standing {
+	return nil
+}
+func (g *flakyUpdateGame) GetIncomingMatchesWithAllBets() map[string]*models.MatchResult {
+	return make(map[string]*models.MatchResult)
+}
+
+func (g *flakyUpdateGame) GetProvisionalResults() map[string]*models.MatchResult {
 	return make(map[string]*models.MatchResult)
 }
 func (g *flakyUpdateGame) GetMatchById(matchId string) (models.Match, error) {
@@ -122,6 +141,10 @@ func (g *flakyUpdateGame) GetMatchById(matchId string) (models.Match, error) {
 	return match, nil
 }
 func (g *flakyUpdateGame) Finish() {}
+func (g *flakyUpdateGame) Reopen() {}
+func (g *flakyUpdateGame) RescoreMatch(match models.Match) (map[string]int, error) {
+	return nil, nil
+}
 
 // testPlayer is a concrete implementation of models.Player for testing
 type testPlayer struct {
@@ -171,6 +194,7 @@ func setupTestGameService() (*GameServiceImpl, models.Match, []models.Player) {
 	gameRepo := &gameRepositoryMock{}
 	gameRepo.On("GetGame", "test-game").Return(game, nil)
 	gameRepo.On("SaveWithId", "test-game", mock.AnythingOfType("*rules.GameImpl")).Return(nil)
+	gameRepo.On("SaveGameStatus", "test-game", mock.AnythingOfType("models.GameStatus")).Return(nil)
 	betRepo := repositories.NewInMemoryBetRepository()
 
 	// Create player repository and add players to it
@@ -217,7 +241,7 @@ func TestGameService_UpdatePlayerBet(t *testing.T) {
 
 	t.Run("successfully updates player bet", func(t *testing.T) {
 		bet := models.NewBet(match, 2, 1)
-		err := service.UpdatePlayerBet(player1, bet, matchTime.Add(-1*time.Hour))
+		err := service.UpdatePlayerBet(context.Background(), player1, bet, matchTime.Add(-1*time.Hour))
 		require.NoError(t, err)
 
 		// Verify bet was saved
@@ -230,19 +254,62 @@ func TestGameService_UpdatePlayerBet(t *testing.T) {
 
 	t.Run("fails when betting after match time", func(t *testing.T) {
 		bet := models.NewBet(match, 1, 1)
-		err := service.UpdatePlayerBet(player1, bet, matchTime.Add(1*time.Hour))
+		err := service.UpdatePlayerBet(context.Background(), player1, bet, matchTime.Add(1*time.Hour))
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "too late to bet")
 	})
 }
 
+// failingHistoryGameRepository fails to record the events of the games
+type failingHistoryGameRepository struct {
+	*evictingGameRepository
+}
+
+func (r *failingHistoryGameRepository) RecordEvents(ctx context.Context, gameId string, game models.Game) error {
+	return errors.New("event append failed")
+}
+
+func (r *failingHistoryGameRepository) RebaseGame(ctx context.Context, gameId string) error {
+	return nil
+}
+
+// rollbackRecordingUoW records the transactions which were rolled back
+type rollbackRecordingUoW struct {
+	rolledBack int
+}
+
+func (u *rollbackRecordingUoW) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	err := fn(ctx)
+	if err != nil {
+		u.rolledBack++
+	}
+	return err
+}
+
+func TestGameService_UpdatePlayerBet_EventsNotRecorded(t *testing.T) {
+	service, match, players := setupTestGameService()
+	gameRepo := &failingHistoryGameRepository{&evictingGameRepository{GameRepository: service.gameRepo}}
+	uow := &rollbackRecordingUoW{}
+	service.gameRepo = gameRepo
+	service.scoring = matchScorer{uow: uow, gameRepo: gameRepo, betRepo: service.betRepo}
+
+	bet := models.NewBet(match, 2, 1)
+	err := service.UpdatePlayerBet(context.Background(), players[0], bet, matchTime.Add(-1*time.Hour))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "event append failed")
+
+	// The bet is rolled back with the events, and the game is loaded again
+	assert.Equal(t, 1, uow.rolledBack)
+	assert.Equal(t, []string{"test-game"}, gameRepo.evicted)
+}
+
 func TestGameService_HandleMatchUpdates(t *testing.T) {
 	service, match, players := setupTestGameService()
 	player1 := players[0]
 
 	// Add a bet first
 	bet := models.NewBet(match, 2, 1)
-	err := service.UpdatePlayerBet(player1, bet, matchTime.Add(-1*time.Hour))
+	err := service.UpdatePlayerBet(context.Background(), player1, bet, matchTime.Add(-1*time.Hour))
 	require.NoError(t, err)
 
 	t.Run("handles match updates correctly", func(t *testing.T) {
@@ -255,6 +322,7 @@ func TestGameService_HandleMatchUpdates(t *testing.T) {
 
 		// Set up mock expectation for SaveWithId since the game will finish
 		service.gameRepo.(*gameRepositoryMock).On("SaveWithId", "test-game", mock.AnythingOfType("*rules.GameImpl")).Return(nil)
+		service.gameRepo.(*gameRepositoryMock).On("SaveGameStatus", "test-game", mock.AnythingOfType("models.GameStatus")).Return(nil)
 
 		err := service.HandleMatchUpdates(updates)
 		require.NoError(t, err)
@@ -289,12 +357,13 @@ func TestGameService_HandleMatchUpdates(t *testing.T) {
 		// Set up mock expectations
 		gameRepo.On("GetGame", "test-game").Return(game, nil)
 		gameRepo.On("SaveWithId", "test-game", mock.AnythingOfType("*rules.GameImpl")).Return(nil)
+		gameRepo.On("SaveGameStatus", "test-game", mock.AnythingOfType("models.GameStatus")).Return(nil)
 
 		service := NewGameService("test-game", gameRepo, betRepo, repositories.NewInMemoryGamePlayerRepository(repositories.NewInMemoryPlayerRepository()))
 
 		// Add bet for second match
 		bet2 := models.NewBet(match2, 1, 0)
-		err := service.UpdatePlayerBet(player1, bet2, matchTime)
+		err := service.UpdatePlayerBet(context.Background(), player1, bet2, matchTime)
 		require.NoError(t, err)
 
 		// Create updates for both matches
@@ -308,6 +377,7 @@ func TestGameService_HandleMatchUpdates(t *testing.T) {
 
 		// Set up mock expectation for SaveWithId since the game will finish
 		gameRepo.On("SaveWithId", "test-game", mock.AnythingOfType("*rules.GameImpl")).Return(nil)
+		gameRepo.On("SaveGameStatus", "test-game", mock.AnythingOfType("models.GameStatus")).Return(nil)
 
 		err = service.HandleMatchUpdates(updates)
 		require.NoError(t, err)
@@ -322,6 +392,53 @@ func TestGameService_HandleMatchUpdates(t *testing.T) {
 	})
 }
 
+func TestGameService_GetProvisionalLeaderboard(t *testing.T) {
+	service, match, players := setupTestGameService()
+	player1 := players[0]
+	player2 := players[1]
+
+	require.NoError(t, service.UpdatePlayerBet(context.Background(), player1, models.NewBet(match, 2, 1), matchTime.Add(-1*time.Hour)))
+	require.NoError(t, service.UpdatePlayerBet(context.Background(), player2, models.NewBet(match, 0, 1), matchTime.Add(-1*time.Hour)))
+
+	// The watcher reports a goal for the home team
+	liveMatch := newTestSeasonMatchWithOdds("Team1", "Team2", matchTime, 1)
+	liveMatch.Start()
+	liveMatch.HomeGoals = 1
+	require.NoError(t, service.HandleMatchUpdates(map[string]models.Match{match.Id(): liveMatch}))
+
+	leaderboard, err := service.GetProvisionalLeaderboard()
+	require.NoError(t, err)
+	assert.True(t, leaderboard.Provisional)
+	require.Contains(t, leaderboard.LiveMatches, match.Id())
+	assert.Equal(t, 500, leaderboard.ProvisionalPoints[player1.GetID()])
+	assert.Equal(t, 0, leaderboard.ProvisionalPoints[player2.GetID()])
+	assert.Empty(t, leaderboard.ConfirmedPoints)
+
+	// The away team equalizes, then scores again
+	liveMatch.AwayGoals = 2
+	require.NoError(t, service.HandleMatchUpdates(map[string]models.Match{match.Id(): liveMatch}))
+
+	leaderboard, err = service.GetProvisionalLeaderboard()
+	require.NoError(t, err)
+	assert.Equal(t, 0, leaderboard.ProvisionalPoints[player1.GetID()])
+	assert.Equal(t, 500, leaderboard.ProvisionalPoints[player2.GetID()])
+	require.Len(t, leaderboard.Standings, 2)
+	assert.Equal(t, player2.GetID(), leaderboard.Standings[0].Player.GetID())
+	assert.Equal(t, 1, leaderboard.Standings[0].Rank)
+	assert.Equal(t, 2, leaderboard.Standings[1].Rank)
+
+	// The confirmed standings are untouched
+	standings, err := service.GetStandings()
+	require.NoError(t, err)
+	require.Len(t, standings, 2)
+	assert.Equal(t, []int{1, 1}, []int{standings[0].Rank, standings[1].Rank})
+
+	// Nothing was persisted
+	scores, err := service.betRepo.GetScores("test-game")
+	require.NoError(t, err)
+	assert.Empty(t, scores)
+}
+
 func TestGameService_HandleMatchUpdates_ContinuesAfterPerMatchError(t *testing.T) {
 	match1 := models.NewSeasonMatch("Team1", "Team2", "2024", "Premier League", matchTime, 1)
 	match2 := models.NewSeasonMatch("Team3", "Team4", "2024", "Premier League", matchTime.Add(1*time.Hour), 2)
@@ -358,7 +475,7 @@ func TestGameService_HandleMatchUpdates_DoesNotScoreFinishedMatchWithoutOdds(t *
 
 	player1 := players[0]
 	bet := models.NewBet(match, 2, 1)
-	err := service.UpdatePlayerBet(player1, bet, matchTime.Add(-1*time.Hour))
+	err := service.UpdatePlayerBet(context.Background(), player1, bet, matchTime.Add(-1*time.Hour))
 	require.NoError(t, err)
 
 	finishedMatchWithoutOdds := models.NewFinishedSeasonMatch("Team1", "Team2", 2, 1, "2024", "Premier League", matchTime, 1, 0, 0, 0)
@@ -666,6 +783,22 @@ func TestGameService_HandleMatchUpdates_AdjustOdds(t *testing.T) {
 	})
 }
 
+// TestGameService_AdjustOddsAtBetCutoff tests that the odds are blocked before the bets close, not before kickoff
+func TestGameService_AdjustOddsAtBetCutoff(t *testing.T) {
+	baseTime := time.Date(2024, 1, 10, 15, 0, 0, 0, time.UTC)
+	lastMatchState := models.NewSeasonMatchWithKnownOdds("Team1", "Team2", "2024", "Premier League", baseTime.Add(30*time.Minute), 1, 2.0, 3.0, 4.0)
+	game := rules.NewFreshGame("2024", "Premier League", "Test Game", nil, []models.Match{lastMatchState}, &rules.ScorerOriginal{})
+	game.SetBetCutoff(models.BetCutoff{Minutes: 60})
+	service := &GameServiceImpl{timeFunc: func() time.Time { return baseTime }}
+
+	update := models.NewSeasonMatchWithKnownOdds("Team1", "Team2", "2024", "Premier League", baseTime.Add(30*time.Minute), 1, 1.5, 2.5, 3.5)
+	result := service.adjustOdds(game, update, lastMatchState)
+
+	assert.Equal(t, 2.0, result.GetHomeTeamOdds(), "the bets closed 30 minutes ago")
+	assert.Equal(t, 3.0, result.GetAwayTeamOdds())
+	assert.Equal(t, 4.0, result.GetDrawOdds())
+}
+
 // TestGameService_PlayerChangesDisplayNameMidGame tests that a player can change their display name
 // in the middle of a game and continue playing, placing bets, and winning
 func TestGameService_PlayerChangesDisplayNameMidGame(t *testing.T) {
@@ -684,6 +817,7 @@ func TestGameService_PlayerChangesDisplayNameMidGame(t *testing.T) {
 	gameRepo := &gameRepositoryMock{}
 	gameRepo.On("GetGame", "test-game").Return(game, nil)
 	gameRepo.On("SaveWithId", "test-game", mock.AnythingOfType("*rules.GameImpl")).Return(nil)
+	gameRepo.On("SaveGameStatus", "test-game", mock.AnythingOfType("models.GameStatus")).Return(nil)
 	betRepo := repositories.NewInMemoryBetRepository()
 
 	// Create player repository and add players to it
@@ -705,7 +839,7 @@ func TestGameService_PlayerChangesDisplayNameMidGame(t *testing.T) {
 	// Phase 1: Player places initial bet with original name
 	t.Run("Player places bet with original name", func(t *testing.T) {
 		bet1 := models.NewBet(match1, 2, 1) // Betting Team1 wins 2-1
-		err := service.UpdatePlayerBet(player1, bet1, matchTime.Add(-1*time.Hour))
+		err := service.UpdatePlayerBet(context.Background(), player1, bet1, matchTime.Add(-1*time.Hour))
 		require.NoError(t, err)
 
 		// Verify bet was saved
@@ -759,7 +893,7 @@ func TestGameService_PlayerChangesDisplayNameMidGame(t *testing.T) {
 	// Phase 3: Player continues to place bets with new name
 	t.Run("Player places more bets with new name", func(t *testing.T) {
 		bet2 := models.NewBet(match2, 1, 0) // Betting Team3 wins 1-0
-		err := service.UpdatePlayerBet(player1, bet2, matchTime.Add(1*time.Hour))
+		err := service.UpdatePlayerBet(context.Background(), player1, bet2, matchTime.Add(1*time.Hour))
 		require.NoError(t, err)
 
 		// Verify both bets are saved for the same player
@@ -788,9 +922,9 @@ func TestGameService_PlayerChangesDisplayNameMidGame(t *testing.T) {
 		// Player2 places losing bets
 		wrongBet1 := models.NewBet(match1, 0, 3) // Wrong prediction
 		wrongBet2 := models.NewBet(match2, 2, 2) // Wrong prediction
-		err := service.UpdatePlayerBet(player2, wrongBet1, matchTime.Add(-30*time.Minute))
+		err := service.UpdatePlayerBet(context.Background(), player2, wrongBet1, matchTime.Add(-30*time.Minute))
 		require.NoError(t, err)
-		err = service.UpdatePlayerBet(player2, wrongBet2, matchTime.Add(1*time.Hour))
+		err = service.UpdatePlayerBet(context.Background(), player2, wrongBet2, matchTime.Add(1*time.Hour))
 		require.NoError(t, err)
 
 		// Finish matches with results that match player1's predictions
@@ -877,6 +1011,7 @@ func TestGameService_HandleMatchUpdates_SavesFinishedGameToDatabase(t *testing.T
 	game := rules.NewFreshGame("2024", "Premier League", "Test Game", players, []models.Match{match}, &scorerMock{})
 	mockGameRepo.On("GetGame", "test-game").Return(game, nil)
 	mockGameRepo.On("SaveWithId", "test-game", mock.AnythingOfType("*rules.GameImpl")).Return(nil)
+	mockGameRepo.On("SaveGameStatus", "test-game", mock.AnythingOfType("models.GameStatus")).Return(nil)
 
 	// Create service
 	service := NewGameService("test-game", mockGameRepo, betRepo, repositories.NewInMemoryGamePlayerRepository(repositories.NewInMemoryPlayerRepository()))
@@ -884,9 +1019,9 @@ func TestGameService_HandleMatchUpdates_SavesFinishedGameToDatabase(t *testing.T
 	// Add bets for both players
 	bet1 := models.NewBet(match, 2, 1)
 	bet2 := models.NewBet(match, 1, 2)
-	err := service.UpdatePlayerBet(player1, bet1, matchTime)
+	err := service.UpdatePlayerBet(context.Background(), player1, bet1, matchTime)
 	require.NoError(t, err)
-	err = service.UpdatePlayerBet(player2, bet2, matchTime)
+	err = service.UpdatePlayerBet(context.Background(), player2, bet2, matchTime)
 	require.NoError(t, err)
 
 	// Create finished match update
@@ -929,6 +1064,7 @@ func TestGameService_HandleMatchUpdates_SaveFinishedGameError(t *testing.T) {
 	game := rules.NewFreshGame("2024", "Premier League", "Test Game", players, []models.Match{match}, &scorerMock{})
 	mockGameRepo.On("GetGame", "test-game").Return(game, nil)
 	mockGameRepo.On("SaveWithId", "test-game", mock.AnythingOfType("*rules.GameImpl")).Return(nil)
+	mockGameRepo.On("SaveGameStatus", "test-game", mock.AnythingOfType("models.GameStatus")).Return(nil)
 
 	// Create service
 	service := NewGameService("test-game", mockGameRepo, betRepo, repositories.NewInMemoryGamePlayerRepository(repositories.NewInMemoryPlayerRepository()))
@@ -936,9 +1072,9 @@ func TestGameService_HandleMatchUpdates_SaveFinishedGameError(t *testing.T) {
 	// Add bets for both players
 	bet1 := models.NewBet(match, 2, 1)
 	bet2 := models.NewBet(match, 1, 2)
-	err := service.UpdatePlayerBet(player1, bet1, matchTime)
+	err := service.UpdatePlayerBet(context.Background(), player1, bet1, matchTime)
 	require.NoError(t, err)
-	err = service.UpdatePlayerBet(player2, bet2, matchTime)
+	err = service.UpdatePlayerBet(context.Background(), player2, bet2, matchTime)
 	require.NoError(t, err)
 
 	// Create finished match update
@@ -951,6 +1087,7 @@ func TestGameService_HandleMatchUpdates_SaveFinishedGameError(t *testing.T) {
 	mockGameRepo.ExpectedCalls = nil
 	mockGameRepo.On("GetGame", "test-game").Return(game, nil)
 	mockGameRepo.On("SaveWithId", "test-game", mock.AnythingOfType("*rules.GameImpl")).Return(fmt.Errorf("database error"))
+	mockGameRepo.On("SaveGameStatus", "test-game", mock.AnythingOfType("models.GameStatus")).Return(nil)
 
 	// Handle the updates - should return an error
 	err = service.HandleMatchUpdates(updates)
@@ -1000,7 +1137,8 @@ func TestBug_PlayersOnlyGetPointsForDraws_HandleMatchUpdates(t *testing.T) {
 	lastMatchState.AwayGoals = 0
 
 	// Simulate the adjustOdds call that happens in HandleMatchUpdates
-	adjustedMatch := service.adjustOdds(finishedMatch, lastMatchState)
+	game := rules.NewFreshGame("2024/2025", "Ligue 1", "Test Game", nil, []models.Match{lastMatchState}, &rules.ScorerOriginal{})
+	adjustedMatch := service.adjustOdds(game, finishedMatch, lastMatchState)
 
 	// Now test the scoring logic with the adjusted match
 	bets := []*models.Bet{
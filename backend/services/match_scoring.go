@@ -0,0 +1,87 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"ligain/backend/models"
+	"ligain/backend/repositories"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// ScoreReconciliation is the outcome of checking the points of a game against the scores saved in the database
+type ScoreReconciliation struct {
+	// Scored are the past matches which had never been scored, with or without bets, and which were scored by the check
+	Scored []string
+	// Mismatched tells whether the points of the cached game differed from the saved scores, the game is then loaded again
+	Mismatched bool
+}
+
+// gameLocks serialize the changes made to each game by this instance with the recording of their events. The cached
+// game is shared by the requests, so a request recording its changes must not take the events of another one
+var gameLocks sync.Map
+
+// lockGame locks the changes to a game, until the returned function is called
+func lockGame(gameID string) func() {
+	mu, _ := gameLocks.LoadOrStore(gameID, &sync.Mutex{})
+	mu.(*sync.Mutex).Lock()
+	return mu.(*sync.Mutex).Unlock
+}
+
+// matchScorer holds what's needed to apply a scoring of a match to a game
+type matchScorer struct {
+	uow      repositories.UnitOfWork
+	gameRepo repositories.GameRepository
+	betRepo  repositories.BetRepository
+}
+
+// apply saves a scoring of a match with the scores of the players, the status of the game and its events, in a single transaction.
+// score updates the game and returns the scores to save: it's only called once the scoring is claimed, so that
+// a scoring already applied isn't applied again. Returns false in that case.
+// The cached game is evicted whenever it may not match the database anymore
+func (s *matchScorer) apply(ctx context.Context, game models.Game, scoring *models.MatchScoring, match models.Match, score func() (map[string]int, error)) (bool, error) {
+	applied := false
+	err := s.uow.WithinTx(ctx, func(txCtx context.Context) error {
+		claimed, err := s.betRepo.ClaimScoring(txCtx, scoring)
+		if err != nil || !claimed {
+			return err
+		}
+
+		// The game is updated first, if saving fails the tx rolls back and the game is evicted
+		scores, err := score()
+		if err != nil {
+			return err
+		}
+		if err := s.betRepo.SaveScores(txCtx, scoring.GameID, match, game.GetPlayers(), scores); err != nil {
+			return fmt.Errorf("error saving scores: %v", err)
+		}
+		if err := s.gameRepo.SaveGameStatus(txCtx, scoring.GameID, game.GetGameStatus()); err != nil {
+			return err
+		}
+		if recorder, ok := s.gameRepo.(repositories.GameHistoryRecorder); ok {
+			if err := recorder.RecordEvents(txCtx, scoring.GameID, game); err != nil {
+				return err
+			}
+		}
+		applied = true
+		return nil
+	})
+	if err != nil {
+		s.evictGame(scoring.GameID)
+		return false, err
+	}
+	if !applied {
+		log.Infof("Scoring %d of match %s was already applied to game %s", scoring.Version, scoring.MatchID, scoring.GameID)
+		s.evictGame(scoring.GameID)
+		return false, nil
+	}
+	return true, nil
+}
+
+// evictGame drops the cached game, so that it's loaded again from the database
+func (s *matchScorer) evictGame(gameID string) {
+	if evicter, ok := s.gameRepo.(repositories.GameCacheEvicter); ok {
+		evicter.EvictGame(gameID)
+	}
+}
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"context"
+	"ligain/backend/models"
+	"ligain/backend/repositories"
+	"ligain/backend/rules"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchScorer_ApplyIsIdempotent(t *testing.T) {
+	service, match, players := setupTestGameService()
+	require.NoError(t, service.UpdatePlayerBet(context.Background(), players[0], models.NewBet(match, 2, 1), matchTime.Add(-1*time.Hour)))
+	game, err := service.getGame()
+	require.NoError(t, err)
+
+	finishedMatch := models.NewFinishedSeasonMatch("Team1", "Team2", 2, 1, "2024", "Premier League", matchTime, 1, 1.0, 2.0, 3.0)
+	scores, err := game.CalculateMatchScores(finishedMatch)
+	require.NoError(t, err)
+
+	calls := 0
+	score := func() (map[string]int, error) {
+		calls++
+		game.ApplyMatchScores(finishedMatch, scores)
+		return scores, nil
+	}
+	scoring := models.NewMatchScoring("test-game", match.Id(), models.InitialScoringVersion, matchTime)
+
+	applied, err := service.scoring.apply(context.Background(), game, scoring, finishedMatch, score)
+	require.NoError(t, err)
+	assert.True(t, applied)
+
+	// Replaying the same scoring doesn't score the match again
+	applied, err = service.scoring.apply(context.Background(), game, scoring, finishedMatch, score)
+	require.NoError(t, err)
+	assert.False(t, applied)
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, 500, game.GetPlayersPoints()[players[0].GetID()])
+}
+
+func TestGameService_ReconcileScores(t *testing.T) {
+	service, match, players := setupTestGameService()
+	player1 := players[0]
+	require.NoError(t, service.UpdatePlayerBet(context.Background(), player1, models.NewBet(match, 2, 1), matchTime.Add(-1*time.Hour)))
+	ctx := context.Background()
+
+	// The game was updated, but the instance stopped before the scores were saved
+	game, err := service.getGame()
+	require.NoError(t, err)
+	finishedMatch := models.NewFinishedSeasonMatch("Team1", "Team2", 2, 1, "2024", "Premier League", matchTime, 1, 1.0, 2.0, 3.0)
+	scores, err := game.CalculateMatchScores(finishedMatch)
+	require.NoError(t, err)
+	game.ApplyMatchScores(finishedMatch, scores)
+
+	reconciliation, err := service.ReconcileScores(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{match.Id()}, reconciliation.Scored)
+	assert.False(t, reconciliation.Mismatched)
+
+	saved, err := service.betRepo.GetScoresByMatchAndPlayer("test-game")
+	require.NoError(t, err)
+	assert.Equal(t, 500, saved[match.Id()][player1.GetID()])
+	versions, err := service.betRepo.GetScoringVersions(ctx, "test-game")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{match.Id(): models.InitialScoringVersion}, versions)
+
+	// Checking again finds nothing to do
+	reconciliation, err = service.ReconcileScores(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, reconciliation.Scored)
+	assert.False(t, reconciliation.Mismatched)
+
+	// The saved scores changed behind the cached game
+	require.NoError(t, service.betRepo.SaveScore("test-game", finishedMatch, player1, 100))
+	reconciliation, err = service.ReconcileScores(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, reconciliation.Scored)
+	assert.True(t, reconciliation.Mismatched)
+}
+
+func TestGameService_ReconcileScoresWithoutBets(t *testing.T) {
+	players := []models.Player{newTestPlayer("Player1"), newTestPlayer("Player2")}
+	beforeGame := models.NewFinishedSeasonMatch("Team5", "Team6", 0, 0, "2024", "Premier League", matchTime.Add(-7*24*time.Hour), 1, 1.0, 2.0, 3.0)
+	betOn := models.NewFinishedSeasonMatch("Team1", "Team2", 2, 1, "2024", "Premier League", matchTime, 2, 1.0, 2.0, 3.0)
+	withoutBets := models.NewFinishedSeasonMatch("Team3", "Team4", 1, 1, "2024", "Premier League", matchTime.Add(24*time.Hour), 2, 1.0, 2.0, 3.0)
+	incoming := newTestSeasonMatchWithOdds("Team7", "Team8", matchTime.Add(7*24*time.Hour), 3)
+	bets := map[string]map[string]*models.Bet{
+		betOn.Id(): {players[0].GetID(): models.NewBet(betOn, 2, 1)},
+	}
+	// The instance stopped before scoring the matches
+	game := rules.NewStartedGame("2024", "Premier League", "Test Game", players,
+		[]models.Match{incoming}, []models.Match{beforeGame, betOn, withoutBets},
+		&scorerMock{}, bets, make(map[string]map[string]int))
+	gameRepo := &gameRepositoryMock{}
+	gameRepo.On("GetGame", "test-game").Return(game, nil)
+	gameRepo.On("SaveWithId", "test-game", mock.AnythingOfType("*rules.GameImpl")).Return(nil)
+	gameRepo.On("SaveGameStatus", "test-game", mock.AnythingOfType("models.GameStatus")).Return(nil)
+	service := NewGameService("test-game", gameRepo, repositories.NewInMemoryBetRepository(), nil)
+	ctx := context.Background()
+
+	reconciliation, err := service.ReconcileScores(ctx)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{betOn.Id(), withoutBets.Id()}, reconciliation.Scored)
+	assert.False(t, reconciliation.Mismatched)
+
+	// The players without a bet get the penalty, the match which finished before the game isn't scored
+	saved, err := service.betRepo.GetScoresByMatchAndPlayer("test-game")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{players[0].GetID(): 500, players[1].GetID(): -100}, saved[betOn.Id()])
+	assert.Equal(t, map[string]int{players[0].GetID(): -100, players[1].GetID(): -100}, saved[withoutBets.Id()])
+	assert.NotContains(t, saved, beforeGame.Id())
+	versions, err := service.betRepo.GetScoringVersions(ctx, "test-game")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{betOn.Id(): models.InitialScoringVersion, withoutBets.Id(): models.InitialScoringVersion}, versions)
+}
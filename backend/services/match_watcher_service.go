@@ -14,6 +14,9 @@ type MatchWatcherService interface {
 	Subscribe(handler GameService) error
 	// Unsubscribe removes a game service from receiving updates
 	Unsubscribe(gameID string) error
+	// Unwatch stops following a match, like when its result is forced. The instances not watching it
+	// themselves leave it once they see it saved as finished
+	Unwatch(matchID string) error
 	// Start begins the background polling and notification process
 	Start(ctx context.Context) error
 	// Stop stops the background polling process
@@ -16,13 +16,18 @@ import (
 
 type MatchWatcherServiceSportsmonk struct {
 	watchedMatches map[string]models.Match
-	repo           repositories.SportsmonkRepository
-	matchRepo      repositories.MatchRepository
-	subscribers    map[string]GameService
-	stopChan       chan struct{}
-	isRunning      bool
-	pollInterval   time.Duration
-	now            func() time.Time
+	// watchedMu guards watchedMatches, which the admin requests change too
+	watchedMu    sync.Mutex
+	repo         repositories.SportsmonkRepository
+	matchRepo    repositories.MatchRepository
+	subscribers  map[string]GameService
+	stopChan     chan struct{}
+	isRunning    bool
+	pollInterval time.Duration
+	now          func() time.Time
+	// leaderLock elects the single instance polling the matches, every instance polls when it's nil
+	leaderLock repositories.LeaderLock
+	lease      repositories.Lease
 }
 
 var (
@@ -78,6 +83,13 @@ func NewMatchWatcherServiceSportsmonkWithOptions(
 	}
 }
 
+// SetLeaderLock makes the instances sharing the lock elect a leader, the only one polling and saving the
+// match updates. The others get the resulting game changes through the database, and one of them takes
+// over when the leader is gone. It must be called before Start
+func (m *MatchWatcherServiceSportsmonk) SetLeaderLock(lock repositories.LeaderLock) {
+	m.leaderLock = lock
+}
+
 func (m *MatchWatcherServiceSportsmonk) Subscribe(handler GameService) error {
 	gameID := handler.GetGameID()
 	m.subscribers[gameID] = handler
@@ -96,6 +108,19 @@ func (m *MatchWatcherServiceSportsmonk) Unsubscribe(gameID string) error {
 	return nil
 }
 
+// Unwatch implements MatchWatcherService
+func (m *MatchWatcherServiceSportsmonk) Unwatch(matchID string) error {
+	m.watchedMu.Lock()
+	defer m.watchedMu.Unlock()
+	if _, exists := m.watchedMatches[matchID]; !exists {
+		return nil
+	}
+
+	delete(m.watchedMatches, matchID)
+	log.Infof("Match %s is no longer watched", matchID)
+	return nil
+}
+
 func (m *MatchWatcherServiceSportsmonk) Start(ctx context.Context) error {
 	log.Infof("Starting match watcher service")
 	if m.isRunning {
@@ -124,6 +149,7 @@ func (m *MatchWatcherServiceSportsmonk) Stop() error {
 func (m *MatchWatcherServiceSportsmonk) pollLoop(ctx context.Context) {
 	ticker := time.NewTicker(m.pollInterval)
 	defer ticker.Stop()
+	defer m.resign()
 
 	for {
 		select {
@@ -134,9 +160,71 @@ func (m *MatchWatcherServiceSportsmonk) pollLoop(ctx context.Context) {
 			log.Info("Match watcher stop signal received, stopping poll loop")
 			return
 		case <-ticker.C:
-			m.checkForUpdates()
+			if m.lead(ctx) {
+				m.checkForUpdates()
+			}
+		}
+	}
+}
+
+// lead tells whether this instance is the leader, trying to become it when no instance is
+func (m *MatchWatcherServiceSportsmonk) lead(ctx context.Context) bool {
+	if m.leaderLock == nil {
+		return true
+	}
+	if m.lease != nil {
+		err := m.lease.Check(ctx)
+		if err == nil {
+			return true
+		}
+		log.Warnf("Match watcher is no longer the leader: %v", err)
+		m.resign()
+	}
+
+	lease, acquired, err := m.leaderLock.TryAcquire(ctx)
+	if err != nil {
+		log.Errorf("Error electing the match watcher leader: %v", err)
+		return false
+	}
+	if !acquired {
+		return false
+	}
+	m.lease = lease
+	log.Info("Match watcher is the leader, polling the matches")
+
+	// The previous leader may have saved updates this instance didn't see
+	if err := m.reloadWatchedMatches(); err != nil {
+		log.Errorf("Error reloading the watched matches: %v", err)
+		m.resign()
+		return false
+	}
+	return true
+}
+
+func (m *MatchWatcherServiceSportsmonk) resign() {
+	if m.lease == nil {
+		return
+	}
+	m.lease.Release()
+	m.lease = nil
+}
+
+// reloadWatchedMatches replaces the watched matches with their last state saved in the database. The matches
+// saved as finished are kept as they were, so their saved result is sent again at the next poll: the previous
+// leader may have died before every game scored them, and the games which did ignore it
+func (m *MatchWatcherServiceSportsmonk) reloadWatchedMatches() error {
+	saved, err := m.matchRepo.GetMatches()
+	if err != nil {
+		return err
+	}
+	m.watchedMu.Lock()
+	defer m.watchedMu.Unlock()
+	for id := range m.watchedMatches {
+		if match, ok := saved[id]; ok && !match.IsFinished() {
+			m.watchedMatches[id] = match
 		}
 	}
+	return nil
 }
 
 func (m *MatchWatcherServiceSportsmonk) checkForUpdates() {
@@ -164,22 +252,49 @@ func (m *MatchWatcherServiceSportsmonk) checkForUpdates() {
 	}
 }
 
+// getMatchesUpdates returns the matches whose state changed since the last poll. A watched match already saved
+// as finished, like when its result was forced from another instance, is updated with its saved result instead of
+// the one of the provider, and isn't watched anymore
 func (m *MatchWatcherServiceSportsmonk) getMatchesUpdates() (map[string]models.Match, error) {
 	updates := make(map[string]models.Match)
 	twoWeeksFromNow := m.now().Add(14 * 24 * time.Hour)
 	matchesToQuery := make(map[string]models.Match)
+	m.watchedMu.Lock()
 	for id, match := range m.watchedMatches {
 		if !match.GetDate().After(twoWeeksFromNow) {
 			matchesToQuery[id] = match
 		}
 	}
-	log.Infof("Getting last match infos for %d matches (out of %d watched, excluding matches scheduled beyond 2 weeks in the future)", len(matchesToQuery), len(m.watchedMatches))
+	watchedCount := len(m.watchedMatches)
+	m.watchedMu.Unlock()
+	log.Infof("Getting last match infos for %d matches (out of %d watched, excluding matches scheduled beyond 2 weeks in the future)", len(matchesToQuery), watchedCount)
 	lastMatchInfos, err := m.repo.GetLastMatchInfos(matchesToQuery)
 	if err != nil {
 		return nil, err
 	}
+	saved, err := m.matchRepo.GetMatches()
+	if err != nil {
+		return nil, err
+	}
+
+	m.watchedMu.Lock()
+	defer m.watchedMu.Unlock()
+	for matchId, lastMatchState := range m.watchedMatches {
+		if savedMatch, ok := saved[matchId]; ok && savedMatch.IsFinished() {
+			log.Infof("Match %s was saved as finished with result %d - %d, it's no longer watched", matchId, savedMatch.GetHomeGoals(), savedMatch.GetAwayGoals())
+			if !lastMatchState.IsFinished() || matchWasUpdated(savedMatch, lastMatchState) {
+				updates[matchId] = savedMatch
+			}
+			delete(m.watchedMatches, matchId)
+		}
+	}
 	for matchId, match := range lastMatchInfos {
-		if matchWasUpdated(match, m.watchedMatches[matchId]) {
+		lastMatchState, watched := m.watchedMatches[matchId]
+		// Unwatched meanwhile, like when its result was forced
+		if !watched {
+			continue
+		}
+		if matchWasUpdated(match, lastMatchState) {
 			updates[matchId] = match
 			m.watchedMatches[matchId] = match
 			if match.IsFinished() {
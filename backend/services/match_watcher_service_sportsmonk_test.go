@@ -54,7 +54,15 @@ func (m *MockGameService) GetMatchResults() map[string]*models.MatchResult {
 	return make(map[string]*models.MatchResult)
 }
 
-func (m *MockGameService) UpdatePlayerBet(player models.Player, bet *models.Bet, now time.Time) error {
+func (m *MockGameService) GetProvisionalLeaderboard() (*models.ProvisionalLeaderboard, error) {
+	return models.NewProvisionalLeaderboard(nil, nil), nil
+}
+
+func (m *MockGameService) GetStandings() ([]models.Standing, error) {
+	return []models.Standing{}, nil
+}
+
+func (m *MockGameService) UpdatePlayerBet(ctx context.Context, player models.Player, bet *models.Bet, now time.Time) error {
 	return nil
 }
 
@@ -509,3 +517,105 @@ func TestMatchWatcherService_DetectsRescheduleOfPastMatch(t *testing.T) {
 	assert.Equal(t, rescheduledDate, updatedMatch.GetDate())
 	assert.Equal(t, rescheduledDate, service.watchedMatches[pastMatch.Id()].GetDate())
 }
+
+func TestMatchWatcherService_LeaderElection(t *testing.T) {
+	ctx := context.Background()
+	matchTime := time.Date(2024, 1, 10, 15, 0, 0, 0, time.UTC)
+	scheduled := models.NewSeasonMatch("Team1", "Team2", "2024", "Premier League", matchTime, 1)
+	toFinish := models.NewSeasonMatch("Team3", "Team4", "2024", "Premier League", matchTime, 1)
+
+	matchRepo := repositories.NewInMemoryMatchRepository()
+	lock := repositories.NewInMemoryLeaderLock()
+	newReplica := func() *MatchWatcherServiceSportsmonk {
+		return &MatchWatcherServiceSportsmonk{
+			watchedMatches: map[string]models.Match{scheduled.Id(): scheduled, toFinish.Id(): toFinish},
+			repo:           &SportsmonkRepositoryMock{},
+			subscribers:    make(map[string]GameService),
+			stopChan:       make(chan struct{}),
+			pollInterval:   30 * time.Second,
+			matchRepo:      matchRepo,
+			now:            func() time.Time { return time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC) },
+			leaderLock:     lock,
+		}
+	}
+	leader := newReplica()
+	follower := newReplica()
+
+	require.True(t, leader.lead(ctx))
+	require.False(t, follower.lead(ctx))
+	require.True(t, leader.lead(ctx))
+
+	// The leader saves updates the follower doesn't see, then loses its connection
+	withOdds := models.NewSeasonMatchWithKnownOdds("Team1", "Team2", "2024", "Premier League", matchTime, 1, 1.5, 3.0, 2.5)
+	finished := models.NewFinishedSeasonMatch("Team3", "Team4", 1, 0, "2024", "Premier League", matchTime, 1, 1.5, 3.0, 2.5)
+	require.NoError(t, matchRepo.SaveMatch(withOdds))
+	require.NoError(t, matchRepo.SaveMatch(finished))
+	lock.Revoke()
+
+	require.True(t, follower.lead(ctx))
+	assert.False(t, leader.lead(ctx))
+	assert.Equal(t, withOdds, follower.watchedMatches[scheduled.Id()])
+	// The result is sent again, in case the previous leader died before every game scored it
+	assert.Equal(t, toFinish, follower.watchedMatches[toFinish.Id()])
+
+	// Stepping down lets another instance lead
+	follower.resign()
+	assert.True(t, leader.lead(ctx))
+	assert.False(t, follower.lead(ctx))
+}
+
+func TestMatchWatcherService_ForcedResultIsNotOverwritten(t *testing.T) {
+	matchTime := time.Date(2024, 1, 10, 15, 0, 0, 0, time.UTC)
+	forcedElsewhere := models.NewSeasonMatch("Team1", "Team2", "2024", "Premier League", matchTime, 1)
+	forcedHere := models.NewSeasonMatch("Team3", "Team4", "2024", "Premier League", matchTime, 1)
+
+	// The provider still reports the results the admins corrected
+	mockRepo := &SportsmonkRepositoryMock{
+		lastMatchInfos: []map[string]models.Match{
+			{
+				forcedElsewhere.Id(): models.NewFinishedSeasonMatch("Team1", "Team2", 3, 0, "2024", "Premier League", matchTime, 1, 1.5, 3.0, 2.5),
+				forcedHere.Id():      models.NewFinishedSeasonMatch("Team3", "Team4", 3, 0, "2024", "Premier League", matchTime, 1, 1.5, 3.0, 2.5),
+			},
+		},
+	}
+	matchRepo := repositories.NewInMemoryMatchRepository()
+	service := &MatchWatcherServiceSportsmonk{
+		watchedMatches: map[string]models.Match{forcedElsewhere.Id(): forcedElsewhere, forcedHere.Id(): forcedHere},
+		repo:           mockRepo,
+		subscribers:    make(map[string]GameService),
+		stopChan:       make(chan struct{}),
+		pollInterval:   30 * time.Second,
+		matchRepo:      matchRepo,
+		now:            func() time.Time { return time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC) },
+	}
+
+	// One result is forced from another instance, which only saves it, the other one from this instance
+	forcedResult := models.NewFinishedSeasonMatch("Team1", "Team2", 1, 1, "2024", "Premier League", matchTime, 1, 1.5, 3.0, 2.5)
+	require.NoError(t, matchRepo.SaveMatch(forcedResult))
+	forcedHereResult := models.NewFinishedSeasonMatch("Team3", "Team4", 0, 2, "2024", "Premier League", matchTime, 1, 1.5, 3.0, 2.5)
+	require.NoError(t, matchRepo.SaveMatch(forcedHereResult))
+	require.NoError(t, service.Unwatch(forcedHere.Id()))
+
+	handler := NewMockGameService("game1")
+	require.NoError(t, service.Subscribe(handler))
+	service.checkForUpdates()
+	time.Sleep(10 * time.Millisecond)
+
+	// The games get the forced result, and the saved one isn't overwritten by the one of the provider
+	require.Len(t, handler.updates, 1)
+	assert.Equal(t, map[string]models.Match{forcedElsewhere.Id(): forcedResult}, handler.updates[0])
+	assert.NotContains(t, mockRepo.receivedMatches[0], forcedHere.Id())
+	assert.Empty(t, service.watchedMatches)
+	for _, forced := range []models.Match{forcedResult, forcedHereResult} {
+		saved, err := matchRepo.GetMatch(forced.Id())
+		require.NoError(t, err)
+		assert.Equal(t, forced.GetHomeGoals(), saved.GetHomeGoals())
+		assert.Equal(t, forced.GetAwayGoals(), saved.GetAwayGoals())
+	}
+
+	// The next poll doesn't query them anymore
+	updates, err := service.getMatchesUpdates()
+	require.NoError(t, err)
+	assert.Empty(t, updates)
+	assert.Empty(t, mockRepo.receivedMatches[1])
+}
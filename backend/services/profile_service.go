@@ -42,6 +42,11 @@ type ProfileService interface {
 
 	// GetPlayerProfile retrieves a player's profile with refreshed signed URL if needed
 	GetPlayerProfile(ctx context.Context, playerID string) (*models.PlayerData, error)
+
+	// RefreshExpiringAvatarURLs refreshes the signed URLs about to expire, so that the avatars shown
+	// in the games stay valid even when their players don't open their profile
+	// Returns the number of URLs refreshed
+	RefreshExpiringAvatarURLs(ctx context.Context) (int, error)
 }
 
 // ProfileServiceImpl implements ProfileService
@@ -165,6 +170,24 @@ func (s *ProfileServiceImpl) GetPlayerProfile(ctx context.Context, playerID stri
 	return player, nil
 }
 
+// RefreshExpiringAvatarURLs implements ProfileService.RefreshExpiringAvatarURLs
+func (s *ProfileServiceImpl) RefreshExpiringAvatarURLs(ctx context.Context) (int, error) {
+	players, err := s.playerRepository.GetPlayersWithAvatarURLExpiringBefore(ctx, time.Now().Add(signedURLRefreshThreshold))
+	if err != nil {
+		return 0, err
+	}
+
+	refreshed := 0
+	for _, player := range players {
+		if _, _, err := s.refreshSignedURL(ctx, player); err != nil {
+			log.Warnf("RefreshExpiringAvatarURLs - Failed to refresh signed URL for player %s: %v", player.ID, err)
+			continue
+		}
+		refreshed++
+	}
+	return refreshed, nil
+}
+
 // needsSignedURLRefresh checks if the signed URL needs to be refreshed
 func (s *ProfileServiceImpl) needsSignedURLRefresh(player *models.PlayerData) bool {
 	if player.AvatarSignedURLExpiresAt == nil {
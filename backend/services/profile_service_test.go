@@ -194,6 +194,16 @@ func (m *MockPlayerRepoForProfile) ClearAvatar(ctx context.Context, playerID str
 	return nil
 }
 
+func (m *MockPlayerRepoForProfile) GetPlayersWithAvatarURLExpiringBefore(ctx context.Context, before time.Time) ([]*models.PlayerData, error) {
+	players := make([]*models.PlayerData, 0)
+	for _, player := range m.players {
+		if player.AvatarObjectKey != nil && (player.AvatarSignedURLExpiresAt == nil || player.AvatarSignedURLExpiresAt.Before(before)) {
+			players = append(players, player)
+		}
+	}
+	return players, nil
+}
+
 // Tests for ProfileService
 
 func TestProfileService_UploadAvatar_Success(t *testing.T) {
@@ -607,3 +617,54 @@ func TestProfileService_GetPlayerProfile_NoAvatar(t *testing.T) {
 	require.NotNil(t, player)
 	assert.Nil(t, player.AvatarSignedURL)
 }
+
+func TestProfileService_RefreshExpiringAvatarURLs(t *testing.T) {
+	storageService := NewMockStorageServiceForProfile()
+	storageService.GeneratedURL = "https://storage.example.com/new-signed-url"
+	playerRepo := NewMockPlayerRepoForProfile()
+
+	avatarKey := "avatars/player-1/test.webp"
+	oldURL := "https://storage.example.com/old-signed-url"
+	expiringAt := time.Now().Add(12 * time.Hour)
+	freshAt := time.Now().Add(6 * 24 * time.Hour)
+	playerRepo.players["expiring"] = &models.PlayerData{
+		ID:                       "expiring",
+		AvatarObjectKey:          &avatarKey,
+		AvatarSignedURL:          &oldURL,
+		AvatarSignedURLExpiresAt: &expiringAt,
+	}
+	playerRepo.players["fresh"] = &models.PlayerData{
+		ID:                       "fresh",
+		AvatarObjectKey:          &avatarKey,
+		AvatarSignedURL:          &oldURL,
+		AvatarSignedURLExpiresAt: &freshAt,
+	}
+	playerRepo.players["no-avatar"] = &models.PlayerData{ID: "no-avatar"}
+
+	service := NewProfileService(storageService, NewMockImageProcessorForProfile(), playerRepo)
+
+	refreshed, err := service.RefreshExpiringAvatarURLs(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, refreshed)
+	assert.Equal(t, "https://storage.example.com/new-signed-url", *playerRepo.players["expiring"].AvatarSignedURL)
+	assert.True(t, playerRepo.players["expiring"].AvatarSignedURLExpiresAt.After(freshAt))
+	assert.Equal(t, oldURL, *playerRepo.players["fresh"].AvatarSignedURL)
+	assert.Nil(t, playerRepo.players["no-avatar"].AvatarSignedURL)
+}
+
+func TestProfileService_RefreshExpiringAvatarURLs_StorageErrorSkipsPlayer(t *testing.T) {
+	storageService := NewMockStorageServiceForProfile()
+	storageService.SignedURLError = errors.New("storage unavailable")
+	playerRepo := NewMockPlayerRepoForProfile()
+
+	avatarKey := "avatars/player-1/test.webp"
+	playerRepo.players["player-1"] = &models.PlayerData{ID: "player-1", AvatarObjectKey: &avatarKey}
+
+	service := NewProfileService(storageService, NewMockImageProcessorForProfile(), playerRepo)
+
+	refreshed, err := service.RefreshExpiringAvatarURLs(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, 0, refreshed)
+}
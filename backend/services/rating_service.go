@@ -0,0 +1,167 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"ligain/backend/models"
+	"ligain/backend/repositories"
+	"ligain/backend/rules"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultGlobalLeaderboardSize is the number of players returned by the global leaderboard by default
+	DefaultGlobalLeaderboardSize = 50
+	// MaxGlobalLeaderboardSize is the maximum number of players the global leaderboard can return
+	MaxGlobalLeaderboardSize = 200
+)
+
+// RatingService updates the cross-game skill rating of players after each scored match
+type RatingService interface {
+	ScoreObserver
+	// GetPlayerRating returns the rating of a player, or nil if they have never been rated
+	GetPlayerRating(ctx context.Context, playerID string) (*models.PlayerRating, error)
+	// GetGlobalLeaderboard returns the best rated players across every game, best first
+	GetGlobalLeaderboard(ctx context.Context, limit int) ([]*models.PlayerRating, error)
+}
+
+// RatingServiceImpl implements RatingService
+type RatingServiceImpl struct {
+	ratingRepo repositories.RatingRepository
+	timeFunc   func() time.Time
+	// mu serializes rating updates, since the same players can be rated by several games at once
+	mu sync.Mutex
+}
+
+// NewRatingService creates a new RatingService instance
+func NewRatingService(ratingRepo repositories.RatingRepository) *RatingServiceImpl {
+	return NewRatingServiceWithTimeFunc(ratingRepo, time.Now)
+}
+
+// NewRatingServiceWithTimeFunc creates a RatingService with a custom time function (for testing)
+func NewRatingServiceWithTimeFunc(ratingRepo repositories.RatingRepository, timeFunc func() time.Time) *RatingServiceImpl {
+	return &RatingServiceImpl{
+		ratingRepo: ratingRepo,
+		timeFunc:   timeFunc,
+	}
+}
+
+// OnMatchScored implements ScoreObserver by updating the ratings of the players of the game.
+// The changes of a previous scoring of the match are reverted first, and each player counts the match once
+func (s *RatingServiceImpl) OnMatchScored(gameID string, game models.Game, match models.Match, scoring *models.MatchScoring) error {
+	result, exists := game.GetPastResults()[match.Id()]
+	if !exists {
+		return fmt.Errorf("match %s has no result in game %s", match.Id(), gameID)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ctx := context.Background()
+	previous, err := s.ratingRepo.GetMatchRatingChanges(ctx, gameID, match.Id())
+	if err != nil {
+		return fmt.Errorf("error getting rating changes: %v", err)
+	}
+	for _, change := range previous {
+		if change.Version >= scoring.Version {
+			return nil
+		}
+	}
+	// A match rated before the changes were recorded can't be reverted, it keeps its rating
+	if len(previous) == 0 && scoring.Version > models.InitialScoringVersion {
+		log.Warnf("Match %s of game %s has no rating changes to revert, its ratings are left as they were", match.Id(), gameID)
+		return nil
+	}
+	if len(previous) == 0 && len(result.Scores) < 2 {
+		return nil
+	}
+
+	playerNames := make(map[string]string)
+	for _, player := range game.GetPlayers() {
+		playerNames[player.GetID()] = player.GetName()
+	}
+	playerIDs := make([]string, 0, len(result.Scores)+len(previous))
+	for playerID := range result.Scores {
+		playerIDs = append(playerIDs, playerID)
+	}
+	for playerID := range previous {
+		if _, scored := result.Scores[playerID]; !scored {
+			playerIDs = append(playerIDs, playerID)
+		}
+	}
+
+	existing, err := s.ratingRepo.GetRatings(ctx, playerIDs)
+	if err != nil {
+		return fmt.Errorf("error getting ratings: %v", err)
+	}
+	// The ratings as they were before the match
+	ratings := make(map[string]*models.PlayerRating)
+	for _, playerID := range playerIDs {
+		rating, exists := existing[playerID]
+		if !exists {
+			rating = models.NewPlayerRating(playerID, playerNames[playerID])
+		}
+		if change, exists := previous[playerID]; exists {
+			rating.Rating -= change.Delta
+			rating.MatchesRated--
+		}
+		ratings[playerID] = rating
+	}
+	before := make(map[string]float64)
+	for playerID, rating := range ratings {
+		before[playerID] = rating.Rating
+	}
+
+	now := s.timeFunc()
+	deltas := rules.RateMatch(before, result)
+	updated := make([]*models.PlayerRating, 0, len(ratings))
+	changes := make([]*models.RatingChange, 0, len(deltas))
+	for _, playerID := range playerIDs {
+		rating := ratings[playerID]
+		delta, rated := deltas[playerID]
+		if rated {
+			rating.Rating += delta
+			rating.MatchesRated++
+			changes = append(changes, &models.RatingChange{
+				GameID:   gameID,
+				MatchID:  match.Id(),
+				PlayerID: playerID,
+				Version:  scoring.Version,
+				Delta:    delta,
+			})
+		} else if previous[playerID] == nil {
+			continue
+		}
+		rating.UpdatedAt = now
+		updated = append(updated, rating)
+	}
+
+	if err := s.ratingRepo.SaveMatchRatings(ctx, gameID, match.Id(), updated, changes); err != nil {
+		return fmt.Errorf("error saving ratings: %v", err)
+	}
+	log.Infof("Updated the rating of %d players after scoring %d of match %s in game %s", len(updated), scoring.Version, match.Id(), gameID)
+	return nil
+}
+
+// GetPlayerRating implements RatingService.GetPlayerRating
+func (s *RatingServiceImpl) GetPlayerRating(ctx context.Context, playerID string) (*models.PlayerRating, error) {
+	ratings, err := s.ratingRepo.GetRatings(ctx, []string{playerID})
+	if err != nil {
+		return nil, err
+	}
+	return ratings[playerID], nil
+}
+
+// GetGlobalLeaderboard implements RatingService.GetGlobalLeaderboard
+func (s *RatingServiceImpl) GetGlobalLeaderboard(ctx context.Context, limit int) ([]*models.PlayerRating, error) {
+	if limit <= 0 {
+		limit = DefaultGlobalLeaderboardSize
+	}
+	if limit > MaxGlobalLeaderboardSize {
+		limit = MaxGlobalLeaderboardSize
+	}
+	return s.ratingRepo.GetTopRatings(ctx, limit)
+}
@@ -0,0 +1,77 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"ligain/backend/models"
+	"ligain/backend/repositories"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRatingService_UpdatesRatingsWhenMatchIsScored(t *testing.T) {
+	service, match, players := setupTestGameService()
+	player1 := players[0]
+	player2 := players[1]
+
+	updatedAt := time.Date(2024, 3, 16, 0, 0, 0, 0, time.UTC)
+	ratingService := NewRatingServiceWithTimeFunc(repositories.NewInMemoryRatingRepository(), func() time.Time { return updatedAt })
+	service.AddScoreObserver(ratingService)
+
+	require.NoError(t, service.UpdatePlayerBet(context.Background(), player1, models.NewBet(match, 2, 1), matchTime.Add(-1*time.Hour)))
+	require.NoError(t, service.UpdatePlayerBet(context.Background(), player2, models.NewBet(match, 0, 1), matchTime.Add(-1*time.Hour)))
+
+	finishedMatch := newTestSeasonMatchWithOdds("Team1", "Team2", matchTime, 1)
+	finishedMatch.Finish(2, 1)
+	require.NoError(t, service.HandleMatchUpdates(map[string]models.Match{match.Id(): finishedMatch}))
+
+	rating1, err := ratingService.GetPlayerRating(context.Background(), player1.GetID())
+	require.NoError(t, err)
+	require.NotNil(t, rating1)
+	assert.Greater(t, rating1.Rating, models.InitialRating)
+	assert.Equal(t, 1, rating1.MatchesRated)
+	assert.Equal(t, updatedAt, rating1.UpdatedAt)
+	assert.Equal(t, player1.GetName(), rating1.PlayerName)
+
+	rating2, err := ratingService.GetPlayerRating(context.Background(), player2.GetID())
+	require.NoError(t, err)
+	require.NotNil(t, rating2)
+	assert.Less(t, rating2.Rating, models.InitialRating)
+	assert.InDelta(t, 2*models.InitialRating, rating1.Rating+rating2.Rating, 0.001)
+
+	leaderboard, err := ratingService.GetGlobalLeaderboard(context.Background(), 0)
+	require.NoError(t, err)
+	require.Len(t, leaderboard, 2)
+	assert.Equal(t, player1.GetID(), leaderboard[0].PlayerID)
+	assert.Equal(t, player2.GetID(), leaderboard[1].PlayerID)
+}
+
+func TestRatingService_UnratedPlayer(t *testing.T) {
+	ratingService := NewRatingService(repositories.NewInMemoryRatingRepository())
+
+	rating, err := ratingService.GetPlayerRating(context.Background(), "unknown")
+	require.NoError(t, err)
+	assert.Nil(t, rating)
+}
+
+func TestRatingService_GlobalLeaderboardLimit(t *testing.T) {
+	ratingRepo := repositories.NewInMemoryRatingRepository()
+	ratings := make([]*models.PlayerRating, 0, MaxGlobalLeaderboardSize+10)
+	for i := 0; i < MaxGlobalLeaderboardSize+10; i++ {
+		ratings = append(ratings, &models.PlayerRating{PlayerID: fmt.Sprintf("player-%03d", i), Rating: float64(1000 + i)})
+	}
+	require.NoError(t, ratingRepo.SaveRatings(context.Background(), ratings))
+	ratingService := NewRatingService(ratingRepo)
+
+	leaderboard, err := ratingService.GetGlobalLeaderboard(context.Background(), 3)
+	require.NoError(t, err)
+	require.Len(t, leaderboard, 3)
+	assert.Equal(t, float64(1000+MaxGlobalLeaderboardSize+9), leaderboard[0].Rating)
+
+	leaderboard, err = ratingService.GetGlobalLeaderboard(context.Background(), 10000)
+	require.NoError(t, err)
+	assert.Len(t, leaderboard, MaxGlobalLeaderboardSize)
+}
@@ -0,0 +1,28 @@
+package services
+
+import (
+	"context"
+	"ligain/backend/models"
+	"time"
+)
+
+// ScoreObserver is notified after the scores of a finished match have been saved and applied to a game.
+// A match is scored again with a later version when its result is corrected: the observer then replaces what it did
+// for the previous scoring instead of adding to it, and ignores a scoring it already handled
+type ScoreObserver interface {
+	// OnMatchScored receives the game with the match already moved to its past results, and the scoring applied
+	OnMatchScored(gameID string, game models.Game, match models.Match, scoring *models.MatchScoring) error
+}
+
+// ActivityObserver is notified of the events that make the activity feed of a game
+type ActivityObserver interface {
+	// OnActivity receives an activity without id nor date, both are set when it's recorded
+	OnActivity(activity *models.Activity) error
+}
+
+// BetRecorder records the bets saved by the players, in the transaction which saves the bet
+type BetRecorder interface {
+	// RecordBet receives the bet as saved at placedAt, ctx carries the transaction and the device it was sent from.
+	// An error fails the bet, which is rolled back
+	RecordBet(ctx context.Context, gameID string, player models.Player, bet *models.Bet, placedAt time.Time) error
+}
@@ -14,6 +14,9 @@ type BlobStorage interface {
 	// GenerateSignedURL creates a time-limited URL for reading an object
 	GenerateSignedURL(ctx context.Context, objectPath string, ttl time.Duration) (string, error)
 
+	// Download retrieves the data of an object
+	Download(ctx context.Context, objectPath string) ([]byte, error)
+
 	// Delete removes an object (idempotent - no error if doesn't exist)
 	Delete(ctx context.Context, objectPath string) error
 }
@@ -96,7 +96,7 @@ func (g *GCSBlobStorage) Exists(ctx context.Context, objectPath string) (bool, e
 	return true, nil
 }
 
-// Download retrieves object data (useful for testing).
+// Download retrieves object data.
 func (g *GCSBlobStorage) Download(ctx context.Context, objectPath string) ([]byte, error) {
 	bucket := g.client.Bucket(g.bucketName)
 	obj := bucket.Object(objectPath)
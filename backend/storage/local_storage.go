@@ -0,0 +1,133 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LocalBlobStorage implements BlobStorage on the local filesystem, for development and tests.
+// Its signed URLs point to its own ServeHTTP, which checks the signature and the expiry like GCS does.
+type LocalBlobStorage struct {
+	dir     string
+	baseURL string
+	key     []byte
+}
+
+// NewLocalBlobStorage creates a LocalBlobStorage storing objects under dir.
+// Signed URLs start with baseURL, where ServeHTTP must be mounted, and are signed with key.
+func NewLocalBlobStorage(dir string, baseURL string, key []byte) (*LocalBlobStorage, error) {
+	if len(key) == 0 {
+		return nil, errors.New("a signing key is required")
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+
+	return &LocalBlobStorage{
+		dir:     dir,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		key:     key,
+	}, nil
+}
+
+// Upload stores data at the given path. The content type isn't kept.
+func (l *LocalBlobStorage) Upload(ctx context.Context, objectPath string, data []byte, contentType string) error {
+	path, err := l.path(objectPath)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0o600)
+}
+
+// GenerateSignedURL creates a time-limited URL for reading an object through ServeHTTP.
+func (l *LocalBlobStorage) GenerateSignedURL(ctx context.Context, objectPath string, ttl time.Duration) (string, error) {
+	if _, err := l.path(objectPath); err != nil {
+		return "", err
+	}
+
+	expires := strconv.FormatInt(time.Now().Add(ttl).Unix(), 10)
+	query := url.Values{}
+	query.Set("expires", expires)
+	query.Set("signature", l.sign(objectPath, expires))
+
+	return fmt.Sprintf("%s/%s?%s", l.baseURL, objectPath, query.Encode()), nil
+}
+
+// Download retrieves object data.
+func (l *LocalBlobStorage) Download(ctx context.Context, objectPath string) ([]byte, error) {
+	path, err := l.path(objectPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return os.ReadFile(path)
+}
+
+// Delete removes an object (idempotent - no error if doesn't exist).
+func (l *LocalBlobStorage) Delete(ctx context.Context, objectPath string) error {
+	path, err := l.path(objectPath)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// ServeHTTP serves the objects of the signed URLs. The object path is the request path, stripped of any mount prefix.
+func (l *LocalBlobStorage) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	objectPath := strings.TrimPrefix(r.URL.Path, "/")
+	expires := r.URL.Query().Get("expires")
+
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	signature := r.URL.Query().Get("signature")
+	if err != nil || !hmac.Equal([]byte(signature), []byte(l.sign(objectPath, expires))) {
+		http.Error(w, "invalid signature", http.StatusForbidden)
+		return
+	}
+	if time.Now().Unix() >= expiresAt {
+		http.Error(w, "expired", http.StatusForbidden)
+		return
+	}
+
+	path, err := l.path(objectPath)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	http.ServeFile(w, r, path)
+}
+
+// path maps an object path to a file under the storage directory, rejecting paths escaping it
+func (l *LocalBlobStorage) path(objectPath string) (string, error) {
+	cleaned := filepath.Clean("/" + objectPath)
+	if objectPath == "" || cleaned != "/"+objectPath {
+		return "", fmt.Errorf("invalid object path %q", objectPath)
+	}
+
+	return filepath.Join(l.dir, filepath.FromSlash(cleaned)), nil
+}
+
+// sign computes the signature of an object path and expiry
+func (l *LocalBlobStorage) sign(objectPath string, expires string) string {
+	mac := hmac.New(sha256.New, l.key)
+	mac.Write([]byte(objectPath + "\n" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
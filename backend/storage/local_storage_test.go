@@ -0,0 +1,86 @@
+package storage
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestLocalStorage(t *testing.T) *LocalBlobStorage {
+	local, err := NewLocalBlobStorage(t.TempDir(), "http://localhost:8080/storage", []byte("test-key"))
+	require.NoError(t, err)
+	return local
+}
+
+func TestLocalBlobStorage_UploadDownloadDelete(t *testing.T) {
+	local := newTestLocalStorage(t)
+	ctx := context.Background()
+
+	require.NoError(t, local.Upload(ctx, "exports/player1/export.zip", []byte("archive"), "application/zip"))
+
+	data, err := local.Download(ctx, "exports/player1/export.zip")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("archive"), data)
+
+	require.NoError(t, local.Delete(ctx, "exports/player1/export.zip"))
+	_, err = local.Download(ctx, "exports/player1/export.zip")
+	assert.Error(t, err)
+
+	// Delete is idempotent
+	assert.NoError(t, local.Delete(ctx, "exports/player1/export.zip"))
+}
+
+func TestLocalBlobStorage_RejectsEscapingPaths(t *testing.T) {
+	local := newTestLocalStorage(t)
+	ctx := context.Background()
+
+	assert.Error(t, local.Upload(ctx, "../outside.txt", []byte("data"), "text/plain"))
+	assert.Error(t, local.Upload(ctx, "exports/../../outside.txt", []byte("data"), "text/plain"))
+	_, err := local.Download(ctx, "/etc/passwd")
+	assert.Error(t, err)
+}
+
+func TestLocalBlobStorage_ServeSignedURL(t *testing.T) {
+	local := newTestLocalStorage(t)
+	ctx := context.Background()
+	require.NoError(t, local.Upload(ctx, "exports/player1/export.zip", []byte("archive"), "application/zip"))
+
+	serve := func(signedURL string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, strings.TrimPrefix(signedURL, "http://localhost:8080/storage"), nil)
+		w := httptest.NewRecorder()
+		local.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("Valid", func(t *testing.T) {
+		signedURL, err := local.GenerateSignedURL(ctx, "exports/player1/export.zip", time.Hour)
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(signedURL, "http://localhost:8080/storage/exports/player1/export.zip?"))
+
+		w := serve(signedURL)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "archive", w.Body.String())
+	})
+
+	t.Run("Tampered", func(t *testing.T) {
+		signedURL, err := local.GenerateSignedURL(ctx, "exports/player1/export.zip", time.Hour)
+		require.NoError(t, err)
+
+		w := serve(strings.Replace(signedURL, "player1", "player2", 1))
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		signedURL, err := local.GenerateSignedURL(ctx, "exports/player1/export.zip", -time.Minute)
+		require.NoError(t, err)
+
+		w := serve(signedURL)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
@@ -13,6 +13,7 @@ type MockBlobStorage struct {
 	// Error injection for testing error paths
 	UploadError    error
 	SignedURLError error
+	DownloadError  error
 	DeleteError    error
 }
 
@@ -42,6 +43,19 @@ func (m *MockBlobStorage) GenerateSignedURL(ctx context.Context, objectPath stri
 	return fmt.Sprintf("https://mock-storage.example.com/%s?expires=%.0f", objectPath, ttl.Seconds()), nil
 }
 
+// Download returns the data stored at the given path.
+func (m *MockBlobStorage) Download(ctx context.Context, objectPath string) ([]byte, error) {
+	if m.DownloadError != nil {
+		return nil, m.DownloadError
+	}
+
+	data, exists := m.objects[objectPath]
+	if !exists {
+		return nil, fmt.Errorf("object %s not found", objectPath)
+	}
+	return data, nil
+}
+
 // Delete removes an object (idempotent).
 func (m *MockBlobStorage) Delete(ctx context.Context, objectPath string) error {
 	if m.DeleteError != nil {
@@ -103,3 +103,17 @@ func TestMockBlobStorage_Clear_RemovesAllObjects(t *testing.T) {
 
 	assert.Equal(t, 0, mock.ObjectCount())
 }
+
+func TestMockBlobStorage_Download_ReturnsData(t *testing.T) {
+	mock := NewMockBlobStorage()
+	ctx := context.Background()
+
+	_ = mock.Upload(ctx, "test/path.webp", []byte("data"), "image/webp")
+
+	data, err := mock.Download(ctx, "test/path.webp")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("data"), data)
+
+	_, err = mock.Download(ctx, "missing/path.webp")
+	assert.Error(t, err)
+}
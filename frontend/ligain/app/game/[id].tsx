@@ -10,6 +10,7 @@ import { colors } from '../../src/constants/colors';
 import { useTranslation } from 'react-i18next';
 import { useGames } from '../../src/contexts/GamesContext';
 import { useMatches } from '../../src/contexts/MatchesContext';
+import { isBettingOpen } from '../../src/types/match';
 import { getTranslatedGameStatus } from '../../src/utils/gameStatusUtils';
 import { Picker } from '@react-native-picker/picker';
 import { SeasonBanner } from '../../src/components/SeasonBanner';
@@ -95,7 +96,7 @@ export default function GameOverviewScreen() {
     if (!isSelectedGame || !closestMatchday || !incomingByMatchday[closestMatchday]) return [];
     const now = new Date();
     return incomingByMatchday[closestMatchday]
-      .filter(mr => !mr.match.hasStarted(now) && !(mr.bets && mr.bets[player?.id ?? '']))
+      .filter(mr => isBettingOpen(mr, now) && !(mr.bets && mr.bets[player?.id ?? '']))
       .sort((a, b) => a.match.getDate().getTime() - b.match.getDate().getTime());
   }, [isSelectedGame, closestMatchday, incomingByMatchday, player?.id]);
 
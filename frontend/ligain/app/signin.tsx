@@ -15,7 +15,7 @@ import { Ionicons } from '@expo/vector-icons';
 import { useAuth } from '../src/contexts/AuthContext';
 import { AuthService } from '../src/services/authService';
 import { colors } from '../src/constants/colors';
-import { API_CONFIG, getApiHeaders } from '../src/config/api';
+import { API_CONFIG, getApiHeaders, saveAuthTokens } from '../src/config/api';
 import { GoogleSignInButton } from '../src/components/GoogleSignInButton';
 import { AppleSignInButton } from '../src/components/AppleSignInButton';
 import { PrivacyTermsModal } from '../src/components/PrivacyTermsModal';
@@ -97,7 +97,7 @@ export default function SignInScreen() {
     setIsLoading(true);
     try {
       const result = await AuthService.signInAsGuest('Player1');
-      await setItem('auth_token', result.token);
+      await saveAuthTokens(result.token, result.refreshToken);
       await setItem('player_data', JSON.stringify({ id: result.playerId, name: result.name }));
       setPlayer({ id: result.playerId || 'guest-player', name: result.name || 'Guest Player' });
       setShowGuestPasswordModal(false);
@@ -1,5 +1,5 @@
 import { useMatches } from '../src/contexts/MatchesContext';
-import { MatchResult } from '../src/types/match';
+import { MatchResult, isBettingOpen } from '../src/types/match';
 
 interface UseNextMatchResult {
   remainingCount: number;
@@ -17,7 +17,7 @@ export function useNextMatch(
   const unbetSiblings = (incomingByMatchday[matchday] ?? [])
     .filter(mr =>
       mr.match.id() !== currentMatchId &&
-      !mr.match.hasStarted(now) &&
+      isBettingOpen(mr, now) &&
       !(mr.bets && mr.bets[playerId])
     )
     .sort((a, b) => a.match.getDate().getTime() - b.match.getDate().getTime());
@@ -67,7 +67,7 @@ export class MockAuthApi implements AuthApi {
     };
   }
 
-  async signInGuest(name: string): Promise<AuthSignInResponse> {
+  async signInGuest(name: string, _secret?: string): Promise<AuthSignInResponse> {
     await simulateDelay(500);
     console.log(`[MockAuthApi] signInGuest as "${name}" - returning mock response`);
     return {
@@ -80,6 +80,12 @@ export class MockAuthApi implements AuthApi {
     };
   }
 
+  async claimGuestSecret(): Promise<string> {
+    await simulateDelay(200);
+    console.log('[MockAuthApi] claimGuestSecret');
+    return 'MOCK-MOCK-MOCK-MOCK';
+  }
+
   async signOut(): Promise<void> {
     await simulateDelay(200);
     console.log('[MockAuthApi] signOut');
@@ -5,7 +5,8 @@
  * They wrap the existing fetch logic from the application.
  */
 
-import { API_CONFIG, getApiHeaders, authenticatedFetch } from '../config/api';
+import { API_CONFIG, REFRESH_TOKEN_KEY, getApiHeaders, authenticatedFetch } from '../config/api';
+import { getItem } from '../utils/storage';
 import { Player } from '../contexts/AuthContext';
 import {
   AuthApi,
@@ -85,14 +86,14 @@ export class RealAuthApi implements AuthApi {
     };
   }
 
-  async signInGuest(name: string): Promise<AuthSignInResponse> {
+  async signInGuest(name: string, secret?: string): Promise<AuthSignInResponse> {
     const response = await fetch(`${API_CONFIG.BASE_URL}/api/auth/signin/guest`, {
       method: 'POST',
       headers: {
         ...getApiHeaders(),
         'Content-Type': 'application/json',
       },
-      body: JSON.stringify({ name }),
+      body: JSON.stringify({ name, secret }),
     });
 
     if (!response.ok) {
@@ -107,10 +108,28 @@ export class RealAuthApi implements AuthApi {
     };
   }
 
+  async claimGuestSecret(): Promise<string> {
+    const response = await authenticatedFetch(`${API_CONFIG.BASE_URL}/api/auth/guest/secret`, {
+      method: 'POST',
+    });
+
+    if (!response.ok) {
+      const errorData = await response.json().catch(() => ({}));
+      throw new Error(errorData.error || `HTTP ${response.status}: ${response.statusText}`);
+    }
+
+    const data = await response.json();
+    return data.guestSecret;
+  }
+
   async signOut(): Promise<void> {
     try {
+      // Signing out with the refresh token ends the session, so the token can't be refreshed anymore
+      const refreshToken = await getItem(REFRESH_TOKEN_KEY);
       await authenticatedFetch(`${API_CONFIG.BASE_URL}/api/auth/signout`, {
         method: 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify(refreshToken ? { refreshToken } : {}),
       });
     } catch (error) {
       // Ignore network errors during signout
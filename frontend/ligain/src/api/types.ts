@@ -34,7 +34,11 @@ export class AvatarError extends Error {
 
 export interface AuthSignInResponse {
   token: string;
+  // Exchanged for a new access token once the short lived token expires
+  refreshToken?: string;
   player: Player;
+  // Only given to a new guest, who needs it to sign in again as the same guest
+  guestSecret?: string;
   status?: 'need_display_name';
   suggestedName?: string;
   error?: string;
@@ -139,8 +143,15 @@ export interface AuthApi {
   /**
    * Sign in as a guest user
    * @param name - Display name for the guest
+   * @param secret - Secret of the guest, needed to sign in again as an existing guest
    */
-  signInGuest(name: string): Promise<AuthSignInResponse>;
+  signInGuest(name: string, secret?: string): Promise<AuthSignInResponse>;
+
+  /**
+   * Give the signed in guest a new secret, replacing the previous one
+   * @returns The new secret
+   */
+  claimGuestSecret(): Promise<string>;
 
   /**
    * Sign out the current user
@@ -248,4 +248,42 @@ describe('authenticatedFetch', () => {
       expect(setItem).toHaveBeenCalledWith('auth_token', 'new-auth-token');
     });
   });
+
+  describe('Refresh on HTTP 401', () => {
+    const noHeaders = { get: jest.fn().mockReturnValue(null) };
+
+    it('should refresh the tokens and retry the request', async () => {
+      const { setItem } = require('../../utils/storage');
+
+      mockFetch
+        .mockResolvedValueOnce({ status: 401, headers: noHeaders })
+        .mockResolvedValueOnce({
+          status: 200,
+          ok: true,
+          headers: noHeaders,
+          json: jest.fn().mockResolvedValue({ token: 'new-access-token', refreshToken: 'new-refresh-token' }),
+        })
+        .mockResolvedValueOnce({ status: 200, ok: true, headers: noHeaders });
+
+      const response = await authenticatedFetch('https://api.test.com/endpoint');
+
+      expect(response.status).toBe(200);
+      expect(mockFetch).toHaveBeenCalledTimes(3);
+      expect(mockFetch.mock.calls[1][0]).toBe('https://api.test.com/api/auth/refresh');
+      expect(JSON.parse(mockFetch.mock.calls[1][1].body)).toEqual({ refreshToken: 'test-auth-token' });
+      expect(setItem).toHaveBeenCalledWith('auth_token', 'new-access-token');
+      expect(setItem).toHaveBeenCalledWith('refresh_token', 'new-refresh-token');
+    });
+
+    it('should return the 401 when the refresh fails', async () => {
+      mockFetch
+        .mockResolvedValueOnce({ status: 401, headers: noHeaders })
+        .mockResolvedValueOnce({ status: 401, ok: false, headers: noHeaders });
+
+      const response = await authenticatedFetch('https://api.test.com/endpoint');
+
+      expect(response.status).toBe(401);
+      expect(mockFetch).toHaveBeenCalledTimes(2);
+    });
+  });
 });
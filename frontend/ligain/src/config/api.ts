@@ -1,5 +1,5 @@
 import Constants from 'expo-constants';
-import { getItem } from '../utils/storage';
+import { getItem, setItem } from '../utils/storage';
 
 export const API_CONFIG = {
   BASE_URL: Constants.expoConfig?.extra?.apiBaseUrl,
@@ -8,6 +8,10 @@ export const API_CONFIG = {
   APP_VERSION: Constants.expoConfig?.version || '0.0.0',
 } as const;
 
+// Storage keys of the access token, sent with every request, and of the refresh token that renews it
+export const AUTH_TOKEN_KEY = 'auth_token';
+export const REFRESH_TOKEN_KEY = 'refresh_token';
+
 // Default App Store URL for forced updates
 const DEFAULT_STORE_URL = 'https://apps.apple.com/fr/app/ligain/id6748531523';
 
@@ -42,7 +46,7 @@ export const getAuthenticatedHeaders = async (additionalHeaders?: Record<string,
   const baseHeaders = getApiHeaders(additionalHeaders);
   
   try {
-    const token = await getItem('auth_token');
+    const token = await getItem(AUTH_TOKEN_KEY);
     console.log('🔧 API - Auth token exists:', !!token);
     console.log('🔧 API - Token value:', token ? `${token.substring(0, 10)}...` : 'null');
     
@@ -61,6 +65,55 @@ export const getAuthenticatedHeaders = async (additionalHeaders?: Record<string,
   }
 };
 
+/**
+ * Store the tokens of a sign-in or of a refresh
+ */
+export const saveAuthTokens = async (token: string, refreshToken?: string) => {
+  await setItem(AUTH_TOKEN_KEY, token);
+  if (refreshToken) {
+    await setItem(REFRESH_TOKEN_KEY, refreshToken);
+  }
+};
+
+// The refresh in progress. A refresh token can only be used once, so concurrent requests share the same refresh
+let pendingRefresh: Promise<boolean> | null = null;
+
+/**
+ * Exchange the stored refresh token for a new access token and a new refresh token
+ * @returns Whether new tokens were stored
+ */
+export const refreshAuthTokens = (): Promise<boolean> => {
+  if (!pendingRefresh) {
+    pendingRefresh = (async () => {
+      try {
+        const refreshToken = await getItem(REFRESH_TOKEN_KEY);
+        if (!refreshToken) return false;
+
+        const response = await fetch(`${API_CONFIG.BASE_URL}/api/auth/refresh`, {
+          method: 'POST',
+          headers: getApiHeaders({ 'Content-Type': 'application/json' }),
+          body: JSON.stringify({ refreshToken }),
+        });
+        if (!response.ok) {
+          console.warn('⚠️ API - Token refresh failed with status', response.status);
+          return false;
+        }
+
+        const data = await response.json();
+        await saveAuthTokens(data.token, data.refreshToken);
+        console.log('🔄 API - Tokens refreshed');
+        return true;
+      } catch (error) {
+        console.error('❌ API - Error refreshing tokens:', error);
+        return false;
+      } finally {
+        pendingRefresh = null;
+      }
+    })();
+  }
+  return pendingRefresh;
+};
+
 // Enhanced fetch function that handles automatic token refresh and version checks
 export const authenticatedFetch = async (url: string, options: RequestInit = {}): Promise<Response> => {
   console.log('🔧 API - Making authenticated request to:', url);
@@ -69,11 +122,19 @@ export const authenticatedFetch = async (url: string, options: RequestInit = {})
   const headers = await getAuthenticatedHeaders(options.headers as Record<string, string>);
 
   // Make the request
-  const response = await fetch(url, {
+  let response = await fetch(url, {
     ...options,
     headers,
   });
 
+  // The access token expired: refresh it and try again once
+  if (response.status === 401 && await refreshAuthTokens()) {
+    response = await fetch(url, {
+      ...options,
+      headers: await getAuthenticatedHeaders(options.headers as Record<string, string>),
+    });
+  }
+
   // Check for version outdated response (HTTP 426)
   if (response.status === 426) {
     console.log('⚠️ API - App version outdated, update required');
@@ -100,8 +161,7 @@ export const authenticatedFetch = async (url: string, options: RequestInit = {})
 
   if (newToken && tokenRefreshed === 'true') {
     console.log('🔄 API - Token was refreshed automatically, updating stored token');
-    const { setItem } = await import('../utils/storage');
-    await setItem('auth_token', newToken);
+    await setItem(AUTH_TOKEN_KEY, newToken);
   }
 
   return response;
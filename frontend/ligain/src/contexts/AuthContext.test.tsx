@@ -135,6 +135,7 @@ describe('AuthContext', () => {
       // Mock a successful response
       const mockResponse = {
         token: 'test-token',
+        refreshToken: 'test-refresh-token',
         player: {
           id: 'test-player-id',
           name: 'Test User',
@@ -160,6 +161,7 @@ describe('AuthContext', () => {
       expect(result.current.player).toEqual(mockResponse.player);
       expect(result.current.isLoading).toBe(false);
       expect(setItem).toHaveBeenCalledWith('auth_token', mockResponse.token);
+      expect(setItem).toHaveBeenCalledWith('refresh_token', mockResponse.refreshToken);
       expect(setItem).toHaveBeenCalledWith('player_data', JSON.stringify(mockResponse.player));
     });
   });
@@ -212,7 +214,7 @@ describe('AuthContext', () => {
 
       // Should still clear local storage regardless of network errors
       expect(result.current.player).toBeNull();
-      expect(multiRemove).toHaveBeenCalledWith(['auth_token', 'player_data']);
+      expect(multiRemove).toHaveBeenCalledWith(['auth_token', 'refresh_token', 'player_data']);
     });
   });
 });
@@ -1,6 +1,8 @@
 import React, { createContext, useContext, useState, useEffect, ReactNode } from 'react';
 import { getItem, setItem, multiRemove, isUsingMemoryFallback } from '../utils/storage';
 import { useAuthApi, useProfileApi } from '../api';
+import { getGuestSecret, saveGuestSecret, isGuestPlayer } from '../utils/guestSecret';
+import { AUTH_TOKEN_KEY, REFRESH_TOKEN_KEY, saveAuthTokens } from '../config/api';
 
 export interface Player {
   id: string;
@@ -58,7 +60,6 @@ export const AuthProvider: React.FC<AuthProviderProps> = ({ children }) => {
   const [selectedProvider, setSelectedProvider] = useState<'google' | 'apple' | 'guest' | null>(null);
   const [isMounted, setIsMounted] = useState(true);
 
-  const AUTH_TOKEN_KEY = 'auth_token';
   const PLAYER_DATA_KEY = 'player_data';
 
   // Track if component is mounted to prevent state updates after unmount
@@ -71,6 +72,18 @@ export const AuthProvider: React.FC<AuthProviderProps> = ({ children }) => {
     checkAuth();
   }, []);
 
+  // Guests created before secrets existed claim one while they're still signed in,
+  // since they can't sign in again by name alone
+  const ensureGuestSecret = async (signedIn: Player) => {
+    if (!isGuestPlayer(signedIn) || await getGuestSecret(signedIn.name)) return;
+    try {
+      const secret = await authApi.claimGuestSecret();
+      await saveGuestSecret(signedIn.name, secret);
+    } catch (error) {
+      console.warn('AuthContext - Failed to claim a guest secret:', error);
+    }
+  };
+
   const checkAuth = async () => {
     if (!isMounted) return;
 
@@ -95,9 +108,10 @@ export const AuthProvider: React.FC<AuthProviderProps> = ({ children }) => {
         if (result) {
           console.log('AuthContext - Auth valid, setting player:', result.player?.name);
           setPlayer(result.player);
+          await ensureGuestSecret(result.player);
         } else {
           console.log('AuthContext - Auth invalid, clearing storage');
-          await multiRemove([AUTH_TOKEN_KEY, PLAYER_DATA_KEY]);
+          await multiRemove([AUTH_TOKEN_KEY, REFRESH_TOKEN_KEY, PLAYER_DATA_KEY]);
           if (!isMounted) return;
           setPlayer(null);
         }
@@ -128,7 +142,10 @@ export const AuthProvider: React.FC<AuthProviderProps> = ({ children }) => {
 
       try {
         if (provider === 'guest') {
-          response = await authApi.signInGuest(name);
+          response = await authApi.signInGuest(name, await getGuestSecret(name));
+          if (response.guestSecret) {
+            await saveGuestSecret(response.player.name, response.guestSecret);
+          }
         } else {
           response = await authApi.signIn(provider, token, email, name);
         }
@@ -151,7 +168,7 @@ export const AuthProvider: React.FC<AuthProviderProps> = ({ children }) => {
 
         // Store token and player data
         console.log('AuthContext - Storing token and player data');
-        await setItem(AUTH_TOKEN_KEY, response.token);
+        await saveAuthTokens(response.token, response.refreshToken);
         await setItem(PLAYER_DATA_KEY, JSON.stringify(response.player));
 
         setPlayer(response.player);
@@ -191,7 +208,7 @@ export const AuthProvider: React.FC<AuthProviderProps> = ({ children }) => {
       console.error('Sign out error:', error);
     } finally {
       // Clear local storage regardless of backend response
-      await multiRemove([AUTH_TOKEN_KEY, PLAYER_DATA_KEY]);
+      await multiRemove([AUTH_TOKEN_KEY, REFRESH_TOKEN_KEY, PLAYER_DATA_KEY]);
       setPlayer(null);
     }
   };
@@ -42,6 +42,7 @@ function processMatchesResponse(data: any): MatchesState {
     const processed: Record<string, MatchResult> = {};
     Object.entries(matches).forEach(([key, value]: [string, any]) => {
       const match = SeasonMatch.fromJSON(value.match);
+      const bettingClosesAt = value.bettingClosesAt ? new Date(value.bettingClosesAt) : undefined;
 
       const bets = value.bets
         ? Object.entries(value.bets).reduce((acc: Record<string, any>, [, betData]: [string, any]) => {
@@ -50,7 +51,7 @@ function processMatchesResponse(data: any): MatchesState {
               playerName: betData.playerName,
               predictedHomeGoals: betData.predictedHomeGoals,
               predictedAwayGoals: betData.predictedAwayGoals,
-              isModifiable: () => !match.isFinished() && !match.isInProgress(),
+              isModifiable: (now: Date) => !match.isFinished() && !match.isInProgress() && now < (bettingClosesAt ?? match.getDate()),
             };
             return acc;
           }, {})
@@ -77,7 +78,7 @@ function processMatchesResponse(data: any): MatchesState {
           }, {})
         : null;
 
-      processed[key] = { match, bets, scores, playerBetStatuses };
+      processed[key] = { match, bets, scores, playerBetStatuses, bettingClosesAt };
     });
     return processed;
   };
@@ -1,11 +1,11 @@
 import { useEffect, useRef } from 'react';
 import { useAuth } from '../contexts/AuthContext';
 import { useNotifications } from './useNotifications';
-import { MatchResult } from '../types/match';
+import { MatchResult, getBettingClosesAt } from '../types/match';
 
 /**
  * Automatically manages match notifications.
- * Schedules notifications for matches without bets (1 hour before start),
+ * Schedules notifications for matches without bets (1 hour before the bets close),
  * cancels when bets are placed, and cleans up past matches.
  * 
  * @param incomingMatches - The incoming matches object from useMatches hook
@@ -39,7 +39,7 @@ export const useMatchNotifications = (
   };
 
   /**
-   * Schedules a notification if conditions are met (enabled, no bet, future match, not already scheduled).
+   * Schedules a notification if conditions are met (enabled, no bet, bets still open, not already scheduled).
    * @param matchId - Unique identifier for the match
    * @param matchResult - The match result containing match and bet information
    * @private
@@ -61,15 +61,15 @@ export const useMatchNotifications = (
     }
 
     const match = matchResult.match;
-    const matchDate = match.getDate();
+    const closesAt = getBettingClosesAt(matchResult);
 
-    if (!matchDate || matchDate <= new Date()) {
+    if (!closesAt || closesAt <= new Date()) {
       return;
     }
 
     const notificationId = await scheduleMatchNotification(
       matchId,
-      matchDate,
+      closesAt,
       match.homeTeamDisplayName(),
       match.awayTeamDisplayName()
     );
@@ -96,7 +96,7 @@ export const useMatchNotifications = (
   };
 
   /**
-   * Cleans up notifications for past matches.
+   * Cleans up notifications for matches whose bets are closed.
    * @param matchId - Unique identifier for the match
    * @param matchResult - The match result containing match information
    * @private
@@ -105,11 +105,10 @@ export const useMatchNotifications = (
     matchId: string,
     matchResult: MatchResult
   ) => {
-    const match = matchResult.match;
-    const matchDate = match.getDate();
+    const closesAt = getBettingClosesAt(matchResult);
     const now = new Date();
 
-    if (matchDate && matchDate < now) {
+    if (closesAt && closesAt < now) {
       await cancelMatchNotification(matchId);
       scheduledMatchIdsRef.current.delete(matchId);
     }
@@ -288,7 +288,7 @@ export const useNotifications = () => {
   }, [requestPermissions]);
 
   /**
-   * Schedules a local notification for a match 1 hour before it starts.
+   * Schedules a local notification for a match 1 hour before its bets close.
    * 
    * This function:
    * 1. Checks if notifications are enabled and permissions granted
@@ -309,7 +309,7 @@ export const useNotifications = () => {
    * - Permissions not granted: Returns null (can't schedule without permissions)
    * 
    * @param matchId - Unique identifier for the match (used for cancellation)
-   * @param matchDate - Date and time when the match starts
+   * @param matchDate - Date and time when the bets close on the match, its kickoff unless the game closes them earlier
    * @param homeTeam - Name of home team (for notification message)
    * @param awayTeam - Name of away team (for notification message)
    * @returns Promise resolving to notification identifier string, or null if not scheduled
@@ -2,6 +2,7 @@ import { GoogleSignin, statusCodes } from '@react-native-google-signin/google-si
 import { Platform } from 'react-native';
 import * as AppleAuthentication from 'expo-apple-authentication';
 import { API_CONFIG, getApiHeaders } from '../config/api';
+import { getGuestSecret, saveGuestSecret } from '../utils/guestSecret';
 
 // Configure Google Sign-In (only once, here)
 const googleSignInConfig: Record<string, any> = {
@@ -21,6 +22,7 @@ GoogleSignin.configure(googleSignInConfig);
 export interface AuthResult {
   provider: 'google' | 'apple' | 'guest';
   token: string;
+  refreshToken?: string;
   email: string;
   name: string;
   playerId?: string; // Optional for guest users
@@ -287,6 +289,7 @@ export class AuthService {
         },
         body: JSON.stringify({
           name: displayName,
+          secret: await getGuestSecret(displayName),
         }),
       });
 
@@ -296,11 +299,15 @@ export class AuthService {
       }
 
       const data = await response.json();
-      console.log('🔐 Guest Sign-In - Success:', data);
+      console.log('🔐 Guest Sign-In - Success:', { playerId: data.player?.id });
+      if (data.guestSecret) {
+        await saveGuestSecret(data.player.name, data.guestSecret);
+      }
       
       const result: AuthResult = {
         provider: 'guest',
         token: data.token,
+        refreshToken: data.refreshToken,
         email: '', // Guest users don't have email
         name: data.player.name,
         playerId: data.player.id, // Include the player ID from backend
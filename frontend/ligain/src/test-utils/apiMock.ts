@@ -15,6 +15,7 @@ export const createMockAuthApi = () => ({
   checkAuth: jest.fn().mockResolvedValue(null),
   signIn: jest.fn().mockResolvedValue({ token: 'test-token', player: { id: '1', name: 'Test' } }),
   signInGuest: jest.fn().mockResolvedValue({ token: 'test-token', player: { id: '1', name: 'Test' } }),
+  claimGuestSecret: jest.fn().mockResolvedValue('TEST-TEST-TEST-TEST'),
   signOut: jest.fn().mockResolvedValue(undefined),
 });
 
@@ -228,6 +228,17 @@ export interface MatchResult {
     bets: { [key: string]: SimplifiedBet } | null;
     scores: { [key: string]: SimplifiedScore } | null;
     playerBetStatuses: { [key: string]: PlayerBetStatus } | null;
+    // When the game stops taking bets on an incoming match, it can be well before kickoff
+    bettingClosesAt?: Date;
+}
+
+// The bets close at kickoff unless the game closes them earlier
+export function getBettingClosesAt(matchResult: MatchResult): Date {
+    return matchResult.bettingClosesAt ?? matchResult.match.getDate();
+}
+
+export function isBettingOpen(matchResult: MatchResult, now: Date): boolean {
+    return !matchResult.match.isFinished() && !matchResult.match.isInProgress() && now < getBettingClosesAt(matchResult);
 }
 
 export interface MatchesResponse {
@@ -0,0 +1,31 @@
+import { getItem, setItem } from './storage';
+import type { Player } from '../contexts/AuthContext';
+
+// The secrets of the guests signed in on this device, by name. They're kept on sign out,
+// since a guest can only sign in again with their secret
+const GUEST_SECRETS_KEY = 'guest_secrets';
+
+async function getGuestSecrets(): Promise<Record<string, string>> {
+  const stored = await getItem(GUEST_SECRETS_KEY);
+  if (!stored) return {};
+  try {
+    return JSON.parse(stored);
+  } catch {
+    return {};
+  }
+}
+
+export async function getGuestSecret(name: string): Promise<string | undefined> {
+  const secrets = await getGuestSecrets();
+  return secrets[name];
+}
+
+export async function saveGuestSecret(name: string, secret: string): Promise<void> {
+  const secrets = await getGuestSecrets();
+  await setItem(GUEST_SECRETS_KEY, JSON.stringify({ ...secrets, [name]: secret }));
+}
+
+// A guest has neither an email nor a Google or Apple account
+export function isGuestPlayer(player: Player): boolean {
+  return !player.email && (!player.provider || player.provider === 'guest');
+}
@@ -36,6 +36,7 @@ func main() {
 		apiKey := ligainCfg.Require("api_key")
 		allowedOrigins := ligainCfg.Require("allowed_origins")
 		sportsmonkToken := ligainCfg.Require("sportsmonk_api_token")
+		authTokenSigningKey := ligainCfg.Require("auth_token_signing_key")
 		googleClientID := ligainCfg.Get("google_client_id") // Optional for now
 
 		// Apple authentication configuration
@@ -158,6 +159,10 @@ func main() {
 									Name:  pulumi.String("SPORTSMONK_API_TOKEN"),
 									Value: pulumi.String(sportsmonkToken),
 								},
+								&cloudrun.ServiceTemplateSpecContainerEnvArgs{
+									Name:  pulumi.String("AUTH_TOKEN_SIGNING_KEY"),
+									Value: pulumi.String(authTokenSigningKey),
+								},
 								&cloudrun.ServiceTemplateSpecContainerEnvArgs{
 									Name:  pulumi.String("GOOGLE_CLIENT_ID"),
 									Value: pulumi.String(googleClientID),
@@ -0,0 +1,63 @@
+// Package dbutil holds the database setup shared by the scripts
+package dbutil
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+
+	"github.com/golang-migrate/migrate/v4"
+	migratePostgres "github.com/golang-migrate/migrate/v4/database/postgres"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+const (
+	dbUser     = "postgres"
+	dbPassword = "postgres"
+	dbName     = "ligain_test"
+	dbHost     = "localhost"
+	dbPort     = 5432
+)
+
+// DatabaseURL returns DATABASE_URL, or the local database started by `make db-start` when it's not set
+func DatabaseURL() string {
+	if dbURL := os.Getenv("DATABASE_URL"); dbURL != "" {
+		return dbURL
+	}
+
+	return fmt.Sprintf("postgres://%s:%s@%s:%d/%s?sslmode=disable",
+		dbUser, dbPassword, dbHost, dbPort, dbName)
+}
+
+// Open connects to the database at DatabaseURL
+func Open() (*sql.DB, error) {
+	db, err := sql.Open("pgx", DatabaseURL())
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %v", err)
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to ping database: %v", err)
+	}
+	return db, nil
+}
+
+// RunMigrations applies the migrations found at sourceURL, like file://backend/migrations, that aren't applied yet
+func RunMigrations(db *sql.DB, sourceURL string) error {
+	driver, err := migratePostgres.WithInstance(db, &migratePostgres.Config{})
+	if err != nil {
+		return fmt.Errorf("failed to create postgres driver: %v", err)
+	}
+
+	m, err := migrate.NewWithDatabaseInstance(sourceURL, "postgres", driver)
+	if err != nil {
+		return fmt.Errorf("failed to create migrate instance: %v", err)
+	}
+
+	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
+		return fmt.Errorf("failed to run migrations: %v", err)
+	}
+
+	return nil
+}
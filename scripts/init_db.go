@@ -4,45 +4,23 @@ import (
 	"database/sql"
 	"fmt"
 	"ligain/backend/models"
+	"ligain/scripts/dbutil"
 	"log"
-	"os"
 	"strings"
 	"time"
-
-	"github.com/golang-migrate/migrate/v4"
-	"github.com/golang-migrate/migrate/v4/database/postgres"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
-	_ "github.com/jackc/pgx/v5/stdlib"
-)
-
-const (
-	dbUser     = "postgres"
-	dbPassword = "postgres"
-	dbName     = "ligain_test"
-	dbHost     = "localhost"
-	dbPort     = 5432
 )
 
 func main() {
-	// Get database URL from environment or use default
-	dbURL := getDatabaseURL()
-
-	log.Printf("Connecting to database with URL: %s", dbURL)
-	db, err := sql.Open("pgx", dbURL)
-	if err != nil {
-		log.Fatalf("Failed to connect to database: %v", err)
-	}
-
-	// Test the connection
-	err = db.Ping()
+	log.Printf("Connecting to database with URL: %s", dbutil.DatabaseURL())
+	db, err := dbutil.Open()
 	if err != nil {
-		log.Fatalf("Failed to ping database: %v", err)
+		log.Fatal(err)
 	}
 	log.Println("Successfully connected to database")
 
 	// Run migrations
 	log.Println("Starting migrations...")
-	err = runMigrations(db)
+	err = dbutil.RunMigrations(db, "file://backend/migrations")
 	if err != nil {
 		log.Fatalf("Failed to run migrations: %v", err)
 	}
@@ -59,41 +37,6 @@ func main() {
 	db.Close()
 }
 
-func getDatabaseURL() string {
-	// Check if DATABASE_URL environment variable is set
-	if dbURL := os.Getenv("DATABASE_URL"); dbURL != "" {
-		return dbURL
-	}
-
-	// Fall back to default local database configuration
-	return fmt.Sprintf("postgres://%s:%s@%s:%d/%s?sslmode=disable",
-		dbUser, dbPassword, dbHost, dbPort, dbName)
-}
-
-func runMigrations(db *sql.DB) error {
-	// Initialize golang-migrate
-	driver, err := postgres.WithInstance(db, &postgres.Config{})
-	if err != nil {
-		return fmt.Errorf("failed to create postgres driver: %v", err)
-	}
-
-	m, err := migrate.NewWithDatabaseInstance(
-		"file://backend/migrations",
-		"postgres",
-		driver,
-	)
-	if err != nil {
-		return fmt.Errorf("failed to create migrate instance: %v", err)
-	}
-
-	// Run migrations
-	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
-		return fmt.Errorf("failed to run migrations: %v", err)
-	}
-
-	return nil
-}
-
 func insertTestData(db *sql.DB) error {
 	// Insert game with hardcoded UUID
 	gameId := "123e4567-e89b-12d3-a456-426614174000" // Hardcoded UUID that can be used in main.go
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	postgresRepo "ligain/backend/repositories/postgres"
+)
+
+func cleanupCodes(ctx context.Context, app *app, args []string) error {
+	_ = app.flags("codes cleanup").Parse(args)
+
+	count, err := app.countRows(ctx, `SELECT COUNT(*) FROM game_codes WHERE expires_at <= NOW()`)
+	if err != nil {
+		return err
+	}
+	if app.dryRun {
+		fmt.Printf("Would delete %d expired join codes\n", count)
+		return nil
+	}
+	if err := postgresRepo.NewPostgresGameCodeRepository(app.db).DeleteExpiredCodes(); err != nil {
+		return err
+	}
+	fmt.Printf("Deleted %d expired join codes\n", count)
+	return nil
+}
+
+func cleanupTokens(ctx context.Context, app *app, args []string) error {
+	_ = app.flags("tokens cleanup").Parse(args)
+
+	authTokens, err := app.countRows(ctx, `SELECT COUNT(*) FROM auth_tokens WHERE expires_at < NOW()`)
+	if err != nil {
+		return err
+	}
+	refreshTokens, err := app.countRows(ctx, `SELECT COUNT(*) FROM refresh_token WHERE expires_at < NOW()`)
+	if err != nil {
+		return err
+	}
+	if app.dryRun {
+		fmt.Printf("Would delete %d expired access tokens and %d expired refresh tokens\n", authTokens, refreshTokens)
+		return nil
+	}
+	if err := app.getAuthService().CleanupExpiredTokens(ctx); err != nil {
+		return err
+	}
+	fmt.Printf("Deleted %d expired access tokens and %d expired refresh tokens\n", authTokens, refreshTokens)
+	return nil
+}
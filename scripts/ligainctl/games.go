@@ -0,0 +1,196 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"ligain/backend/models"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+)
+
+func listGames(ctx context.Context, app *app, args []string) error {
+	flags := app.flags("games list")
+	query := flags.String("query", "", "id or join code of a game, or text its name contains")
+	limit := flags.Int("limit", 0, "number of games to list, 50 by default")
+	_ = flags.Parse(args)
+
+	adminService, err := app.getAdminService()
+	if err != nil {
+		return err
+	}
+	games, err := adminService.SearchGames(ctx, *query, *limit)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tCOMPETITION\tSEASON\tSTATUS\tPLAYERS\tCODE\tCREATED")
+	for _, game := range games {
+		created := "-"
+		if game.CreatedAt != nil {
+			created = game.CreatedAt.Format(time.RFC3339)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%d\t%s\t%s\n",
+			game.ID, game.Name, game.CompetitionName, game.SeasonYear, game.Status, game.PlayerCount, orDash(game.Code), created)
+	}
+	return w.Flush()
+}
+
+func showGame(ctx context.Context, app *app, args []string) error {
+	flags := app.flags("games show")
+	gameID := flags.String("game", "", "id of the game")
+	_ = flags.Parse(args)
+	if err := requireFlag("game", *gameID); err != nil {
+		return err
+	}
+
+	adminService, err := app.getAdminService()
+	if err != nil {
+		return err
+	}
+	details, err := adminService.GetGameDetails(ctx, *gameID)
+	if err != nil {
+		return err
+	}
+
+	summary := details.Summary
+	fmt.Printf("%s (%s)\n", summary.Name, summary.ID)
+	fmt.Printf("%s %s, %s, %d players, join code %s\n\n",
+		summary.CompetitionName, summary.SeasonYear, summary.Status, summary.PlayerCount, orDash(summary.Code))
+
+	playerNames := make(map[string]string)
+	for _, player := range details.Players {
+		playerNames[player.GetID()] = player.GetName()
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "RANK\tPLAYER\tPOINTS\tEXACT SCORES\tMISSED BETS")
+	for _, standing := range details.Standings {
+		fmt.Fprintf(w, "%d\t%s\t%d\t%d\t%d\n",
+			standing.Rank, standing.Player.GetName(), standing.Points, standing.ExactScores, standing.MissedBets)
+	}
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "MATCH\tDATE\tSTATUS\tRESULT\tBETS")
+	for _, result := range sortedMatchResults(details.PastMatches, details.IncomingMatches) {
+		match := result.Match
+		score := "-"
+		if match.IsFinished() || match.IsInProgress() {
+			score = fmt.Sprintf("%d - %d", match.GetHomeGoals(), match.GetAwayGoals())
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+			match.Id(), match.GetDate().Format(time.RFC3339), match.GetStatus(), score, formatBets(result, playerNames))
+	}
+	return w.Flush()
+}
+
+func finishGame(ctx context.Context, app *app, args []string) error {
+	flags := app.flags("games finish")
+	gameID := flags.String("game", "", "id of the game")
+	_ = flags.Parse(args)
+	if err := requireFlag("game", *gameID); err != nil {
+		return err
+	}
+
+	adminService, err := app.getAdminService()
+	if err != nil {
+		return err
+	}
+	details, err := adminService.GetGameDetails(ctx, *gameID)
+	if err != nil {
+		return err
+	}
+	if details.Summary.Status == string(models.GameStatusFinished) {
+		return fmt.Errorf("game %s is already finished", *gameID)
+	}
+
+	if app.dryRun {
+		fmt.Printf("Would finish %s (%s), %d matches still to play\n", details.Summary.Name, *gameID, len(details.IncomingMatches))
+		return nil
+	}
+	if err := adminService.FinishGame(ctx, app.operator, *gameID); err != nil {
+		return err
+	}
+	fmt.Printf("Finished %s (%s)\n", details.Summary.Name, *gameID)
+	return nil
+}
+
+func recomputeScores(ctx context.Context, app *app, args []string) error {
+	flags := app.flags("scores recompute")
+	gameID := flags.String("game", "", "id of the game")
+	_ = flags.Parse(args)
+	if err := requireFlag("game", *gameID); err != nil {
+		return err
+	}
+
+	adminService, err := app.getAdminService()
+	if err != nil {
+		return err
+	}
+	details, err := adminService.GetGameDetails(ctx, *gameID)
+	if err != nil {
+		return err
+	}
+
+	if app.dryRun {
+		fmt.Printf("Would score the %d past matches of %s (%s) again\n", len(details.PastMatches), details.Summary.Name, *gameID)
+		return nil
+	}
+	if err := adminService.RescoreGame(ctx, app.operator, *gameID); err != nil {
+		return err
+	}
+	fmt.Printf("Scored the %d past matches of %s (%s) again\n", len(details.PastMatches), details.Summary.Name, *gameID)
+	return nil
+}
+
+// sortedMatchResults returns the results of the matches of a game, by date
+func sortedMatchResults(resultMaps ...map[string]*models.MatchResult) []*models.MatchResult {
+	results := make([]*models.MatchResult, 0)
+	for _, resultMap := range resultMaps {
+		for _, result := range resultMap {
+			results = append(results, result)
+		}
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if !results[i].Match.GetDate().Equal(results[j].Match.GetDate()) {
+			return results[i].Match.GetDate().Before(results[j].Match.GetDate())
+		}
+		return results[i].Match.Id() < results[j].Match.Id()
+	})
+	return results
+}
+
+// formatBets lists the bets on a match, with the points they scored once it's scored, like "Alice 2-1 (500)"
+func formatBets(result *models.MatchResult, playerNames map[string]string) string {
+	playerIDs := make([]string, 0, len(result.Bets))
+	for playerID := range result.Bets {
+		playerIDs = append(playerIDs, playerID)
+	}
+	sort.Strings(playerIDs)
+
+	formatted := ""
+	for i, playerID := range playerIDs {
+		if i > 0 {
+			formatted += ", "
+		}
+		name := playerNames[playerID]
+		if name == "" {
+			name = playerID
+		}
+		bet := result.Bets[playerID]
+		formatted += fmt.Sprintf("%s %d-%d", name, bet.PredictedHomeGoals, bet.PredictedAwayGoals)
+		if points, scored := result.Scores[playerID]; scored {
+			formatted += fmt.Sprintf(" (%d)", points)
+		}
+	}
+	return orDash(formatted)
+}
+
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	postgresRepo "ligain/backend/repositories/postgres"
+	"ligain/backend/services"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+func (a *app) getAPIKeyService() services.APIKeyService {
+	return services.NewAPIKeyService(postgresRepo.NewPostgresAPIKeyRepository(a.db))
+}
+
+func listKeys(ctx context.Context, app *app, args []string) error {
+	_ = app.flags("keys list").Parse(args)
+
+	keys, err := app.getAPIKeyService().ListKeys(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tPREFIX\tSCOPES\tRATE LIMIT\tCREATED\tEXPIRES\tSTATUS")
+	for _, key := range keys {
+		expires := "-"
+		if key.ExpiresAt != nil {
+			expires = key.ExpiresAt.Format(time.RFC3339)
+		}
+		status := "active"
+		if !key.IsActive(now) {
+			status = "inactive"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%s\t%s\t%s\n",
+			key.ID, key.Name, key.Prefix, strings.Join(key.Scopes, ","), key.RateLimitPerMinute,
+			key.CreatedAt.Format(time.RFC3339), expires, status)
+	}
+	return w.Flush()
+}
+
+// createKey prints the raw key once, as only its hash is stored
+func createKey(ctx context.Context, app *app, args []string) error {
+	flags := app.flags("keys create")
+	name := flags.String("name", "", "name of the client, like ios or android")
+	scopes := flags.String("scopes", "read,write", "comma-separated scopes among read, write and admin")
+	rateLimit := flags.Int("rate-limit", 0, "requests per minute, 0 for no limit")
+	_ = flags.Parse(args)
+	if err := requireFlag("name", *name); err != nil {
+		return err
+	}
+
+	if app.dryRun {
+		fmt.Printf("Would create a key for %s with the scopes %s\n", *name, *scopes)
+		return nil
+	}
+	rawKey, key, err := app.getAPIKeyService().CreateKey(ctx, *name, strings.Split(*scopes, ","), *rateLimit)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Created key %s for %s: %s\n", key.ID, key.Name, rawKey)
+	return nil
+}
+
+func rotateKey(ctx context.Context, app *app, args []string) error {
+	flags := app.flags("keys rotate")
+	name := flags.String("name", "", "name of the client")
+	overlap := flags.Duration("overlap", 30*24*time.Hour, "how long the previous keys stay valid")
+	_ = flags.Parse(args)
+	if err := requireFlag("name", *name); err != nil {
+		return err
+	}
+
+	keyService := app.getAPIKeyService()
+	expiresAt := time.Now().Add(*overlap).Format(time.RFC3339)
+	if app.dryRun {
+		keys, err := keyService.ListKeys(ctx)
+		if err != nil {
+			return err
+		}
+		active := 0
+		for _, key := range keys {
+			if key.Name == *name && key.IsActive(time.Now()) {
+				active++
+			}
+		}
+		fmt.Printf("Would create a key for %s, and expire its %d active keys at %s\n", *name, active, expiresAt)
+		return nil
+	}
+	rawKey, key, err := keyService.RotateKey(ctx, *name, *overlap)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Created key %s for %s: %s\n", key.ID, key.Name, rawKey)
+	fmt.Printf("The previous keys of %s expire at %s\n", key.Name, expiresAt)
+	return nil
+}
+
+func revokeKey(ctx context.Context, app *app, args []string) error {
+	flags := app.flags("keys revoke")
+	id := flags.String("id", "", "id of the key")
+	_ = flags.Parse(args)
+	if err := requireFlag("id", *id); err != nil {
+		return err
+	}
+
+	if app.dryRun {
+		fmt.Printf("Would revoke key %s\n", *id)
+		return nil
+	}
+	if err := app.getAPIKeyService().RevokeKey(ctx, *id); err != nil {
+		return err
+	}
+	fmt.Printf("Revoked key %s, instances stop accepting it within %s\n", *id, services.APIKeyCacheTTL)
+	return nil
+}
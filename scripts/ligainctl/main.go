@@ -0,0 +1,212 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"ligain/backend/models"
+	"ligain/backend/repositories"
+	postgresRepo "ligain/backend/repositories/postgres"
+	"ligain/backend/services"
+	"ligain/scripts/dbutil"
+	"log"
+	"os"
+	"time"
+)
+
+const usage = `Maintain the games, matches and players of the production database.
+
+Usage:
+  ligainctl [-dry-run] <group> <command> [flags]
+
+Commands:
+  games list [-query <id, code or name>] [-limit 50]
+  games show -game <game id>
+  games finish -game <game id>
+  matches sync -competition "Ligue 1" -season 2025/2026
+  matches override-score -match <match id> -home <goals> -away <goals>
+  scores recompute -game <game id>
+  players merge -from <player id> -into <player id>
+  players delete -player <player id>
+  codes cleanup
+  tokens cleanup
+  keys list
+  keys create -name <client> [-scopes read,write] [-rate-limit <requests per minute>]
+  keys rotate -name <client> [-overlap 720h]
+  keys revoke -id <key id>
+
+With -dry-run, which every command accepts, the changes are shown but not made.
+The database is read from DATABASE_URL. Changes to games are written to the admin audit log as done by ligainctl,
+and reach the running backend instances through the game_changed notifications.
+`
+
+// command is a subcommand of ligainctl, like "games list"
+type command struct {
+	group string
+	name  string
+	run   func(ctx context.Context, app *app, args []string) error
+}
+
+var commands = []command{
+	{"games", "list", listGames},
+	{"games", "show", showGame},
+	{"games", "finish", finishGame},
+	{"matches", "sync", syncMatches},
+	{"matches", "override-score", overrideScore},
+	{"scores", "recompute", recomputeScores},
+	{"players", "merge", mergePlayers},
+	{"players", "delete", deletePlayer},
+	{"codes", "cleanup", cleanupCodes},
+	{"tokens", "cleanup", cleanupTokens},
+	{"keys", "list", listKeys},
+	{"keys", "create", createKey},
+	{"keys", "rotate", rotateKey},
+	{"keys", "revoke", revokeKey},
+}
+
+func main() {
+	globalFlags := flag.NewFlagSet("ligainctl", flag.ExitOnError)
+	globalFlags.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	dryRun := globalFlags.Bool("dry-run", false, "show the changes without making them")
+	_ = globalFlags.Parse(os.Args[1:])
+
+	args := globalFlags.Args()
+	if len(args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	cmd := findCommand(args[0], args[1])
+	if cmd == nil {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	db, err := dbutil.Open()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	app := newApp(db, *dryRun)
+	if err := cmd.run(context.Background(), app, args[2:]); err != nil {
+		log.Fatalf("Failed to run %s %s: %v", cmd.group, cmd.name, err)
+	}
+}
+
+func findCommand(group, name string) *command {
+	for i := range commands {
+		if commands[i].group == group && commands[i].name == name {
+			return &commands[i]
+		}
+	}
+	return nil
+}
+
+// app holds the repositories and services the commands share. The game repository and the services are only
+// created when a command needs them, as the game service registry loads every running game
+type app struct {
+	db       *sql.DB
+	dryRun   bool
+	operator *models.PlayerData
+
+	gameRepo     repositories.GameRepository
+	adminService services.AdminService
+	authService  *services.AuthService
+}
+
+func newApp(db *sql.DB, dryRun bool) *app {
+	name := "ligainctl"
+	if user := os.Getenv("USER"); user != "" {
+		name = fmt.Sprintf("ligainctl (%s)", user)
+	}
+	return &app{
+		db:     db,
+		dryRun: dryRun,
+		// The operator has no player account, so the audit log keeps only their name
+		operator: &models.PlayerData{Name: name, Role: models.PlayerRoleAdmin},
+	}
+}
+
+// flags creates the flag set of a command, which accepts -dry-run after the command too
+func (a *app) flags(name string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ExitOnError)
+	flags.BoolVar(&a.dryRun, "dry-run", a.dryRun, "show the changes without making them")
+	return flags
+}
+
+func (a *app) getGameRepo() (repositories.GameRepository, error) {
+	if a.gameRepo == nil {
+		gameRepo, err := postgresRepo.NewPostgresGameRepository(a.db)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create game repository: %v", err)
+		}
+		a.gameRepo = gameRepo
+	}
+	return a.gameRepo, nil
+}
+
+func (a *app) getAdminService() (services.AdminService, error) {
+	if a.adminService != nil {
+		return a.adminService, nil
+	}
+
+	gameRepo, err := a.getGameRepo()
+	if err != nil {
+		return nil, err
+	}
+	betRepo := postgresRepo.NewPostgresBetRepository(a.db)
+	gamePlayerRepo := postgresRepo.NewPostgresGamePlayerRepository(a.db)
+	// No watcher: the backend instances register the games put back in progress when they're notified of the change
+	uow := postgresRepo.NewUnitOfWork(a.db)
+	registry, err := services.NewGameServiceRegistryWithUnitOfWork(uow, gameRepo, betRepo, gamePlayerRepo, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load running games: %v", err)
+	}
+
+	a.adminService = services.NewAdminService(
+		uow,
+		postgresRepo.NewPostgresAdminRepository(a.db),
+		gameRepo,
+		postgresRepo.NewPostgresGameEventRepository(a.db),
+		postgresRepo.NewPostgresGameCodeRepository(a.db),
+		betRepo,
+		postgresRepo.NewPostgresMatchRepository(a.db),
+		registry,
+		nil,
+	)
+	return a.adminService, nil
+}
+
+func (a *app) getAuthService() *services.AuthService {
+	if a.authService == nil {
+		// Access tokens are neither issued nor checked here, so no signer nor OAuth verifier is needed
+		a.authService = services.NewAuthServiceWithTokens(
+			postgresRepo.NewPostgresPlayerRepository(a.db),
+			postgresRepo.NewPostgresRefreshTokenRepository(a.db),
+			postgresRepo.NewPostgresGuestCredentialRepository(a.db),
+			postgresRepo.NewPostgresAccountDeletionRepository(a.db),
+			nil,
+			nil,
+			time.Now,
+		)
+	}
+	return a.authService
+}
+
+// countRows returns the number of rows a COUNT query finds, to show what a cleanup would delete
+func (a *app) countRows(ctx context.Context, query string, args ...any) (int, error) {
+	var count int
+	if err := a.db.QueryRowContext(ctx, query, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count rows: %v", err)
+	}
+	return count, nil
+}
+
+// requireFlag fails when a mandatory flag wasn't given
+func requireFlag(name, value string) error {
+	if value == "" {
+		return fmt.Errorf("-%s is required", name)
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"ligain/backend/api"
+	"ligain/backend/models"
+	postgresRepo "ligain/backend/repositories/postgres"
+	"os"
+	"sort"
+	"strings"
+)
+
+// syncMatches saves the fixtures of a season as the provider knows them: new matches, rescheduled ones, results and odds.
+// The running games are scored by the match watcher of the backend, not by the sync
+func syncMatches(ctx context.Context, app *app, args []string) error {
+	flags := app.flags("matches sync")
+	competition := flags.String("competition", "Ligue 1", "competition code of the matches")
+	season := flags.String("season", "", "season code of the matches, like 2025/2026")
+	_ = flags.Parse(args)
+	if err := requireFlag("season", *season); err != nil {
+		return err
+	}
+	apiToken := os.Getenv("SPORTSMONK_API_TOKEN")
+	if apiToken == "" {
+		return fmt.Errorf("SPORTSMONK_API_TOKEN environment variable is required")
+	}
+
+	sportsmonkAPI := api.NewSportsmonkAPI(apiToken)
+	competitionID, err := sportsmonkAPI.GetCompetitionId(*competition)
+	if err != nil {
+		return err
+	}
+	seasonIDs, err := sportsmonkAPI.GetSeasonIds([]string{*season}, competitionID)
+	if err != nil {
+		return fmt.Errorf("failed to get season id: %v", err)
+	}
+	seasonID, exists := seasonIDs[*season]
+	if !exists {
+		return fmt.Errorf("season %s of %s not found", *season, *competition)
+	}
+	fixtures, err := sportsmonkAPI.GetSeasonFixtures(seasonID)
+	if err != nil {
+		return fmt.Errorf("failed to get fixtures: %v", err)
+	}
+
+	matchRepo := postgresRepo.NewPostgresMatchRepository(app.db)
+	saved, err := matchRepo.GetMatchesByCompetitionAndSeason(*competition, *season)
+	if err != nil {
+		return fmt.Errorf("failed to get saved matches: %v", err)
+	}
+	savedMatches := make(map[string]models.Match, len(saved))
+	for _, match := range saved {
+		savedMatches[match.Id()] = match
+	}
+
+	// The provider can return the same match under several fixtures
+	latest := make(map[string]models.Match, len(fixtures))
+	for _, match := range fixtures {
+		latest[match.Id()] = match
+	}
+	matchIDs := make([]string, 0, len(latest))
+	for matchID := range latest {
+		matchIDs = append(matchIDs, matchID)
+	}
+	sort.Strings(matchIDs)
+
+	created, updated := 0, 0
+	for _, matchID := range matchIDs {
+		match := latest[matchID]
+		savedMatch, exists := savedMatches[matchID]
+		changes := matchChanges(savedMatch, match)
+		if exists && len(changes) == 0 {
+			continue
+		}
+
+		if exists {
+			updated++
+			fmt.Printf("Update %s: %s\n", matchID, strings.Join(changes, ", "))
+		} else {
+			created++
+			fmt.Printf("Add %s on %s\n", matchID, match.GetDate().Format("2006-01-02 15:04"))
+		}
+		if app.dryRun {
+			continue
+		}
+		if err := matchRepo.SaveMatch(match); err != nil {
+			return fmt.Errorf("failed to save match %s: %v", matchID, err)
+		}
+	}
+
+	verb := "Synced"
+	if app.dryRun {
+		verb = "Would sync"
+	}
+	fmt.Printf("%s %s %s: %d new matches, %d updated, %d unchanged\n",
+		verb, *competition, *season, created, updated, len(matchIDs)-created-updated)
+	return nil
+}
+
+// matchChanges describes what differs between the saved match and the one from the provider
+func matchChanges(saved, latest models.Match) []string {
+	if saved == nil {
+		return nil
+	}
+
+	changes := make([]string, 0)
+	if saved.GetStatus() != latest.GetStatus() {
+		changes = append(changes, fmt.Sprintf("status %s -> %s", saved.GetStatus(), latest.GetStatus()))
+	}
+	if saved.GetHomeGoals() != latest.GetHomeGoals() || saved.GetAwayGoals() != latest.GetAwayGoals() {
+		changes = append(changes, fmt.Sprintf("score %d-%d -> %d-%d",
+			saved.GetHomeGoals(), saved.GetAwayGoals(), latest.GetHomeGoals(), latest.GetAwayGoals()))
+	}
+	if !saved.GetDate().Equal(latest.GetDate()) {
+		changes = append(changes, fmt.Sprintf("date %s -> %s",
+			saved.GetDate().Format("2006-01-02 15:04"), latest.GetDate().Format("2006-01-02 15:04")))
+	}
+	if saved.GetHomeTeamOdds() != latest.GetHomeTeamOdds() ||
+		saved.GetDrawOdds() != latest.GetDrawOdds() ||
+		saved.GetAwayTeamOdds() != latest.GetAwayTeamOdds() {
+		changes = append(changes, "odds")
+	}
+	return changes
+}
+
+func overrideScore(ctx context.Context, app *app, args []string) error {
+	flags := app.flags("matches override-score")
+	matchID := flags.String("match", "", "id of the match")
+	homeGoals := flags.Int("home", -1, "goals of the home team")
+	awayGoals := flags.Int("away", -1, "goals of the away team")
+	_ = flags.Parse(args)
+	if err := requireFlag("match", *matchID); err != nil {
+		return err
+	}
+	if *homeGoals < 0 || *awayGoals < 0 {
+		return fmt.Errorf("-home and -away are required, and can't be negative")
+	}
+
+	adminService, err := app.getAdminService()
+	if err != nil {
+		return err
+	}
+	matchGames, err := adminService.GetMatchGames(ctx, *matchID)
+	if err != nil {
+		return err
+	}
+
+	match := matchGames.Match
+	fmt.Printf("%s vs %s on %s, %s", match.GetHomeTeam(), match.GetAwayTeam(), match.GetDate().Format("2006-01-02 15:04"), match.GetStatus())
+	if match.IsFinished() {
+		fmt.Printf(" %d - %d", match.GetHomeGoals(), match.GetAwayGoals())
+	}
+	fmt.Println()
+	if len(matchGames.IncomingIn) > 0 {
+		fmt.Printf("To score in: %s\n", strings.Join(matchGames.IncomingIn, ", "))
+	}
+	if len(matchGames.ScoredIn) > 0 {
+		fmt.Printf("To score again in: %s\n", strings.Join(matchGames.ScoredIn, ", "))
+	}
+
+	if app.dryRun {
+		fmt.Printf("Would finish the match %d - %d\n", *homeGoals, *awayGoals)
+		return nil
+	}
+	gameIDs, err := adminService.ForceMatchResult(ctx, app.operator, *matchID, *homeGoals, *awayGoals)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Finished the match %d - %d in %d games\n", *homeGoals, *awayGoals, len(gameIDs))
+	return nil
+}
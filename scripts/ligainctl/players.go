@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"ligain/backend/repositories"
+	postgresRepo "ligain/backend/repositories/postgres"
+	"ligain/backend/services"
+	"time"
+)
+
+func mergePlayers(ctx context.Context, app *app, args []string) error {
+	flags := app.flags("players merge")
+	sourceID := flags.String("from", "", "id of the player merged, and then deleted")
+	targetID := flags.String("into", "", "id of the player kept")
+	_ = flags.Parse(args)
+	if err := requireFlag("from", *sourceID); err != nil {
+		return err
+	}
+	if err := requireFlag("into", *targetID); err != nil {
+		return err
+	}
+
+	gameRepo, err := app.getGameRepo()
+	if err != nil {
+		return err
+	}
+	// A dry run does the whole merge in a transaction that's rolled back, so its summary is exactly what would be merged
+	var uow repositories.UnitOfWork = postgresRepo.NewUnitOfWork(app.db)
+	if app.dryRun {
+		uow = postgresRepo.NewDryRunUnitOfWork(app.db)
+	}
+	mergeService := services.NewAccountMergeService(
+		uow,
+		postgresRepo.NewPostgresAccountMergeRepository(app.db),
+		postgresRepo.NewPostgresPlayerRepository(app.db),
+		gameRepo,
+		app.getAuthService(),
+	)
+
+	summary, err := mergeService.MergePlayers(ctx, *sourceID, *targetID)
+	if err != nil {
+		return err
+	}
+
+	verb := "Merged"
+	if app.dryRun {
+		verb = "Would merge"
+	}
+	fmt.Printf("%s %s into %s (%s): %d bets moved, %d games\n",
+		verb, *sourceID, summary.Player.Name, summary.Player.ID, summary.MovedBets, len(summary.GameIDs))
+	for _, conflict := range summary.Conflicts {
+		kept := "the bet of the kept player"
+		if conflict.KeptMergedBet {
+			kept = "the bet of the merged player"
+		}
+		fmt.Printf("  Both bet on %s in game %s, kept %s\n", conflict.MatchID, conflict.GameID, kept)
+	}
+	return nil
+}
+
+// deletePlayer deletes an account like the player would, so it can still be restored during the grace period
+func deletePlayer(ctx context.Context, app *app, args []string) error {
+	flags := app.flags("players delete")
+	playerID := flags.String("player", "", "id of the player")
+	_ = flags.Parse(args)
+	if err := requireFlag("player", *playerID); err != nil {
+		return err
+	}
+
+	player, err := postgresRepo.NewPostgresPlayerRepository(app.db).GetPlayerByID(ctx, *playerID)
+	if err != nil || player == nil {
+		return fmt.Errorf("player %s not found", *playerID)
+	}
+
+	purgeAfter := time.Now().Add(services.AccountDeletionGracePeriod).Format(time.RFC3339)
+	if app.dryRun {
+		fmt.Printf("Would delete %s (%s), purged after %s\n", player.Name, player.ID, purgeAfter)
+		return nil
+	}
+	if err := app.getAuthService().DeleteAccount(ctx, *playerID); err != nil {
+		return err
+	}
+	fmt.Printf("Deleted %s (%s), purged after %s\n", player.Name, player.ID, purgeAfter)
+	return nil
+}
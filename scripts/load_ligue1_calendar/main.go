@@ -1,21 +1,14 @@
 package main
 
 import (
-	"database/sql"
-	"fmt"
 	"ligain/backend/api"
 	"ligain/backend/models"
 	postgresRepo "ligain/backend/repositories/postgres"
+	"ligain/scripts/dbutil"
 	"log"
 	"os"
 
 	"math/rand"
-
-	"github.com/golang-migrate/migrate/v4"
-	migratePostgres "github.com/golang-migrate/migrate/v4/database/postgres"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
-	_ "github.com/jackc/pgx/v5/stdlib"
-	_ "github.com/lib/pq"
 )
 
 const (
@@ -28,26 +21,19 @@ const (
 func main() {
 	// Get database URL from environment or use default
 	rand.Seed(100)
-	dbURL := getDatabaseURL()
 	apiToken := getAPIToken()
 
-	log.Printf("Connecting to database with URL: %s", dbURL)
-	db, err := sql.Open("pgx", dbURL)
+	log.Printf("Connecting to database with URL: %s", dbutil.DatabaseURL())
+	db, err := dbutil.Open()
 	if err != nil {
-		log.Fatalf("Failed to connect to database: %v", err)
+		log.Fatal(err)
 	}
 	defer db.Close()
-
-	// Test the connection
-	err = db.Ping()
-	if err != nil {
-		log.Fatalf("Failed to ping database: %v", err)
-	}
 	log.Println("Successfully connected to database")
 
 	// Run migrations to ensure schema is up to date
 	log.Println("Running migrations...")
-	err = runMigrations(db)
+	err = dbutil.RunMigrations(db, "file://../../backend/migrations")
 	if err != nil {
 		log.Fatalf("Failed to run migrations: %v", err)
 	}
@@ -151,25 +137,6 @@ func adjustOdds(match models.Match) models.Match {
 	return match
 }
 
-func getDatabaseURL() string {
-	// Check if DATABASE_URL environment variable is set
-	if dbURL := os.Getenv("DATABASE_URL"); dbURL != "" {
-		return dbURL
-	}
-
-	// Fall back to default local database configuration
-	const (
-		dbUser     = "postgres"
-		dbPassword = "postgres"
-		dbName     = "ligain_test"
-		dbHost     = "localhost"
-		dbPort     = 5432
-	)
-
-	return fmt.Sprintf("postgres://%s:%s@%s:%d/%s?sslmode=disable",
-		dbUser, dbPassword, dbHost, dbPort, dbName)
-}
-
 func getAPIToken() string {
 	apiToken := os.Getenv("SPORTSMONK_API_TOKEN")
 	if apiToken == "" {
@@ -177,27 +144,3 @@ func getAPIToken() string {
 	}
 	return apiToken
 }
-
-func runMigrations(db *sql.DB) error {
-	// Initialize golang-migrate
-	driver, err := migratePostgres.WithInstance(db, &migratePostgres.Config{})
-	if err != nil {
-		return fmt.Errorf("failed to create postgres driver: %v", err)
-	}
-
-	m, err := migrate.NewWithDatabaseInstance(
-		"file://../../backend/migrations",
-		"postgres",
-		driver,
-	)
-	if err != nil {
-		return fmt.Errorf("failed to create migrate instance: %v", err)
-	}
-
-	// Run migrations
-	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
-		return fmt.Errorf("failed to run migrations: %v", err)
-	}
-
-	return nil
-}